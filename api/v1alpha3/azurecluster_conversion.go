@@ -50,14 +50,25 @@ func (src *AzureCluster) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	}
 
 	dst.Spec.NetworkSpec.PrivateDNSZoneName = restored.Spec.NetworkSpec.PrivateDNSZoneName
+	dst.Spec.NetworkSpec.DNSForwardingRulesetLink = restored.Spec.NetworkSpec.DNSForwardingRulesetLink
+	dst.Spec.NetworkSpec.ResourceGroup = restored.Spec.NetworkSpec.ResourceGroup
 
 	dst.Spec.NetworkSpec.APIServerLB.FrontendIPsCount = restored.Spec.NetworkSpec.APIServerLB.FrontendIPsCount
 	dst.Spec.NetworkSpec.APIServerLB.IdleTimeoutInMinutes = restored.Spec.NetworkSpec.APIServerLB.IdleTimeoutInMinutes
+	dst.Spec.NetworkSpec.APIServerLB.BackendPool = restored.Spec.NetworkSpec.APIServerLB.BackendPool
 	dst.Spec.NetworkSpec.NodeOutboundLB = restored.Spec.NetworkSpec.NodeOutboundLB
 	dst.Spec.NetworkSpec.ControlPlaneOutboundLB = restored.Spec.NetworkSpec.ControlPlaneOutboundLB
 	dst.Spec.CloudProviderConfigOverrides = restored.Spec.CloudProviderConfigOverrides
+	dst.Spec.ResourceManagerEndpoint = restored.Spec.ResourceManagerEndpoint
+	dst.Spec.APIProfile = restored.Spec.APIProfile
+	dst.Spec.CloudProviderMode = restored.Spec.CloudProviderMode
 	dst.Spec.BastionSpec = restored.Spec.BastionSpec
 
+	dst.Status.Region = restored.Status.Region
+	dst.Status.ResourceGroup = restored.Status.ResourceGroup
+	dst.Status.EstimatedCost = restored.Status.EstimatedCost
+	dst.Status.LongRunningOperationStates = restored.Status.LongRunningOperationStates
+
 	// Here we manually restore outbound security rules. Since v1alpha3 only supports ingress ("Inbound") rules, all v1alpha4 outbound rules are dropped when an AzureCluster
 	// is converted to v1alpha3. We loop through all security group rules. For all previously existing outbound rules we restore the full rule.
 	for _, restoredSubnet := range restored.Spec.NetworkSpec.Subnets {
@@ -287,3 +298,8 @@ func Convert_v1alpha3_VnetSpec_To_v1alpha4_VnetSpec(in *VnetSpec, out *infrav1al
 func Convert_v1alpha4_LoadBalancerSpec_To_v1alpha3_LoadBalancerSpec(in *infrav1alpha4.LoadBalancerSpec, out *LoadBalancerSpec, s apiconversion.Scope) error {
 	return autoConvert_v1alpha4_LoadBalancerSpec_To_v1alpha3_LoadBalancerSpec(in, out, s)
 }
+
+// Convert_v1alpha4_Future_To_v1alpha3_Future converts from the Hub version (v1alpha4) of the Future to this version.
+func Convert_v1alpha4_Future_To_v1alpha3_Future(in *infrav1alpha4.Future, out *Future, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_Future_To_v1alpha3_Future(in, out, s)
+}
@@ -52,6 +52,15 @@ func (src *AzureMachine) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	}
 
 	dst.Spec.SubnetName = restored.Spec.SubnetName
+	dst.Spec.OSDisk.BootstrapFormat = restored.Spec.OSDisk.BootstrapFormat
+	dst.Spec.OSDisk.Distribution = restored.Spec.OSDisk.Distribution
+	dst.Spec.EnableRDP = restored.Spec.EnableRDP
+	dst.Spec.BootstrapDataKeyVault = restored.Spec.BootstrapDataKeyVault
+	dst.Spec.AzureMonitor = restored.Spec.AzureMonitor
+	dst.Spec.Backup = restored.Spec.Backup
+
+	dst.Status.LongRunningOperationStates = restored.Status.LongRunningOperationStates
+	dst.Status.BootstrapDataSecretState = restored.Status.BootstrapDataSecretState
 
 	return nil
 }
@@ -52,6 +52,12 @@ func (src *AzureMachineTemplate) ConvertTo(dstRaw conversion.Hub) error { // nol
 	}
 
 	dst.Spec.Template.Spec.SubnetName = restored.Spec.Template.Spec.SubnetName
+	dst.Spec.Template.Spec.OSDisk.BootstrapFormat = restored.Spec.Template.Spec.OSDisk.BootstrapFormat
+	dst.Spec.Template.Spec.OSDisk.Distribution = restored.Spec.Template.Spec.OSDisk.Distribution
+	dst.Spec.Template.Spec.EnableRDP = restored.Spec.Template.Spec.EnableRDP
+	dst.Spec.Template.Spec.BootstrapDataKeyVault = restored.Spec.Template.Spec.BootstrapDataKeyVault
+	dst.Spec.Template.Spec.AzureMonitor = restored.Spec.Template.Spec.AzureMonitor
+	dst.Spec.Template.Spec.Backup = restored.Spec.Template.Spec.Backup
 
 	return nil
 }
@@ -230,11 +230,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.Future)(nil), (*Future)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_Future_To_v1alpha3_Future(a.(*v1alpha4.Future), b.(*Future), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*Image)(nil), (*v1alpha4.Image)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_Image_To_v1alpha4_Image(a.(*Image), b.(*v1alpha4.Image), scope)
 	}); err != nil {
@@ -410,6 +405,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha4.Future)(nil), (*Future)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_Future_To_v1alpha3_Future(a.(*v1alpha4.Future), b.(*Future), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha4.LoadBalancerSpec)(nil), (*LoadBalancerSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_LoadBalancerSpec_To_v1alpha3_LoadBalancerSpec(a.(*v1alpha4.LoadBalancerSpec), b.(*LoadBalancerSpec), scope)
 	}); err != nil {
@@ -691,8 +691,11 @@ func autoConvert_v1alpha4_AzureClusterSpec_To_v1alpha3_AzureClusterSpec(in *v1al
 	out.AdditionalTags = *(*Tags)(unsafe.Pointer(&in.AdditionalTags))
 	out.IdentityRef = (*v1.ObjectReference)(unsafe.Pointer(in.IdentityRef))
 	// WARNING: in.AzureEnvironment requires manual conversion: does not exist in peer-type
+	// WARNING: in.ResourceManagerEndpoint requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIProfile requires manual conversion: does not exist in peer-type
 	// WARNING: in.BastionSpec requires manual conversion: does not exist in peer-type
 	// WARNING: in.CloudProviderConfigOverrides requires manual conversion: does not exist in peer-type
+	// WARNING: in.CloudProviderMode requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -705,8 +708,12 @@ func autoConvert_v1alpha3_AzureClusterStatus_To_v1alpha4_AzureClusterStatus(in *
 
 func autoConvert_v1alpha4_AzureClusterStatus_To_v1alpha3_AzureClusterStatus(in *v1alpha4.AzureClusterStatus, out *AzureClusterStatus, s conversion.Scope) error {
 	out.FailureDomains = *(*apiv1alpha3.FailureDomains)(unsafe.Pointer(&in.FailureDomains))
+	// WARNING: in.Region requires manual conversion: does not exist in peer-type
+	// WARNING: in.ResourceGroup requires manual conversion: does not exist in peer-type
+	// WARNING: in.EstimatedCost requires manual conversion: does not exist in peer-type
 	out.Ready = in.Ready
 	out.Conditions = *(*apiv1alpha3.Conditions)(unsafe.Pointer(&in.Conditions))
+	// WARNING: in.LongRunningOperationStates requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -864,6 +871,10 @@ func autoConvert_v1alpha4_AzureMachineSpec_To_v1alpha3_AzureMachineSpec(in *v1al
 	out.SpotVMOptions = (*SpotVMOptions)(unsafe.Pointer(in.SpotVMOptions))
 	out.SecurityProfile = (*SecurityProfile)(unsafe.Pointer(in.SecurityProfile))
 	// WARNING: in.SubnetName requires manual conversion: does not exist in peer-type
+	// WARNING: in.EnableRDP requires manual conversion: does not exist in peer-type
+	// WARNING: in.BootstrapDataKeyVault requires manual conversion: does not exist in peer-type
+	// WARNING: in.AzureMonitor requires manual conversion: does not exist in peer-type
+	// WARNING: in.Backup requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -884,6 +895,8 @@ func autoConvert_v1alpha4_AzureMachineStatus_To_v1alpha3_AzureMachineStatus(in *
 	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
 	out.Conditions = *(*apiv1alpha3.Conditions)(unsafe.Pointer(&in.Conditions))
+	// WARNING: in.LongRunningOperationStates requires manual conversion: does not exist in peer-type
+	// WARNING: in.BootstrapDataSecretState requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1211,16 +1224,12 @@ func Convert_v1alpha3_Future_To_v1alpha4_Future(in *Future, out *v1alpha4.Future
 func autoConvert_v1alpha4_Future_To_v1alpha3_Future(in *v1alpha4.Future, out *Future, s conversion.Scope) error {
 	out.Type = in.Type
 	out.ResourceGroup = in.ResourceGroup
+	// WARNING: in.ServiceName requires manual conversion: does not exist in peer-type
 	out.Name = in.Name
 	out.FutureData = in.FutureData
 	return nil
 }
 
-// Convert_v1alpha4_Future_To_v1alpha3_Future is an autogenerated conversion function.
-func Convert_v1alpha4_Future_To_v1alpha3_Future(in *v1alpha4.Future, out *Future, s conversion.Scope) error {
-	return autoConvert_v1alpha4_Future_To_v1alpha3_Future(in, out, s)
-}
-
 func autoConvert_v1alpha3_Image_To_v1alpha4_Image(in *Image, out *v1alpha4.Image, s conversion.Scope) error {
 	out.ID = (*string)(unsafe.Pointer(in.ID))
 	if in.SharedGallery != nil {
@@ -1283,6 +1292,7 @@ func autoConvert_v1alpha4_LoadBalancerSpec_To_v1alpha3_LoadBalancerSpec(in *v1al
 	out.Type = LBType(in.Type)
 	// WARNING: in.FrontendIPsCount requires manual conversion: does not exist in peer-type
 	// WARNING: in.IdleTimeoutInMinutes requires manual conversion: does not exist in peer-type
+	// WARNING: in.BackendPool requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1328,6 +1338,8 @@ func autoConvert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec(in *v1alpha4.Netwo
 	// WARNING: in.NodeOutboundLB requires manual conversion: does not exist in peer-type
 	// WARNING: in.ControlPlaneOutboundLB requires manual conversion: does not exist in peer-type
 	// WARNING: in.PrivateDNSZoneName requires manual conversion: does not exist in peer-type
+	// WARNING: in.DNSForwardingRulesetLink requires manual conversion: does not exist in peer-type
+	// WARNING: in.ResourceGroup requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -18,6 +18,7 @@ package v1alpha4
 
 import (
 	"fmt"
+	"strings"
 
 	"k8s.io/utils/pointer"
 )
@@ -41,8 +42,15 @@ const (
 	DefaultOutboundRuleIdleTimeoutInMinutes = 4
 	// DefaultAzureCloud is the public cloud that will be used by most users.
 	DefaultAzureCloud = "AzurePublicCloud"
+	// AzureStackCloud is the name of the environment of the clusters created in an Azure Stack Hub.
+	AzureStackCloud = "AzureStackCloud"
+	// DefaultAzureStackAPIProfile is the API profile used by default with Azure Stack Hub.
+	DefaultAzureStackAPIProfile = "2020-09-01-hybrid"
 )
 
+// SupportedAzureEnvironments are the names of the Azure clouds clusters can be created in.
+var SupportedAzureEnvironments = []string{DefaultAzureCloud, "AzureUSGovernmentCloud", "AzureChinaCloud", "AzureGermanCloud", AzureStackCloud}
+
 func (c *AzureCluster) setDefaults() {
 	c.setResourceGroupDefault()
 	c.setAzureEnvironmentDefault()
@@ -56,6 +64,7 @@ func (c *AzureCluster) setNetworkSpecDefaults() {
 	c.setAPIServerLBDefaults()
 	c.setNodeOutboundLBDefaults()
 	c.setControlPlaneOutboundLBDefaults()
+	c.setDNSForwardingRulesetLinkDefaults()
 }
 
 func (c *AzureCluster) setResourceGroupDefault() {
@@ -68,11 +77,17 @@ func (c *AzureCluster) setAzureEnvironmentDefault() {
 	if c.Spec.AzureEnvironment == "" {
 		c.Spec.AzureEnvironment = DefaultAzureCloud
 	}
+	if strings.EqualFold(c.Spec.AzureEnvironment, AzureStackCloud) && c.Spec.APIProfile == "" {
+		c.Spec.APIProfile = DefaultAzureStackAPIProfile
+	}
 }
 
 func (c *AzureCluster) setVnetDefaults() {
 	if c.Spec.NetworkSpec.Vnet.ResourceGroup == "" {
 		c.Spec.NetworkSpec.Vnet.ResourceGroup = c.Spec.ResourceGroup
+		if c.Spec.NetworkSpec.ResourceGroup != "" {
+			c.Spec.NetworkSpec.Vnet.ResourceGroup = c.Spec.NetworkSpec.ResourceGroup
+		}
 	}
 	if c.Spec.NetworkSpec.Vnet.Name == "" {
 		c.Spec.NetworkSpec.Vnet.Name = generateVnetName(c.ObjectMeta.Name)
@@ -194,6 +209,10 @@ func (c *AzureCluster) setAPIServerLBDefaults() {
 			}
 		}
 	}
+
+	if lb.BackendPool.Name == "" {
+		lb.BackendPool.Name = generateBackendAddressPoolName(lb.Name)
+	}
 }
 
 func (c *AzureCluster) setNodeOutboundLBDefaults() {
@@ -223,7 +242,14 @@ func (c *AzureCluster) setNodeOutboundLBDefaults() {
 	lb := c.Spec.NetworkSpec.NodeOutboundLB
 	lb.Type = Public
 	lb.SKU = SKUStandard
-	lb.Name = c.ObjectMeta.Name
+
+	if lb.Name == "" {
+		lb.Name = c.ObjectMeta.Name
+	}
+
+	if lb.BackendPool.Name == "" {
+		lb.BackendPool.Name = generateOutboundBackendAddressPoolName(lb.Name)
+	}
 
 	if lb.IdleTimeoutInMinutes == nil {
 		lb.IdleTimeoutInMinutes = pointer.Int32Ptr(DefaultOutboundRuleIdleTimeoutInMinutes)
@@ -255,6 +281,10 @@ func (c *AzureCluster) setControlPlaneOutboundLBDefaults() {
 		lb.Name = generateControlPlaneOutboundLBName(c.ObjectMeta.Name)
 	}
 
+	if lb.BackendPool.Name == "" {
+		lb.BackendPool.Name = generateOutboundBackendAddressPoolName(lb.Name)
+	}
+
 	if lb.IdleTimeoutInMinutes == nil {
 		lb.IdleTimeoutInMinutes = pointer.Int32Ptr(DefaultOutboundRuleIdleTimeoutInMinutes)
 	}
@@ -317,6 +347,12 @@ func (c *AzureCluster) setBastionDefaults() {
 	}
 }
 
+func (c *AzureCluster) setDNSForwardingRulesetLinkDefaults() {
+	if link := c.Spec.NetworkSpec.DNSForwardingRulesetLink; link != nil && link.Name == "" {
+		link.Name = generateDNSForwardingRulesetLinkName(c.Spec.NetworkSpec.Vnet.Name)
+	}
+}
+
 // generateVnetName generates a virtual network name, based on the cluster name.
 func generateVnetName(clusterName string) string {
 	return fmt.Sprintf("%s-%s", clusterName, "vnet")
@@ -382,6 +418,16 @@ func generateFrontendIPConfigName(lbName string) string {
 	return fmt.Sprintf("%s-%s", lbName, "frontEnd")
 }
 
+// generateBackendAddressPoolName generates a load balancer backend address pool name.
+func generateBackendAddressPoolName(lbName string) string {
+	return fmt.Sprintf("%s-%s", lbName, "backendPool")
+}
+
+// generateOutboundBackendAddressPoolName generates a load balancer outbound backend address pool name.
+func generateOutboundBackendAddressPoolName(lbName string) string {
+	return fmt.Sprintf("%s-%s", lbName, "outboundBackendPool")
+}
+
 // generateNodeOutboundIPName generates a public IP name, based on the cluster name.
 func generateNodeOutboundIPName(clusterName string) string {
 	return fmt.Sprintf("pip-%s-node-outbound", clusterName)
@@ -397,6 +443,11 @@ func generateNatGatewayIPName(clusterName, subnetName string) string {
 	return fmt.Sprintf("pip-%s-%s-natgw", clusterName, subnetName)
 }
 
+// generateDNSForwardingRulesetLinkName generates a DNS forwarding ruleset virtual network link name, based on the vnet name.
+func generateDNSForwardingRulesetLinkName(vnetName string) string {
+	return fmt.Sprintf("%s-%s", vnetName, "ruleset-link")
+}
+
 // withIndex appends the index as suffix to a generated name.
 func withIndex(name string, n int) string {
 	return fmt.Sprintf("%s-%d", name, n)
@@ -137,6 +137,36 @@ func TestVnetDefaults(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "network resource group specified",
+			cluster: &AzureCluster{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					ResourceGroup: "cluster-test",
+					NetworkSpec: NetworkSpec{
+						ResourceGroup: "network-rg",
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					ResourceGroup: "cluster-test",
+					NetworkSpec: NetworkSpec{
+						ResourceGroup: "network-rg",
+						Vnet: VnetSpec{
+							ResourceGroup: "network-rg",
+							Name:          "cluster-test-vnet",
+							CIDRBlocks:    []string{DefaultVnetCIDR},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "vnet not specified",
 			cluster: &AzureCluster{
@@ -658,6 +688,7 @@ func TestAPIServerLBDefaults(t *testing.T) {
 							},
 							Type:                 Public,
 							IdleTimeoutInMinutes: to.Int32Ptr(DefaultOutboundRuleIdleTimeoutInMinutes),
+							BackendPool:          BackendPool{Name: "cluster-test-public-lb-backendPool"},
 						},
 					},
 				},
@@ -694,6 +725,7 @@ func TestAPIServerLBDefaults(t *testing.T) {
 							},
 							Type:                 Internal,
 							IdleTimeoutInMinutes: to.Int32Ptr(DefaultOutboundRuleIdleTimeoutInMinutes),
+							BackendPool:          BackendPool{Name: "cluster-test-internal-lb-backendPool"},
 						},
 					},
 				},
@@ -772,6 +804,45 @@ func TestAzureEnviromentDefault(t *testing.T) {
 				},
 			},
 		},
+		"azure env set to AzureStackCloud": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+				},
+				Spec: AzureClusterSpec{
+					AzureEnvironment: AzureStackCloud,
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+				},
+				Spec: AzureClusterSpec{
+					AzureEnvironment: AzureStackCloud,
+					APIProfile:       "2020-09-01-hybrid",
+				},
+			},
+		},
+		"azure env set to AzureStackCloud with an API profile": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+				},
+				Spec: AzureClusterSpec{
+					AzureEnvironment: AzureStackCloud,
+					APIProfile:       "2019-03-01-hybrid",
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+				},
+				Spec: AzureClusterSpec{
+					AzureEnvironment: AzureStackCloud,
+					APIProfile:       "2019-03-01-hybrid",
+				},
+			},
+		},
 	}
 
 	for name := range cases {
@@ -827,6 +898,7 @@ func TestNodeOutboundLBDefaults(t *testing.T) {
 							Type:                 Public,
 							FrontendIPsCount:     to.Int32Ptr(1),
 							IdleTimeoutInMinutes: to.Int32Ptr(DefaultOutboundRuleIdleTimeoutInMinutes),
+							BackendPool:          BackendPool{Name: "cluster-test-outboundBackendPool"},
 						},
 					},
 				},
@@ -857,6 +929,49 @@ func TestNodeOutboundLBDefaults(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "custom lb and backend pool names",
+			cluster: &AzureCluster{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{Type: Public},
+						NodeOutboundLB: &LoadBalancerSpec{
+							Name:        "my-outbound-lb",
+							BackendPool: BackendPool{Name: "my-outbound-pool"},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{
+							Type: Public,
+						},
+						NodeOutboundLB: &LoadBalancerSpec{
+							Name: "my-outbound-lb",
+							SKU:  SKUStandard,
+							FrontendIPs: []FrontendIP{{
+								Name: "my-outbound-lb-frontEnd",
+								PublicIP: &PublicIPSpec{
+									Name: "pip-cluster-test-node-outbound",
+								},
+							}},
+							Type:                 Public,
+							FrontendIPsCount:     to.Int32Ptr(1),
+							IdleTimeoutInMinutes: to.Int32Ptr(DefaultOutboundRuleIdleTimeoutInMinutes),
+							BackendPool:          BackendPool{Name: "my-outbound-pool"},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "frontendIPsCount > 1",
 			cluster: &AzureCluster{
@@ -902,6 +1017,7 @@ func TestNodeOutboundLBDefaults(t *testing.T) {
 							Type:                 Public,
 							FrontendIPsCount:     to.Int32Ptr(2),
 							IdleTimeoutInMinutes: to.Int32Ptr(15),
+							BackendPool:          BackendPool{Name: "cluster-test-outboundBackendPool"},
 						},
 					},
 				},
@@ -1024,6 +1140,7 @@ func TestControlPlaneOutboundLBDefaults(t *testing.T) {
 							Type:                 Public,
 							FrontendIPsCount:     to.Int32Ptr(2),
 							IdleTimeoutInMinutes: to.Int32Ptr(15),
+							BackendPool:          BackendPool{Name: "cluster-test-outbound-lb-outboundBackendPool"},
 						},
 					},
 				},
@@ -1247,3 +1364,107 @@ func TestBastionDefault(t *testing.T) {
 		})
 	}
 }
+
+func TestDNSForwardingRulesetLinkDefaults(t *testing.T) {
+	rulesetID := "/subscriptions/123/resourceGroups/dns-rg/providers/Microsoft.Network/dnsForwardingRulesets/my-ruleset"
+
+	cases := map[string]struct {
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		"no link set": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{Name: "foo-vnet"},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{Name: "foo-vnet"},
+					},
+				},
+			},
+		},
+		"link without name": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{Name: "foo-vnet"},
+						DNSForwardingRulesetLink: &DNSForwardingRulesetLinkSpec{
+							RulesetID: rulesetID,
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{Name: "foo-vnet"},
+						DNSForwardingRulesetLink: &DNSForwardingRulesetLinkSpec{
+							RulesetID: rulesetID,
+							Name:      "foo-vnet-ruleset-link",
+						},
+					},
+				},
+			},
+		},
+		"link with name": {
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{Name: "foo-vnet"},
+						DNSForwardingRulesetLink: &DNSForwardingRulesetLinkSpec{
+							RulesetID: rulesetID,
+							Name:      "my-link",
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{Name: "foo-vnet"},
+						DNSForwardingRulesetLink: &DNSForwardingRulesetLinkSpec{
+							RulesetID: rulesetID,
+							Name:      "my-link",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c.cluster.setDNSForwardingRulesetLinkDefaults()
+			if !reflect.DeepEqual(c.cluster, c.output) {
+				expected, _ := json.MarshalIndent(c.output, "", "\t")
+				actual, _ := json.MarshalIndent(c.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
@@ -29,6 +29,22 @@ const (
 
 	// ClusterLabelNamespace indicates the namespace of the cluster.
 	ClusterLabelNamespace = "azurecluster.infrastructure.cluster.x-k8s.io/cluster-namespace"
+
+	// DNSLabelSuffixAnnotation persists the unique suffix appended to the DNS labels generated for the cluster,
+	// so that domain name labels stay stable across reconciles and don't collide with other clusters in the region.
+	DNSLabelSuffixAnnotation = "azurecluster.infrastructure.cluster.x-k8s.io/dns-label-suffix"
+
+	// AdoptResourcesAnnotation enables the adoption of pre-existing Azure resources whose names match the ones expected
+	// by the cluster. Its value is the lifecycle the adopted resources are tagged with, either "owned" or "shared".
+	AdoptResourcesAnnotation = "azurecluster.infrastructure.cluster.x-k8s.io/adopt-resources"
+
+	// LastGarbageCollectionAnnotation records when the resources orphaned by the cluster were last garbage collected,
+	// in RFC3339 format, so that the garbage collection runs periodically rather than on every reconcile.
+	LastGarbageCollectionAnnotation = "azurecluster.infrastructure.cluster.x-k8s.io/last-garbage-collection"
+
+	// DryRunAnnotation enables the dry-run mode for the cluster and its machines when set to "true": the changes the
+	// controllers would make to their Azure resources are reported in events and conditions instead of being made.
+	DryRunAnnotation = "azurecluster.infrastructure.cluster.x-k8s.io/dry-run"
 )
 
 // AzureClusterSpec defines the desired state of AzureCluster.
@@ -63,9 +79,22 @@ type AzureClusterSpec struct {
 	// - GermanCloud: "AzureGermanCloud"
 	// - PublicCloud: "AzurePublicCloud"
 	// - USGovernmentCloud: "AzureUSGovernmentCloud"
+	// - AzureStackCloud: "AzureStackCloud", for Azure Stack Hub
 	// +optional
 	AzureEnvironment string `json:"azureEnvironment,omitempty"`
 
+	// ResourceManagerEndpoint is the Azure Resource Manager endpoint of an Azure Stack Hub, e.g.
+	// "https://management.local.azurestack.external/". The other endpoints of the cloud are looked up from the metadata
+	// it serves. It is required when AzureEnvironment is "AzureStackCloud", and not allowed otherwise.
+	// +optional
+	ResourceManagerEndpoint string `json:"resourceManagerEndpoint,omitempty"`
+
+	// APIProfile is the API profile supported by the cloud. The Azure API requests use the API versions of the profile
+	// instead of the latest ones. It defaults to "2020-09-01-hybrid" when AzureEnvironment is "AzureStackCloud".
+	// +kubebuilder:validation:Enum=2019-03-01-hybrid;2020-09-01-hybrid
+	// +optional
+	APIProfile string `json:"apiProfile,omitempty"`
+
 	// BastionSpec encapsulates all things related to the Bastions in the cluster.
 	// +optional
 	BastionSpec BastionSpec `json:"bastionSpec,omitempty"`
@@ -77,8 +106,26 @@ type AzureClusterSpec struct {
 	// Note: All cloud provider config values can be customized by creating the secret beforehand. CloudProviderConfigOverrides is only used when the secret is managed by the Azure Provider.
 	// +optional
 	CloudProviderConfigOverrides *CloudProviderConfigOverrides `json:"cloudProviderConfigOverrides,omitempty"`
+
+	// CloudProviderMode is the way the Azure cloud provider runs in the cluster, either InTree when it is built into
+	// the Kubernetes components, or External when cloud-provider-azure runs as a deployment in the cluster.
+	// In External mode, the cloud provider config of the control plane is also written to the "azure-cloud-provider"
+	// secret of the kube-system namespace of the cluster, where cloud-provider-azure looks for it. Defaults to InTree.
+	// +kubebuilder:validation:Enum=InTree;External
+	// +optional
+	CloudProviderMode CloudProviderMode `json:"cloudProviderMode,omitempty"`
 }
 
+// CloudProviderMode is the way the Azure cloud provider runs in a cluster.
+type CloudProviderMode string
+
+const (
+	// CloudProviderModeInTree is the mode of the cloud provider built into the Kubernetes components.
+	CloudProviderModeInTree CloudProviderMode = "InTree"
+	// CloudProviderModeExternal is the mode of cloud-provider-azure running as a deployment in the cluster.
+	CloudProviderModeExternal CloudProviderMode = "External"
+)
+
 // AzureClusterStatus defines the observed state of AzureCluster.
 type AzureClusterStatus struct {
 	// FailureDomains specifies the list of unique failure domains for the location/region of the cluster.
@@ -89,6 +136,20 @@ type AzureClusterStatus struct {
 	// This list will be used by Cluster API to try and spread the machines across the failure domains.
 	FailureDomains clusterv1.FailureDomains `json:"failureDomains,omitempty"`
 
+	// Region describes the location/region of the cluster, its availability zones and the regions it is paired with.
+	// It is meant for tooling that makes placement and disaster recovery decisions without querying Azure.
+	// +optional
+	Region *RegionStatus `json:"region,omitempty"`
+
+	// ResourceGroup describes the resource group of the cluster and whether it is managed by the provider.
+	// +optional
+	ResourceGroup *ResourceGroupStatus `json:"resourceGroup,omitempty"`
+
+	// EstimatedCost is the estimated monthly cost of the Azure resources declared by the cluster and its machines, at
+	// the Azure retail prices. It is only published when the CostEstimation feature gate is enabled.
+	// +optional
+	EstimatedCost *CostEstimate `json:"estimatedCost,omitempty"`
+
 	// Ready is true when the provider resource is ready.
 	// +optional
 	Ready bool `json:"ready"`
@@ -96,6 +157,75 @@ type AzureClusterStatus struct {
 	// Conditions defines current service state of the AzureCluster.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// LongRunningOperationStates saves the states of the Azure long-running operations of the cluster so that they
+	// can be continued on the next reconciliation loops, rather than blocking the controller until they complete.
+	// +optional
+	LongRunningOperationStates Futures `json:"longRunningOperationStates,omitempty"`
+}
+
+// RegionStatus describes an Azure region.
+type RegionStatus struct {
+	// Name is the name of the region.
+	Name string `json:"name"`
+
+	// Zones are the availability zones of the region. It is empty if the region doesn't support availability zones.
+	// +optional
+	Zones []string `json:"zones,omitempty"`
+
+	// PairedRegions are the regions Azure pairs with this region for disaster recovery.
+	// See: https://docs.microsoft.com/en-us/azure/best-practices-availability-paired-regions
+	// +optional
+	PairedRegions []string `json:"pairedRegions,omitempty"`
+}
+
+// ResourceGroupStatus describes an Azure resource group used by a cluster.
+type ResourceGroupStatus struct {
+	// Name is the name of the resource group.
+	Name string `json:"name"`
+
+	// Managed is true when the resource group is owned by the cluster, meaning it was created by the provider and gets
+	// deleted along with the cluster. A pre-existing resource group which is not tagged as owned by the cluster is
+	// unmanaged: the resources of the cluster are placed in it, but it is never deleted.
+	Managed bool `json:"managed"`
+}
+
+// CostEstimate is the estimated monthly cost of Azure resources, at the pay-as-you-go retail prices of their region.
+// It leaves out reservations, savings plans and negotiated discounts, as well as usage-based charges such as data transfer.
+type CostEstimate struct {
+	// Currency is the code of the currency of the prices, e.g. USD.
+	Currency string `json:"currency"`
+
+	// MonthlyTotal is the estimated cost of the priced resources for a month of 730 hours, e.g. "291.27".
+	MonthlyTotal string `json:"monthlyTotal"`
+
+	// Resources are the estimated monthly costs of the priced resources.
+	// +optional
+	Resources []ResourceCost `json:"resources,omitempty"`
+
+	// UnpricedResources are the names of the resources whose price could not be found, which are left out of the total.
+	// +optional
+	UnpricedResources []string `json:"unpricedResources,omitempty"`
+
+	// LastUpdated is the time the estimate was computed.
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+// ResourceCost is the estimated monthly cost of an Azure resource.
+type ResourceCost struct {
+	// Name is the name of the resource.
+	Name string `json:"name"`
+
+	// Type is the type of the resource, e.g. Microsoft.Compute/virtualMachines.
+	Type string `json:"type"`
+
+	// SKU is the SKU the resource is priced at, e.g. the VM size of a virtual machine.
+	// +optional
+	SKU string `json:"sku,omitempty"`
+
+	// Monthly is the estimated cost of the resource for a month of 730 hours, e.g. "70.08".
+	Monthly string `json:"monthly"`
 }
 
 // +kubebuilder:object:root=true
@@ -105,6 +235,7 @@ type AzureClusterStatus struct {
 // +kubebuilder:printcolumn:name="SubscriptionID",type="string",priority=1,JSONPath=".spec.subscriptionID"
 // +kubebuilder:printcolumn:name="Location",type="string",priority=1,JSONPath=".spec.location"
 // +kubebuilder:printcolumn:name="Endpoint",type="string",priority=1,JSONPath=".spec.controlPlaneEndpoint.host",description="Control Plane Endpoint"
+// +kubebuilder:printcolumn:name="Monthly Cost",type="string",priority=1,JSONPath=".status.estimatedCost.monthlyTotal",description="Estimated monthly cost of the Azure resources"
 // +kubebuilder:resource:path=azureclusters,scope=Namespaced,categories=cluster-api
 // +kubebuilder:storageversion
 // +kubebuilder:subresource:status
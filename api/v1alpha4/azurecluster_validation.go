@@ -19,8 +19,10 @@ package v1alpha4
 import (
 	"fmt"
 	"net"
+	"net/url"
 	"reflect"
 	"regexp"
+	"strings"
 
 	"k8s.io/utils/pointer"
 
@@ -42,6 +44,10 @@ const (
 	// described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules.
 	subnetRegex       = `^[-\w\._]+$`
 	loadBalancerRegex = `^[-\w\._]+$`
+	// DNS forwarding ruleset virtual network links follow the same naming rules as private DNS zone virtual network links.
+	dnsForwardingRulesetLinkRegex = `^[a-zA-Z0-9][-\w\.]{0,78}[\w]$`
+	// dnsForwardingRulesetIDRegex matches the resource ID of a DNS forwarding ruleset.
+	dnsForwardingRulesetIDRegex = `(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Network/dnsForwardingRulesets/[^/]+$`
 	// MaxLoadBalancerOutboundIPs is the maximum number of outbound IPs in a Standard LoadBalancer frontend configuration.
 	MaxLoadBalancerOutboundIPs = 16
 	// MinLBIdleTimeoutInMinutes is the minimum number of minutes for the LB idle timeout.
@@ -58,6 +64,7 @@ const (
 func (c *AzureCluster) validateCluster(old *AzureCluster) error {
 	var allErrs field.ErrorList
 	allErrs = append(allErrs, c.validateClusterName()...)
+	allErrs = append(allErrs, c.validateAdoptResourcesAnnotation()...)
 	allErrs = append(allErrs, c.validateClusterSpec(old)...)
 	if len(allErrs) == 0 {
 		return nil
@@ -72,10 +79,16 @@ func (c *AzureCluster) validateCluster(old *AzureCluster) error {
 func (c *AzureCluster) validateClusterSpec(old *AzureCluster) field.ErrorList {
 	var allErrs field.ErrorList
 	var oldNetworkSpec NetworkSpec
+	var oldAdditionalTags Tags
 	if old != nil {
 		oldNetworkSpec = old.Spec.NetworkSpec
+		oldAdditionalTags = old.Spec.AdditionalTags
 	}
 	allErrs = append(allErrs, validateNetworkSpec(c.Spec.NetworkSpec, oldNetworkSpec, field.NewPath("spec").Child("networkSpec"))...)
+	allErrs = append(allErrs, c.validateSubnetCIDRsOverlap()...)
+	allErrs = append(allErrs, ValidateTagsUpdate(c.Spec.AdditionalTags, oldAdditionalTags, field.NewPath("spec", "additionalTags"))...)
+	allErrs = append(allErrs, ValidateAzureEnvironment(c.Spec.AzureEnvironment, field.NewPath("spec").Child("azureEnvironment"))...)
+	allErrs = append(allErrs, validateResourceManagerEndpoint(c.Spec.ResourceManagerEndpoint, c.Spec.AzureEnvironment, field.NewPath("spec").Child("resourceManagerEndpoint"))...)
 
 	var oldCloudProviderConfigOverrides *CloudProviderConfigOverrides
 	if old != nil {
@@ -87,6 +100,38 @@ func (c *AzureCluster) validateClusterSpec(old *AzureCluster) field.ErrorList {
 	return allErrs
 }
 
+// ValidateAzureEnvironment validates the name of the Azure cloud a cluster is created in. Names are matched
+// case-insensitively, like when the endpoints of the cloud are looked up.
+func ValidateAzureEnvironment(azureEnvironment string, fldPath *field.Path) field.ErrorList {
+	if azureEnvironment == "" {
+		return nil
+	}
+	for _, name := range SupportedAzureEnvironments {
+		if strings.EqualFold(azureEnvironment, name) {
+			return nil
+		}
+	}
+	return field.ErrorList{field.NotSupported(fldPath, azureEnvironment, SupportedAzureEnvironments)}
+}
+
+// validateResourceManagerEndpoint validates the Azure Resource Manager endpoint of an Azure Stack Hub, which is only
+// set with the AzureStackCloud environment.
+func validateResourceManagerEndpoint(endpoint, azureEnvironment string, fldPath *field.Path) field.ErrorList {
+	if !strings.EqualFold(azureEnvironment, AzureStackCloud) {
+		if endpoint != "" {
+			return field.ErrorList{field.Forbidden(fldPath, fmt.Sprintf("resourceManagerEndpoint can only be set with the %s environment", AzureStackCloud))}
+		}
+		return nil
+	}
+	if endpoint == "" {
+		return field.ErrorList{field.Required(fldPath, fmt.Sprintf("resourceManagerEndpoint is required with the %s environment", AzureStackCloud))}
+	}
+	if u, err := url.Parse(endpoint); err != nil || u.Scheme != "https" || u.Host == "" {
+		return field.ErrorList{field.Invalid(fldPath, endpoint, "must be an https URL")}
+	}
+	return nil
+}
+
 // validateClusterName validates ClusterName.
 func (c *AzureCluster) validateClusterName() field.ErrorList {
 	var allErrs field.ErrorList
@@ -105,6 +150,21 @@ func (c *AzureCluster) validateClusterName() field.ErrorList {
 	return allErrs
 }
 
+// validateAdoptResourcesAnnotation validates the lifecycle requested for the adoption of pre-existing resources.
+func (c *AzureCluster) validateAdoptResourcesAnnotation() field.ErrorList {
+	lifecycle, ok := c.Annotations[AdoptResourcesAnnotation]
+	if !ok {
+		return nil
+	}
+	switch ResourceLifecycle(lifecycle) {
+	case ResourceLifecycleOwned, ResourceLifecycleShared:
+		return nil
+	default:
+		return field.ErrorList{field.NotSupported(field.NewPath("metadata", "annotations").Key(AdoptResourcesAnnotation), lifecycle,
+			[]string{string(ResourceLifecycleOwned), string(ResourceLifecycleShared)})}
+	}
+}
+
 // validateNetworkSpec validates a NetworkSpec.
 func validateNetworkSpec(networkSpec NetworkSpec, old NetworkSpec, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
@@ -122,6 +182,12 @@ func validateNetworkSpec(networkSpec NetworkSpec, old NetworkSpec, fldPath *fiel
 		allErrs = append(allErrs, validateSubnets(networkSpec.Subnets, networkSpec.Vnet, fldPath.Child("subnets"))...)
 	}
 
+	if networkSpec.ResourceGroup != "" {
+		if err := validateResourceGroup(networkSpec.ResourceGroup, fldPath.Child("resourceGroup")); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
 	var cidrBlocks []string
 	controlPlaneSubnet, err := networkSpec.GetControlPlaneSubnet()
 	if err != nil {
@@ -147,6 +213,8 @@ func validateNetworkSpec(networkSpec NetworkSpec, old NetworkSpec, fldPath *fiel
 
 	allErrs = append(allErrs, validatePrivateDNSZoneName(networkSpec, fldPath)...)
 
+	allErrs = append(allErrs, validateDNSForwardingRulesetLink(networkSpec, fldPath.Child("dnsForwardingRulesetLink"))...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -224,14 +292,18 @@ func validateSubnetCIDR(subnetCidrBlocks []string, vnetCidrBlocks []string, fldP
 	}
 
 	for _, subnetCidr := range subnetCidrBlocks {
-		subnetCidrIP, _, err := net.ParseCIDR(subnetCidr)
+		_, subnetNw, err := net.ParseCIDR(subnetCidr)
 		if err != nil {
 			allErrs = append(allErrs, field.Invalid(fldPath, subnetCidr, "invalid CIDR format"))
+			continue
 		}
 
+		// The whole subnet range has to be in the vnet range, not only its first address.
 		var found bool
+		subnetOnes, subnetBits := subnetNw.Mask.Size()
 		for _, vnetNw := range vnetNws {
-			if vnetNw.Contains(subnetCidrIP) {
+			vnetOnes, vnetBits := vnetNw.Mask.Size()
+			if vnetNw.Contains(subnetNw.IP) && subnetBits == vnetBits && subnetOnes >= vnetOnes {
 				found = true
 				break
 			}
@@ -245,6 +317,89 @@ func validateSubnetCIDR(subnetCidrBlocks []string, vnetCidrBlocks []string, fldP
 	return allErrs
 }
 
+// subnetCIDR is a CIDR block of a subnet of the cluster.
+type subnetCIDR struct {
+	subnet  string
+	cidr    string
+	network *net.IPNet
+	fldPath *field.Path
+}
+
+// subnetCIDRs returns the valid CIDR blocks of the subnets of the cluster, including the Azure Bastion subnet.
+func (c *AzureCluster) subnetCIDRs() []subnetCIDR {
+	var cidrs []subnetCIDR
+	add := func(subnet SubnetSpec, fldPath *field.Path) {
+		for i, cidr := range subnet.CIDRBlocks {
+			if _, network, err := net.ParseCIDR(cidr); err == nil {
+				cidrs = append(cidrs, subnetCIDR{subnet: subnet.Name, cidr: cidr, network: network, fldPath: fldPath.Index(i)})
+			}
+		}
+	}
+
+	for i, subnet := range c.Spec.NetworkSpec.Subnets {
+		add(subnet, field.NewPath("spec", "networkSpec", "subnets").Index(i).Child("cidrBlocks"))
+	}
+	if c.Spec.BastionSpec.AzureBastion != nil {
+		add(c.Spec.BastionSpec.AzureBastion.Subnet, field.NewPath("spec", "bastionSpec", "azureBastion", "subnet", "cidrBlocks"))
+	}
+	return cidrs
+}
+
+// validateSubnetCIDRsOverlap validates that the CIDR blocks of the subnets of the cluster, including the Azure Bastion
+// subnet, don't overlap each other, and that the Azure Bastion subnet is in the vnet range.
+func (c *AzureCluster) validateSubnetCIDRsOverlap() field.ErrorList {
+	var allErrs field.ErrorList
+	if c.Spec.BastionSpec.AzureBastion != nil && len(c.Spec.NetworkSpec.Vnet.CIDRBlocks) > 0 {
+		allErrs = append(allErrs, validateSubnetCIDR(c.Spec.BastionSpec.AzureBastion.Subnet.CIDRBlocks, c.Spec.NetworkSpec.Vnet.CIDRBlocks,
+			field.NewPath("spec", "bastionSpec", "azureBastion", "subnet", "cidrBlocks"))...)
+	}
+
+	cidrs := c.subnetCIDRs()
+	for i := range cidrs {
+		for j := 0; j < i; j++ {
+			if cidrsOverlap(cidrs[i].network, cidrs[j].network) {
+				allErrs = append(allErrs, field.Invalid(cidrs[i].fldPath, cidrs[i].cidr,
+					fmt.Sprintf("subnet CIDR overlaps with CIDR %s of subnet %s", cidrs[j].cidr, cidrs[j].subnet)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// ValidateClusterNetworkCIDRs validates that the CIDR blocks of the subnets of the cluster don't overlap the pod and
+// service CIDR blocks declared in the cluster network of its Cluster.
+func (c *AzureCluster) ValidateClusterNetworkCIDRs(podCIDRBlocks, serviceCIDRBlocks []string) error {
+	var allErrs field.ErrorList
+	for _, cidr := range c.subnetCIDRs() {
+		for _, declared := range []struct {
+			kind       string
+			cidrBlocks []string
+		}{
+			{kind: "pod", cidrBlocks: podCIDRBlocks},
+			{kind: "service", cidrBlocks: serviceCIDRBlocks},
+		} {
+			for _, block := range declared.cidrBlocks {
+				if _, network, err := net.ParseCIDR(block); err == nil && cidrsOverlap(cidr.network, network) {
+					allErrs = append(allErrs, field.Invalid(cidr.fldPath, cidr.cidr,
+						fmt.Sprintf("subnet CIDR overlaps with the %s CIDR %s of the Cluster", declared.kind, block)))
+				}
+			}
+		}
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "infrastructure.cluster.x-k8s.io", Kind: "AzureCluster"},
+		c.Name, allErrs)
+}
+
+// cidrsOverlap returns true if two networks share at least one address.
+func cidrsOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
 // validateVnetCIDR validates the CIDR blocks of a Vnet.
 func validateVnetCIDR(vnetCIDRBlocks []string, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
@@ -317,6 +472,9 @@ func validateAPIServerLB(lb LoadBalancerSpec, old LoadBalancerSpec, cidrs []stri
 	if old.Name != "" && old.Name != lb.Name {
 		allErrs = append(allErrs, field.Forbidden(fldPath.Child("name"), "API Server load balancer name should not be modified after AzureCluster creation."))
 	}
+	if old.BackendPool.Name != "" && old.BackendPool.Name != lb.BackendPool.Name {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("backendPool", "name"), "API Server load balancer backend pool name should not be modified after AzureCluster creation."))
+	}
 
 	if old.IdleTimeoutInMinutes != nil && !pointer.Int32Equal(old.IdleTimeoutInMinutes, lb.IdleTimeoutInMinutes) {
 		allErrs = append(allErrs, field.Forbidden(fldPath.Child("idleTimeoutInMinutes"), "API Server load balancer idle timeout cannot be modified after AzureCluster creation."))
@@ -382,6 +540,10 @@ func validateNodeOutboundLB(lb *LoadBalancerSpec, old *LoadBalancerSpec, apiserv
 		allErrs = append(allErrs, field.Forbidden(fldPath.Child("name"), "Node outbound load balancer Name should not be modified after AzureCluster creation."))
 	}
 
+	if old != nil && old.BackendPool.Name != "" && old.BackendPool.Name != lb.BackendPool.Name {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("backendPool", "name"), "Node outbound load balancer backend pool name should not be modified after AzureCluster creation."))
+	}
+
 	if old != nil && old.SKU != lb.SKU {
 		allErrs = append(allErrs, field.Forbidden(fldPath.Child("sku"), "Node outbound load balancer SKU should not be modified after AzureCluster creation."))
 	}
@@ -473,6 +635,35 @@ func validatePrivateDNSZoneName(networkSpec NetworkSpec, fldPath *field.Path) fi
 	return allErrs
 }
 
+// validateDNSForwardingRulesetLink validates the DNSForwardingRulesetLink.
+func validateDNSForwardingRulesetLink(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	link := networkSpec.DNSForwardingRulesetLink
+	if link == nil {
+		return nil
+	}
+	if networkSpec.APIServerLB.Type != Internal {
+		allErrs = append(allErrs, field.Invalid(fldPath, networkSpec.APIServerLB.Type,
+			"DNSForwardingRulesetLink is available only if APIServerLB.Type is Internal"))
+	}
+	if success, _ := regexp.MatchString(dnsForwardingRulesetIDRegex, link.RulesetID); !success {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("rulesetID"), link.RulesetID,
+			"RulesetID must be the resource ID of a Microsoft.Network/dnsForwardingRulesets resource"))
+	}
+	if link.Name != "" {
+		if success, _ := regexp.MatchString(dnsForwardingRulesetLinkRegex, link.Name); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), link.Name,
+				fmt.Sprintf("name of DNS forwarding ruleset link doesn't match regex %s", dnsForwardingRulesetLinkRegex)))
+		}
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return allErrs
+}
+
 // validateCloudProviderConfigOverrides validates CloudProviderConfigOverrides.
 func validateCloudProviderConfigOverrides(old, new *CloudProviderConfigOverrides, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
@@ -102,6 +102,145 @@ func TestClusterNameValidation(t *testing.T) {
 	}
 }
 
+func TestAdoptResourcesAnnotationValidation(t *testing.T) {
+	g := NewWithT(t)
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+	}{
+		{
+			name:    "no adoption",
+			wantErr: false,
+		},
+		{
+			name:        "adopt owned resources",
+			annotations: map[string]string{AdoptResourcesAnnotation: "owned"},
+			wantErr:     false,
+		},
+		{
+			name:        "adopt shared resources",
+			annotations: map[string]string{AdoptResourcesAnnotation: "shared"},
+			wantErr:     false,
+		},
+		{
+			name:        "adopt resources with an invalid lifecycle",
+			annotations: map[string]string{AdoptResourcesAnnotation: "true"},
+			wantErr:     true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			azureCluster := AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-cluster",
+					Annotations: tc.annotations,
+				},
+			}
+
+			allErrs := azureCluster.validateAdoptResourcesAnnotation()
+			if tc.wantErr {
+				g.Expect(allErrs).ToNot(BeNil())
+			} else {
+				g.Expect(allErrs).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateAzureEnvironment(t *testing.T) {
+	g := NewWithT(t)
+	tests := []struct {
+		name             string
+		azureEnvironment string
+		wantErr          bool
+	}{
+		{
+			name:    "defaulted environment",
+			wantErr: false,
+		},
+		{
+			name:             "public cloud",
+			azureEnvironment: "AzurePublicCloud",
+			wantErr:          false,
+		},
+		{
+			name:             "us government cloud",
+			azureEnvironment: "AzureUSGovernmentCloud",
+			wantErr:          false,
+		},
+		{
+			name:             "china cloud in lower case",
+			azureEnvironment: "azurechinacloud",
+			wantErr:          false,
+		},
+		{
+			name:             "unknown cloud",
+			azureEnvironment: "AzureMoonCloud",
+			wantErr:          true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			allErrs := ValidateAzureEnvironment(tc.azureEnvironment, field.NewPath("spec", "azureEnvironment"))
+			if tc.wantErr {
+				g.Expect(allErrs).ToNot(BeNil())
+			} else {
+				g.Expect(allErrs).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateResourceManagerEndpoint(t *testing.T) {
+	g := NewWithT(t)
+	tests := []struct {
+		name             string
+		azureEnvironment string
+		endpoint         string
+		wantErr          bool
+	}{
+		{
+			name:             "public cloud without endpoint",
+			azureEnvironment: "AzurePublicCloud",
+			wantErr:          false,
+		},
+		{
+			name:             "public cloud with endpoint",
+			azureEnvironment: "AzurePublicCloud",
+			endpoint:         "https://management.local.azurestack.external/",
+			wantErr:          true,
+		},
+		{
+			name:             "azure stack with endpoint",
+			azureEnvironment: "AzureStackCloud",
+			endpoint:         "https://management.local.azurestack.external/",
+			wantErr:          false,
+		},
+		{
+			name:             "azure stack without endpoint",
+			azureEnvironment: "AzureStackCloud",
+			wantErr:          true,
+		},
+		{
+			name:             "azure stack with http endpoint",
+			azureEnvironment: "AzureStackCloud",
+			endpoint:         "http://management.local.azurestack.external/",
+			wantErr:          true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			allErrs := validateResourceManagerEndpoint(tc.endpoint, tc.azureEnvironment, field.NewPath("spec", "resourceManagerEndpoint"))
+			if tc.wantErr {
+				g.Expect(allErrs).ToNot(BeNil())
+			} else {
+				g.Expect(allErrs).To(BeNil())
+			}
+		})
+	}
+}
+
 func TestClusterWithPreexistingVnetValid(t *testing.T) {
 	g := NewWithT(t)
 
@@ -304,6 +443,30 @@ func TestNetworkSpecWithPreexistingVnetInvalidResourceGroup(t *testing.T) {
 	})
 }
 
+func TestNetworkSpecInvalidNetworkResourceGroup(t *testing.T) {
+	g := NewWithT(t)
+
+	type test struct {
+		name        string
+		networkSpec NetworkSpec
+	}
+
+	testCase := test{
+		name:        "azurecluster networkspec - invalid network resource group",
+		networkSpec: createValidNetworkSpec(),
+	}
+
+	testCase.networkSpec.ResourceGroup = "invalid-name###"
+
+	t.Run(testCase.name, func(t *testing.T) {
+		errs := validateNetworkSpec(testCase.networkSpec, NetworkSpec{}, field.NewPath("spec").Child("networkSpec"))
+		g.Expect(errs).To(HaveLen(1))
+		g.Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+		g.Expect(errs[0].Field).To(Equal("spec.networkSpec.resourceGroup"))
+		g.Expect(errs[0].BadValue).To(BeEquivalentTo(testCase.networkSpec.ResourceGroup))
+	})
+}
+
 func TestNetworkSpecWithoutPreexistingVnetValid(t *testing.T) {
 	g := NewWithT(t)
 
@@ -597,6 +760,18 @@ func TestValidateSubnetCIDR(t *testing.T) {
 			subnetCidrBlocks: []string{"10.1.0.0/16", "10.0.0.0/16", "11.1.0.0/16"},
 			wantErr:          false,
 		},
+		{
+			name:             "subnet cidr larger than vnet range",
+			vnetCidrBlocks:   []string{"10.0.0.0/16"},
+			subnetCidrBlocks: []string{"10.0.0.0/8"},
+			wantErr:          true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "subnets.cidrBlocks",
+				BadValue: "10.0.0.0/8",
+				Detail:   "subnet CIDR not in vnet CIDR range",
+			},
+		},
 	}
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -617,6 +792,118 @@ func TestValidateSubnetCIDR(t *testing.T) {
 	}
 }
 
+func TestValidateSubnetCIDRsOverlap(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		cluster     *AzureCluster
+		wantErr     bool
+		expectedErr field.Error
+	}{
+		{
+			name:    "subnet cidrs don't overlap",
+			cluster: createAzureClusterWithSubnetCIDRs([]string{"10.0.0.0/16"}, []string{"10.1.0.0/16"}, "10.255.255.224/27"),
+			wantErr: false,
+		},
+		{
+			name:    "subnet cidrs overlap",
+			cluster: createAzureClusterWithSubnetCIDRs([]string{"10.0.0.0/16"}, []string{"10.0.128.0/17"}, "10.255.255.224/27"),
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.networkSpec.subnets[1].cidrBlocks[0]",
+				BadValue: "10.0.128.0/17",
+				Detail:   "subnet CIDR overlaps with CIDR 10.0.0.0/16 of subnet control-plane-subnet",
+			},
+		},
+		{
+			name:    "bastion subnet cidr overlaps",
+			cluster: createAzureClusterWithSubnetCIDRs([]string{"10.0.0.0/16"}, []string{"10.1.0.0/16"}, "10.1.255.224/27"),
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.bastionSpec.azureBastion.subnet.cidrBlocks[0]",
+				BadValue: "10.1.255.224/27",
+				Detail:   "subnet CIDR overlaps with CIDR 10.1.0.0/16 of subnet node-subnet",
+			},
+		},
+		{
+			name:    "bastion subnet cidr not in vnet range",
+			cluster: createAzureClusterWithSubnetCIDRs([]string{"10.0.0.0/16"}, []string{"10.1.0.0/16"}, "11.255.255.224/27"),
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.bastionSpec.azureBastion.subnet.cidrBlocks",
+				BadValue: "11.255.255.224/27",
+				Detail:   "subnet CIDR not in vnet CIDR range",
+			},
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.cluster.validateSubnetCIDRsOverlap()
+			if testCase.wantErr {
+				g.Expect(err).To(HaveLen(1))
+				g.Expect(err[0].Error()).To(Equal(testCase.expectedErr.Error()))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestValidateClusterNetworkCIDRs(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name              string
+		podCIDRBlocks     []string
+		serviceCIDRBlocks []string
+		wantErr           bool
+		expectedErr       string
+	}{
+		{
+			name:              "no cluster network cidrs",
+			podCIDRBlocks:     nil,
+			serviceCIDRBlocks: nil,
+			wantErr:           false,
+		},
+		{
+			name:              "cluster network cidrs don't overlap subnets",
+			podCIDRBlocks:     []string{"192.168.0.0/16"},
+			serviceCIDRBlocks: []string{"10.96.0.0/12"},
+			wantErr:           false,
+		},
+		{
+			name:              "pod cidr overlaps a subnet",
+			podCIDRBlocks:     []string{"10.1.0.0/24"},
+			serviceCIDRBlocks: []string{"10.96.0.0/12"},
+			wantErr:           true,
+			expectedErr:       "subnet CIDR overlaps with the pod CIDR 10.1.0.0/24 of the Cluster",
+		},
+		{
+			name:              "service cidr overlaps the bastion subnet",
+			podCIDRBlocks:     []string{"192.168.0.0/16"},
+			serviceCIDRBlocks: []string{"10.255.0.0/16"},
+			wantErr:           true,
+			expectedErr:       "subnet CIDR overlaps with the service CIDR 10.255.0.0/16 of the Cluster",
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			cluster := createAzureClusterWithSubnetCIDRs([]string{"10.0.0.0/16"}, []string{"10.1.0.0/16"}, "10.255.255.224/27")
+			err := cluster.ValidateClusterNetworkCIDRs(testCase.podCIDRBlocks, testCase.serviceCIDRBlocks)
+			if testCase.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(testCase.expectedErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestValidateSecurityRule(t *testing.T) {
 	g := NewWithT(t)
 
@@ -948,6 +1235,110 @@ func TestPrivateDNSZoneName(t *testing.T) {
 	}
 }
 
+func TestValidateDNSForwardingRulesetLink(t *testing.T) {
+	g := NewWithT(t)
+
+	rulesetID := "/subscriptions/123/resourceGroups/dns-rg/providers/Microsoft.Network/dnsForwardingRulesets/my-ruleset"
+
+	testcases := []struct {
+		name        string
+		network     NetworkSpec
+		wantErr     bool
+		expectedErr field.Error
+	}{
+		{
+			name: "no link",
+			network: NetworkSpec{
+				APIServerLB: createValidAPIServerLB(),
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid link",
+			network: NetworkSpec{
+				APIServerLB: createValidAPIServerInternalLB(),
+				DNSForwardingRulesetLink: &DNSForwardingRulesetLinkSpec{
+					RulesetID: rulesetID,
+					Name:      "my-vnet-ruleset-link",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "public api server lb",
+			network: NetworkSpec{
+				APIServerLB: LoadBalancerSpec{
+					Name: "my-lb",
+					Type: Public,
+				},
+				DNSForwardingRulesetLink: &DNSForwardingRulesetLinkSpec{
+					RulesetID: rulesetID,
+				},
+			},
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.networkSpec.dnsForwardingRulesetLink",
+				BadValue: "Public",
+				Detail:   "DNSForwardingRulesetLink is available only if APIServerLB.Type is Internal",
+			},
+			wantErr: true,
+		},
+		{
+			name: "ruleset ID of another resource type",
+			network: NetworkSpec{
+				APIServerLB: createValidAPIServerInternalLB(),
+				DNSForwardingRulesetLink: &DNSForwardingRulesetLinkSpec{
+					RulesetID: "/subscriptions/123/resourceGroups/dns-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+				},
+			},
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.networkSpec.dnsForwardingRulesetLink.rulesetID",
+				BadValue: "/subscriptions/123/resourceGroups/dns-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+				Detail:   "RulesetID must be the resource ID of a Microsoft.Network/dnsForwardingRulesets resource",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid link name",
+			network: NetworkSpec{
+				APIServerLB: createValidAPIServerInternalLB(),
+				DNSForwardingRulesetLink: &DNSForwardingRulesetLinkSpec{
+					RulesetID: rulesetID,
+					Name:      "bad@name-",
+				},
+			},
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.networkSpec.dnsForwardingRulesetLink.name",
+				BadValue: "bad@name-",
+				Detail:   "name of DNS forwarding ruleset link doesn't match regex ^[a-zA-Z0-9][-\\w\\.]{0,78}[\\w]$",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range testcases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateDNSForwardingRulesetLink(test.network, field.NewPath("spec", "networkSpec", "dnsForwardingRulesetLink"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+				found := false
+				for _, actual := range err {
+					if actual.Error() == test.expectedErr.Error() {
+						found = true
+					}
+				}
+				g.Expect(found).To(BeTrue())
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
 func TestValidateNodeOutboundLB(t *testing.T) {
 	g := NewWithT(t)
 
@@ -1306,6 +1697,41 @@ func createValidSubnets() Subnets {
 	}
 }
 
+func createAzureClusterWithSubnetCIDRs(controlPlaneCIDRs, nodeCIDRs []string, bastionCIDR string) *AzureCluster {
+	return &AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-cluster",
+		},
+		Spec: AzureClusterSpec{
+			NetworkSpec: NetworkSpec{
+				Vnet: VnetSpec{
+					CIDRBlocks: []string{DefaultVnetCIDR},
+				},
+				Subnets: Subnets{
+					{
+						Name:       "control-plane-subnet",
+						Role:       SubnetControlPlane,
+						CIDRBlocks: controlPlaneCIDRs,
+					},
+					{
+						Name:       "node-subnet",
+						Role:       SubnetNode,
+						CIDRBlocks: nodeCIDRs,
+					},
+				},
+			},
+			BastionSpec: BastionSpec{
+				AzureBastion: &AzureBastion{
+					Subnet: SubnetSpec{
+						Name:       DefaultAzureBastionSubnetName,
+						CIDRBlocks: []string{bastionCIDR},
+					},
+				},
+			},
+		},
+	}
+}
+
 func createValidVnet() VnetSpec {
 	return VnetSpec{
 		ResourceGroup: "custom-vnet",
@@ -38,6 +38,7 @@ func (c *AzureCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha4-azurecluster,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azureclusters,versions=v1alpha4,name=validation.azurecluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha4-azurecluster-lookup,mutating=false,failurePolicy=ignore,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azureclusters,versions=v1alpha4,name=lookupvalidation.azurecluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha4-azurecluster,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azureclusters,versions=v1alpha4,name=default.azurecluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
 var _ webhook.Validator = &AzureCluster{}
@@ -87,7 +88,21 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) error {
 	if !reflect.DeepEqual(c.Spec.AzureEnvironment, old.Spec.AzureEnvironment) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "AzureEnvironment"),
-				c.Spec.ResourceGroup, "field is immutable"),
+				c.Spec.AzureEnvironment, "field is immutable"),
+		)
+	}
+
+	if !reflect.DeepEqual(c.Spec.ResourceManagerEndpoint, old.Spec.ResourceManagerEndpoint) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "resourceManagerEndpoint"),
+				c.Spec.ResourceManagerEndpoint, "field is immutable"),
+		)
+	}
+
+	if !reflect.DeepEqual(c.Spec.CloudProviderMode, old.Spec.CloudProviderMode) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "cloudProviderMode"),
+				c.Spec.CloudProviderMode, "field is immutable"),
 		)
 	}
 
@@ -98,6 +113,28 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
+	if !reflect.DeepEqual(c.Spec.NetworkSpec.ResourceGroup, old.Spec.NetworkSpec.ResourceGroup) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "networkSpec", "resourceGroup"),
+				c.Spec.NetworkSpec.ResourceGroup, "field is immutable"),
+		)
+	}
+
+	// The vnet is looked up by name and resource group, changing them would orphan the existing vnet.
+	if old.Spec.NetworkSpec.Vnet.Name != "" && c.Spec.NetworkSpec.Vnet.Name != old.Spec.NetworkSpec.Vnet.Name {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "networkSpec", "vnet", "name"),
+				c.Spec.NetworkSpec.Vnet.Name, "field is immutable"),
+		)
+	}
+
+	if old.Spec.NetworkSpec.Vnet.ResourceGroup != "" && c.Spec.NetworkSpec.Vnet.ResourceGroup != old.Spec.NetworkSpec.Vnet.ResourceGroup {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "networkSpec", "vnet", "resourceGroup"),
+				c.Spec.NetworkSpec.Vnet.ResourceGroup, "field is immutable"),
+		)
+	}
+
 	// Allow enabling azure bastion but avoid disabling it.
 	if old.Spec.BastionSpec.AzureBastion != nil && !reflect.DeepEqual(old.Spec.BastionSpec.AzureBastion, c.Spec.BastionSpec.AzureBastion) {
 		allErrs = append(allErrs,
@@ -106,6 +143,14 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
+	// Allow linking a DNS forwarding ruleset but avoid changing or removing the link.
+	if old.Spec.NetworkSpec.DNSForwardingRulesetLink != nil && !reflect.DeepEqual(old.Spec.NetworkSpec.DNSForwardingRulesetLink, c.Spec.NetworkSpec.DNSForwardingRulesetLink) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "networkSpec", "dnsForwardingRulesetLink"),
+				c.Spec.NetworkSpec.DNSForwardingRulesetLink, "field is immutable"),
+		)
+	}
+
 	if !reflect.DeepEqual(c.Spec.NetworkSpec.ControlPlaneOutboundLB, old.Spec.NetworkSpec.ControlPlaneOutboundLB) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "networkSpec", "controlPlaneOutboundLB"),
@@ -186,6 +186,38 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "azurecluster cloud provider mode is immutable",
+			oldCluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					CloudProviderMode: CloudProviderModeInTree,
+				},
+			},
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					CloudProviderMode: CloudProviderModeExternal,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azurecluster network resource group is immutable",
+			oldCluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						ResourceGroup: "network-rg",
+					},
+				},
+			},
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						ResourceGroup: "network-rg-2",
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "azurecluster location is immutable",
 			oldCluster: &AzureCluster{
@@ -228,6 +260,42 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "azurecluster vnet name is immutable",
+			oldCluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{Name: "my-vnet"},
+					},
+				},
+			},
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{Name: "my-vnet-2"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azurecluster vnet resource group is immutable",
+			oldCluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"},
+					},
+				},
+			},
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg-2"},
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "control plane outbound lb is immutable",
 			oldCluster: &AzureCluster{
@@ -246,6 +314,30 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "dns forwarding ruleset link is immutable",
+			oldCluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						DNSForwardingRulesetLink: &DNSForwardingRulesetLinkSpec{
+							RulesetID: "/subscriptions/123/resourceGroups/dns-rg/providers/Microsoft.Network/dnsForwardingRulesets/my-ruleset",
+							Name:      "my-link",
+						},
+					},
+				},
+			},
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						DNSForwardingRulesetLink: &DNSForwardingRulesetLinkSpec{
+							RulesetID: "/subscriptions/123/resourceGroups/dns-rg/providers/Microsoft.Network/dnsForwardingRulesets/other-ruleset",
+							Name:      "my-link",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -85,11 +85,9 @@ func (s *AzureMachineSpec) SetIdentityDefaults() {
 }
 
 // SetDefaults sets to the defaults for the AzureMachineSpec.
+// The SSH public key is not defaulted here: when it is omitted on an AzureMachine a keypair is
+// generated for the cluster and stored in a Secret by the SSH key defaulting webhook.
 func (s *AzureMachineSpec) SetDefaults(log logr.Logger) {
-	err := s.SetDefaultSSHPublicKey()
-	if err != nil {
-		log.Error(err, "SetDefaultSshPublicKey failed")
-	}
 	s.SetDefaultCachingType()
 	s.SetDataDisksDefaults()
 	s.SetIdentityDefaults()
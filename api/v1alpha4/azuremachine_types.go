@@ -28,6 +28,22 @@ const (
 	// MachineFinalizer allows ReconcileAzureMachine to clean up Azure resources associated with AzureMachine before
 	// removing it from the apiserver.
 	MachineFinalizer = "azuremachine.infrastructure.cluster.x-k8s.io"
+
+	// The following finalizers track the Azure resources created for an AzureMachine. Each one is removed once its
+	// resources are deleted, so that an interrupted deletion resumes where it stopped rather than from scratch.
+
+	// VMFinalizer tracks the virtual machine of an AzureMachine.
+	VMFinalizer = MachineFinalizer + "/virtualmachine"
+	// NetworkInterfacesFinalizer tracks the network interfaces of an AzureMachine.
+	NetworkInterfacesFinalizer = MachineFinalizer + "/networkinterfaces"
+	// InboundNatRulesFinalizer tracks the inbound NAT rules of an AzureMachine.
+	InboundNatRulesFinalizer = MachineFinalizer + "/inboundnatrules"
+	// PublicIPsFinalizer tracks the public IPs of an AzureMachine.
+	PublicIPsFinalizer = MachineFinalizer + "/publicips"
+	// DisksFinalizer tracks the OS disk of an AzureMachine.
+	DisksFinalizer = MachineFinalizer + "/disks"
+	// AvailabilitySetFinalizer tracks the availability set of an AzureMachine.
+	AvailabilitySetFinalizer = MachineFinalizer + "/availabilityset"
 )
 
 // AzureMachineSpec defines the desired state of AzureMachine.
@@ -112,6 +128,27 @@ type AzureMachineSpec struct {
 	// SubnetName selects the Subnet where the VM will be placed
 	// +optional
 	SubnetName string `json:"subnetName,omitempty"`
+
+	// EnableRDP gives Remote Desktop access to a Windows machine through an inbound NAT rule of the node outbound load
+	// balancer. The security group of the subnet of the machine must allow inbound traffic to port 3389. Default is
+	// false for disabled.
+	// +optional
+	EnableRDP bool `json:"enableRDP,omitempty"`
+
+	// BootstrapDataKeyVault stores the bootstrap data of a Linux machine in an Azure Key Vault, which the machine fetches
+	// it from at boot with a user-assigned identity, rather than passing it in the custom data of the VM.
+	// +optional
+	BootstrapDataKeyVault *BootstrapDataKeyVault `json:"bootstrapDataKeyVault,omitempty"`
+
+	// AzureMonitor installs an Azure Monitor agent on the machine, sending its metrics and logs to Azure Monitor.
+	// +optional
+	AzureMonitor *AzureMonitor `json:"azureMonitor,omitempty"`
+
+	// Backup enrolls the VM of a control plane machine, with all its disks, into a backup policy of a Recovery Services
+	// vault once the VM is created, for point-in-time recovery of its OS and etcd disks. It is ignored for the other
+	// machines.
+	// +optional
+	Backup *Backup `json:"backup,omitempty"`
 }
 
 // SpotVMOptions defines the options relevant to running the Machine on Spot VMs.
@@ -121,6 +158,34 @@ type SpotVMOptions struct {
 	MaxPrice *resource.Quantity `json:"maxPrice,omitempty"`
 }
 
+// BootstrapDataKeyVault defines the Azure Key Vault the bootstrap data of a machine is stored in, as a secret deleted
+// once the node of the machine has joined the cluster.
+type BootstrapDataKeyVault struct {
+	// VaultName is the name of the Key Vault. The identity of the controller must be allowed to set and delete its
+	// secrets.
+	VaultName string `json:"vaultName"`
+
+	// Identity is the user-assigned identity the machine fetches its bootstrap data with. It must be one of the
+	// user-assigned identities of the machine, and be allowed to get the secrets of the Key Vault.
+	Identity UserAssignedIdentity `json:"identity"`
+}
+
+// Backup defines the backup policy of a Recovery Services vault the VM of a machine is protected with. The protection
+// is stopped when the machine is deleted, retaining the existing recovery points.
+type Backup struct {
+	// VaultResourceGroup is the resource group of the Recovery Services vault. Defaults to the resource group of the
+	// cluster.
+	// +optional
+	VaultResourceGroup string `json:"vaultResourceGroup,omitempty"`
+
+	// VaultName is the name of the Recovery Services vault, which must be in the subscription and the location of the
+	// cluster. The identity of the controller must be allowed to manage its protected items.
+	VaultName string `json:"vaultName"`
+
+	// PolicyName is the name of the VM backup policy of the vault.
+	PolicyName string `json:"policyName"`
+}
+
 // AzureMachineStatus defines the observed state of AzureMachine.
 type AzureMachineStatus struct {
 	// Ready is true when the provider resource is ready.
@@ -175,6 +240,16 @@ type AzureMachineStatus struct {
 	// Conditions defines current service state of the AzureMachine.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// LongRunningOperationStates saves the states of the Azure long-running operations of the machine so that they
+	// can be continued on the next reconciliation loops, rather than blocking the controller until they complete.
+	// +optional
+	LongRunningOperationStates Futures `json:"longRunningOperationStates,omitempty"`
+
+	// BootstrapDataSecretState is the state of the Key Vault secret storing the bootstrap data of the machine, either
+	// Succeeded once it is created or Deleted once the node of the machine has joined the cluster.
+	// +optional
+	BootstrapDataSecretState *ProvisioningState `json:"bootstrapDataSecretState,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -17,16 +17,29 @@ limitations under the License.
 package v1alpha4
 
 import (
+	"crypto/rsa"
 	"encoding/base64"
 	"fmt"
+	"regexp"
 
 	"github.com/google/uuid"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-30/compute"
 	"golang.org/x/crypto/ssh"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
+// minimumSSHKeyBits is the minimum size of the RSA SSH public keys accepted by Azure.
+const minimumSSHKeyBits = 2048
+
+const (
+	// keyVaultNameRegex is the format of the names of Azure Key Vaults.
+	keyVaultNameRegex = `^[a-zA-Z][a-zA-Z0-9-]{1,22}[a-zA-Z0-9]$`
+	// recoveryServicesVaultNameRegex is the format of the names of Recovery Services vaults.
+	recoveryServicesVaultNameRegex = `^[a-zA-Z][a-zA-Z0-9-]{1,49}$`
+)
+
 // ValidateAzureMachineSpec check for validation errors of azuremachine.spec.
 func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 	var allErrs field.ErrorList
@@ -39,8 +52,11 @@ func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 		allErrs = append(allErrs, errs...)
 	}
 
-	if errs := ValidateSSHKey(spec.SSHPublicKey, field.NewPath("sshPublicKey")); len(errs) > 0 {
-		allErrs = append(allErrs, errs...)
+	// AzureMachineTemplates may omit the SSH public key, AzureMachines get one generated on creation.
+	if spec.SSHPublicKey != "" {
+		if errs := ValidateSSHKey(spec.SSHPublicKey, field.NewPath("sshPublicKey")); len(errs) > 0 {
+			allErrs = append(allErrs, errs...)
+		}
 	}
 
 	if errs := ValidateSystemAssignedIdentity(spec.Identity, "", spec.RoleAssignmentName, field.NewPath("roleAssignmentName")); len(errs) > 0 {
@@ -55,6 +71,150 @@ func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateSpotVMOptions(spec.SpotVMOptions, field.NewPath("spotVMOptions")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateTags(spec.AdditionalTags, field.NewPath("spec", "additionalTags")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateRDP(spec.EnableRDP, spec.OSDisk.OSType, field.NewPath("enableRDP")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateBootstrapDataKeyVault(spec.BootstrapDataKeyVault, spec, field.NewPath("bootstrapDataKeyVault")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateAzureMonitor(spec.AzureMonitor, spec.Identity, spec.UserAssignedIdentities, field.NewPath("azureMonitor")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateBackup(spec.Backup, field.NewPath("backup")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	return allErrs
+}
+
+// ValidateAzureMonitor validates the Azure Monitor agent of machines: AzureMonitorAgent authenticates with one of
+// their managed identities, while LogAnalyticsAgent authenticates with the key of a Log Analytics workspace.
+func ValidateAzureMonitor(monitor *AzureMonitor, identityType VMIdentity, userAssignedIdentities []UserAssignedIdentity, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if monitor == nil {
+		return allErrs
+	}
+
+	switch monitor.Agent {
+	case MonitoringAgentAzureMonitor:
+		if monitor.WorkspaceSecretRef != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("workspaceSecretRef"), "the workspace secret can only be set for the LogAnalyticsAgent agent"))
+		}
+		if monitor.Identity == nil {
+			if identityType != VMIdentitySystemAssigned {
+				allErrs = append(allErrs, field.Required(fldPath.Child("identity"), "the AzureMonitorAgent agent requires a user-assigned identity, unless the machines have a system-assigned identity"))
+			}
+			break
+		}
+		found := false
+		for _, identity := range userAssignedIdentities {
+			if identity.ProviderID == monitor.Identity.ProviderID {
+				found = true
+				break
+			}
+		}
+		if identityType != VMIdentityUserAssigned || !found {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("identity", "providerID"), monitor.Identity.ProviderID,
+				"the identity must be one of the user-assigned identities of the machines"))
+		}
+	case MonitoringAgentLogAnalytics:
+		if monitor.Identity != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("identity"), "the identity can only be set for the AzureMonitorAgent agent"))
+		}
+		if monitor.WorkspaceSecretRef == nil || monitor.WorkspaceSecretRef.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("workspaceSecretRef", "name"), "the LogAnalyticsAgent agent requires the secret of a Log Analytics workspace"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("agent"), monitor.Agent,
+			[]string{string(MonitoringAgentAzureMonitor), string(MonitoringAgentLogAnalytics)}))
+	}
+
+	return allErrs
+}
+
+// ValidateBackup validates the backup policy of a machine.
+func ValidateBackup(backup *Backup, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if backup == nil {
+		return allErrs
+	}
+
+	if backup.VaultResourceGroup != "" {
+		if success, _ := regexp.MatchString(resourceGroupRegex, backup.VaultResourceGroup); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("vaultResourceGroup"), backup.VaultResourceGroup,
+				fmt.Sprintf("resource group names must match the regex %s", resourceGroupRegex)))
+		}
+	}
+
+	if success, _ := regexp.MatchString(recoveryServicesVaultNameRegex, backup.VaultName); !success {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("vaultName"), backup.VaultName,
+			fmt.Sprintf("Recovery Services vault names must match the regex %s", recoveryServicesVaultNameRegex)))
+	}
+
+	if backup.PolicyName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("policyName"), "the backup policy name is required"))
+	}
+
+	return allErrs
+}
+
+// ValidateBootstrapDataKeyVault validates the Key Vault storing the bootstrap data of a machine, which can only be
+// fetched by Linux machines bootstrapped with cloud-init using one of their user-assigned identities.
+func ValidateBootstrapDataKeyVault(keyVault *BootstrapDataKeyVault, spec AzureMachineSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if keyVault == nil {
+		return allErrs
+	}
+
+	if success, _ := regexp.MatchString(keyVaultNameRegex, keyVault.VaultName); !success {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("vaultName"), keyVault.VaultName,
+			fmt.Sprintf("Key Vault names must match the regex %s", keyVaultNameRegex)))
+	}
+
+	found := false
+	for _, identity := range spec.UserAssignedIdentities {
+		if identity.ProviderID == keyVault.Identity.ProviderID {
+			found = true
+			break
+		}
+	}
+	if spec.Identity != VMIdentityUserAssigned || !found {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("identity", "providerID"), keyVault.Identity.ProviderID,
+			"the identity must be one of the user-assigned identities of the machine"))
+	}
+
+	if spec.OSDisk.OSType == string(compute.Windows) {
+		allErrs = append(allErrs, field.Invalid(fldPath, keyVault.VaultName, "Windows machines cannot fetch their bootstrap data from Key Vault"))
+	}
+
+	if spec.OSDisk.BootstrapFormat == BootstrapFormatIgnition {
+		allErrs = append(allErrs, field.Invalid(fldPath, keyVault.VaultName, "machines bootstrapped with Ignition cannot fetch their bootstrap data from Key Vault"))
+	}
+
+	return allErrs
+}
+
+// ValidateRDP validates that Remote Desktop access is only enabled for Windows machines.
+func ValidateRDP(enableRDP bool, osType string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if enableRDP && osType != string(compute.Windows) {
+		allErrs = append(allErrs, field.Invalid(fldPath, enableRDP, "Remote Desktop access can only be enabled for Windows machines"))
+	}
 	return allErrs
 }
 
@@ -62,17 +222,36 @@ func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 func ValidateSSHKey(sshKey string, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
+	if sshKey == "" {
+		allErrs = append(allErrs, field.Required(fldPath, "the SSH public key is required"))
+		return allErrs
+	}
+
 	decoded, err := base64.StdEncoding.DecodeString(sshKey)
 	if err != nil {
 		allErrs = append(allErrs, field.Invalid(fldPath, sshKey, "the SSH public key is not properly base64 encoded"))
 		return allErrs
 	}
 
-	if _, _, _, _, err := ssh.ParseAuthorizedKey(decoded); err != nil {
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey(decoded)
+	if err != nil {
 		allErrs = append(allErrs, field.Invalid(fldPath, sshKey, "the SSH public key is not valid"))
 		return allErrs
 	}
 
+	// Azure only accepts RSA keys of at least 2048 bits, other keys fail when creating the VM.
+	if publicKey.Type() != ssh.KeyAlgoRSA {
+		allErrs = append(allErrs, field.Invalid(fldPath, sshKey,
+			fmt.Sprintf("the SSH public key type %s is not supported, only %s keys are", publicKey.Type(), ssh.KeyAlgoRSA)))
+		return allErrs
+	}
+	if cryptoKey, ok := publicKey.(ssh.CryptoPublicKey); ok {
+		if rsaKey, ok := cryptoKey.CryptoPublicKey().(*rsa.PublicKey); ok && rsaKey.N.BitLen() < minimumSSHKeyBits {
+			allErrs = append(allErrs, field.Invalid(fldPath, sshKey,
+				fmt.Sprintf("the SSH public key has %d bits, it should have at least %d", rsaKey.N.BitLen(), minimumSSHKeyBits)))
+		}
+	}
+
 	return allErrs
 }
 
@@ -104,6 +283,23 @@ func ValidateUserAssignedIdentity(identityType VMIdentity, userAssignedIdentetie
 	return allErrs
 }
 
+// ValidateSpotVMOptions validates the Spot VM options.
+func ValidateSpotVMOptions(spotVMOptions *SpotVMOptions, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spotVMOptions == nil || spotVMOptions.MaxPrice == nil {
+		return allErrs
+	}
+
+	// Azure only accepts -1, to never evict the VM for price reasons, or a positive maximum price.
+	maxPrice := spotVMOptions.MaxPrice
+	if maxPrice.Sign() <= 0 && maxPrice.Cmp(resource.MustParse("-1")) != 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxPrice"), maxPrice.String(),
+			"the maximum price should be greater than 0, or -1 to pay up to the on-demand price"))
+	}
+	return allErrs
+}
+
 // ValidateDataDisks validates a list of data disks.
 func ValidateDataDisks(dataDisks []DataDisk, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -163,6 +359,14 @@ func ValidateOSDisk(osDisk OSDisk, fieldPath *field.Path) field.ErrorList {
 		allErrs = append(allErrs, field.Required(fieldPath.Child("OSType"), "the OS type cannot be empty"))
 	}
 
+	if osDisk.BootstrapFormat == BootstrapFormatIgnition && osDisk.OSType == string(compute.Windows) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("BootstrapFormat"), osDisk.BootstrapFormat, "Windows machines cannot be bootstrapped with Ignition"))
+	}
+
+	if osDisk.Distribution != "" && osDisk.OSType == string(compute.Windows) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("Distribution"), osDisk.Distribution, "the Linux distribution cannot be set for Windows machines"))
+	}
+
 	allErrs = append(allErrs, validateCachingType(osDisk.CachingType, fieldPath)...)
 
 	if osDisk.ManagedDisk != nil {
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha4
 
 import (
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/base64"
@@ -30,6 +31,8 @@ import (
 
 	. "github.com/onsi/gomega"
 	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -56,6 +59,29 @@ func TestAzureMachine_ValidateSSHKey(t *testing.T) {
 			sshKey:  generateSSHPublicKey(false),
 			wantErr: true,
 		},
+		{
+			name:    "empty ssh key",
+			sshKey:  "",
+			wantErr: true,
+		},
+		{
+			name: "ed25519 ssh key",
+			sshKey: func() string {
+				publicKey, _, _ := ed25519.GenerateKey(rand.Reader)
+				sshPublicKey, _ := ssh.NewPublicKey(publicKey)
+				return base64.StdEncoding.EncodeToString(ssh.MarshalAuthorizedKey(sshPublicKey))
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "rsa ssh key smaller than 2048 bits",
+			sshKey: func() string {
+				privateKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+				sshPublicKey, _ := ssh.NewPublicKey(&privateKey.PublicKey)
+				return base64.StdEncoding.EncodeToString(ssh.MarshalAuthorizedKey(sshPublicKey))
+			}(),
+			wantErr: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -99,6 +125,16 @@ func TestAzureMachine_ValidateOSDisk(t *testing.T) {
 			wantErr: true,
 			osDisk:  createOSDiskWithCacheType("invalid_cache_type"),
 		},
+		{
+			name:    "valid ignition os disk spec",
+			wantErr: false,
+			osDisk: OSDisk{
+				DiskSizeGB:      to.Int32Ptr(30),
+				OSType:          "Linux",
+				CachingType:     "ReadWrite",
+				BootstrapFormat: BootstrapFormatIgnition,
+			},
+		},
 		{
 			name:    "valid ephemeral os disk spec",
 			wantErr: false,
@@ -198,6 +234,16 @@ func generateNegativeTestCases() []osDiskTestInput {
 				Option: string(compute.Local),
 			},
 		},
+		{
+			DiskSizeGB:      to.Int32Ptr(128),
+			OSType:          "Windows",
+			BootstrapFormat: BootstrapFormatIgnition,
+		},
+		{
+			DiskSizeGB:   to.Int32Ptr(128),
+			OSType:       "Windows",
+			Distribution: LinuxDistributionAzureLinux,
+		},
 	}
 
 	for i, input := range invalidDiskSpecs {
@@ -456,6 +502,363 @@ func TestAzureMachine_ValidateSystemAssignedIdentity(t *testing.T) {
 	}
 }
 
+func TestAzureMachine_ValidateSpotVMOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	maxPrice := func(price string) *SpotVMOptions {
+		q := resource.MustParse(price)
+		return &SpotVMOptions{MaxPrice: &q}
+	}
+
+	tests := []struct {
+		name          string
+		spotVMOptions *SpotVMOptions
+		wantErr       bool
+	}{
+		{
+			name:          "no spot options",
+			spotVMOptions: nil,
+			wantErr:       false,
+		},
+		{
+			name:          "no max price",
+			spotVMOptions: &SpotVMOptions{},
+			wantErr:       false,
+		},
+		{
+			name:          "positive max price",
+			spotVMOptions: maxPrice("0.05"),
+			wantErr:       false,
+		},
+		{
+			name:          "on-demand max price",
+			spotVMOptions: maxPrice("-1"),
+			wantErr:       false,
+		},
+		{
+			name:          "zero max price",
+			spotVMOptions: maxPrice("0"),
+			wantErr:       true,
+		},
+		{
+			name:          "negative max price",
+			spotVMOptions: maxPrice("-2"),
+			wantErr:       true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateSpotVMOptions(tc.spotVMOptions, field.NewPath("spotVMOptions"))
+			if tc.wantErr {
+				g.Expect(err).ToNot(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateRDP(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name      string
+		enableRDP bool
+		osType    string
+		wantErr   bool
+	}{
+		{
+			name:      "RDP disabled on Linux",
+			enableRDP: false,
+			osType:    "Linux",
+			wantErr:   false,
+		},
+		{
+			name:      "RDP enabled on Windows",
+			enableRDP: true,
+			osType:    "Windows",
+			wantErr:   false,
+		},
+		{
+			name:      "RDP enabled on Linux",
+			enableRDP: true,
+			osType:    "Linux",
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateRDP(tc.enableRDP, tc.osType, field.NewPath("enableRDP"))
+			if tc.wantErr {
+				g.Expect(err).ToNot(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateBootstrapDataKeyVault(t *testing.T) {
+	g := NewWithT(t)
+
+	identity := UserAssignedIdentity{
+		ProviderID: "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity",
+	}
+	otherIdentity := UserAssignedIdentity{
+		ProviderID: "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/other-identity",
+	}
+
+	tests := []struct {
+		name     string
+		keyVault *BootstrapDataKeyVault
+		spec     AzureMachineSpec
+		wantErr  bool
+	}{
+		{
+			name: "no Key Vault",
+			spec: AzureMachineSpec{
+				OSDisk: OSDisk{OSType: "Linux"},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Key Vault with a user-assigned identity of the machine",
+			keyVault: &BootstrapDataKeyVault{VaultName: "my-vault", Identity: identity},
+			spec: AzureMachineSpec{
+				OSDisk:                 OSDisk{OSType: "Linux"},
+				Identity:               VMIdentityUserAssigned,
+				UserAssignedIdentities: []UserAssignedIdentity{otherIdentity, identity},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Key Vault with an invalid name",
+			keyVault: &BootstrapDataKeyVault{VaultName: "my_vault", Identity: identity},
+			spec: AzureMachineSpec{
+				OSDisk:                 OSDisk{OSType: "Linux"},
+				Identity:               VMIdentityUserAssigned,
+				UserAssignedIdentities: []UserAssignedIdentity{identity},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Key Vault with an identity which isn't one of the machine",
+			keyVault: &BootstrapDataKeyVault{VaultName: "my-vault", Identity: identity},
+			spec: AzureMachineSpec{
+				OSDisk:                 OSDisk{OSType: "Linux"},
+				Identity:               VMIdentityUserAssigned,
+				UserAssignedIdentities: []UserAssignedIdentity{otherIdentity},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Key Vault without user-assigned identities",
+			keyVault: &BootstrapDataKeyVault{VaultName: "my-vault", Identity: identity},
+			spec: AzureMachineSpec{
+				OSDisk:   OSDisk{OSType: "Linux"},
+				Identity: VMIdentitySystemAssigned,
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Key Vault on Windows",
+			keyVault: &BootstrapDataKeyVault{VaultName: "my-vault", Identity: identity},
+			spec: AzureMachineSpec{
+				OSDisk:                 OSDisk{OSType: "Windows"},
+				Identity:               VMIdentityUserAssigned,
+				UserAssignedIdentities: []UserAssignedIdentity{identity},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Key Vault with Ignition",
+			keyVault: &BootstrapDataKeyVault{VaultName: "my-vault", Identity: identity},
+			spec: AzureMachineSpec{
+				OSDisk:                 OSDisk{OSType: "Linux", BootstrapFormat: BootstrapFormatIgnition},
+				Identity:               VMIdentityUserAssigned,
+				UserAssignedIdentities: []UserAssignedIdentity{identity},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateBootstrapDataKeyVault(tc.keyVault, tc.spec, field.NewPath("bootstrapDataKeyVault"))
+			if tc.wantErr {
+				g.Expect(err).ToNot(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateAzureMonitor(t *testing.T) {
+	g := NewWithT(t)
+
+	identity := UserAssignedIdentity{
+		ProviderID: "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity",
+	}
+
+	tests := []struct {
+		name                   string
+		monitor                *AzureMonitor
+		identityType           VMIdentity
+		userAssignedIdentities []UserAssignedIdentity
+		wantErr                bool
+	}{
+		{
+			name:    "no Azure Monitor agent",
+			wantErr: false,
+		},
+		{
+			name:                   "AzureMonitorAgent with a user-assigned identity of the machine",
+			monitor:                &AzureMonitor{Agent: MonitoringAgentAzureMonitor, Identity: &identity},
+			identityType:           VMIdentityUserAssigned,
+			userAssignedIdentities: []UserAssignedIdentity{identity},
+			wantErr:                false,
+		},
+		{
+			name:         "AzureMonitorAgent with the system-assigned identity of the machine",
+			monitor:      &AzureMonitor{Agent: MonitoringAgentAzureMonitor},
+			identityType: VMIdentitySystemAssigned,
+			wantErr:      false,
+		},
+		{
+			name:    "AzureMonitorAgent without identity",
+			monitor: &AzureMonitor{Agent: MonitoringAgentAzureMonitor},
+			wantErr: true,
+		},
+		{
+			name:                   "AzureMonitorAgent with an identity which isn't one of the machine",
+			monitor:                &AzureMonitor{Agent: MonitoringAgentAzureMonitor, Identity: &identity},
+			identityType:           VMIdentityUserAssigned,
+			userAssignedIdentities: []UserAssignedIdentity{{ProviderID: "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/other-identity"}},
+			wantErr:                true,
+		},
+		{
+			name: "AzureMonitorAgent with a workspace secret",
+			monitor: &AzureMonitor{
+				Agent:              MonitoringAgentAzureMonitor,
+				WorkspaceSecretRef: &corev1.LocalObjectReference{Name: "my-workspace"},
+			},
+			identityType: VMIdentitySystemAssigned,
+			wantErr:      true,
+		},
+		{
+			name: "LogAnalyticsAgent with a workspace secret",
+			monitor: &AzureMonitor{
+				Agent:              MonitoringAgentLogAnalytics,
+				WorkspaceSecretRef: &corev1.LocalObjectReference{Name: "my-workspace"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "LogAnalyticsAgent without workspace secret",
+			monitor: &AzureMonitor{Agent: MonitoringAgentLogAnalytics},
+			wantErr: true,
+		},
+		{
+			name: "LogAnalyticsAgent with an identity",
+			monitor: &AzureMonitor{
+				Agent:              MonitoringAgentLogAnalytics,
+				Identity:           &identity,
+				WorkspaceSecretRef: &corev1.LocalObjectReference{Name: "my-workspace"},
+			},
+			identityType:           VMIdentityUserAssigned,
+			userAssignedIdentities: []UserAssignedIdentity{identity},
+			wantErr:                true,
+		},
+		{
+			name:    "unknown agent",
+			monitor: &AzureMonitor{Agent: "OmsAgent"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateAzureMonitor(tc.monitor, tc.identityType, tc.userAssignedIdentities, field.NewPath("azureMonitor"))
+			if tc.wantErr {
+				g.Expect(err).ToNot(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateBackup(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		backup  *Backup
+		wantErr bool
+	}{
+		{
+			name:    "no backup",
+			backup:  nil,
+			wantErr: false,
+		},
+		{
+			name: "vault in the resource group of the cluster",
+			backup: &Backup{
+				VaultName:  "my-vault",
+				PolicyName: "DefaultPolicy",
+			},
+			wantErr: false,
+		},
+		{
+			name: "vault in another resource group",
+			backup: &Backup{
+				VaultResourceGroup: "my-backup-rg",
+				VaultName:          "my-vault",
+				PolicyName:         "DefaultPolicy",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid vault resource group",
+			backup: &Backup{
+				VaultResourceGroup: "my/backup/rg",
+				VaultName:          "my-vault",
+				PolicyName:         "DefaultPolicy",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid vault name",
+			backup: &Backup{
+				VaultName:  "1-vault",
+				PolicyName: "DefaultPolicy",
+			},
+			wantErr: true,
+		},
+		{
+			name: "no policy name",
+			backup: &Backup{
+				VaultName: "my-vault",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateBackup(tc.backup, field.NewPath("backup"))
+			if tc.wantErr {
+				g.Expect(err).ToNot(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
 func TestAzureMachine_ValidateDataDisksUpdate(t *testing.T) {
 	g := NewWithT(t)
 
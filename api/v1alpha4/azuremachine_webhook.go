@@ -22,6 +22,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -38,7 +39,9 @@ func (m *AzureMachine) SetupWebhookWithManager(mgr ctrl.Manager) error {
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha4-azuremachine,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremachines,versions=v1alpha4,name=validation.azuremachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha4-azuremachine-lookup,mutating=false,failurePolicy=ignore,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremachines,versions=v1alpha4,name=lookupvalidation.azuremachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha4-azuremachine,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremachines,versions=v1alpha4,name=default.azuremachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+// +kubebuilder:webhook:verbs=create,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha4-azuremachine-sshkey,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremachines,versions=v1alpha4,name=sshkey.azuremachine.infrastructure.cluster.x-k8s.io,sideEffects=NoneOnDryRun,admissionReviewVersions=v1;v1beta1
 
 var _ webhook.Validator = &AzureMachine{}
 
@@ -46,7 +49,18 @@ var _ webhook.Validator = &AzureMachine{}
 func (m *AzureMachine) ValidateCreate() error {
 	machinelog.Info("validate create", "name", m.Name)
 
-	if allErrs := ValidateAzureMachineSpec(m.Spec); len(allErrs) > 0 {
+	allErrs := ValidateAzureMachineSpec(m.Spec)
+
+	if m.Spec.SSHPublicKey == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "sshPublicKey"), "the SSH public key is required"))
+	}
+
+	// the RDP port is forwarded by the node outbound load balancer, which control plane machines aren't in
+	if _, ok := m.Labels[clusterv1.MachineControlPlaneLabelName]; ok && m.Spec.EnableRDP {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "enableRDP"), m.Spec.EnableRDP, "Remote Desktop access cannot be enabled on control plane machines"))
+	}
+
+	if len(allErrs) > 0 {
 		return apierrors.NewInvalid(GroupVersion.WithKind("AzureMachine").GroupKind(), m.Name, allErrs)
 	}
 
@@ -87,6 +101,13 @@ func (m *AzureMachine) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
+	if m.Spec.VMSize != old.Spec.VMSize {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "vmSize"),
+				m.Spec.VMSize, "field is immutable"),
+		)
+	}
+
 	if !reflect.DeepEqual(m.Spec.OSDisk, old.Spec.OSDisk) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "osDisk"),
@@ -143,6 +164,36 @@ func (m *AzureMachine) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
+	if m.Spec.EnableRDP != old.Spec.EnableRDP {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "enableRDP"),
+				m.Spec.EnableRDP, "field is immutable"),
+		)
+	}
+
+	if !reflect.DeepEqual(m.Spec.BootstrapDataKeyVault, old.Spec.BootstrapDataKeyVault) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "bootstrapDataKeyVault"),
+				m.Spec.BootstrapDataKeyVault, "field is immutable"),
+		)
+	}
+
+	if !reflect.DeepEqual(m.Spec.AzureMonitor, old.Spec.AzureMonitor) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "azureMonitor"),
+				m.Spec.AzureMonitor, "field is immutable"),
+		)
+	}
+
+	if !reflect.DeepEqual(m.Spec.Backup, old.Spec.Backup) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "backup"),
+				m.Spec.Backup, "field is immutable"),
+		)
+	}
+
+	allErrs = append(allErrs, ValidateTagsUpdate(m.Spec.AdditionalTags, old.Spec.AdditionalTags, field.NewPath("spec", "additionalTags"))...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
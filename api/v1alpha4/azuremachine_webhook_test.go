@@ -21,6 +21,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
 	. "github.com/onsi/gomega"
@@ -104,6 +105,16 @@ func TestAzureMachine_ValidateCreate(t *testing.T) {
 			machine: createMachineWithOsDiskCacheType(t, "invalid_cache_type"),
 			wantErr: true,
 		},
+		{
+			name:    "windows node azuremachine with RDP enabled",
+			machine: createWindowsMachineWithRDP(t, false),
+			wantErr: false,
+		},
+		{
+			name:    "windows control plane azuremachine with RDP enabled",
+			machine: createWindowsMachineWithRDP(t, true),
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -482,6 +493,34 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalidTest: azuremachine.spec.VMSize is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					VMSize: "Standard_D2s_v3",
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					VMSize: "Standard_D4s_v3",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "validTest: azuremachine.spec.VMSize is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					VMSize: "Standard_D2s_v3",
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					VMSize: "Standard_D2s_v3",
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalidTest: azuremachine.spec.SecurityProfile is immutable",
 			oldMachine: &AzureMachine{
@@ -510,6 +549,71 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalidTest: azuremachine.spec.EnableRDP is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					EnableRDP: false,
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					EnableRDP: true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.BootstrapDataKeyVault is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					BootstrapDataKeyVault: &BootstrapDataKeyVault{
+						VaultName: "my-vault",
+						Identity: UserAssignedIdentity{
+							ProviderID: "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.AzureMonitor is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					AzureMonitor: &AzureMonitor{
+						Agent: MonitoringAgentAzureMonitor,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.Backup is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					Backup: &Backup{
+						VaultName:  "my-vault",
+						PolicyName: "DefaultPolicy",
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					Backup: &Backup{
+						VaultName:  "my-vault",
+						PolicyName: "EnhancedPolicy",
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -538,7 +642,7 @@ func TestAzureMachine_Default(t *testing.T) {
 	g.Expect(publicKeyExistTest.machine.Spec.SSHPublicKey).To(Equal(existingPublicKey))
 
 	publicKeyNotExistTest.machine.Default()
-	g.Expect(publicKeyNotExistTest.machine.Spec.SSHPublicKey).To(Not(BeEmpty()))
+	g.Expect(publicKeyNotExistTest.machine.Spec.SSHPublicKey).To(BeEmpty())
 
 	cacheTypeNotSpecifiedTest := test{machine: &AzureMachine{Spec: AzureMachineSpec{OSDisk: OSDisk{CachingType: ""}}}}
 	cacheTypeNotSpecifiedTest.machine.Default()
@@ -614,3 +718,18 @@ func createMachineWithOsDiskCacheType(t *testing.T, cacheType string) *AzureMach
 	machine.Spec.OSDisk.CachingType = cacheType
 	return machine
 }
+
+func createWindowsMachineWithRDP(t *testing.T, controlPlane bool) *AzureMachine {
+	machine := &AzureMachine{
+		Spec: AzureMachineSpec{
+			SSHPublicKey: validSSHPublicKey,
+			OSDisk:       validOSDisk,
+			EnableRDP:    true,
+		},
+	}
+	machine.Spec.OSDisk.OSType = string(compute.Windows)
+	if controlPlane {
+		machine.Labels = map[string]string{clusterv1.MachineControlPlaneLabelName: ""}
+	}
+	return machine
+}
@@ -87,7 +87,7 @@ func TestAzureMachineTemplate_ValidateCreate(t *testing.T) {
 			machineTemplate: createAzureMachineTemplateFromMachine(
 				createMachineWithSSHPublicKey(t, ""),
 			),
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name: "azuremachinetemplate with invalid SSHPublicKey",
@@ -24,6 +24,41 @@ const (
 	NetworkInfrastructureReadyCondition clusterv1.ConditionType = "NetworkInfrastructureReady"
 	// NamespaceNotAllowedByIdentity used to indicate cluster in a namespace not allowed by identity.
 	NamespaceNotAllowedByIdentity = "NamespaceNotAllowedByIdentity"
+	// DNSLabelConflictReason used when a DNS label requested for the cluster is already in use in the region.
+	DNSLabelConflictReason = "DNSLabelConflict"
+
+	// IdentityReadyCondition reports on the AzureClusterIdentity referenced by the cluster and on its client secret.
+	IdentityReadyCondition clusterv1.ConditionType = "IdentityReady"
+	// IdentityNotFoundReason used when the AzureClusterIdentity referenced by the cluster doesn't exist.
+	IdentityNotFoundReason = "IdentityNotFound"
+	// IdentitySecretNotFoundReason used when the client secret of the AzureClusterIdentity doesn't exist.
+	IdentitySecretNotFoundReason = "IdentitySecretNotFound"
+	// InvalidIdentitySecretReason used when the client secret of the AzureClusterIdentity is missing or malformed.
+	InvalidIdentitySecretReason = "InvalidIdentitySecret"
+
+	// ResourceGroupReadyCondition reports on the status of the cluster resource group.
+	ResourceGroupReadyCondition clusterv1.ConditionType = "ResourceGroupReady"
+	// VNetReadyCondition reports on the status of the cluster virtual network.
+	VNetReadyCondition clusterv1.ConditionType = "VNetReady"
+	// SecurityGroupsReadyCondition reports on the status of the cluster network security groups.
+	SecurityGroupsReadyCondition clusterv1.ConditionType = "SecurityGroupsReady"
+	// RouteTablesReadyCondition reports on the status of the cluster route tables.
+	RouteTablesReadyCondition clusterv1.ConditionType = "RouteTablesReady"
+	// PublicIPsReadyCondition reports on the status of the public IPs of the cluster or of the machine.
+	PublicIPsReadyCondition clusterv1.ConditionType = "PublicIPsReady"
+	// NATGatewaysReadyCondition reports on the status of the cluster NAT gateways.
+	NATGatewaysReadyCondition clusterv1.ConditionType = "NATGatewaysReady"
+	// SubnetsReadyCondition reports on the status of the cluster subnets.
+	SubnetsReadyCondition clusterv1.ConditionType = "SubnetsReady"
+	// LoadBalancersReadyCondition reports on the status of the cluster load balancers.
+	LoadBalancersReadyCondition clusterv1.ConditionType = "LoadBalancersReady"
+	// PrivateDNSReadyCondition reports on the status of the private DNS zone of the cluster.
+	PrivateDNSReadyCondition clusterv1.ConditionType = "PrivateDNSReady"
+	// BastionHostReadyCondition reports on the status of the cluster bastion host.
+	BastionHostReadyCondition clusterv1.ConditionType = "BastionHostReady"
+	// RegionMetadataAvailableCondition reports on the lookup of the region metadata published in the AzureCluster status.
+	// It isn't part of the summary of the AzureCluster Ready condition, as the region metadata is only informational.
+	RegionMetadataAvailableCondition clusterv1.ConditionType = "RegionMetadataAvailable"
 )
 
 // AzureMachine Conditions and Reasons.
@@ -48,6 +83,20 @@ const (
 	BootstrapInProgressReason = "BootstrapInProgress"
 	// BootstrapFailedReason is used to indicate the bootstrap process ran into an error.
 	BootstrapFailedReason = "BootstrapFailed"
+
+	// NetworkInterfacesReadyCondition reports on the status of the network interfaces of the machine.
+	NetworkInterfacesReadyCondition clusterv1.ConditionType = "NetworkInterfacesReady"
+	// InboundNATRulesReadyCondition reports on the status of the inbound NAT rules of the machine.
+	InboundNATRulesReadyCondition clusterv1.ConditionType = "InboundNATRulesReady"
+	// AvailabilitySetReadyCondition reports on the status of the availability set of the machine.
+	AvailabilitySetReadyCondition clusterv1.ConditionType = "AvailabilitySetReady"
+	// DisksReadyCondition reports on the status of the disks of the machine.
+	DisksReadyCondition clusterv1.ConditionType = "DisksReady"
+	// VMExtensionsReadyCondition reports on the status of the VM extensions of the machine.
+	VMExtensionsReadyCondition clusterv1.ConditionType = "VMExtensionsReady"
+	// VMBackupReadyCondition reports on the enrollment of the VM of the machine into its backup policy. It isn't part of
+	// the Ready condition of the machine, which doesn't wait for the enrollment.
+	VMBackupReadyCondition clusterv1.ConditionType = "VMBackupReady"
 )
 
 // AzureMachinePool Conditions and Reasons.
@@ -75,3 +124,16 @@ const (
 	// ScaleSetModelOutOfDateReason describes the machine pool model being out of date.
 	ScaleSetModelOutOfDateReason = "ScaleSetModelOutOfDate"
 )
+
+// Azure resource Reasons, shared by the conditions reporting on the resources of a single service.
+const (
+	// CreatingReason used when the creation or update of the resources is in progress.
+	CreatingReason = "Creating"
+	// FailedReason used when the creation or update of the resources failed.
+	FailedReason = "Failed"
+	// DryRunReason used when changes to the resources were skipped in dry-run mode.
+	DryRunReason = "DryRun"
+	// QuotaExceededReason used when the resources were not created because they would exceed a quota of the
+	// subscription.
+	QuotaExceededReason = "QuotaExceeded"
+)
@@ -41,6 +41,13 @@ func (t Tags) HasOwned(cluster string) bool {
 	return ok && ResourceLifecycle(value) == ResourceLifecycleOwned
 }
 
+// HasShared returns true if the tags contains a tag that marks the resource as shared by the cluster, meaning that it
+// is only read by this management tooling, and neither updated nor deleted along with the cluster.
+func (t Tags) HasShared(cluster string) bool {
+	value, ok := t[ClusterTagKey(cluster)]
+	return ok && ResourceLifecycle(value) == ResourceLifecycleShared
+}
+
 // HasAzureCloudProviderOwned returns true if the tags contains a tag that marks the resource as owned by the cluster from the perspective of the in-tree cloud provider.
 func (t Tags) HasAzureCloudProviderOwned(cluster string) bool {
 	value, ok := t[ClusterAzureCloudProviderTagKey(cluster)]
@@ -136,6 +143,12 @@ const (
 	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
 	// for annotation formatting rules.
 	VMTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-vm"
+
+	// ClusterTagsLastAppliedAnnotation is the key for the AzureCluster object annotation
+	// which tracks the AdditionalTags applied to the resources of the cluster.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	ClusterTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-cluster"
 )
 
 // SpecVersionHashTagKey is the key for the spec version hash used to enable quick spec difference comparison.
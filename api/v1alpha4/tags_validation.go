@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// Limits on tags, described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources#limitations.
+	maxTagsPerResource  = 50
+	maxTagNameLength    = 512
+	maxTagValueLength   = 256
+	tagNameInvalidChars = `<>%&\?/`
+	// reservedTagCount is the number of tags the provider sets on a resource on top of its additional tags: the cluster
+	// lifecycle tag, the Name and role tags, the cloud provider lifecycle tag and the spec version hash.
+	reservedTagCount = 5
+	// nameTagKey is the tag the provider sets to the name of a resource.
+	nameTagKey = "Name"
+)
+
+// ValidateTags validates additional tags against the limits Azure enforces on resource tags, and makes sure they don't
+// collide with the tags managed by the provider.
+func ValidateTags(tags Tags, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if max := maxTagsPerResource - reservedTagCount; len(tags) > max {
+		allErrs = append(allErrs, field.TooMany(fldPath, len(tags), max))
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Tag names are case-insensitive in Azure.
+	seen := make(map[string]string, len(tags))
+	for _, key := range keys {
+		value := tags[key]
+		keyPath := fldPath.Key(key)
+		if key == "" {
+			allErrs = append(allErrs, field.Invalid(keyPath, key, "tag name must not be empty"))
+			continue
+		}
+		if len(key) > maxTagNameLength {
+			allErrs = append(allErrs, field.TooLong(keyPath, key, maxTagNameLength))
+		}
+		if strings.ContainsAny(key, tagNameInvalidChars) {
+			allErrs = append(allErrs, field.Invalid(keyPath, key, fmt.Sprintf("tag name must not contain any of the characters %s", tagNameInvalidChars)))
+		}
+		if strings.EqualFold(key, nameTagKey) {
+			allErrs = append(allErrs, field.Invalid(keyPath, key, fmt.Sprintf("tag name %s is reserved for the name of the resource, which is set by the provider", nameTagKey)))
+		}
+		if strings.HasPrefix(strings.ToLower(key), strings.ToLower(NameAzureProviderPrefix)) {
+			allErrs = append(allErrs, field.Invalid(keyPath, key, fmt.Sprintf("tag name must not start with %s, which is reserved for the tags managed by the provider", NameAzureProviderPrefix)))
+		}
+		if other, ok := seen[strings.ToLower(key)]; ok {
+			allErrs = append(allErrs, field.Duplicate(keyPath, fmt.Sprintf("%s and %s only differ in case", other, key)))
+		}
+		seen[strings.ToLower(key)] = key
+		if len(value) > maxTagValueLength {
+			allErrs = append(allErrs, field.TooLong(keyPath, value, maxTagValueLength))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateTagsUpdate validates additional tags only if they changed, so that objects whose tags were valid when they
+// were created can still be updated.
+func ValidateTagsUpdate(tags, oldTags Tags, fldPath *field.Path) field.ErrorList {
+	if reflect.DeepEqual(tags, oldTags) {
+		return nil
+	}
+	return ValidateTags(tags, fldPath)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateTags(t *testing.T) {
+	g := NewWithT(t)
+
+	tooManyTags := Tags{}
+	for i := 0; i < 46; i++ {
+		tooManyTags[fmt.Sprintf("tag-%d", i)] = "value"
+	}
+
+	tests := []struct {
+		name    string
+		tags    Tags
+		wantErr bool
+	}{
+		{
+			name:    "no tags",
+			tags:    nil,
+			wantErr: false,
+		},
+		{
+			name:    "valid tags",
+			tags:    Tags{"environment": "production", "cost-center": "1234", "kubernetes.io_role": "node"},
+			wantErr: false,
+		},
+		{
+			name:    "too many tags",
+			tags:    tooManyTags,
+			wantErr: true,
+		},
+		{
+			name:    "empty tag name",
+			tags:    Tags{"": "value"},
+			wantErr: true,
+		},
+		{
+			name:    "tag name too long",
+			tags:    Tags{strings.Repeat("a", 513): "value"},
+			wantErr: true,
+		},
+		{
+			name:    "tag value too long",
+			tags:    Tags{"key": strings.Repeat("a", 257)},
+			wantErr: true,
+		},
+		{
+			name:    "tag name with invalid characters",
+			tags:    Tags{"team/owner": "value"},
+			wantErr: true,
+		},
+		{
+			name:    "tag name with the provider prefix",
+			tags:    Tags{"sigs.k8s.io_cluster-api-provider-azure_cluster_other": "owned"},
+			wantErr: true,
+		},
+		{
+			name:    "tag name with the provider prefix in a different case",
+			tags:    Tags{"SIGS.K8S.IO_cluster-api-provider-azure_role": "node"},
+			wantErr: true,
+		},
+		{
+			name:    "reserved Name tag",
+			tags:    Tags{"Name": "my-vm"},
+			wantErr: true,
+		},
+		{
+			name:    "reserved Name tag in a different case",
+			tags:    Tags{"name": "my-vm"},
+			wantErr: true,
+		},
+		{
+			name:    "tag names only differing in case",
+			tags:    Tags{"Environment": "production", "environment": "staging"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateTags(tc.tags, field.NewPath("spec", "additionalTags"))
+			if tc.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateTagsUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	invalid := Tags{"Name": "my-vm"}
+	g.Expect(ValidateTagsUpdate(invalid, Tags{"Name": "my-vm"}, field.NewPath("spec", "additionalTags"))).To(BeEmpty())
+	g.Expect(ValidateTagsUpdate(invalid, nil, field.NewPath("spec", "additionalTags"))).NotTo(BeEmpty())
+	g.Expect(ValidateTagsUpdate(Tags{"environment": "production"}, invalid, field.NewPath("spec", "additionalTags"))).To(BeEmpty())
+}
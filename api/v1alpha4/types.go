@@ -27,6 +27,11 @@ const (
 	ControlPlane string = "control-plane"
 	// Node machine label.
 	Node string = "node"
+
+	// DeleteForMoveAnnotation is set by clusterctl move on the objects of the source management cluster before deleting
+	// them, once they have been recreated in the target management cluster. The Azure resources of objects deleted with
+	// this annotation are left untouched, as they are now managed from the target management cluster.
+	DeleteForMoveAnnotation = "clusterctl.cluster.x-k8s.io/delete-for-move"
 )
 
 // Future contains the data needed for an Azure long-running operation to continue across reconcile loops.
@@ -38,6 +43,10 @@ type Future struct {
 	// +optional
 	ResourceGroup string `json:"resourceGroup,omitempty"`
 
+	// ServiceName is the name of the service which started the operation, e.g. virtualnetworks
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+
 	// Name is the name of the Azure resource
 	// +optional
 	Name string `json:"name,omitempty"`
@@ -46,6 +55,9 @@ type Future struct {
 	FutureData string `json:"futureData,omitempty"`
 }
 
+// Futures is a slice of Future.
+type Futures []Future
+
 // NetworkSpec specifies what the Azure networking resources should look like.
 type NetworkSpec struct {
 	// Vnet is the configuration for the Azure virtual network.
@@ -72,6 +84,29 @@ type NetworkSpec struct {
 	// PrivateDNSZoneName defines the zone name for the Azure Private DNS.
 	// +optional
 	PrivateDNSZoneName string `json:"privateDNSZoneName,omitempty"`
+
+	// DNSForwardingRulesetLink links the cluster virtual network to an existing Azure DNS Private Resolver
+	// forwarding ruleset, so that nodes can resolve names forwarded by the ruleset (e.g. on-premises domains).
+	// +optional
+	DNSForwardingRulesetLink *DNSForwardingRulesetLinkSpec `json:"dnsForwardingRulesetLink,omitempty"`
+
+	// ResourceGroup is the name of the resource group in which the network resources of the cluster (security groups,
+	// route tables, NAT gateways, public IPs, load balancers, private DNS zone and Azure Bastion host) are placed.
+	// It allows keeping networking in a resource group owned by a different team than the one holding the compute resources.
+	// The resource group must already exist when it differs from the cluster resource group. Defaults to the cluster resource group.
+	// +optional
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+}
+
+// DNSForwardingRulesetLinkSpec configures a virtual network link to an existing DNS forwarding ruleset.
+type DNSForwardingRulesetLinkSpec struct {
+	// RulesetID is the resource ID of the existing DNS forwarding ruleset to link the virtual network to.
+	// +kubebuilder:validation:MinLength=1
+	RulesetID string `json:"rulesetID"`
+
+	// Name defines the name of the virtual network link resource created in the ruleset.
+	// +optional
+	Name string `json:"name,omitempty"`
 }
 
 // VnetSpec configures an Azure virtual network.
@@ -188,6 +223,17 @@ type LoadBalancerSpec struct {
 	FrontendIPsCount *int32 `json:"frontendIPsCount,omitempty"`
 	// IdleTimeoutInMinutes specifies the timeout for the TCP idle connection.
 	IdleTimeoutInMinutes *int32 `json:"idleTimeoutInMinutes,omitempty"`
+	// BackendPool describes the backend pool of the load balancer.
+	// +optional
+	BackendPool BackendPool `json:"backendPool,omitempty"`
+}
+
+// BackendPool describes the backend pool of the load balancer.
+type BackendPool struct {
+	// Name specifies the name of backend pool for the load balancer. If not specified, the default name will
+	// be set, depending on the load balancer role.
+	// +optional
+	Name string `json:"name,omitempty"`
 }
 
 // SKU defines an Azure load balancer SKU.
@@ -377,6 +423,37 @@ type UserAssignedIdentity struct {
 	ProviderID string `json:"providerID"`
 }
 
+// MonitoringAgent is the agent sending the metrics and logs of a machine to Azure Monitor.
+// +kubebuilder:validation:Enum=AzureMonitorAgent;LogAnalyticsAgent
+type MonitoringAgent string
+
+const (
+	// MonitoringAgentAzureMonitor is the Azure Monitor agent, which authenticates with a managed identity of the
+	// machine and sends the data selected by the data collection rules associated with the machine.
+	MonitoringAgentAzureMonitor MonitoringAgent = "AzureMonitorAgent"
+	// MonitoringAgentLogAnalytics is the Log Analytics agent, which authenticates with the ID and key of the Log
+	// Analytics workspace it sends data to.
+	MonitoringAgentLogAnalytics MonitoringAgent = "LogAnalyticsAgent"
+)
+
+// AzureMonitor defines the Azure Monitor agent installed on machines, as a VM extension sending their metrics and logs
+// to existing Azure Monitor workspaces.
+type AzureMonitor struct {
+	// Agent is the agent installed on the machines.
+	Agent MonitoringAgent `json:"agent"`
+
+	// Identity is the user-assigned identity AzureMonitorAgent authenticates with, which must be one of the
+	// user-assigned identities of the machines. If not set, AzureMonitorAgent authenticates with the system-assigned
+	// identity of the machines.
+	// +optional
+	Identity *UserAssignedIdentity `json:"identity,omitempty"`
+
+	// WorkspaceSecretRef is a reference to a Secret in the namespace of the machines holding the ID and the key of the
+	// Log Analytics workspace LogAnalyticsAgent sends data to, in its `workspaceID` and `workspaceKey` keys.
+	// +optional
+	WorkspaceSecretRef *corev1.LocalObjectReference `json:"workspaceSecretRef,omitempty"`
+}
+
 const (
 	// AzureIdentityBindingSelector is the label used to match with the AzureIdentityBinding
 	// For the controller to match an identity binding, it needs a [label] with the key `aadpodidbinding`
@@ -418,8 +495,41 @@ type OSDisk struct {
 	// +optional
 	// +kubebuilder:validation:Enum=None;ReadOnly;ReadWrite
 	CachingType string `json:"cachingType,omitempty"`
+	// BootstrapFormat is the format of the bootstrap data the image of the OS disk is provisioned with, cloud-config
+	// for the images provisioned by cloud-init, or ignition for the images provisioned by Ignition, such as Flatcar
+	// Container Linux. Ignition configs are passed as is in the custom data of the VM. Defaults to the format of the
+	// bootstrap data secret, or cloud-config if the bootstrap provider doesn't set it.
+	// +optional
+	// +kubebuilder:validation:Enum=cloud-config;ignition
+	BootstrapFormat BootstrapFormat `json:"bootstrapFormat,omitempty"`
+	// Distribution is the Linux distribution of the default image of the machine, used when no image is set, Ubuntu or
+	// AzureLinux for Azure Linux. The Azure Linux reference images require VM sizes supporting generation 2 VMs.
+	// Defaults to Ubuntu for Linux machines, and must not be set for Windows machines.
+	// +optional
+	// +kubebuilder:validation:Enum=Ubuntu;AzureLinux
+	Distribution LinuxDistribution `json:"distribution,omitempty"`
 }
 
+// LinuxDistribution is the Linux distribution of the default image of a machine.
+type LinuxDistribution string
+
+const (
+	// LinuxDistributionUbuntu is the Ubuntu distribution.
+	LinuxDistributionUbuntu LinuxDistribution = "Ubuntu"
+	// LinuxDistributionAzureLinux is the Azure Linux distribution, formerly known as CBL-Mariner.
+	LinuxDistributionAzureLinux LinuxDistribution = "AzureLinux"
+)
+
+// BootstrapFormat is the format of the bootstrap data of a machine.
+type BootstrapFormat string
+
+const (
+	// BootstrapFormatCloudConfig is the cloud-config format of the bootstrap data of cloud-init.
+	BootstrapFormatCloudConfig BootstrapFormat = "cloud-config"
+	// BootstrapFormatIgnition is the JSON format of the bootstrap data of Ignition.
+	BootstrapFormatIgnition BootstrapFormat = "ignition"
+)
+
 // DataDisk specifies the parameters that are used to add one or more data disks to the machine.
 type DataDisk struct {
 	// NameSuffix is the suffix to be appended to the machine name to generate the disk name.
@@ -553,8 +663,38 @@ type AddressRecord struct {
 type CloudProviderConfigOverrides struct {
 	RateLimits []RateLimitSpec `json:"rateLimits,omitempty"`
 	BackOffs   BackOffConfig   `json:"backOffs,omitempty"`
+
+	// VMType is the type of the Azure nodes the cloud provider manages, either standard for availability
+	// sets and standalone VMs or vmss for virtual machine scale sets. Defaults to vmss.
+	// +kubebuilder:validation:Enum=standard;vmss
+	// +optional
+	VMType string `json:"vmType,omitempty"`
+
+	// UserAssignedIdentityID is the client ID of the user-assigned identity the cloud provider authenticates
+	// with. When empty, the first user-assigned identity of the machine is used.
+	// +optional
+	UserAssignedIdentityID string `json:"userAssignedIdentityID,omitempty"`
+
+	// ExcludeMasterFromStandardLB excludes the control plane nodes from the backend pool of the standard load
+	// balancer the cloud provider creates for services of type LoadBalancer.
+	// +optional
+	ExcludeMasterFromStandardLB *bool `json:"excludeMasterFromStandardLB,omitempty"`
+
+	// SecretRef is a reference to a Secret in the namespace of the AzureCluster holding the full cloud provider
+	// config to use on nodes instead of the generated one. The Secret must have an `azure.json` key, and may
+	// have `control-plane-azure.json` and `worker-node-azure.json` keys to use a different config per role.
+	// All the other overrides are ignored when it is set.
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
 }
 
+const (
+	// VMTypeStandard is the cloud provider VM type of nodes in availability sets and standalone VMs.
+	VMTypeStandard = "standard"
+	// VMTypeVMSS is the cloud provider VM type of nodes in virtual machine scale sets.
+	VMTypeVMSS = "vmss"
+)
+
 // BackOffConfig indicates the back-off config options.
 type BackOffConfig struct {
 	CloudProviderBackoff         bool               `json:"cloudProviderBackoff,omitempty"`
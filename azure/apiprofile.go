@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// apiVersionParameter is the query parameter holding the API version of an Azure Resource Manager request.
+const apiVersionParameter = "api-version"
+
+// APIProfile maps resource providers, e.g. Microsoft.Compute, or resource types, e.g. Microsoft.Compute/disks, to the
+// API version to use with them instead of the API version of the SDK, for clouds such as Azure Stack Hub which only
+// support older API versions. Resource types take precedence over their resource provider.
+type APIProfile map[string]string
+
+// APIProfiles are the API profiles supported by Azure Stack Hub, by name. Their API versions are the ones of the API
+// profile packages of the SDK, e.g. github.com/Azure/azure-sdk-for-go/profiles/2020-09-01.
+var APIProfiles = map[string]APIProfile{
+	"2019-03-01-hybrid": {
+		"Microsoft.Authorization":                     "2015-07-01",
+		"Microsoft.Authorization/locks":               "2016-09-01",
+		"Microsoft.Compute":                           "2017-12-01",
+		"Microsoft.Compute/disks":                     "2017-03-30",
+		"Microsoft.Compute/snapshots":                 "2017-03-30",
+		"Microsoft.Network":                           "2017-10-01",
+		"Microsoft.Resources":                         "2018-05-01",
+		"Microsoft.Resources/subscriptions/locations": "2016-06-01",
+		"Microsoft.Storage":                           "2017-10-01",
+	},
+	"2020-09-01-hybrid": {
+		"Microsoft.Authorization":                     "2015-07-01",
+		"Microsoft.Authorization/locks":               "2016-09-01",
+		"Microsoft.Compute":                           "2020-06-01",
+		"Microsoft.Compute/disks":                     "2019-07-01",
+		"Microsoft.Compute/snapshots":                 "2019-07-01",
+		"Microsoft.Network":                           "2018-11-01",
+		"Microsoft.Resources":                         "2018-05-01",
+		"Microsoft.Resources/subscriptions/locations": "2018-06-01",
+		"Microsoft.Storage":                           "2017-10-01",
+	},
+}
+
+type apiProfileKey struct{}
+
+// apiProfileValue is the API profile of a context, and the host of the Azure Resource Manager endpoint it applies to.
+type apiProfileValue struct {
+	profile APIProfile
+	host    string
+}
+
+// WithAPIProfile returns a context in which the requests to the given Azure Resource Manager endpoint use the API
+// versions of the named API profile. An empty or unknown name leaves the API versions of the SDK unchanged.
+func WithAPIProfile(ctx context.Context, name, resourceManagerEndpoint string) context.Context {
+	profile, ok := APIProfiles[name]
+	if !ok {
+		return ctx
+	}
+	endpoint, err := url.Parse(resourceManagerEndpoint)
+	if err != nil || endpoint.Host == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, apiProfileKey{}, apiProfileValue{profile: profile, host: endpoint.Host})
+}
+
+// WithAPIProfileVersions returns a SendDecorator which replaces the API version of the Azure Resource Manager requests
+// with the one of the API profile of their context, if any. Requests to other endpoints, such as Key Vault, and to
+// resource providers missing from the API profile are sent unchanged.
+func WithAPIProfileVersions() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+			value, ok := req.Context().Value(apiProfileKey{}).(apiProfileValue)
+			if !ok || !strings.EqualFold(req.URL.Host, value.host) {
+				return s.Do(req)
+			}
+			query := req.URL.Query()
+			if query.Get(apiVersionParameter) == "" {
+				return s.Do(req)
+			}
+			version, ok := value.profile.apiVersion(resourceTypeFromPath(req.URL.Path))
+			if !ok {
+				return s.Do(req)
+			}
+			query.Set(apiVersionParameter, version)
+			req.URL.RawQuery = query.Encode()
+			return s.Do(req)
+		})
+	}
+}
+
+// apiVersion returns the API version of the profile for a resource type, falling back to the one of its parent types
+// and resource provider. Resource groups and subscriptions belong to the Microsoft.Resources resource provider.
+func (p APIProfile) apiVersion(resourceType string) (string, bool) {
+	if !strings.Contains(strings.SplitN(resourceType, "/", 2)[0], ".") {
+		resourceType = "Microsoft.Resources/" + resourceType
+	}
+	for t := resourceType; t != ""; {
+		for key, version := range p {
+			if strings.EqualFold(key, t) {
+				return version, true
+			}
+		}
+		i := strings.LastIndex(t, "/")
+		if i < 0 {
+			break
+		}
+		t = t[:i]
+	}
+	return "", false
+}
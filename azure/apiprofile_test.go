@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	compute20190301 "github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/compute/mgmt/compute"
+	network20190301 "github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/network/mgmt/network"
+	resources20190301 "github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/resources/mgmt/resources"
+	storage20190301 "github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/storage/mgmt/storage"
+	network20200901 "github.com/Azure/azure-sdk-for-go/profiles/2020-09-01/network/mgmt/network"
+	resources20200901 "github.com/Azure/azure-sdk-for-go/profiles/2020-09-01/resources/mgmt/resources"
+	storage20200901 "github.com/Azure/azure-sdk-for-go/profiles/2020-09-01/storage/mgmt/storage"
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+)
+
+const fakeResourceManagerEndpoint = "https://management.local.azurestack.external/"
+
+func TestWithAPIProfileVersions(t *testing.T) {
+	var sent string
+	sender := WithAPIProfileVersions()(autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+		sent = req.URL.Query().Get("api-version")
+		return &http.Response{StatusCode: http.StatusOK, Request: req}, nil
+	}))
+
+	cases := map[string]struct {
+		ctx             context.Context
+		host            string
+		path            string
+		expectedVersion string
+	}{
+		"no API profile": {
+			ctx:             context.TODO(),
+			host:            "management.local.azurestack.external",
+			path:            "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm",
+			expectedVersion: "2021-04-01",
+		},
+		"unknown API profile": {
+			ctx:             WithAPIProfile(context.TODO(), "2015-01-01-hybrid", fakeResourceManagerEndpoint),
+			host:            "management.local.azurestack.external",
+			path:            "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm",
+			expectedVersion: "2021-04-01",
+		},
+		"resource provider in the API profile": {
+			ctx:             WithAPIProfile(context.TODO(), "2020-09-01-hybrid", fakeResourceManagerEndpoint),
+			host:            "management.local.azurestack.external",
+			path:            "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm",
+			expectedVersion: "2020-06-01",
+		},
+		"resource type in the API profile": {
+			ctx:             WithAPIProfile(context.TODO(), "2020-09-01-hybrid", fakeResourceManagerEndpoint),
+			host:            "management.local.azurestack.external",
+			path:            "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-disk",
+			expectedVersion: "2019-07-01",
+		},
+		"child resource type": {
+			ctx:             WithAPIProfile(context.TODO(), "2019-03-01-hybrid", fakeResourceManagerEndpoint),
+			host:            "management.local.azurestack.external",
+			path:            "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet",
+			expectedVersion: "2017-10-01",
+		},
+		"resource group": {
+			ctx:             WithAPIProfile(context.TODO(), "2020-09-01-hybrid", fakeResourceManagerEndpoint),
+			host:            "management.local.azurestack.external",
+			path:            "/subscriptions/123/resourceGroups/my-rg",
+			expectedVersion: "2018-05-01",
+		},
+		"locations": {
+			ctx:             WithAPIProfile(context.TODO(), "2020-09-01-hybrid", fakeResourceManagerEndpoint),
+			host:            "management.local.azurestack.external",
+			path:            "/subscriptions/123/locations",
+			expectedVersion: "2018-06-01",
+		},
+		"resource lock": {
+			ctx:             WithAPIProfile(context.TODO(), "2020-09-01-hybrid", fakeResourceManagerEndpoint),
+			host:            "management.local.azurestack.external",
+			path:            "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/providers/Microsoft.Authorization/locks/my-lock",
+			expectedVersion: "2016-09-01",
+		},
+		"request to another endpoint": {
+			ctx:             WithAPIProfile(context.TODO(), "2020-09-01-hybrid", fakeResourceManagerEndpoint),
+			host:            "my-vault.vault.local.azurestack.external",
+			path:            "/secrets/my-secret",
+			expectedVersion: "2021-04-01",
+		},
+		"resource provider missing from the API profile": {
+			ctx:             WithAPIProfile(context.TODO(), "2020-09-01-hybrid", fakeResourceManagerEndpoint),
+			host:            "management.local.azurestack.external",
+			path:            "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ContainerService/managedClusters/my-aks",
+			expectedVersion: "2021-04-01",
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			req := &http.Request{
+				Method: http.MethodGet,
+				URL:    &url.URL{Scheme: "https", Host: tc.host, Path: tc.path, RawQuery: "api-version=2021-04-01"},
+			}
+			_, err := sender.Do(req.WithContext(tc.ctx))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(sent).To(Equal(tc.expectedVersion))
+		})
+	}
+}
+
+func TestAPIProfilesMatchTheSDK(t *testing.T) {
+	// The user agents of the API profile packages of the SDK name the API versions of their resource providers. The
+	// compute package of the 2020-09-01 profile mixes several API versions, and is checked by the request tests instead.
+	cases := map[string]struct {
+		profile          string
+		resourceProvider string
+		userAgent        string
+	}{
+		"2019-03-01 compute":   {"2019-03-01-hybrid", "Microsoft.Compute", compute20190301.UserAgent()},
+		"2019-03-01 network":   {"2019-03-01-hybrid", "Microsoft.Network", network20190301.UserAgent()},
+		"2019-03-01 resources": {"2019-03-01-hybrid", "Microsoft.Resources", resources20190301.UserAgent()},
+		"2019-03-01 storage":   {"2019-03-01-hybrid", "Microsoft.Storage", storage20190301.UserAgent()},
+		"2020-09-01 network":   {"2020-09-01-hybrid", "Microsoft.Network", network20200901.UserAgent()},
+		"2020-09-01 resources": {"2020-09-01-hybrid", "Microsoft.Resources", resources20200901.UserAgent()},
+		"2020-09-01 storage":   {"2020-09-01-hybrid", "Microsoft.Storage", storage20200901.UserAgent()},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(tc.userAgent).To(ContainSubstring("/" + APIProfiles[tc.profile][tc.resourceProvider] + " "))
+		})
+	}
+}
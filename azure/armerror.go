@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ARMError is the structured error Azure Resource Manager returns for a failed request or long-running operation.
+type ARMError struct {
+	// Code is the machine-readable error code, e.g. QuotaExceeded.
+	Code string `json:"code"`
+	// Message is the human-readable description of the error.
+	Message string `json:"message"`
+	// Target is the property or resource the error is about, if any.
+	Target string `json:"target,omitempty"`
+	// Details are the errors which led to this one, if any.
+	Details []ARMError `json:"details,omitempty"`
+}
+
+// Error returns the code, target and message of the error, followed by the ones of its details.
+func (e ARMError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Code)
+	if e.Target != "" {
+		fmt.Fprintf(&b, " (target: %s)", e.Target)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if len(e.Details) > 0 {
+		details := make([]string, 0, len(e.Details))
+		for _, d := range e.Details {
+			details = append(details, d.Error())
+		}
+		fmt.Fprintf(&b, " [%s]", strings.Join(details, "; "))
+	}
+	return b.String()
+}
+
+// ParseARMError returns the structured ARM error wrapped by err, if any, instead of the nested messages of the
+// errors wrapping it.
+func ParseARMError(err error) *ARMError {
+	serr := serviceError(err)
+	if serr == nil || serr.Code == "" {
+		return nil
+	}
+	armErr := &ARMError{
+		Code:    serr.Code,
+		Message: serr.Message,
+	}
+	if serr.Target != nil {
+		armErr.Target = *serr.Target
+	}
+	for _, detail := range serr.Details {
+		// The details are loosely typed, but follow the same schema as the error itself.
+		var d ARMError
+		if b, err := json.Marshal(detail); err == nil && json.Unmarshal(b, &d) == nil && d.Code != "" {
+			armErr.Details = append(armErr.Details, d)
+		}
+	}
+	return armErr
+}
+
+// FailureReason returns the code of the ARM error wrapped by err, which can be used as the reason of a condition,
+// or defaultReason if err doesn't wrap any.
+func FailureReason(err error, defaultReason string) string {
+	if armErr := ParseARMError(err); armErr != nil {
+		return armErr.Code
+	}
+	return defaultReason
+}
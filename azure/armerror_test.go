@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestParseARMError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		want        *ARMError
+		wantMessage string
+	}{
+		{
+			name: "error which doesn't wrap an ARM error",
+			err:  errors.New("boom"),
+		},
+		{
+			name: "error of an Azure API request",
+			err: errors.Wrap(autorest.DetailedError{
+				Original: &azure.RequestError{
+					ServiceError: &azure.ServiceError{
+						Code:    "InvalidParameter",
+						Message: "The value of parameter osDisk.diskSizeGB is invalid.",
+						Target:  to.StringPtr("diskSizeGB"),
+					},
+				},
+			}, "failed to create virtual machine"),
+			want: &ARMError{
+				Code:    "InvalidParameter",
+				Message: "The value of parameter osDisk.diskSizeGB is invalid.",
+				Target:  "diskSizeGB",
+			},
+			wantMessage: "InvalidParameter (target: diskSizeGB): The value of parameter osDisk.diskSizeGB is invalid.",
+		},
+		{
+			name: "error of a failed long-running operation with details",
+			err: WithTransientError(&azure.ServiceError{
+				Code:    "DeploymentFailed",
+				Message: "At least one resource deployment operation failed.",
+				Details: []map[string]interface{}{
+					{"code": "QuotaExceeded", "message": "Operation could not be completed as it results in exceeding approved quota."},
+					{"unexpected": "schema"},
+				},
+			}, 0),
+			want: &ARMError{
+				Code:    "DeploymentFailed",
+				Message: "At least one resource deployment operation failed.",
+				Details: []ARMError{
+					{Code: "QuotaExceeded", Message: "Operation could not be completed as it results in exceeding approved quota."},
+				},
+			},
+			wantMessage: "DeploymentFailed: At least one resource deployment operation failed. [QuotaExceeded: Operation could not be completed as it results in exceeding approved quota.]",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got := ParseARMError(tt.err)
+			g.Expect(got).To(Equal(tt.want))
+			if tt.want != nil {
+				g.Expect(got.Error()).To(Equal(tt.wantMessage))
+				g.Expect(FailureReason(tt.err, "Failed")).To(Equal(tt.want.Code))
+			} else {
+				g.Expect(FailureReason(tt.err, "Failed")).To(Equal("Failed"))
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ package azure
 
 import (
 	"fmt"
+	"hash/fnv"
+	"strings"
 
 	"github.com/Azure/go-autorest/autorest/azure"
 
@@ -39,6 +41,8 @@ const (
 	DefaultImageOfferID = "capi"
 	// DefaultWindowsImageOfferID is the default Azure Marketplace offer ID for Windows.
 	DefaultWindowsImageOfferID = "capi-windows"
+	// DefaultAzureLinuxImageOfferID is the default Azure Marketplace offer ID for Azure Linux.
+	DefaultAzureLinuxImageOfferID = "capi-azurelinux"
 	// DefaultImagePublisherID is the default Azure Marketplace publisher ID.
 	DefaultImagePublisherID = "cncf-upstream"
 	// LatestVersion is the image version latest.
@@ -48,6 +52,17 @@ const (
 const (
 	// WindowsOS is Windows OS value for OSDisk.
 	WindowsOS = "Windows"
+	// MaxWindowsScaleSetNameLength is the maximum length of the name of a Windows scale set, used as the computer name
+	// prefix of its instances, to which Azure appends a 6 characters instance suffix within the 15 characters limit of
+	// Windows computer names.
+	MaxWindowsScaleSetNameLength = 9
+)
+
+const (
+	// SSHPort is the port of the SSH server of Linux machines.
+	SSHPort int32 = 22
+	// RDPPort is the port of the Remote Desktop server of Windows machines.
+	RDPPort int32 = 3389
 )
 
 const (
@@ -74,6 +89,12 @@ const (
 	// bootstrapSentinelFile is the file written by bootstrap provider on machines to indicate successful bootstrapping,
 	// as defined by the Cluster API Bootstrap Provider contract (https://cluster-api.sigs.k8s.io/developer/providers/bootstrap.html).
 	bootstrapSentinelFile = "/run/cluster-api/bootstrap-success.complete"
+	// windowsBootstrapSentinelFile is the bootstrapSentinelFile of Windows machines, on their system drive.
+	windowsBootstrapSentinelFile = "C:/run/cluster-api/bootstrap-success.complete"
+	// WindowsBootstrapExtensionAnnotation is set on the AzureMachines and AzureMachinePools whose Windows VM or VMSS was
+	// created by CAPZ with the Windows bootstrap extension. Windows machines created before that extension existed
+	// don't have it, so they don't get the extension, as their bootstrap data may not write the sentinel file.
+	WindowsBootstrapExtensionAnnotation = "sigs.k8s.io/cluster-api-provider-azure-windows-bootstrap-extension"
 )
 
 const (
@@ -142,6 +163,11 @@ func GenerateNICName(machineName string) string {
 	return fmt.Sprintf("%s-nic", machineName)
 }
 
+// GenerateRDPNATRuleName generates the name of the inbound NAT rule giving Remote Desktop access to a VM.
+func GenerateRDPNATRuleName(machineName string) string {
+	return fmt.Sprintf("%s-rdp", machineName)
+}
+
 // GeneratePublicNICName generates the name of a public network interface based on the name of a VM.
 func GeneratePublicNICName(machineName string) string {
 	return fmt.Sprintf("%s-public-nic", machineName)
@@ -165,11 +191,28 @@ func GenerateAvailabilitySetName(clusterName, nodeGroup string) string {
 	return fmt.Sprintf("%s_%s-as", clusterName, nodeGroup)
 }
 
+// GenerateWindowsScaleSetName generates the name of a Windows scale set from a name longer than
+// MaxWindowsScaleSetNameLength, keeping its first characters and a hash of the whole name so that different long names
+// don't end up with the same scale set name.
+func GenerateWindowsScaleSetName(name string) string {
+	if len(name) <= MaxWindowsScaleSetNameLength {
+		return name
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	return fmt.Sprintf("%s-%05x", strings.TrimSuffix(name[0:3], "-"), h.Sum32()&0xfffff)
+}
+
 // WithIndex appends the index as suffix to a generated name.
 func WithIndex(name string, n int) string {
 	return fmt.Sprintf("%s-%d", name, n)
 }
 
+// ResourceGroupID returns the azure resource ID for a given resource group.
+func ResourceGroupID(subscriptionID, resourceGroup string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", subscriptionID, resourceGroup)
+}
+
 // VMID returns the azure resource ID for a given VM.
 func VMID(subscriptionID, resourceGroup, vmName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachines/%s", subscriptionID, resourceGroup, vmName)
@@ -205,6 +248,31 @@ func NatGatewayID(subscriptionID, resourceGroup, natgatewayName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/natGateways/%s", subscriptionID, resourceGroup, natgatewayName)
 }
 
+// LoadBalancerID returns the azure resource ID for a given load balancer.
+func LoadBalancerID(subscriptionID, resourceGroup, loadBalancerName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/loadBalancers/%s", subscriptionID, resourceGroup, loadBalancerName)
+}
+
+// BastionHostID returns the azure resource ID for a given bastion host.
+func BastionHostID(subscriptionID, resourceGroup, bastionName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/bastionHosts/%s", subscriptionID, resourceGroup, bastionName)
+}
+
+// PrivateDNSZoneID returns the azure resource ID for a given private DNS zone.
+func PrivateDNSZoneID(subscriptionID, resourceGroup, zoneName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/privateDnsZones/%s", subscriptionID, resourceGroup, zoneName)
+}
+
+// VirtualNetworkLinkID returns the azure resource ID for a virtual network link of a given private DNS zone.
+func VirtualNetworkLinkID(subscriptionID, resourceGroup, zoneName, linkName string) string {
+	return fmt.Sprintf("%s/virtualNetworkLinks/%s", PrivateDNSZoneID(subscriptionID, resourceGroup, zoneName), linkName)
+}
+
+// DiskID returns the azure resource ID for a given managed disk.
+func DiskID(subscriptionID, resourceGroup, diskName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/disks/%s", subscriptionID, resourceGroup, diskName)
+}
+
 // NetworkInterfaceID returns the azure resource ID for a given network interface.
 func NetworkInterfaceID(subscriptionID, resourceGroup, nicName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/networkInterfaces/%s", subscriptionID, resourceGroup, nicName)
@@ -235,6 +303,16 @@ func AvailabilitySetID(subscriptionID, resourceGroup, availabilitySetName string
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/availabilitySets/%s", subscriptionID, resourceGroup, availabilitySetName)
 }
 
+// BackupPolicyID returns the azure resource ID for a given backup policy of a Recovery Services vault.
+func BackupPolicyID(subscriptionID, resourceGroup, vaultName, policyName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.RecoveryServices/vaults/%s/backupPolicies/%s", subscriptionID, resourceGroup, vaultName, policyName)
+}
+
+// DNSForwardingRulesetLinkID returns the azure resource ID for a virtual network link of a given DNS forwarding ruleset.
+func DNSForwardingRulesetLinkID(rulesetID, linkName string) string {
+	return fmt.Sprintf("%s/virtualNetworkLinks/%s", rulesetID, linkName)
+}
+
 // GetDefaultImageSKUID gets the SKU ID of the image to use for the provided version of Kubernetes.
 func getDefaultImageSKUID(k8sVersion, os, osVersion string) (string, error) {
 	version, err := semver.ParseTolerant(k8sVersion)
@@ -296,13 +374,73 @@ func GetDefaultWindowsImage(k8sVersion string) (*infrav1.Image, error) {
 	return defaultImage, nil
 }
 
+// GetDefaultAzureLinuxImage returns the default image spec for Azure Linux, whose reference images are built for
+// generation 2 VMs only.
+func GetDefaultAzureLinuxImage(k8sVersion string) (*infrav1.Image, error) {
+	skuID, err := getDefaultImageSKUID(k8sVersion, "azurelinux", "3")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get default image")
+	}
+
+	defaultImage := &infrav1.Image{
+		Marketplace: &infrav1.AzureMarketplaceImage{
+			Publisher: DefaultImagePublisherID,
+			Offer:     DefaultAzureLinuxImageOfferID,
+			SKU:       skuID + "-gen2",
+			Version:   LatestVersion,
+		},
+	}
+
+	return defaultImage, nil
+}
+
+// GetDefaultLinuxImage returns the default image spec of a Linux distribution.
+func GetDefaultLinuxImage(distribution infrav1.LinuxDistribution, k8sVersion string) (*infrav1.Image, error) {
+	if distribution == infrav1.LinuxDistributionAzureLinux {
+		return GetDefaultAzureLinuxImage(k8sVersion)
+	}
+	return GetDefaultUbuntuImage(k8sVersion)
+}
+
+// IsGeneration2Image returns true if the image is a reference image built for generation 2 VMs only, whose
+// marketplace SKU has a "-gen2" suffix.
+func IsGeneration2Image(image *infrav1.Image) bool {
+	return image != nil && image.Marketplace != nil && image.Marketplace.Publisher == DefaultImagePublisherID &&
+		strings.HasSuffix(image.Marketplace.SKU, "-gen2")
+}
+
 // GetBootstrappingVMExtension returns the CAPZ Bootstrapping VM extension.
 // The CAPZ Bootstrapping extension is a simple clone of https://github.com/Azure/custom-script-extension-linux which allows running arbitrary scripts on the VM.
 // Its role is to detect and report Kubernetes bootstrap failure or success.
 func GetBootstrappingVMExtension(osType string, cloud string) (name, publisher, version string) {
-	// currently, the bootstrap extension is only available for Linux and in AzurePublicCloud.
-	if osType == "Linux" && cloud == azure.PublicCloud.Name {
+	// currently, the bootstrap extension is only available in AzurePublicCloud.
+	if cloud != azure.PublicCloud.Name {
+		return "", "", ""
+	}
+	switch osType {
+	case "Linux":
 		return "CAPZ.Linux.Bootstrapping", "Microsoft.Azure.ContainerUpstream", "1.0"
+	case WindowsOS:
+		return "CAPZ.Windows.Bootstrapping", "Microsoft.Azure.ContainerUpstream", "1.0"
+	}
+
+	return "", "", ""
+}
+
+// GetMonitoringVMExtension returns the VM extension of an Azure Monitor agent, which is the same in all the clouds
+// providing it.
+func GetMonitoringVMExtension(agent infrav1.MonitoringAgent, osType string) (name, publisher, version string) {
+	switch agent {
+	case infrav1.MonitoringAgentAzureMonitor:
+		if osType == WindowsOS {
+			return "AzureMonitorWindowsAgent", "Microsoft.Azure.Monitor", "1.0"
+		}
+		return "AzureMonitorLinuxAgent", "Microsoft.Azure.Monitor", "1.0"
+	case infrav1.MonitoringAgentLogAnalytics:
+		if osType == WindowsOS {
+			return "MicrosoftMonitoringAgent", "Microsoft.EnterpriseCloud.Monitoring", "1.0"
+		}
+		return "OmsAgentForLinux", "Microsoft.EnterpriseCloud.Monitoring", "1.13"
 	}
 
 	return "", "", ""
@@ -310,7 +448,11 @@ func GetBootstrappingVMExtension(osType string, cloud string) (name, publisher,
 
 // BootstrapExtensionCommand is the command that runs on the Boostrap VM extension to check for bootstrap success.
 // The command checks for the existence of the bootstrapSentinelFile on the machine, with retries and sleep between retries.
-func BootstrapExtensionCommand() string {
+// Windows machines run it with PowerShell rather than with a POSIX shell.
+func BootstrapExtensionCommand(osType string) string {
+	if osType == WindowsOS {
+		return fmt.Sprintf("powershell.exe -Command \"for ($i = 0; $i -lt %d; $i++) { if (Test-Path %s) { exit 0 }; Start-Sleep -Seconds %d }; exit 1\"", bootstrapExtensionRetries, windowsBootstrapSentinelFile, bootstrapExtensionSleep)
+	}
 	return fmt.Sprintf("for i in $(seq 1 %d); do test -f %s && break; if [ $i -eq %d ]; then return 1; else sleep %d; fi; done", bootstrapExtensionRetries, bootstrapSentinelFile, bootstrapExtensionRetries, bootstrapExtensionSleep)
 }
 
@@ -319,10 +461,28 @@ func UserAgent() string {
 	return fmt.Sprintf("cluster-api-provider-azure/%s", version.Get().String())
 }
 
-// SetAutoRestClientDefaults set authorizer and user agent for autorest client.
+// SetAutoRestClientDefaults set authorizer, user agent, API profile, rate limiting, metrics, operation policies, dry-run
+// mode and tracing for autorest client.
 func SetAutoRestClientDefaults(c *autorest.Client, auth autorest.Authorizer) {
 	c.Authorizer = auth
 	AutoRestClientAppendUserAgent(c, UserAgent())
+	// Decorators are applied from the innermost to the outermost, so that the metrics only measure the requests sent to
+	// Azure and not the time they were held back by rate limiting, while the trace spans include it.
+	if sender := HTTPSender(); sender != nil {
+		c.Sender = sender
+	}
+	if c.Sender == nil {
+		c.Sender = autorest.CreateSender(WithAPIProfileVersions(), WithMetrics(), WithRateLimiting(), WithCorrelationRequestID(), WithOperationPolicy(), WithDryRunMode(), WithTracing())
+	} else {
+		c.Sender = autorest.DecorateSender(c.Sender, WithAPIProfileVersions(), WithMetrics(), WithRateLimiting(), WithCorrelationRequestID(), WithOperationPolicy(), WithDryRunMode(), WithTracing())
+	}
+	if hasRetryPolicy() {
+		// Requests are retried by WithOperationPolicy instead, which applies the retries of their operation type.
+		c.RetryAttempts = 1
+	}
+	if delay := clientPollingDelay(); delay > 0 {
+		c.PollingDelay = delay
+	}
 }
 
 // AutoRestClientAppendUserAgent autorest client calls "AddToUserAgent" but ignores errors.
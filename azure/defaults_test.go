@@ -21,7 +21,10 @@ import (
 	"testing"
 
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
 	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 )
 
 func TestGetDefaultImageSKUID(t *testing.T) {
@@ -235,3 +238,164 @@ func TestGetDefaultUbuntuImage(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDefaultLinuxImage(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		distribution  infrav1.LinuxDistribution
+		expectedOffer string
+		expectedSKU   string
+	}{
+		{
+			expectedOffer: DefaultImageOfferID,
+			expectedSKU:   "k8s-1dot21dot2-ubuntu-2004",
+		},
+		{
+			distribution:  infrav1.LinuxDistributionUbuntu,
+			expectedOffer: DefaultImageOfferID,
+			expectedSKU:   "k8s-1dot21dot2-ubuntu-2004",
+		},
+		{
+			distribution:  infrav1.LinuxDistributionAzureLinux,
+			expectedOffer: DefaultAzureLinuxImageOfferID,
+			expectedSKU:   "k8s-1dot21dot2-azurelinux-3-gen2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.distribution), func(t *testing.T) {
+			image, err := GetDefaultLinuxImage(test.distribution, "v1.21.2")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(image.Marketplace.Offer).To(Equal(test.expectedOffer))
+			g.Expect(image.Marketplace.SKU).To(Equal(test.expectedSKU))
+			g.Expect(IsGeneration2Image(image)).To(Equal(test.distribution == infrav1.LinuxDistributionAzureLinux))
+		})
+	}
+}
+
+func TestGenerateWindowsScaleSetName(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{
+			name:     "mp-win",
+			expected: "mp-win",
+		},
+		{
+			name:     "mp-win-12",
+			expected: "mp-win-12",
+		},
+		{
+			name:     "machine-90123456",
+			expected: "mac-22927",
+		},
+		{
+			name:     "capz-e2e-mp-win",
+			expected: "cap-550c1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			name := GenerateWindowsScaleSetName(test.name)
+			g.Expect(name).To(Equal(test.expected))
+			g.Expect(len(name)).To(BeNumerically("<=", MaxWindowsScaleSetNameLength))
+		})
+	}
+}
+
+func TestGetBootstrappingVMExtension(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		osType       string
+		cloud        string
+		expectedName string
+	}{
+		{
+			osType:       "Linux",
+			cloud:        azure.PublicCloud.Name,
+			expectedName: "CAPZ.Linux.Bootstrapping",
+		},
+		{
+			osType:       WindowsOS,
+			cloud:        azure.PublicCloud.Name,
+			expectedName: "CAPZ.Windows.Bootstrapping",
+		},
+		{
+			osType: "Linux",
+			cloud:  azure.USGovernmentCloud.Name,
+		},
+		{
+			osType: WindowsOS,
+			cloud:  azure.ChinaCloud.Name,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.osType+"-"+test.cloud, func(t *testing.T) {
+			name, _, _ := GetBootstrappingVMExtension(test.osType, test.cloud)
+			g.Expect(name).To(Equal(test.expectedName))
+		})
+	}
+}
+
+func TestGetMonitoringVMExtension(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		agent             infrav1.MonitoringAgent
+		osType            string
+		expectedName      string
+		expectedPublisher string
+	}{
+		{
+			agent:             infrav1.MonitoringAgentAzureMonitor,
+			osType:            "Linux",
+			expectedName:      "AzureMonitorLinuxAgent",
+			expectedPublisher: "Microsoft.Azure.Monitor",
+		},
+		{
+			agent:             infrav1.MonitoringAgentAzureMonitor,
+			osType:            WindowsOS,
+			expectedName:      "AzureMonitorWindowsAgent",
+			expectedPublisher: "Microsoft.Azure.Monitor",
+		},
+		{
+			agent:             infrav1.MonitoringAgentLogAnalytics,
+			osType:            "Linux",
+			expectedName:      "OmsAgentForLinux",
+			expectedPublisher: "Microsoft.EnterpriseCloud.Monitoring",
+		},
+		{
+			agent:             infrav1.MonitoringAgentLogAnalytics,
+			osType:            WindowsOS,
+			expectedName:      "MicrosoftMonitoringAgent",
+			expectedPublisher: "Microsoft.EnterpriseCloud.Monitoring",
+		},
+		{
+			agent:  "SomeAgent",
+			osType: "Linux",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.agent)+"-"+test.osType, func(t *testing.T) {
+			name, publisher, _ := GetMonitoringVMExtension(test.agent, test.osType)
+			g.Expect(name).To(Equal(test.expectedName))
+			g.Expect(publisher).To(Equal(test.expectedPublisher))
+		})
+	}
+}
+
+func TestBootstrapExtensionCommand(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(BootstrapExtensionCommand("Linux")).To(ContainSubstring("test -f /run/cluster-api/bootstrap-success.complete"))
+	g.Expect(BootstrapExtensionCommand(WindowsOS)).To(HavePrefix("powershell.exe"))
+	g.Expect(BootstrapExtensionCommand(WindowsOS)).To(ContainSubstring("Test-Path C:/run/cluster-api/bootstrap-success.complete"))
+}
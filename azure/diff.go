@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IsUpToDate returns whether an existing Azure resource already has all the properties set in the parameters of a
+// create or update request, in which case the request can be skipped. Properties only present in the existing
+// resource, like read-only properties or tags added outside of Cluster API, are ignored. Strings are compared case
+// insensitively, as Azure doesn't always preserve the case of resource IDs and enum values.
+//
+// It is used by the services which update their existing resources to match their spec. The services which only create
+// missing resources, e.g. route tables, subnets, virtual networks and network interfaces, or only add the missing
+// rules to existing ones, e.g. security groups and load balancers, don't send requests for resources which are already
+// up to date either.
+func IsUpToDate(desired, existing interface{}) (bool, error) {
+	desiredValue, err := toJSONValue(desired)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to marshal desired Azure resource")
+	}
+	existingValue, err := toJSONValue(existing)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to marshal existing Azure resource")
+	}
+	return isSubset(desiredValue, existingValue), nil
+}
+
+// toJSONValue converts an Azure SDK object into the generic value of its JSON representation, which only contains
+// the properties sent to Azure.
+func toJSONValue(obj interface{}) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// isSubset returns whether all the values set in desired are equal in existing.
+func isSubset(desired, existing interface{}) bool {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		e, ok := existing.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range d {
+			if value == nil {
+				continue
+			}
+			if existingValue, ok := e[key]; !ok || !isSubset(value, existingValue) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		e, ok := existing.([]interface{})
+		if !ok || len(d) != len(e) {
+			return false
+		}
+		for i := range d {
+			if !isSubset(d[i], e[i]) {
+				return false
+			}
+		}
+		return true
+	case string:
+		e, ok := existing.(string)
+		return ok && strings.EqualFold(d, e)
+	default:
+		return reflect.DeepEqual(desired, existing)
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+)
+
+func TestIsUpToDate(t *testing.T) {
+	desired := network.PublicIPAddress{
+		Name:     to.StringPtr("my-publicip"),
+		Location: to.StringPtr("eastus"),
+		Tags:     map[string]*string{"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned")},
+		Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
+		Zones:    &[]string{"1", "2", "3"},
+		PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+			PublicIPAddressVersion:   network.IPVersionIPv4,
+			PublicIPAllocationMethod: network.IPAllocationMethodStatic,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		existing network.PublicIPAddress
+		want     bool
+	}{
+		{
+			name: "existing resource has the same properties",
+			existing: network.PublicIPAddress{
+				ID:       to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-publicip"),
+				Name:     to.StringPtr("my-publicip"),
+				Location: to.StringPtr("eastus"),
+				Tags:     map[string]*string{"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned")},
+				Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard, Tier: network.PublicIPAddressSkuTierRegional},
+				Zones:    &[]string{"1", "2", "3"},
+				PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+					PublicIPAddressVersion:   network.IPVersionIPv4,
+					PublicIPAllocationMethod: network.IPAllocationMethodStatic,
+					IPAddress:                to.StringPtr("1.2.3.4"),
+					IdleTimeoutInMinutes:     to.Int32Ptr(4),
+				},
+			},
+			want: true,
+		},
+		{
+			name: "existing resource has additional tags and different case",
+			existing: network.PublicIPAddress{
+				Name:     to.StringPtr("my-publicip"),
+				Location: to.StringPtr("EastUS"),
+				Tags: map[string]*string{
+					"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+					"foo": to.StringPtr("bar"),
+				},
+				Sku:   &network.PublicIPAddressSku{Name: "standard"},
+				Zones: &[]string{"1", "2", "3"},
+				PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+					PublicIPAddressVersion:   "ipv4",
+					PublicIPAllocationMethod: network.IPAllocationMethodStatic,
+				},
+			},
+			want: true,
+		},
+		{
+			name: "existing resource is missing a tag",
+			existing: network.PublicIPAddress{
+				Name:     to.StringPtr("my-publicip"),
+				Location: to.StringPtr("eastus"),
+				Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
+				Zones:    &[]string{"1", "2", "3"},
+				PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+					PublicIPAddressVersion:   network.IPVersionIPv4,
+					PublicIPAllocationMethod: network.IPAllocationMethodStatic,
+				},
+			},
+			want: false,
+		},
+		{
+			name: "existing resource has a different property",
+			existing: network.PublicIPAddress{
+				Name:     to.StringPtr("my-publicip"),
+				Location: to.StringPtr("eastus"),
+				Tags:     map[string]*string{"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned")},
+				Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
+				Zones:    &[]string{"1", "2", "3"},
+				PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+					PublicIPAddressVersion:   network.IPVersionIPv4,
+					PublicIPAllocationMethod: network.IPAllocationMethodDynamic,
+				},
+			},
+			want: false,
+		},
+		{
+			name: "existing resource has different list items",
+			existing: network.PublicIPAddress{
+				Name:     to.StringPtr("my-publicip"),
+				Location: to.StringPtr("eastus"),
+				Tags:     map[string]*string{"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned")},
+				Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
+				Zones:    &[]string{"1"},
+				PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+					PublicIPAddressVersion:   network.IPVersionIPv4,
+					PublicIPAllocationMethod: network.IPAllocationMethodStatic,
+				},
+			},
+			want: false,
+		},
+		{
+			name:     "existing resource is empty",
+			existing: network.PublicIPAddress{},
+			want:     false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := IsUpToDate(desired, tc.existing)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tc.want))
+		})
+	}
+}
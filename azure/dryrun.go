@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/Azure/go-autorest/autorest"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ResourceDryRunReason is the reason of the events recorded for the Azure API requests skipped in dry-run mode.
+const ResourceDryRunReason = "AzureResourceDryRun"
+
+var dryRunEnabled bool
+
+// SetDryRun enables the dry-run mode for all the objects, in which the Azure API requests which would change Azure
+// resources are reported instead of being sent. It is meant to be called once at startup.
+func SetDryRun(enabled bool) {
+	dryRunEnabled = enabled
+}
+
+// DryRunError is returned instead of sending an Azure API request which would change Azure resources in dry-run mode.
+type DryRunError struct {
+	// Method is the HTTP method of the request, e.g. PUT.
+	Method string
+	// ResourceType is the type of the resource the request is about, e.g. Microsoft.Network/virtualNetworks.
+	ResourceType string
+	// Path is the path of the resource the request is about.
+	Path string
+}
+
+// Error returns the request which was skipped.
+func (e DryRunError) Error() string {
+	return fmt.Sprintf("dry run: would send %s %s", e.Method, e.Path)
+}
+
+// IsDryRunError returns true if the error is, or wraps, a DryRunError.
+func IsDryRunError(err error) bool {
+	return errors.As(err, &DryRunError{})
+}
+
+type dryRunKey struct{}
+
+// dryRunReport collects the requests skipped in dry-run mode during a reconciliation.
+type dryRunReport struct {
+	mu      sync.Mutex
+	skipped []DryRunError
+}
+
+// WithDryRun returns a context in which the Azure API requests which would change Azure resources are reported
+// instead of being sent, whether or not the dry-run mode is enabled for all the objects. The skipped requests can
+// be listed with DryRunOperations.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, &dryRunReport{})
+}
+
+// IsDryRun returns true if the Azure API requests which would change Azure resources are not sent in the context.
+func IsDryRun(ctx context.Context) bool {
+	_, ok := ctx.Value(dryRunKey{}).(*dryRunReport)
+	return ok || dryRunEnabled
+}
+
+// DryRunOperations returns the requests skipped so far in a context created by WithDryRun.
+func DryRunOperations(ctx context.Context) []DryRunError {
+	report, ok := ctx.Value(dryRunKey{}).(*dryRunReport)
+	if !ok {
+		return nil
+	}
+	report.mu.Lock()
+	defer report.mu.Unlock()
+	return append([]DryRunError(nil), report.skipped...)
+}
+
+// WithDryRunMode returns a SendDecorator which, in dry-run mode, only sends the GET and HEAD requests. The other
+// requests are logged and recorded as events, and fail with a DryRunError.
+func WithDryRunMode() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			if !IsDryRun(ctx) || req.Method == http.MethodGet || req.Method == http.MethodHead {
+				return s.Do(req)
+			}
+
+			dryRunErr := DryRunError{
+				Method:       req.Method,
+				ResourceType: resourceTypeFromPath(req.URL.Path),
+				Path:         req.URL.Path,
+			}
+			if report, ok := ctx.Value(dryRunKey{}).(*dryRunReport); ok {
+				report.mu.Lock()
+				report.skipped = append(report.skipped, dryRunErr)
+				report.mu.Unlock()
+			}
+			log.FromContext(ctx).Info("Skipping Azure API request in dry-run mode", "method", req.Method, "path", req.URL.Path)
+			RecordEvent(ctx, corev1.EventTypeNormal, ResourceDryRunReason, "Would send %s %s", req.Method, req.URL.Path)
+			return nil, dryRunErr
+		})
+	}
+}
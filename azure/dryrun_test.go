@@ -0,0 +1,59 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestWithDryRunMode(t *testing.T) {
+	g := NewWithT(t)
+
+	var sent []string
+	sender := WithDryRunMode()(autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+		sent = append(sent, req.Method)
+		return &http.Response{StatusCode: http.StatusOK, Request: req}, nil
+	}))
+	recorder := record.NewFakeRecorder(1)
+	ctx := WithDryRun(WithEventRecorder(context.TODO(), recorder, &corev1.Pod{}))
+	path := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"
+
+	_, err := sender.Do((&http.Request{Method: http.MethodGet, URL: &url.URL{Path: path}}).WithContext(ctx))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = sender.Do((&http.Request{Method: http.MethodPut, URL: &url.URL{Path: path}}).WithContext(ctx))
+	g.Expect(IsDryRunError(errors.Wrap(err, "failed to create"))).To(BeTrue())
+	g.Expect(sent).To(Equal([]string{http.MethodGet}))
+	g.Expect(DryRunOperations(ctx)).To(Equal([]DryRunError{
+		{Method: http.MethodPut, ResourceType: "Microsoft.Network/virtualNetworks", Path: path},
+	}))
+	g.Expect(recorder.Events).To(Receive(Equal("Normal AzureResourceDryRun Would send PUT " + path)))
+
+	// Outside of dry-run mode, every request is sent.
+	_, err = sender.Do((&http.Request{Method: http.MethodDelete, URL: &url.URL{Path: path}}).WithContext(context.TODO()))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sent).To(Equal([]string{http.MethodGet, http.MethodDelete}))
+}
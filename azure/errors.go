@@ -17,12 +17,17 @@ limitations under the License.
 package azure
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 )
@@ -30,7 +35,31 @@ import (
 // ErrNotOwned is returned when a resource can't be deleted because it isn't owned.
 var ErrNotOwned = errors.New("resource is not managed and cannot be deleted")
 
-const codeResourceGroupNotFound = "ResourceGroupNotFound"
+const (
+	codeResourceGroupNotFound    = "ResourceGroupNotFound"
+	codeDNSRecordInUse           = "DnsRecordInUse"
+	codeQuotaExceeded            = "QuotaExceeded"
+	codeOperationNotAllowed      = "OperationNotAllowed"
+	codeSkuNotAvailable          = "SkuNotAvailable"
+	codeInvalidParameter         = "InvalidParameter"
+	codePropertyChangeNotAllowed = "PropertyChangeNotAllowed"
+)
+
+// DefaultTimeoutBackoff is how long to wait before retrying an Azure API request which timed out.
+const DefaultTimeoutBackoff = 30 * time.Second
+
+// DefaultQuotaExceededBackoff is how long to wait before checking again the quotas which prevented the creation of
+// resources, as they only change when resources are deleted or the limits are raised.
+const DefaultQuotaExceededBackoff = 5 * time.Minute
+
+// terminalErrorCodes maps the codes of the ARM errors which can't be recovered from by retrying the same request to
+// the failure reason of the machine they happened to.
+var terminalErrorCodes = map[string]capierrors.MachineStatusError{
+	codeQuotaExceeded:            capierrors.InsufficientResourcesMachineError,
+	codeSkuNotAvailable:          capierrors.InsufficientResourcesMachineError,
+	codeInvalidParameter:         capierrors.InvalidConfigurationMachineError,
+	codePropertyChangeNotAllowed: capierrors.UnsupportedChangeMachineError,
+}
 
 // ResourceGroupNotFound parses the error to check if it's a resource group not found error.
 func ResourceGroupNotFound(err error) bool {
@@ -39,6 +68,14 @@ func ResourceGroupNotFound(err error) bool {
 	return errors.As(err, &derr) && errors.As(derr.Original, &serr) && serr.Code == codeResourceGroupNotFound
 }
 
+// DNSRecordInUse parses the error to check if it's a DNS label conflict error, which happens when the
+// requested domain name label is already used by another public IP in the same region.
+func DNSRecordInUse(err error) bool {
+	derr := autorest.DetailedError{}
+	serr := &azure.ServiceError{}
+	return errors.As(err, &derr) && errors.As(derr.Original, &serr) && serr.Code == codeDNSRecordInUse
+}
+
 // ResourceNotFound parses the error to check if it's a resource not found error.
 func ResourceNotFound(err error) bool {
 	derr := autorest.DetailedError{}
@@ -61,6 +98,25 @@ func (vde VMDeletedError) Error() string {
 	return fmt.Sprintf("VM with provider id %q has been deleted", vde.ProviderID)
 }
 
+// QuotaExceededError is returned when creating resources would exceed a quota of the subscription in a location.
+type QuotaExceededError struct {
+	Quota     string
+	Location  string
+	Limit     int64
+	Usage     int64
+	Requested int64
+}
+
+// Error returns the error string.
+func (qee QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s quota exceeded in location %s: %d requested, %d of %d already used", qee.Quota, qee.Location, qee.Requested, qee.Usage, qee.Limit)
+}
+
+// IsQuotaExceededError returns true if the error is caused by a QuotaExceededError.
+func IsQuotaExceededError(err error) bool {
+	return errors.As(err, &QuotaExceededError{})
+}
+
 // ReconcileError represents an error that is not automatically recoverable
 // errorType indicates what type of action is required to recover. It can take two values:
 // 1. `Transient` - Can be recovered through manual intervention, will be requeued after.
@@ -107,6 +163,11 @@ func (t ReconcileError) IsTerminal() bool {
 	return t.errorType == TerminalErrorType
 }
 
+// Unwrap returns the underlying error.
+func (t ReconcileError) Unwrap() error {
+	return t.error
+}
+
 // Is returns true if the target is a ReconcileError.
 func (t ReconcileError) Is(target error) bool {
 	return errors.As(target, &ReconcileError{})
@@ -148,3 +209,90 @@ func (onde OperationNotDoneError) Error() string {
 func (onde OperationNotDoneError) Is(target error) bool {
 	return errors.As(target, &OperationNotDoneError{})
 }
+
+// IsOperationNotDoneError returns true if the error is, or wraps, an OperationNotDoneError.
+func IsOperationNotDoneError(err error) bool {
+	var onde *OperationNotDoneError
+	return errors.As(err, &onde)
+}
+
+// serviceError returns the ARM error wrapped by err, if any.
+func serviceError(err error) *azure.ServiceError {
+	derr := autorest.DetailedError{}
+	if errors.As(err, &derr) {
+		err = derr.Original
+	}
+	serr := &azure.ServiceError{}
+	if errors.As(err, &serr) {
+		return serr
+	}
+	rerr := &azure.RequestError{}
+	if errors.As(err, &rerr) && rerr.ServiceError != nil {
+		return rerr.ServiceError
+	}
+	return nil
+}
+
+// serviceErrorCode returns the code of the ARM error wrapped by err, if any.
+func serviceErrorCode(err error) (code string, message string) {
+	if serr := serviceError(err); serr != nil {
+		return serr.Code, serr.Message
+	}
+	return "", ""
+}
+
+// terminalErrorCode returns the code of the ARM error wrapped by err if it can't be recovered from by retrying.
+func terminalErrorCode(err error) (string, bool) {
+	code, message := serviceErrorCode(err)
+	if code == codeOperationNotAllowed && strings.Contains(strings.ToLower(message), "quota") {
+		// Compute reports exceeded core quotas as OperationNotAllowed.
+		code = codeQuotaExceeded
+	}
+	_, ok := terminalErrorCodes[code]
+	return code, ok
+}
+
+// TimedOut parses the error to check if the request to Azure timed out, either on the client side or on the server side.
+func TimedOut(err error) bool {
+	derr := autorest.DetailedError{}
+	if errors.As(err, &derr) && (derr.StatusCode == http.StatusRequestTimeout || derr.StatusCode == http.StatusGatewayTimeout) {
+		return true
+	}
+	var nerr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &nerr) && nerr.Timeout())
+}
+
+// ClassifyError wraps the ARM errors which can't be recovered from by retrying, such as exceeded quotas, unavailable
+// SKUs and invalid parameters, in a terminal ReconcileError, and throttling errors, timeouts and the quotas found
+// exceeded before creating resources in a transient ReconcileError. Other errors, including errors which are already classified, are returned unchanged.
+func ClassifyError(err error) error {
+	if err == nil || errors.As(err, &ReconcileError{}) {
+		return err
+	}
+	if IsQuotaExceededError(err) {
+		return WithTransientError(err, DefaultQuotaExceededBackoff)
+	}
+	if _, ok := terminalErrorCode(err); ok {
+		return WithTerminalError(err)
+	}
+	derr := autorest.DetailedError{}
+	if errors.As(err, &derr) && derr.StatusCode == http.StatusTooManyRequests {
+		requeueAfter := DefaultThrottleBackoff
+		if derr.Response != nil {
+			requeueAfter = retryAfter(derr.Response)
+		}
+		return WithTransientError(err, requeueAfter)
+	}
+	if TimedOut(err) {
+		return WithTransientError(err, DefaultTimeoutBackoff)
+	}
+	return err
+}
+
+// MachineFailureReason returns the failure reason to set on a machine which failed to reconcile with a terminal error.
+func MachineFailureReason(err error) capierrors.MachineStatusError {
+	if code, ok := terminalErrorCode(err); ok {
+		return terminalErrorCodes[code]
+	}
+	return capierrors.CreateMachineError
+}
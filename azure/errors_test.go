@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+)
+
+func newServiceError(statusCode int, code, message string) error {
+	return autorest.DetailedError{
+		Original:   &azure.ServiceError{Code: code, Message: message},
+		StatusCode: statusCode,
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	throttled := autorest.DetailedError{
+		StatusCode: http.StatusTooManyRequests,
+		Response:   &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"12"}}},
+	}
+
+	tests := []struct {
+		name          string
+		err           error
+		wantTerminal  bool
+		wantTransient bool
+		wantRequeue   time.Duration
+		wantReason    capierrors.MachineStatusError
+	}{
+		{
+			name:         "quota exceeded",
+			err:          errors.Wrap(newServiceError(http.StatusBadRequest, "QuotaExceeded", "Quota exceeded"), "failed to create VM"),
+			wantTerminal: true,
+			wantReason:   capierrors.InsufficientResourcesMachineError,
+		},
+		{
+			name:         "core quota exceeded",
+			err:          newServiceError(http.StatusConflict, "OperationNotAllowed", "Operation could not be completed as it results in exceeding approved standardDSv3Family Cores quota."),
+			wantTerminal: true,
+			wantReason:   capierrors.InsufficientResourcesMachineError,
+		},
+		{
+			name:         "SKU not available",
+			err:          newServiceError(http.StatusConflict, "SkuNotAvailable", "The requested size for resource is currently not available in location"),
+			wantTerminal: true,
+			wantReason:   capierrors.InsufficientResourcesMachineError,
+		},
+		{
+			name:         "invalid parameter",
+			err:          newServiceError(http.StatusBadRequest, "InvalidParameter", "The value of parameter imageReference.sku is invalid."),
+			wantTerminal: true,
+			wantReason:   capierrors.InvalidConfigurationMachineError,
+		},
+		{
+			name:          "throttled",
+			err:           errors.Wrap(throttled, "failed to get VM"),
+			wantTransient: true,
+			wantRequeue:   12 * time.Second,
+			wantReason:    capierrors.CreateMachineError,
+		},
+		{
+			name:          "gateway timeout",
+			err:           autorest.DetailedError{StatusCode: http.StatusGatewayTimeout},
+			wantTransient: true,
+			wantRequeue:   DefaultTimeoutBackoff,
+			wantReason:    capierrors.CreateMachineError,
+		},
+		{
+			name:          "client timeout",
+			err:           errors.Wrap(context.DeadlineExceeded, "failed to get VM"),
+			wantTransient: true,
+			wantRequeue:   DefaultTimeoutBackoff,
+			wantReason:    capierrors.CreateMachineError,
+		},
+		{
+			name:          "quota exceeded before creation",
+			err:           errors.Wrap(QuotaExceededError{Quota: "Total Regional vCPUs", Location: "eastus", Limit: 10, Usage: 8, Requested: 4}, "failed to create VM"),
+			wantTransient: true,
+			wantRequeue:   DefaultQuotaExceededBackoff,
+			wantReason:    capierrors.CreateMachineError,
+		},
+		{
+			name:       "operation not allowed for other reasons",
+			err:        newServiceError(http.StatusConflict, "OperationNotAllowed", "Another operation is in progress."),
+			wantReason: capierrors.CreateMachineError,
+		},
+		{
+			name:       "internal server error",
+			err:        newServiceError(http.StatusInternalServerError, "InternalServerError", "An internal error occurred."),
+			wantReason: capierrors.CreateMachineError,
+		},
+		{
+			name:          "already classified",
+			err:           WithTransientError(newServiceError(http.StatusBadRequest, "InvalidParameter", ""), time.Minute),
+			wantTransient: true,
+			wantRequeue:   time.Minute,
+			wantReason:    capierrors.InvalidConfigurationMachineError,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := ClassifyError(tc.err)
+			g.Expect(err.Error()).To(ContainSubstring(tc.err.Error()))
+
+			var reconcileError ReconcileError
+			classified := errors.As(err, &reconcileError)
+			g.Expect(classified).To(Equal(tc.wantTerminal || tc.wantTransient))
+			if classified {
+				g.Expect(reconcileError.IsTerminal()).To(Equal(tc.wantTerminal))
+				g.Expect(reconcileError.IsTransient()).To(Equal(tc.wantTransient))
+				g.Expect(reconcileError.RequeueAfter()).To(Equal(tc.wantRequeue))
+			}
+			g.Expect(MachineFailureReason(err)).To(Equal(tc.wantReason))
+		})
+	}
+}
+
+func TestClassifyErrorNil(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(ClassifyError(nil)).To(BeNil())
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// ResourceCreatedReason is the reason of the events recorded when an Azure resource is created or updated.
+	ResourceCreatedReason = "AzureResourceCreated"
+	// ResourceCreateFailedReason is the reason of the events recorded when an Azure resource fails to be created or updated.
+	ResourceCreateFailedReason = "AzureResourceCreateFailed"
+	// ResourceDeletedReason is the reason of the events recorded when an Azure resource is deleted.
+	ResourceDeletedReason = "AzureResourceDeleted"
+	// ResourceDeleteFailedReason is the reason of the events recorded when an Azure resource fails to be deleted.
+	ResourceDeleteFailedReason = "AzureResourceDeleteFailed"
+)
+
+type eventRecorderKey struct{}
+
+// objectEventRecorder records events on a single object.
+type objectEventRecorder struct {
+	recorder record.EventRecorder
+	object   runtime.Object
+}
+
+// WithEventRecorder returns a context which makes the services record the lifecycle events of the Azure resources
+// they reconcile on the given object, e.g. the AzureCluster or AzureMachine being reconciled.
+func WithEventRecorder(ctx context.Context, recorder record.EventRecorder, object runtime.Object) context.Context {
+	if recorder == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, eventRecorderKey{}, objectEventRecorder{recorder: recorder, object: object})
+}
+
+// RecordEvent records an event on the object of the context, if any.
+func RecordEvent(ctx context.Context, eventType, reason, messageFmt string, args ...interface{}) {
+	if r, ok := ctx.Value(eventRecorderKey{}).(objectEventRecorder); ok {
+		r.recorder.Eventf(r.object, eventType, reason, messageFmt, args...)
+	}
+}
+
+// RecordCreate records the outcome of creating or updating an Azure resource of the given kind, e.g. "public IP".
+// Operations still in progress are not recorded, so that their outcome is recorded once they are done, and neither
+// are the ones skipped in dry-run mode, which are recorded when they are skipped.
+func RecordCreate(ctx context.Context, kind, name string, err error) {
+	switch {
+	case err == nil:
+		RecordEvent(ctx, corev1.EventTypeNormal, ResourceCreatedReason, "Created %s %s", kind, name)
+	case !IsOperationNotDoneError(err) && !IsDryRunError(err):
+		RecordEvent(ctx, corev1.EventTypeWarning, ResourceCreateFailedReason, "Failed to create %s %s: %s", kind, name, ErrorMessage(err))
+	}
+}
+
+// RecordDelete records the outcome of deleting an Azure resource of the given kind, e.g. "public IP". Operations
+// still in progress or skipped in dry-run mode, and resources which were already deleted, are not recorded.
+func RecordDelete(ctx context.Context, kind, name string, err error) {
+	switch {
+	case err == nil:
+		RecordEvent(ctx, corev1.EventTypeNormal, ResourceDeletedReason, "Deleted %s %s", kind, name)
+	case !IsOperationNotDoneError(err) && !ResourceNotFound(err) && !IsDryRunError(err):
+		RecordEvent(ctx, corev1.EventTypeWarning, ResourceDeleteFailedReason, "Failed to delete %s %s: %s", kind, name, ErrorMessage(err))
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+)
+
+func TestRecordCreate(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		event string
+	}{
+		{
+			name:  "created",
+			event: "Normal AzureResourceCreated Created public IP my-ip",
+		},
+		{
+			name:  "failed",
+			err:   errors.New("quota exceeded"),
+			event: "Warning AzureResourceCreateFailed Failed to create public IP my-ip: quota exceeded",
+		},
+		{
+			name: "in progress",
+			err:  WithTransientError(NewOperationNotDoneError(&infrav1.Future{}), time.Second),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			recorder := record.NewFakeRecorder(1)
+			ctx := WithEventRecorder(context.TODO(), recorder, &corev1.Pod{})
+
+			RecordCreate(ctx, "public IP", "my-ip", tt.err)
+			if tt.event == "" {
+				g.Expect(recorder.Events).To(BeEmpty())
+			} else {
+				g.Expect(recorder.Events).To(Receive(Equal(tt.event)))
+			}
+		})
+	}
+}
+
+func TestRecordDelete(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		event string
+	}{
+		{
+			name:  "deleted",
+			event: "Normal AzureResourceDeleted Deleted virtual machine my-vm",
+		},
+		{
+			name:  "failed",
+			err:   errors.New("conflict"),
+			event: "Warning AzureResourceDeleteFailed Failed to delete virtual machine my-vm: conflict",
+		},
+		{
+			name: "already deleted",
+			err:  autorest.DetailedError{StatusCode: http.StatusNotFound},
+		},
+		{
+			name: "in progress",
+			err:  NewOperationNotDoneError(&infrav1.Future{}),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			recorder := record.NewFakeRecorder(1)
+			ctx := WithEventRecorder(context.TODO(), recorder, &corev1.Pod{})
+
+			RecordDelete(ctx, "virtual machine", "my-vm", tt.err)
+			if tt.event == "" {
+				g.Expect(recorder.Events).To(BeEmpty())
+			} else {
+				g.Expect(recorder.Events).To(Receive(Equal(tt.event)))
+			}
+		})
+	}
+}
+
+func TestRecordEventWithoutRecorder(t *testing.T) {
+	// Services reconciled without an event recorder in their context don't record anything.
+	RecordCreate(context.TODO(), "public IP", "my-ip", nil)
+	RecordDelete(WithEventRecorder(context.TODO(), nil, &corev1.Pod{}), "public IP", "my-ip", nil)
+}
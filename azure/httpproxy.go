@@ -0,0 +1,197 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// HTTPProxyConfig is the outbound proxy and the additional trusted CA certificates of restricted environments.
+type HTTPProxyConfig struct {
+	// HTTPProxy is the URL of the proxy of the HTTP requests of the nodes.
+	HTTPProxy string
+	// HTTPSProxy is the URL of the proxy of the HTTPS requests, e.g. http://proxy.example.com:3128.
+	HTTPSProxy string
+	// NoProxy is the comma-separated list of the hosts, domains and CIDRs the nodes reach without the proxy.
+	NoProxy string
+	// CABundle holds the PEM encoded CA certificates trusted in addition to the system ones.
+	CABundle []byte
+	// Bootstrap enables adding the proxy and CA settings to the cloud-init bootstrap data of the nodes.
+	Bootstrap bool
+}
+
+// proxyDropInPaths are the systemd drop-ins setting the proxy environment variables of the node services which pull
+// images or reach the Azure API.
+var proxyDropInPaths = []string{
+	"/etc/systemd/system/containerd.service.d/http-proxy.conf",
+	"/etc/systemd/system/kubelet.service.d/http-proxy.conf",
+}
+
+var (
+	httpProxyConfig HTTPProxyConfig
+	httpSender      *http.Client
+)
+
+// SetHTTPProxy configures the proxy and the additional trusted CA certificates of the requests sent to Azure, and of
+// the nodes when enabled. The requests sent to Azure go through the proxy of the environment when no HTTPS proxy is
+// set. It is meant to be called once at startup, before any Azure client is created.
+func SetHTTPProxy(config HTTPProxyConfig) error {
+	for _, proxy := range []string{config.HTTPProxy, config.HTTPSProxy} {
+		if proxy == "" {
+			continue
+		}
+		if u, err := url.Parse(proxy); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.Errorf("invalid proxy URL %q", proxy)
+		}
+	}
+
+	if config.HTTPSProxy == "" && len(config.CABundle) == 0 {
+		httpProxyConfig, httpSender = config, nil
+		return nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.HTTPSProxy != "" {
+		proxyURL, _ := url.Parse(config.HTTPSProxy)
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	if len(config.CABundle) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(config.CABundle) {
+			return errors.New("the CA bundle does not have any valid PEM encoded certificate")
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	httpProxyConfig, httpSender = config, &http.Client{Transport: transport}
+	return nil
+}
+
+// HTTPSender returns the client sending the requests to Azure through the configured proxy and trusting the
+// configured CA certificates, or nil when none is configured and the default client is used.
+func HTTPSender() *http.Client {
+	return httpSender
+}
+
+// InjectHTTPProxy adds the configured proxy and CA settings to cloud-init bootstrap data when enabled, so that the
+// nodes trust the CA certificates and the container runtime and kubelet send their requests through the proxy.
+// Bootstrap data in other formats is returned unchanged.
+func InjectHTTPProxy(bootstrapData []byte) ([]byte, error) {
+	return httpProxyConfig.injectInto(bootstrapData)
+}
+
+func (c HTTPProxyConfig) injectInto(bootstrapData []byte) ([]byte, error) {
+	hasProxy := c.HTTPProxy != "" || c.HTTPSProxy != ""
+	if !c.Bootstrap || (!hasProxy && len(c.CABundle) == 0) {
+		return bootstrapData, nil
+	}
+
+	header, body := splitCloudConfigHeader(bootstrapData)
+	if header == "" {
+		return bootstrapData, nil
+	}
+
+	cloudConfig := map[string]interface{}{}
+	if err := yaml.Unmarshal(body, &cloudConfig); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the cloud-config bootstrap data")
+	}
+
+	if len(c.CABundle) > 0 {
+		caCerts, _ := cloudConfig["ca_certs"].(map[string]interface{})
+		if caCerts == nil {
+			caCerts = map[string]interface{}{}
+		}
+		trusted, _ := caCerts["trusted"].([]interface{})
+		caCerts["trusted"] = append(trusted, string(c.CABundle))
+		cloudConfig["ca_certs"] = caCerts
+	}
+
+	if hasProxy {
+		files, _ := cloudConfig["write_files"].([]interface{})
+		for _, path := range proxyDropInPaths {
+			files = append(files, map[string]interface{}{
+				"path":        path,
+				"owner":       "root:root",
+				"permissions": "0644",
+				"content":     c.systemdDropIn(),
+			})
+		}
+		cloudConfig["write_files"] = files
+
+		// containerd is already running when the files are written, it needs to be restarted before images are pulled.
+		runcmd, _ := cloudConfig["runcmd"].([]interface{})
+		cloudConfig["runcmd"] = append([]interface{}{"systemctl daemon-reload", "systemctl restart containerd"}, runcmd...)
+	}
+
+	data, err := yaml.Marshal(cloudConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the cloud-config bootstrap data")
+	}
+	return append([]byte(header), data...), nil
+}
+
+// systemdDropIn returns the systemd drop-in setting the proxy environment variables of a service.
+func (c HTTPProxyConfig) systemdDropIn() string {
+	var b strings.Builder
+	b.WriteString("[Service]\n")
+	for _, env := range []struct{ name, value string }{
+		{"HTTP_PROXY", c.HTTPProxy},
+		{"HTTPS_PROXY", c.HTTPSProxy},
+		{"NO_PROXY", c.NoProxy},
+	} {
+		if env.value != "" {
+			fmt.Fprintf(&b, "Environment=\"%s=%s\"\n", env.name, env.value)
+		}
+	}
+	return b.String()
+}
+
+// splitCloudConfigHeader splits cloud-init bootstrap data into its leading comment lines, which must include the
+// #cloud-config one, and the cloud-config document. The header is empty when the data isn't a cloud-config.
+func splitCloudConfigHeader(data []byte) (string, []byte) {
+	var (
+		end           int
+		isCloudConfig bool
+	)
+	for end < len(data) && data[end] == '#' {
+		lineEnd := bytes.IndexByte(data[end:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(data) - end - 1
+		}
+		if strings.TrimSpace(string(data[end:end+lineEnd+1])) == "#cloud-config" {
+			isCloudConfig = true
+		}
+		end += lineEnd + 1
+	}
+	if !isCloudConfig {
+		return "", data
+	}
+	return string(data[:end]), data[end:]
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+)
+
+func TestSetHTTPProxy(t *testing.T) {
+	g := NewWithT(t)
+	defer func() {
+		g.Expect(SetHTTPProxy(HTTPProxyConfig{})).To(Succeed())
+	}()
+
+	g.Expect(SetHTTPProxy(HTTPProxyConfig{})).To(Succeed())
+	g.Expect(HTTPSender()).To(BeNil())
+
+	g.Expect(SetHTTPProxy(HTTPProxyConfig{HTTPSProxy: "proxy.example.com"})).To(HaveOccurred())
+	g.Expect(SetHTTPProxy(HTTPProxyConfig{CABundle: []byte("not a certificate")})).To(HaveOccurred())
+
+	// The certificate of the server is only trusted when it is in the CA bundle.
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	g.Expect(SetHTTPProxy(HTTPProxyConfig{CABundle: caBundle})).To(Succeed())
+	g.Expect(HTTPSender()).NotTo(BeNil())
+	resp, err := HTTPSender().Get(server.URL)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resp.Body.Close()).To(Succeed())
+
+	// The requests are sent through the proxy.
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+	}))
+	defer proxy.Close()
+
+	g.Expect(SetHTTPProxy(HTTPProxyConfig{HTTPSProxy: proxy.URL})).To(Succeed())
+	resp, err = HTTPSender().Get("http://management.azure.invalid/subscriptions")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resp.Body.Close()).To(Succeed())
+	g.Expect(proxied).To(Equal([]string{"http://management.azure.invalid/subscriptions"}))
+}
+
+func TestInjectHTTPProxy(t *testing.T) {
+	bootstrapData := `## template: jinja
+#cloud-config
+
+write_files:
+-   path: /etc/kubernetes/azure.json
+    content: |
+      {}
+runcmd:
+  - 'kubeadm init --config /run/kubeadm/kubeadm.yaml'
+`
+
+	tests := []struct {
+		name          string
+		config        HTTPProxyConfig
+		bootstrapData string
+		expectedData  string
+	}{
+		{
+			name:          "bootstrap data is unchanged when not enabled",
+			config:        HTTPProxyConfig{HTTPSProxy: "http://proxy.example.com:3128"},
+			bootstrapData: bootstrapData,
+			expectedData:  bootstrapData,
+		},
+		{
+			name:          "bootstrap data which isn't a cloud-config is unchanged",
+			config:        HTTPProxyConfig{HTTPSProxy: "http://proxy.example.com:3128", Bootstrap: true},
+			bootstrapData: `{"ignition": {"version": "3.1.0"}}`,
+			expectedData:  `{"ignition": {"version": "3.1.0"}}`,
+		},
+		{
+			name: "proxy and CA settings are added to cloud-config",
+			config: HTTPProxyConfig{
+				HTTPProxy:  "http://proxy.example.com:3128",
+				HTTPSProxy: "http://proxy.example.com:3128",
+				NoProxy:    "localhost,127.0.0.1,169.254.169.254,10.0.0.0/8",
+				CABundle:   []byte("-----BEGIN CERTIFICATE-----\n...\n-----END CERTIFICATE-----\n"),
+				Bootstrap:  true,
+			},
+			bootstrapData: bootstrapData,
+			expectedData: `## template: jinja
+#cloud-config
+ca_certs:
+  trusted:
+  - |
+    -----BEGIN CERTIFICATE-----
+    ...
+    -----END CERTIFICATE-----
+runcmd:
+- systemctl daemon-reload
+- systemctl restart containerd
+- kubeadm init --config /run/kubeadm/kubeadm.yaml
+write_files:
+- content: |
+    {}
+  path: /etc/kubernetes/azure.json
+- content: |
+    [Service]
+    Environment="HTTP_PROXY=http://proxy.example.com:3128"
+    Environment="HTTPS_PROXY=http://proxy.example.com:3128"
+    Environment="NO_PROXY=localhost,127.0.0.1,169.254.169.254,10.0.0.0/8"
+  owner: root:root
+  path: /etc/systemd/system/containerd.service.d/http-proxy.conf
+  permissions: "0644"
+- content: |
+    [Service]
+    Environment="HTTP_PROXY=http://proxy.example.com:3128"
+    Environment="HTTPS_PROXY=http://proxy.example.com:3128"
+    Environment="NO_PROXY=localhost,127.0.0.1,169.254.169.254,10.0.0.0/8"
+  owner: root:root
+  path: /etc/systemd/system/kubelet.service.d/http-proxy.conf
+  permissions: "0644"
+`,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			data, err := tc.config.injectInto([]byte(tc.bootstrapData))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(data)).To(Equal(tc.expectedData))
+		})
+	}
+
+	t.Run("invalid cloud-config", func(t *testing.T) {
+		g := NewWithT(t)
+		config := HTTPProxyConfig{HTTPSProxy: "http://proxy.example.com:3128", Bootstrap: true}
+		_, err := config.injectInto([]byte("#cloud-config\nruncmd: [\n"))
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	// The jinja expressions of the bootstrap data are kept as is.
+	t.Run("jinja expressions", func(t *testing.T) {
+		g := NewWithT(t)
+		config := HTTPProxyConfig{HTTPSProxy: "http://proxy.example.com:3128", Bootstrap: true}
+		data, err := config.injectInto([]byte("## template: jinja\n#cloud-config\nhostname: '{{ ds.meta_data[\"local_hostname\"] }}'\n"))
+		g.Expect(err).NotTo(HaveOccurred())
+		_, body := splitCloudConfigHeader(data)
+		cloudConfig := map[string]interface{}{}
+		g.Expect(yaml.Unmarshal(body, &cloudConfig)).To(Succeed())
+		g.Expect(cloudConfig["hostname"]).To(Equal(`{{ ds.meta_data["local_hostname"] }}`))
+	})
+}
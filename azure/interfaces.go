@@ -50,6 +50,14 @@ type Authorizer interface {
 	HashKey() string
 }
 
+// KeyVaultDescriber is an interface which can get the endpoints of the Azure Key Vaults of the cloud environment, and
+// an authorizer for the requests to their data plane.
+type KeyVaultDescriber interface {
+	KeyVaultDNSSuffix() string
+	KeyVaultResource() string
+	KeyVaultAuthorizer(ctx context.Context) (autorest.Authorizer, error)
+}
+
 // NetworkDescriber is an interface which can get common Azure Cluster Networking information.
 type NetworkDescriber interface {
 	Vnet() *infrav1.VnetSpec
@@ -67,12 +75,14 @@ type NetworkDescriber interface {
 	GetPrivateDNSZoneName() string
 	OutboundLBName(string) string
 	OutboundPoolName(string) string
+	NodeOutboundLB() *infrav1.LoadBalancerSpec
 }
 
 // ClusterDescriber is an interface which can get common Azure Cluster information.
 type ClusterDescriber interface {
 	Authorizer
 	ResourceGroup() string
+	NetworkResourceGroup() string
 	ClusterName() string
 	Location() string
 	AdditionalTags() infrav1.Tags
@@ -80,8 +90,28 @@ type ClusterDescriber interface {
 	CloudProviderConfigOverrides() *infrav1.CloudProviderConfigOverrides
 }
 
+// AsyncStatusUpdater is an interface used to keep track of the Azure long-running operations of a service in the
+// status of the object being reconciled, so that they can be checked on by the next reconciliation loops.
+type AsyncStatusUpdater interface {
+	SetLongRunningOperationState(*infrav1.Future)
+	GetLongRunningOperationState(name, service string) *infrav1.Future
+	DeleteLongRunningOperationState(name, service string)
+}
+
 // ClusterScoper combines the ClusterDescriber and NetworkDescriber interfaces.
 type ClusterScoper interface {
 	ClusterDescriber
 	NetworkDescriber
 }
+
+// ResourceSpecGetter is an interface for getting all the required information to create, update or delete an Azure
+// resource, so that services can rely on a common implementation of the create, update and delete flows.
+type ResourceSpecGetter interface {
+	// ResourceName returns the name of the resource.
+	ResourceName() string
+	// ResourceGroupName returns the name of the resource group the resource is in.
+	ResourceGroupName() string
+	// Parameters takes the existing resource, or nil if it doesn't exist yet, and returns the parameters of the request
+	// to create or update it. It returns nil if the existing resource is up to date.
+	Parameters(existing interface{}) (interface{}, error)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "capz"
+	metricsSubsystem = "azure_api"
+)
+
+var (
+	apiRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "requests_total",
+		Help:      "Number of requests sent to the Azure API, by resource type, HTTP method and response status code.",
+	}, []string{"resource_type", "method", "code"})
+
+	apiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the requests sent to the Azure API, by resource type and HTTP method.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"resource_type", "method"})
+
+	apiThrottledTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "throttled_requests_total",
+		Help:      "Number of requests throttled by the Azure API with a 429 Too Many Requests response, by resource type and HTTP method.",
+	}, []string{"resource_type", "method"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(apiRequestsTotal, apiRequestDuration, apiThrottledTotal)
+}
+
+// WithMetrics returns a SendDecorator which records the count, status code and latency of the requests sent to the
+// Azure API in the controller Prometheus metrics.
+func WithMetrics() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+			resourceType := resourceTypeFromPath(req.URL.Path)
+			start := time.Now()
+			resp, err := s.Do(req)
+			apiRequestDuration.WithLabelValues(resourceType, req.Method).Observe(time.Since(start).Seconds())
+
+			code := "error"
+			if resp != nil {
+				code = strconv.Itoa(resp.StatusCode)
+				if resp.StatusCode == http.StatusTooManyRequests {
+					apiThrottledTotal.WithLabelValues(resourceType, req.Method).Inc()
+				}
+			}
+			apiRequestsTotal.WithLabelValues(resourceType, req.Method, code).Inc()
+			return resp, err
+		})
+	}
+}
+
+// resourceTypeFromPath returns the resource type an Azure Resource Manager request path refers to, e.g.
+// Microsoft.Network/virtualNetworks/subnets, without the names of the resources so that it can be used as a metric
+// label. Paths without a resource provider, like the ones of resource groups, return the types of their scopes.
+func resourceTypeFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	start := 0
+	types := []string{}
+	for i := len(segments) - 2; i >= 0; i-- {
+		if strings.EqualFold(segments[i], "providers") {
+			// the resource provider namespace is followed by pairs of resource types and names
+			types = append(types, segments[i+1])
+			start = i + 2
+			break
+		}
+	}
+	for i := start; i < len(segments); i += 2 {
+		types = append(types, segments[i])
+	}
+	return strings.Join(types, "/")
+}
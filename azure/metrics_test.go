@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestResourceTypeFromPath(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{
+			path: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet",
+			want: "Microsoft.Network/virtualNetworks/subnets",
+		},
+		{
+			path: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/my-vmss/virtualMachines",
+			want: "Microsoft.Compute/virtualMachineScaleSets/virtualMachines",
+		},
+		{
+			path: "/subscriptions/123/providers/Microsoft.Compute/skus",
+			want: "Microsoft.Compute/skus",
+		},
+		{
+			path: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm/providers/Microsoft.Authorization/roleAssignments/my-role",
+			want: "Microsoft.Authorization/roleAssignments",
+		},
+		{
+			path: "/subscriptions/123/resourcegroups/my-rg",
+			want: "subscriptions/resourcegroups",
+		},
+	}
+	for _, tt := range tests {
+		g.Expect(resourceTypeFromPath(tt.path)).To(Equal(tt.want), tt.path)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	g := NewWithT(t)
+
+	statusCode := http.StatusOK
+	var sendErr error
+	sender := WithMetrics()(autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+		if sendErr != nil {
+			return nil, sendErr
+		}
+		return &http.Response{StatusCode: statusCode, Request: req}, nil
+	}))
+	req := &http.Request{Method: http.MethodPut, URL: &url.URL{Path: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/metricsTest/my-test"}}
+	resourceType := "Microsoft.Network/metricsTest"
+
+	_, err := sender.Do(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(testutil.ToFloat64(apiRequestsTotal.WithLabelValues(resourceType, http.MethodPut, "200"))).To(Equal(float64(1)))
+
+	statusCode = http.StatusTooManyRequests
+	_, err = sender.Do(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(testutil.ToFloat64(apiRequestsTotal.WithLabelValues(resourceType, http.MethodPut, "429"))).To(Equal(float64(1)))
+	g.Expect(testutil.ToFloat64(apiThrottledTotal.WithLabelValues(resourceType, http.MethodPut))).To(Equal(float64(1)))
+
+	sendErr = errors.New("connection reset")
+	_, err = sender.Do(req)
+	g.Expect(err).To(MatchError("connection reset"))
+	g.Expect(testutil.ToFloat64(apiRequestsTotal.WithLabelValues(resourceType, http.MethodPut, "error"))).To(Equal(float64(1)))
+
+	g.Expect(testutil.CollectAndCount(apiRequestDuration, "capz_azure_api_request_duration_seconds")).To(BeNumerically(">=", 1))
+}
@@ -281,6 +281,72 @@ func (mr *MockAuthorizerMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockAuthorizer)(nil).TenantID))
 }
 
+// MockKeyVaultDescriber is a mock of KeyVaultDescriber interface.
+type MockKeyVaultDescriber struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyVaultDescriberMockRecorder
+}
+
+// MockKeyVaultDescriberMockRecorder is the mock recorder for MockKeyVaultDescriber.
+type MockKeyVaultDescriberMockRecorder struct {
+	mock *MockKeyVaultDescriber
+}
+
+// NewMockKeyVaultDescriber creates a new mock instance.
+func NewMockKeyVaultDescriber(ctrl *gomock.Controller) *MockKeyVaultDescriber {
+	mock := &MockKeyVaultDescriber{ctrl: ctrl}
+	mock.recorder = &MockKeyVaultDescriberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyVaultDescriber) EXPECT() *MockKeyVaultDescriberMockRecorder {
+	return m.recorder
+}
+
+// KeyVaultAuthorizer mocks base method.
+func (m *MockKeyVaultDescriber) KeyVaultAuthorizer(ctx context.Context) (autorest.Authorizer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeyVaultAuthorizer", ctx)
+	ret0, _ := ret[0].(autorest.Authorizer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// KeyVaultAuthorizer indicates an expected call of KeyVaultAuthorizer.
+func (mr *MockKeyVaultDescriberMockRecorder) KeyVaultAuthorizer(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeyVaultAuthorizer", reflect.TypeOf((*MockKeyVaultDescriber)(nil).KeyVaultAuthorizer), ctx)
+}
+
+// KeyVaultDNSSuffix mocks base method.
+func (m *MockKeyVaultDescriber) KeyVaultDNSSuffix() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeyVaultDNSSuffix")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// KeyVaultDNSSuffix indicates an expected call of KeyVaultDNSSuffix.
+func (mr *MockKeyVaultDescriberMockRecorder) KeyVaultDNSSuffix() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeyVaultDNSSuffix", reflect.TypeOf((*MockKeyVaultDescriber)(nil).KeyVaultDNSSuffix))
+}
+
+// KeyVaultResource mocks base method.
+func (m *MockKeyVaultDescriber) KeyVaultResource() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeyVaultResource")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// KeyVaultResource indicates an expected call of KeyVaultResource.
+func (mr *MockKeyVaultDescriberMockRecorder) KeyVaultResource() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeyVaultResource", reflect.TypeOf((*MockKeyVaultDescriber)(nil).KeyVaultResource))
+}
+
 // MockNetworkDescriber is a mock of NetworkDescriber interface.
 type MockNetworkDescriber struct {
 	ctrl     *gomock.Controller
@@ -416,6 +482,20 @@ func (mr *MockNetworkDescriberMockRecorder) IsVnetManaged() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsVnetManaged", reflect.TypeOf((*MockNetworkDescriber)(nil).IsVnetManaged))
 }
 
+// NodeOutboundLB mocks base method.
+func (m *MockNetworkDescriber) NodeOutboundLB() *v1alpha4.LoadBalancerSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeOutboundLB")
+	ret0, _ := ret[0].(*v1alpha4.LoadBalancerSpec)
+	return ret0
+}
+
+// NodeOutboundLB indicates an expected call of NodeOutboundLB.
+func (mr *MockNetworkDescriberMockRecorder) NodeOutboundLB() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeOutboundLB", reflect.TypeOf((*MockNetworkDescriber)(nil).NodeOutboundLB))
+}
+
 // NodeSubnets mocks base method.
 func (m *MockNetworkDescriber) NodeSubnets() []v1alpha4.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -689,6 +769,20 @@ func (mr *MockClusterDescriberMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockClusterDescriber)(nil).Location))
 }
 
+// NetworkResourceGroup mocks base method.
+func (m *MockClusterDescriber) NetworkResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NetworkResourceGroup indicates an expected call of NetworkResourceGroup.
+func (mr *MockClusterDescriberMockRecorder) NetworkResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkResourceGroup", reflect.TypeOf((*MockClusterDescriber)(nil).NetworkResourceGroup))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockClusterDescriber) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -731,6 +825,67 @@ func (mr *MockClusterDescriberMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockClusterDescriber)(nil).TenantID))
 }
 
+// MockAsyncStatusUpdater is a mock of AsyncStatusUpdater interface.
+type MockAsyncStatusUpdater struct {
+	ctrl     *gomock.Controller
+	recorder *MockAsyncStatusUpdaterMockRecorder
+}
+
+// MockAsyncStatusUpdaterMockRecorder is the mock recorder for MockAsyncStatusUpdater.
+type MockAsyncStatusUpdaterMockRecorder struct {
+	mock *MockAsyncStatusUpdater
+}
+
+// NewMockAsyncStatusUpdater creates a new mock instance.
+func NewMockAsyncStatusUpdater(ctrl *gomock.Controller) *MockAsyncStatusUpdater {
+	mock := &MockAsyncStatusUpdater{ctrl: ctrl}
+	mock.recorder = &MockAsyncStatusUpdaterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAsyncStatusUpdater) EXPECT() *MockAsyncStatusUpdaterMockRecorder {
+	return m.recorder
+}
+
+// DeleteLongRunningOperationState mocks base method.
+func (m *MockAsyncStatusUpdater) DeleteLongRunningOperationState(name, service string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteLongRunningOperationState", name, service)
+}
+
+// DeleteLongRunningOperationState indicates an expected call of DeleteLongRunningOperationState.
+func (mr *MockAsyncStatusUpdaterMockRecorder) DeleteLongRunningOperationState(name, service interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockAsyncStatusUpdater)(nil).DeleteLongRunningOperationState), name, service)
+}
+
+// GetLongRunningOperationState mocks base method.
+func (m *MockAsyncStatusUpdater) GetLongRunningOperationState(name, service string) *v1alpha4.Future {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLongRunningOperationState", name, service)
+	ret0, _ := ret[0].(*v1alpha4.Future)
+	return ret0
+}
+
+// GetLongRunningOperationState indicates an expected call of GetLongRunningOperationState.
+func (mr *MockAsyncStatusUpdaterMockRecorder) GetLongRunningOperationState(name, service interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongRunningOperationState", reflect.TypeOf((*MockAsyncStatusUpdater)(nil).GetLongRunningOperationState), name, service)
+}
+
+// SetLongRunningOperationState mocks base method.
+func (m *MockAsyncStatusUpdater) SetLongRunningOperationState(arg0 *v1alpha4.Future) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLongRunningOperationState", arg0)
+}
+
+// SetLongRunningOperationState indicates an expected call of SetLongRunningOperationState.
+func (mr *MockAsyncStatusUpdaterMockRecorder) SetLongRunningOperationState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockAsyncStatusUpdater)(nil).SetLongRunningOperationState), arg0)
+}
+
 // MockClusterScoper is a mock of ClusterScoper interface.
 type MockClusterScoper struct {
 	ctrl     *gomock.Controller
@@ -1020,6 +1175,34 @@ func (mr *MockClusterScoperMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockClusterScoper)(nil).Location))
 }
 
+// NetworkResourceGroup mocks base method.
+func (m *MockClusterScoper) NetworkResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NetworkResourceGroup indicates an expected call of NetworkResourceGroup.
+func (mr *MockClusterScoperMockRecorder) NetworkResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkResourceGroup", reflect.TypeOf((*MockClusterScoper)(nil).NetworkResourceGroup))
+}
+
+// NodeOutboundLB mocks base method.
+func (m *MockClusterScoper) NodeOutboundLB() *v1alpha4.LoadBalancerSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeOutboundLB")
+	ret0, _ := ret[0].(*v1alpha4.LoadBalancerSpec)
+	return ret0
+}
+
+// NodeOutboundLB indicates an expected call of NodeOutboundLB.
+func (mr *MockClusterScoperMockRecorder) NodeOutboundLB() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeOutboundLB", reflect.TypeOf((*MockClusterScoper)(nil).NodeOutboundLB))
+}
+
 // NodeSubnets mocks base method.
 func (m *MockClusterScoper) NodeSubnets() []v1alpha4.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -1157,3 +1340,69 @@ func (mr *MockClusterScoperMockRecorder) Vnet() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vnet", reflect.TypeOf((*MockClusterScoper)(nil).Vnet))
 }
+
+// MockResourceSpecGetter is a mock of ResourceSpecGetter interface.
+type MockResourceSpecGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockResourceSpecGetterMockRecorder
+}
+
+// MockResourceSpecGetterMockRecorder is the mock recorder for MockResourceSpecGetter.
+type MockResourceSpecGetterMockRecorder struct {
+	mock *MockResourceSpecGetter
+}
+
+// NewMockResourceSpecGetter creates a new mock instance.
+func NewMockResourceSpecGetter(ctrl *gomock.Controller) *MockResourceSpecGetter {
+	mock := &MockResourceSpecGetter{ctrl: ctrl}
+	mock.recorder = &MockResourceSpecGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResourceSpecGetter) EXPECT() *MockResourceSpecGetterMockRecorder {
+	return m.recorder
+}
+
+// Parameters mocks base method.
+func (m *MockResourceSpecGetter) Parameters(existing interface{}) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Parameters", existing)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Parameters indicates an expected call of Parameters.
+func (mr *MockResourceSpecGetterMockRecorder) Parameters(existing interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Parameters", reflect.TypeOf((*MockResourceSpecGetter)(nil).Parameters), existing)
+}
+
+// ResourceGroupName mocks base method.
+func (m *MockResourceSpecGetter) ResourceGroupName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceGroupName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ResourceGroupName indicates an expected call of ResourceGroupName.
+func (mr *MockResourceSpecGetterMockRecorder) ResourceGroupName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroupName", reflect.TypeOf((*MockResourceSpecGetter)(nil).ResourceGroupName))
+}
+
+// ResourceName mocks base method.
+func (m *MockResourceSpecGetter) ResourceName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ResourceName indicates an expected call of ResourceName.
+func (mr *MockResourceSpecGetterMockRecorder) ResourceName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceName", reflect.TypeOf((*MockResourceSpecGetter)(nil).ResourceName))
+}
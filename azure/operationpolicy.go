@@ -0,0 +1,256 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+)
+
+// OperationType is the type of an Azure API operation, which selects the OperationPolicy applied to its requests.
+type OperationType string
+
+const (
+	// OperationRead is the type of the operations reading Azure resources, including the polling of long-running
+	// operations.
+	OperationRead OperationType = "read"
+	// OperationWrite is the type of the operations creating or updating Azure resources.
+	OperationWrite OperationType = "write"
+	// OperationDelete is the type of the operations deleting Azure resources.
+	OperationDelete OperationType = "delete"
+)
+
+// DefaultPollingDelay is how long to wait before checking again on a long-running operation which is not done yet.
+const DefaultPollingDelay = 15 * time.Second
+
+// OperationTypes are all the operation types, in the order they are documented.
+var OperationTypes = []OperationType{OperationRead, OperationWrite, OperationDelete}
+
+// OperationPolicy tunes how the requests of an operation type are sent to the Azure API. The zero value of each field
+// keeps the default behavior.
+type OperationPolicy struct {
+	// PollingDelay is how long to wait before checking again on a long-running operation of this type which is not
+	// done yet, when Azure doesn't tell when to retry.
+	PollingDelay time.Duration
+	// RetryAttempts is how many times a request which failed with a transient error is retried. Negative disables
+	// retries.
+	RetryAttempts int
+	// Timeout bounds the time a request takes, including its retries.
+	Timeout time.Duration
+}
+
+var (
+	operationPoliciesMu sync.RWMutex
+	operationPolicies   = map[OperationType]OperationPolicy{}
+
+	// retryBackoff is the delay before the first retry of a request, doubled for each further retry, unless Azure tells
+	// when to retry.
+	retryBackoff = autorest.DefaultRetryDuration
+)
+
+// SetOperationPolicies configures the policies of the operation types. The operation types without a policy keep the
+// default behavior. It is meant to be called once at startup, before any Azure client is created.
+func SetOperationPolicies(policies map[OperationType]OperationPolicy) {
+	operationPoliciesMu.Lock()
+	defer operationPoliciesMu.Unlock()
+
+	operationPolicies = map[OperationType]OperationPolicy{}
+	for t, p := range policies {
+		operationPolicies[t] = p
+	}
+}
+
+// GetOperationPolicy returns the policy of an operation type.
+func GetOperationPolicy(t OperationType) OperationPolicy {
+	operationPoliciesMu.RLock()
+	defer operationPoliciesMu.RUnlock()
+
+	return operationPolicies[t]
+}
+
+// PollingDelay returns how long to wait before checking again on a long-running operation of a type which is not done
+// yet.
+func PollingDelay(t OperationType) time.Duration {
+	if delay := GetOperationPolicy(t).PollingDelay; delay > 0 {
+		return delay
+	}
+	return DefaultPollingDelay
+}
+
+// hasRetryPolicy returns true if the retries of an operation type are configured, in which case the requests are
+// retried by WithOperationPolicy rather than by the Azure SDK clients.
+func hasRetryPolicy() bool {
+	operationPoliciesMu.RLock()
+	defer operationPoliciesMu.RUnlock()
+
+	for _, p := range operationPolicies {
+		if p.RetryAttempts != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// clientPollingDelay returns how long the Azure SDK clients waiting for long-running operations in-process wait between
+// polls, which is the shortest polling delay configured for writes and deletes, or zero if none is configured.
+func clientPollingDelay() time.Duration {
+	var delay time.Duration
+	for _, t := range []OperationType{OperationWrite, OperationDelete} {
+		if d := GetOperationPolicy(t).PollingDelay; d > 0 && (delay == 0 || d < delay) {
+			delay = d
+		}
+	}
+	return delay
+}
+
+// OperationTypeForMethod returns the operation type of a request from its HTTP method.
+func OperationTypeForMethod(method string) OperationType {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return OperationRead
+	case http.MethodDelete:
+		return OperationDelete
+	default:
+		return OperationWrite
+	}
+}
+
+// ParseOperationPolicies builds the policies of the operation types from flag values mapping operation types to
+// polling delays, retry attempts and timeouts, e.g. {"write": "30s", "delete": "1m"} for the polling delays.
+func ParseOperationPolicies(pollingDelays, retryAttempts, timeouts map[string]string) (map[OperationType]OperationPolicy, error) {
+	policies := map[OperationType]OperationPolicy{}
+	update := func(key string, f func(*OperationPolicy)) error {
+		t := OperationType(strings.ToLower(strings.TrimSpace(key)))
+		if !isOperationType(t) {
+			return errors.Errorf("unknown operation type %q, must be one of %s", key, operationTypeNames())
+		}
+		p := policies[t]
+		f(&p)
+		policies[t] = p
+		return nil
+	}
+
+	for key, value := range pollingDelays {
+		delay, err := time.ParseDuration(value)
+		if err != nil || delay <= 0 {
+			return nil, errors.Errorf("invalid polling delay %q for operation type %q, must be a positive duration", value, key)
+		}
+		if err := update(key, func(p *OperationPolicy) { p.PollingDelay = delay }); err != nil {
+			return nil, err
+		}
+	}
+	for key, value := range retryAttempts {
+		attempts, err := strconv.Atoi(value)
+		if err != nil || attempts < 0 {
+			return nil, errors.Errorf("invalid retry attempts %q for operation type %q, must be a non-negative integer", value, key)
+		}
+		if attempts == 0 {
+			// Zero keeps the default behavior in an OperationPolicy.
+			attempts = -1
+		}
+		if err := update(key, func(p *OperationPolicy) { p.RetryAttempts = attempts }); err != nil {
+			return nil, err
+		}
+	}
+	for key, value := range timeouts {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return nil, errors.Errorf("invalid timeout %q for operation type %q, must be a positive duration", value, key)
+		}
+		if err := update(key, func(p *OperationPolicy) { p.Timeout = timeout }); err != nil {
+			return nil, err
+		}
+	}
+	return policies, nil
+}
+
+func isOperationType(t OperationType) bool {
+	for _, known := range OperationTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+func operationTypeNames() string {
+	names := make([]string, 0, len(OperationTypes))
+	for _, t := range OperationTypes {
+		names = append(names, string(t))
+	}
+	return strings.Join(names, ", ")
+}
+
+// WithOperationPolicy returns a SendDecorator which applies the timeout and the retries of the policy of the operation
+// type of each request.
+func WithOperationPolicy() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+			policy := GetOperationPolicy(OperationTypeForMethod(req.Method))
+
+			var cancel context.CancelFunc
+			if policy.Timeout > 0 {
+				var ctx context.Context
+				ctx, cancel = context.WithTimeout(req.Context(), policy.Timeout)
+				req = req.WithContext(ctx)
+			}
+
+			sender := s
+			if hasRetryPolicy() {
+				// The Azure SDK clients send each request once when a retry policy is configured, see
+				// SetAutoRestClientDefaults. Their attempts include the first request.
+				attempts := autorest.DefaultRetryAttempts
+				if policy.RetryAttempts != 0 {
+					attempts = policy.RetryAttempts + 1
+				}
+				if attempts > 1 {
+					sender = autorest.DecorateSender(s, autorest.DoRetryForStatusCodes(attempts, retryBackoff, autorest.StatusCodesForRetry...))
+				}
+			}
+
+			resp, err := sender.Do(req)
+			if cancel != nil {
+				if resp == nil || resp.Body == nil {
+					cancel()
+				} else {
+					// The body is read after the request returns, so the context is only canceled when it's closed.
+					resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+				}
+			}
+			return resp, err
+		})
+	}
+}
+
+// cancelOnCloseBody is a response body which cancels the context of its request when it is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+)
+
+func TestParseOperationPolicies(t *testing.T) {
+	tests := []struct {
+		name          string
+		pollingDelays map[string]string
+		retryAttempts map[string]string
+		timeouts      map[string]string
+		want          map[OperationType]OperationPolicy
+		wantErr       string
+	}{
+		{
+			name: "no flags",
+			want: map[OperationType]OperationPolicy{},
+		},
+		{
+			name:          "policies by operation type",
+			pollingDelays: map[string]string{"write": "30s", "Delete": "1m"},
+			retryAttempts: map[string]string{"read": "5", "write": "0"},
+			timeouts:      map[string]string{"read": "20s"},
+			want: map[OperationType]OperationPolicy{
+				OperationRead:   {RetryAttempts: 5, Timeout: 20 * time.Second},
+				OperationWrite:  {PollingDelay: 30 * time.Second, RetryAttempts: -1},
+				OperationDelete: {PollingDelay: time.Minute},
+			},
+		},
+		{
+			name:          "unknown operation type",
+			pollingDelays: map[string]string{"patch": "30s"},
+			wantErr:       `unknown operation type "patch", must be one of read, write, delete`,
+		},
+		{
+			name:          "invalid polling delay",
+			pollingDelays: map[string]string{"write": "0s"},
+			wantErr:       `invalid polling delay "0s" for operation type "write", must be a positive duration`,
+		},
+		{
+			name:          "invalid retry attempts",
+			retryAttempts: map[string]string{"read": "-1"},
+			wantErr:       `invalid retry attempts "-1" for operation type "read", must be a non-negative integer`,
+		},
+		{
+			name:     "invalid timeout",
+			timeouts: map[string]string{"delete": "forever"},
+			wantErr:  `invalid timeout "forever" for operation type "delete", must be a positive duration`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := ParseOperationPolicies(tt.pollingDelays, tt.retryAttempts, tt.timeouts)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestOperationTypeForMethod(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(OperationTypeForMethod(http.MethodGet)).To(Equal(OperationRead))
+	g.Expect(OperationTypeForMethod(http.MethodHead)).To(Equal(OperationRead))
+	g.Expect(OperationTypeForMethod(http.MethodPut)).To(Equal(OperationWrite))
+	g.Expect(OperationTypeForMethod(http.MethodPatch)).To(Equal(OperationWrite))
+	g.Expect(OperationTypeForMethod(http.MethodPost)).To(Equal(OperationWrite))
+	g.Expect(OperationTypeForMethod(http.MethodDelete)).To(Equal(OperationDelete))
+}
+
+func TestPollingDelay(t *testing.T) {
+	g := NewWithT(t)
+
+	SetOperationPolicies(map[OperationType]OperationPolicy{
+		OperationWrite:  {PollingDelay: time.Minute},
+		OperationDelete: {PollingDelay: 30 * time.Second},
+	})
+	defer SetOperationPolicies(nil)
+
+	g.Expect(PollingDelay(OperationWrite)).To(Equal(time.Minute))
+	g.Expect(PollingDelay(OperationRead)).To(Equal(DefaultPollingDelay))
+	g.Expect(clientPollingDelay()).To(Equal(30 * time.Second))
+}
+
+func TestWithOperationPolicyRetries(t *testing.T) {
+	g := NewWithT(t)
+
+	SetOperationPolicies(map[OperationType]OperationPolicy{
+		OperationRead:  {RetryAttempts: 2},
+		OperationWrite: {RetryAttempts: -1},
+	})
+	defer SetOperationPolicies(nil)
+	defer func(backoff time.Duration) { retryBackoff = backoff }(retryBackoff)
+	retryBackoff = time.Millisecond
+
+	var sent int
+	sender := WithOperationPolicy()(autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+		sent++
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: ioutil.NopCloser(strings.NewReader("")), Request: req}, nil
+	}))
+
+	get, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, "https://management.azure.com/subscriptions/123", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	resp, err := sender.Do(get)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+	g.Expect(sent).To(Equal(3))
+
+	sent = 0
+	put, err := http.NewRequestWithContext(context.TODO(), http.MethodPut, "https://management.azure.com/subscriptions/123", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = sender.Do(put)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sent).To(Equal(1))
+}
+
+func TestWithOperationPolicyTimeout(t *testing.T) {
+	g := NewWithT(t)
+
+	SetOperationPolicies(map[OperationType]OperationPolicy{
+		OperationDelete: {Timeout: time.Minute},
+	})
+	defer SetOperationPolicies(nil)
+
+	var reqCtx context.Context
+	sender := WithOperationPolicy()(autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+		reqCtx = req.Context()
+		return &http.Response{StatusCode: http.StatusAccepted, Body: ioutil.NopCloser(strings.NewReader("")), Request: req}, nil
+	}))
+
+	del, err := http.NewRequestWithContext(context.TODO(), http.MethodDelete, "https://management.azure.com/subscriptions/123", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	resp, err := sender.Do(del)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	deadline, ok := reqCtx.Deadline()
+	g.Expect(ok).To(BeTrue())
+	g.Expect(time.Until(deadline)).To(BeNumerically("<=", time.Minute))
+
+	// The context outlives the request until the response body is closed.
+	g.Expect(reqCtx.Err()).NotTo(HaveOccurred())
+	g.Expect(resp.Body.Close()).To(Succeed())
+	g.Expect(reqCtx.Err()).To(MatchError(context.Canceled))
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// DefaultThrottleBackoff is how long requests to a subscription are held back after Azure throttled a request to it
+// without telling when to retry.
+const DefaultThrottleBackoff = 5 * time.Second
+
+var apiRateLimiters = newRateLimiters(0, 0)
+
+// SetAPIRateLimits configures the client-side rate limits applied to the Azure API requests of each subscription.
+// A qps of zero or less disables client-side rate limiting, while 429 responses are always honored. It is meant to be
+// called once at startup, before any Azure client is created.
+func SetAPIRateLimits(qps float32, burst int) {
+	apiRateLimiters = newRateLimiters(qps, burst)
+}
+
+// WithRateLimiting returns a SendDecorator which holds back requests to a subscription to honor the client-side rate
+// limits and the Retry-After header of the requests to the same subscription Azure throttled.
+func WithRateLimiting() autorest.SendDecorator {
+	return apiRateLimiters.decorator()
+}
+
+// rateLimiters holds the rate limiter of every subscription.
+type rateLimiters struct {
+	qps            float32
+	burst          int
+	mu             sync.Mutex
+	bySubscription map[string]*subscriptionRateLimiter
+}
+
+// subscriptionRateLimiter rate limits the requests to a subscription.
+type subscriptionRateLimiter struct {
+	// limiter is nil when client-side rate limiting is disabled.
+	limiter        flowcontrol.RateLimiter
+	mu             sync.Mutex
+	throttledUntil time.Time
+}
+
+func newRateLimiters(qps float32, burst int) *rateLimiters {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiters{
+		qps:            qps,
+		burst:          burst,
+		bySubscription: map[string]*subscriptionRateLimiter{},
+	}
+}
+
+func (r *rateLimiters) decorator() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+			limiter := r.forSubscription(subscriptionFromPath(req.URL.Path))
+			if err := limiter.wait(req.Context()); err != nil {
+				return nil, err
+			}
+			resp, err := s.Do(req)
+			if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+				limiter.throttle(retryAfter(resp))
+			}
+			return resp, err
+		})
+	}
+}
+
+// forSubscription returns the rate limiter of a subscription, creating it on first use.
+func (r *rateLimiters) forSubscription(subscriptionID string) *subscriptionRateLimiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	limiter, ok := r.bySubscription[subscriptionID]
+	if !ok {
+		limiter = &subscriptionRateLimiter{}
+		if r.qps > 0 {
+			limiter.limiter = flowcontrol.NewTokenBucketRateLimiter(r.qps, r.burst)
+		}
+		r.bySubscription[subscriptionID] = limiter
+	}
+	return limiter
+}
+
+// wait blocks until a request can be sent to the subscription, or the context is done.
+func (l *subscriptionRateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	throttledFor := time.Until(l.throttledUntil)
+	l.mu.Unlock()
+
+	if throttledFor > 0 {
+		timer := time.NewTimer(throttledFor)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if l.limiter != nil {
+		return l.limiter.Wait(ctx)
+	}
+	return nil
+}
+
+// throttle holds back the requests to the subscription for the given duration.
+func (l *subscriptionRateLimiter) throttle(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(l.throttledUntil) {
+		l.throttledUntil = until
+	}
+}
+
+// subscriptionFromPath returns the subscription ID of an Azure Resource Manager request path, or an empty string
+// for the requests which are not scoped to a subscription.
+func subscriptionFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if strings.EqualFold(segments[i], "subscriptions") {
+			return strings.ToLower(segments[i+1])
+		}
+	}
+	return ""
+}
+
+// retryAfter returns how long to wait before retrying a throttled request, according to its Retry-After header.
+func retryAfter(resp *http.Response) time.Duration {
+	header := resp.Header.Get("Retry-After")
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil && time.Until(date) > 0 {
+		return time.Until(date)
+	}
+	return DefaultThrottleBackoff
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+)
+
+func TestSubscriptionFromPath(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{
+			path: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+			want: "123",
+		},
+		{
+			path: "/Subscriptions/ABC/providers/Microsoft.Compute/skus",
+			want: "abc",
+		},
+		{
+			path: "/providers/Microsoft.Authorization/roleDefinitions",
+			want: "",
+		},
+		{
+			path: "/subscriptions",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		g.Expect(subscriptionFromPath(tt.path)).To(Equal(tt.want), tt.path)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name   string
+		header string
+		min    time.Duration
+		max    time.Duration
+	}{
+		{
+			name:   "delay in seconds",
+			header: "17",
+			min:    17 * time.Second,
+			max:    17 * time.Second,
+		},
+		{
+			name:   "http date",
+			header: time.Now().Add(time.Minute).UTC().Format(http.TimeFormat),
+			min:    58 * time.Second,
+			max:    time.Minute,
+		},
+		{
+			name:   "missing header",
+			header: "",
+			min:    DefaultThrottleBackoff,
+			max:    DefaultThrottleBackoff,
+		},
+		{
+			name:   "date in the past",
+			header: time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat),
+			min:    DefaultThrottleBackoff,
+			max:    DefaultThrottleBackoff,
+		},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{Header: http.Header{}}
+		if tt.header != "" {
+			resp.Header.Set("Retry-After", tt.header)
+		}
+		got := retryAfter(resp)
+		g.Expect(got).To(BeNumerically(">=", tt.min), tt.name)
+		g.Expect(got).To(BeNumerically("<=", tt.max), tt.name)
+	}
+}
+
+func TestRateLimitingHonorsThrottling(t *testing.T) {
+	g := NewWithT(t)
+
+	limiters := newRateLimiters(0, 0)
+	sender := limiters.decorator()(autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"1"}}, Request: req}, nil
+	}))
+
+	throttled := &http.Request{URL: &url.URL{Path: "/subscriptions/123/resourceGroups/my-rg"}}
+	resp, err := sender.Do(throttled.WithContext(context.TODO()))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resp.StatusCode).To(Equal(http.StatusTooManyRequests))
+
+	// Requests to the throttled subscription are held back until the Retry-After delay elapsed.
+	ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+	defer cancel()
+	g.Expect(limiters.forSubscription("123").wait(ctx)).To(MatchError(context.DeadlineExceeded))
+
+	// Requests to other subscriptions are not.
+	g.Expect(limiters.forSubscription("456").wait(context.TODO())).To(Succeed())
+}
+
+func TestRateLimitingQPS(t *testing.T) {
+	g := NewWithT(t)
+
+	limiters := newRateLimiters(1, 2)
+	limiter := limiters.forSubscription("123")
+
+	// The burst is available right away.
+	g.Expect(limiter.wait(context.TODO())).To(Succeed())
+	g.Expect(limiter.wait(context.TODO())).To(Succeed())
+
+	// Further requests have to wait for the rate limiter to refill.
+	ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+	defer cancel()
+	g.Expect(limiter.wait(ctx)).NotTo(Succeed())
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// CorrelationRequestIDHeader is the header of the correlation ID of the Azure API requests, which the requests sent
+	// within a single reconciliation share.
+	CorrelationRequestIDHeader = "x-ms-correlation-request-id"
+	// RequestIDHeader is the header of the ID Azure assigns to each request.
+	RequestIDHeader = "x-ms-request-id"
+)
+
+// responseError is an error annotated with the Azure API response it was returned for, e.g. the one of the polling
+// request of a failed long-running operation, whose error doesn't reference it.
+type responseError struct {
+	error
+	response *http.Response
+}
+
+// Unwrap returns the annotated error.
+func (e responseError) Unwrap() error {
+	return e.error
+}
+
+// WithResponse annotates err with the Azure API response it was returned for, so that the IDs of the request can be
+// surfaced along with it.
+func WithResponse(err error, resp *http.Response) error {
+	if err == nil || resp == nil {
+		return err
+	}
+	return responseError{error: err, response: resp}
+}
+
+// RequestIDs returns the correlation ID and the request ID of the Azure API request err was returned for, which
+// Azure support needs to look into a failure. They are empty if err doesn't come from an Azure API response.
+func RequestIDs(err error) (correlationID, requestID string) {
+	var resp *http.Response
+	rerr := responseError{}
+	derr := autorest.DetailedError{}
+	aerr := &azure.RequestError{}
+	switch {
+	case errors.As(err, &rerr):
+		resp = rerr.response
+	case errors.As(err, &derr) && derr.Response != nil:
+		resp = derr.Response
+	case errors.As(err, &aerr):
+		resp = aerr.Response
+	}
+	if resp == nil {
+		return "", ""
+	}
+	return resp.Header.Get(CorrelationRequestIDHeader), resp.Header.Get(RequestIDHeader)
+}
+
+// ErrorMessage returns the message of err, followed by the correlation ID and the request ID of the Azure API
+// request it was returned for, if any. The message of errors wrapping an ARM error is the one of the structured
+// ARM error, without the messages of the errors wrapping it.
+func ErrorMessage(err error) string {
+	message := err.Error()
+	if armErr := ParseARMError(err); armErr != nil {
+		message = armErr.Error()
+	}
+	correlationID, requestID := RequestIDs(err)
+	if correlationID == "" && requestID == "" {
+		return message
+	}
+	return fmt.Sprintf("%s (correlation ID: %s, request ID: %s)", message, correlationID, requestID)
+}
+
+// WithCorrelationRequestID returns a SendDecorator which sends the correlation ID of the reconciliation, which is also
+// an attribute of its trace spans, as the correlation ID of the Azure API requests. The IDs of the failed requests are
+// logged.
+func WithCorrelationRequestID() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+			if corrID, ok := tele.CorrIDFromCtx(req.Context()); ok && req.Header.Get(CorrelationRequestIDHeader) == "" {
+				if req.Header == nil {
+					req.Header = http.Header{}
+				}
+				req.Header.Set(CorrelationRequestIDHeader, string(corrID))
+			}
+
+			resp, err := s.Do(req)
+			if resp != nil && resp.StatusCode >= http.StatusBadRequest && resp.StatusCode != http.StatusNotFound {
+				log.FromContext(req.Context()).V(2).Info("Azure API request failed",
+					"method", req.Method,
+					"path", req.URL.Path,
+					"statusCode", resp.StatusCode,
+					"correlationID", resp.Header.Get(CorrelationRequestIDHeader),
+					"requestID", resp.Header.Get(RequestIDHeader),
+				)
+			}
+			return resp, err
+		})
+	}
+}
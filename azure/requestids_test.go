@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+func TestErrorMessage(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header: http.Header{
+			"X-Ms-Correlation-Request-Id": []string{"corr-id"},
+			"X-Ms-Request-Id":             []string{"req-id"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "error which doesn't come from Azure",
+			err:  errors.New("boom"),
+			want: "boom",
+		},
+		{
+			name: "error of an Azure API request",
+			err:  errors.Wrap(autorest.DetailedError{PackageType: "network.VirtualNetworksClient", Method: "CreateOrUpdate", Message: "boom", StatusCode: http.StatusBadRequest, Response: resp}, "failed to create"),
+			want: "failed to create: network.VirtualNetworksClient#CreateOrUpdate: boom: StatusCode=400 (correlation ID: corr-id, request ID: req-id)",
+		},
+		{
+			name: "error of a failed long-running operation",
+			err:  errors.Wrap(WithResponse(&azure.ServiceError{Code: "Failed", Message: "boom"}, resp), "operation failed"),
+			want: "Failed: boom (correlation ID: corr-id, request ID: req-id)",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(ErrorMessage(tt.err)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestWithCorrelationRequestID(t *testing.T) {
+	g := NewWithT(t)
+
+	var sent *http.Request
+	sender := WithCorrelationRequestID()(autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+		sent = req
+		return &http.Response{StatusCode: http.StatusOK, Request: req}, nil
+	}))
+	ctx, span := tele.Tracer().Start(context.Background(), "test")
+	defer span.End()
+	corrID, _ := tele.CorrIDFromCtx(ctx)
+
+	req := (&http.Request{Method: http.MethodGet, URL: &url.URL{Path: "/subscriptions/123"}}).WithContext(ctx)
+	_, err := sender.Do(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sent.Header.Get(CorrelationRequestIDHeader)).To(Equal(string(corrID)))
+
+	req = (&http.Request{Method: http.MethodGet, URL: &url.URL{Path: "/subscriptions/123"}, Header: http.Header{}}).WithContext(ctx)
+	req.Header.Set(CorrelationRequestIDHeader, "preset")
+	_, err = sender.Do(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sent.Header.Get(CorrelationRequestIDHeader)).To(Equal("preset"))
+}
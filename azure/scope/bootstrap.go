@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+const (
+	// bootstrapDataValueKey is the key of the bootstrap data in the bootstrap data secrets.
+	bootstrapDataValueKey = "value"
+	// bootstrapDataFormatKey is the key of the format of the bootstrap data in the bootstrap data secrets of the
+	// bootstrap providers supporting several formats.
+	bootstrapDataFormatKey = "format"
+)
+
+// bootstrapFormat returns the format of the bootstrap data of a machine: the one of its OS disk if set, otherwise the
+// one of its bootstrap data secret, or cloud-config.
+func bootstrapFormat(osDisk infrav1.OSDisk, secret *corev1.Secret) infrav1.BootstrapFormat {
+	if osDisk.BootstrapFormat != "" {
+		return osDisk.BootstrapFormat
+	}
+	if format, ok := secret.Data[bootstrapDataFormatKey]; ok && len(format) > 0 {
+		return infrav1.BootstrapFormat(format)
+	}
+	return infrav1.BootstrapFormatCloudConfig
+}
+
+// customData returns the base64 encoded custom data of a VM from its bootstrap data secret.
+func customData(osDisk infrav1.OSDisk, secret *corev1.Secret) (string, error) {
+	value, err := bootstrapData(osDisk, secret)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(value), nil
+}
+
+// bootstrapData returns the bootstrap data of a VM from its bootstrap data secret. The proxy settings are only added to
+// cloud-config bootstrap data of Linux machines, while Ignition configs and the bootstrap data of Windows machines are
+// returned as is.
+func bootstrapData(osDisk infrav1.OSDisk, secret *corev1.Secret) ([]byte, error) {
+	value, ok := secret.Data[bootstrapDataValueKey]
+	if !ok {
+		return nil, errors.New("error retrieving bootstrap data: secret value key is missing")
+	}
+
+	format := bootstrapFormat(osDisk, secret)
+	if osDisk.OSType == azure.WindowsOS && format == infrav1.BootstrapFormatIgnition {
+		return nil, errors.New("error retrieving bootstrap data: Windows machines cannot be bootstrapped with Ignition")
+	}
+	if osDisk.OSType != azure.WindowsOS && format == infrav1.BootstrapFormatCloudConfig {
+		var err error
+		if value, err = azure.InjectHTTPProxy(value); err != nil {
+			return nil, errors.Wrap(err, "failed to add the proxy settings to the bootstrap data")
+		}
+	}
+	return value, nil
+}
+
+// bootstrapDataSecretName returns the name of the Key Vault secret storing the bootstrap data of a machine, unique to
+// each AzureMachine so that the secret of a deleted machine is never reused by a new one with the same name.
+func bootstrapDataSecretName(uid types.UID) string {
+	return fmt.Sprintf("bootstrap-data-%s", uid)
+}
+
+// bootstrapDataFetcher returns the base64 encoded custom data of a VM fetching its bootstrap data from a Key Vault
+// secret at boot. It is a multipart cloud-init config made of a boothook, downloading the secret with a token of the
+// user-assigned identity of the VM, and of an include of the downloaded cloud-config.
+func bootstrapDataFetcher(secret azure.BootstrapDataSecretSpec, keyVaultResource, identityID string) string {
+	tokenURL := fmt.Sprintf("%s?api-version=2018-02-01&resource=%s&msi_res_id=%s", imdsTokenEndpoint,
+		url.QueryEscape(keyVaultResource), url.QueryEscape(strings.TrimPrefix(identityID, azure.ProviderIDPrefix)))
+	secretURL := fmt.Sprintf("%s/secrets/%s?api-version=7.0", strings.TrimSuffix(secret.VaultURL, "/"), secret.Name)
+	fetcher := fmt.Sprintf(bootstrapDataFetcherTemplate, secret.Name, tokenURL, secretURL)
+	return base64.StdEncoding.EncodeToString([]byte(fetcher))
+}
+
+// imdsTokenEndpoint is the endpoint of the Azure Instance Metadata Service issuing the tokens of the managed identities
+// of a VM.
+const imdsTokenEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+// bootstrapDataFetcherTemplate is the custom data of the VMs fetching their bootstrap data from Key Vault. The boothook
+// runs at every boot, but only downloads the bootstrap data once, to a tmpfs readable by root only.
+const bootstrapDataFetcherTemplate = `Content-Type: multipart/mixed; boundary="==BOUNDARY=="
+MIME-Version: 1.0
+
+--==BOUNDARY==
+Content-Type: text/cloud-boothook; charset="us-ascii"
+
+#!/bin/bash
+set -o nounset
+set -o pipefail
+umask 077
+
+BOOTSTRAP_DATA_FILE=/run/capz/bootstrap-data
+FETCHED_FILE=/var/lib/capz/%[1]s.fetched
+if [[ -f "${FETCHED_FILE}" ]]; then
+  exit 0
+fi
+mkdir -p "$(dirname "${BOOTSTRAP_DATA_FILE}")" "$(dirname "${FETCHED_FILE}")"
+
+for attempt in $(seq 60); do
+  if TOKEN=$(curl --noproxy '*' -sSf -H Metadata:true "%[2]s" |
+      python3 -c 'import json,sys; print(json.load(sys.stdin)["access_token"])') &&
+    curl -sSf -H "Authorization: Bearer ${TOKEN}" "%[3]s" |
+      python3 -c 'import json,sys; sys.stdout.write(json.load(sys.stdin)["value"])' > "${BOOTSTRAP_DATA_FILE}"; then
+    touch "${FETCHED_FILE}"
+    exit 0
+  fi
+  echo "failed to fetch the bootstrap data, attempt ${attempt}" >&2
+  sleep 5
+done
+exit 1
+
+--==BOUNDARY==
+Content-Type: text/x-include-url; charset="us-ascii"
+
+file:///run/capz/bootstrap-data
+
+--==BOUNDARY==--
+`
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"encoding/base64"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+func TestBootstrapFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		osDisk infrav1.OSDisk
+		secret *corev1.Secret
+		want   infrav1.BootstrapFormat
+	}{
+		{
+			name:   "defaults to cloud-config",
+			osDisk: infrav1.OSDisk{OSType: "Linux"},
+			secret: &corev1.Secret{Data: map[string][]byte{"value": []byte("#cloud-config")}},
+			want:   infrav1.BootstrapFormatCloudConfig,
+		},
+		{
+			name:   "uses the format of the bootstrap data secret",
+			osDisk: infrav1.OSDisk{OSType: "Linux"},
+			secret: &corev1.Secret{Data: map[string][]byte{"value": []byte("{}"), "format": []byte("ignition")}},
+			want:   infrav1.BootstrapFormatIgnition,
+		},
+		{
+			name:   "the format of the OS disk overrides the one of the secret",
+			osDisk: infrav1.OSDisk{OSType: "Linux", BootstrapFormat: infrav1.BootstrapFormatIgnition},
+			secret: &corev1.Secret{Data: map[string][]byte{"value": []byte("{}"), "format": []byte("cloud-config")}},
+			want:   infrav1.BootstrapFormatIgnition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(bootstrapFormat(tt.osDisk, tt.secret)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestCustomData(t *testing.T) {
+	g := NewWithT(t)
+
+	ignition := []byte(`{"ignition":{"version":"3.1.0"}}`)
+	got, err := customData(infrav1.OSDisk{OSType: "Linux", BootstrapFormat: infrav1.BootstrapFormatIgnition},
+		&corev1.Secret{Data: map[string][]byte{"value": ignition}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal(base64.StdEncoding.EncodeToString(ignition)))
+
+	_, err = customData(infrav1.OSDisk{OSType: azure.WindowsOS},
+		&corev1.Secret{Data: map[string][]byte{"value": ignition, "format": []byte("ignition")}})
+	g.Expect(err).To(MatchError("error retrieving bootstrap data: Windows machines cannot be bootstrapped with Ignition"))
+
+	_, err = customData(infrav1.OSDisk{OSType: "Linux"}, &corev1.Secret{})
+	g.Expect(err).To(MatchError("error retrieving bootstrap data: secret value key is missing"))
+}
+
+func TestBootstrapDataFetcher(t *testing.T) {
+	g := NewWithT(t)
+
+	got := bootstrapDataFetcher(azure.BootstrapDataSecretSpec{Name: "bootstrap-data-1234", VaultURL: "https://my-vault.vault.azure.net"},
+		"https://vault.azure.net", "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity")
+	fetcher, err := base64.StdEncoding.DecodeString(got)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(fetcher)).To(ContainSubstring("Content-Type: text/cloud-boothook"))
+	g.Expect(string(fetcher)).To(ContainSubstring("http://169.254.169.254/metadata/identity/oauth2/token?api-version=2018-02-01&resource=https%3A%2F%2Fvault.azure.net&msi_res_id=%2Fsubscriptions%2F123%2FresourceGroups%2Fmy-rg%2Fproviders%2FMicrosoft.ManagedIdentity%2FuserAssignedIdentities%2Fmy-identity"))
+	g.Expect(string(fetcher)).To(ContainSubstring("https://my-vault.vault.azure.net/secrets/bootstrap-data-1234?api-version=7.0"))
+	g.Expect(string(fetcher)).To(ContainSubstring("/var/lib/capz/bootstrap-data-1234.fetched"))
+	g.Expect(string(fetcher)).To(ContainSubstring("Content-Type: text/x-include-url"))
+	g.Expect(string(fetcher)).NotTo(ContainSubstring("%!"))
+}
@@ -21,12 +21,16 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
+	"net/url"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 )
 
 // AzureClients contains all the Azure clients used by the scopes.
@@ -36,6 +40,8 @@ type AzureClients struct {
 	Authorizer                 autorest.Authorizer
 	ResourceManagerEndpoint    string
 	ResourceManagerVMDNSSuffix string
+
+	credentialsProvider CredentialsProvider
 }
 
 // CloudEnvironment returns the Azure environment the controller runs in.
@@ -72,8 +78,8 @@ func (c *AzureClients) HashKey() string {
 	return base64.URLEncoding.EncodeToString(hasher.Sum(nil))
 }
 
-func (c *AzureClients) setCredentials(subscriptionID, environmentName string) error {
-	settings, err := c.getSettingsFromEnvironment(environmentName)
+func (c *AzureClients) setCredentials(subscriptionID, environmentName, resourceManagerEndpoint string) error {
+	settings, err := c.getSettingsFromEnvironment(environmentName, resourceManagerEndpoint)
 	if err != nil {
 		return err
 	}
@@ -94,17 +100,37 @@ func (c *AzureClients) setCredentials(subscriptionID, environmentName string) er
 	c.Values[auth.TenantID] = strings.TrimSuffix(c.Values[auth.TenantID], "\n")
 
 	if c.Authorizer == nil {
-		c.Authorizer, err = c.GetAuthorizer()
+		c.Authorizer, err = getAuthorizer(c.EnvironmentSettings)
 	}
 	return err
 }
 
-func (c *AzureClients) setCredentialsWithProvider(ctx context.Context, subscriptionID, environmentName string, credentialsProvider CredentialsProvider) error {
+// getAuthorizer returns an authorizer from the credentials of the controller environment, for the resource of the
+// settings. The tokens of service principals with a client secret are requested through the proxy configured for the
+// Azure requests, if any.
+func getAuthorizer(settings auth.EnvironmentSettings) (autorest.Authorizer, error) {
+	if settings.Values[auth.ClientSecret] == "" {
+		return settings.GetAuthorizer()
+	}
+
+	credentials, err := settings.GetClientCredentials()
+	if err != nil {
+		return nil, err
+	}
+	spt, err := credentials.ServicePrincipalToken()
+	if err != nil {
+		return nil, err
+	}
+	setHTTPSender(spt)
+	return autorest.NewBearerAuthorizer(spt), nil
+}
+
+func (c *AzureClients) setCredentialsWithProvider(ctx context.Context, subscriptionID, environmentName, resourceManagerEndpoint string, credentialsProvider CredentialsProvider) error {
 	if credentialsProvider == nil {
 		return fmt.Errorf("credentials provider cannot have an empty value")
 	}
 
-	settings, err := c.getSettingsFromEnvironment(environmentName)
+	settings, err := c.getSettingsFromEnvironment(environmentName, resourceManagerEndpoint)
 	if err != nil {
 		return err
 	}
@@ -130,10 +156,38 @@ func (c *AzureClients) setCredentialsWithProvider(ctx context.Context, subscript
 	c.Values[auth.ClientSecret] = strings.TrimSuffix(clientSecret, "\n")
 
 	c.Authorizer, err = credentialsProvider.GetAuthorizer(ctx, c.ResourceManagerEndpoint, c.Environment.ActiveDirectoryEndpoint)
+	c.credentialsProvider = credentialsProvider
 	return err
 }
 
-func (c *AzureClients) getSettingsFromEnvironment(environmentName string) (s auth.EnvironmentSettings, err error) {
+// KeyVaultAuthorizer returns an authorizer for the data plane of Azure Key Vault, from the same credentials as the
+// Authorizer of Azure Resource Manager.
+func (c *AzureClients) KeyVaultAuthorizer(ctx context.Context) (autorest.Authorizer, error) {
+	if c.credentialsProvider != nil {
+		return c.credentialsProvider.GetAuthorizer(ctx, c.KeyVaultResource(), c.Environment.ActiveDirectoryEndpoint)
+	}
+
+	settings := c.EnvironmentSettings
+	settings.Values = make(map[string]string, len(c.Values))
+	for k, v := range c.Values {
+		settings.Values[k] = v
+	}
+	settings.Values[auth.Resource] = c.KeyVaultResource()
+	return getAuthorizer(settings)
+}
+
+// KeyVaultResource returns the resource identifier of the data plane of Azure Key Vault, which the tokens of its
+// requests are issued for.
+func (c *AzureClients) KeyVaultResource() string {
+	return strings.TrimSuffix(c.Environment.ResourceIdentifiers.KeyVault, "/")
+}
+
+// KeyVaultDNSSuffix returns the DNS suffix of the Azure Key Vaults of the environment.
+func (c *AzureClients) KeyVaultDNSSuffix() string {
+	return c.Environment.KeyVaultDNSSuffix
+}
+
+func (c *AzureClients) getSettingsFromEnvironment(environmentName, resourceManagerEndpoint string) (s auth.EnvironmentSettings, err error) {
 	s = auth.EnvironmentSettings{
 		Values: map[string]string{},
 	}
@@ -148,9 +202,12 @@ func (c *AzureClients) getSettingsFromEnvironment(environmentName string) (s aut
 	setValue(s, auth.Username)
 	setValue(s, auth.Password)
 	setValue(s, auth.Resource)
-	if v := s.Values[auth.EnvironmentName]; v == "" {
+	switch v := s.Values[auth.EnvironmentName]; {
+	case v == "":
 		s.Environment = azure.PublicCloud
-	} else {
+	case strings.EqualFold(v, infrav1.AzureStackCloud):
+		s.Environment, err = getAzureStackEnvironment(resourceManagerEndpoint)
+	default:
 		s.Environment, err = azure.EnvironmentFromName(v)
 	}
 	if s.Values[auth.Resource] == "" {
@@ -159,6 +216,46 @@ func (c *AzureClients) getSettingsFromEnvironment(environmentName string) (s aut
 	return
 }
 
+// azureStackEnvironments caches the environments of Azure Stack Hubs by Azure Resource Manager endpoint, as looking
+// them up requires a request to the endpoint.
+var azureStackEnvironments sync.Map
+
+// getAzureStackEnvironment returns the environment of the Azure Stack Hub with the given Azure Resource Manager
+// endpoint, whose other endpoints are looked up from the metadata it serves.
+func getAzureStackEnvironment(resourceManagerEndpoint string) (azure.Environment, error) {
+	if resourceManagerEndpoint == "" {
+		return azure.Environment{}, fmt.Errorf("the Azure Resource Manager endpoint is required with the %s environment", infrav1.AzureStackCloud)
+	}
+	if env, ok := azureStackEnvironments.Load(resourceManagerEndpoint); ok {
+		return env.(azure.Environment), nil
+	}
+
+	env, err := azure.EnvironmentFromURL(resourceManagerEndpoint,
+		azure.OverrideProperty{Key: azure.EnvironmentName, Value: infrav1.AzureStackCloud},
+		azure.OverrideProperty{Key: azure.EnvironmentResourceManagerVMDNSSuffix, Value: azureStackVMDNSSuffix(resourceManagerEndpoint)},
+	)
+	if err != nil {
+		return azure.Environment{}, fmt.Errorf("failed to get the %s environment from %s: %w", infrav1.AzureStackCloud, resourceManagerEndpoint, err)
+	}
+	azureStackEnvironments.Store(resourceManagerEndpoint, env)
+	return env, nil
+}
+
+// azureStackVMDNSSuffix returns the DNS suffix of the public IPs of an Azure Stack Hub, e.g. cloudapp.azurestack.external
+// for the https://management.local.azurestack.external/ endpoint of the local region.
+func azureStackVMDNSSuffix(resourceManagerEndpoint string) string {
+	u, err := url.Parse(resourceManagerEndpoint)
+	if err != nil {
+		return ""
+	}
+	// The host of the endpoint is made of the management prefix, the region and the external domain of the hub.
+	labels := strings.SplitN(u.Hostname(), ".", 3)
+	if len(labels) < 3 {
+		return ""
+	}
+	return "cloudapp." + labels[2]
+}
+
 // setValue adds the specified environment variable value to the Values map if it exists.
 func setValue(settings auth.EnvironmentSettings, key string) {
 	if v := os.Getenv(key); v != "" {
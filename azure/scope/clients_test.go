@@ -17,6 +17,8 @@ limitations under the License.
 package scope
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/Azure/go-autorest/autorest"
@@ -71,7 +73,7 @@ func TestGettingEnvironment(t *testing.T) {
 			c := AzureClients{
 				Authorizer: autorest.NullAuthorizer{},
 			}
-			err := c.setCredentials("1234", test.azureEnv)
+			err := c.setCredentials("1234", test.azureEnv, "")
 			if test.expectedError {
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(err.Error()).To(ContainSubstring(test.expectedErrorMessage))
@@ -83,3 +85,44 @@ func TestGettingEnvironment(t *testing.T) {
 		})
 	}
 }
+
+func TestGettingAzureStackEnvironment(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metadata/endpoints" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"galleryEndpoint": "https://adminportal.local.azurestack.external:30015/",
+			"graphEndpoint": "https://graph.windows.net/",
+			"portalEndpoint": "https://portal.local.azurestack.external/",
+			"authentication": {
+				"loginEndpoint": "https://login.microsoftonline.com/",
+				"audiences": ["https://management.azurestackci.onmicrosoft.com/1234"]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	c := AzureClients{
+		Authorizer: autorest.NullAuthorizer{},
+	}
+	err := c.setCredentials("1234", "AzureStackCloud", server.URL)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.CloudEnvironment()).To(Equal("AzureStackCloud"))
+	g.Expect(c.ResourceManagerEndpoint).To(Equal(server.URL))
+	g.Expect(c.Environment.ActiveDirectoryEndpoint).To(Equal("https://login.microsoftonline.com/"))
+
+	err = (&AzureClients{Authorizer: autorest.NullAuthorizer{}}).setCredentials("1234", "AzureStackCloud", "")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestAzureStackVMDNSSuffix(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(azureStackVMDNSSuffix("https://management.local.azurestack.external/")).To(Equal("cloudapp.azurestack.external"))
+	g.Expect(azureStackVMDNSSuffix("https://management.redmond.contoso.com")).To(Equal("cloudapp.contoso.com"))
+	g.Expect(azureStackVMDNSSuffix("https://localhost:8443/")).To(BeEmpty())
+}
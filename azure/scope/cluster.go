@@ -18,24 +18,39 @@ package scope
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"hash/fnv"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2/klogr"
 	"k8s.io/utils/net"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/external"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
+)
+
+const (
+	// maxDNSLabelLength is the maximum length of a DNS label.
+	maxDNSLabelLength = 63
 )
 
 // ClusterScopeParams defines the input parameters used to create a new Scope.
@@ -45,6 +60,8 @@ type ClusterScopeParams struct {
 	Logger       logr.Logger
 	Cluster      *clusterv1.Cluster
 	AzureCluster *infrav1.AzureCluster
+	// LockResourceGroup protects the cluster resource group, when it is managed, with a CanNotDelete management lock.
+	LockResourceGroup bool
 }
 
 // NewClusterScope creates a new Scope from the supplied parameters.
@@ -62,7 +79,7 @@ func NewClusterScope(ctx context.Context, params ClusterScopeParams) (*ClusterSc
 	}
 
 	if params.AzureCluster.Spec.IdentityRef == nil {
-		err := params.AzureClients.setCredentials(params.AzureCluster.Spec.SubscriptionID, params.AzureCluster.Spec.AzureEnvironment)
+		err := params.AzureClients.setCredentials(params.AzureCluster.Spec.SubscriptionID, params.AzureCluster.Spec.AzureEnvironment, params.AzureCluster.Spec.ResourceManagerEndpoint)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to configure azure settings and credentials from environment")
 		}
@@ -71,7 +88,7 @@ func NewClusterScope(ctx context.Context, params ClusterScopeParams) (*ClusterSc
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to init credentials provider")
 		}
-		err = params.AzureClients.setCredentialsWithProvider(ctx, params.AzureCluster.Spec.SubscriptionID, params.AzureCluster.Spec.AzureEnvironment, credentialsProvider)
+		err = params.AzureClients.setCredentialsWithProvider(ctx, params.AzureCluster.Spec.SubscriptionID, params.AzureCluster.Spec.AzureEnvironment, params.AzureCluster.Spec.ResourceManagerEndpoint, credentialsProvider)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to configure azure settings and credentials for Identity")
 		}
@@ -83,12 +100,14 @@ func NewClusterScope(ctx context.Context, params ClusterScopeParams) (*ClusterSc
 	}
 
 	return &ClusterScope{
-		Logger:       params.Logger,
-		Client:       params.Client,
-		AzureClients: params.AzureClients,
-		Cluster:      params.Cluster,
-		AzureCluster: params.AzureCluster,
-		patchHelper:  helper,
+		Logger:            params.Logger,
+		Client:            params.Client,
+		AzureClients:      params.AzureClients,
+		Cluster:           params.Cluster,
+		AzureCluster:      params.AzureCluster,
+		lockResourceGroup: params.LockResourceGroup,
+		patchHelper:       helper,
+		futuresMu:         &sync.Mutex{},
 	}, nil
 }
 
@@ -99,8 +118,11 @@ type ClusterScope struct {
 	patchHelper *patch.Helper
 
 	AzureClients
-	Cluster      *clusterv1.Cluster
-	AzureCluster *infrav1.AzureCluster
+	Cluster           *clusterv1.Cluster
+	AzureCluster      *infrav1.AzureCluster
+	lockResourceGroup bool
+	// futuresMu guards the futures of the AzureCluster, as its resources are deleted concurrently.
+	futuresMu *sync.Mutex
 }
 
 // BaseURI returns the Azure ResourceManagerEndpoint.
@@ -129,6 +151,7 @@ func (s *ClusterScope) PublicIPSpecs() []azure.PublicIPSpec {
 			Name:    s.APIServerPublicIP().Name,
 			DNSName: s.APIServerPublicIP().DNSName,
 			IsIPv6:  false, // currently azure requires a ipv4 lb rule to enable ipv6
+			Zones:   s.FailureDomainNames(),
 		}}
 	}
 	publicIPSpecs = append(publicIPSpecs, controlPlaneOutboundIPSpecs...)
@@ -172,6 +195,7 @@ func (s *ClusterScope) LBSpecs() []azure.LBSpec {
 			SubnetName:           s.ControlPlaneSubnet().Name,
 			FrontendIPConfigs:    s.APIServerLB().FrontendIPs,
 			APIServerPort:        s.APIServerPort(),
+			FrontendIPZones:      s.FailureDomainNames(),
 			Type:                 s.APIServerLB().Type,
 			SKU:                  infrav1.SKUStandard,
 			Role:                 infrav1.APIServerRole,
@@ -200,7 +224,7 @@ func (s *ClusterScope) LBSpecs() []azure.LBSpec {
 			FrontendIPConfigs:    s.ControlPlaneOutboundLB().FrontendIPs,
 			Type:                 s.ControlPlaneOutboundLB().Type,
 			SKU:                  s.ControlPlaneOutboundLB().SKU,
-			BackendPoolName:      s.OutboundPoolName(azure.GenerateControlPlaneOutboundLBName(s.ClusterName())),
+			BackendPoolName:      s.OutboundPoolName(s.ControlPlaneOutboundLB().Name),
 			IdleTimeoutInMinutes: s.NodeOutboundLB().IdleTimeoutInMinutes,
 			Role:                 infrav1.ControlPlaneOutboundRole,
 		})
@@ -314,21 +338,252 @@ func (s *ClusterScope) PrivateDNSSpec() *azure.PrivateDNSSpec {
 	return spec
 }
 
+// DNSForwardingRulesetLinkSpec returns the DNS forwarding ruleset virtual network link spec.
+func (s *ClusterScope) DNSForwardingRulesetLinkSpec() *azure.DNSForwardingRulesetLinkSpec {
+	link := s.AzureCluster.Spec.NetworkSpec.DNSForwardingRulesetLink
+	if link == nil {
+		return nil
+	}
+	return &azure.DNSForwardingRulesetLinkSpec{
+		Name:      link.Name,
+		RulesetID: link.RulesetID,
+		VNetID:    azure.VNetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name),
+	}
+}
+
 // BastionSpec returns the bastion spec.
 func (s *ClusterScope) BastionSpec() azure.BastionSpec {
 	var ret azure.BastionSpec
 	if s.AzureCluster.Spec.BastionSpec.AzureBastion != nil {
 		ret.AzureBastion = &azure.AzureBastionSpec{
-			Name:         s.AzureCluster.Spec.BastionSpec.AzureBastion.Name,
-			SubnetSpec:   s.AzureCluster.Spec.BastionSpec.AzureBastion.Subnet,
-			PublicIPName: s.AzureCluster.Spec.BastionSpec.AzureBastion.PublicIP.Name,
-			VNetName:     s.Vnet().Name,
+			Name:              s.AzureCluster.Spec.BastionSpec.AzureBastion.Name,
+			SubnetSpec:        s.AzureCluster.Spec.BastionSpec.AzureBastion.Subnet,
+			PublicIPName:      s.AzureCluster.Spec.BastionSpec.AzureBastion.PublicIP.Name,
+			VNetName:          s.Vnet().Name,
+			VNetResourceGroup: s.Vnet().ResourceGroup,
+			DNSName:           s.DNSLabel(s.AzureCluster.Spec.BastionSpec.AzureBastion.Name),
 		}
 	}
 
 	return ret
 }
 
+// resourceIDs returns the IDs of the Azure resources expected by the AzureCluster.
+func (s *ClusterScope) resourceIDs() []string {
+	ids := []string{
+		azure.ResourceGroupID(s.SubscriptionID(), s.ResourceGroup()),
+		azure.VNetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name),
+	}
+	for _, nsgSpec := range s.NSGSpecs() {
+		ids = append(ids, azure.SecurityGroupID(s.SubscriptionID(), s.NetworkResourceGroup(), nsgSpec.Name))
+	}
+	for _, routeTableSpec := range s.RouteTableSpecs() {
+		ids = append(ids, azure.RouteTableID(s.SubscriptionID(), s.NetworkResourceGroup(), routeTableSpec.Name))
+	}
+	for _, natGatewaySpec := range s.NatGatewaySpecs() {
+		ids = append(ids, azure.NatGatewayID(s.SubscriptionID(), s.NetworkResourceGroup(), natGatewaySpec.Name))
+	}
+	for _, publicIPSpec := range s.PublicIPSpecs() {
+		ids = append(ids, azure.PublicIPID(s.SubscriptionID(), s.NetworkResourceGroup(), publicIPSpec.Name))
+	}
+	for _, lbSpec := range s.LBSpecs() {
+		ids = append(ids, azure.LoadBalancerID(s.SubscriptionID(), s.NetworkResourceGroup(), lbSpec.Name))
+	}
+	if zoneSpec := s.PrivateDNSSpec(); zoneSpec != nil {
+		ids = append(ids,
+			azure.PrivateDNSZoneID(s.SubscriptionID(), s.NetworkResourceGroup(), zoneSpec.ZoneName),
+			azure.VirtualNetworkLinkID(s.SubscriptionID(), s.NetworkResourceGroup(), zoneSpec.ZoneName, zoneSpec.LinkName),
+		)
+	}
+	if bastionSpec := s.BastionSpec(); bastionSpec.AzureBastion != nil {
+		ids = append(ids, azure.BastionHostID(s.SubscriptionID(), s.NetworkResourceGroup(), bastionSpec.AzureBastion.Name))
+	}
+	return ids
+}
+
+// AdoptionSpecs returns the pre-existing resources to adopt, if the adoption of resources is enabled on the AzureCluster.
+func (s *ClusterScope) AdoptionSpecs() []azure.AdoptionSpec {
+	lifecycle, ok := s.AzureCluster.Annotations[infrav1.AdoptResourcesAnnotation]
+	if !ok {
+		return nil
+	}
+	var specs []azure.AdoptionSpec
+	for _, id := range s.resourceIDs() {
+		specs = append(specs, azure.AdoptionSpec{
+			Scope:     id,
+			Lifecycle: infrav1.ResourceLifecycle(lifecycle),
+		})
+	}
+	return specs
+}
+
+// TagsSpecs returns the tags for the resources of the AzureCluster.
+// Resources not owned by the cluster, like a pre-existing virtual network, are skipped by the tags service.
+func (s *ClusterScope) TagsSpecs() []azure.TagsSpec {
+	// All the resources of the cluster share the same additional tags, so they are tracked by a single annotation.
+	var specs []azure.TagsSpec
+	for _, id := range s.resourceIDs() {
+		specs = append(specs, azure.TagsSpec{
+			Scope:      id,
+			Tags:       s.AdditionalTags(),
+			Annotation: infrav1.ClusterTagsLastAppliedAnnotation,
+		})
+	}
+	return specs
+}
+
+// ExpectedResourceIDs returns the IDs of all the resources expected to exist for the cluster and its AzureMachines.
+// Resources owned by the cluster which are not part of this list are garbage collected by the orphans service.
+func (s *ClusterScope) ExpectedResourceIDs(ctx context.Context) ([]string, error) {
+	ids := s.resourceIDs()
+
+	azureMachines := &infrav1.AzureMachineList{}
+	if err := s.Client.List(ctx, azureMachines, client.InNamespace(s.Namespace()), client.MatchingLabels{clusterv1.ClusterLabelName: s.ClusterName()}); err != nil {
+		return nil, errors.Wrap(err, "failed to list AzureMachines")
+	}
+	for i := range azureMachines.Items {
+		azureMachine := &azureMachines.Items[i]
+		name := vmName(azureMachine)
+		ids = append(ids,
+			azure.NetworkInterfaceID(s.SubscriptionID(), s.ResourceGroup(), azure.GenerateNICName(name)),
+			azure.NetworkInterfaceID(s.SubscriptionID(), s.ResourceGroup(), azure.GeneratePublicNICName(name)),
+			azure.PublicIPID(s.SubscriptionID(), s.NetworkResourceGroup(), azure.GenerateNodePublicIPName(name)),
+			azure.DiskID(s.SubscriptionID(), s.ResourceGroup(), azure.GenerateOSDiskName(name)),
+		)
+		for _, dataDisk := range azureMachine.Spec.DataDisks {
+			ids = append(ids, azure.DiskID(s.SubscriptionID(), s.ResourceGroup(), azure.GenerateDataDiskName(name, dataDisk.NameSuffix)))
+		}
+	}
+	return ids, nil
+}
+
+// CostSpecs returns the specs of the resources of the cluster and its AzureMachines whose monthly cost is estimated.
+func (s *ClusterScope) CostSpecs(ctx context.Context) ([]azure.CostSpec, error) {
+	var specs []azure.CostSpec
+
+	for _, lb := range s.LBSpecs() {
+		specs = append(specs, azure.CostSpec{Name: lb.Name, Type: azure.LoadBalancerResourceType, SKU: string(lb.SKU)})
+	}
+
+	// A public IP may be listed several times by PublicIPSpecs, e.g. when several node subnets share a NAT gateway.
+	publicIPs := make(map[string]bool)
+	for _, ip := range s.PublicIPSpecs() {
+		if publicIPs[ip.Name] {
+			continue
+		}
+		publicIPs[ip.Name] = true
+		specs = append(specs, azure.CostSpec{Name: ip.Name, Type: azure.PublicIPResourceType, SKU: string(infrav1.SKUStandard), IsIPv6: ip.IsIPv6})
+	}
+
+	for _, natGateway := range s.NatGatewaySpecs() {
+		specs = append(specs, azure.CostSpec{Name: natGateway.Name, Type: azure.NatGatewayResourceType, SKU: string(infrav1.SKUStandard)})
+	}
+
+	if bastion := s.BastionSpec().AzureBastion; bastion != nil {
+		specs = append(specs, azure.CostSpec{Name: bastion.Name, Type: azure.BastionHostResourceType, SKU: "Basic"})
+	}
+
+	azureMachines := &infrav1.AzureMachineList{}
+	if err := s.Client.List(ctx, azureMachines, client.InNamespace(s.Namespace()), client.MatchingLabels{clusterv1.ClusterLabelName: s.ClusterName()}); err != nil {
+		return nil, errors.Wrap(err, "failed to list AzureMachines")
+	}
+	for _, azureMachine := range azureMachines.Items {
+		if !azureMachine.DeletionTimestamp.IsZero() {
+			continue
+		}
+		specs = append(specs, azureMachineCostSpecs(azureMachine)...)
+	}
+
+	return specs, nil
+}
+
+// azureMachineCostSpecs returns the specs of the resources of an AzureMachine whose monthly cost is estimated.
+func azureMachineCostSpecs(azureMachine infrav1.AzureMachine) []azure.CostSpec {
+	specs := []azure.CostSpec{{
+		Name:      azureMachine.Name,
+		Type:      azure.VirtualMachineResourceType,
+		SKU:       azureMachine.Spec.VMSize,
+		IsWindows: azureMachine.Spec.OSDisk.OSType == azure.WindowsOS,
+		IsSpot:    azureMachine.Spec.SpotVMOptions != nil,
+	}}
+
+	// Ephemeral OS disks are stored on the VM host and are included in the price of the VM.
+	if azureMachine.Spec.OSDisk.DiffDiskSettings == nil {
+		// The OS disk takes the size of the image when it isn't set, which is 30 GB for the default images.
+		sizeGB := to.Int32(azureMachine.Spec.OSDisk.DiskSizeGB)
+		if sizeGB == 0 {
+			sizeGB = 30
+		}
+		specs = append(specs, azure.CostSpec{
+			Name:       azure.GenerateOSDiskName(azureMachine.Name),
+			Type:       azure.DiskResourceType,
+			SKU:        storageAccountType(azureMachine.Spec.OSDisk.ManagedDisk),
+			DiskSizeGB: sizeGB,
+		})
+	}
+
+	for _, dataDisk := range azureMachine.Spec.DataDisks {
+		specs = append(specs, azure.CostSpec{
+			Name:       azure.GenerateDataDiskName(azureMachine.Name, dataDisk.NameSuffix),
+			Type:       azure.DiskResourceType,
+			SKU:        storageAccountType(dataDisk.ManagedDisk),
+			DiskSizeGB: dataDisk.DiskSizeGB,
+		})
+	}
+
+	if azureMachine.Spec.AllocatePublicIP {
+		specs = append(specs, azure.CostSpec{
+			Name: azure.GenerateNodePublicIPName(azureMachine.Name),
+			Type: azure.PublicIPResourceType,
+			SKU:  string(infrav1.SKUStandard),
+		})
+	}
+
+	return specs
+}
+
+// storageAccountType returns the storage account type of a managed disk, which defaults to Standard_LRS in Azure.
+func storageAccountType(managedDisk *infrav1.ManagedDiskParameters) string {
+	if managedDisk == nil || managedDisk.StorageAccountType == "" {
+		return "Standard_LRS"
+	}
+	return managedDisk.StorageAccountType
+}
+
+// LastGarbageCollection returns when the resources orphaned by the cluster were last garbage collected.
+// It returns the zero time if they have never been, or if the annotation can't be parsed.
+func (s *ClusterScope) LastGarbageCollection() time.Time {
+	last, err := time.Parse(time.RFC3339, s.AzureCluster.Annotations[infrav1.LastGarbageCollectionAnnotation])
+	if err != nil {
+		return time.Time{}
+	}
+	return last
+}
+
+// SetLastGarbageCollection records when the resources orphaned by the cluster were last garbage collected.
+func (s *ClusterScope) SetLastGarbageCollection(last time.Time) {
+	if s.AzureCluster.Annotations == nil {
+		s.AzureCluster.Annotations = make(map[string]string)
+	}
+	s.AzureCluster.Annotations[infrav1.LastGarbageCollectionAnnotation] = last.UTC().Format(time.RFC3339)
+}
+
+// SetLongRunningOperationState will set the future on the AzureCluster status to allow the resource to continue
+// reconciliation without blocking on the long-running operation.
+func (s *ClusterScope) SetLongRunningOperationState(future *infrav1.Future) {
+	futures.Set(s.futuresMu, &s.AzureCluster.Status.LongRunningOperationStates, future)
+}
+
+// GetLongRunningOperationState will get the future on the AzureCluster status for the named resource of a service.
+func (s *ClusterScope) GetLongRunningOperationState(name, service string) *infrav1.Future {
+	return futures.Get(s.futuresMu, &s.AzureCluster.Status.LongRunningOperationStates, name, service)
+}
+
+// DeleteLongRunningOperationState will delete the future from the AzureCluster status for the named resource of a service.
+func (s *ClusterScope) DeleteLongRunningOperationState(name, service string) {
+	futures.Delete(s.futuresMu, &s.AzureCluster.Status.LongRunningOperationStates, name, service)
+}
+
 // Vnet returns the cluster Vnet.
 func (s *ClusterScope) Vnet() *infrav1.VnetSpec {
 	return &s.AzureCluster.Spec.NetworkSpec.Vnet
@@ -444,20 +699,29 @@ func (s *ClusterScope) GetPrivateDNSZoneName() string {
 
 // APIServerLBPoolName returns the API Server LB backend pool name.
 func (s *ClusterScope) APIServerLBPoolName(loadBalancerName string) string {
+	if lb := s.APIServerLB(); lb.Name == loadBalancerName && lb.BackendPool.Name != "" {
+		return lb.BackendPool.Name
+	}
 	return azure.GenerateBackendAddressPoolName(loadBalancerName)
 }
 
 // NodeOutboundLBName returns the name of the node outbound LB.
 func (s *ClusterScope) NodeOutboundLBName() string {
+	if lb := s.NodeOutboundLB(); lb != nil && lb.Name != "" {
+		return lb.Name
+	}
 	return s.ClusterName()
 }
 
 // OutboundLBName returns the name of the outbound LB.
 func (s *ClusterScope) OutboundLBName(role string) string {
 	if role == infrav1.Node {
-		return s.ClusterName()
+		return s.NodeOutboundLBName()
 	}
 	if s.IsAPIServerPrivate() {
+		if lb := s.ControlPlaneOutboundLB(); lb != nil && lb.Name != "" {
+			return lb.Name
+		}
 		return azure.GenerateControlPlaneOutboundLBName(s.ClusterName())
 	}
 	return s.APIServerLBName()
@@ -465,6 +729,11 @@ func (s *ClusterScope) OutboundLBName(role string) string {
 
 // OutboundPoolName returns the outbound LB backend pool name.
 func (s *ClusterScope) OutboundPoolName(loadBalancerName string) string {
+	for _, lb := range []*infrav1.LoadBalancerSpec{s.NodeOutboundLB(), s.ControlPlaneOutboundLB()} {
+		if lb != nil && lb.Name == loadBalancerName && lb.BackendPool.Name != "" {
+			return lb.BackendPool.Name
+		}
+	}
 	return azure.GenerateOutboundBackendAddressPoolName(loadBalancerName)
 }
 
@@ -473,6 +742,15 @@ func (s *ClusterScope) ResourceGroup() string {
 	return s.AzureCluster.Spec.ResourceGroup
 }
 
+// NetworkResourceGroup returns the resource group holding the network resources of the cluster.
+// It defaults to the cluster resource group.
+func (s *ClusterScope) NetworkResourceGroup() string {
+	if s.AzureCluster.Spec.NetworkSpec.ResourceGroup != "" {
+		return s.AzureCluster.Spec.NetworkSpec.ResourceGroup
+	}
+	return s.ResourceGroup()
+}
+
 // ClusterName returns the cluster name.
 func (s *ClusterScope) ClusterName() string {
 	return s.Cluster.Name
@@ -488,6 +766,50 @@ func (s *ClusterScope) Location() string {
 	return s.AzureCluster.Spec.Location
 }
 
+// FailureDomainNames returns the sorted names of the cluster failure domains, which are the availability zones of the
+// cluster location. Load balancer frontends use them to be zone-redundant, so that they serve machines in every zone.
+func (s *ClusterScope) FailureDomainNames() []string {
+	if len(s.AzureCluster.Status.FailureDomains) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(s.AzureCluster.Status.FailureDomains))
+	for name := range s.AzureCluster.Status.FailureDomains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Region returns the region published in the AzureCluster status.
+func (s *ClusterScope) Region() *infrav1.RegionStatus {
+	return s.AzureCluster.Status.Region
+}
+
+// SetRegion publishes the region in the AzureCluster status.
+func (s *ClusterScope) SetRegion(region infrav1.RegionStatus) {
+	s.AzureCluster.Status.Region = &region
+}
+
+// CostEstimate returns the cost estimate published in the AzureCluster status.
+func (s *ClusterScope) CostEstimate() *infrav1.CostEstimate {
+	return s.AzureCluster.Status.EstimatedCost
+}
+
+// SetCostEstimate publishes the cost estimate in the AzureCluster status.
+func (s *ClusterScope) SetCostEstimate(estimate infrav1.CostEstimate) {
+	s.AzureCluster.Status.EstimatedCost = &estimate
+}
+
+// ResourceGroupLockEnabled returns true if the managed cluster resource group should be protected by a CanNotDelete management lock.
+func (s *ClusterScope) ResourceGroupLockEnabled() bool {
+	return s.lockResourceGroup
+}
+
+// SetResourceGroupStatus records the cluster resource group and whether its lifecycle is managed by the cluster.
+func (s *ClusterScope) SetResourceGroupStatus(status infrav1.ResourceGroupStatus) {
+	s.AzureCluster.Status.ResourceGroup = &status
+}
+
 // AvailabilitySetEnabled informs machines that they should be part of an Availability Set.
 func (s *ClusterScope) AvailabilitySetEnabled() bool {
 	return len(s.AzureCluster.Status.FailureDomains) == 0
@@ -498,14 +820,42 @@ func (s *ClusterScope) CloudProviderConfigOverrides() *infrav1.CloudProviderConf
 	return s.AzureCluster.Spec.CloudProviderConfigOverrides
 }
 
-// GenerateFQDN generates a fully qualified domain name, based on a hash, cluster name and cluster location.
-func (s *ClusterScope) GenerateFQDN(ipName string) string {
-	h := fnv.New32a()
-	if _, err := h.Write([]byte(fmt.Sprintf("%s/%s/%s", s.SubscriptionID(), s.ResourceGroup(), ipName))); err != nil {
-		return ""
+// DNSLabelSuffix returns the unique suffix appended to the DNS labels generated for the cluster, persisted as an
+// annotation on the AzureCluster so that it remains stable for the lifetime of the cluster, or an empty string if it
+// hasn't been generated yet.
+func (s *ClusterScope) DNSLabelSuffix() string {
+	return s.AzureCluster.Annotations[infrav1.DNSLabelSuffixAnnotation]
+}
+
+// SetDNSLabelSuffix sets the unique suffix appended to the DNS labels generated for the cluster.
+func (s *ClusterScope) SetDNSLabelSuffix(suffix string) {
+	if s.AzureCluster.Annotations == nil {
+		s.AzureCluster.Annotations = make(map[string]string)
 	}
-	hash := fmt.Sprintf("%x", h.Sum32())
-	return strings.ToLower(fmt.Sprintf("%s-%s.%s.%s", s.ClusterName(), hash, s.Location(), s.AzureClients.ResourceManagerVMDNSSuffix))
+	s.AzureCluster.Annotations[infrav1.DNSLabelSuffixAnnotation] = suffix
+}
+
+// DNSLabel generates a DNS label unique to the cluster, based on the given prefix and the cluster DNS label suffix.
+// The prefix is used as is until the suffix is generated.
+func (s *ClusterScope) DNSLabel(prefix string) string {
+	suffix := s.DNSLabelSuffix()
+	prefix = strings.ToLower(prefix)
+	if suffix == "" {
+		if len(prefix) > maxDNSLabelLength {
+			prefix = strings.TrimSuffix(prefix[:maxDNSLabelLength], "-")
+		}
+		return prefix
+	}
+	// DNS labels are limited to 63 characters, truncate the prefix so that the suffix is always kept.
+	if maxPrefixLength := maxDNSLabelLength - len(suffix) - 1; len(prefix) > maxPrefixLength {
+		prefix = strings.TrimSuffix(prefix[:maxPrefixLength], "-")
+	}
+	return fmt.Sprintf("%s-%s", prefix, suffix)
+}
+
+// GenerateFQDN generates a fully qualified domain name, based on the DNS label prefix, the cluster DNS label suffix and cluster location.
+func (s *ClusterScope) GenerateFQDN(prefix string) string {
+	return strings.ToLower(fmt.Sprintf("%s.%s.%s", s.DNSLabel(prefix), s.Location(), s.AzureClients.ResourceManagerVMDNSSuffix))
 }
 
 // GenerateLegacyFQDN generates an IP name and a fully qualified domain name, based on a hash, cluster name and cluster location.
@@ -531,7 +881,18 @@ func (s *ClusterScope) ListOptionsLabelSelector() client.ListOption {
 func (s *ClusterScope) PatchObject(ctx context.Context) error {
 	conditions.SetSummary(s.AzureCluster,
 		conditions.WithConditions(
+			infrav1.IdentityReadyCondition,
 			infrav1.NetworkInfrastructureReadyCondition,
+			infrav1.ResourceGroupReadyCondition,
+			infrav1.VNetReadyCondition,
+			infrav1.SecurityGroupsReadyCondition,
+			infrav1.RouteTablesReadyCondition,
+			infrav1.PublicIPsReadyCondition,
+			infrav1.NATGatewaysReadyCondition,
+			infrav1.SubnetsReadyCondition,
+			infrav1.LoadBalancersReadyCondition,
+			infrav1.PrivateDNSReadyCondition,
+			infrav1.BastionHostReadyCondition,
 		),
 		conditions.WithStepCounterIfOnly(
 			infrav1.NetworkInfrastructureReadyCondition,
@@ -543,15 +904,67 @@ func (s *ClusterScope) PatchObject(ctx context.Context) error {
 		s.AzureCluster,
 		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 			clusterv1.ReadyCondition,
+			infrav1.IdentityReadyCondition,
 			infrav1.NetworkInfrastructureReadyCondition,
+			infrav1.ResourceGroupReadyCondition,
+			infrav1.VNetReadyCondition,
+			infrav1.SecurityGroupsReadyCondition,
+			infrav1.RouteTablesReadyCondition,
+			infrav1.PublicIPsReadyCondition,
+			infrav1.NATGatewaysReadyCondition,
+			infrav1.SubnetsReadyCondition,
+			infrav1.LoadBalancersReadyCondition,
+			infrav1.PrivateDNSReadyCondition,
+			infrav1.BastionHostReadyCondition,
+			infrav1.RegionMetadataAvailableCondition,
 		}})
 }
 
+// UpdatePutStatus sets the condition of the AzureCluster reporting on the resources of service from the result of their creation or update.
+func (s *ClusterScope) UpdatePutStatus(condition clusterv1.ConditionType, service string, err error) {
+	updatePutStatus(s.AzureCluster, condition, service, err)
+}
+
+// UpdateDeleteStatus sets the condition of the AzureCluster reporting on the resources of service from the result of their deletion.
+func (s *ClusterScope) UpdateDeleteStatus(condition clusterv1.ConditionType, service string, err error) {
+	updateDeleteStatus(s.AzureCluster, condition, service, err)
+}
+
 // Close closes the current scope persisting the cluster configuration and status.
 func (s *ClusterScope) Close(ctx context.Context) error {
 	return s.PatchObject(ctx)
 }
 
+// AnnotationJSON returns a map[string]interface from a JSON annotation.
+func (s *ClusterScope) AnnotationJSON(annotation string) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	jsonAnnotation := s.AzureCluster.GetAnnotations()[annotation]
+	if len(jsonAnnotation) == 0 {
+		return out, nil
+	}
+	err := json.Unmarshal([]byte(jsonAnnotation), &out)
+	if err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// UpdateAnnotationJSON updates the `annotation` with
+// `content`. `content` in this case should be a `map[string]interface{}`
+// suitable for turning into JSON. This `content` map will be marshalled into a
+// JSON string before being set as the given `annotation`.
+func (s *ClusterScope) UpdateAnnotationJSON(annotation string, content map[string]interface{}) error {
+	b, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	if s.AzureCluster.Annotations == nil {
+		s.AzureCluster.Annotations = make(map[string]string)
+	}
+	s.AzureCluster.Annotations[annotation] = string(b)
+	return nil
+}
+
 // AdditionalTags returns AdditionalTags from the scope's AzureCluster.
 func (s *ClusterScope) AdditionalTags() infrav1.Tags {
 	tags := make(infrav1.Tags)
@@ -585,6 +998,62 @@ func (s *ClusterScope) SetFailureDomain(id string, spec clusterv1.FailureDomainS
 	s.AzureCluster.Status.FailureDomains[id] = spec
 }
 
+// ControlPlaneVMSize returns the VM size of the AzureMachineTemplate referenced by the cluster's control plane.
+// It returns an empty string if the control plane or its machine template don't exist (yet), if the control plane
+// can't be read by the controller, or if the control plane isn't backed by an AzureMachineTemplate.
+func (s *ClusterScope) ControlPlaneVMSize(ctx context.Context) (string, error) {
+	template, err := s.ControlPlaneMachineTemplate(ctx)
+	if err != nil || template == nil {
+		return "", err
+	}
+	return template.Spec.Template.Spec.VMSize, nil
+}
+
+// ControlPlaneMachineTemplate returns the AzureMachineTemplate referenced by the cluster's control plane.
+// It returns nil if the control plane or its machine template don't exist (yet), if the control plane can't be read
+// by the controller, or if the control plane isn't backed by an AzureMachineTemplate.
+func (s *ClusterScope) ControlPlaneMachineTemplate(ctx context.Context) (*infrav1.AzureMachineTemplate, error) {
+	if s.Cluster == nil || s.Cluster.Spec.ControlPlaneRef == nil {
+		return nil, nil
+	}
+
+	controlPlane, err := external.Get(ctx, s.Client, s.Cluster.Spec.ControlPlaneRef, s.Cluster.Namespace)
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil, nil
+	case apierrors.IsForbidden(err), meta.IsNoMatchError(err), runtime.IsNotRegisteredError(err):
+		// The controller is only allowed to read KubeadmControlPlanes, other control plane providers are treated as
+		// not backed by an AzureMachineTemplate.
+		s.V(4).Info("unable to read the control plane, its machine template is unknown", "kind", s.Cluster.Spec.ControlPlaneRef.Kind, "error", err.Error())
+		return nil, nil
+	case err != nil:
+		return nil, errors.Wrapf(err, "failed to get control plane %s", s.Cluster.Spec.ControlPlaneRef.Name)
+	}
+
+	kind, _, err := unstructured.NestedString(controlPlane.Object, "spec", "machineTemplate", "infrastructureRef", "kind")
+	if err != nil || kind != "AzureMachineTemplate" {
+		return nil, nil
+	}
+	name, _, err := unstructured.NestedString(controlPlane.Object, "spec", "machineTemplate", "infrastructureRef", "name")
+	if err != nil || name == "" {
+		return nil, nil
+	}
+	namespace, _, _ := unstructured.NestedString(controlPlane.Object, "spec", "machineTemplate", "infrastructureRef", "namespace")
+	if namespace == "" {
+		namespace = s.Cluster.Namespace
+	}
+
+	template := &infrav1.AzureMachineTemplate{}
+	if err := s.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, template); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get AzureMachineTemplate %s/%s", namespace, name)
+	}
+
+	return template, nil
+}
+
 // SetControlPlaneSecurityRules sets the default security rules of the control plane subnet.
 // Note that this is not done in a webhook as it requires a valid Cluster object to exist to get the API Server port.
 func (s *ClusterScope) SetControlPlaneSecurityRules() {
@@ -645,7 +1114,7 @@ func (s *ClusterScope) SetDNSName() {
 	// Generate valid FQDN if not set.
 	// Note: this function uses the AzureCluster subscription ID.
 	if !s.IsAPIServerPrivate() && s.APIServerPublicIP().DNSName == "" {
-		s.APIServerPublicIP().DNSName = s.GenerateFQDN(s.APIServerPublicIP().Name)
+		s.APIServerPublicIP().DNSName = s.GenerateFQDN(s.ClusterName())
 	}
 }
 
@@ -657,12 +1126,14 @@ func (s *ClusterScope) getOutboundLBPublicIPSpecs(outboundLB *infrav1.LoadBalanc
 		// do nothing
 	} else if *loadBalancerNodeOutboundIPs == 1 {
 		outboundIPSpecs = append(outboundIPSpecs, azure.PublicIPSpec{
-			Name: generateOutboundIPName(s.ClusterName()),
+			Name:  generateOutboundIPName(s.ClusterName()),
+			Zones: s.FailureDomainNames(),
 		})
 	} else {
 		for i := 0; i < int(*loadBalancerNodeOutboundIPs); i++ {
 			outboundIPSpecs = append(outboundIPSpecs, azure.PublicIPSpec{
-				Name: azure.WithIndex(generateOutboundIPName(s.ClusterName()), i+1),
+				Name:  azure.WithIndex(generateOutboundIPName(s.ClusterName()), i+1),
+				Zones: s.FailureDomainNames(),
 			})
 		}
 	}
@@ -18,16 +18,27 @@ package scope
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -89,3 +100,614 @@ func TestGettingSecurityRules(t *testing.T) {
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(len(subnet.SecurityGroup.SecurityRules)).To(Equal(2))
 }
+
+func TestDNSLabelSuffix(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster",
+			Namespace: "default",
+		},
+	}
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-azure-cluster",
+			Namespace: "default",
+		},
+		Spec: infrav1.AzureClusterSpec{
+			SubscriptionID: "123",
+			Location:       "WestUS2",
+		},
+	}
+	azureCluster.Default()
+
+	initObjects := []runtime.Object{cluster, azureCluster}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+	clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+		AzureClients: AzureClients{
+			Authorizer:                 autorest.NullAuthorizer{},
+			ResourceManagerVMDNSSuffix: "cloudapp.azure.com",
+		},
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+		Client:       fakeClient,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(clusterScope.DNSLabelSuffix()).To(BeEmpty())
+	g.Expect(clusterScope.DNSLabel("My-Bastion")).To(Equal("my-bastion"))
+
+	suffix := "abc123"
+	clusterScope.SetDNSLabelSuffix(suffix)
+	g.Expect(clusterScope.AzureCluster.Annotations).To(HaveKeyWithValue(infrav1.DNSLabelSuffixAnnotation, suffix))
+	g.Expect(clusterScope.DNSLabelSuffix()).To(Equal(suffix))
+
+	g.Expect(clusterScope.DNSLabel("My-Bastion")).To(Equal("my-bastion-" + suffix))
+	g.Expect(clusterScope.GenerateFQDN("my-cluster")).To(Equal("my-cluster-" + suffix + ".westus2.cloudapp.azure.com"))
+
+	longLabel := clusterScope.DNSLabel(strings.Repeat("a", 70))
+	g.Expect(len(longLabel)).To(BeNumerically("<=", maxDNSLabelLength))
+	g.Expect(longLabel).To(HaveSuffix("-" + suffix))
+}
+
+func TestControlPlaneVMSize(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster",
+			Namespace: "default",
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef: &corev1.ObjectReference{
+				APIVersion: "controlplane.cluster.x-k8s.io/v1alpha4",
+				Kind:       "KubeadmControlPlane",
+				Name:       "my-control-plane",
+				Namespace:  "default",
+			},
+		},
+	}
+	controlPlane := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "controlplane.cluster.x-k8s.io/v1alpha4",
+			"kind":       "KubeadmControlPlane",
+			"metadata": map[string]interface{}{
+				"name":      "my-control-plane",
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"machineTemplate": map[string]interface{}{
+					"infrastructureRef": map[string]interface{}{
+						"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha4",
+						"kind":       "AzureMachineTemplate",
+						"name":       "my-control-plane-template",
+					},
+				},
+			},
+		},
+	}
+	template := &infrav1.AzureMachineTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-control-plane-template",
+			Namespace: "default",
+		},
+		Spec: infrav1.AzureMachineTemplateSpec{
+			Template: infrav1.AzureMachineTemplateResource{
+				Spec: infrav1.AzureMachineSpec{
+					VMSize: "Standard_D2s_v3",
+				},
+			},
+		},
+	}
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-azure-cluster",
+			Namespace: "default",
+		},
+		Spec: infrav1.AzureClusterSpec{
+			SubscriptionID: "123",
+		},
+	}
+	azureCluster.Default()
+
+	initObjects := []runtime.Object{cluster, azureCluster, controlPlane, template}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+	clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+		Client:       fakeClient,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	vmSize, err := clusterScope.ControlPlaneVMSize(context.TODO())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(vmSize).To(Equal("Standard_D2s_v3"))
+
+	cluster.Spec.ControlPlaneRef.Name = "does-not-exist"
+	vmSize, err = clusterScope.ControlPlaneVMSize(context.TODO())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(vmSize).To(BeEmpty())
+
+	cluster.Spec.ControlPlaneRef = nil
+	vmSize, err = clusterScope.ControlPlaneVMSize(context.TODO())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(vmSize).To(BeEmpty())
+}
+
+// controlPlaneErrorClient fails to get unstructured objects, like a client which isn't allowed to read a control plane
+// or doesn't know about its kind.
+type controlPlaneErrorClient struct {
+	client.Client
+	err error
+}
+
+func (c *controlPlaneErrorClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if _, ok := obj.(*unstructured.Unstructured); ok {
+		return c.err
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+func TestControlPlaneVMSizeWithOtherControlPlane(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	controlPlaneRef := &corev1.ObjectReference{
+		APIVersion: "controlplane.cluster.x-k8s.io/v1alpha3",
+		Kind:       "TalosControlPlane",
+		Name:       "my-control-plane",
+		Namespace:  "default",
+	}
+	gr := schema.GroupResource{Group: "controlplane.cluster.x-k8s.io", Resource: "taloscontrolplanes"}
+
+	testcases := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "control plane can't be read",
+			err:  apierrors.NewForbidden(gr, "my-control-plane", errors.New("forbidden")),
+		},
+		{
+			name: "control plane kind is unknown",
+			err:  &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: gr.Group, Kind: controlPlaneRef.Kind}, SearchedVersions: []string{"v1alpha3"}},
+		},
+		{
+			name: "control plane doesn't have a machine template",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-cluster",
+					Namespace: "default",
+				},
+				Spec: clusterv1.ClusterSpec{
+					ControlPlaneRef: controlPlaneRef.DeepCopy(),
+				},
+			}
+			controlPlane := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": controlPlaneRef.APIVersion,
+					"kind":       controlPlaneRef.Kind,
+					"metadata": map[string]interface{}{
+						"name":      "my-control-plane",
+						"namespace": "default",
+					},
+					"spec": map[string]interface{}{
+						"controlPlaneConfig": map[string]interface{}{},
+					},
+				},
+			}
+			azureCluster := &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-azure-cluster",
+					Namespace: "default",
+				},
+				Spec: infrav1.AzureClusterSpec{
+					SubscriptionID: "123",
+				},
+			}
+			azureCluster.Default()
+
+			var c client.Client = fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cluster, azureCluster, controlPlane).Build()
+			if tc.err != nil {
+				c = &controlPlaneErrorClient{Client: c, err: tc.err}
+			}
+
+			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+				AzureClients: AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Cluster:      cluster,
+				AzureCluster: azureCluster,
+				Client:       c,
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			vmSize, err := clusterScope.ControlPlaneVMSize(context.TODO())
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(vmSize).To(BeEmpty())
+		})
+	}
+}
+
+func TestClusterScopeTagsSpecs(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster",
+			Namespace: "default",
+		},
+	}
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-azure-cluster",
+			Namespace: "default",
+		},
+		Spec: infrav1.AzureClusterSpec{
+			SubscriptionID: "123",
+			Location:       "westus2",
+			ResourceGroup:  "my-rg",
+			AdditionalTags: infrav1.Tags{"foo": "bar"},
+		},
+	}
+	azureCluster.Default()
+
+	initObjects := []runtime.Object{cluster, azureCluster}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+	clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+		Client:       fakeClient,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	specs := clusterScope.TagsSpecs()
+	var scopes []string
+	for _, spec := range specs {
+		g.Expect(spec.Tags).To(Equal(infrav1.Tags{"foo": "bar"}))
+		g.Expect(spec.Annotation).To(Equal(infrav1.ClusterTagsLastAppliedAnnotation))
+		scopes = append(scopes, spec.Scope)
+	}
+	g.Expect(scopes).To(ContainElements(
+		"/subscriptions/123/resourceGroups/my-rg",
+		"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/"+clusterScope.Vnet().Name,
+		"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/"+clusterScope.APIServerLBName(),
+	))
+
+	g.Expect(clusterScope.UpdateAnnotationJSON(infrav1.ClusterTagsLastAppliedAnnotation, map[string]interface{}{"foo": "bar"})).To(Succeed())
+	annotation, err := clusterScope.AnnotationJSON(infrav1.ClusterTagsLastAppliedAnnotation)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(annotation).To(Equal(map[string]interface{}{"foo": "bar"}))
+
+	g.Expect(clusterScope.AdoptionSpecs()).To(BeEmpty())
+	clusterScope.AzureCluster.Annotations[infrav1.AdoptResourcesAnnotation] = string(infrav1.ResourceLifecycleShared)
+	adoptionSpecs := clusterScope.AdoptionSpecs()
+	g.Expect(adoptionSpecs).To(HaveLen(len(specs)))
+	for i, spec := range adoptionSpecs {
+		g.Expect(spec.Scope).To(Equal(scopes[i]))
+		g.Expect(spec.Lifecycle).To(Equal(infrav1.ResourceLifecycleShared))
+	}
+}
+
+func TestClusterScopeNetworkResourceGroup(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster",
+			Namespace: "default",
+		},
+	}
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-azure-cluster",
+			Namespace: "default",
+		},
+		Spec: infrav1.AzureClusterSpec{
+			SubscriptionID: "123",
+			Location:       "westus2",
+			ResourceGroup:  "my-rg",
+		},
+	}
+	azureCluster.Default()
+
+	initObjects := []runtime.Object{cluster, azureCluster}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+	clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+		Client:       fakeClient,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(clusterScope.NetworkResourceGroup()).To(Equal("my-rg"))
+
+	clusterScope.AzureCluster.Spec.NetworkSpec.ResourceGroup = "my-network-rg"
+	g.Expect(clusterScope.NetworkResourceGroup()).To(Equal("my-network-rg"))
+	g.Expect(clusterScope.ResourceGroup()).To(Equal("my-rg"))
+
+	var scopes []string
+	for _, spec := range clusterScope.TagsSpecs() {
+		scopes = append(scopes, spec.Scope)
+	}
+	g.Expect(scopes).To(ContainElements(
+		"/subscriptions/123/resourceGroups/my-rg",
+		"/subscriptions/123/resourceGroups/my-network-rg/providers/Microsoft.Network/loadBalancers/"+clusterScope.APIServerLBName(),
+	))
+}
+
+func TestClusterScopeLoadBalancerNames(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster",
+			Namespace: "default",
+		},
+	}
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster",
+			Namespace: "default",
+		},
+		Spec: infrav1.AzureClusterSpec{
+			SubscriptionID: "123",
+			Location:       "westus2",
+			ResourceGroup:  "my-rg",
+			NetworkSpec: infrav1.NetworkSpec{
+				NodeOutboundLB: &infrav1.LoadBalancerSpec{
+					Name:        "my-node-outbound-lb",
+					BackendPool: infrav1.BackendPool{Name: "my-node-outbound-pool"},
+				},
+			},
+		},
+	}
+	azureCluster.Default()
+
+	initObjects := []runtime.Object{cluster, azureCluster}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+	clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+		Client:       fakeClient,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(clusterScope.APIServerLBName()).To(Equal("my-cluster-public-lb"))
+	g.Expect(clusterScope.APIServerLBPoolName(clusterScope.APIServerLBName())).To(Equal("my-cluster-public-lb-backendPool"))
+	g.Expect(clusterScope.OutboundLBName(infrav1.Node)).To(Equal("my-node-outbound-lb"))
+	g.Expect(clusterScope.OutboundPoolName("my-node-outbound-lb")).To(Equal("my-node-outbound-pool"))
+
+	clusterScope.AzureCluster.Spec.NetworkSpec.APIServerLB.BackendPool.Name = "my-apiserver-pool"
+	g.Expect(clusterScope.APIServerLBPoolName(clusterScope.APIServerLBName())).To(Equal("my-apiserver-pool"))
+
+	var poolNames []string
+	for _, spec := range clusterScope.LBSpecs() {
+		poolNames = append(poolNames, spec.BackendPoolName)
+	}
+	g.Expect(poolNames).To(Equal([]string{"my-apiserver-pool", "my-node-outbound-pool"}))
+}
+
+func TestClusterScopeExpectedResourceIDs(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster",
+			Namespace: "default",
+		},
+	}
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-azure-cluster",
+			Namespace: "default",
+		},
+		Spec: infrav1.AzureClusterSpec{
+			SubscriptionID: "123",
+			Location:       "westus2",
+			ResourceGroup:  "my-rg",
+			NetworkSpec: infrav1.NetworkSpec{
+				ResourceGroup: "my-network-rg",
+			},
+		},
+	}
+	azureCluster.Default()
+	azureMachine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-machine",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+		},
+		Spec: infrav1.AzureMachineSpec{
+			DataDisks: []infrav1.DataDisk{{NameSuffix: "etcddisk"}},
+		},
+	}
+	windowsAzureMachine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-windows-machine",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+		},
+		Spec: infrav1.AzureMachineSpec{
+			OSDisk: infrav1.OSDisk{OSType: azure.WindowsOS},
+		},
+	}
+	otherAzureMachine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-machine",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "other-cluster"},
+		},
+	}
+
+	initObjects := []runtime.Object{cluster, azureCluster, azureMachine, windowsAzureMachine, otherAzureMachine}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+	clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+		Client:       fakeClient,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ids, err := clusterScope.ExpectedResourceIDs(context.TODO())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ids).To(ContainElements(
+		"/subscriptions/123/resourceGroups/my-rg",
+		"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/my-machine-nic",
+		"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-machine_OSDisk",
+		"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-machine_etcddisk",
+		"/subscriptions/123/resourceGroups/my-network-rg/providers/Microsoft.Network/publicIPAddresses/pip-my-machine",
+		"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/my-window-chine-nic",
+		"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-window-chine_OSDisk",
+	))
+	for _, id := range ids {
+		g.Expect(id).NotTo(ContainSubstring("other-machine"))
+	}
+
+	g.Expect(clusterScope.LastGarbageCollection()).To(BeZero())
+	last := time.Date(2021, time.July, 1, 12, 0, 0, 0, time.UTC)
+	clusterScope.SetLastGarbageCollection(last)
+	g.Expect(clusterScope.AzureCluster.Annotations).To(HaveKeyWithValue(infrav1.LastGarbageCollectionAnnotation, "2021-07-01T12:00:00Z"))
+	g.Expect(clusterScope.LastGarbageCollection()).To(Equal(last))
+}
+
+func TestClusterScopeCostSpecs(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster",
+			Namespace: "default",
+		},
+	}
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-azure-cluster",
+			Namespace: "default",
+		},
+		Spec: infrav1.AzureClusterSpec{
+			SubscriptionID: "123",
+			Location:       "westus2",
+			ResourceGroup:  "my-rg",
+		},
+	}
+	azureCluster.Default()
+	azureMachine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-machine",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+		},
+		Spec: infrav1.AzureMachineSpec{
+			VMSize: "Standard_D2s_v3",
+			OSDisk: infrav1.OSDisk{
+				OSType:      "Linux",
+				DiskSizeGB:  to.Int32Ptr(128),
+				ManagedDisk: &infrav1.ManagedDiskParameters{StorageAccountType: "Premium_LRS"},
+			},
+			DataDisks:        []infrav1.DataDisk{{NameSuffix: "etcddisk", DiskSizeGB: 256}},
+			AllocatePublicIP: true,
+			SpotVMOptions:    &infrav1.SpotVMOptions{},
+		},
+	}
+	ephemeralAzureMachine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ephemeral-machine",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+		},
+		Spec: infrav1.AzureMachineSpec{
+			VMSize: "Standard_D4s_v3",
+			OSDisk: infrav1.OSDisk{
+				OSType:           "Windows",
+				DiffDiskSettings: &infrav1.DiffDiskSettings{Option: "Local"},
+			},
+		},
+	}
+	otherAzureMachine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-machine",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "other-cluster"},
+		},
+	}
+
+	initObjects := []runtime.Object{cluster, azureCluster, azureMachine, ephemeralAzureMachine, otherAzureMachine}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+	clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+		Client:       fakeClient,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	specs, err := clusterScope.CostSpecs(context.TODO())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(specs).To(ContainElements(
+		azure.CostSpec{Name: azureCluster.Spec.NetworkSpec.APIServerLB.Name, Type: azure.LoadBalancerResourceType, SKU: "Standard"},
+		azure.CostSpec{Name: azureCluster.Spec.NetworkSpec.APIServerLB.FrontendIPs[0].PublicIP.Name, Type: azure.PublicIPResourceType, SKU: "Standard"},
+		azure.CostSpec{Name: "my-machine", Type: azure.VirtualMachineResourceType, SKU: "Standard_D2s_v3", IsSpot: true},
+		azure.CostSpec{Name: "my-machine_OSDisk", Type: azure.DiskResourceType, SKU: "Premium_LRS", DiskSizeGB: 128},
+		azure.CostSpec{Name: "my-machine_etcddisk", Type: azure.DiskResourceType, SKU: "Standard_LRS", DiskSizeGB: 256},
+		azure.CostSpec{Name: "pip-my-machine", Type: azure.PublicIPResourceType, SKU: "Standard"},
+		azure.CostSpec{Name: "ephemeral-machine", Type: azure.VirtualMachineResourceType, SKU: "Standard_D4s_v3", IsWindows: true},
+	))
+	for _, spec := range specs {
+		g.Expect(spec.Name).NotTo(HavePrefix("other-machine"))
+		g.Expect(spec.Name).NotTo(Equal("ephemeral-machine_OSDisk"))
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// updatePutStatus sets the condition of obj reporting on the resources of service from the result of their creation or update.
+// The reason of a failure is the code of the ARM error it was caused by, if any.
+func updatePutStatus(obj conditions.Setter, condition clusterv1.ConditionType, service string, err error) {
+	switch {
+	case err == nil:
+		conditions.MarkTrue(obj, condition)
+	case azure.IsOperationNotDoneError(err):
+		conditions.MarkFalse(obj, condition, infrav1.CreatingReason, clusterv1.ConditionSeverityInfo, "%s creating or updating", service)
+	case azure.IsDryRunError(err):
+		conditions.MarkFalse(obj, condition, infrav1.DryRunReason, clusterv1.ConditionSeverityInfo, "%s not created or updated in dry-run mode", service)
+	case azure.IsQuotaExceededError(err):
+		conditions.MarkFalse(obj, condition, infrav1.QuotaExceededReason, clusterv1.ConditionSeverityWarning, "%s not created: %s", service, azure.ErrorMessage(err))
+	default:
+		conditions.MarkFalse(obj, condition, azure.FailureReason(err, infrav1.FailedReason), clusterv1.ConditionSeverityError, "%s failed to create or update: %s", service, azure.ErrorMessage(err))
+	}
+}
+
+// updateDeleteStatus sets the condition of obj reporting on the resources of service from the result of their deletion.
+// The reason of a failure is the code of the ARM error it was caused by, if any.
+func updateDeleteStatus(obj conditions.Setter, condition clusterv1.ConditionType, service string, err error) {
+	switch {
+	case err == nil || azure.ResourceNotFound(err):
+		conditions.MarkFalse(obj, condition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "%s deleted", service)
+	case azure.IsOperationNotDoneError(err):
+		conditions.MarkFalse(obj, condition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "%s deleting", service)
+	case azure.IsDryRunError(err):
+		conditions.MarkFalse(obj, condition, infrav1.DryRunReason, clusterv1.ConditionSeverityInfo, "%s not deleted in dry-run mode", service)
+	default:
+		conditions.MarkFalse(obj, condition, azure.FailureReason(err, clusterv1.DeletionFailedReason), clusterv1.ConditionSeverityWarning, "%s failed to delete: %s", service, azure.ErrorMessage(err))
+	}
+}
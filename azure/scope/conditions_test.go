@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	. "github.com/onsi/gomega"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+func TestUpdatePutStatus(t *testing.T) {
+	tests := []struct {
+		name             string
+		err              error
+		expectedStatus   bool
+		expectedReason   string
+		expectedSeverity clusterv1.ConditionSeverity
+	}{
+		{
+			name:           "resources created",
+			expectedStatus: true,
+		},
+		{
+			name:             "creation in progress",
+			err:              azure.NewOperationNotDoneError(&infrav1.Future{}),
+			expectedReason:   infrav1.CreatingReason,
+			expectedSeverity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			name:             "creation skipped in dry-run mode",
+			err:              azure.DryRunError{Method: http.MethodPut},
+			expectedReason:   infrav1.DryRunReason,
+			expectedSeverity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			name:             "creation would exceed a quota",
+			err:              azure.QuotaExceededError{Quota: "Public IP Addresses", Location: "eastus", Limit: 10, Usage: 10, Requested: 1},
+			expectedReason:   infrav1.QuotaExceededReason,
+			expectedSeverity: clusterv1.ConditionSeverityWarning,
+		},
+		{
+			name:             "creation failed",
+			err:              errors.New("boom"),
+			expectedReason:   infrav1.FailedReason,
+			expectedSeverity: clusterv1.ConditionSeverityError,
+		},
+		{
+			name:             "creation failed with an ARM error",
+			err:              autorest.DetailedError{Original: &azureautorest.ServiceError{Code: "InvalidParameter", Message: "boom"}},
+			expectedReason:   "InvalidParameter",
+			expectedSeverity: clusterv1.ConditionSeverityError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &ClusterScope{AzureCluster: &infrav1.AzureCluster{}}
+
+			s.UpdatePutStatus(infrav1.VNetReadyCondition, "virtual network", tt.err)
+
+			g.Expect(conditions.IsTrue(s.AzureCluster, infrav1.VNetReadyCondition)).To(Equal(tt.expectedStatus))
+			if !tt.expectedStatus {
+				g.Expect(conditions.GetReason(s.AzureCluster, infrav1.VNetReadyCondition)).To(Equal(tt.expectedReason))
+				g.Expect(*conditions.GetSeverity(s.AzureCluster, infrav1.VNetReadyCondition)).To(Equal(tt.expectedSeverity))
+			}
+		})
+	}
+}
+
+func TestUpdateDeleteStatus(t *testing.T) {
+	tests := []struct {
+		name             string
+		err              error
+		expectedReason   string
+		expectedSeverity clusterv1.ConditionSeverity
+	}{
+		{
+			name:             "resources deleted",
+			expectedReason:   clusterv1.DeletedReason,
+			expectedSeverity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			name:             "resources not found",
+			err:              autorest.DetailedError{StatusCode: http.StatusNotFound},
+			expectedReason:   clusterv1.DeletedReason,
+			expectedSeverity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			name:             "deletion in progress",
+			err:              azure.NewOperationNotDoneError(&infrav1.Future{}),
+			expectedReason:   clusterv1.DeletingReason,
+			expectedSeverity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			name:             "deletion skipped in dry-run mode",
+			err:              azure.DryRunError{Method: http.MethodDelete},
+			expectedReason:   infrav1.DryRunReason,
+			expectedSeverity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			name:             "deletion failed",
+			err:              errors.New("boom"),
+			expectedReason:   clusterv1.DeletionFailedReason,
+			expectedSeverity: clusterv1.ConditionSeverityWarning,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			m := &MachineScope{AzureMachine: &infrav1.AzureMachine{}}
+
+			m.UpdateDeleteStatus(infrav1.DisksReadyCondition, "disks", tt.err)
+
+			g.Expect(conditions.IsFalse(m.AzureMachine, infrav1.DisksReadyCondition)).To(BeTrue())
+			g.Expect(conditions.GetReason(m.AzureMachine, infrav1.DisksReadyCondition)).To(Equal(tt.expectedReason))
+			g.Expect(*conditions.GetSeverity(m.AzureMachine, infrav1.DisksReadyCondition)).To(Equal(tt.expectedSeverity))
+		})
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/Azure/go-autorest/autorest/adal"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/util/identity"
 	"sigs.k8s.io/cluster-api-provider-azure/util/system"
@@ -177,6 +178,9 @@ func (p *AzureCredentialsProvider) GetAuthorizer(ctx context.Context, resourceMa
 		if err != nil {
 			return nil, errors.Errorf("failed to get token from service principal identity: %v", err)
 		}
+		// The tokens of the other identity types are requested from the instance metadata endpoint, which isn't
+		// reachable through a proxy.
+		setHTTPSender(spt)
 
 	default:
 		return nil, errors.Errorf("identity type %s not supported", p.Identity.Spec.Type)
@@ -185,6 +189,14 @@ func (p *AzureCredentialsProvider) GetAuthorizer(ctx context.Context, resourceMa
 	return autorest.NewBearerAuthorizer(spt), nil
 }
 
+// setHTTPSender makes the token requests go through the proxy and trust the CA certificates configured for the
+// Azure requests, if any.
+func setHTTPSender(spt *adal.ServicePrincipalToken) {
+	if sender := azure.HTTPSender(); sender != nil {
+		spt.SetSender(sender)
+	}
+}
+
 // GetClientID returns the Client ID associated with the AzureCredentialsProvider's Identity.
 func (p *AzureCredentialsProvider) GetClientID() string {
 	return p.Identity.Spec.ClientID
@@ -18,11 +18,15 @@ package scope
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -39,6 +43,7 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
 )
 
 // MachineScopeParams defines the input parameters used to create a new MachineScope.
@@ -77,6 +82,7 @@ func NewMachineScope(params MachineScopeParams) (*MachineScope, error) {
 		Logger:        params.Logger,
 		patchHelper:   helper,
 		ClusterScoper: params.ClusterScope,
+		futuresMu:     &sync.Mutex{},
 	}, nil
 }
 
@@ -89,6 +95,8 @@ type MachineScope struct {
 	azure.ClusterScoper
 	Machine      *clusterv1.Machine
 	AzureMachine *infrav1.AzureMachine
+	// futuresMu guards the futures of the AzureMachine, as its resources are deleted concurrently.
+	futuresMu *sync.Mutex
 }
 
 // VMSpec returns the VM spec.
@@ -109,24 +117,42 @@ func (m *MachineScope) VMSpec() azure.VMSpec {
 	}
 }
 
-// TagsSpecs returns the tags for the AzureMachine.
+// TagsSpecs returns the tags for the AzureMachine and the resources attached to it.
 func (m *MachineScope) TagsSpecs() []azure.TagsSpec {
-	return []azure.TagsSpec{
-		{
-			Scope:      azure.VMID(m.SubscriptionID(), m.ResourceGroup(), m.Name()),
+	scopes := []string{azure.VMID(m.SubscriptionID(), m.ResourceGroup(), m.Name())}
+	for _, nicSpec := range m.NICSpecs() {
+		scopes = append(scopes, azure.NetworkInterfaceID(m.SubscriptionID(), m.ResourceGroup(), nicSpec.Name))
+	}
+	for _, diskSpec := range m.DiskSpecs() {
+		scopes = append(scopes, azure.DiskID(m.SubscriptionID(), m.ResourceGroup(), diskSpec.Name))
+	}
+	for _, publicIPSpec := range m.PublicIPSpecs() {
+		scopes = append(scopes, azure.PublicIPID(m.SubscriptionID(), m.NetworkResourceGroup(), publicIPSpec.Name))
+	}
+
+	specs := make([]azure.TagsSpec, len(scopes))
+	for i, scope := range scopes {
+		specs[i] = azure.TagsSpec{
+			Scope:      scope,
 			Tags:       m.AdditionalTags(),
 			Annotation: infrav1.VMTagsLastAppliedAnnotation,
-		},
+		}
 	}
+	return specs
 }
 
 // PublicIPSpecs returns the public IP specs.
 func (m *MachineScope) PublicIPSpecs() []azure.PublicIPSpec {
 	var spec []azure.PublicIPSpec
 	if m.AzureMachine.Spec.AllocatePublicIP {
-		spec = append(spec, azure.PublicIPSpec{
+		publicIP := azure.PublicIPSpec{
 			Name: azure.GenerateNodePublicIPName(m.Name()),
-		})
+		}
+		// Keep the instance public IP in the same zone as the VM, a zonal VM can't use an IP from another zone.
+		if zone := m.AvailabilityZone(); zone != "" {
+			publicIP.Zones = []string{zone}
+		}
+		spec = append(spec, publicIP)
 	}
 	return spec
 }
@@ -141,9 +167,34 @@ func (m *MachineScope) InboundNatSpecs() []azure.InboundNatSpec {
 			},
 		}
 	}
+	if m.AzureMachine.Spec.EnableRDP {
+		return []azure.InboundNatSpec{
+			{
+				Name:             azure.GenerateRDPNATRuleName(m.Name()),
+				LoadBalancerName: m.OutboundLBName(infrav1.Node),
+				BackendPort:      azure.RDPPort,
+			},
+		}
+	}
 	return []azure.InboundNatSpec{}
 }
 
+// ValidateRDP returns a terminal error if Remote Desktop access is enabled on a machine which can't get it, as its RDP
+// port is forwarded by the node outbound load balancer: control plane machines, and the machines of clusters without
+// a node outbound load balancer, e.g. private clusters.
+func (m *MachineScope) ValidateRDP() error {
+	if !m.AzureMachine.Spec.EnableRDP {
+		return nil
+	}
+	if m.Role() == infrav1.ControlPlane {
+		return azure.WithTerminalError(errors.New("remote desktop access cannot be enabled on control plane machines"))
+	}
+	if m.NodeOutboundLB() == nil {
+		return azure.WithTerminalError(errors.New("remote desktop access requires a node outbound load balancer, which the cluster doesn't have"))
+	}
+	return nil
+}
+
 // NICSpecs returns the network interface specs.
 func (m *MachineScope) NICSpecs() []azure.NICSpec {
 	spec := azure.NICSpec{
@@ -174,6 +225,10 @@ func (m *MachineScope) NICSpecs() []azure.NICSpec {
 		spec.PublicLBName = m.OutboundLBName(m.Role())
 		spec.PublicLBAddressPoolName = m.OutboundPoolName(m.OutboundLBName(m.Role()))
 	}
+	if m.Role() == infrav1.Node && m.AzureMachine.Spec.EnableRDP {
+		spec.PublicLBName = m.OutboundLBName(m.Role())
+		spec.PublicLBNATRuleName = azure.GenerateRDPNATRuleName(m.Name())
+	}
 	specs := []azure.NICSpec{spec}
 	if m.AzureMachine.Spec.AllocatePublicIP {
 		specs = append(specs, azure.NICSpec{
@@ -228,23 +283,64 @@ func (m *MachineScope) RoleAssignmentSpecs() []azure.RoleAssignmentSpec {
 	return []azure.RoleAssignmentSpec{}
 }
 
-// VMExtensionSpecs returns the vm extension specs.
-func (m *MachineScope) VMExtensionSpecs() []azure.VMExtensionSpec {
+// BackupSpec returns the backup protection spec of the VM of a control plane machine with a backup policy.
+func (m *MachineScope) BackupSpec() *azure.BackupSpec {
+	backup := m.AzureMachine.Spec.Backup
+	if backup == nil || !m.IsControlPlane() {
+		return nil
+	}
+
+	vaultResourceGroup := backup.VaultResourceGroup
+	if vaultResourceGroup == "" {
+		vaultResourceGroup = m.ResourceGroup()
+	}
+	return &azure.BackupSpec{
+		VMName:             m.Name(),
+		VaultResourceGroup: vaultResourceGroup,
+		VaultName:          backup.VaultName,
+		PolicyName:         backup.PolicyName,
+	}
+}
+
+// VMExtensionSpecs returns the vm extension specs, which are created one at a time and in order as Azure doesn't allow
+// concurrent operations on the extensions of a VM. The monitoring agent comes after the bootstrap extension.
+func (m *MachineScope) VMExtensionSpecs(ctx context.Context) ([]azure.VMExtensionSpec, error) {
+	extensionSpecs := []azure.VMExtensionSpec{}
 	name, publisher, version := azure.GetBootstrappingVMExtension(m.AzureMachine.Spec.OSDisk.OSType, m.CloudEnvironment())
+	// Windows VMs only get the bootstrap extension when they were created with it, see WindowsBootstrapExtensionAnnotation.
+	if m.AzureMachine.Spec.OSDisk.OSType == azure.WindowsOS && m.AzureMachine.Annotations[azure.WindowsBootstrapExtensionAnnotation] != "true" {
+		name = ""
+	}
 	if name != "" {
-		return []azure.VMExtensionSpec{
-			{
-				Name:      name,
-				VMName:    m.Name(),
-				Publisher: publisher,
-				Version:   version,
-				ProtectedSettings: map[string]string{
-					"commandToExecute": azure.BootstrapExtensionCommand(),
-				},
+		extensionSpecs = append(extensionSpecs, azure.VMExtensionSpec{
+			Name:      name,
+			VMName:    m.Name(),
+			Publisher: publisher,
+			Version:   version,
+			ProtectedSettings: map[string]string{
+				"commandToExecute": azure.BootstrapExtensionCommand(m.AzureMachine.Spec.OSDisk.OSType),
 			},
+		})
+	}
+
+	if monitor := m.AzureMachine.Spec.AzureMonitor; monitor != nil {
+		settings, protectedSettings, err := monitoringAgentSettings(ctx, m.client, m.AzureMachine.Namespace, monitor)
+		if err != nil {
+			return nil, err
 		}
+		name, publisher, version := azure.GetMonitoringVMExtension(monitor.Agent, m.AzureMachine.Spec.OSDisk.OSType)
+		extensionSpecs = append(extensionSpecs, azure.VMExtensionSpec{
+			Name:                    name,
+			VMName:                  m.Name(),
+			Publisher:               publisher,
+			Version:                 version,
+			Settings:                settings,
+			ProtectedSettings:       protectedSettings,
+			AutoUpgradeMinorVersion: true,
+			SkipBootstrapConditions: true,
+		})
 	}
-	return []azure.VMExtensionSpec{}
+	return extensionSpecs, nil
 }
 
 // Subnet returns the machine's subnet.
@@ -275,16 +371,94 @@ func (m *MachineScope) AvailabilityZone() string {
 	return ""
 }
 
+// AssignFailureDomain picks a failure domain for a MachineDeployment machine that doesn't have one yet, so that
+// machines get spread across zones instead of being created without a zone. The zone with the fewest machines of
+// the same MachineDeployment is chosen; ties are broken by a hash of the AzureMachine name, which keeps the choice
+// deterministic while still spreading machines that are created at the same time.
+// The zone is set on AzureMachine.Spec.FailureDomain, from where Cluster API copies it to the Machine.
+func (m *MachineScope) AssignFailureDomain(ctx context.Context, failureDomains clusterv1.FailureDomains) error {
+	if len(failureDomains) == 0 || m.AvailabilityZone() != "" || m.ProviderID() != "" || m.IsControlPlane() {
+		return nil
+	}
+	mdName, ok := m.Machine.Labels[clusterv1.MachineDeploymentLabelName]
+	if !ok {
+		return nil
+	}
+
+	machines := &clusterv1.MachineList{}
+	if err := m.client.List(ctx, machines, client.InNamespace(m.Machine.Namespace), client.MatchingLabels{
+		clusterv1.ClusterLabelName:           m.ClusterName(),
+		clusterv1.MachineDeploymentLabelName: mdName,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to list machines of machine deployment %s", mdName)
+	}
+
+	// Machines only get their failure domain copied from the AzureMachine once Cluster API reconciled them,
+	// so look at the AzureMachines too to account for machines that were just assigned a zone.
+	azureMachines := &infrav1.AzureMachineList{}
+	if err := m.client.List(ctx, azureMachines, client.InNamespace(m.Machine.Namespace), client.MatchingLabels{
+		clusterv1.ClusterLabelName: m.ClusterName(),
+	}); err != nil {
+		return errors.Wrap(err, "failed to list azure machines")
+	}
+	azureMachineZones := make(map[string]string, len(azureMachines.Items))
+	for _, azureMachine := range azureMachines.Items {
+		if azureMachine.Spec.FailureDomain != nil {
+			azureMachineZones[azureMachine.Name] = *azureMachine.Spec.FailureDomain
+		}
+	}
+
+	machinesPerZone := make(map[string]int, len(failureDomains))
+	for zone := range failureDomains {
+		machinesPerZone[zone] = 0
+	}
+	for _, machine := range machines.Items {
+		if machine.Name == m.Machine.Name {
+			continue
+		}
+		zone := azureMachineZones[machine.Spec.InfrastructureRef.Name]
+		if machine.Spec.FailureDomain != nil {
+			zone = *machine.Spec.FailureDomain
+		}
+		if _, ok := machinesPerZone[zone]; ok {
+			machinesPerZone[zone]++
+		}
+	}
+
+	var leastLoaded []string
+	for zone, count := range machinesPerZone {
+		if len(leastLoaded) == 0 || count < machinesPerZone[leastLoaded[0]] {
+			leastLoaded = []string{zone}
+		} else if count == machinesPerZone[leastLoaded[0]] {
+			leastLoaded = append(leastLoaded, zone)
+		}
+	}
+	sort.Strings(leastLoaded)
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(m.AzureMachine.Name))
+	zone := leastLoaded[int(h.Sum32()%uint32(len(leastLoaded)))]
+
+	m.V(2).Info("assigning failure domain", "failureDomain", zone, "machineDeployment", mdName)
+	m.AzureMachine.Spec.FailureDomain = to.StringPtr(zone)
+	return nil
+}
+
 // Name returns the AzureMachine name.
 func (m *MachineScope) Name() string {
 	if id := m.GetVMID(); id != "" {
 		return id
 	}
+	return vmName(m.AzureMachine)
+}
+
+// vmName returns the name of the VM of an AzureMachine.
+func vmName(azureMachine *infrav1.AzureMachine) string {
 	// Windows Machine names cannot be longer than 15 chars
-	if m.AzureMachine.Spec.OSDisk.OSType == azure.WindowsOS && len(m.AzureMachine.Name) > 15 {
-		return strings.TrimSuffix(m.AzureMachine.Name[0:9], "-") + "-" + m.AzureMachine.Name[len(m.AzureMachine.Name)-5:]
+	if azureMachine.Spec.OSDisk.OSType == azure.WindowsOS && len(azureMachine.Name) > 15 {
+		return strings.TrimSuffix(azureMachine.Name[0:9], "-") + "-" + azureMachine.Name[len(azureMachine.Name)-5:]
 	}
-	return m.AzureMachine.Name
+	return azureMachine.Name
 }
 
 // Namespace returns the namespace name.
@@ -369,9 +543,9 @@ func (m *MachineScope) SetNotReady() {
 	m.AzureMachine.Status.Ready = false
 }
 
-// SetFailureMessage sets the AzureMachine status failure message.
+// SetFailureMessage sets the AzureMachine status failure message, along with the IDs of the failed Azure API request, if any.
 func (m *MachineScope) SetFailureMessage(v error) {
-	m.AzureMachine.Status.FailureMessage = to.StringPtr(v.Error())
+	m.AzureMachine.Status.FailureMessage = to.StringPtr(azure.ErrorMessage(v))
 }
 
 // SetFailureReason sets the AzureMachine status failure reason.
@@ -379,6 +553,22 @@ func (m *MachineScope) SetFailureReason(v capierrors.MachineStatusError) {
 	m.AzureMachine.Status.FailureReason = &v
 }
 
+// SetLongRunningOperationState will set the future on the AzureMachine status to allow the resource to continue
+// reconciliation without blocking on the long-running operation.
+func (m *MachineScope) SetLongRunningOperationState(future *infrav1.Future) {
+	futures.Set(m.futuresMu, &m.AzureMachine.Status.LongRunningOperationStates, future)
+}
+
+// GetLongRunningOperationState will get the future on the AzureMachine status for the named resource of a service.
+func (m *MachineScope) GetLongRunningOperationState(name, service string) *infrav1.Future {
+	return futures.Get(m.futuresMu, &m.AzureMachine.Status.LongRunningOperationStates, name, service)
+}
+
+// DeleteLongRunningOperationState will delete the future from the AzureMachine status for the named resource of a service.
+func (m *MachineScope) DeleteLongRunningOperationState(name, service string) {
+	futures.Delete(m.futuresMu, &m.AzureMachine.Status.LongRunningOperationStates, name, service)
+}
+
 // SetBootstrapConditions sets the AzureMachine BootstrapSucceeded condition based on the extension provisioning states.
 func (m *MachineScope) SetBootstrapConditions(provisioningState string, extensionName string) error {
 	switch infrav1.ProvisioningState(provisioningState) {
@@ -470,6 +660,12 @@ func (m *MachineScope) PatchObject(ctx context.Context) error {
 	conditions.SetSummary(m.AzureMachine,
 		conditions.WithConditions(
 			infrav1.VMRunningCondition,
+			infrav1.PublicIPsReadyCondition,
+			infrav1.InboundNATRulesReadyCondition,
+			infrav1.NetworkInterfacesReadyCondition,
+			infrav1.AvailabilitySetReadyCondition,
+			infrav1.DisksReadyCondition,
+			infrav1.VMExtensionsReadyCondition,
 		),
 		conditions.WithStepCounterIfOnly(
 			infrav1.VMRunningCondition,
@@ -482,9 +678,26 @@ func (m *MachineScope) PatchObject(ctx context.Context) error {
 		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 			clusterv1.ReadyCondition,
 			infrav1.VMRunningCondition,
+			infrav1.PublicIPsReadyCondition,
+			infrav1.InboundNATRulesReadyCondition,
+			infrav1.NetworkInterfacesReadyCondition,
+			infrav1.AvailabilitySetReadyCondition,
+			infrav1.DisksReadyCondition,
+			infrav1.VMExtensionsReadyCondition,
+			infrav1.VMBackupReadyCondition,
 		}})
 }
 
+// UpdatePutStatus sets the condition of the AzureMachine reporting on the resources of service from the result of their creation or update.
+func (m *MachineScope) UpdatePutStatus(condition clusterv1.ConditionType, service string, err error) {
+	updatePutStatus(m.AzureMachine, condition, service, err)
+}
+
+// UpdateDeleteStatus sets the condition of the AzureMachine reporting on the resources of service from the result of their deletion.
+func (m *MachineScope) UpdateDeleteStatus(condition clusterv1.ConditionType, service string, err error) {
+	updateDeleteStatus(m.AzureMachine, condition, service, err)
+}
+
 // Close the MachineScope by updating the machine spec, machine status.
 func (m *MachineScope) Close(ctx context.Context) error {
 	return m.PatchObject(ctx)
@@ -504,22 +717,105 @@ func (m *MachineScope) AdditionalTags() infrav1.Tags {
 	return tags
 }
 
-// GetBootstrapData returns the bootstrap data from the secret in the Machine's bootstrap.dataSecretName.
+// GetBootstrapData returns the custom data of the VM: the bootstrap data from the secret in the Machine's
+// bootstrap.dataSecretName, or a cloud-init config fetching it from Key Vault if the AzureMachine has a bootstrap data
+// Key Vault.
 func (m *MachineScope) GetBootstrapData(ctx context.Context) (string, error) {
+	if keyVault := m.AzureMachine.Spec.BootstrapDataKeyVault; keyVault != nil {
+		describer, err := m.keyVaultDescriber()
+		if err != nil {
+			return "", err
+		}
+		secret, err := m.BootstrapDataSecretSpec()
+		if err != nil {
+			return "", err
+		}
+		return bootstrapDataFetcher(*secret, describer.KeyVaultResource(), keyVault.Identity.ProviderID), nil
+	}
+
+	secret, err := m.getBootstrapDataSecret(ctx)
+	if err != nil {
+		return "", err
+	}
+	return customData(m.AzureMachine.Spec.OSDisk, secret)
+}
+
+// GetBootstrapDataSecretValue returns the bootstrap data from the secret in the Machine's bootstrap.dataSecretName, as
+// the value of the Key Vault secret the machine fetches it from.
+func (m *MachineScope) GetBootstrapDataSecretValue(ctx context.Context) (string, error) {
+	secret, err := m.getBootstrapDataSecret(ctx)
+	if err != nil {
+		return "", err
+	}
+	value, err := bootstrapData(m.AzureMachine.Spec.OSDisk, secret)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// getBootstrapDataSecret returns the secret in the Machine's bootstrap.dataSecretName.
+func (m *MachineScope) getBootstrapDataSecret(ctx context.Context) (*corev1.Secret, error) {
 	if m.Machine.Spec.Bootstrap.DataSecretName == nil {
-		return "", errors.New("error retrieving bootstrap data: linked Machine's bootstrap.dataSecretName is nil")
+		return nil, errors.New("error retrieving bootstrap data: linked Machine's bootstrap.dataSecretName is nil")
 	}
 	secret := &corev1.Secret{}
 	key := types.NamespacedName{Namespace: m.Namespace(), Name: *m.Machine.Spec.Bootstrap.DataSecretName}
 	if err := m.client.Get(ctx, key, secret); err != nil {
-		return "", errors.Wrapf(err, "failed to retrieve bootstrap data secret for AzureMachine %s/%s", m.Namespace(), m.Name())
+		return nil, errors.Wrapf(err, "failed to retrieve bootstrap data secret for AzureMachine %s/%s", m.Namespace(), m.Name())
+	}
+	return secret, nil
+}
+
+// BootstrapDataSecretSpec returns the Key Vault secret storing the bootstrap data of the machine, or nil if its
+// bootstrap data is passed in the custom data of the VM.
+func (m *MachineScope) BootstrapDataSecretSpec() (*azure.BootstrapDataSecretSpec, error) {
+	keyVault := m.AzureMachine.Spec.BootstrapDataKeyVault
+	if keyVault == nil {
+		return nil, nil
+	}
+	describer, err := m.keyVaultDescriber()
+	if err != nil {
+		return nil, err
 	}
+	return &azure.BootstrapDataSecretSpec{
+		Name:     bootstrapDataSecretName(m.AzureMachine.UID),
+		VaultURL: fmt.Sprintf("https://%s.%s", keyVault.VaultName, describer.KeyVaultDNSSuffix()),
+		Consumed: m.Machine.Status.NodeRef != nil,
+		State:    bootstrapDataSecretState(m.AzureMachine.Status.BootstrapDataSecretState),
+	}, nil
+}
+
+// bootstrapDataSecretState returns the recorded state of the bootstrap data secret of a machine, or an empty state.
+func bootstrapDataSecretState(state *infrav1.ProvisioningState) infrav1.ProvisioningState {
+	if state == nil {
+		return ""
+	}
+	return *state
+}
+
+// SetBootstrapDataSecretState records the state of the Key Vault secret storing the bootstrap data of the machine.
+func (m *MachineScope) SetBootstrapDataSecretState(state infrav1.ProvisioningState) {
+	m.AzureMachine.Status.BootstrapDataSecretState = &state
+}
+
+// KeyVaultAuthorizer returns an authorizer for the requests to the data plane of Azure Key Vault.
+func (m *MachineScope) KeyVaultAuthorizer(ctx context.Context) (autorest.Authorizer, error) {
+	describer, err := m.keyVaultDescriber()
+	if err != nil {
+		return nil, err
+	}
+	return describer.KeyVaultAuthorizer(ctx)
+}
 
-	value, ok := secret.Data["value"]
+// keyVaultDescriber returns the cluster scope of the machine as a describer of the Azure Key Vaults of its cloud
+// environment.
+func (m *MachineScope) keyVaultDescriber() (azure.KeyVaultDescriber, error) {
+	describer, ok := m.ClusterScoper.(azure.KeyVaultDescriber)
 	if !ok {
-		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
+		return nil, errors.Errorf("the scope of cluster %s does not support Azure Key Vault", m.ClusterName())
 	}
-	return base64.StdEncoding.EncodeToString(value), nil
+	return describer, nil
 }
 
 // GetVMImage returns the image from the machine configuration, or a default one.
@@ -535,7 +831,7 @@ func (m *MachineScope) GetVMImage() (*infrav1.Image, error) {
 	}
 
 	m.Info("No image specified for machine, using default Linux Image", "machine", m.AzureMachine.GetName())
-	return azure.GetDefaultUbuntuImage(to.String(m.Machine.Spec.Version))
+	return azure.GetDefaultLinuxImage(m.AzureMachine.Spec.OSDisk.Distribution, to.String(m.Machine.Spec.Version))
 }
 
 // SetSubnetName defaults the AzureMachine subnet name to the name of one the subnets with the machine role when there is only one of them.
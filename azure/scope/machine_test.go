@@ -17,14 +17,23 @@ limitations under the License.
 package scope
 
 import (
+	"context"
 	"testing"
 
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/Azure/go-autorest/autorest/to"
-
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2/klogr"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestMachineScope_Name(t *testing.T) {
@@ -223,3 +232,732 @@ func TestMachineScope_ProviderID(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineScope_AssignFailureDomain(t *testing.T) {
+	failureDomains := clusterv1.FailureDomains{
+		"1": clusterv1.FailureDomainSpec{},
+		"2": clusterv1.FailureDomainSpec{},
+		"3": clusterv1.FailureDomainSpec{},
+	}
+	newMachine := func(name string, failureDomain *string, labels map[string]string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    labels,
+			},
+			Spec: clusterv1.MachineSpec{
+				ClusterName:   "my-cluster",
+				FailureDomain: failureDomain,
+				InfrastructureRef: corev1.ObjectReference{
+					Name: name,
+				},
+			},
+		}
+	}
+	newAzureMachine := func(name string, failureDomain *string) *infrav1.AzureMachine {
+		return &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName: "my-cluster",
+				},
+			},
+			Spec: infrav1.AzureMachineSpec{
+				FailureDomain: failureDomain,
+			},
+		}
+	}
+	mdLabels := func(md string) map[string]string {
+		return map[string]string{
+			clusterv1.ClusterLabelName:           "my-cluster",
+			clusterv1.MachineDeploymentLabelName: md,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		machine        *clusterv1.Machine
+		azureMachine   *infrav1.AzureMachine
+		failureDomains clusterv1.FailureDomains
+		objects        []runtime.Object
+		want           []string
+	}{
+		{
+			name:           "keeps the failure domain set on the machine",
+			machine:        newMachine("machine", to.StringPtr("2"), mdLabels("md")),
+			azureMachine:   newAzureMachine("machine", nil),
+			failureDomains: failureDomains,
+			want:           nil,
+		},
+		{
+			name:           "does not assign a failure domain to control plane machines",
+			machine:        newMachine("machine", nil, map[string]string{clusterv1.MachineControlPlaneLabelName: ""}),
+			azureMachine:   newAzureMachine("machine", nil),
+			failureDomains: failureDomains,
+			want:           nil,
+		},
+		{
+			name:           "does not assign a failure domain to machines without a machine deployment",
+			machine:        newMachine("machine", nil, map[string]string{clusterv1.ClusterLabelName: "my-cluster"}),
+			azureMachine:   newAzureMachine("machine", nil),
+			failureDomains: failureDomains,
+			want:           nil,
+		},
+		{
+			name:           "does not assign a failure domain when the location has no zones",
+			machine:        newMachine("machine", nil, mdLabels("md")),
+			azureMachine:   newAzureMachine("machine", nil),
+			failureDomains: clusterv1.FailureDomains{},
+			want:           nil,
+		},
+		{
+			name:           "picks the least loaded zone",
+			machine:        newMachine("machine", nil, mdLabels("md")),
+			azureMachine:   newAzureMachine("machine", nil),
+			failureDomains: failureDomains,
+			objects: []runtime.Object{
+				newMachine("machine-a", to.StringPtr("1"), mdLabels("md")),
+				newMachine("machine-b", to.StringPtr("3"), mdLabels("md")),
+				newMachine("machine-c", to.StringPtr("2"), mdLabels("other-md")),
+			},
+			want: []string{"2"},
+		},
+		{
+			name:           "counts zones assigned to azure machines not yet copied to the machine",
+			machine:        newMachine("machine", nil, mdLabels("md")),
+			azureMachine:   newAzureMachine("machine", nil),
+			failureDomains: failureDomains,
+			objects: []runtime.Object{
+				newMachine("machine-a", to.StringPtr("1"), mdLabels("md")),
+				newMachine("machine-b", nil, mdLabels("md")),
+				newAzureMachine("machine-b", to.StringPtr("2")),
+			},
+			want: []string{"3"},
+		},
+		{
+			name:           "ignores zones that are not failure domains",
+			machine:        newMachine("machine", nil, mdLabels("md")),
+			azureMachine:   newAzureMachine("machine", nil),
+			failureDomains: clusterv1.FailureDomains{"1": clusterv1.FailureDomainSpec{}, "2": clusterv1.FailureDomainSpec{}},
+			objects: []runtime.Object{
+				newMachine("machine-a", to.StringPtr("1"), mdLabels("md")),
+				newMachine("machine-b", to.StringPtr("3"), mdLabels("md")),
+			},
+			want: []string{"2"},
+		},
+		{
+			name:           "picks one of the tied zones",
+			machine:        newMachine("machine", nil, mdLabels("md")),
+			azureMachine:   newAzureMachine("machine", nil),
+			failureDomains: failureDomains,
+			objects: []runtime.Object{
+				newMachine("machine-a", to.StringPtr("1"), mdLabels("md")),
+			},
+			want: []string{"2", "3"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = clusterv1.AddToScheme(scheme)
+			_ = infrav1.AddToScheme(scheme)
+
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tc.objects...).Build()
+			machineScope := &MachineScope{
+				Logger: klogr.New(),
+				client: fakeClient,
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "my-cluster",
+							Namespace: "default",
+						},
+					},
+				},
+				Machine:      tc.machine,
+				AzureMachine: tc.azureMachine,
+			}
+
+			g.Expect(machineScope.AssignFailureDomain(context.TODO(), tc.failureDomains)).To(Succeed())
+			if tc.want == nil {
+				g.Expect(machineScope.AzureMachine.Spec.FailureDomain).To(BeNil())
+				return
+			}
+			g.Expect(machineScope.AzureMachine.Spec.FailureDomain).NotTo(BeNil())
+			g.Expect(tc.want).To(ContainElement(*machineScope.AzureMachine.Spec.FailureDomain))
+		})
+	}
+}
+
+func TestMachineScope_PublicIPSpecs(t *testing.T) {
+	tests := []struct {
+		name         string
+		machineScope MachineScope
+		want         []azure.PublicIPSpec
+	}{
+		{
+			name: "returns nothing if no public IP is allocated",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "returns a public IP without zones for a machine without a zone",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						AllocatePublicIP: true,
+					},
+				},
+			},
+			want: []azure.PublicIPSpec{
+				{
+					Name: "pip-machine-name",
+				},
+			},
+		},
+		{
+			name: "returns a public IP in the zone of the machine",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{
+					Spec: clusterv1.MachineSpec{
+						FailureDomain: to.StringPtr("2"),
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						AllocatePublicIP: true,
+					},
+				},
+			},
+			want: []azure.PublicIPSpec{
+				{
+					Name:  "pip-machine-name",
+					Zones: []string{"2"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(tt.machineScope.PublicIPSpecs()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachineScope_InboundNatSpecs(t *testing.T) {
+	clusterScope := &ClusterScope{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-cluster",
+			},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				NetworkSpec: infrav1.NetworkSpec{
+					APIServerLB: infrav1.LoadBalancerSpec{
+						Name: "my-cluster-public-lb",
+					},
+					NodeOutboundLB: &infrav1.LoadBalancerSpec{
+						Name: "my-cluster-node-lb",
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		machineScope MachineScope
+		want         []azure.InboundNatSpec
+	}{
+		{
+			name: "returns an SSH rule on the API server load balancer for a control plane machine",
+			machineScope: MachineScope{
+				ClusterScoper: clusterScope,
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							clusterv1.MachineControlPlaneLabelName: "true",
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+				},
+			},
+			want: []azure.InboundNatSpec{
+				{
+					Name:             "machine-name",
+					LoadBalancerName: "my-cluster-public-lb",
+				},
+			},
+		},
+		{
+			name: "returns nothing for a node",
+			machineScope: MachineScope{
+				ClusterScoper: clusterScope,
+				Machine:       &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+				},
+			},
+			want: []azure.InboundNatSpec{},
+		},
+		{
+			name: "returns an RDP rule on the node outbound load balancer for a Windows node with RDP enabled",
+			machineScope: MachineScope{
+				ClusterScoper: clusterScope,
+				Machine:       &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "win-node",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							OSType: azure.WindowsOS,
+						},
+						EnableRDP: true,
+					},
+				},
+			},
+			want: []azure.InboundNatSpec{
+				{
+					Name:             "win-node-rdp",
+					LoadBalancerName: "my-cluster-node-lb",
+					BackendPort:      azure.RDPPort,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(tt.machineScope.InboundNatSpecs()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachineScope_ValidateRDP(t *testing.T) {
+	newClusterScope := func(nodeOutboundLB *infrav1.LoadBalancerSpec) *ClusterScope {
+		return &ClusterScope{
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-cluster",
+				},
+			},
+			AzureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						NodeOutboundLB: nodeOutboundLB,
+					},
+				},
+			},
+		}
+	}
+	windowsMachine := func(enableRDP bool) *infrav1.AzureMachine {
+		return &infrav1.AzureMachine{
+			Spec: infrav1.AzureMachineSpec{
+				OSDisk: infrav1.OSDisk{
+					OSType: azure.WindowsOS,
+				},
+				EnableRDP: enableRDP,
+			},
+		}
+	}
+	controlPlaneMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				clusterv1.MachineControlPlaneLabelName: "true",
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		machineScope MachineScope
+		wantErr      bool
+	}{
+		{
+			name: "accepts a machine without RDP",
+			machineScope: MachineScope{
+				ClusterScoper: newClusterScope(nil),
+				Machine:       &clusterv1.Machine{},
+				AzureMachine:  windowsMachine(false),
+			},
+		},
+		{
+			name: "accepts a node with RDP in a cluster with a node outbound load balancer",
+			machineScope: MachineScope{
+				ClusterScoper: newClusterScope(&infrav1.LoadBalancerSpec{Name: "my-cluster-node-lb"}),
+				Machine:       &clusterv1.Machine{},
+				AzureMachine:  windowsMachine(true),
+			},
+		},
+		{
+			name: "rejects a node with RDP in a cluster without a node outbound load balancer",
+			machineScope: MachineScope{
+				ClusterScoper: newClusterScope(nil),
+				Machine:       &clusterv1.Machine{},
+				AzureMachine:  windowsMachine(true),
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects a control plane machine with RDP",
+			machineScope: MachineScope{
+				ClusterScoper: newClusterScope(&infrav1.LoadBalancerSpec{Name: "my-cluster-node-lb"}),
+				Machine:       controlPlaneMachine,
+				AzureMachine:  windowsMachine(true),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := tt.machineScope.ValidateRDP()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				var reconcileError azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+				g.Expect(reconcileError.IsTerminal()).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestMachineScope_BootstrapDataSecretSpec(t *testing.T) {
+	clusterScope := &ClusterScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Environment: azureautorest.USGovernmentCloud,
+			},
+		},
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-cluster",
+			},
+		},
+	}
+	created := infrav1.Succeeded
+
+	tests := []struct {
+		name         string
+		machineScope MachineScope
+		want         *azure.BootstrapDataSecretSpec
+	}{
+		{
+			name: "returns nothing without a bootstrap data Key Vault",
+			machineScope: MachineScope{
+				ClusterScoper: clusterScope,
+				Machine:       &clusterv1.Machine{},
+				AzureMachine:  &infrav1.AzureMachine{},
+			},
+			want: nil,
+		},
+		{
+			name: "returns the secret of the machine in the Key Vault of the cloud environment",
+			machineScope: MachineScope{
+				ClusterScoper: clusterScope,
+				Machine:       &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						UID: "1234",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						BootstrapDataKeyVault: &infrav1.BootstrapDataKeyVault{
+							VaultName: "my-vault",
+						},
+					},
+				},
+			},
+			want: &azure.BootstrapDataSecretSpec{
+				Name:     "bootstrap-data-1234",
+				VaultURL: "https://my-vault.vault.usgovcloudapi.net",
+			},
+		},
+		{
+			name: "the secret is consumed once the node has joined the cluster",
+			machineScope: MachineScope{
+				ClusterScoper: clusterScope,
+				Machine: &clusterv1.Machine{
+					Status: clusterv1.MachineStatus{
+						NodeRef: &corev1.ObjectReference{Name: "my-node"},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						UID: "1234",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						BootstrapDataKeyVault: &infrav1.BootstrapDataKeyVault{
+							VaultName: "my-vault",
+						},
+					},
+					Status: infrav1.AzureMachineStatus{
+						BootstrapDataSecretState: &created,
+					},
+				},
+			},
+			want: &azure.BootstrapDataSecretSpec{
+				Name:     "bootstrap-data-1234",
+				VaultURL: "https://my-vault.vault.usgovcloudapi.net",
+				Consumed: true,
+				State:    infrav1.Succeeded,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := tt.machineScope.BootstrapDataSecretSpec()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachineScope_VMExtensionSpecs(t *testing.T) {
+	clusterScope := &ClusterScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Environment: azureautorest.PublicCloud,
+			},
+		},
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-cluster",
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		azureMachine *infrav1.AzureMachine
+		want         []azure.VMExtensionSpec
+	}{
+		{
+			name: "returns the bootstrap extension",
+			azureMachine: &infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "machine-name",
+				},
+				Spec: infrav1.AzureMachineSpec{
+					OSDisk: infrav1.OSDisk{OSType: "Linux"},
+				},
+			},
+			want: []azure.VMExtensionSpec{
+				{
+					Name:      "CAPZ.Linux.Bootstrapping",
+					VMName:    "machine-name",
+					Publisher: "Microsoft.Azure.ContainerUpstream",
+					Version:   "1.0",
+					ProtectedSettings: map[string]string{
+						"commandToExecute": azure.BootstrapExtensionCommand("Linux"),
+					},
+				},
+			},
+		},
+		{
+			name: "returns the monitoring agent after the bootstrap extension",
+			azureMachine: &infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "machine-name",
+					Annotations: map[string]string{
+						azure.WindowsBootstrapExtensionAnnotation: "true",
+					},
+				},
+				Spec: infrav1.AzureMachineSpec{
+					OSDisk: infrav1.OSDisk{OSType: azure.WindowsOS},
+					AzureMonitor: &infrav1.AzureMonitor{
+						Agent: infrav1.MonitoringAgentAzureMonitor,
+					},
+				},
+			},
+			want: []azure.VMExtensionSpec{
+				{
+					Name:      "CAPZ.Windows.Bootstrapping",
+					VMName:    "machine-name",
+					Publisher: "Microsoft.Azure.ContainerUpstream",
+					Version:   "1.0",
+					ProtectedSettings: map[string]string{
+						"commandToExecute": azure.BootstrapExtensionCommand(azure.WindowsOS),
+					},
+				},
+				{
+					Name:                    "AzureMonitorWindowsAgent",
+					VMName:                  "machine-name",
+					Publisher:               "Microsoft.Azure.Monitor",
+					Version:                 "1.0",
+					AutoUpgradeMinorVersion: true,
+					SkipBootstrapConditions: true,
+				},
+			},
+		},
+		{
+			name: "does not return the bootstrap extension for a Windows VM created without it",
+			azureMachine: &infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "machine-name",
+				},
+				Spec: infrav1.AzureMachineSpec{
+					OSDisk: infrav1.OSDisk{OSType: azure.WindowsOS},
+				},
+			},
+			want: []azure.VMExtensionSpec{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				ClusterScoper: clusterScope,
+				Machine:       &clusterv1.Machine{},
+				AzureMachine:  tt.azureMachine,
+			}
+			got, err := machineScope.VMExtensionSpecs(context.TODO())
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachineScope_BackupSpec(t *testing.T) {
+	clusterScope := &ClusterScope{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-cluster",
+			},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+			},
+		},
+	}
+	controlPlaneMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				clusterv1.MachineControlPlaneLabelName: "true",
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		machineScope MachineScope
+		want         *azure.BackupSpec
+	}{
+		{
+			name: "returns nothing without a backup policy",
+			machineScope: MachineScope{
+				ClusterScoper: clusterScope,
+				Machine:       controlPlaneMachine,
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "returns nothing for a worker machine",
+			machineScope: MachineScope{
+				ClusterScoper: clusterScope,
+				Machine:       &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						Backup: &infrav1.Backup{
+							VaultName:  "my-vault",
+							PolicyName: "DefaultPolicy",
+						},
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "defaults the vault resource group to the resource group of the cluster",
+			machineScope: MachineScope{
+				ClusterScoper: clusterScope,
+				Machine:       controlPlaneMachine,
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						Backup: &infrav1.Backup{
+							VaultName:  "my-vault",
+							PolicyName: "DefaultPolicy",
+						},
+					},
+				},
+			},
+			want: &azure.BackupSpec{
+				VMName:             "machine-name",
+				VaultResourceGroup: "my-rg",
+				VaultName:          "my-vault",
+				PolicyName:         "DefaultPolicy",
+			},
+		},
+		{
+			name: "returns the vault in another resource group",
+			machineScope: MachineScope{
+				ClusterScoper: clusterScope,
+				Machine:       controlPlaneMachine,
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						Backup: &infrav1.Backup{
+							VaultResourceGroup: "my-backup-rg",
+							VaultName:          "my-vault",
+							PolicyName:         "DefaultPolicy",
+						},
+					},
+				},
+			},
+			want: &azure.BackupSpec{
+				VMName:             "machine-name",
+				VaultResourceGroup: "my-backup-rg",
+				VaultName:          "my-vault",
+				PolicyName:         "DefaultPolicy",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(tt.machineScope.BackupSpec()).To(Equal(tt.want))
+		})
+	}
+}
@@ -18,7 +18,6 @@ package scope
 
 import (
 	"context"
-	"encoding/base64"
 	"strings"
 	"time"
 
@@ -120,7 +119,7 @@ func (m *MachinePoolScope) ScaleSetSpec() azure.ScaleSetSpec {
 		VNetName:                m.Vnet().Name,
 		VNetResourceGroup:       m.Vnet().ResourceGroup,
 		PublicLBName:            m.OutboundLBName(infrav1.Node),
-		PublicLBAddressPoolName: azure.GenerateOutboundBackendAddressPoolName(m.OutboundLBName(infrav1.Node)),
+		PublicLBAddressPoolName: m.OutboundPoolName(m.OutboundLBName(infrav1.Node)),
 		AcceleratedNetworking:   m.AzureMachinePool.Spec.Template.AcceleratedNetworking,
 		Identity:                m.AzureMachinePool.Spec.Identity,
 		UserAssignedIdentities:  m.AzureMachinePool.Spec.UserAssignedIdentities,
@@ -132,9 +131,13 @@ func (m *MachinePoolScope) ScaleSetSpec() azure.ScaleSetSpec {
 
 // Name returns the Azure Machine Pool Name.
 func (m *MachinePoolScope) Name() string {
+	// Keep the name of an existing scale set, which may have been shortened differently by previous releases.
+	if id := m.ProviderID(); id != "" {
+		return id
+	}
 	// Windows Machine pools names cannot be longer than 9 chars
-	if m.AzureMachinePool.Spec.Template.OSDisk.OSType == azure.WindowsOS && len(m.AzureMachinePool.Name) > 9 {
-		return "win-" + m.AzureMachinePool.Name[len(m.AzureMachinePool.Name)-5:]
+	if m.AzureMachinePool.Spec.Template.OSDisk.OSType == azure.WindowsOS {
+		return azure.GenerateWindowsScaleSetName(m.AzureMachinePool.Name)
 	}
 	return m.AzureMachinePool.Name
 }
@@ -521,12 +524,7 @@ func (m *MachinePoolScope) GetBootstrapData(ctx context.Context) (string, error)
 	if err := m.client.Get(ctx, key, secret); err != nil {
 		return "", errors.Wrapf(err, "failed to retrieve bootstrap data secret for AzureMachinePool %s/%s", m.AzureMachinePool.Namespace, m.Name())
 	}
-
-	value, ok := secret.Data["value"]
-	if !ok {
-		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
-	}
-	return base64.StdEncoding.EncodeToString(value), nil
+	return customData(m.AzureMachinePool.Spec.Template.OSDisk, secret)
 }
 
 // GetVMImage picks an image from the machine configuration, or uses a default one.
@@ -544,7 +542,7 @@ func (m *MachinePoolScope) GetVMImage() (*infrav1.Image, error) {
 		m.V(4).Info("No image specified for machine, using default Windows Image", "machine", m.MachinePool.GetName())
 		defaultImage, err = azure.GetDefaultWindowsImage(to.String(m.MachinePool.Spec.Template.Spec.Version))
 	} else {
-		defaultImage, err = azure.GetDefaultUbuntuImage(to.String(m.MachinePool.Spec.Template.Spec.Version))
+		defaultImage, err = azure.GetDefaultLinuxImage(m.AzureMachinePool.Spec.Template.OSDisk.Distribution, to.String(m.MachinePool.Spec.Template.Spec.Version))
 	}
 
 	if err != nil {
@@ -574,22 +572,43 @@ func (m *MachinePoolScope) RoleAssignmentSpecs() []azure.RoleAssignmentSpec {
 }
 
 // VMSSExtensionSpecs returns the vmss extension specs.
-func (m *MachinePoolScope) VMSSExtensionSpecs() []azure.VMSSExtensionSpec {
+func (m *MachinePoolScope) VMSSExtensionSpecs(ctx context.Context) ([]azure.VMSSExtensionSpec, error) {
+	extensionSpecs := []azure.VMSSExtensionSpec{}
 	name, publisher, version := azure.GetBootstrappingVMExtension(m.AzureMachinePool.Spec.Template.OSDisk.OSType, m.CloudEnvironment())
+	// Windows scale sets only get the bootstrap extension when they were created with it, see WindowsBootstrapExtensionAnnotation.
+	if m.AzureMachinePool.Spec.Template.OSDisk.OSType == azure.WindowsOS && m.AzureMachinePool.Annotations[azure.WindowsBootstrapExtensionAnnotation] != "true" {
+		name = ""
+	}
 	if name != "" {
-		return []azure.VMSSExtensionSpec{
-			{
-				Name:         name,
-				ScaleSetName: m.Name(),
-				Publisher:    publisher,
-				Version:      version,
-				ProtectedSettings: map[string]string{
-					"commandToExecute": azure.BootstrapExtensionCommand(),
-				},
+		extensionSpecs = append(extensionSpecs, azure.VMSSExtensionSpec{
+			Name:         name,
+			ScaleSetName: m.Name(),
+			Publisher:    publisher,
+			Version:      version,
+			ProtectedSettings: map[string]string{
+				"commandToExecute": azure.BootstrapExtensionCommand(m.AzureMachinePool.Spec.Template.OSDisk.OSType),
 			},
-		}
+		})
 	}
-	return []azure.VMSSExtensionSpec{}
+
+	if monitor := m.AzureMachinePool.Spec.AzureMonitor; monitor != nil {
+		settings, protectedSettings, err := monitoringAgentSettings(ctx, m.client, m.AzureMachinePool.Namespace, monitor)
+		if err != nil {
+			return nil, err
+		}
+		name, publisher, version := azure.GetMonitoringVMExtension(monitor.Agent, m.AzureMachinePool.Spec.Template.OSDisk.OSType)
+		extensionSpecs = append(extensionSpecs, azure.VMSSExtensionSpec{
+			Name:                    name,
+			ScaleSetName:            m.Name(),
+			Publisher:               publisher,
+			Version:                 version,
+			Settings:                settings,
+			ProtectedSettings:       protectedSettings,
+			AutoUpgradeMinorVersion: true,
+			SkipBootstrapConditions: true,
+		})
+	}
+	return extensionSpecs, nil
 }
 
 func (m *MachinePoolScope) getDeploymentStrategy() machinepool.TypedDeleteSelector {
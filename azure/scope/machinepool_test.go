@@ -76,6 +76,49 @@ func TestMachinePoolScope_Name(t *testing.T) {
 				},
 				ClusterScoper: nil,
 			},
+			want:       "mac-22927",
+			testLength: true,
+		},
+		{
+			name: "windows longer than 9 with a similar name should be shortened differently",
+			machinePoolScope: MachinePoolScope{
+				MachinePool: nil,
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-80123456",
+					},
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						Template: infrav1exp.AzureMachinePoolMachineTemplate{
+							OSDisk: infrav1.OSDisk{
+								OSType: "Windows",
+							},
+						},
+					},
+				},
+				ClusterScoper: nil,
+			},
+			want:       "mac-4a656",
+			testLength: true,
+		},
+		{
+			name: "windows with an existing scale set should keep its name",
+			machinePoolScope: MachinePoolScope{
+				MachinePool: nil,
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-90123456",
+					},
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						ProviderID: "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/win-23456",
+						Template: infrav1exp.AzureMachinePoolMachineTemplate{
+							OSDisk: infrav1.OSDisk{
+								OSType: "Windows",
+							},
+						},
+					},
+				},
+				ClusterScoper: nil,
+			},
 			want: "win-23456",
 		},
 	}
@@ -47,6 +47,9 @@ import (
 )
 
 const (
+	// uninitializedNodeTaint is the taint kubelet adds to its node when it runs with an external cloud provider.
+	uninitializedNodeTaint = "node.cloudprovider.kubernetes.io/uninitialized"
+
 	// MachinePoolMachineScopeName is the sourceName, or more specifically the UserAgent, of client used in cordon and drain.
 	MachinePoolMachineScopeName = "azuremachinepoolmachine-scope"
 )
@@ -255,7 +258,7 @@ func (s *MachinePoolMachineScope) UpdateStatus(ctx context.Context) error {
 			APIVersion: node.APIVersion,
 		}
 
-		s.AzureMachinePoolMachine.Status.Ready = noderefutil.IsNodeReady(node)
+		s.AzureMachinePoolMachine.Status.Ready = noderefutil.IsNodeReady(node) && !isNodeUninitialized(node)
 		s.AzureMachinePoolMachine.Status.Version = node.Status.NodeInfo.KubeletVersion
 	}
 
@@ -516,6 +519,17 @@ func getWorkloadClient(ctx context.Context, c client.Client, cluster client.Obje
 	return remote.NewClusterClient(ctx, MachinePoolMachineScopeName, c, cluster)
 }
 
+// isNodeUninitialized returns true if the node still has the taint kubelet adds when it runs with an external cloud
+// provider, which is removed once cloud-provider-azure has initialized the node.
+func isNodeUninitialized(node *corev1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == uninitializedNodeTaint {
+			return true
+		}
+	}
+	return false
+}
+
 // writer implements io.Writer interface as a pass-through for klog.
 type writer struct {
 	logFunc func(args ...interface{})
@@ -187,6 +187,30 @@ func TestMachineScope_UpdateStatus(t *testing.T) {
 				}))
 			},
 		},
+		{
+			Name: "should not mark AMPM ready if node is not initialized by the external cloud provider",
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) (*azure.VMSSVM, *infrav1.AzureMachinePoolMachine) {
+				node := getReadyNode()
+				node.Spec.Taints = []corev1.Taint{
+					{
+						Key:    "node.cloudprovider.kubernetes.io/uninitialized",
+						Value:  "true",
+						Effect: corev1.TaintEffectNoSchedule,
+					},
+				}
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(node, nil)
+				return nil, ampm
+			},
+			Verify: func(g *WithT, scope *MachinePoolMachineScope) {
+				g.Expect(scope.AzureMachinePoolMachine.Status).To(Equal(infrav1.AzureMachinePoolMachineStatus{
+					Ready:   false,
+					Version: "1.2.3",
+					NodeRef: &corev1.ObjectReference{
+						Name: "node1",
+					},
+				}))
+			},
+		},
 		{
 			Name: "fails fetching the node",
 			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) (*azure.VMSSVM, *infrav1.AzureMachinePoolMachine) {
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"sync"
 
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/go-logr/logr"
@@ -39,6 +40,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
 )
 
 // ManagedControlPlaneScopeParams defines the input parameters used to create a new managed
@@ -70,7 +72,7 @@ func NewManagedControlPlaneScope(ctx context.Context, params ManagedControlPlane
 	}
 
 	if params.ControlPlane.Spec.IdentityRef == nil {
-		if err := params.AzureClients.setCredentials(params.ControlPlane.Spec.SubscriptionID, ""); err != nil {
+		if err := params.AzureClients.setCredentials(params.ControlPlane.Spec.SubscriptionID, params.ControlPlane.Spec.AzureEnvironment, ""); err != nil {
 			return nil, errors.Wrap(err, "failed to create Azure session")
 		}
 	} else {
@@ -79,7 +81,7 @@ func NewManagedControlPlaneScope(ctx context.Context, params ManagedControlPlane
 			return nil, errors.Wrap(err, "failed to init credentials provider")
 		}
 
-		if err := params.AzureClients.setCredentialsWithProvider(ctx, params.ControlPlane.Spec.SubscriptionID, "", credentialsProvider); err != nil {
+		if err := params.AzureClients.setCredentialsWithProvider(ctx, params.ControlPlane.Spec.SubscriptionID, params.ControlPlane.Spec.AzureEnvironment, "", credentialsProvider); err != nil {
 			return nil, errors.Wrap(err, "failed to configure azure settings and credentials for Identity")
 		}
 	}
@@ -99,6 +101,7 @@ func NewManagedControlPlaneScope(ctx context.Context, params ManagedControlPlane
 		InfraMachinePool: params.InfraMachinePool,
 		PatchTarget:      params.PatchTarget,
 		patchHelper:      helper,
+		futuresMu:        &sync.Mutex{},
 	}, nil
 }
 
@@ -117,6 +120,8 @@ type ManagedControlPlaneScope struct {
 	PatchTarget      client.Object
 
 	SystemNodePools []infrav1exp.AzureManagedMachinePool
+	// futuresMu guards the futures of the AzureManagedControlPlane.
+	futuresMu *sync.Mutex
 }
 
 // ResourceGroup returns the managed control plane's resource group.
@@ -127,6 +132,39 @@ func (s *ManagedControlPlaneScope) ResourceGroup() string {
 	return s.ControlPlane.Spec.ResourceGroupName
 }
 
+// NetworkResourceGroup returns the resource group holding the network resources of the cluster.
+// Managed clusters keep their network resources in the control plane resource group.
+func (s *ManagedControlPlaneScope) NetworkResourceGroup() string {
+	return s.ResourceGroup()
+}
+
+// SetResourceGroupStatus records the cluster resource group and whether its lifecycle is managed by the cluster.
+func (s *ManagedControlPlaneScope) SetResourceGroupStatus(status infrav1.ResourceGroupStatus) {
+	s.ControlPlane.Status.ResourceGroup = &status
+}
+
+// ResourceGroupLockEnabled returns false, as a management lock on the resource group would also prevent
+// the deletion of the managed cluster it contains.
+func (s *ManagedControlPlaneScope) ResourceGroupLockEnabled() bool {
+	return false
+}
+
+// SetLongRunningOperationState will set the future on the AzureManagedControlPlane status to allow the resource to continue
+// reconciliation without blocking on the long-running operation.
+func (s *ManagedControlPlaneScope) SetLongRunningOperationState(future *infrav1.Future) {
+	futures.Set(s.futuresMu, &s.ControlPlane.Status.LongRunningOperationStates, future)
+}
+
+// GetLongRunningOperationState will get the future on the AzureManagedControlPlane status for the named resource of a service.
+func (s *ManagedControlPlaneScope) GetLongRunningOperationState(name, service string) *infrav1.Future {
+	return futures.Get(s.futuresMu, &s.ControlPlane.Status.LongRunningOperationStates, name, service)
+}
+
+// DeleteLongRunningOperationState will delete the future from the AzureManagedControlPlane status for the named resource of a service.
+func (s *ManagedControlPlaneScope) DeleteLongRunningOperationState(name, service string) {
+	futures.Delete(s.futuresMu, &s.ControlPlane.Status.LongRunningOperationStates, name, service)
+}
+
 // NodeResourceGroup returns the managed control plane's node resource group.
 func (s *ManagedControlPlaneScope) NodeResourceGroup() string {
 	if s.ControlPlane == nil {
@@ -314,6 +352,11 @@ func (s *ManagedControlPlaneScope) OutboundPoolName(_ string) string {
 	return "aksOutboundBackendPool" // hard-coded in aks
 }
 
+// NodeOutboundLB returns nil as the outbound LB of managed clusters is not managed.
+func (s *ManagedControlPlaneScope) NodeOutboundLB() *infrav1.LoadBalancerSpec {
+	return nil
+}
+
 // GetPrivateDNSZoneName returns the Private DNS Zone from the spec or generate it from cluster name.
 // Currently always empty as managed control planes do not currently implement private clusters.
 func (s *ManagedControlPlaneScope) GetPrivateDNSZoneName() string {
@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+const (
+	// workspaceIDKey is the key of the workspace ID in the Log Analytics workspace secrets.
+	workspaceIDKey = "workspaceID"
+	// workspaceKeyKey is the key of the workspace key in the Log Analytics workspace secrets.
+	workspaceKeyKey = "workspaceKey"
+)
+
+// monitoringAgentSettings returns the public and protected settings of the VM extension of a monitoring agent: the
+// managed identity the Azure Monitor agent authenticates with when it isn't the system-assigned one, or the
+// Log Analytics workspace read from the secret in the given namespace.
+func monitoringAgentSettings(ctx context.Context, c client.Client, namespace string, monitor *infrav1.AzureMonitor) (map[string]interface{}, map[string]string, error) {
+	switch monitor.Agent {
+	case infrav1.MonitoringAgentAzureMonitor:
+		if monitor.Identity == nil {
+			return nil, nil, nil
+		}
+		return map[string]interface{}{
+			"authentication": map[string]interface{}{
+				"managedIdentity": map[string]interface{}{
+					"identifier-name":  "mi_res_id",
+					"identifier-value": strings.TrimPrefix(monitor.Identity.ProviderID, azure.ProviderIDPrefix),
+				},
+			},
+		}, nil, nil
+	case infrav1.MonitoringAgentLogAnalytics:
+		if monitor.WorkspaceSecretRef == nil {
+			return nil, nil, errors.New("the Log Analytics agent requires a workspace secret")
+		}
+		secret := &corev1.Secret{}
+		key := types.NamespacedName{Namespace: namespace, Name: monitor.WorkspaceSecretRef.Name}
+		if err := c.Get(ctx, key, secret); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to get Log Analytics workspace secret %s", key)
+		}
+		workspaceID, workspaceKey := secret.Data[workspaceIDKey], secret.Data[workspaceKeyKey]
+		if len(workspaceID) == 0 || len(workspaceKey) == 0 {
+			return nil, nil, errors.Errorf("Log Analytics workspace secret %s must have the %s and %s keys", key, workspaceIDKey, workspaceKeyKey)
+		}
+		return map[string]interface{}{"workspaceId": string(workspaceID)}, map[string]string{"workspaceKey": string(workspaceKey)}, nil
+	}
+
+	return nil, nil, errors.Errorf("unsupported monitoring agent %s", monitor.Agent)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+)
+
+func TestMonitoringAgentSettings(t *testing.T) {
+	workspaceSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-workspace",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"workspaceID":  []byte("1234"),
+			"workspaceKey": []byte("secret"),
+		},
+	}
+
+	tests := []struct {
+		name                  string
+		monitor               *infrav1.AzureMonitor
+		objects               []runtime.Object
+		wantSettings          map[string]interface{}
+		wantProtectedSettings map[string]string
+		wantErr               string
+	}{
+		{
+			name:    "Azure Monitor agent with the system-assigned identity",
+			monitor: &infrav1.AzureMonitor{Agent: infrav1.MonitoringAgentAzureMonitor},
+		},
+		{
+			name: "Azure Monitor agent with a user-assigned identity",
+			monitor: &infrav1.AzureMonitor{
+				Agent: infrav1.MonitoringAgentAzureMonitor,
+				Identity: &infrav1.UserAssignedIdentity{
+					ProviderID: "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity",
+				},
+			},
+			wantSettings: map[string]interface{}{
+				"authentication": map[string]interface{}{
+					"managedIdentity": map[string]interface{}{
+						"identifier-name":  "mi_res_id",
+						"identifier-value": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity",
+					},
+				},
+			},
+		},
+		{
+			name: "Log Analytics agent with its workspace",
+			monitor: &infrav1.AzureMonitor{
+				Agent:              infrav1.MonitoringAgentLogAnalytics,
+				WorkspaceSecretRef: &corev1.LocalObjectReference{Name: "my-workspace"},
+			},
+			objects:               []runtime.Object{workspaceSecret},
+			wantSettings:          map[string]interface{}{"workspaceId": "1234"},
+			wantProtectedSettings: map[string]string{"workspaceKey": "secret"},
+		},
+		{
+			name: "Log Analytics agent without its workspace secret",
+			monitor: &infrav1.AzureMonitor{
+				Agent:              infrav1.MonitoringAgentLogAnalytics,
+				WorkspaceSecretRef: &corev1.LocalObjectReference{Name: "my-workspace"},
+			},
+			wantErr: "failed to get Log Analytics workspace secret default/my-workspace",
+		},
+		{
+			name: "Log Analytics agent with an incomplete workspace secret",
+			monitor: &infrav1.AzureMonitor{
+				Agent:              infrav1.MonitoringAgentLogAnalytics,
+				WorkspaceSecretRef: &corev1.LocalObjectReference{Name: "my-workspace"},
+			},
+			objects: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-workspace",
+						Namespace: "default",
+					},
+					Data: map[string][]byte{
+						"workspaceID": []byte("1234"),
+					},
+				},
+			},
+			wantErr: "must have the workspaceID and workspaceKey keys",
+		},
+		{
+			name:    "unsupported agent",
+			monitor: &infrav1.AzureMonitor{Agent: "SomeAgent"},
+			wantErr: "unsupported monitoring agent SomeAgent",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = corev1.AddToScheme(scheme)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tt.objects...).Build()
+
+			settings, protectedSettings, err := monitoringAgentSettings(context.TODO(), fakeClient, "default", tt.monitor)
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(settings).To(Equal(tt.wantSettings))
+			g.Expect(protectedSettings).To(Equal(tt.wantProtectedSettings))
+		})
+	}
+}
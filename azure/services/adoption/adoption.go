@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adoption
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// AdoptionScope defines the scope interface for an adoption service.
+type AdoptionScope interface {
+	logr.Logger
+	azure.ClusterDescriber
+	AdoptionSpecs() []azure.AdoptionSpec
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope AdoptionScope
+	client
+}
+
+// New creates a new adoption service.
+func New(scope AdoptionScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Reconcile claims the pre-existing resources matching the adoption specs by tagging them with the cluster lifecycle tag.
+// Owned resources also get the additional tags of the cluster and are managed from then on, while shared resources are
+// only ever read. Resources which don't exist yet are left to their own service, and resources which already carry
+// the cluster tag are left untouched.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, span := tele.Tracer().Start(ctx, "adoption.Service.Reconcile")
+	defer span.End()
+
+	for _, adoptionSpec := range s.Scope.AdoptionSpecs() {
+		var tags infrav1.Tags
+		switch adoptionSpec.Lifecycle {
+		case infrav1.ResourceLifecycleOwned:
+			tags = infrav1.Build(infrav1.BuildParams{
+				ClusterName: s.Scope.ClusterName(),
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+				Additional:  s.Scope.AdditionalTags(),
+			})
+		case infrav1.ResourceLifecycleShared:
+			tags = infrav1.Tags{infrav1.ClusterTagKey(s.Scope.ClusterName()): string(infrav1.ResourceLifecycleShared)}
+		default:
+			return errors.Errorf("invalid adoption lifecycle %q, must be either %q or %q", adoptionSpec.Lifecycle, infrav1.ResourceLifecycleOwned, infrav1.ResourceLifecycleShared)
+		}
+
+		result, err := s.client.GetAtScope(ctx, adoptionSpec.Scope)
+		if err != nil && azure.ResourceNotFound(err) {
+			// nothing to adopt, the resource gets created by its own service
+			continue
+		} else if err != nil {
+			return errors.Wrapf(err, "failed to get tags of %s", adoptionSpec.Scope)
+		}
+		if result.Properties != nil {
+			if _, ok := result.Properties.Tags[infrav1.ClusterTagKey(s.Scope.ClusterName())]; ok {
+				// the resource has already been claimed by the cluster
+				continue
+			}
+		}
+
+		s.Scope.V(2).Info("adopting resource", "resource", adoptionSpec.Scope, "lifecycle", adoptionSpec.Lifecycle)
+		if _, err := s.client.UpdateAtScope(ctx, adoptionSpec.Scope, resources.TagsPatchResource{
+			Operation:  "Merge",
+			Properties: &resources.Tags{Tags: converters.TagsToMap(tags)},
+		}); err != nil {
+			return errors.Wrapf(err, "failed to adopt %s", adoptionSpec.Scope)
+		}
+		s.Scope.V(2).Info("successfully adopted resource", "resource", adoptionSpec.Scope, "lifecycle", adoptionSpec.Lifecycle)
+	}
+	return nil
+}
+
+// Delete is a no-op as adopted resources are deleted, or kept if shared, by their own service.
+func (s *Service) Delete(ctx context.Context) error {
+	_, span := tele.Tracer().Start(ctx, "adoption.Service.Delete")
+	defer span.End()
+
+	return nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adoption
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/v2/klogr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/adoption/mock_adoption"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+func TestReconcileAdoption(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expect        func(s *mock_adoption.MockAdoptionScopeMockRecorder, m *mock_adoption.MockclientMockRecorder)
+		expectedError string
+	}{
+		{
+			name:          "adoption disabled",
+			expectedError: "",
+			expect: func(s *mock_adoption.MockAdoptionScopeMockRecorder, m *mock_adoption.MockclientMockRecorder) {
+				s.AdoptionSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "adopt owned resources",
+			expectedError: "",
+			expect: func(s *mock_adoption.MockAdoptionScopeMockRecorder, m *mock_adoption.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{"foo": "bar"})
+				s.AdoptionSpecs().Return([]azure.AdoptionSpec{
+					{Scope: "/subscriptions/123/resourceGroups/my-rg", Lifecycle: infrav1.ResourceLifecycleOwned},
+					{Scope: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet", Lifecycle: infrav1.ResourceLifecycleOwned},
+				})
+				m.GetAtScope(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg").Return(resources.TagsResource{
+					Properties: &resources.Tags{Tags: map[string]*string{"team": to.StringPtr("infra")}},
+				}, nil)
+				m.UpdateAtScope(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg", resources.TagsPatchResource{
+					Operation: "Merge",
+					Properties: &resources.Tags{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+							"foo": to.StringPtr("bar"),
+						},
+					},
+				})
+				m.GetAtScope(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet").
+					Return(resources.TagsResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "adopt shared resources",
+			expectedError: "",
+			expect: func(s *mock_adoption.MockAdoptionScopeMockRecorder, m *mock_adoption.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdoptionSpecs().Return([]azure.AdoptionSpec{
+					{Scope: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet", Lifecycle: infrav1.ResourceLifecycleShared},
+				})
+				m.GetAtScope(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet").Return(resources.TagsResource{}, nil)
+				m.UpdateAtScope(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet", resources.TagsPatchResource{
+					Operation: "Merge",
+					Properties: &resources.Tags{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("shared"),
+						},
+					},
+				})
+			},
+		},
+		{
+			name:          "resource already claimed",
+			expectedError: "",
+			expect: func(s *mock_adoption.MockAdoptionScopeMockRecorder, m *mock_adoption.MockclientMockRecorder) {
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.AdoptionSpecs().Return([]azure.AdoptionSpec{
+					{Scope: "/subscriptions/123/resourceGroups/my-rg", Lifecycle: infrav1.ResourceLifecycleOwned},
+				})
+				m.GetAtScope(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg").Return(resources.TagsResource{
+					Properties: &resources.Tags{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("shared"),
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "invalid lifecycle",
+			expectedError: "invalid adoption lifecycle \"mine\", must be either \"owned\" or \"shared\"",
+			expect: func(s *mock_adoption.MockAdoptionScopeMockRecorder, m *mock_adoption.MockclientMockRecorder) {
+				s.AdoptionSpecs().Return([]azure.AdoptionSpec{
+					{Scope: "/subscriptions/123/resourceGroups/my-rg", Lifecycle: "mine"},
+				})
+			},
+		},
+		{
+			name:          "error getting tags",
+			expectedError: "failed to get tags of /subscriptions/123/resourceGroups/my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_adoption.MockAdoptionScopeMockRecorder, m *mock_adoption.MockclientMockRecorder) {
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdoptionSpecs().Return([]azure.AdoptionSpec{
+					{Scope: "/subscriptions/123/resourceGroups/my-rg", Lifecycle: infrav1.ResourceLifecycleShared},
+				})
+				m.GetAtScope(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg").
+					Return(resources.TagsResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:          "error updating tags",
+			expectedError: "failed to adopt /subscriptions/123/resourceGroups/my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_adoption.MockAdoptionScopeMockRecorder, m *mock_adoption.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdoptionSpecs().Return([]azure.AdoptionSpec{
+					{Scope: "/subscriptions/123/resourceGroups/my-rg", Lifecycle: infrav1.ResourceLifecycleShared},
+				})
+				m.GetAtScope(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg").Return(resources.TagsResource{}, nil)
+				m.UpdateAtScope(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg", gomock.Any()).
+					Return(resources.TagsResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_adoption.NewMockAdoptionScope(mockCtrl)
+			clientMock := mock_adoption.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
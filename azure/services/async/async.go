@@ -0,0 +1,310 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package async
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// PutFuture is a future that was derived from a PUT request.
+	PutFuture string = "PUT"
+	// DeleteFuture is a future that was derived from a DELETE request.
+	DeleteFuture string = "DELETE"
+	// PostFuture is a future that was derived from a POST request, e.g. an action on a resource.
+	PostFuture string = "POST"
+)
+
+// resourceKinds are the readable kinds of the resources of the services with long-running operations, used in events.
+var resourceKinds = map[string]string{
+	"bastionhosts":    "bastion host",
+	"loadbalancers":   "load balancer",
+	"virtualmachines": "virtual machine",
+	"virtualnetworks": "virtual network",
+}
+
+// ResourceKind returns the readable kind of the resources of a service, e.g. "virtual machine" for virtualmachines.
+func ResourceKind(serviceName string) string {
+	if kind, ok := resourceKinds[serviceName]; ok {
+		return kind
+	}
+	return serviceName
+}
+
+// FutureHandler is a client which can check on the progress of the long-running operations it started.
+type FutureHandler interface {
+	// IsDone returns true if the long-running operation of the future is done, along with an error if the operation
+	// failed. It returns false with an error if the operation couldn't be polled, in which case it may still be running.
+	IsDone(ctx context.Context, future *infrav1.Future) (bool, error)
+}
+
+// Getter is a client which can get Azure resources.
+type Getter interface {
+	// Get returns the existing resource of a spec.
+	Get(ctx context.Context, spec azure.ResourceSpecGetter) (interface{}, error)
+}
+
+// Creator is a client which can create or update Azure resources with long-running operations.
+type Creator interface {
+	FutureHandler
+	Getter
+	// CreateOrUpdateAsync starts creating or updating the resource of a spec with the given parameters, and returns the
+	// future of the long-running operation without waiting for it to complete.
+	CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (*infrav1.Future, error)
+}
+
+// Deleter is a client which can delete Azure resources with long-running operations.
+type Deleter interface {
+	FutureHandler
+	// DeleteAsync starts deleting the resource of a spec, and returns the future of the long-running operation without
+	// waiting for it to complete.
+	DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (*infrav1.Future, error)
+}
+
+// Reconciler creates, updates and deletes the Azure resources described by specs.
+type Reconciler interface {
+	// CreateResource creates or updates the resource of a spec, and returns it once it is up to date.
+	CreateResource(ctx context.Context, spec azure.ResourceSpecGetter, serviceName string) (interface{}, error)
+	// DeleteResource deletes the resource of a spec.
+	DeleteResource(ctx context.Context, spec azure.ResourceSpecGetter, serviceName string) error
+}
+
+// Scope is the scope of a Service.
+type Scope interface {
+	logr.Logger
+	azure.AsyncStatusUpdater
+}
+
+// Service is a Reconciler which tracks the long-running operations it starts in the status of the object being
+// reconciled, instead of waiting for them to complete.
+type Service struct {
+	Scope        Scope
+	createClient Creator
+	deleteClient Deleter
+}
+
+var _ Reconciler = (*Service)(nil)
+
+// New creates a new async service.
+func New(scope Scope, createClient Creator, deleteClient Deleter) *Service {
+	return &Service{
+		Scope:        scope,
+		createClient: createClient,
+		deleteClient: deleteClient,
+	}
+}
+
+// CreateResource creates or updates the resource of a spec, unless it is already up to date. It returns an
+// OperationNotDoneError wrapped in a transient error while the operation is in progress, and the resource once it is
+// up to date.
+func (s *Service) CreateResource(ctx context.Context, spec azure.ResourceSpecGetter, serviceName string) (interface{}, error) {
+	ctx, span := tele.Tracer().Start(ctx, "async.Service.CreateResource")
+	defer span.End()
+
+	resourceName := spec.ResourceName()
+	rgName := spec.ResourceGroupName()
+
+	if future := s.Scope.GetLongRunningOperationState(resourceName, serviceName); future != nil {
+		if err := CheckOperation(ctx, s.Scope, s.createClient, future); err != nil {
+			return nil, errors.Wrapf(err, "failed to create resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+		}
+		if future.Type == PutFuture {
+			// Don't start over right after the previous update completed.
+			s.Scope.V(2).Info("successfully created resource", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
+			return s.getResource(ctx, spec, serviceName)
+		}
+	}
+
+	existing, err := s.createClient.Get(ctx, spec)
+	switch {
+	case err != nil && !azure.ResourceNotFound(err):
+		return nil, errors.Wrapf(err, "failed to get existing resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+	case err != nil:
+		existing = nil
+	}
+
+	parameters, err := spec.Parameters(existing)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get desired parameters for resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+	}
+	if parameters == nil {
+		s.Scope.V(4).Info("resource is up to date, skipping update", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
+		return existing, nil
+	}
+
+	s.Scope.V(2).Info("creating resource", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
+	future, err := s.createClient.CreateOrUpdateAsync(ctx, spec, parameters)
+	if err != nil {
+		azure.RecordCreate(ctx, ResourceKind(serviceName), resourceName, err)
+		return nil, errors.Wrapf(err, "failed to create resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+	}
+	if err := TrackOperation(ctx, s.Scope, s.createClient, future); err != nil {
+		return nil, errors.Wrapf(err, "failed to create resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+	}
+
+	s.Scope.V(2).Info("successfully created resource", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
+	return s.getResource(ctx, spec, serviceName)
+}
+
+// DeleteResource deletes the resource of a spec. It returns an OperationNotDoneError wrapped in a transient error
+// while the operation is in progress, and nil once the resource is deleted.
+func (s *Service) DeleteResource(ctx context.Context, spec azure.ResourceSpecGetter, serviceName string) error {
+	ctx, span := tele.Tracer().Start(ctx, "async.Service.DeleteResource")
+	defer span.End()
+
+	resourceName := spec.ResourceName()
+	rgName := spec.ResourceGroupName()
+
+	if future := s.Scope.GetLongRunningOperationState(resourceName, serviceName); future != nil {
+		if err := CheckOperation(ctx, s.Scope, s.deleteClient, future); err != nil {
+			return errors.Wrapf(err, "failed to delete resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+		}
+		if future.Type == DeleteFuture {
+			s.Scope.V(2).Info("successfully deleted resource", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
+			return nil
+		}
+	}
+
+	s.Scope.V(2).Info("deleting resource", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
+	future, err := s.deleteClient.DeleteAsync(ctx, spec)
+	if err != nil {
+		if azure.ResourceNotFound(err) {
+			// already deleted
+			return nil
+		}
+		azure.RecordDelete(ctx, ResourceKind(serviceName), resourceName, err)
+		return errors.Wrapf(err, "failed to delete resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+	}
+	if err := TrackOperation(ctx, s.Scope, s.deleteClient, future); err != nil {
+		return errors.Wrapf(err, "failed to delete resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+	}
+
+	s.Scope.V(2).Info("successfully deleted resource", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
+	return nil
+}
+
+// getResource returns the existing resource of a spec.
+func (s *Service) getResource(ctx context.Context, spec azure.ResourceSpecGetter, serviceName string) (interface{}, error) {
+	result, err := s.createClient.Get(ctx, spec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get created resource %s/%s (service: %s)", spec.ResourceGroupName(), spec.ResourceName(), serviceName)
+	}
+	return result, nil
+}
+
+// NewFuture serializes the future of a long-running operation so that it can be stored in the status of the object
+// being reconciled, and be checked on by the next reconciliation loops instead of waiting for the operation to complete.
+func NewFuture(future azureautorest.FutureAPI, futureType, serviceName, resourceGroup, name string) (*infrav1.Future, error) {
+	jsonData, err := future.MarshalJSON()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal async future")
+	}
+
+	return &infrav1.Future{
+		Type:          futureType,
+		ServiceName:   serviceName,
+		ResourceGroup: resourceGroup,
+		Name:          name,
+		FutureData:    base64.URLEncoding.EncodeToString(jsonData),
+	}, nil
+}
+
+// IsDone polls the long-running operation of a serialized future once, and returns true if it is done. See
+// FutureHandler for the errors it returns. A future which can't be deserialized is reported as done with an error, as
+// it can't be polled again either.
+func IsDone(ctx context.Context, sender autorest.Sender, future *infrav1.Future) (bool, error) {
+	futureData, err := base64.URLEncoding.DecodeString(future.FutureData)
+	if err != nil {
+		return true, errors.Wrap(err, "failed to base64 decode future data")
+	}
+
+	var azureFuture azureautorest.Future
+	if err := json.Unmarshal(futureData, &azureFuture); err != nil {
+		return true, errors.Wrap(err, "failed to unmarshal future data")
+	}
+
+	done, err := azureFuture.DoneWithContext(ctx, sender)
+	if done && err != nil {
+		// The errors of failed operations don't reference the response of the polling request which surfaced them.
+		err = azure.WithResponse(err, azureFuture.Response())
+	}
+	return done, err
+}
+
+// TrackOperation stores the future of a long-running operation which was just started in the status of the object
+// being reconciled and checks on it once. See CheckOperation.
+func TrackOperation(ctx context.Context, scope azure.AsyncStatusUpdater, client FutureHandler, future *infrav1.Future) error {
+	scope.SetLongRunningOperationState(future)
+	return CheckOperation(ctx, scope, client, future)
+}
+
+// CheckOperation checks on a long-running operation stored in the status of the object being reconciled. It returns
+// an OperationNotDoneError wrapped in a transient error while the operation is in progress, so that the object is
+// requeued rather than blocking the controller, and a transient error if the operation couldn't be polled, e.g.
+// because of throttling, so that it is checked on again. Once the operation is done, it is removed from the status,
+// and the error of the operation, if it failed, is returned.
+func CheckOperation(ctx context.Context, scope azure.AsyncStatusUpdater, client FutureHandler, future *infrav1.Future) error {
+	done, err := client.IsDone(ctx, future)
+	switch {
+	case err != nil && !done:
+		// The operation may still be running: keep track of it.
+		err = errors.Wrapf(err, "failed to check on operation type %s on Azure resource %s/%s", future.Type, future.ResourceGroup, future.Name)
+		return azure.WithTransientError(err, azure.PollingDelay(operationType(future)))
+	case err != nil:
+		// The operation failed: forget it so that it is started over by the next reconciliation loop.
+		scope.DeleteLongRunningOperationState(future.Name, future.ServiceName)
+		err = errors.Wrapf(err, "operation type %s on Azure resource %s/%s failed", future.Type, future.ResourceGroup, future.Name)
+		recordOperation(ctx, future, err)
+		return err
+	case !done:
+		return azure.WithTransientError(azure.NewOperationNotDoneError(future), azure.PollingDelay(operationType(future)))
+	}
+
+	scope.DeleteLongRunningOperationState(future.Name, future.ServiceName)
+	recordOperation(ctx, future, nil)
+	return nil
+}
+
+// operationType returns the operation type of a long-running operation, which selects its polling delay.
+func operationType(future *infrav1.Future) azure.OperationType {
+	if future.Type == DeleteFuture {
+		return azure.OperationDelete
+	}
+	return azure.OperationWrite
+}
+
+// recordOperation records the outcome of a long-running operation as an event on the object being reconciled.
+func recordOperation(ctx context.Context, future *infrav1.Future, err error) {
+	kind := ResourceKind(future.ServiceName)
+	switch future.Type {
+	case PutFuture:
+		azure.RecordCreate(ctx, kind, future.Name, err)
+	case DeleteFuture:
+		azure.RecordDelete(ctx, kind, future.Name, err)
+	}
+}
@@ -0,0 +1,400 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package async
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/v2/klogr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/mocks"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var fakeFuture = &infrav1.Future{
+	Type:          PutFuture,
+	ServiceName:   "virtualnetworks",
+	ResourceGroup: "my-rg",
+	Name:          "my-vnet",
+	FutureData:    "",
+}
+
+var fakeDeleteFuture = &infrav1.Future{
+	Type:          DeleteFuture,
+	ServiceName:   "virtualnetworks",
+	ResourceGroup: "my-rg",
+	Name:          "my-vnet",
+	FutureData:    "",
+}
+
+type fakeResource struct {
+	Name string
+}
+
+var (
+	existingResource = fakeResource{Name: "existing"}
+	createdResource  = fakeResource{Name: "created"}
+	desiredResource  = fakeResource{Name: "desired"}
+	notFoundError    = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusNotFound}, "Not Found")
+	serverError      = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+)
+
+func TestCheckOperation(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expect        func(s *mocks.MockAsyncStatusUpdaterMockRecorder, c *mock_async.MockFutureHandlerMockRecorder)
+		expectedError string
+		notDone       bool
+	}{
+		{
+			name:          "operation is done",
+			expectedError: "",
+			expect: func(s *mocks.MockAsyncStatusUpdaterMockRecorder, c *mock_async.MockFutureHandlerMockRecorder) {
+				c.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, nil)
+				s.DeleteLongRunningOperationState("my-vnet", "virtualnetworks")
+			},
+		},
+		{
+			name:          "operation is in progress",
+			expectedError: "transient reconcile error occurred: operation type PUT on Azure resource my-rg/my-vnet is not done. Object will be requeued after 15s",
+			notDone:       true,
+			expect: func(s *mocks.MockAsyncStatusUpdaterMockRecorder, c *mock_async.MockFutureHandlerMockRecorder) {
+				c.IsDone(gomockinternal.AContext(), fakeFuture).Return(false, nil)
+			},
+		},
+		{
+			name:          "operation could not be polled",
+			expectedError: "transient reconcile error occurred: failed to check on operation type PUT on Azure resource my-rg/my-vnet: #: Internal Server Error: StatusCode=500. Object will be requeued after 15s",
+			expect: func(s *mocks.MockAsyncStatusUpdaterMockRecorder, c *mock_async.MockFutureHandlerMockRecorder) {
+				c.IsDone(gomockinternal.AContext(), fakeFuture).Return(false, serverError)
+			},
+		},
+		{
+			name:          "operation failed",
+			expectedError: "operation type PUT on Azure resource my-rg/my-vnet failed: Code=\"InternalServerError\"",
+			expect: func(s *mocks.MockAsyncStatusUpdaterMockRecorder, c *mock_async.MockFutureHandlerMockRecorder) {
+				c.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, errors.New("Code=\"InternalServerError\""))
+				s.DeleteLongRunningOperationState("my-vnet", "virtualnetworks")
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mocks.NewMockAsyncStatusUpdater(mockCtrl)
+			clientMock := mock_async.NewMockFutureHandler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			err := CheckOperation(context.TODO(), scopeMock, clientMock, fakeFuture)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(azure.IsOperationNotDoneError(err)).To(Equal(tc.notDone))
+		})
+	}
+}
+
+func TestTrackOperation(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mocks.NewMockAsyncStatusUpdater(mockCtrl)
+	clientMock := mock_async.NewMockFutureHandler(mockCtrl)
+
+	gomock.InOrder(
+		scopeMock.EXPECT().SetLongRunningOperationState(fakeFuture),
+		clientMock.EXPECT().IsDone(gomockinternal.AContext(), fakeFuture).Return(false, nil),
+	)
+
+	err := TrackOperation(context.TODO(), scopeMock, clientMock, fakeFuture)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(azure.IsOperationNotDoneError(err)).To(BeTrue())
+
+	var reconcileError azure.ReconcileError
+	g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+	g.Expect(reconcileError.IsTransient()).To(BeTrue())
+}
+
+func TestCheckOperationPollingDelay(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mocks.NewMockAsyncStatusUpdater(mockCtrl)
+	clientMock := mock_async.NewMockFutureHandler(mockCtrl)
+
+	azure.SetOperationPolicies(map[azure.OperationType]azure.OperationPolicy{
+		azure.OperationDelete: {PollingDelay: time.Minute},
+	})
+	defer azure.SetOperationPolicies(nil)
+
+	clientMock.EXPECT().IsDone(gomockinternal.AContext(), fakeFuture).Return(false, nil)
+	clientMock.EXPECT().IsDone(gomockinternal.AContext(), fakeDeleteFuture).Return(false, nil)
+
+	var reconcileError azure.ReconcileError
+	g.Expect(errors.As(CheckOperation(context.TODO(), scopeMock, clientMock, fakeFuture), &reconcileError)).To(BeTrue())
+	g.Expect(reconcileError.RequeueAfter()).To(Equal(azure.DefaultPollingDelay))
+
+	g.Expect(errors.As(CheckOperation(context.TODO(), scopeMock, clientMock, fakeDeleteFuture), &reconcileError)).To(BeTrue())
+	g.Expect(reconcileError.RequeueAfter()).To(Equal(time.Minute))
+}
+
+func TestCreateResource(t *testing.T) {
+	testcases := []struct {
+		name           string
+		expect         func(s *mock_async.MockScopeMockRecorder, c *mock_async.MockCreatorMockRecorder, r *mocks.MockResourceSpecGetterMockRecorder)
+		expectedResult interface{}
+		expectedError  string
+	}{
+		{
+			name:           "create a resource which doesn't exist",
+			expectedResult: createdResource,
+			expect: func(s *mock_async.MockScopeMockRecorder, c *mock_async.MockCreatorMockRecorder, r *mocks.MockResourceSpecGetterMockRecorder) {
+				s.GetLongRunningOperationState("my-vnet", "virtualnetworks").Return(nil)
+				gomock.InOrder(
+					c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mocks.MockResourceSpecGetter{})).Return(nil, notFoundError),
+					r.Parameters(nil).Return(desiredResource, nil),
+					c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mocks.MockResourceSpecGetter{}), desiredResource).Return(fakeFuture, nil),
+					s.SetLongRunningOperationState(fakeFuture),
+					c.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, nil),
+					s.DeleteLongRunningOperationState("my-vnet", "virtualnetworks"),
+					c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mocks.MockResourceSpecGetter{})).Return(createdResource, nil),
+				)
+			},
+		},
+		{
+			name:           "start updating an existing resource which is out of date",
+			expectedError:  "failed to create resource my-rg/my-vnet (service: virtualnetworks): transient reconcile error occurred: operation type PUT on Azure resource my-rg/my-vnet is not done. Object will be requeued after 15s",
+			expectedResult: nil,
+			expect: func(s *mock_async.MockScopeMockRecorder, c *mock_async.MockCreatorMockRecorder, r *mocks.MockResourceSpecGetterMockRecorder) {
+				s.GetLongRunningOperationState("my-vnet", "virtualnetworks").Return(nil)
+				gomock.InOrder(
+					c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mocks.MockResourceSpecGetter{})).Return(existingResource, nil),
+					r.Parameters(existingResource).Return(desiredResource, nil),
+					c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mocks.MockResourceSpecGetter{}), desiredResource).Return(fakeFuture, nil),
+					s.SetLongRunningOperationState(fakeFuture),
+					c.IsDone(gomockinternal.AContext(), fakeFuture).Return(false, nil),
+				)
+			},
+		},
+		{
+			name:           "skip the update of an existing resource which is up to date",
+			expectedResult: existingResource,
+			expect: func(s *mock_async.MockScopeMockRecorder, c *mock_async.MockCreatorMockRecorder, r *mocks.MockResourceSpecGetterMockRecorder) {
+				s.GetLongRunningOperationState("my-vnet", "virtualnetworks").Return(nil)
+				c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mocks.MockResourceSpecGetter{})).Return(existingResource, nil)
+				r.Parameters(existingResource).Return(nil, nil)
+			},
+		},
+		{
+			name:           "operation started by a previous reconcile still in progress",
+			expectedError:  "failed to create resource my-rg/my-vnet (service: virtualnetworks): transient reconcile error occurred: operation type PUT on Azure resource my-rg/my-vnet is not done. Object will be requeued after 15s",
+			expectedResult: nil,
+			expect: func(s *mock_async.MockScopeMockRecorder, c *mock_async.MockCreatorMockRecorder, r *mocks.MockResourceSpecGetterMockRecorder) {
+				s.GetLongRunningOperationState("my-vnet", "virtualnetworks").Return(fakeFuture)
+				c.IsDone(gomockinternal.AContext(), fakeFuture).Return(false, nil)
+			},
+		},
+		{
+			name:           "operation started by a previous reconcile is done",
+			expectedResult: createdResource,
+			expect: func(s *mock_async.MockScopeMockRecorder, c *mock_async.MockCreatorMockRecorder, r *mocks.MockResourceSpecGetterMockRecorder) {
+				s.GetLongRunningOperationState("my-vnet", "virtualnetworks").Return(fakeFuture)
+				c.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, nil)
+				s.DeleteLongRunningOperationState("my-vnet", "virtualnetworks")
+				c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mocks.MockResourceSpecGetter{})).Return(createdResource, nil)
+			},
+		},
+		{
+			name:           "fail to get the existing resource",
+			expectedError:  "failed to get existing resource my-rg/my-vnet (service: virtualnetworks): #: Internal Server Error: StatusCode=500",
+			expectedResult: nil,
+			expect: func(s *mock_async.MockScopeMockRecorder, c *mock_async.MockCreatorMockRecorder, r *mocks.MockResourceSpecGetterMockRecorder) {
+				s.GetLongRunningOperationState("my-vnet", "virtualnetworks").Return(nil)
+				c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mocks.MockResourceSpecGetter{})).Return(nil, serverError)
+			},
+		},
+		{
+			name:           "fail to compute the parameters",
+			expectedError:  "failed to get desired parameters for resource my-rg/my-vnet (service: virtualnetworks): invalid spec",
+			expectedResult: nil,
+			expect: func(s *mock_async.MockScopeMockRecorder, c *mock_async.MockCreatorMockRecorder, r *mocks.MockResourceSpecGetterMockRecorder) {
+				s.GetLongRunningOperationState("my-vnet", "virtualnetworks").Return(nil)
+				c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mocks.MockResourceSpecGetter{})).Return(nil, notFoundError)
+				r.Parameters(nil).Return(nil, errors.New("invalid spec"))
+			},
+		},
+		{
+			name:           "fail to start creating the resource",
+			expectedError:  "failed to create resource my-rg/my-vnet (service: virtualnetworks): #: Internal Server Error: StatusCode=500",
+			expectedResult: nil,
+			expect: func(s *mock_async.MockScopeMockRecorder, c *mock_async.MockCreatorMockRecorder, r *mocks.MockResourceSpecGetterMockRecorder) {
+				s.GetLongRunningOperationState("my-vnet", "virtualnetworks").Return(nil)
+				c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mocks.MockResourceSpecGetter{})).Return(nil, notFoundError)
+				r.Parameters(nil).Return(desiredResource, nil)
+				c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mocks.MockResourceSpecGetter{}), desiredResource).Return(nil, serverError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_async.NewMockScope(mockCtrl)
+			clientMock := mock_async.NewMockCreator(mockCtrl)
+			specMock := mocks.NewMockResourceSpecGetter(mockCtrl)
+
+			scopeMock.EXPECT().V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			specMock.EXPECT().ResourceName().AnyTimes().Return("my-vnet")
+			specMock.EXPECT().ResourceGroupName().AnyTimes().Return("my-rg")
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), specMock.EXPECT())
+
+			s := New(scopeMock, clientMock, nil)
+			result, err := s.CreateResource(context.TODO(), specMock, "virtualnetworks")
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.expectedResult != nil {
+				g.Expect(result).To(Equal(tc.expectedResult))
+			} else {
+				g.Expect(result).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestDeleteResource(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expect        func(s *mock_async.MockScopeMockRecorder, c *mock_async.MockDeleterMockRecorder)
+		expectedError string
+	}{
+		{
+			name: "delete a resource",
+			expect: func(s *mock_async.MockScopeMockRecorder, c *mock_async.MockDeleterMockRecorder) {
+				s.GetLongRunningOperationState("my-vnet", "virtualnetworks").Return(nil)
+				gomock.InOrder(
+					c.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mocks.MockResourceSpecGetter{})).Return(fakeDeleteFuture, nil),
+					s.SetLongRunningOperationState(fakeDeleteFuture),
+					c.IsDone(gomockinternal.AContext(), fakeDeleteFuture).Return(true, nil),
+					s.DeleteLongRunningOperationState("my-vnet", "virtualnetworks"),
+				)
+			},
+		},
+		{
+			name:          "deletion in progress",
+			expectedError: "failed to delete resource my-rg/my-vnet (service: virtualnetworks): transient reconcile error occurred: operation type DELETE on Azure resource my-rg/my-vnet is not done. Object will be requeued after 15s",
+			expect: func(s *mock_async.MockScopeMockRecorder, c *mock_async.MockDeleterMockRecorder) {
+				s.GetLongRunningOperationState("my-vnet", "virtualnetworks").Return(nil)
+				gomock.InOrder(
+					c.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mocks.MockResourceSpecGetter{})).Return(fakeDeleteFuture, nil),
+					s.SetLongRunningOperationState(fakeDeleteFuture),
+					c.IsDone(gomockinternal.AContext(), fakeDeleteFuture).Return(false, nil),
+				)
+			},
+		},
+		{
+			name: "resource already deleted",
+			expect: func(s *mock_async.MockScopeMockRecorder, c *mock_async.MockDeleterMockRecorder) {
+				s.GetLongRunningOperationState("my-vnet", "virtualnetworks").Return(nil)
+				c.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mocks.MockResourceSpecGetter{})).Return(nil, notFoundError)
+			},
+		},
+		{
+			name: "deletion started by a previous reconcile is done",
+			expect: func(s *mock_async.MockScopeMockRecorder, c *mock_async.MockDeleterMockRecorder) {
+				s.GetLongRunningOperationState("my-vnet", "virtualnetworks").Return(fakeDeleteFuture)
+				c.IsDone(gomockinternal.AContext(), fakeDeleteFuture).Return(true, nil)
+				s.DeleteLongRunningOperationState("my-vnet", "virtualnetworks")
+			},
+		},
+		{
+			name: "creation started by a previous reconcile is done",
+			expect: func(s *mock_async.MockScopeMockRecorder, c *mock_async.MockDeleterMockRecorder) {
+				s.GetLongRunningOperationState("my-vnet", "virtualnetworks").Return(fakeFuture)
+				gomock.InOrder(
+					c.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, nil),
+					s.DeleteLongRunningOperationState("my-vnet", "virtualnetworks"),
+					c.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mocks.MockResourceSpecGetter{})).Return(fakeDeleteFuture, nil),
+					s.SetLongRunningOperationState(fakeDeleteFuture),
+					c.IsDone(gomockinternal.AContext(), fakeDeleteFuture).Return(true, nil),
+					s.DeleteLongRunningOperationState("my-vnet", "virtualnetworks"),
+				)
+			},
+		},
+		{
+			name:          "fail to start deleting the resource",
+			expectedError: "failed to delete resource my-rg/my-vnet (service: virtualnetworks): #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_async.MockScopeMockRecorder, c *mock_async.MockDeleterMockRecorder) {
+				s.GetLongRunningOperationState("my-vnet", "virtualnetworks").Return(nil)
+				c.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mocks.MockResourceSpecGetter{})).Return(nil, serverError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_async.NewMockScope(mockCtrl)
+			clientMock := mock_async.NewMockDeleter(mockCtrl)
+			specMock := mocks.NewMockResourceSpecGetter(mockCtrl)
+
+			scopeMock.EXPECT().V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			specMock.EXPECT().ResourceName().AnyTimes().Return("my-vnet")
+			specMock.EXPECT().ResourceGroupName().AnyTimes().Return("my-rg")
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := New(scopeMock, nil, clientMock)
+			err := s.DeleteResource(context.TODO(), specMock, "virtualnetworks")
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
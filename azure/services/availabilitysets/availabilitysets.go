@@ -80,8 +80,6 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "failed to determine max fault domain count")
 	}
 
-	s.Scope.V(2).Info("creating availability set", "availability set", availabilitySetName)
-
 	asParams := compute.AvailabilitySet{
 		Sku: &compute.Sku{
 			Name: to.StringPtr(string(compute.Aligned)),
@@ -99,7 +97,24 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		Location: to.StringPtr(s.Scope.Location()),
 	}
 
+	existing, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), availabilitySetName)
+	switch {
+	case err != nil && !azure.ResourceNotFound(err):
+		return errors.Wrapf(err, "failed to get availability set %s", availabilitySetName)
+	case err == nil:
+		upToDate, err := azure.IsUpToDate(asParams, existing)
+		if err != nil {
+			return errors.Wrapf(err, "failed to compare availability set %s", availabilitySetName)
+		}
+		if upToDate {
+			s.Scope.V(4).Info("availability set is up to date, skipping update", "availability set", availabilitySetName)
+			return nil
+		}
+	}
+
+	s.Scope.V(2).Info("creating availability set", "availability set", availabilitySetName)
 	_, err = s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), availabilitySetName, asParams)
+	azure.RecordCreate(ctx, "availability set", availabilitySetName, err)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create availability set %s", availabilitySetName)
 	}
@@ -136,6 +151,7 @@ func (s *Service) Delete(ctx context.Context) error {
 
 	s.Scope.V(2).Info("deleting availability set", "availability set", availabilitySetName)
 	err = s.Client.Delete(ctx, s.Scope.ResourceGroup(), availabilitySetName)
+	azure.RecordDelete(ctx, "availability set", availabilitySetName, err)
 	if err != nil && azure.ResourceNotFound(err) {
 		// already deleted
 		return nil
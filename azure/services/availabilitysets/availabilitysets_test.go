@@ -19,6 +19,7 @@ package availabilitysets
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-30/compute"
@@ -47,10 +48,11 @@ func TestReconcileAvailabilitySets(t *testing.T) {
 			expect: func(s *mock_availabilitysets.MockAvailabilitySetScopeMockRecorder, m *mock_availabilitysets.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).MinTimes(2).Return(klogr.New())
 				s.AvailabilitySet().Return("as-name", true)
-				s.ResourceGroup().Return("my-rg")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.ClusterName().Return("cl-name")
 				s.AdditionalTags().Return(map[string]string{})
 				s.Location().Return("test-location")
+				m.Get(gomockinternal.AContext(), "my-rg", "as-name").Return(compute.AvailabilitySet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "as-name",
 					compute.AvailabilitySet{
 						Sku: &compute.Sku{Name: to.StringPtr("Aligned")},
@@ -79,6 +81,46 @@ func TestReconcileAvailabilitySets(t *testing.T) {
 				svc.resourceSKUCache = resourceSkusCache
 			},
 		},
+		{
+			name:          "skip update if the availability set is up to date",
+			expectedError: "",
+			expect: func(s *mock_availabilitysets.MockAvailabilitySetScopeMockRecorder, m *mock_availabilitysets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.AvailabilitySet().Return("as-name", true)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().Return("cl-name")
+				s.AdditionalTags().Return(map[string]string{})
+				s.Location().Return("test-location")
+				m.Get(gomockinternal.AContext(), "my-rg", "as-name").Return(compute.AvailabilitySet{
+					ID:   to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/availabilitySets/as-name"),
+					Name: to.StringPtr("as-name"),
+					Sku:  &compute.Sku{Name: to.StringPtr("Aligned")},
+					AvailabilitySetProperties: &compute.AvailabilitySetProperties{
+						PlatformFaultDomainCount:  pointer.Int32Ptr(3),
+						PlatformUpdateDomainCount: pointer.Int32Ptr(5),
+					},
+					Tags: map[string]*string{"sigs.k8s.io_cluster-api-provider-azure_cluster_cl-name": to.StringPtr("owned"),
+						"sigs.k8s.io_cluster-api-provider-azure_role": to.StringPtr("common"), "Name": to.StringPtr("as-name")},
+					Location: to.StringPtr("test-location"),
+				}, nil)
+			},
+			setupSKUs: func(svc *Service) {
+				skus := []compute.ResourceSku{
+					{
+						Name: to.StringPtr("Aligned"),
+						Kind: to.StringPtr(string(resourceskus.AvailabilitySets)),
+						Capabilities: &[]compute.ResourceSkuCapabilities{
+							{
+								Name:  to.StringPtr(resourceskus.MaximumPlatformFaultDomainCount),
+								Value: to.StringPtr("3"),
+							},
+						},
+					},
+				}
+				resourceSkusCache := resourceskus.NewStaticCache(skus, "")
+				svc.resourceSKUCache = resourceSkusCache
+			},
+		},
 		{
 			name:          "noop if the machine does not need to be assigned an availability set (machines without a deployment)",
 			expectedError: "",
@@ -94,10 +136,11 @@ func TestReconcileAvailabilitySets(t *testing.T) {
 			expect: func(s *mock_availabilitysets.MockAvailabilitySetScopeMockRecorder, m *mock_availabilitysets.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.AvailabilitySet().Return("as-name", true)
-				s.ResourceGroup().Return("my-rg")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.ClusterName().Return("cl-name")
 				s.AdditionalTags().Return(map[string]string{})
 				s.Location().Return("test-location")
+				m.Get(gomockinternal.AContext(), "my-rg", "as-name").Return(compute.AvailabilitySet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "as-name",
 					gomock.AssignableToTypeOf(compute.AvailabilitySet{})).Return(compute.AvailabilitySet{}, errors.New("something went wrong"))
 			},
@@ -18,84 +18,62 @@ package bastionhosts
 
 import (
 	"context"
-	"fmt"
-	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 )
 
 func (s *Service) ensureAzureBastion(ctx context.Context, azureBastionSpec azure.AzureBastionSpec) error {
+	spec := &AzureBastionSpec{
+		Name:           azureBastionSpec.Name,
+		ResourceGroup:  s.Scope.NetworkResourceGroup(),
+		Location:       s.Scope.Location(),
+		ClusterName:    s.Scope.ClusterName(),
+		DNSName:        azureBastionSpec.DNSName,
+		AdditionalTags: s.Scope.AdditionalTags(),
+	}
+
 	s.Scope.V(2).Info("getting azure bastion public IP", "publicIP", azureBastionSpec.PublicIPName)
-	publicIP, err := s.publicIPsClient.Get(ctx, s.Scope.ResourceGroup(), azureBastionSpec.PublicIPName)
+	publicIP, err := s.publicIPsClient.Get(ctx, s.Scope.NetworkResourceGroup(), azureBastionSpec.PublicIPName)
 	if err != nil {
 		return errors.Wrap(err, "failed to get public IP for azure bastion")
 	}
+	spec.PublicIPID = to.String(publicIP.ID)
 
 	s.Scope.V(2).Info("getting azure bastion subnet", "subnet", azureBastionSpec.SubnetSpec)
-	subnet, err := s.subnetsClient.Get(ctx, s.Scope.ResourceGroup(), azureBastionSpec.VNetName, azureBastionSpec.SubnetSpec.Name)
+	subnet, err := s.subnetsClient.Get(ctx, azureBastionSpec.VNetResourceGroup, azureBastionSpec.VNetName, azureBastionSpec.SubnetSpec.Name)
 	if err != nil {
 		return errors.Wrap(err, "failed to get subnet for azure bastion")
 	}
+	spec.SubnetID = to.String(subnet.ID)
 
-	s.Scope.V(2).Info("creating bastion host", "bastion", azureBastionSpec.Name)
-	bastionHostIPConfigName := fmt.Sprintf("%s-%s", azureBastionSpec.Name, "bastionIP")
-	err = s.client.CreateOrUpdate(
-		ctx,
-		s.Scope.ResourceGroup(),
-		azureBastionSpec.Name,
-		network.BastionHost{
-			Name:     to.StringPtr(azureBastionSpec.Name),
-			Location: to.StringPtr(s.Scope.Location()),
-			Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
-				ClusterName: s.Scope.ClusterName(),
-				Lifecycle:   infrav1.ResourceLifecycleOwned,
-				Name:        to.StringPtr(azureBastionSpec.Name),
-				Role:        to.StringPtr("Bastion"),
-			})),
-			BastionHostPropertiesFormat: &network.BastionHostPropertiesFormat{
-				DNSName: to.StringPtr(fmt.Sprintf("%s-bastion", strings.ToLower(azureBastionSpec.Name))),
-				IPConfigurations: &[]network.BastionHostIPConfiguration{
-					{
-						Name: to.StringPtr(bastionHostIPConfigName),
-						BastionHostIPConfigurationPropertiesFormat: &network.BastionHostIPConfigurationPropertiesFormat{
-							Subnet: &network.SubResource{
-								ID: subnet.ID,
-							},
-							PublicIPAddress: &network.SubResource{
-								ID: publicIP.ID,
-							},
-							PrivateIPAllocationMethod: network.IPAllocationMethodDynamic,
-						},
-					},
-				},
-			},
-		},
-	)
-	if err != nil {
+	if _, err := s.CreateResource(ctx, spec, serviceName); err != nil {
 		return errors.Wrap(err, "cannot create Azure Bastion")
 	}
-
-	s.Scope.V(2).Info("successfully created bastion host", "bastion", azureBastionSpec.Name)
 	return nil
 }
 
 func (s *Service) ensureAzureBastionDeleted(ctx context.Context, azureBastionSpec azure.AzureBastionSpec) error {
-	s.Scope.V(2).Info("deleting bastion host", "bastion", azureBastionSpec.Name)
-
-	err := s.client.Delete(ctx, s.Scope.ResourceGroup(), azureBastionSpec.Name)
-	if err != nil && azure.ResourceNotFound(err) {
-		// Resource already deleted, all good.
-	} else if err != nil {
-		return errors.Wrapf(err, "failed to delete Azure Bastion %s in resource group %s", azureBastionSpec.Name, s.Scope.ResourceGroup())
+	spec := &AzureBastionSpec{
+		Name:          azureBastionSpec.Name,
+		ResourceGroup: s.Scope.NetworkResourceGroup(),
 	}
 
-	s.Scope.V(2).Info("successfully deleted bastion host", "bastion", azureBastionSpec.Name)
+	existing, err := s.client.Get(ctx, spec)
+	if err != nil && !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to get Azure Bastion %s in resource group %s", azureBastionSpec.Name, s.Scope.NetworkResourceGroup())
+	}
+	if bastionHost, ok := existing.(network.BastionHost); ok && converters.MapToTags(bastionHost.Tags).HasShared(s.Scope.ClusterName()) {
+		s.Scope.V(2).Info("skipping deletion of shared Azure Bastion", "bastion", azureBastionSpec.Name)
+		return nil
+	}
 
+	if err := s.DeleteResource(ctx, spec, serviceName); err != nil {
+		return errors.Wrapf(err, "failed to delete Azure Bastion %s in resource group %s", azureBastionSpec.Name, s.Scope.NetworkResourceGroup())
+	}
 	return nil
 }
@@ -23,23 +23,27 @@ import (
 	"github.com/pkg/errors"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
-
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+const serviceName = "bastionhosts"
+
 // BastionScope defines the scope interface for a bastion host service.
 type BastionScope interface {
 	logr.Logger
 	azure.ClusterDescriber
 	azure.NetworkDescriber
+	azure.AsyncStatusUpdater
 	BastionSpec() azure.BastionSpec
 }
 
 // Service provides operations on Azure resources.
 type Service struct {
 	Scope BastionScope
+	async.Reconciler
 	client
 	subnetsClient   subnets.Client
 	publicIPsClient publicips.Client
@@ -47,12 +51,28 @@ type Service struct {
 
 // New creates a new service.
 func New(scope BastionScope) *Service {
-	return &Service{
+	client := newClient(scope)
+	svc := &Service{
 		Scope:           scope,
-		client:          newClient(scope),
+		Reconciler:      async.New(scope, client, client),
+		client:          client,
 		subnetsClient:   subnets.NewClient(scope),
 		publicIPsClient: publicips.NewClient(scope),
 	}
+
+	// The bastion subnet and public IP are only read here, they are reconciled by their own services: serve them from
+	// the caches shared across reconciles instead of getting them from Azure twice per reconcile.
+	if subnetsClient, err := subnets.NewCachedClient(scope); err == nil {
+		svc.subnetsClient = subnetsClient
+	} else {
+		scope.Error(err, "failed to create cached subnets client, falling back to uncached reads")
+	}
+	if publicIPsClient, err := publicips.NewCachedClient(scope); err == nil {
+		svc.publicIPsClient = publicIPsClient
+	} else {
+		scope.Error(err, "failed to create cached public IPs client, falling back to uncached reads")
+	}
+	return svc
 }
 
 // Reconcile gets/creates/updates a bastion host.
@@ -21,22 +21,48 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2/klogr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 
 	"sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
 	mock_bastionhosts "sigs.k8s.io/cluster-api-provider-azure/azure/services/bastionhosts/mocks_bastionhosts"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips/mock_publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets/mock_subnets"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
 
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/golang/mock/gomock"
-
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"k8s.io/client-go/kubernetes/scheme"
-	"k8s.io/klog/v2/klogr"
-	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+var (
+	fakeBastionSpec = azure.BastionSpec{
+		AzureBastion: &azure.AzureBastionSpec{
+			Name:              "my-bastion",
+			DNSName:           "my-bastion.bastion.azure.com",
+			VNetName:          "my-vnet",
+			VNetResourceGroup: "my-rg",
+			SubnetSpec: v1alpha4.SubnetSpec{
+				Name: "my-subnet",
+			},
+			PublicIPName: "my-publicip",
+		},
+	}
+	fakeAzureBastionSpec = AzureBastionSpec{
+		Name:           "my-bastion",
+		ResourceGroup:  "my-rg",
+		Location:       "westus",
+		ClusterName:    "my-cluster",
+		SubnetID:       "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet",
+		PublicIPID:     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-publicip",
+		DNSName:        "my-bastion.bastion.azure.com",
+		AdditionalTags: v1alpha4.Tags{},
+	}
+	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
 )
 
 func init() {
@@ -48,200 +74,75 @@ func TestReconcileBastionHosts(t *testing.T) {
 		name          string
 		expectedError string
 		expect        func(s *mock_bastionhosts.MockBastionScopeMockRecorder,
-			m *mock_bastionhosts.MockclientMockRecorder,
+			r *mock_async.MockReconcilerMockRecorder,
 			mSubnet *mock_subnets.MockClientMockRecorder,
 			mPublicIP *mock_publicips.MockClientMockRecorder)
 	}{
 		{
-			name:          "fail to get subnets",
-			expectedError: "failed to get subnet: #: Internal Server Error: StatusCode=500",
-			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder,
-				m *mock_bastionhosts.MockclientMockRecorder,
-				mSubnet *mock_subnets.MockClientMockRecorder,
-				mPublicIP *mock_publicips.MockClientMockRecorder) {
-				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.BastionSpec().Return(azure.BastionSpec{
-					AzureBastion: &azure.AzureBastionSpec{
-						Name:     "my-bastion",
-						VNetName: "my-vnet",
-						SubnetSpec: v1alpha4.SubnetSpec{
-							Name: "my-subnet",
-						},
-						PublicIPName: "my-publicip",
-					},
-				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				mSubnet.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").
-					Return(network.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
-			},
-		},
-		{
-			name:          "fail to get publicip",
-			expectedError: "failed to get existing publicIP: #: Internal Server Error: StatusCode=500",
+			name:          "bastion host successfully created",
+			expectedError: "",
 			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder,
-				m *mock_bastionhosts.MockclientMockRecorder,
+				r *mock_async.MockReconcilerMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder) {
-				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.BastionSpec().Return(azure.BastionSpec{
-					AzureBastion: &azure.AzureBastionSpec{
-						Name:     "my-bastion",
-						VNetName: "my-vnet",
-						SubnetSpec: v1alpha4.SubnetSpec{
-							Name: "my-subnet",
-						},
-						PublicIPName: "my-publicip",
-					},
-				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.BastionSpec().Return(fakeBastionSpec)
 				gomock.InOrder(
-					mSubnet.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
-					mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")),
+					mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{ID: to.StringPtr(fakeAzureBastionSpec.PublicIPID)}, nil),
+					mSubnet.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{ID: to.StringPtr(fakeAzureBastionSpec.SubnetID)}, nil),
+					r.CreateResource(gomockinternal.AContext(), &fakeAzureBastionSpec, serviceName).Return(network.BastionHost{}, nil),
 				)
 			},
 		},
 		{
-			name:          "create publicip fails",
-			expectedError: "failed to create bastion publicIP: #: Internal Server Error: StatusCode=500",
+			name:          "fail to get public IP",
+			expectedError: "error creating Azure Bastion: failed to get public IP for azure bastion: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder,
-				m *mock_bastionhosts.MockclientMockRecorder,
+				r *mock_async.MockReconcilerMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder) {
-				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.BastionSpec().Return(azure.BastionSpec{
-					AzureBastion: &azure.AzureBastionSpec{
-						Name:     "my-bastion",
-						VNetName: "my-vnet",
-						SubnetSpec: v1alpha4.SubnetSpec{
-							Name: "my-subnet",
-						},
-						PublicIPName: "my-publicip",
-					},
-				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				s.Location().AnyTimes().Return("fake-location")
-				gomock.InOrder(
-					mSubnet.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
-					mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")),
-					mPublicIP.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip", gomock.AssignableToTypeOf(network.PublicIPAddress{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")),
-				)
+				s.BastionSpec().Return(fakeBastionSpec)
+				mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, internalError)
 			},
 		},
 		{
-			name:          "fails to get a created publicip",
-			expectedError: "failed to get created publicIP: #: Internal Server Error: StatusCode=500",
+			name:          "fail to get subnet",
+			expectedError: "error creating Azure Bastion: failed to get subnet for azure bastion: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder,
-				m *mock_bastionhosts.MockclientMockRecorder,
+				r *mock_async.MockReconcilerMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder) {
-				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.BastionSpec().Return(azure.BastionSpec{
-					AzureBastion: &azure.AzureBastionSpec{
-						Name:     "my-bastion",
-						VNetName: "my-vnet",
-						SubnetSpec: v1alpha4.SubnetSpec{
-							Name: "my-subnet",
-						},
-						PublicIPName: "my-publicip",
-					},
-				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				s.Location().AnyTimes().Return("fake-location")
-				mSubnet.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil)
-				mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				mPublicIP.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip", gomock.AssignableToTypeOf(network.PublicIPAddress{})).Return(nil)
-				mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				s.BastionSpec().Return(fakeBastionSpec)
+				mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{ID: to.StringPtr(fakeAzureBastionSpec.PublicIPID)}, nil)
+				mSubnet.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, internalError)
 			},
 		},
 		{
-			name:          "bastion successfully created with created public ip",
-			expectedError: "",
+			name:          "fail to create bastion host",
+			expectedError: "error creating Azure Bastion: cannot create Azure Bastion: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder,
-				m *mock_bastionhosts.MockclientMockRecorder,
+				r *mock_async.MockReconcilerMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder) {
-				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.BastionSpec().Return(azure.BastionSpec{
-					AzureBastion: &azure.AzureBastionSpec{
-						Name:     "my-bastion",
-						VNetName: "my-vnet",
-						SubnetSpec: v1alpha4.SubnetSpec{
-							Name: "my-subnet",
-						},
-						PublicIPName: "my-publicip",
-					},
-				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				s.Location().AnyTimes().Return("fake-location")
-				s.ClusterName().AnyTimes().Return("fake-cluster")
-				gomock.InOrder(
-					mSubnet.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
-					mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")),
-					mPublicIP.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip", gomock.AssignableToTypeOf(network.PublicIPAddress{})).Return(nil),
-					mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, nil),
-					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-bastion", gomock.AssignableToTypeOf(network.BastionHost{})),
-				)
+				s.BastionSpec().Return(fakeBastionSpec)
+				mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{ID: to.StringPtr(fakeAzureBastionSpec.PublicIPID)}, nil)
+				mSubnet.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{ID: to.StringPtr(fakeAzureBastionSpec.SubnetID)}, nil)
+				r.CreateResource(gomockinternal.AContext(), &fakeAzureBastionSpec, serviceName).Return(nil, internalError)
 			},
 		},
 		{
-			name:          "bastion successfully created",
+			name:          "no bastion host",
 			expectedError: "",
 			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder,
-				m *mock_bastionhosts.MockclientMockRecorder,
-				mSubnet *mock_subnets.MockClientMockRecorder,
-				mPublicIP *mock_publicips.MockClientMockRecorder) {
-				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.BastionSpec().Return(azure.BastionSpec{
-					AzureBastion: &azure.AzureBastionSpec{
-						Name:     "my-bastion",
-						VNetName: "my-vnet",
-						SubnetSpec: v1alpha4.SubnetSpec{
-							Name: "my-subnet",
-						},
-						PublicIPName: "my-publicip",
-					},
-				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				s.Location().AnyTimes().Return("fake-location")
-				s.ClusterName().AnyTimes().Return("fake-cluster")
-				gomock.InOrder(
-					mSubnet.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
-					mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, nil),
-					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-bastion", gomock.AssignableToTypeOf(network.BastionHost{})),
-				)
-			},
-		},
-		{
-			name:          "fail to create a bastion",
-			expectedError: "error creating Azure Bastion: cannot create Azure Bastion: #: Internal Server Error: StatusCode=500",
-			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder,
-				m *mock_bastionhosts.MockclientMockRecorder,
+				r *mock_async.MockReconcilerMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder) {
-				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.BastionSpec().Return(azure.BastionSpec{
-					AzureBastion: &azure.AzureBastionSpec{
-						Name:     "my-bastion",
-						VNetName: "my-vnet",
-						SubnetSpec: v1alpha4.SubnetSpec{
-							Name: "my-subnet",
-						},
-						PublicIPName: "my-publicip",
-					},
-				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				s.Location().AnyTimes().Return("fake-location")
-				s.ClusterName().AnyTimes().Return("fake-cluster")
-				gomock.InOrder(
-					mPublicIP.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, nil),
-					mSubnet.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
-					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-bastion", gomock.AssignableToTypeOf(network.BastionHost{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")),
-				)
+				s.BastionSpec().Return(azure.BastionSpec{})
 			},
 		},
 	}
 
 	for _, tc := range testcases {
+		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			g := NewWithT(t)
 
@@ -249,16 +150,20 @@ func TestReconcileBastionHosts(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 			scopeMock := mock_bastionhosts.NewMockBastionScope(mockCtrl)
-			clientMock := mock_bastionhosts.NewMockclient(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
 			subnetMock := mock_subnets.NewMockClient(mockCtrl)
 			publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
 
-			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(),
-				subnetMock.EXPECT(), publicIPsMock.EXPECT())
+			scopeMock.EXPECT().V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			scopeMock.EXPECT().NetworkResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().Location().AnyTimes().Return("westus")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			scopeMock.EXPECT().AdditionalTags().AnyTimes().Return(v1alpha4.Tags{})
+			tc.expect(scopeMock.EXPECT(), reconcilerMock.EXPECT(), subnetMock.EXPECT(), publicIPsMock.EXPECT())
 
 			s := &Service{
 				Scope:           scopeMock,
-				client:          clientMock,
+				Reconciler:      reconcilerMock,
 				subnetsClient:   subnetMock,
 				publicIPsClient: publicIPsMock,
 			}
@@ -278,84 +183,50 @@ func TestDeleteBastionHost(t *testing.T) {
 	testcases := []struct {
 		name          string
 		expectedError string
-		expect        func(s *mock_bastionhosts.MockBastionScopeMockRecorder,
-			m *mock_bastionhosts.MockclientMockRecorder,
-			mSubnet *mock_subnets.MockClientMockRecorder,
-			mPublicIP *mock_publicips.MockClientMockRecorder)
+		expect        func(s *mock_bastionhosts.MockBastionScopeMockRecorder, m *mock_bastionhosts.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder)
 	}{
 		{
-			name: "successfully delete an existing bastion host",
-
+			name:          "successfully delete bastion host",
 			expectedError: "",
-			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder,
-				m *mock_bastionhosts.MockclientMockRecorder,
-				mSubnet *mock_subnets.MockClientMockRecorder,
-				mPublicIP *mock_publicips.MockClientMockRecorder) {
-				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.BastionSpec().Return(azure.BastionSpec{
-					AzureBastion: &azure.AzureBastionSpec{
-						Name:     "my-bastionhost",
-						VNetName: "my-vnet",
-						SubnetSpec: v1alpha4.SubnetSpec{
-							Name: "my-subnet",
-						},
-						PublicIPName: "my-publicip",
-					},
-				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				m.Delete(gomockinternal.AContext(), "my-rg", "my-bastionhost")
+			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder, m *mock_bastionhosts.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BastionSpec().Return(fakeBastionSpec)
+				m.Get(gomockinternal.AContext(), &AzureBastionSpec{Name: "my-bastion", ResourceGroup: "my-rg"}).Return(network.BastionHost{Name: to.StringPtr("my-bastion")}, nil)
+				r.DeleteResource(gomockinternal.AContext(), &AzureBastionSpec{Name: "my-bastion", ResourceGroup: "my-rg"}, serviceName).Return(nil)
 			},
 		},
 		{
-			name:          "bastion host already deleted",
+			name:          "shared bastion host is not deleted",
 			expectedError: "",
-			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder,
-				m *mock_bastionhosts.MockclientMockRecorder,
-				mSubnet *mock_subnets.MockClientMockRecorder,
-				mPublicIP *mock_publicips.MockClientMockRecorder) {
-				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.BastionSpec().Return(azure.BastionSpec{
-					AzureBastion: &azure.AzureBastionSpec{
-						Name:     "my-bastionhost",
-						VNetName: "my-vnet",
-						SubnetSpec: v1alpha4.SubnetSpec{
-							Name: "my-subnet",
-						},
-						PublicIPName: "my-publicip",
+			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder, m *mock_bastionhosts.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BastionSpec().Return(fakeBastionSpec)
+				m.Get(gomockinternal.AContext(), &AzureBastionSpec{Name: "my-bastion", ResourceGroup: "my-rg"}).Return(network.BastionHost{
+					Name: to.StringPtr("my-bastion"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("shared"),
 					},
-				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				m.Delete(gomockinternal.AContext(), "my-rg", "my-bastionhost").
-					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				m.Delete(gomockinternal.AContext(), "my-rg", "my-bastionhost1")
+				}, nil)
 			},
 		},
 		{
 			name:          "bastion host deletion fails",
-			expectedError: "error deleting Azure Bastion: failed to delete Azure Bastion my-bastionhost in resource group my-rg: #: Internal Server Error: StatusCode=500",
-			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder,
-				m *mock_bastionhosts.MockclientMockRecorder,
-				mSubnet *mock_subnets.MockClientMockRecorder,
-				mPublicIP *mock_publicips.MockClientMockRecorder) {
-				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.BastionSpec().Return(azure.BastionSpec{
-					AzureBastion: &azure.AzureBastionSpec{
-						Name:     "my-bastionhost",
-						VNetName: "my-vnet",
-						SubnetSpec: v1alpha4.SubnetSpec{
-							Name: "my-subnet",
-						},
-						PublicIPName: "my-publicip",
-					},
-				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				m.Delete(gomockinternal.AContext(), "my-rg", "my-bastionhost").
-					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			expectedError: "error deleting Azure Bastion: failed to delete Azure Bastion my-bastion in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder, m *mock_bastionhosts.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BastionSpec().Return(fakeBastionSpec)
+				m.Get(gomockinternal.AContext(), &AzureBastionSpec{Name: "my-bastion", ResourceGroup: "my-rg"}).Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusNotFound}, "Not found"))
+				r.DeleteResource(gomockinternal.AContext(), &AzureBastionSpec{Name: "my-bastion", ResourceGroup: "my-rg"}, serviceName).Return(internalError)
+			},
+		},
+		{
+			name:          "no bastion host",
+			expectedError: "",
+			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder, m *mock_bastionhosts.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BastionSpec().Return(azure.BastionSpec{})
 			},
 		},
 	}
 
 	for _, tc := range testcases {
+		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			g := NewWithT(t)
 
@@ -364,17 +235,17 @@ func TestDeleteBastionHost(t *testing.T) {
 			defer mockCtrl.Finish()
 			scopeMock := mock_bastionhosts.NewMockBastionScope(mockCtrl)
 			clientMock := mock_bastionhosts.NewMockclient(mockCtrl)
-			subnetMock := mock_subnets.NewMockClient(mockCtrl)
-			publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
 
-			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(),
-				subnetMock.EXPECT(), publicIPsMock.EXPECT())
+			scopeMock.EXPECT().V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			scopeMock.EXPECT().NetworkResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), reconcilerMock.EXPECT())
 
 			s := &Service{
-				Scope:           scopeMock,
-				client:          clientMock,
-				subnetsClient:   subnetMock,
-				publicIPsClient: publicIPsMock,
+				Scope:      scopeMock,
+				Reconciler: reconcilerMock,
+				client:     clientMock,
 			}
 
 			err := s.Delete(context.TODO())
@@ -21,16 +21,20 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 // client wraps go-sdk.
 type client interface {
-	Get(context.Context, string, string) (network.BastionHost, error)
-	CreateOrUpdate(context.Context, string, string, network.BastionHost) error
-	Delete(context.Context, string, string) error
+	Get(context.Context, azure.ResourceSpecGetter) (interface{}, error)
+	CreateOrUpdateAsync(context.Context, azure.ResourceSpecGetter, interface{}) (*infrav1.Future, error)
+	DeleteAsync(context.Context, azure.ResourceSpecGetter) (*infrav1.Future, error)
+	IsDone(context.Context, *infrav1.Future) (bool, error)
 }
 
 // azureClient contains the Azure go-sdk Client.
@@ -54,43 +58,48 @@ func newBastionHostsClient(subscriptionID string, baseURI string, authorizer aut
 }
 
 // Get gets information about the specified bastion host.
-func (ac *azureClient) Get(ctx context.Context, resourceGroupName, bastionName string) (network.BastionHost, error) {
+func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (interface{}, error) {
 	ctx, span := tele.Tracer().Start(ctx, "bastionhosts.AzureClient.Get")
 	defer span.End()
 
-	return ac.interfaces.Get(ctx, resourceGroupName, bastionName)
+	return ac.interfaces.Get(ctx, spec.ResourceGroupName(), spec.ResourceName())
 }
 
-// CreateOrUpdate creates or updates a bastion host.
-func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, bastionName string, bastionHost network.BastionHost) error {
-	ctx, span := tele.Tracer().Start(ctx, "bastionhosts.AzureClient.CreateOrUpdate")
+// CreateOrUpdateAsync starts creating or updating a bastion host, and returns the future of the long-running operation
+// without waiting for it to complete.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (*infrav1.Future, error) {
+	ctx, span := tele.Tracer().Start(ctx, "bastionhosts.AzureClient.CreateOrUpdateAsync")
 	defer span.End()
 
-	future, err := ac.interfaces.CreateOrUpdate(ctx, resourceGroupName, bastionName, bastionHost)
-	if err != nil {
-		return err
+	bastionHost, ok := parameters.(network.BastionHost)
+	if !ok {
+		return nil, errors.Errorf("%T is not a network.BastionHost", parameters)
 	}
-	err = future.WaitForCompletionRef(ctx, ac.interfaces.Client)
+
+	future, err := ac.interfaces.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), bastionHost)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	_, err = future.Result(ac.interfaces)
-	return err
+	return async.NewFuture(&future, async.PutFuture, serviceName, spec.ResourceGroupName(), spec.ResourceName())
 }
 
-// Delete deletes the specified network interface.
-func (ac *azureClient) Delete(ctx context.Context, resourceGroupName, bastionName string) error {
-	ctx, span := tele.Tracer().Start(ctx, "bastionhosts.AzureClient.Delete")
+// DeleteAsync starts deleting the specified bastion host, and returns the future of the long-running operation
+// without waiting for it to complete.
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (*infrav1.Future, error) {
+	ctx, span := tele.Tracer().Start(ctx, "bastionhosts.AzureClient.DeleteAsync")
 	defer span.End()
 
-	future, err := ac.interfaces.Delete(ctx, resourceGroupName, bastionName)
-	if err != nil {
-		return err
-	}
-	err = future.WaitForCompletionRef(ctx, ac.interfaces.Client)
+	future, err := ac.interfaces.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName())
 	if err != nil {
-		return err
+		return nil, err
 	}
-	_, err = future.Result(ac.interfaces)
-	return err
+	return async.NewFuture(&future, async.DeleteFuture, serviceName, spec.ResourceGroupName(), spec.ResourceName())
+}
+
+// IsDone returns true if the long-running operation of the future is done.
+func (ac *azureClient) IsDone(ctx context.Context, future *infrav1.Future) (bool, error) {
+	ctx, span := tele.Tracer().Start(ctx, "bastionhosts.AzureClient.IsDone")
+	defer span.End()
+
+	return async.IsDone(ctx, ac.interfaces, future)
 }
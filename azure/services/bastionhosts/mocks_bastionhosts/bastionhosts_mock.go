@@ -249,6 +249,18 @@ func (mr *MockBastionScopeMockRecorder) ControlPlaneSubnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnet", reflect.TypeOf((*MockBastionScope)(nil).ControlPlaneSubnet))
 }
 
+// DeleteLongRunningOperationState mocks base method.
+func (m *MockBastionScope) DeleteLongRunningOperationState(name, service string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteLongRunningOperationState", name, service)
+}
+
+// DeleteLongRunningOperationState indicates an expected call of DeleteLongRunningOperationState.
+func (mr *MockBastionScopeMockRecorder) DeleteLongRunningOperationState(name, service interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockBastionScope)(nil).DeleteLongRunningOperationState), name, service)
+}
+
 // Enabled mocks base method.
 func (m *MockBastionScope) Enabled() bool {
 	m.ctrl.T.Helper()
@@ -280,6 +292,20 @@ func (mr *MockBastionScopeMockRecorder) Error(err, msg interface{}, keysAndValue
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockBastionScope)(nil).Error), varargs...)
 }
 
+// GetLongRunningOperationState mocks base method.
+func (m *MockBastionScope) GetLongRunningOperationState(name, service string) *v1alpha4.Future {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLongRunningOperationState", name, service)
+	ret0, _ := ret[0].(*v1alpha4.Future)
+	return ret0
+}
+
+// GetLongRunningOperationState indicates an expected call of GetLongRunningOperationState.
+func (mr *MockBastionScopeMockRecorder) GetLongRunningOperationState(name, service interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongRunningOperationState", reflect.TypeOf((*MockBastionScope)(nil).GetLongRunningOperationState), name, service)
+}
+
 // GetPrivateDNSZoneName mocks base method.
 func (m *MockBastionScope) GetPrivateDNSZoneName() string {
 	m.ctrl.T.Helper()
@@ -381,6 +407,34 @@ func (mr *MockBastionScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockBastionScope)(nil).Location))
 }
 
+// NetworkResourceGroup mocks base method.
+func (m *MockBastionScope) NetworkResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NetworkResourceGroup indicates an expected call of NetworkResourceGroup.
+func (mr *MockBastionScopeMockRecorder) NetworkResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkResourceGroup", reflect.TypeOf((*MockBastionScope)(nil).NetworkResourceGroup))
+}
+
+// NodeOutboundLB mocks base method.
+func (m *MockBastionScope) NodeOutboundLB() *v1alpha4.LoadBalancerSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeOutboundLB")
+	ret0, _ := ret[0].(*v1alpha4.LoadBalancerSpec)
+	return ret0
+}
+
+// NodeOutboundLB indicates an expected call of NodeOutboundLB.
+func (mr *MockBastionScopeMockRecorder) NodeOutboundLB() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeOutboundLB", reflect.TypeOf((*MockBastionScope)(nil).NodeOutboundLB))
+}
+
 // NodeSubnets mocks base method.
 func (m *MockBastionScope) NodeSubnets() []v1alpha4.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -437,6 +491,18 @@ func (mr *MockBastionScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockBastionScope)(nil).ResourceGroup))
 }
 
+// SetLongRunningOperationState mocks base method.
+func (m *MockBastionScope) SetLongRunningOperationState(arg0 *v1alpha4.Future) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLongRunningOperationState", arg0)
+}
+
+// SetLongRunningOperationState indicates an expected call of SetLongRunningOperationState.
+func (mr *MockBastionScopeMockRecorder) SetLongRunningOperationState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockBastionScope)(nil).SetLongRunningOperationState), arg0)
+}
+
 // SetSubnet mocks base method.
 func (m *MockBastionScope) SetSubnet(arg0 v1alpha4.SubnetSpec) {
 	m.ctrl.T.Helper()
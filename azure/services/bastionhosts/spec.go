@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bastionhosts
+
+import (
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// AzureBastionSpec defines the specification for an Azure Bastion host.
+type AzureBastionSpec struct {
+	Name           string
+	ResourceGroup  string
+	Location       string
+	ClusterName    string
+	SubnetID       string
+	PublicIPID     string
+	DNSName        string
+	AdditionalTags infrav1.Tags
+}
+
+var _ azure.ResourceSpecGetter = (*AzureBastionSpec)(nil)
+
+// ResourceName returns the name of the bastion host.
+func (s *AzureBastionSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the bastion host.
+func (s *AzureBastionSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// Parameters returns the parameters for the bastion host, or nil if the existing bastion host is up to date.
+func (s *AzureBastionSpec) Parameters(existing interface{}) (interface{}, error) {
+	bastionHost := network.BastionHost{
+		Name:     to.StringPtr(s.Name),
+		Location: to.StringPtr(s.Location),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.StringPtr(s.Name),
+			Role:        to.StringPtr("Bastion"),
+			Additional:  s.AdditionalTags,
+		})),
+		BastionHostPropertiesFormat: &network.BastionHostPropertiesFormat{
+			DNSName: to.StringPtr(s.DNSName),
+			IPConfigurations: &[]network.BastionHostIPConfiguration{
+				{
+					Name: to.StringPtr(fmt.Sprintf("%s-%s", s.Name, "bastionIP")),
+					BastionHostIPConfigurationPropertiesFormat: &network.BastionHostIPConfigurationPropertiesFormat{
+						Subnet: &network.SubResource{
+							ID: to.StringPtr(s.SubnetID),
+						},
+						PublicIPAddress: &network.SubResource{
+							ID: to.StringPtr(s.PublicIPID),
+						},
+						PrivateIPAllocationMethod: network.IPAllocationMethodDynamic,
+					},
+				},
+			},
+		},
+	}
+
+	if existing != nil {
+		existingBastionHost, ok := existing.(network.BastionHost)
+		if !ok {
+			return nil, errors.Errorf("%T is not a network.BastionHost", existing)
+		}
+		if converters.MapToTags(existingBastionHost.Tags).HasShared(s.ClusterName) {
+			// shared bastion hosts are only read by the cluster
+			return nil, nil
+		}
+		// The DNS name of a bastion host can't be changed, keep the one it was created with, e.g. the legacy
+		// <name>-bastion label of the bastion hosts created before the DNS labels of the cluster were made unique.
+		if existingBastionHost.BastionHostPropertiesFormat != nil && existingBastionHost.DNSName != nil {
+			bastionHost.DNSName = existingBastionHost.DNSName
+		}
+		upToDate, err := azure.IsUpToDate(bastionHost, existingBastionHost)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compare bastion host %s", s.Name)
+		}
+		if upToDate {
+			return nil, nil
+		}
+	}
+
+	return bastionHost, nil
+}
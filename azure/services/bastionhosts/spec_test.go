@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bastionhosts
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+)
+
+func TestParameters(t *testing.T) {
+	upToDate := network.BastionHost{
+		ID:       to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/bastionHosts/my-bastion"),
+		Name:     to.StringPtr("my-bastion"),
+		Location: to.StringPtr("westus"),
+		Tags: map[string]*string{
+			"Name": to.StringPtr("my-bastion"),
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+			"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr("Bastion"),
+		},
+		BastionHostPropertiesFormat: &network.BastionHostPropertiesFormat{
+			DNSName:           to.StringPtr("my-bastion.bastion.azure.com"),
+			ProvisioningState: network.ProvisioningStateSucceeded,
+			IPConfigurations: &[]network.BastionHostIPConfiguration{
+				{
+					Name: to.StringPtr("my-bastion-bastionIP"),
+					BastionHostIPConfigurationPropertiesFormat: &network.BastionHostIPConfigurationPropertiesFormat{
+						Subnet: &network.SubResource{
+							ID: to.StringPtr("/subscriptions/123/resourcegroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet"),
+						},
+						PublicIPAddress: &network.SubResource{
+							ID: to.StringPtr("/subscriptions/123/resourcegroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-publicip"),
+						},
+						PrivateIPAllocationMethod: network.IPAllocationMethodDynamic,
+					},
+				},
+			},
+		},
+	}
+	legacyDNSName := upToDate
+	legacyProperties := *upToDate.BastionHostPropertiesFormat
+	legacyProperties.DNSName = to.StringPtr("my-bastion-bastion")
+	legacyDNSName.BastionHostPropertiesFormat = &legacyProperties
+	outOfDate := upToDate
+	outOfDate.BastionHostPropertiesFormat = &network.BastionHostPropertiesFormat{
+		DNSName: to.StringPtr("other.bastion.azure.com"),
+	}
+	shared := outOfDate
+	shared.Tags = map[string]*string{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("shared"),
+	}
+
+	testcases := []struct {
+		name          string
+		existing      interface{}
+		expectUpdate  bool
+		expectedError string
+	}{
+		{
+			name:         "bastion host doesn't exist",
+			existing:     nil,
+			expectUpdate: true,
+		},
+		{
+			name:         "bastion host is up to date",
+			existing:     upToDate,
+			expectUpdate: false,
+		},
+		{
+			name:         "bastion host with a legacy DNS name is up to date",
+			existing:     legacyDNSName,
+			expectUpdate: false,
+		},
+		{
+			name:         "bastion host is out of date",
+			existing:     outOfDate,
+			expectUpdate: true,
+		},
+		{
+			name:         "shared bastion host is not updated",
+			existing:     shared,
+			expectUpdate: false,
+		},
+		{
+			name:          "existing resource is not a bastion host",
+			existing:      network.Subnet{},
+			expectedError: "network.Subnet is not a network.BastionHost",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			spec := fakeAzureBastionSpec
+			parameters, err := spec.Parameters(tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if !tc.expectUpdate {
+				g.Expect(parameters).To(BeNil())
+				return
+			}
+			g.Expect(parameters).To(BeAssignableToTypeOf(network.BastionHost{}))
+			bastionHost := parameters.(network.BastionHost)
+			g.Expect(bastionHost.Location).To(Equal(to.StringPtr("westus")))
+			if tc.existing != nil {
+				g.Expect(bastionHost.DNSName).To(Equal(tc.existing.(network.BastionHost).DNSName))
+			}
+			g.Expect((*bastionHost.IPConfigurations)[0].Subnet.ID).To(Equal(to.StringPtr(fakeAzureBastionSpec.SubnetID)))
+			g.Expect((*bastionHost.IPConfigurations)[0].PublicIPAddress.ID).To(Equal(to.StringPtr(fakeAzureBastionSpec.PublicIPID)))
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapsecrets
+
+import (
+	"context"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// BootstrapSecretScope defines the scope interface for a bootstrap data secrets service.
+type BootstrapSecretScope interface {
+	logr.Logger
+	BootstrapDataSecretSpec() (*azure.BootstrapDataSecretSpec, error)
+	GetBootstrapDataSecretValue(ctx context.Context) (string, error)
+	SetBootstrapDataSecretState(state infrav1.ProvisioningState)
+	KeyVaultAuthorizer(ctx context.Context) (autorest.Authorizer, error)
+}
+
+// Service provides operations on the Key Vault secrets storing the bootstrap data of the machines.
+type Service struct {
+	Scope BootstrapSecretScope
+	client
+}
+
+// New creates a new bootstrap data secrets service. Its client is only created when a machine has a bootstrap data
+// Key Vault, as Key Vault requests need their own authorizer.
+func New(scope BootstrapSecretScope) *Service {
+	return &Service{
+		Scope: scope,
+	}
+}
+
+// Reconcile creates the Key Vault secret storing the bootstrap data of the machine, and deletes it once the node of the
+// machine has joined the cluster. The state of the secret is recorded, so that it is created and deleted only once
+// without having to be read.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, span := tele.Tracer().Start(ctx, "bootstrapsecrets.Service.Reconcile")
+	defer span.End()
+
+	secretSpec, err := s.Scope.BootstrapDataSecretSpec()
+	if err != nil {
+		return err
+	}
+	if secretSpec == nil {
+		return nil
+	}
+	if secretSpec.Consumed {
+		return s.deleteSecret(ctx, *secretSpec)
+	}
+	if secretSpec.State == infrav1.Succeeded {
+		// the bootstrap data of a machine never changes, so an existing secret is up to date.
+		return nil
+	}
+
+	if err := s.ensureClient(ctx); err != nil {
+		return err
+	}
+	value, err := s.Scope.GetBootstrapDataSecretValue(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.Scope.V(2).Info("creating bootstrap data secret", "secret", secretSpec.Name, "vault", secretSpec.VaultURL)
+	err = s.client.SetSecret(ctx, secretSpec.VaultURL, secretSpec.Name, value)
+	azure.RecordCreate(ctx, "bootstrap data secret", secretSpec.Name, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create bootstrap data secret %s in Key Vault %s", secretSpec.Name, secretSpec.VaultURL)
+	}
+	s.Scope.SetBootstrapDataSecretState(infrav1.Succeeded)
+	s.Scope.V(2).Info("successfully created bootstrap data secret", "secret", secretSpec.Name, "vault", secretSpec.VaultURL)
+	return nil
+}
+
+// Delete deletes the Key Vault secret storing the bootstrap data of the machine, if it still exists.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, span := tele.Tracer().Start(ctx, "bootstrapsecrets.Service.Delete")
+	defer span.End()
+
+	secretSpec, err := s.Scope.BootstrapDataSecretSpec()
+	if err != nil {
+		return err
+	}
+	if secretSpec == nil {
+		return nil
+	}
+	return s.deleteSecret(ctx, *secretSpec)
+}
+
+// deleteSecret deletes a bootstrap data secret, unless it is already recorded as deleted.
+func (s *Service) deleteSecret(ctx context.Context, secretSpec azure.BootstrapDataSecretSpec) error {
+	if secretSpec.State == infrav1.Deleted {
+		return nil
+	}
+	if err := s.ensureClient(ctx); err != nil {
+		return err
+	}
+
+	err := s.client.DeleteSecret(ctx, secretSpec.VaultURL, secretSpec.Name)
+	if azure.ResourceNotFound(err) {
+		// already deleted
+		s.Scope.SetBootstrapDataSecretState(infrav1.Deleted)
+		return nil
+	}
+	azure.RecordDelete(ctx, "bootstrap data secret", secretSpec.Name, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete bootstrap data secret %s in Key Vault %s", secretSpec.Name, secretSpec.VaultURL)
+	}
+	s.Scope.SetBootstrapDataSecretState(infrav1.Deleted)
+	s.Scope.V(2).Info("successfully deleted bootstrap data secret", "secret", secretSpec.Name, "vault", secretSpec.VaultURL)
+	return nil
+}
+
+// ensureClient creates the Key Vault client of the service, unless it already has one.
+func (s *Service) ensureClient(ctx context.Context) error {
+	if s.client != nil {
+		return nil
+	}
+	authorizer, err := s.Scope.KeyVaultAuthorizer(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get an authorizer for Azure Key Vault")
+	}
+	s.client = newClient(authorizer)
+	return nil
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapsecrets
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/v2/klogr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bootstrapsecrets/mock_bootstrapsecrets"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var fakeSecretSpec = azure.BootstrapDataSecretSpec{
+	Name:     "bootstrap-data-1234",
+	VaultURL: "https://my-vault.vault.azure.net",
+}
+
+func TestReconcileBootstrapSecret(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_bootstrapsecrets.MockclientMockRecorder)
+	}{
+		{
+			name:          "noop if the machine has no bootstrap data Key Vault",
+			expectedError: "",
+			expect: func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_bootstrapsecrets.MockclientMockRecorder) {
+				s.BootstrapDataSecretSpec().Return(nil, nil)
+			},
+		},
+		{
+			name:          "secret already created",
+			expectedError: "",
+			expect: func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_bootstrapsecrets.MockclientMockRecorder) {
+				s.BootstrapDataSecretSpec().Return(&azure.BootstrapDataSecretSpec{
+					Name:     "bootstrap-data-1234",
+					VaultURL: "https://my-vault.vault.azure.net",
+					State:    infrav1.Succeeded,
+				}, nil)
+			},
+		},
+		{
+			name:          "creates the secret",
+			expectedError: "",
+			expect: func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_bootstrapsecrets.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BootstrapDataSecretSpec().Return(&fakeSecretSpec, nil)
+				s.GetBootstrapDataSecretValue(gomockinternal.AContext()).Return("#cloud-config", nil)
+				m.SetSecret(gomockinternal.AContext(), "https://my-vault.vault.azure.net", "bootstrap-data-1234", "#cloud-config")
+				s.SetBootstrapDataSecretState(infrav1.Succeeded)
+			},
+		},
+		{
+			name:          "fails to create the secret",
+			expectedError: "failed to create bootstrap data secret bootstrap-data-1234 in Key Vault https://my-vault.vault.azure.net: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_bootstrapsecrets.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BootstrapDataSecretSpec().Return(&fakeSecretSpec, nil)
+				s.GetBootstrapDataSecretValue(gomockinternal.AContext()).Return("#cloud-config", nil)
+				m.SetSecret(gomockinternal.AContext(), "https://my-vault.vault.azure.net", "bootstrap-data-1234", "#cloud-config").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:          "deletes the secret once consumed",
+			expectedError: "",
+			expect: func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_bootstrapsecrets.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BootstrapDataSecretSpec().Return(&azure.BootstrapDataSecretSpec{
+					Name:     "bootstrap-data-1234",
+					VaultURL: "https://my-vault.vault.azure.net",
+					Consumed: true,
+					State:    infrav1.Succeeded,
+				}, nil)
+				m.DeleteSecret(gomockinternal.AContext(), "https://my-vault.vault.azure.net", "bootstrap-data-1234")
+				s.SetBootstrapDataSecretState(infrav1.Deleted)
+			},
+		},
+		{
+			name:          "consumed secret already deleted",
+			expectedError: "",
+			expect: func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_bootstrapsecrets.MockclientMockRecorder) {
+				s.BootstrapDataSecretSpec().Return(&azure.BootstrapDataSecretSpec{
+					Name:     "bootstrap-data-1234",
+					VaultURL: "https://my-vault.vault.azure.net",
+					Consumed: true,
+				}, nil)
+				m.DeleteSecret(gomockinternal.AContext(), "https://my-vault.vault.azure.net", "bootstrap-data-1234").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				s.SetBootstrapDataSecretState(infrav1.Deleted)
+			},
+		},
+		{
+			name:          "consumed secret recorded as deleted",
+			expectedError: "",
+			expect: func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_bootstrapsecrets.MockclientMockRecorder) {
+				s.BootstrapDataSecretSpec().Return(&azure.BootstrapDataSecretSpec{
+					Name:     "bootstrap-data-1234",
+					VaultURL: "https://my-vault.vault.azure.net",
+					Consumed: true,
+					State:    infrav1.Deleted,
+				}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_bootstrapsecrets.NewMockBootstrapSecretScope(mockCtrl)
+			clientMock := mock_bootstrapsecrets.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteBootstrapSecret(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_bootstrapsecrets.MockclientMockRecorder)
+	}{
+		{
+			name:          "noop if the machine has no bootstrap data Key Vault",
+			expectedError: "",
+			expect: func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_bootstrapsecrets.MockclientMockRecorder) {
+				s.BootstrapDataSecretSpec().Return(nil, nil)
+			},
+		},
+		{
+			name:          "deletes the secret",
+			expectedError: "",
+			expect: func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_bootstrapsecrets.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BootstrapDataSecretSpec().Return(&fakeSecretSpec, nil)
+				m.DeleteSecret(gomockinternal.AContext(), "https://my-vault.vault.azure.net", "bootstrap-data-1234")
+				s.SetBootstrapDataSecretState(infrav1.Deleted)
+			},
+		},
+		{
+			name:          "fails to delete the secret",
+			expectedError: "failed to delete bootstrap data secret bootstrap-data-1234 in Key Vault https://my-vault.vault.azure.net: #: Forbidden: StatusCode=403",
+			expect: func(s *mock_bootstrapsecrets.MockBootstrapSecretScopeMockRecorder, m *mock_bootstrapsecrets.MockclientMockRecorder) {
+				s.BootstrapDataSecretSpec().Return(&fakeSecretSpec, nil)
+				m.DeleteSecret(gomockinternal.AContext(), "https://my-vault.vault.azure.net", "bootstrap-data-1234").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 403}, "Forbidden"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_bootstrapsecrets.NewMockBootstrapSecretScope(mockCtrl)
+			clientMock := mock_bootstrapsecrets.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
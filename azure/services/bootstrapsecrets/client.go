@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapsecrets
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	SetSecret(ctx context.Context, vaultURL, name, value string) error
+	DeleteSecret(ctx context.Context, vaultURL, name string) error
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	keyvault keyvault.BaseClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new Key Vault client from an authorizer for the data plane of Azure Key Vault.
+func newClient(authorizer autorest.Authorizer) *azureClient {
+	return &azureClient{newKeyVaultClient(authorizer)}
+}
+
+// newKeyVaultClient creates a new Key Vault client from an authorizer.
+func newKeyVaultClient(authorizer autorest.Authorizer) keyvault.BaseClient {
+	keyVaultClient := keyvault.New()
+	azure.SetAutoRestClientDefaults(&keyVaultClient.Client, authorizer)
+	return keyVaultClient
+}
+
+// SetSecret creates a secret, or a new version of it if it already exists.
+func (ac *azureClient) SetSecret(ctx context.Context, vaultURL, name, value string) error {
+	ctx, span := tele.Tracer().Start(ctx, "bootstrapsecrets.AzureClient.SetSecret")
+	defer span.End()
+
+	_, err := ac.keyvault.SetSecret(ctx, vaultURL, name, keyvault.SecretSetParameters{
+		Value:       to.StringPtr(value),
+		ContentType: to.StringPtr("text/plain"),
+	})
+	return err
+}
+
+// DeleteSecret deletes all the versions of a secret.
+func (ac *azureClient) DeleteSecret(ctx context.Context, vaultURL, name string) error {
+	ctx, span := tele.Tracer().Start(ctx, "bootstrapsecrets.AzureClient.DeleteSecret")
+	defer span.End()
+
+	_, err := ac.keyvault.DeleteSecret(ctx, vaultURL, name)
+	return err
+}
@@ -0,0 +1,206 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../bootstrapsecrets.go
+
+// Package mock_bootstrapsecrets is a generated GoMock package.
+package mock_bootstrapsecrets
+
+import (
+	context "context"
+	reflect "reflect"
+
+	autorest "github.com/Azure/go-autorest/autorest"
+	logr "github.com/go-logr/logr"
+	gomock "github.com/golang/mock/gomock"
+	v1alpha4 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// MockBootstrapSecretScope is a mock of BootstrapSecretScope interface.
+type MockBootstrapSecretScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockBootstrapSecretScopeMockRecorder
+}
+
+// MockBootstrapSecretScopeMockRecorder is the mock recorder for MockBootstrapSecretScope.
+type MockBootstrapSecretScopeMockRecorder struct {
+	mock *MockBootstrapSecretScope
+}
+
+// NewMockBootstrapSecretScope creates a new mock instance.
+func NewMockBootstrapSecretScope(ctrl *gomock.Controller) *MockBootstrapSecretScope {
+	mock := &MockBootstrapSecretScope{ctrl: ctrl}
+	mock.recorder = &MockBootstrapSecretScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBootstrapSecretScope) EXPECT() *MockBootstrapSecretScopeMockRecorder {
+	return m.recorder
+}
+
+// BootstrapDataSecretSpec mocks base method.
+func (m *MockBootstrapSecretScope) BootstrapDataSecretSpec() (*azure.BootstrapDataSecretSpec, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataSecretSpec")
+	ret0, _ := ret[0].(*azure.BootstrapDataSecretSpec)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BootstrapDataSecretSpec indicates an expected call of BootstrapDataSecretSpec.
+func (mr *MockBootstrapSecretScopeMockRecorder) BootstrapDataSecretSpec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataSecretSpec", reflect.TypeOf((*MockBootstrapSecretScope)(nil).BootstrapDataSecretSpec))
+}
+
+// Enabled mocks base method.
+func (m *MockBootstrapSecretScope) Enabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Enabled indicates an expected call of Enabled.
+func (mr *MockBootstrapSecretScopeMockRecorder) Enabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enabled", reflect.TypeOf((*MockBootstrapSecretScope)(nil).Enabled))
+}
+
+// Error mocks base method.
+func (m *MockBootstrapSecretScope) Error(err error, msg string, keysAndValues ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{err, msg}
+	for _, a := range keysAndValues {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Error", varargs...)
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockBootstrapSecretScopeMockRecorder) Error(err, msg interface{}, keysAndValues ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{err, msg}, keysAndValues...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockBootstrapSecretScope)(nil).Error), varargs...)
+}
+
+// GetBootstrapDataSecretValue mocks base method.
+func (m *MockBootstrapSecretScope) GetBootstrapDataSecretValue(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBootstrapDataSecretValue", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBootstrapDataSecretValue indicates an expected call of GetBootstrapDataSecretValue.
+func (mr *MockBootstrapSecretScopeMockRecorder) GetBootstrapDataSecretValue(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBootstrapDataSecretValue", reflect.TypeOf((*MockBootstrapSecretScope)(nil).GetBootstrapDataSecretValue), ctx)
+}
+
+// Info mocks base method.
+func (m *MockBootstrapSecretScope) Info(msg string, keysAndValues ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{msg}
+	for _, a := range keysAndValues {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Info", varargs...)
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockBootstrapSecretScopeMockRecorder) Info(msg interface{}, keysAndValues ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{msg}, keysAndValues...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockBootstrapSecretScope)(nil).Info), varargs...)
+}
+
+// KeyVaultAuthorizer mocks base method.
+func (m *MockBootstrapSecretScope) KeyVaultAuthorizer(ctx context.Context) (autorest.Authorizer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeyVaultAuthorizer", ctx)
+	ret0, _ := ret[0].(autorest.Authorizer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// KeyVaultAuthorizer indicates an expected call of KeyVaultAuthorizer.
+func (mr *MockBootstrapSecretScopeMockRecorder) KeyVaultAuthorizer(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeyVaultAuthorizer", reflect.TypeOf((*MockBootstrapSecretScope)(nil).KeyVaultAuthorizer), ctx)
+}
+
+// SetBootstrapDataSecretState mocks base method.
+func (m *MockBootstrapSecretScope) SetBootstrapDataSecretState(state v1alpha4.ProvisioningState) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetBootstrapDataSecretState", state)
+}
+
+// SetBootstrapDataSecretState indicates an expected call of SetBootstrapDataSecretState.
+func (mr *MockBootstrapSecretScopeMockRecorder) SetBootstrapDataSecretState(state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBootstrapDataSecretState", reflect.TypeOf((*MockBootstrapSecretScope)(nil).SetBootstrapDataSecretState), state)
+}
+
+// V mocks base method.
+func (m *MockBootstrapSecretScope) V(level int) logr.Logger {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "V", level)
+	ret0, _ := ret[0].(logr.Logger)
+	return ret0
+}
+
+// V indicates an expected call of V.
+func (mr *MockBootstrapSecretScopeMockRecorder) V(level interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V", reflect.TypeOf((*MockBootstrapSecretScope)(nil).V), level)
+}
+
+// WithName mocks base method.
+func (m *MockBootstrapSecretScope) WithName(name string) logr.Logger {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithName", name)
+	ret0, _ := ret[0].(logr.Logger)
+	return ret0
+}
+
+// WithName indicates an expected call of WithName.
+func (mr *MockBootstrapSecretScopeMockRecorder) WithName(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithName", reflect.TypeOf((*MockBootstrapSecretScope)(nil).WithName), name)
+}
+
+// WithValues mocks base method.
+func (m *MockBootstrapSecretScope) WithValues(keysAndValues ...interface{}) logr.Logger {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range keysAndValues {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WithValues", varargs...)
+	ret0, _ := ret[0].(logr.Logger)
+	return ret0
+}
+
+// WithValues indicates an expected call of WithValues.
+func (mr *MockBootstrapSecretScopeMockRecorder) WithValues(keysAndValues ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithValues", reflect.TypeOf((*MockBootstrapSecretScope)(nil).WithValues), keysAndValues...)
+}
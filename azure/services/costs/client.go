@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// retailPricesURL is the endpoint of the Azure Retail Prices API, which doesn't require authentication.
+	// See https://docs.microsoft.com/en-us/rest/api/cost-management/retail-prices/azure-retail-prices.
+	retailPricesURL = "https://prices.azure.com/api/retail/prices"
+	// maxPages bounds the number of pages listed for a filter, as the filters only match a few prices.
+	maxPages = 10
+)
+
+// Price is a retail price of the Azure Retail Prices API.
+type Price struct {
+	CurrencyCode  string  `json:"currencyCode"`
+	RetailPrice   float64 `json:"retailPrice"`
+	ArmRegionName string  `json:"armRegionName"`
+	ArmSkuName    string  `json:"armSkuName"`
+	ServiceName   string  `json:"serviceName"`
+	ProductName   string  `json:"productName"`
+	SkuName       string  `json:"skuName"`
+	MeterName     string  `json:"meterName"`
+	UnitOfMeasure string  `json:"unitOfMeasure"`
+	Type          string  `json:"type"`
+}
+
+// pricesPage is a page of the Azure Retail Prices API.
+type pricesPage struct {
+	Items        []Price `json:"Items"`
+	NextPageLink string  `json:"NextPageLink"`
+}
+
+// client wraps the Azure Retail Prices API.
+type client interface {
+	ListPrices(ctx context.Context, filter string) ([]Price, error)
+}
+
+// retailPricesClient lists prices from the Azure Retail Prices API, and caches them as they rarely change.
+type retailPricesClient struct {
+	baseURL    string
+	httpClient *http.Client
+	cache      ttllru.PeekingCacher
+}
+
+var _ client = (*retailPricesClient)(nil)
+
+var (
+	doOnce        sync.Once
+	defaultClient *retailPricesClient
+	clientErr     error
+)
+
+// getClient returns the client shared by all the clusters, so that they share its cache. Like the Azure clients, it
+// sends its requests through the configured proxy and trusts the configured CA certificates, if any.
+func getClient() (*retailPricesClient, error) {
+	doOnce.Do(func() {
+		httpClient := &http.Client{}
+		if sender := azure.HTTPSender(); sender != nil {
+			*httpClient = *sender
+		}
+		httpClient.Timeout = 30 * time.Second
+		defaultClient, clientErr = newClient(retailPricesURL, httpClient)
+	})
+	return defaultClient, clientErr
+}
+
+// newClient creates a new Azure Retail Prices API client.
+func newClient(baseURL string, httpClient *http.Client) (*retailPricesClient, error) {
+	cache, err := ttllru.New(1024, 24*time.Hour)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating LRU cache for retail prices")
+	}
+	return &retailPricesClient{
+		baseURL:    baseURL,
+		httpClient: httpClient,
+		cache:      cache,
+	}, nil
+}
+
+// ListPrices lists the retail prices matching an OData filter, e.g. "serviceName eq 'Virtual Machines'".
+func (c *retailPricesClient) ListPrices(ctx context.Context, filter string) ([]Price, error) {
+	ctx, span := tele.Tracer().Start(ctx, "costs.retailPricesClient.ListPrices")
+	defer span.End()
+
+	// Peek doesn't extend the time to live of the prices, so that they are refreshed daily even if they are used often.
+	if prices, _, ok := c.cache.Peek(filter); ok {
+		return prices.([]Price), nil
+	}
+
+	var prices []Price
+	next := c.baseURL + "?" + url.Values{"$filter": []string{filter}}.Encode()
+	for page := 0; next != ""; page++ {
+		if page == maxPages {
+			return nil, errors.Errorf("more than %d pages of retail prices match %q", maxPages, filter)
+		}
+		result, err := c.getPage(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, result.Items...)
+		next = result.NextPageLink
+	}
+
+	_ = c.cache.Add(filter, prices)
+	return prices, nil
+}
+
+// getPage gets a page of retail prices.
+func (c *retailPricesClient) getPage(ctx context.Context, pageURL string) (*pricesPage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create retail prices request")
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get retail prices")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to get retail prices: %s", resp.Status)
+	}
+	page := &pricesPage{}
+	if err := json.NewDecoder(resp.Body).Decode(page); err != nil {
+		return nil, errors.Wrap(err, "failed to decode retail prices")
+	}
+	return page, nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestListPrices(t *testing.T) {
+	g := NewWithT(t)
+
+	filter := "serviceName eq 'Virtual Machines' and armRegionName eq 'eastus'"
+	requests := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		g.Expect(r.URL.Query().Get("$filter")).To(Equal(filter))
+		page := pricesPage{Items: []Price{{ArmSkuName: "Standard_D2s_v3", RetailPrice: 0.096}}}
+		if r.URL.Query().Get("$skip") == "" {
+			page = pricesPage{
+				Items:        []Price{{ArmSkuName: "Standard_B2s", RetailPrice: 0.0416}},
+				NextPageLink: server.URL + "?" + url.Values{"$filter": []string{filter}, "$skip": []string{"100"}}.Encode(),
+			}
+		}
+		g.Expect(json.NewEncoder(w).Encode(page)).To(Succeed())
+	}))
+	defer server.Close()
+
+	c, err := newClient(server.URL, server.Client())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	prices, err := c.ListPrices(context.TODO(), filter)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(prices).To(Equal([]Price{
+		{ArmSkuName: "Standard_B2s", RetailPrice: 0.0416},
+		{ArmSkuName: "Standard_D2s_v3", RetailPrice: 0.096},
+	}))
+	g.Expect(requests).To(Equal(2))
+
+	// The prices are cached.
+	_, err = c.ListPrices(context.TODO(), filter)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(requests).To(Equal(2))
+}
+
+func TestListPricesFailure(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c, err := newClient(server.URL, server.Client())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = c.ListPrices(context.TODO(), "serviceName eq 'Storage'")
+	g.Expect(err).To(MatchError("failed to get retail prices: 429 Too Many Requests"))
+}
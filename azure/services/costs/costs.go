@@ -0,0 +1,236 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costs
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// hoursPerMonth is the number of hours in a month the Azure pricing calculator uses to estimate monthly costs.
+	hoursPerMonth = 730
+	// currency is the currency of the Azure retail prices when none is requested.
+	currency = "USD"
+)
+
+// diskTiers are the sizes in GB of the managed disk tiers, by tier number, e.g. P10 and E10 disks hold 128 GB.
+var diskTiers = []struct {
+	number int
+	sizeGB int32
+}{
+	{1, 4}, {2, 8}, {3, 16}, {4, 32}, {6, 64}, {10, 128}, {15, 256}, {20, 512}, {30, 1024},
+	{40, 2048}, {50, 4096}, {60, 8192}, {70, 16384}, {80, 32767},
+}
+
+// diskProducts are the products and tier prefixes of the managed disks, by storage account type. Standard HDD disks
+// start at the S4 tier. Ultra disks are billed by provisioned performance and are not priced.
+var diskProducts = map[string]struct {
+	productName string
+	tierPrefix  string
+	redundancy  string
+	minTier     int
+}{
+	"Premium_LRS":     {"Premium SSD Managed Disks", "P", "LRS", 1},
+	"Premium_ZRS":     {"Premium SSD Managed Disks", "P", "ZRS", 1},
+	"StandardSSD_LRS": {"Standard SSD Managed Disks", "E", "LRS", 1},
+	"StandardSSD_ZRS": {"Standard SSD Managed Disks", "E", "ZRS", 1},
+	"Standard_LRS":    {"Standard HDD Managed Disks", "S", "LRS", 4},
+}
+
+// Scope defines the scope interface for a costs service.
+type Scope interface {
+	logr.Logger
+	Location() string
+	CostSpecs(ctx context.Context) ([]azure.CostSpec, error)
+	CostEstimate() *infrav1.CostEstimate
+	SetCostEstimate(infrav1.CostEstimate)
+}
+
+// Service estimates the monthly cost of Azure resources.
+type Service struct {
+	Scope Scope
+	client
+}
+
+// New creates a new costs service.
+func New(scope Scope) (*Service, error) {
+	c, err := getClient()
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:  scope,
+		client: c,
+	}, nil
+}
+
+// Reconcile publishes the estimated monthly cost of the resources of the cluster and its machines.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, span := tele.Tracer().Start(ctx, "costs.Service.Reconcile")
+	defer span.End()
+
+	specs, err := s.Scope.CostSpecs(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the resources to estimate the cost of")
+	}
+
+	estimate := infrav1.CostEstimate{Currency: currency}
+	total := 0.0
+	for _, spec := range specs {
+		monthly, sku, ok, err := s.monthlyPrice(ctx, spec)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the price of %s", spec.Name)
+		}
+		if !ok {
+			s.Scope.V(4).Info("no retail price found", "name", spec.Name, "type", spec.Type, "sku", sku)
+			estimate.UnpricedResources = append(estimate.UnpricedResources, spec.Name)
+			continue
+		}
+		total += monthly
+		estimate.Resources = append(estimate.Resources, infrav1.ResourceCost{
+			Name:    spec.Name,
+			Type:    spec.Type,
+			SKU:     sku,
+			Monthly: formatPrice(monthly),
+		})
+	}
+	estimate.MonthlyTotal = formatPrice(total)
+
+	// The estimate is only updated when it changes, to avoid patching the status on every reconcile.
+	if current := s.Scope.CostEstimate(); current != nil {
+		estimate.LastUpdated = current.LastUpdated
+		if reflect.DeepEqual(*current, estimate) {
+			return nil
+		}
+	}
+	now := metav1.Now()
+	estimate.LastUpdated = &now
+	s.Scope.V(2).Info("estimated monthly cost", "total", estimate.MonthlyTotal, "currency", estimate.Currency)
+	s.Scope.SetCostEstimate(estimate)
+	return nil
+}
+
+// Delete is a no-op as the cost estimate doesn't map to any Azure resource.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
+
+// monthlyPrice returns the estimated monthly price of a resource and the SKU it is priced at. It returns false if the
+// resource has no retail price.
+func (s *Service) monthlyPrice(ctx context.Context, spec azure.CostSpec) (float64, string, bool, error) {
+	location := s.Scope.Location()
+	switch spec.Type {
+	case azure.VirtualMachineResourceType:
+		filter := fmt.Sprintf("serviceName eq 'Virtual Machines' and armRegionName eq '%s' and armSkuName eq '%s' and priceType eq 'Consumption'", location, spec.SKU)
+		price, ok, err := s.findPrice(ctx, filter, func(p Price) bool {
+			return !strings.Contains(p.SkuName, "Low Priority") &&
+				strings.Contains(p.SkuName, "Spot") == spec.IsSpot &&
+				strings.Contains(p.ProductName, "Windows") == spec.IsWindows
+		})
+		return price * hoursPerMonth, spec.SKU, ok, err
+
+	case azure.DiskResourceType:
+		product, ok := diskProducts[spec.SKU]
+		if !ok {
+			return 0, spec.SKU, false, nil
+		}
+		tier := diskTier(product.tierPrefix, product.minTier, spec.DiskSizeGB)
+		if tier == "" {
+			return 0, spec.SKU, false, nil
+		}
+		skuName := tier + " " + product.redundancy
+		filter := fmt.Sprintf("serviceName eq 'Storage' and armRegionName eq '%s' and productName eq '%s' and skuName eq '%s'", location, product.productName, skuName)
+		price, ok, err := s.findPrice(ctx, filter, func(p Price) bool {
+			return p.MeterName == skuName+" Disk"
+		})
+		return price, skuName, ok, err
+
+	case azure.PublicIPResourceType:
+		version := "IPv4"
+		if spec.IsIPv6 {
+			version = "IPv6"
+		}
+		meterName := fmt.Sprintf("%s %s Static Public IP", spec.SKU, version)
+		filter := fmt.Sprintf("serviceName eq 'Virtual Network' and armRegionName eq '%s' and productName eq 'IP Addresses' and meterName eq '%s'", location, meterName)
+		price, ok, err := s.findPrice(ctx, filter, nil)
+		return price * hoursPerMonth, spec.SKU, ok, err
+
+	case azure.LoadBalancerResourceType:
+		// Basic load balancers are free.
+		if spec.SKU != string(infrav1.SKUStandard) {
+			return 0, spec.SKU, true, nil
+		}
+		filter := fmt.Sprintf("serviceName eq 'Load Balancer' and armRegionName eq '%s' and skuName eq 'Standard' and meterName eq 'Standard Included LB Rules and Outbound Rules'", location)
+		price, ok, err := s.findPrice(ctx, filter, nil)
+		return price * hoursPerMonth, spec.SKU, ok, err
+
+	case azure.NatGatewayResourceType:
+		filter := fmt.Sprintf("serviceName eq 'NAT Gateway' and armRegionName eq '%s' and meterName eq 'Standard Gateway'", location)
+		price, ok, err := s.findPrice(ctx, filter, nil)
+		return price * hoursPerMonth, spec.SKU, ok, err
+
+	case azure.BastionHostResourceType:
+		filter := fmt.Sprintf("serviceName eq 'Azure Bastion' and armRegionName eq '%s' and skuName eq '%s' and meterName eq '%s Gateway'", location, spec.SKU, spec.SKU)
+		price, ok, err := s.findPrice(ctx, filter, nil)
+		return price * hoursPerMonth, spec.SKU, ok, err
+	}
+
+	return 0, spec.SKU, false, nil
+}
+
+// findPrice returns the first consumption price matching the filter and, if not nil, the match function.
+func (s *Service) findPrice(ctx context.Context, filter string, match func(Price) bool) (float64, bool, error) {
+	prices, err := s.client.ListPrices(ctx, filter)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, price := range prices {
+		if price.Type != "Consumption" || price.CurrencyCode != currency {
+			continue
+		}
+		if match == nil || match(price) {
+			return price.RetailPrice, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// diskTier returns the smallest tier of a managed disk product holding sizeGB, e.g. P10 for a 100 GB premium SSD.
+func diskTier(prefix string, minTier int, sizeGB int32) string {
+	for _, tier := range diskTiers {
+		if tier.number >= minTier && tier.sizeGB >= sizeGB {
+			return fmt.Sprintf("%s%d", prefix, tier.number)
+		}
+	}
+	return ""
+}
+
+// formatPrice formats a price with two decimals.
+func formatPrice(price float64) string {
+	return fmt.Sprintf("%.2f", price)
+}
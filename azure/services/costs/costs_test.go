@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2/klogr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/costs/mock_costs"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+// fakeClient returns the prices of each filter.
+type fakeClient map[string][]Price
+
+func (c fakeClient) ListPrices(_ context.Context, filter string) ([]Price, error) {
+	if prices, ok := c[filter]; ok {
+		return prices, nil
+	}
+	return nil, errors.Errorf("unexpected filter %q", filter)
+}
+
+var fakePrices = fakeClient{
+	"serviceName eq 'Virtual Machines' and armRegionName eq 'eastus' and armSkuName eq 'Standard_D2s_v3' and priceType eq 'Consumption'": {
+		{CurrencyCode: "USD", Type: "Consumption", SkuName: "D2s v3", ProductName: "Virtual Machines DSv3 Series Windows", RetailPrice: 0.188},
+		{CurrencyCode: "USD", Type: "Consumption", SkuName: "D2s v3 Spot", ProductName: "Virtual Machines DSv3 Series", RetailPrice: 0.0192},
+		{CurrencyCode: "USD", Type: "Consumption", SkuName: "D2s v3 Low Priority", ProductName: "Virtual Machines DSv3 Series", RetailPrice: 0.0192},
+		{CurrencyCode: "USD", Type: "Consumption", SkuName: "D2s v3", ProductName: "Virtual Machines DSv3 Series", RetailPrice: 0.096},
+	},
+	"serviceName eq 'Storage' and armRegionName eq 'eastus' and productName eq 'Premium SSD Managed Disks' and skuName eq 'P10 LRS'": {
+		{CurrencyCode: "USD", Type: "Consumption", SkuName: "P10 LRS", MeterName: "P10 LRS Disk Mount", RetailPrice: 1.23},
+		{CurrencyCode: "USD", Type: "Consumption", SkuName: "P10 LRS", MeterName: "P10 LRS Disk", RetailPrice: 19.71},
+	},
+	"serviceName eq 'Virtual Network' and armRegionName eq 'eastus' and productName eq 'IP Addresses' and meterName eq 'Standard IPv4 Static Public IP'": {
+		{CurrencyCode: "USD", Type: "Consumption", RetailPrice: 0.005},
+	},
+	"serviceName eq 'Load Balancer' and armRegionName eq 'eastus' and skuName eq 'Standard' and meterName eq 'Standard Included LB Rules and Outbound Rules'": {
+		{CurrencyCode: "USD", Type: "Consumption", RetailPrice: 0.025},
+	},
+}
+
+func TestReconcileCosts(t *testing.T) {
+	lastUpdated := metav1.Now()
+	estimate := infrav1.CostEstimate{
+		Currency:     "USD",
+		MonthlyTotal: "111.69",
+		Resources: []infrav1.ResourceCost{
+			{Name: "my-lb", Type: azure.LoadBalancerResourceType, SKU: "Standard", Monthly: "18.25"},
+			{Name: "my-ip", Type: azure.PublicIPResourceType, SKU: "Standard", Monthly: "3.65"},
+			{Name: "my-vm", Type: azure.VirtualMachineResourceType, SKU: "Standard_D2s_v3", Monthly: "70.08"},
+			{Name: "my-vm_OSDisk", Type: azure.DiskResourceType, SKU: "P10 LRS", Monthly: "19.71"},
+			{Name: "my-internal-lb", Type: azure.LoadBalancerResourceType, SKU: "Basic", Monthly: "0.00"},
+		},
+		UnpricedResources: []string{"my-vm_ultra"},
+	}
+	specs := []azure.CostSpec{
+		{Name: "my-lb", Type: azure.LoadBalancerResourceType, SKU: "Standard"},
+		{Name: "my-ip", Type: azure.PublicIPResourceType, SKU: "Standard"},
+		{Name: "my-vm", Type: azure.VirtualMachineResourceType, SKU: "Standard_D2s_v3"},
+		{Name: "my-vm_OSDisk", Type: azure.DiskResourceType, SKU: "Premium_LRS", DiskSizeGB: 100},
+		{Name: "my-internal-lb", Type: azure.LoadBalancerResourceType, SKU: "Basic"},
+		{Name: "my-vm_ultra", Type: azure.DiskResourceType, SKU: "UltraSSD_LRS", DiskSizeGB: 1024},
+	}
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_costs.MockScopeMockRecorder, set func(infrav1.CostEstimate))
+	}{
+		{
+			name:          "publishes the estimated monthly cost",
+			expectedError: "",
+			expect: func(s *mock_costs.MockScopeMockRecorder, set func(infrav1.CostEstimate)) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.Location().AnyTimes().Return("eastus")
+				s.CostSpecs(gomockinternal.AContext()).Return(specs, nil)
+				s.CostEstimate().Return(nil)
+				s.SetCostEstimate(gomock.Any()).Do(set)
+			},
+		},
+		{
+			name:          "leaves an unchanged estimate alone",
+			expectedError: "",
+			expect: func(s *mock_costs.MockScopeMockRecorder, set func(infrav1.CostEstimate)) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.Location().AnyTimes().Return("eastus")
+				s.CostSpecs(gomockinternal.AContext()).Return(specs, nil)
+				current := estimate
+				current.LastUpdated = &lastUpdated
+				s.CostEstimate().Return(&current)
+			},
+		},
+		{
+			name:          "fail to get a price",
+			expectedError: "failed to get the price of my-vm: unexpected filter \"serviceName eq 'Virtual Machines' and armRegionName eq 'westus' and armSkuName eq 'Standard_D2s_v3' and priceType eq 'Consumption'\"",
+			expect: func(s *mock_costs.MockScopeMockRecorder, set func(infrav1.CostEstimate)) {
+				s.Location().AnyTimes().Return("westus")
+				s.CostSpecs(gomockinternal.AContext()).Return([]azure.CostSpec{specs[2]}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_costs.NewMockScope(mockCtrl)
+
+			var published *infrav1.CostEstimate
+			tc.expect(scopeMock.EXPECT(), func(e infrav1.CostEstimate) { published = &e })
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: fakePrices,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if published != nil {
+				g.Expect(published.LastUpdated).NotTo(BeNil())
+				published.LastUpdated = nil
+				g.Expect(*published).To(Equal(estimate))
+			}
+		})
+	}
+}
+
+func TestDiskTier(t *testing.T) {
+	testcases := []struct {
+		prefix  string
+		minTier int
+		sizeGB  int32
+		want    string
+	}{
+		{prefix: "P", minTier: 1, sizeGB: 4, want: "P1"},
+		{prefix: "P", minTier: 1, sizeGB: 30, want: "P4"},
+		{prefix: "E", minTier: 1, sizeGB: 128, want: "E10"},
+		{prefix: "E", minTier: 1, sizeGB: 129, want: "E15"},
+		{prefix: "S", minTier: 4, sizeGB: 4, want: "S4"},
+		{prefix: "P", minTier: 1, sizeGB: 32768, want: ""},
+	}
+	for _, tc := range testcases {
+		g := NewWithT(t)
+		g.Expect(diskTier(tc.prefix, tc.minTier, tc.sizeGB)).To(Equal(tc.want))
+	}
+}
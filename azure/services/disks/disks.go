@@ -19,10 +19,13 @@ package disks
 import (
 	"context"
 
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -36,22 +39,56 @@ type DiskScope interface {
 // Service provides operations on Azure resources.
 type Service struct {
 	Scope DiskScope
-	client
+	Client
 }
 
 // New creates a new disks service.
 func New(scope DiskScope) *Service {
 	return &Service{
 		Scope:  scope,
-		client: newClient(scope),
+		Client: NewClient(scope),
 	}
 }
 
-// Reconcile on disk is currently no-op. OS disks should only be deleted and will create with the VM automatically.
+// Reconcile ensures the disks of a VM carry the cluster tags and the additional tags.
+// Disks are created with the VM automatically, but don't inherit the tags of the VM.
 func (s *Service) Reconcile(ctx context.Context) error {
-	_, span := tele.Tracer().Start(ctx, "disks.Service.Reconcile")
+	ctx, span := tele.Tracer().Start(ctx, "disks.Service.Reconcile")
 	defer span.End()
 
+	for _, diskSpec := range s.Scope.DiskSpecs() {
+		disk, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), diskSpec.Name)
+		if err != nil && azure.ResourceNotFound(err) {
+			// the disk hasn't been created by the VM yet
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to get disk %s in resource group %s", diskSpec.Name, s.Scope.ResourceGroup())
+		}
+
+		tags := converters.MapToTags(disk.Tags)
+		changed := false
+		for k, v := range infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.Scope.ClusterName(),
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.StringPtr(diskSpec.Name),
+			Additional:  s.Scope.AdditionalTags(),
+		}) {
+			if existing, ok := tags[k]; !ok || existing != v {
+				tags[k] = v
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		s.Scope.V(2).Info("updating disk tags", "disk", diskSpec.Name)
+		if err := s.Client.UpdateTags(ctx, s.Scope.ResourceGroup(), diskSpec.Name, converters.TagsToMap(tags)); err != nil {
+			return errors.Wrapf(err, "failed to update tags of disk %s in resource group %s", diskSpec.Name, s.Scope.ResourceGroup())
+		}
+		s.Scope.V(2).Info("successfully updated disk tags", "disk", diskSpec.Name)
+	}
 	return nil
 }
 
@@ -62,7 +99,8 @@ func (s *Service) Delete(ctx context.Context) error {
 
 	for _, diskSpec := range s.Scope.DiskSpecs() {
 		s.Scope.V(2).Info("deleting disk", "disk", diskSpec.Name)
-		err := s.client.Delete(ctx, s.Scope.ResourceGroup(), diskSpec.Name)
+		err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), diskSpec.Name)
+		azure.RecordDelete(ctx, "disk", diskSpec.Name, err)
 		if err != nil && azure.ResourceNotFound(err) {
 			// already deleted
 			continue
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsforwardingrulesets
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// apiVersion is the Microsoft.Network DNS Private Resolver API version used for ruleset virtual network links.
+// The SDK does not ship a DNS Private Resolver client, so links are managed through the generic resources API.
+const apiVersion = "2022-07-01"
+
+// client wraps go-sdk.
+type client interface {
+	GetLink(context.Context, string) (resources.GenericResource, error)
+	CreateOrUpdateLink(context.Context, string, resources.GenericResource) error
+	DeleteLink(context.Context, string) error
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	resources resources.Client
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new DNS forwarding ruleset client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	c := newResourcesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &azureClient{
+		resources: c,
+	}
+}
+
+// newResourcesClient creates a new generic resources client from subscription ID.
+func newResourcesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) resources.Client {
+	resourcesClient := resources.NewClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&resourcesClient.Client, authorizer)
+	return resourcesClient
+}
+
+// GetLink gets the DNS forwarding ruleset virtual network link with the given resource ID.
+func (ac *azureClient) GetLink(ctx context.Context, linkID string) (resources.GenericResource, error) {
+	ctx, span := tele.Tracer().Start(ctx, "dnsforwardingrulesets.AzureClient.GetLink")
+	defer span.End()
+
+	return ac.resources.GetByID(ctx, linkID, apiVersion)
+}
+
+// CreateOrUpdateLink creates or updates the DNS forwarding ruleset virtual network link with the given resource ID.
+func (ac *azureClient) CreateOrUpdateLink(ctx context.Context, linkID string, link resources.GenericResource) error {
+	ctx, span := tele.Tracer().Start(ctx, "dnsforwardingrulesets.AzureClient.CreateOrUpdateLink")
+	defer span.End()
+
+	future, err := ac.resources.CreateOrUpdateByID(ctx, linkID, apiVersion, link)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.resources.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.resources)
+	return err
+}
+
+// DeleteLink deletes the DNS forwarding ruleset virtual network link with the given resource ID.
+func (ac *azureClient) DeleteLink(ctx context.Context, linkID string) error {
+	ctx, span := tele.Tracer().Start(ctx, "dnsforwardingrulesets.AzureClient.DeleteLink")
+	defer span.End()
+
+	future, err := ac.resources.DeleteByID(ctx, linkID, apiVersion)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.resources.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.resources)
+	return err
+}
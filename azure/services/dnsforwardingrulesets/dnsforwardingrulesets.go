@@ -0,0 +1,165 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsforwardingrulesets
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Scope defines the scope interface for a DNS forwarding ruleset service.
+type Scope interface {
+	logr.Logger
+	azure.ClusterDescriber
+	DNSForwardingRulesetLinkSpec() *azure.DNSForwardingRulesetLinkSpec
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope Scope
+	client
+}
+
+// New creates a new DNS forwarding ruleset service.
+func New(scope Scope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// subResource is a reference to another Azure resource.
+type subResource struct {
+	ID *string `json:"id,omitempty"`
+}
+
+// virtualNetworkLinkProperties are the properties of a DNS forwarding ruleset virtual network link.
+type virtualNetworkLinkProperties struct {
+	VirtualNetwork *subResource      `json:"virtualNetwork,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+}
+
+// Reconcile links the cluster virtual network to the DNS forwarding ruleset.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, span := tele.Tracer().Start(ctx, "dnsforwardingrulesets.Service.Reconcile")
+	defer span.End()
+
+	linkSpec := s.Scope.DNSForwardingRulesetLinkSpec()
+	if linkSpec == nil {
+		return nil
+	}
+	linkID := azure.DNSForwardingRulesetLinkID(linkSpec.RulesetID, linkSpec.Name)
+
+	existing, err := s.client.GetLink(ctx, linkID)
+	switch {
+	case err != nil && !azure.ResourceNotFound(err):
+		return errors.Wrapf(err, "failed to get DNS forwarding ruleset virtual network link %s", linkID)
+	case err == nil:
+		props, err := linkProperties(existing)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse DNS forwarding ruleset virtual network link %s", linkID)
+		}
+		if props.VirtualNetwork == nil || !strings.EqualFold(to.String(props.VirtualNetwork.ID), linkSpec.VNetID) {
+			return errors.Errorf("DNS forwarding ruleset virtual network link %s already exists and does not link virtual network %s", linkID, linkSpec.VNetID)
+		}
+		s.Scope.V(2).Info("DNS forwarding ruleset virtual network link already exists", "link", linkSpec.Name, "ruleset", linkSpec.RulesetID)
+		return nil
+	}
+
+	s.Scope.V(2).Info("creating DNS forwarding ruleset virtual network link", "link", linkSpec.Name, "ruleset", linkSpec.RulesetID)
+	link := resources.GenericResource{
+		Properties: virtualNetworkLinkProperties{
+			VirtualNetwork: &subResource{ID: to.StringPtr(linkSpec.VNetID)},
+			Metadata: infrav1.Build(infrav1.BuildParams{
+				ClusterName: s.Scope.ClusterName(),
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+				Name:        to.StringPtr(linkSpec.Name),
+			}),
+		},
+	}
+	err = s.client.CreateOrUpdateLink(ctx, linkID, link)
+	azure.RecordCreate(ctx, "DNS forwarding ruleset virtual network link", linkSpec.Name, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create DNS forwarding ruleset virtual network link %s", linkID)
+	}
+	s.Scope.V(2).Info("successfully created DNS forwarding ruleset virtual network link", "link", linkSpec.Name, "ruleset", linkSpec.RulesetID)
+
+	return nil
+}
+
+// Delete removes the virtual network link from the DNS forwarding ruleset if it is owned by the cluster.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, span := tele.Tracer().Start(ctx, "dnsforwardingrulesets.Service.Delete")
+	defer span.End()
+
+	linkSpec := s.Scope.DNSForwardingRulesetLinkSpec()
+	if linkSpec == nil {
+		return nil
+	}
+	linkID := azure.DNSForwardingRulesetLinkID(linkSpec.RulesetID, linkSpec.Name)
+
+	existing, err := s.client.GetLink(ctx, linkID)
+	if err != nil && azure.ResourceNotFound(err) {
+		// already deleted
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get DNS forwarding ruleset virtual network link %s", linkID)
+	}
+	props, err := linkProperties(existing)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse DNS forwarding ruleset virtual network link %s", linkID)
+	}
+	if !infrav1.Tags(props.Metadata).HasOwned(s.Scope.ClusterName()) {
+		s.Scope.V(2).Info("skipping DNS forwarding ruleset virtual network link deletion for unmanaged link", "link", linkSpec.Name)
+		return nil
+	}
+
+	s.Scope.V(2).Info("deleting DNS forwarding ruleset virtual network link", "link", linkSpec.Name, "ruleset", linkSpec.RulesetID)
+	err = s.client.DeleteLink(ctx, linkID)
+	azure.RecordDelete(ctx, "DNS forwarding ruleset virtual network link", linkSpec.Name, err)
+	if err != nil && !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to delete DNS forwarding ruleset virtual network link %s", linkID)
+	}
+	s.Scope.V(2).Info("successfully deleted DNS forwarding ruleset virtual network link", "link", linkSpec.Name, "ruleset", linkSpec.RulesetID)
+
+	return nil
+}
+
+// linkProperties decodes the untyped properties of a generic resource into virtual network link properties.
+func linkProperties(link resources.GenericResource) (virtualNetworkLinkProperties, error) {
+	var props virtualNetworkLinkProperties
+	if link.Properties == nil {
+		return props, nil
+	}
+	raw, err := json.Marshal(link.Properties)
+	if err != nil {
+		return props, err
+	}
+	err = json.Unmarshal(raw, &props)
+	return props, err
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsforwardingrulesets
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/v2/klogr"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsforwardingrulesets/mock_dnsforwardingrulesets"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+const (
+	fakeRulesetID = "/subscriptions/123/resourceGroups/dns-rg/providers/Microsoft.Network/dnsForwardingRulesets/my-ruleset"
+	fakeLinkID    = fakeRulesetID + "/virtualNetworkLinks/my-link"
+	fakeVNetID    = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"
+)
+
+var fakeLinkSpec = azure.DNSForwardingRulesetLinkSpec{
+	Name:      "my-link",
+	RulesetID: fakeRulesetID,
+	VNetID:    fakeVNetID,
+}
+
+func TestReconcileDNSForwardingRulesetLink(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_dnsforwardingrulesets.MockScopeMockRecorder, m *mock_dnsforwardingrulesets.MockclientMockRecorder)
+	}{
+		{
+			name:          "no dns forwarding ruleset link",
+			expectedError: "",
+			expect: func(s *mock_dnsforwardingrulesets.MockScopeMockRecorder, m *mock_dnsforwardingrulesets.MockclientMockRecorder) {
+				s.DNSForwardingRulesetLinkSpec().Return(nil)
+			},
+		},
+		{
+			name:          "create link successfully",
+			expectedError: "",
+			expect: func(s *mock_dnsforwardingrulesets.MockScopeMockRecorder, m *mock_dnsforwardingrulesets.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.DNSForwardingRulesetLinkSpec().Return(&fakeLinkSpec)
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetLink(gomockinternal.AContext(), fakeLinkID).
+					Return(resources.GenericResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateLink(gomockinternal.AContext(), fakeLinkID, resources.GenericResource{
+					Properties: virtualNetworkLinkProperties{
+						VirtualNetwork: &subResource{ID: to.StringPtr(fakeVNetID)},
+						Metadata: map[string]string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+							"Name": "my-link",
+						},
+					},
+				})
+			},
+		},
+		{
+			name:          "link to the cluster vnet already exists",
+			expectedError: "",
+			expect: func(s *mock_dnsforwardingrulesets.MockScopeMockRecorder, m *mock_dnsforwardingrulesets.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.DNSForwardingRulesetLinkSpec().Return(&fakeLinkSpec)
+				m.GetLink(gomockinternal.AContext(), fakeLinkID).Return(resources.GenericResource{
+					Properties: map[string]interface{}{
+						"virtualNetwork": map[string]interface{}{"id": fakeVNetID},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "link with the same name points to another vnet",
+			expectedError: "DNS forwarding ruleset virtual network link " + fakeLinkID + " already exists and does not link virtual network " + fakeVNetID,
+			expect: func(s *mock_dnsforwardingrulesets.MockScopeMockRecorder, m *mock_dnsforwardingrulesets.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.DNSForwardingRulesetLinkSpec().Return(&fakeLinkSpec)
+				m.GetLink(gomockinternal.AContext(), fakeLinkID).Return(resources.GenericResource{
+					Properties: map[string]interface{}{
+						"virtualNetwork": map[string]interface{}{"id": "/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Network/virtualNetworks/other-vnet"},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "fail to create link",
+			expectedError: "failed to create DNS forwarding ruleset virtual network link " + fakeLinkID + ": #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_dnsforwardingrulesets.MockScopeMockRecorder, m *mock_dnsforwardingrulesets.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.DNSForwardingRulesetLinkSpec().Return(&fakeLinkSpec)
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetLink(gomockinternal.AContext(), fakeLinkID).
+					Return(resources.GenericResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateLink(gomockinternal.AContext(), fakeLinkID, gomock.AssignableToTypeOf(resources.GenericResource{})).
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_dnsforwardingrulesets.NewMockScope(mockCtrl)
+			clientMock := mock_dnsforwardingrulesets.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteDNSForwardingRulesetLink(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_dnsforwardingrulesets.MockScopeMockRecorder, m *mock_dnsforwardingrulesets.MockclientMockRecorder)
+	}{
+		{
+			name:          "no dns forwarding ruleset link",
+			expectedError: "",
+			expect: func(s *mock_dnsforwardingrulesets.MockScopeMockRecorder, m *mock_dnsforwardingrulesets.MockclientMockRecorder) {
+				s.DNSForwardingRulesetLinkSpec().Return(nil)
+			},
+		},
+		{
+			name:          "delete owned link successfully",
+			expectedError: "",
+			expect: func(s *mock_dnsforwardingrulesets.MockScopeMockRecorder, m *mock_dnsforwardingrulesets.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.DNSForwardingRulesetLinkSpec().Return(&fakeLinkSpec)
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetLink(gomockinternal.AContext(), fakeLinkID).Return(resources.GenericResource{
+					Properties: map[string]interface{}{
+						"virtualNetwork": map[string]interface{}{"id": fakeVNetID},
+						"metadata": map[string]interface{}{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+						},
+					},
+				}, nil)
+				m.DeleteLink(gomockinternal.AContext(), fakeLinkID)
+			},
+		},
+		{
+			name:          "skip deleting unmanaged link",
+			expectedError: "",
+			expect: func(s *mock_dnsforwardingrulesets.MockScopeMockRecorder, m *mock_dnsforwardingrulesets.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.DNSForwardingRulesetLinkSpec().Return(&fakeLinkSpec)
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetLink(gomockinternal.AContext(), fakeLinkID).Return(resources.GenericResource{
+					Properties: map[string]interface{}{
+						"virtualNetwork": map[string]interface{}{"id": fakeVNetID},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "link already deleted",
+			expectedError: "",
+			expect: func(s *mock_dnsforwardingrulesets.MockScopeMockRecorder, m *mock_dnsforwardingrulesets.MockclientMockRecorder) {
+				s.DNSForwardingRulesetLinkSpec().Return(&fakeLinkSpec)
+				m.GetLink(gomockinternal.AContext(), fakeLinkID).
+					Return(resources.GenericResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "error while trying to delete the link",
+			expectedError: "failed to delete DNS forwarding ruleset virtual network link " + fakeLinkID + ": #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_dnsforwardingrulesets.MockScopeMockRecorder, m *mock_dnsforwardingrulesets.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.DNSForwardingRulesetLinkSpec().Return(&fakeLinkSpec)
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetLink(gomockinternal.AContext(), fakeLinkID).Return(resources.GenericResource{
+					Properties: map[string]interface{}{
+						"metadata": map[string]interface{}{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+						},
+					},
+				}, nil)
+				m.DeleteLink(gomockinternal.AContext(), fakeLinkID).
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_dnsforwardingrulesets.NewMockScope(mockCtrl)
+			clientMock := mock_dnsforwardingrulesets.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package groups
 import (
 	"context"
 
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2016-09-01/locks"
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
 	"github.com/Azure/go-autorest/autorest"
 
@@ -31,11 +32,15 @@ type client interface {
 	Get(context.Context, string) (resources.Group, error)
 	CreateOrUpdate(context.Context, string, resources.Group) (resources.Group, error)
 	Delete(context.Context, string) error
+	GetLock(context.Context, string, string) (locks.ManagementLockObject, error)
+	CreateOrUpdateLock(context.Context, string, string, locks.ManagementLockObject) error
+	DeleteLock(context.Context, string, string) error
 }
 
 // azureClient contains the Azure go-sdk Client.
 type azureClient struct {
 	groups resources.GroupsClient
+	locks  locks.ManagementLocksClient
 }
 
 var _ client = (*azureClient)(nil)
@@ -43,8 +48,10 @@ var _ client = (*azureClient)(nil)
 // newClient creates a new VM client from subscription ID.
 func newClient(auth azure.Authorizer) *azureClient {
 	c := newGroupsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	l := newLocksClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
 	return &azureClient{
 		groups: c,
+		locks:  l,
 	}
 }
 
@@ -55,6 +62,13 @@ func newGroupsClient(subscriptionID string, baseURI string, authorizer autorest.
 	return groupsClient
 }
 
+// newLocksClient creates a new management locks client from subscription ID.
+func newLocksClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) locks.ManagementLocksClient {
+	locksClient := locks.NewManagementLocksClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&locksClient.Client, authorizer)
+	return locksClient
+}
+
 // Get gets a resource group.
 func (ac *azureClient) Get(ctx context.Context, name string) (resources.Group, error) {
 	ctx, span := tele.Tracer().Start(ctx, "groups.AzureClient.Get")
@@ -87,3 +101,29 @@ func (ac *azureClient) Delete(ctx context.Context, name string) error {
 	_, err = future.Result(ac.groups)
 	return err
 }
+
+// GetLock gets a management lock of a resource.
+func (ac *azureClient) GetLock(ctx context.Context, scope, lockName string) (locks.ManagementLockObject, error) {
+	ctx, span := tele.Tracer().Start(ctx, "groups.AzureClient.GetLock")
+	defer span.End()
+
+	return ac.locks.GetByScope(ctx, scope, lockName)
+}
+
+// CreateOrUpdateLock creates or updates a management lock of a resource.
+func (ac *azureClient) CreateOrUpdateLock(ctx context.Context, scope, lockName string, lock locks.ManagementLockObject) error {
+	ctx, span := tele.Tracer().Start(ctx, "groups.AzureClient.CreateOrUpdateLock")
+	defer span.End()
+
+	_, err := ac.locks.CreateOrUpdateByScope(ctx, scope, lockName, lock)
+	return err
+}
+
+// DeleteLock deletes a management lock of a resource.
+func (ac *azureClient) DeleteLock(ctx context.Context, scope, lockName string) error {
+	ctx, span := tele.Tracer().Start(ctx, "groups.AzureClient.DeleteLock")
+	defer span.End()
+
+	_, err := ac.locks.DeleteByScope(ctx, scope, lockName)
+	return err
+}
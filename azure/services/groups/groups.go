@@ -18,7 +18,9 @@ package groups
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2016-09-01/locks"
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/go-logr/logr"
@@ -30,6 +32,9 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+// lockName is the name of the management lock protecting the resource groups managed by the provider.
+const lockName = "cluster-api-provider-azure-cannot-delete"
+
 // Service provides operations on Azure resources.
 type Service struct {
 	Scope GroupScope
@@ -40,6 +45,9 @@ type Service struct {
 type GroupScope interface {
 	logr.Logger
 	azure.ClusterDescriber
+	SetResourceGroupStatus(status infrav1.ResourceGroupStatus)
+	ResourceGroupLockEnabled() bool
+	VNetSpec() azure.VNetSpec
 }
 
 // New creates a new service.
@@ -55,9 +63,14 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, span := tele.Tracer().Start(ctx, "groups.Service.Reconcile")
 	defer span.End()
 
-	if _, err := s.client.Get(ctx, s.Scope.ResourceGroup()); err == nil {
-		// resource group already exists, skip creation
-		return nil
+	if existing, err := s.client.Get(ctx, s.Scope.ResourceGroup()); err == nil {
+		// resource group already exists, skip creation and record whether it is managed by this cluster
+		managed := converters.MapToTags(existing.Tags).HasOwned(s.Scope.ClusterName())
+		if !managed {
+			s.Scope.V(4).Info("using unmanaged resource group", "resource group", s.Scope.ResourceGroup())
+		}
+		s.Scope.SetResourceGroupStatus(infrav1.ResourceGroupStatus{Name: s.Scope.ResourceGroup(), Managed: managed})
+		return s.reconcileLock(ctx, managed)
 	} else if !azure.ResourceNotFound(err) {
 		return errors.Wrapf(err, "failed to get resource group %s", s.Scope.ResourceGroup())
 	}
@@ -75,11 +88,64 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	}
 
 	_, err := s.client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), group)
+	azure.RecordCreate(ctx, "resource group", s.Scope.ResourceGroup(), err)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create resource group %s", s.Scope.ResourceGroup())
 	}
+	s.Scope.SetResourceGroupStatus(infrav1.ResourceGroupStatus{Name: s.Scope.ResourceGroup(), Managed: true})
 
 	s.Scope.V(2).Info("successfully created resource group", "resource group", s.Scope.ResourceGroup())
+	return s.reconcileLock(ctx, true)
+}
+
+// lockScope returns the ID of the resource the management lock protecting the resource group is placed on, or an empty
+// string if there is none. A lock on the resource group itself would be inherited by all of its resources, preventing
+// the deletion of the VMs, NICs and disks of machines being scaled down, remediated or upgraded. The cluster virtual
+// network is only deleted along with the cluster, and a resource group holding a locked resource can't be deleted.
+func (s *Service) lockScope() string {
+	vnet := s.Scope.VNetSpec()
+	if vnet.ResourceGroup != s.Scope.ResourceGroup() {
+		return ""
+	}
+	return azure.VNetID(s.Scope.SubscriptionID(), vnet.ResourceGroup, vnet.Name)
+}
+
+// reconcileLock places a CanNotDelete management lock on the virtual network of a managed resource group when resource
+// group locks are enabled, to protect the resource group from being deleted by accident outside of Cluster API.
+func (s *Service) reconcileLock(ctx context.Context, managed bool) error {
+	if !managed || !s.Scope.ResourceGroupLockEnabled() {
+		return nil
+	}
+	scope := s.lockScope()
+	if scope == "" {
+		s.Scope.V(4).Info("not locking resource group as the virtual network is in another resource group", "resource group", s.Scope.ResourceGroup())
+		return nil
+	}
+
+	if _, err := s.client.GetLock(ctx, scope, lockName); err == nil {
+		// resource group already locked
+		return nil
+	} else if !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to get management lock of resource group %s", s.Scope.ResourceGroup())
+	}
+
+	s.Scope.V(2).Info("locking resource group", "resource group", s.Scope.ResourceGroup())
+	lock := locks.ManagementLockObject{
+		ManagementLockProperties: &locks.ManagementLockProperties{
+			Level: locks.CanNotDelete,
+			Notes: to.StringPtr(fmt.Sprintf("Protects the resource group of cluster %s from accidental deletion. Removed by Cluster API when the cluster is deleted.", s.Scope.ClusterName())),
+		},
+	}
+	if err := s.client.CreateOrUpdateLock(ctx, scope, lockName, lock); err != nil {
+		if azure.ResourceNotFound(err) {
+			// the virtual network is created after the resource group, so the lock is placed by a later reconciliation
+			s.Scope.V(4).Info("not locking resource group yet as the virtual network doesn't exist", "resource group", s.Scope.ResourceGroup())
+			return nil
+		}
+		return errors.Wrapf(err, "failed to lock resource group %s", s.Scope.ResourceGroup())
+	}
+
+	s.Scope.V(2).Info("successfully locked resource group", "resource group", s.Scope.ResourceGroup())
 	return nil
 }
 
@@ -102,8 +168,18 @@ func (s *Service) Delete(ctx context.Context) error {
 		return azure.ErrNotOwned
 	}
 
+	// The lock has to be lifted before the resource group can be deleted. It is removed even if resource group
+	// locks are disabled, as they may have been enabled when the resource group was reconciled.
+	if scope := s.lockScope(); scope != "" {
+		s.Scope.V(2).Info("removing management lock of resource group", "resource group", s.Scope.ResourceGroup())
+		if err := s.client.DeleteLock(ctx, scope, lockName); err != nil && !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to remove management lock of resource group %s", s.Scope.ResourceGroup())
+		}
+	}
+
 	s.Scope.V(2).Info("deleting resource group", "resource group", s.Scope.ResourceGroup())
 	err = s.client.Delete(ctx, s.Scope.ResourceGroup())
+	azure.RecordDelete(ctx, "resource group", s.Scope.ResourceGroup(), err)
 	if err != nil && azure.ResourceNotFound(err) {
 		// already deleted
 		return nil
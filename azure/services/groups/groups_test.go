@@ -21,8 +21,10 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2016-09-01/locks"
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"k8s.io/klog/v2/klogr"
@@ -45,8 +47,127 @@ func TestReconcileGroups(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockclientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ResourceGroup().Return("my-rg")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("fake-cluster")
 				m.Get(gomockinternal.AContext(), "my-rg").Return(resources.Group{}, nil)
+				s.SetResourceGroupStatus(infrav1.ResourceGroupStatus{Name: "my-rg", Managed: false})
+			},
+		},
+		{
+			name:          "managed resource group already exist",
+			expectedError: "",
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				m.Get(gomockinternal.AContext(), "my-rg").Return(resources.Group{
+					Tags: converters.TagsToMap(infrav1.Tags{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": "owned",
+					}),
+				}, nil)
+				s.SetResourceGroupStatus(infrav1.ResourceGroupStatus{Name: "my-rg", Managed: true})
+				s.ResourceGroupLockEnabled().Return(false)
+			},
+		},
+		{
+			name:          "lock managed resource group",
+			expectedError: "",
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				m.Get(gomockinternal.AContext(), "my-rg").Return(resources.Group{
+					Tags: converters.TagsToMap(infrav1.Tags{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": "owned",
+					}),
+				}, nil)
+				s.SetResourceGroupStatus(infrav1.ResourceGroupStatus{Name: "my-rg", Managed: true})
+				s.ResourceGroupLockEnabled().Return(true)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.VNetSpec().AnyTimes().Return(azure.VNetSpec{ResourceGroup: "my-rg", Name: "my-vnet"})
+				m.GetLock(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet", lockName).Return(locks.ManagementLockObject{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateLock(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet", lockName, gomockinternal.DiffEq(locks.ManagementLockObject{
+					ManagementLockProperties: &locks.ManagementLockProperties{
+						Level: locks.CanNotDelete,
+						Notes: to.StringPtr("Protects the resource group of cluster fake-cluster from accidental deletion. Removed by Cluster API when the cluster is deleted."),
+					},
+				})).Return(nil)
+			},
+		},
+		{
+			name:          "managed resource group already locked",
+			expectedError: "",
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				m.Get(gomockinternal.AContext(), "my-rg").Return(resources.Group{
+					Tags: converters.TagsToMap(infrav1.Tags{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": "owned",
+					}),
+				}, nil)
+				s.SetResourceGroupStatus(infrav1.ResourceGroupStatus{Name: "my-rg", Managed: true})
+				s.ResourceGroupLockEnabled().Return(true)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.VNetSpec().AnyTimes().Return(azure.VNetSpec{ResourceGroup: "my-rg", Name: "my-vnet"})
+				m.GetLock(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet", lockName).Return(locks.ManagementLockObject{}, nil)
+			},
+		},
+		{
+			name:          "return error when locking a resource group",
+			expectedError: "failed to lock resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				m.Get(gomockinternal.AContext(), "my-rg").Return(resources.Group{
+					Tags: converters.TagsToMap(infrav1.Tags{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": "owned",
+					}),
+				}, nil)
+				s.SetResourceGroupStatus(infrav1.ResourceGroupStatus{Name: "my-rg", Managed: true})
+				s.ResourceGroupLockEnabled().Return(true)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.VNetSpec().AnyTimes().Return(azure.VNetSpec{ResourceGroup: "my-rg", Name: "my-vnet"})
+				m.GetLock(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet", lockName).Return(locks.ManagementLockObject{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateLock(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet", lockName, gomock.AssignableToTypeOf(locks.ManagementLockObject{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:          "lock managed resource group once the virtual network exists",
+			expectedError: "",
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				m.Get(gomockinternal.AContext(), "my-rg").Return(resources.Group{
+					Tags: converters.TagsToMap(infrav1.Tags{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": "owned",
+					}),
+				}, nil)
+				s.SetResourceGroupStatus(infrav1.ResourceGroupStatus{Name: "my-rg", Managed: true})
+				s.ResourceGroupLockEnabled().Return(true)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.VNetSpec().AnyTimes().Return(azure.VNetSpec{ResourceGroup: "my-rg", Name: "my-vnet"})
+				m.GetLock(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet", lockName).Return(locks.ManagementLockObject{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateLock(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet", lockName, gomock.AssignableToTypeOf(locks.ManagementLockObject{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "don't lock managed resource group with a virtual network in another resource group",
+			expectedError: "",
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				m.Get(gomockinternal.AContext(), "my-rg").Return(resources.Group{
+					Tags: converters.TagsToMap(infrav1.Tags{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": "owned",
+					}),
+				}, nil)
+				s.SetResourceGroupStatus(infrav1.ResourceGroupStatus{Name: "my-rg", Managed: true})
+				s.ResourceGroupLockEnabled().Return(true)
+				s.VNetSpec().AnyTimes().Return(azure.VNetSpec{ResourceGroup: "my-network-rg", Name: "my-vnet"})
 			},
 		},
 		{
@@ -72,6 +193,8 @@ func TestReconcileGroups(t *testing.T) {
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
 				m.Get(gomockinternal.AContext(), "my-rg").Return(resources.Group{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", gomock.AssignableToTypeOf(resources.Group{})).Return(resources.Group{}, nil)
+				s.SetResourceGroupStatus(infrav1.ResourceGroupStatus{Name: "my-rg", Managed: true})
+				s.ResourceGroupLockEnabled().Return(false)
 			},
 		},
 		{
@@ -150,6 +273,8 @@ func TestDeleteGroups(t *testing.T) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.ClusterName().AnyTimes().Return("fake-cluster")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.VNetSpec().AnyTimes().Return(azure.VNetSpec{ResourceGroup: "my-rg", Name: "my-vnet"})
 				gomock.InOrder(
 					m.Get(gomockinternal.AContext(), "my-rg").Return(resources.Group{
 						Tags: converters.TagsToMap(infrav1.Tags{
@@ -158,6 +283,7 @@ func TestDeleteGroups(t *testing.T) {
 							"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
 						}),
 					}, nil),
+					m.DeleteLock(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet", lockName).Return(nil),
 					m.Delete(gomockinternal.AContext(), "my-rg").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found")),
 				)
 			},
@@ -179,6 +305,8 @@ func TestDeleteGroups(t *testing.T) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.ClusterName().AnyTimes().Return("fake-cluster")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.VNetSpec().AnyTimes().Return(azure.VNetSpec{ResourceGroup: "my-rg", Name: "my-vnet"})
 				gomock.InOrder(
 					m.Get(gomockinternal.AContext(), "my-rg").Return(resources.Group{
 						Tags: converters.TagsToMap(infrav1.Tags{
@@ -187,10 +315,52 @@ func TestDeleteGroups(t *testing.T) {
 							"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
 						}),
 					}, nil),
+					m.DeleteLock(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet", lockName).Return(nil),
 					m.Delete(gomockinternal.AContext(), "my-rg").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")),
 				)
 			},
 		},
+		{
+			name:          "resource group lock removal fails",
+			expectedError: "failed to remove management lock of resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.VNetSpec().AnyTimes().Return(azure.VNetSpec{ResourceGroup: "my-rg", Name: "my-vnet"})
+				gomock.InOrder(
+					m.Get(gomockinternal.AContext(), "my-rg").Return(resources.Group{
+						Tags: converters.TagsToMap(infrav1.Tags{
+							"Name": "my-rg",
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": "owned",
+							"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+						}),
+					}, nil),
+					m.DeleteLock(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet", lockName).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")),
+				)
+			},
+		},
+		{
+			name:          "resource group with a virtual network in another resource group deletion successfully",
+			expectedError: "",
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				s.VNetSpec().AnyTimes().Return(azure.VNetSpec{ResourceGroup: "my-network-rg", Name: "my-vnet"})
+				gomock.InOrder(
+					m.Get(gomockinternal.AContext(), "my-rg").Return(resources.Group{
+						Tags: converters.TagsToMap(infrav1.Tags{
+							"Name": "my-rg",
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": "owned",
+							"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+						}),
+					}, nil),
+					m.Delete(gomockinternal.AContext(), "my-rg").Return(nil),
+				)
+			},
+		},
 		{
 			name:          "resource group deletion successfully",
 			expectedError: "",
@@ -198,6 +368,8 @@ func TestDeleteGroups(t *testing.T) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.ClusterName().AnyTimes().Return("fake-cluster")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.VNetSpec().AnyTimes().Return(azure.VNetSpec{ResourceGroup: "my-rg", Name: "my-vnet"})
 				gomock.InOrder(
 					m.Get(gomockinternal.AContext(), "my-rg").Return(resources.Group{
 						Tags: converters.TagsToMap(infrav1.Tags{
@@ -206,6 +378,7 @@ func TestDeleteGroups(t *testing.T) {
 							"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
 						}),
 					}, nil),
+					m.DeleteLock(gomockinternal.AContext(), "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet", lockName).Return(nil),
 					m.Delete(gomockinternal.AContext(), "my-rg").Return(nil),
 				)
 			},
@@ -24,6 +24,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	locks "github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2016-09-01/locks"
 	resources "github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -66,6 +67,20 @@ func (mr *MockclientMockRecorder) CreateOrUpdate(arg0, arg1, arg2 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdate", reflect.TypeOf((*Mockclient)(nil).CreateOrUpdate), arg0, arg1, arg2)
 }
 
+// CreateOrUpdateLock mocks base method.
+func (m *Mockclient) CreateOrUpdateLock(arg0 context.Context, arg1, arg2 string, arg3 locks.ManagementLockObject) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdateLock", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrUpdateLock indicates an expected call of CreateOrUpdateLock.
+func (mr *MockclientMockRecorder) CreateOrUpdateLock(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateLock", reflect.TypeOf((*Mockclient)(nil).CreateOrUpdateLock), arg0, arg1, arg2, arg3)
+}
+
 // Delete mocks base method.
 func (m *Mockclient) Delete(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -80,6 +95,20 @@ func (mr *MockclientMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*Mockclient)(nil).Delete), arg0, arg1)
 }
 
+// DeleteLock mocks base method.
+func (m *Mockclient) DeleteLock(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLock", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLock indicates an expected call of DeleteLock.
+func (mr *MockclientMockRecorder) DeleteLock(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLock", reflect.TypeOf((*Mockclient)(nil).DeleteLock), arg0, arg1, arg2)
+}
+
 // Get mocks base method.
 func (m *Mockclient) Get(arg0 context.Context, arg1 string) (resources.Group, error) {
 	m.ctrl.T.Helper()
@@ -94,3 +123,18 @@ func (mr *MockclientMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*Mockclient)(nil).Get), arg0, arg1)
 }
+
+// GetLock mocks base method.
+func (m *Mockclient) GetLock(arg0 context.Context, arg1, arg2 string) (locks.ManagementLockObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLock", arg0, arg1, arg2)
+	ret0, _ := ret[0].(locks.ManagementLockObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLock indicates an expected call of GetLock.
+func (mr *MockclientMockRecorder) GetLock(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLock", reflect.TypeOf((*Mockclient)(nil).GetLock), arg0, arg1, arg2)
+}
@@ -27,6 +27,7 @@ import (
 	logr "github.com/go-logr/logr"
 	gomock "github.com/golang/mock/gomock"
 	v1alpha4 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
 )
 
 // MockGroupScope is a mock of GroupScope interface.
@@ -254,6 +255,20 @@ func (mr *MockGroupScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockGroupScope)(nil).Location))
 }
 
+// NetworkResourceGroup mocks base method.
+func (m *MockGroupScope) NetworkResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NetworkResourceGroup indicates an expected call of NetworkResourceGroup.
+func (mr *MockGroupScopeMockRecorder) NetworkResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkResourceGroup", reflect.TypeOf((*MockGroupScope)(nil).NetworkResourceGroup))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockGroupScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -268,6 +283,32 @@ func (mr *MockGroupScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockGroupScope)(nil).ResourceGroup))
 }
 
+// ResourceGroupLockEnabled mocks base method.
+func (m *MockGroupScope) ResourceGroupLockEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceGroupLockEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ResourceGroupLockEnabled indicates an expected call of ResourceGroupLockEnabled.
+func (mr *MockGroupScopeMockRecorder) ResourceGroupLockEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroupLockEnabled", reflect.TypeOf((*MockGroupScope)(nil).ResourceGroupLockEnabled))
+}
+
+// SetResourceGroupStatus mocks base method.
+func (m *MockGroupScope) SetResourceGroupStatus(status v1alpha4.ResourceGroupStatus) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetResourceGroupStatus", status)
+}
+
+// SetResourceGroupStatus indicates an expected call of SetResourceGroupStatus.
+func (mr *MockGroupScopeMockRecorder) SetResourceGroupStatus(status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResourceGroupStatus", reflect.TypeOf((*MockGroupScope)(nil).SetResourceGroupStatus), status)
+}
+
 // SubscriptionID mocks base method.
 func (m *MockGroupScope) SubscriptionID() string {
 	m.ctrl.T.Helper()
@@ -310,6 +351,20 @@ func (mr *MockGroupScopeMockRecorder) V(level interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V", reflect.TypeOf((*MockGroupScope)(nil).V), level)
 }
 
+// VNetSpec mocks base method.
+func (m *MockGroupScope) VNetSpec() azure.VNetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VNetSpec")
+	ret0, _ := ret[0].(azure.VNetSpec)
+	return ret0
+}
+
+// VNetSpec indicates an expected call of VNetSpec.
+func (mr *MockGroupScopeMockRecorder) VNetSpec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VNetSpec", reflect.TypeOf((*MockGroupScope)(nil).VNetSpec))
+}
+
 // WithName mocks base method.
 func (m *MockGroupScope) WithName(name string) logr.Logger {
 	m.ctrl.T.Helper()
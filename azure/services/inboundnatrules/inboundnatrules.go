@@ -60,7 +60,7 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	for _, inboundNatSpec := range s.Scope.InboundNatSpecs() {
 		s.Scope.V(2).Info("creating inbound NAT rule", "NAT rule", inboundNatSpec.Name)
 
-		lb, err := s.loadBalancersClient.Get(ctx, s.Scope.ResourceGroup(), inboundNatSpec.LoadBalancerName)
+		lb, err := s.loadBalancersClient.Get(ctx, s.Scope.NetworkResourceGroup(), inboundNatSpec.LoadBalancerName)
 		if err != nil {
 			return errors.Wrapf(err, "failed to get Load Balancer %s", inboundNatSpec.LoadBalancerName)
 		}
@@ -75,27 +75,38 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			continue
 		}
 
-		sshFrontendPort, err := s.getAvailablePort(ports)
+		backendPort := inboundNatSpec.BackendPort
+		if backendPort == 0 {
+			backendPort = azure.SSHPort
+		}
+
+		var frontendPort int32
+		if backendPort == azure.RDPPort {
+			frontendPort, err = s.getAvailableRDPPort(ports)
+		} else {
+			frontendPort, err = s.getAvailablePort(ports)
+		}
 		if err != nil {
-			return errors.Wrapf(err, "failed to find available SSH Frontend port for NAT Rule %s in load balancer %s", inboundNatSpec.Name, to.String(lb.Name))
+			return errors.Wrapf(err, "failed to find available Frontend port for NAT Rule %s in load balancer %s", inboundNatSpec.Name, to.String(lb.Name))
 		}
 
 		rule := network.InboundNatRule{
 			Name: to.StringPtr(inboundNatSpec.Name),
 			InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
-				BackendPort:          to.Int32Ptr(22),
+				BackendPort:          to.Int32Ptr(backendPort),
 				EnableFloatingIP:     to.BoolPtr(false),
 				IdleTimeoutInMinutes: to.Int32Ptr(4),
 				FrontendIPConfiguration: &network.SubResource{
 					ID: (*lb.FrontendIPConfigurations)[0].ID,
 				},
 				Protocol:     network.TransportProtocolTCP,
-				FrontendPort: &sshFrontendPort,
+				FrontendPort: &frontendPort,
 			},
 		}
-		s.Scope.V(3).Info("Creating rule %s using port %d", "NAT rule", inboundNatSpec.Name, "port", sshFrontendPort)
+		s.Scope.V(3).Info("Creating rule %s using port %d", "NAT rule", inboundNatSpec.Name, "port", frontendPort)
 
-		err = s.client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), to.String(lb.Name), inboundNatSpec.Name, rule)
+		err = s.client.CreateOrUpdate(ctx, s.Scope.NetworkResourceGroup(), to.String(lb.Name), inboundNatSpec.Name, rule)
+		azure.RecordCreate(ctx, "inbound NAT rule", inboundNatSpec.Name, err)
 		if err != nil {
 			return errors.Wrapf(err, "failed to create inbound NAT rule %s", inboundNatSpec.Name)
 		}
@@ -112,7 +123,8 @@ func (s *Service) Delete(ctx context.Context) error {
 
 	for _, inboundNatSpec := range s.Scope.InboundNatSpecs() {
 		s.Scope.V(2).Info("deleting inbound NAT rule", "NAT rule", inboundNatSpec.Name)
-		err := s.client.Delete(ctx, s.Scope.ResourceGroup(), inboundNatSpec.LoadBalancerName, inboundNatSpec.Name)
+		err := s.client.Delete(ctx, s.Scope.NetworkResourceGroup(), inboundNatSpec.LoadBalancerName, inboundNatSpec.Name)
+		azure.RecordDelete(ctx, "inbound NAT rule", inboundNatSpec.Name, err)
 		if err != nil && !azure.ResourceNotFound(err) {
 			return errors.Wrapf(err, "failed to delete inbound NAT rule %s", inboundNatSpec.Name)
 		}
@@ -149,3 +161,18 @@ func (s *Service) getAvailablePort(ports map[int32]struct{}) (int32, error) {
 	s.Scope.V(2).Info("Found available port", "port", i)
 	return i, nil
 }
+
+func (s *Service) getAvailableRDPPort(ports map[int32]struct{}) (int32, error) {
+	i := azure.RDPPort
+	if _, ok := ports[azure.RDPPort]; ok {
+		for i = 33890; i < 33990; i++ {
+			if _, ok := ports[i]; !ok {
+				s.Scope.V(2).Info("Found available port", "port", i)
+				return i, nil
+			}
+		}
+		return i, errors.Errorf("No available RDP Frontend ports")
+	}
+	s.Scope.V(2).Info("Found available port", "port", i)
+	return i, nil
+}
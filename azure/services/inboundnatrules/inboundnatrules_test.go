@@ -55,7 +55,7 @@ func TestReconcileInboundNATRule(t *testing.T) {
 						LoadBalancerName: "my-lb",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				gomock.InOrder(
@@ -97,7 +97,7 @@ func TestReconcileInboundNATRule(t *testing.T) {
 						LoadBalancerName: "my-public-lb",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				gomock.InOrder(
@@ -117,7 +117,7 @@ func TestReconcileInboundNATRule(t *testing.T) {
 						LoadBalancerName: "my-public-lb",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				gomock.InOrder(
@@ -179,7 +179,7 @@ func TestReconcileInboundNATRule(t *testing.T) {
 						LoadBalancerName: "my-other-public-lb",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				gomock.InOrder(
@@ -274,7 +274,7 @@ func TestDeleteNetworkInterface(t *testing.T) {
 					},
 				})
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-public-lb", "azure-md-0")
 			},
 		},
@@ -289,7 +289,7 @@ func TestDeleteNetworkInterface(t *testing.T) {
 						LoadBalancerName: "my-public-lb",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-public-lb", "azure-md-1").
 					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
@@ -307,7 +307,7 @@ func TestDeleteNetworkInterface(t *testing.T) {
 					},
 				})
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-public-lb", "azure-md-2").
 					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
@@ -541,3 +541,66 @@ func getFullPortsMap() map[int32]struct{} {
 	}
 	return res
 }
+
+func TestGetAvailableRDPPort(t *testing.T) {
+	testcases := []struct {
+		name               string
+		portsInput         map[int32]struct{}
+		expectedError      string
+		expectedPortResult int32
+	}{
+		{
+			name:               "Empty ports",
+			portsInput:         map[int32]struct{}{},
+			expectedPortResult: 3389,
+		},
+		{
+			name: "3389 taken",
+			portsInput: map[int32]struct{}{
+				22:    {},
+				3389:  {},
+				33890: {},
+			},
+			expectedPortResult: 33891,
+		},
+		{
+			name:          "No ports available",
+			portsInput:    getFullRDPPortsMap(),
+			expectedError: "No available RDP Frontend ports",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_inboundnatrules.NewMockInboundNatScope(mockCtrl)
+			scopeMock.EXPECT().V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+
+			s := &Service{
+				Scope: scopeMock,
+			}
+
+			res, err := s.getAvailableRDPPort(tc.portsInput)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(res).To(Equal(tc.expectedPortResult))
+			}
+		})
+	}
+}
+
+func getFullRDPPortsMap() map[int32]struct{} {
+	res := map[int32]struct{}{
+		3389: {},
+	}
+	for i := 33890; i < 33990; i++ {
+		res[int32(i)] = struct{}{}
+	}
+	return res
+}
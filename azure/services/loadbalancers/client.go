@@ -22,15 +22,18 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
 	"github.com/Azure/go-autorest/autorest"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 // Client wraps go-sdk.
 type Client interface {
 	Get(context.Context, string, string) (network.LoadBalancer, error)
-	CreateOrUpdate(context.Context, string, string, network.LoadBalancer) error
-	Delete(context.Context, string, string) error
+	CreateOrUpdateAsync(context.Context, string, string, network.LoadBalancer) (*infrav1.Future, error)
+	DeleteAsync(context.Context, string, string) (*infrav1.Future, error)
+	IsDone(context.Context, *infrav1.Future) (bool, error)
 }
 
 // AzureClient contains the Azure go-sdk Client.
@@ -61,9 +64,10 @@ func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, lbName string
 	return ac.loadbalancers.Get(ctx, resourceGroupName, lbName, "")
 }
 
-// CreateOrUpdate creates or updates a load balancer.
-func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, lbName string, lb network.LoadBalancer) error {
-	ctx, span := tele.Tracer().Start(ctx, "loadbalancers.AzureClient.CreateOrUpdate")
+// CreateOrUpdateAsync starts creating or updating a load balancer, and returns the future of the long-running
+// operation without waiting for it to complete.
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupName string, lbName string, lb network.LoadBalancer) (*infrav1.Future, error) {
+	ctx, span := tele.Tracer().Start(ctx, "loadbalancers.AzureClient.CreateOrUpdateAsync")
 	defer span.End()
 
 	var etag string
@@ -74,7 +78,7 @@ func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName str
 	req, err := ac.loadbalancers.CreateOrUpdatePreparer(ctx, resourceGroupName, lbName, lb)
 	if err != nil {
 		err = autorest.NewErrorWithError(err, "network.LoadBalancersClient", "CreateOrUpdate", nil, "Failure preparing request")
-		return err
+		return nil, err
 	}
 
 	if etag != "" {
@@ -84,31 +88,28 @@ func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName str
 	future, err := ac.loadbalancers.CreateOrUpdateSender(req)
 	if err != nil {
 		err = autorest.NewErrorWithError(err, "network.LoadBalancersClient", "CreateOrUpdate", future.Response(), "Failure sending request")
-		return err
+		return nil, err
 	}
-
-	err = future.WaitForCompletionRef(ctx, ac.loadbalancers.Client)
-	if err != nil {
-		return err
-	}
-
-	_, err = future.Result(ac.loadbalancers)
-	return err
+	return async.NewFuture(&future, async.PutFuture, serviceName, resourceGroupName, lbName)
 }
 
-// Delete deletes the specified load balancer.
-func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, lbName string) error {
-	ctx, span := tele.Tracer().Start(ctx, "loadbalancers.AzureClient.Delete")
+// DeleteAsync starts deleting the specified load balancer, and returns the future of the long-running operation
+// without waiting for it to complete.
+func (ac *AzureClient) DeleteAsync(ctx context.Context, resourceGroupName, lbName string) (*infrav1.Future, error) {
+	ctx, span := tele.Tracer().Start(ctx, "loadbalancers.AzureClient.DeleteAsync")
 	defer span.End()
 
 	future, err := ac.loadbalancers.Delete(ctx, resourceGroupName, lbName)
 	if err != nil {
-		return err
-	}
-	err = future.WaitForCompletionRef(ctx, ac.loadbalancers.Client)
-	if err != nil {
-		return err
+		return nil, err
 	}
-	_, err = future.Result(ac.loadbalancers)
-	return err
+	return async.NewFuture(&future, async.DeleteFuture, serviceName, resourceGroupName, lbName)
+}
+
+// IsDone returns true if the long-running operation of the future is done.
+func (ac *AzureClient) IsDone(ctx context.Context, future *infrav1.Future) (bool, error) {
+	ctx, span := tele.Tracer().Start(ctx, "loadbalancers.AzureClient.IsDone")
+	defer span.End()
+
+	return async.IsDone(ctx, ac.loadbalancers, future)
 }
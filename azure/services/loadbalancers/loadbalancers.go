@@ -27,11 +27,14 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 const (
+	serviceName = "loadbalancers"
+
 	tcpProbe    = "TCPProbe"
 	lbRuleHTTPS = "LBRuleHTTPS"
 	outboundNAT = "OutboundNATAllProtocols"
@@ -42,6 +45,7 @@ type LBScope interface {
 	logr.Logger
 	azure.ClusterDescriber
 	azure.NetworkDescriber
+	azure.AsyncStatusUpdater
 	LBSpecs() []azure.LBSpec
 }
 
@@ -66,7 +70,20 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, span := tele.Tracer().Start(ctx, "loadbalancers.Service.Reconcile")
 	defer span.End()
 
+	// Load balancers which are still being created or updated don't prevent the others from being reconciled, the
+	// first such operation is returned once all load balancers have been gone through so that the cluster is requeued.
+	var inProgress error
 	for _, lbSpec := range s.Scope.LBSpecs() {
+		if future := s.Scope.GetLongRunningOperationState(lbSpec.Name, serviceName); future != nil {
+			if err := async.CheckOperation(ctx, s.Scope, s.Client, future); err != nil {
+				if azure.IsOperationNotDoneError(err) {
+					inProgress = keepFirst(inProgress, errors.Wrapf(err, "failed to create load balancer \"%s\"", lbSpec.Name))
+					continue
+				}
+				return errors.Wrapf(err, "failed to create load balancer \"%s\"", lbSpec.Name)
+			}
+		}
+
 		var (
 			etag                *string
 			frontendIDs         []network.SubResource
@@ -77,10 +94,14 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			probes              = make([]network.Probe, 0)
 		)
 
-		existingLB, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), lbSpec.Name)
+		existingLB, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), lbSpec.Name)
 		switch {
 		case err != nil && !azure.ResourceNotFound(err):
-			return errors.Wrapf(err, "failed to get LB %s in %s", lbSpec.Name, s.Scope.ResourceGroup())
+			return errors.Wrapf(err, "failed to get LB %s in %s", lbSpec.Name, s.Scope.NetworkResourceGroup())
+		case err == nil && converters.MapToTags(existingLB.Tags).HasShared(s.Scope.ClusterName()):
+			// shared LBs are only read by the cluster
+			s.Scope.V(4).Info("LB is shared, skipping update", "load balancer", lbSpec.Name)
+			continue
 		case err == nil:
 			// LB already exists
 			s.Scope.V(2).Info("found existing load balancer, checking if updates are needed", "load balancer", lbSpec.Name)
@@ -138,6 +159,14 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		default:
 			s.Scope.V(2).Info("creating load balancer", "load balancer", lbSpec.Name)
 			frontendIPConfigs, frontendIDs = s.getFrontendIPConfigs(lbSpec)
+			// The zones of a frontend can't be changed once the LB exists, so existing LBs keep the zones they were
+			// created with. Public frontends inherit the zones of their public IP, only private frontends take zones.
+			if lbSpec.Type == infrav1.Internal && len(lbSpec.FrontendIPZones) > 0 {
+				for i := range frontendIPConfigs {
+					zones := lbSpec.FrontendIPZones
+					frontendIPConfigs[i].Zones = &zones
+				}
+			}
 			loadBalancingRules = s.getLoadBalancingRules(lbSpec, frontendIDs)
 			backendAddressPools = s.getBackendAddressPools(lbSpec)
 			outboundRules = s.getOutboundRules(lbSpec, frontendIDs)
@@ -163,15 +192,22 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			},
 		}
 
-		err = s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), lbSpec.Name, lb)
-
+		future, err := s.Client.CreateOrUpdateAsync(ctx, s.Scope.NetworkResourceGroup(), lbSpec.Name, lb)
 		if err != nil {
+			azure.RecordCreate(ctx, async.ResourceKind(serviceName), lbSpec.Name, err)
+			return errors.Wrapf(err, "failed to create load balancer \"%s\"", lbSpec.Name)
+		}
+		if err := async.TrackOperation(ctx, s.Scope, s.Client, future); err != nil {
+			if azure.IsOperationNotDoneError(err) {
+				inProgress = keepFirst(inProgress, errors.Wrapf(err, "failed to create load balancer \"%s\"", lbSpec.Name))
+				continue
+			}
 			return errors.Wrapf(err, "failed to create load balancer \"%s\"", lbSpec.Name)
 		}
 
 		s.Scope.V(2).Info("successfully created load balancer", "load balancer", lbSpec.Name)
 	}
-	return nil
+	return inProgress
 }
 
 // Delete deletes the public load balancer with the provided name.
@@ -179,20 +215,63 @@ func (s *Service) Delete(ctx context.Context) error {
 	ctx, span := tele.Tracer().Start(ctx, "loadbalancers.Service.Delete")
 	defer span.End()
 
+	var inProgress error
 	for _, lbSpec := range s.Scope.LBSpecs() {
+		if future := s.Scope.GetLongRunningOperationState(lbSpec.Name, serviceName); future != nil {
+			if err := async.CheckOperation(ctx, s.Scope, s.Client, future); err != nil {
+				if azure.IsOperationNotDoneError(err) {
+					inProgress = keepFirst(inProgress, errors.Wrapf(err, "failed to delete load balancer %s in resource group %s", lbSpec.Name, s.Scope.NetworkResourceGroup()))
+					continue
+				}
+				return errors.Wrapf(err, "failed to delete load balancer %s in resource group %s", lbSpec.Name, s.Scope.NetworkResourceGroup())
+			}
+			if future.Type == async.DeleteFuture {
+				s.Scope.V(2).Info("deleted public load balancer", "load balancer", lbSpec.Name)
+				continue
+			}
+		}
+
+		existingLB, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), lbSpec.Name)
+		switch {
+		case err != nil && azure.ResourceNotFound(err):
+			// already deleted
+			continue
+		case err != nil:
+			return errors.Wrapf(err, "failed to get LB %s in %s", lbSpec.Name, s.Scope.NetworkResourceGroup())
+		case converters.MapToTags(existingLB.Tags).HasShared(s.Scope.ClusterName()):
+			s.Scope.V(2).Info("skipping deletion of shared load balancer", "load balancer", lbSpec.Name)
+			continue
+		}
+
 		s.Scope.V(2).Info("deleting load balancer", "load balancer", lbSpec.Name)
-		err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), lbSpec.Name)
+		future, err := s.Client.DeleteAsync(ctx, s.Scope.NetworkResourceGroup(), lbSpec.Name)
 		if err != nil && azure.ResourceNotFound(err) {
 			// already deleted
 			continue
 		}
 		if err != nil {
-			return errors.Wrapf(err, "failed to delete load balancer %s in resource group %s", lbSpec.Name, s.Scope.ResourceGroup())
+			azure.RecordDelete(ctx, async.ResourceKind(serviceName), lbSpec.Name, err)
+			return errors.Wrapf(err, "failed to delete load balancer %s in resource group %s", lbSpec.Name, s.Scope.NetworkResourceGroup())
+		}
+		if err := async.TrackOperation(ctx, s.Scope, s.Client, future); err != nil {
+			if azure.IsOperationNotDoneError(err) {
+				inProgress = keepFirst(inProgress, errors.Wrapf(err, "failed to delete load balancer %s in resource group %s", lbSpec.Name, s.Scope.NetworkResourceGroup()))
+				continue
+			}
+			return errors.Wrapf(err, "failed to delete load balancer %s in resource group %s", lbSpec.Name, s.Scope.NetworkResourceGroup())
 		}
 
 		s.Scope.V(2).Info("deleted public load balancer", "load balancer", lbSpec.Name)
 	}
-	return nil
+	return inProgress
+}
+
+// keepFirst returns the first of two errors which is not nil.
+func keepFirst(first, second error) error {
+	if first != nil {
+		return first
+	}
+	return second
 }
 
 func (s *Service) getFrontendIPConfigs(lbSpec azure.LBSpec) ([]network.FrontendIPConfiguration, []network.SubResource) {
@@ -211,16 +290,17 @@ func (s *Service) getFrontendIPConfigs(lbSpec azure.LBSpec) ([]network.FrontendI
 		} else {
 			properties = network.FrontendIPConfigurationPropertiesFormat{
 				PublicIPAddress: &network.PublicIPAddress{
-					ID: to.StringPtr(azure.PublicIPID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), ipConfig.PublicIP.Name)),
+					ID: to.StringPtr(azure.PublicIPID(s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), ipConfig.PublicIP.Name)),
 				},
 			}
 		}
-		frontendIPConfigurations = append(frontendIPConfigurations, network.FrontendIPConfiguration{
+		frontendIPConfig := network.FrontendIPConfiguration{
 			FrontendIPConfigurationPropertiesFormat: &properties,
 			Name:                                    to.StringPtr(ipConfig.Name),
-		})
+		}
+		frontendIPConfigurations = append(frontendIPConfigurations, frontendIPConfig)
 		frontendIDs = append(frontendIDs, network.SubResource{
-			ID: to.StringPtr(azure.FrontendIPConfigID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), lbSpec.Name, ipConfig.Name)),
+			ID: to.StringPtr(azure.FrontendIPConfigID(s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), lbSpec.Name, ipConfig.Name)),
 		})
 	}
 	return frontendIPConfigurations, frontendIDs
@@ -238,7 +318,7 @@ func (s *Service) getOutboundRules(lbSpec azure.LBSpec, frontendIDs []network.Su
 				IdleTimeoutInMinutes:     lbSpec.IdleTimeoutInMinutes,
 				FrontendIPConfigurations: &frontendIDs,
 				BackendAddressPool: &network.SubResource{
-					ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), lbSpec.Name, lbSpec.BackendPoolName)),
+					ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), lbSpec.Name, lbSpec.BackendPoolName)),
 				},
 			},
 		},
@@ -266,10 +346,10 @@ func (s *Service) getLoadBalancingRules(lbSpec azure.LBSpec, frontendIDs []netwo
 					LoadDistribution:        network.LoadDistributionDefault,
 					FrontendIPConfiguration: &frontendIPConfig,
 					BackendAddressPool: &network.SubResource{
-						ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), lbSpec.Name, lbSpec.BackendPoolName)),
+						ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), lbSpec.Name, lbSpec.BackendPoolName)),
 					},
 					Probe: &network.SubResource{
-						ID: to.StringPtr(azure.ProbeID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), lbSpec.Name, tcpProbe)),
+						ID: to.StringPtr(azure.ProbeID(s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), lbSpec.Name, tcpProbe)),
 					},
 				},
 			},
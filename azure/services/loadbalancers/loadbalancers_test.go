@@ -30,11 +30,17 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers/mock_loadbalancers"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks/mock_virtualnetworks"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 )
 
+var (
+	fakeFuture       = &infrav1.Future{Type: async.PutFuture, ServiceName: serviceName, ResourceGroup: "my-rg", Name: "my-lb"}
+	fakeDeleteFuture = &infrav1.Future{Type: async.DeleteFuture, ServiceName: serviceName, ResourceGroup: "my-rg", Name: "my-lb"}
+)
+
 func TestReconcileLoadBalancer(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -53,7 +59,7 @@ func TestReconcileLoadBalancer(t *testing.T) {
 				})
 				setupDefaultLBExpectations(s)
 				m.Get(gomockinternal.AContext(), "my-rg", "my-publiclb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publiclb", gomock.AssignableToTypeOf(network.LoadBalancer{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-publiclb", gomock.AssignableToTypeOf(network.LoadBalancer{})).Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
 		{
@@ -84,7 +90,8 @@ func TestReconcileLoadBalancer(t *testing.T) {
 				setupDefaultLBExpectations(s)
 				gomock.InOrder(
 					m.Get(gomockinternal.AContext(), "my-rg", "my-publiclb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")),
-					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publiclb", gomockinternal.DiffEq(newDefaultPublicAPIServerLB())).Return(nil))
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-publiclb", gomockinternal.DiffEq(newDefaultPublicAPIServerLB())).Return(fakeFuture, nil),
+					m.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, nil))
 			},
 		},
 		{
@@ -116,7 +123,80 @@ func TestReconcileLoadBalancer(t *testing.T) {
 				})
 				gomock.InOrder(
 					m.Get(gomockinternal.AContext(), "my-rg", "my-private-lb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")),
-					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-private-lb", gomockinternal.DiffEq(newDefaultInternalAPIServerLB())).Return(nil))
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-private-lb", gomockinternal.DiffEq(newDefaultInternalAPIServerLB())).Return(fakeFuture, nil),
+					m.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, nil))
+			},
+		},
+		{
+			name:          "create zone-redundant internal apiserver LB",
+			expectedError: "",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder) {
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name:                 "my-private-lb",
+						Role:                 infrav1.APIServerRole,
+						Type:                 infrav1.Internal,
+						SKU:                  infrav1.SKUStandard,
+						SubnetName:           "my-cp-subnet",
+						BackendPoolName:      "my-private-lb-backendPool",
+						IdleTimeoutInMinutes: to.Int32Ptr(4),
+						FrontendIPConfigs: []infrav1.FrontendIP{
+							{
+								Name:             "my-private-lb-frontEnd",
+								PrivateIPAddress: "10.0.0.10",
+							},
+						},
+						FrontendIPZones: []string{"1", "2", "3"},
+						APIServerPort:   6443,
+					},
+				})
+				setupDefaultLBExpectations(s)
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{
+					ResourceGroup: "my-rg",
+					Name:          "my-vnet",
+				})
+				lb := newDefaultInternalAPIServerLB()
+				(*lb.FrontendIPConfigurations)[0].Zones = &[]string{"1", "2", "3"}
+				gomock.InOrder(
+					m.Get(gomockinternal.AContext(), "my-rg", "my-private-lb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")),
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-private-lb", gomockinternal.DiffEq(lb)).Return(fakeFuture, nil),
+					m.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, nil))
+			},
+		},
+		{
+			name:          "existing internal LB without zones keeps its frontend zones",
+			expectedError: "",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder) {
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name:                 "my-private-lb",
+						Role:                 infrav1.APIServerRole,
+						Type:                 infrav1.Internal,
+						SKU:                  infrav1.SKUStandard,
+						SubnetName:           "my-cp-subnet",
+						BackendPoolName:      "my-private-lb-backendPool",
+						IdleTimeoutInMinutes: to.Int32Ptr(4),
+						FrontendIPConfigs: []infrav1.FrontendIP{
+							{
+								Name:             "my-private-lb-frontEnd",
+								PrivateIPAddress: "10.0.0.10",
+							},
+						},
+						FrontendIPZones: []string{"1", "2", "3"},
+						APIServerPort:   6443,
+					},
+				})
+				setupDefaultLBExpectations(s)
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{
+					ResourceGroup: "my-rg",
+					Name:          "my-vnet",
+				})
+				existingLB := newDefaultInternalAPIServerLB()
+				existingLB.BackendAddressPools = &[]network.BackendAddressPool{}
+				gomock.InOrder(
+					m.Get(gomockinternal.AContext(), "my-rg", "my-private-lb").Return(existingLB, nil),
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-private-lb", gomockinternal.DiffEq(newDefaultInternalAPIServerLB())).Return(fakeFuture, nil),
+					m.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, nil))
 			},
 		},
 		{
@@ -144,7 +224,8 @@ func TestReconcileLoadBalancer(t *testing.T) {
 				setupDefaultLBExpectations(s)
 				gomock.InOrder(
 					m.Get(gomockinternal.AContext(), "my-rg", "my-cluster").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")),
-					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", gomockinternal.DiffEq(newDefaultNodeOutboundLB())).Return(nil))
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-cluster", gomockinternal.DiffEq(newDefaultNodeOutboundLB())).Return(fakeFuture, nil),
+					m.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, nil))
 			},
 		},
 		{
@@ -180,11 +261,49 @@ func TestReconcileLoadBalancer(t *testing.T) {
 					Name:          "my-vnet",
 				})
 				m.Get(gomockinternal.AContext(), "my-rg", "my-lb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-lb", gomock.AssignableToTypeOf(network.LoadBalancer{}))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-lb", gomock.AssignableToTypeOf(network.LoadBalancer{})).Return(fakeFuture, nil)
+				m.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, nil)
 				m.Get(gomockinternal.AContext(), "my-rg", "my-lb-2").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-lb-2", gomock.AssignableToTypeOf(network.LoadBalancer{}))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-lb-2", gomock.AssignableToTypeOf(network.LoadBalancer{})).Return(fakeFuture, nil)
+				m.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, nil)
 				m.Get(gomockinternal.AContext(), "my-rg", "my-lb-3").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-lb-3", gomock.AssignableToTypeOf(network.LoadBalancer{}))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-lb-3", gomock.AssignableToTypeOf(network.LoadBalancer{})).Return(fakeFuture, nil)
+				m.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, nil)
+			},
+		},
+		{
+			name:          "LB creation in progress does not prevent other LBs from being reconciled",
+			expectedError: "failed to create load balancer \"my-lb\": transient reconcile error occurred: operation type PUT on Azure resource my-rg/my-lb is not done. Object will be requeued after 15s",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name:                 "my-lb",
+						APIServerPort:        6443,
+						Role:                 infrav1.APIServerRole,
+						Type:                 infrav1.Public,
+						IdleTimeoutInMinutes: to.Int32Ptr(4),
+					},
+					{
+						Name:                 "my-lb-2",
+						Role:                 infrav1.NodeOutboundRole,
+						Type:                 infrav1.Public,
+						IdleTimeoutInMinutes: to.Int32Ptr(30),
+					},
+				})
+				s.GetLongRunningOperationState("my-lb", serviceName).Return(fakeFuture)
+				m.IsDone(gomockinternal.AContext(), fakeFuture).Return(false, nil)
+				s.GetLongRunningOperationState("my-lb-2", serviceName).Return(nil)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-lb-2").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-lb-2", gomock.AssignableToTypeOf(network.LoadBalancer{})).Return(fakeFuture, nil)
+				s.SetLongRunningOperationState(fakeFuture)
+				m.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, nil)
+				s.DeleteLongRunningOperationState("my-lb", serviceName)
 			},
 		},
 		{
@@ -218,6 +337,29 @@ func TestReconcileLoadBalancer(t *testing.T) {
 				m.Get(gomockinternal.AContext(), "my-rg", "my-publiclb").Return(existingLB, nil)
 			},
 		},
+		{
+			name:          "shared LB is not updated",
+			expectedError: "",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder) {
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name:            "my-publiclb",
+						Role:            infrav1.APIServerRole,
+						Type:            infrav1.Public,
+						SKU:             infrav1.SKUStandard,
+						BackendPoolName: "my-publiclb-backendPool",
+						APIServerPort:   6443,
+					},
+				})
+				setupDefaultLBExpectations(s)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publiclb").Return(network.LoadBalancer{
+					Name: to.StringPtr("my-publiclb"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("shared"),
+					},
+				}, nil)
+			},
+		},
 		{
 			name:          "LB already exists and is missing properties",
 			expectedError: "",
@@ -248,7 +390,8 @@ func TestReconcileLoadBalancer(t *testing.T) {
 				existingLB.ID = to.StringPtr("azure/my-publiclb")
 				existingLB.BackendAddressPools = &[]network.BackendAddressPool{}
 				m.Get(gomockinternal.AContext(), "my-rg", "my-publiclb").Return(existingLB, nil)
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publiclb", gomockinternal.DiffEq(newDefaultPublicAPIServerLB())).Return(nil)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-publiclb", gomockinternal.DiffEq(newDefaultPublicAPIServerLB())).Return(fakeFuture, nil)
+				m.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, nil)
 			},
 		},
 	}
@@ -295,6 +438,7 @@ func TestDeleteLoadBalancer(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState(gomock.Any(), serviceName).AnyTimes().Return(nil)
 				s.LBSpecs().Return([]azure.LBSpec{
 					{
 						Name: "my-internallb",
@@ -303,9 +447,15 @@ func TestDeleteLoadBalancer(t *testing.T) {
 						Name: "my-publiclb",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				m.Delete(gomockinternal.AContext(), "my-rg", "my-internallb")
-				m.Delete(gomockinternal.AContext(), "my-rg", "my-publiclb")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-internallb").Return(network.LoadBalancer{Name: to.StringPtr("my-internallb")}, nil)
+				m.DeleteAsync(gomockinternal.AContext(), "my-rg", "my-internallb").Return(fakeDeleteFuture, nil)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publiclb").Return(network.LoadBalancer{Name: to.StringPtr("my-publiclb")}, nil)
+				m.DeleteAsync(gomockinternal.AContext(), "my-rg", "my-publiclb").Return(fakeDeleteFuture, nil)
+				s.SetLongRunningOperationState(fakeDeleteFuture).Times(2)
+				m.IsDone(gomockinternal.AContext(), fakeDeleteFuture).Times(2).Return(true, nil)
+				s.DeleteLongRunningOperationState(fakeDeleteFuture.Name, serviceName).Times(2)
 			},
 		},
 		{
@@ -313,14 +463,71 @@ func TestDeleteLoadBalancer(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState(gomock.Any(), serviceName).AnyTimes().Return(nil)
 				s.LBSpecs().Return([]azure.LBSpec{
 					{
 						Name: "my-publiclb",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				m.Delete(gomockinternal.AContext(), "my-rg", "my-publiclb").
-					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publiclb").
+					Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "shared load balancer is not deleted",
+			expectedError: "",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState(gomock.Any(), serviceName).AnyTimes().Return(nil)
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name: "my-publiclb",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publiclb").Return(network.LoadBalancer{
+					Name: to.StringPtr("my-publiclb"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("shared"),
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "load balancer deletion in progress",
+			expectedError: "failed to delete load balancer my-lb in resource group my-rg: transient reconcile error occurred: operation type DELETE on Azure resource my-rg/my-lb is not done. Object will be requeued after 15s",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name: "my-lb",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.GetLongRunningOperationState("my-lb", serviceName).Return(nil)
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-lb").Return(network.LoadBalancer{Name: to.StringPtr("my-lb")}, nil)
+				m.DeleteAsync(gomockinternal.AContext(), "my-rg", "my-lb").Return(fakeDeleteFuture, nil)
+				s.SetLongRunningOperationState(fakeDeleteFuture)
+				m.IsDone(gomockinternal.AContext(), fakeDeleteFuture).Return(false, nil)
+			},
+		},
+		{
+			name:          "load balancer deletion started by a previous reconcile is done",
+			expectedError: "",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name: "my-lb",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.GetLongRunningOperationState("my-lb", serviceName).Return(fakeDeleteFuture)
+				m.IsDone(gomockinternal.AContext(), fakeDeleteFuture).Return(true, nil)
+				s.DeleteLongRunningOperationState("my-lb", serviceName)
 			},
 		},
 		{
@@ -328,14 +535,17 @@ func TestDeleteLoadBalancer(t *testing.T) {
 			expectedError: "failed to delete load balancer my-publiclb in resource group my-rg: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState(gomock.Any(), serviceName).AnyTimes().Return(nil)
 				s.LBSpecs().Return([]azure.LBSpec{
 					{
 						Name: "my-publiclb",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				m.Delete(gomockinternal.AContext(), "my-rg", "my-publiclb").
-					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publiclb").Return(network.LoadBalancer{Name: to.StringPtr("my-publiclb")}, nil)
+				m.DeleteAsync(gomockinternal.AContext(), "my-rg", "my-publiclb").
+					Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
 	}
@@ -556,8 +766,11 @@ func newDefaultInternalAPIServerLB() network.LoadBalancer {
 func setupDefaultLBExpectations(s *mock_loadbalancers.MockLBScopeMockRecorder) {
 	s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 	s.SubscriptionID().AnyTimes().Return("123")
-	s.ResourceGroup().AnyTimes().Return("my-rg")
+	s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 	s.Location().AnyTimes().Return("testlocation")
 	s.ClusterName().AnyTimes().Return("my-cluster")
 	s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+	s.GetLongRunningOperationState(gomock.Any(), serviceName).AnyTimes().Return(nil)
+	s.SetLongRunningOperationState(gomock.Any()).AnyTimes()
+	s.DeleteLongRunningOperationState(gomock.Any(), serviceName).AnyTimes()
 }
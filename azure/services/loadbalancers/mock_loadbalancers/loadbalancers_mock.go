@@ -235,6 +235,18 @@ func (mr *MockLBScopeMockRecorder) ControlPlaneSubnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnet", reflect.TypeOf((*MockLBScope)(nil).ControlPlaneSubnet))
 }
 
+// DeleteLongRunningOperationState mocks base method.
+func (m *MockLBScope) DeleteLongRunningOperationState(name, service string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteLongRunningOperationState", name, service)
+}
+
+// DeleteLongRunningOperationState indicates an expected call of DeleteLongRunningOperationState.
+func (mr *MockLBScopeMockRecorder) DeleteLongRunningOperationState(name, service interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockLBScope)(nil).DeleteLongRunningOperationState), name, service)
+}
+
 // Enabled mocks base method.
 func (m *MockLBScope) Enabled() bool {
 	m.ctrl.T.Helper()
@@ -266,6 +278,20 @@ func (mr *MockLBScopeMockRecorder) Error(err, msg interface{}, keysAndValues ...
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockLBScope)(nil).Error), varargs...)
 }
 
+// GetLongRunningOperationState mocks base method.
+func (m *MockLBScope) GetLongRunningOperationState(name, service string) *v1alpha4.Future {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLongRunningOperationState", name, service)
+	ret0, _ := ret[0].(*v1alpha4.Future)
+	return ret0
+}
+
+// GetLongRunningOperationState indicates an expected call of GetLongRunningOperationState.
+func (mr *MockLBScopeMockRecorder) GetLongRunningOperationState(name, service interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongRunningOperationState", reflect.TypeOf((*MockLBScope)(nil).GetLongRunningOperationState), name, service)
+}
+
 // GetPrivateDNSZoneName mocks base method.
 func (m *MockLBScope) GetPrivateDNSZoneName() string {
 	m.ctrl.T.Helper()
@@ -381,6 +407,34 @@ func (mr *MockLBScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockLBScope)(nil).Location))
 }
 
+// NetworkResourceGroup mocks base method.
+func (m *MockLBScope) NetworkResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NetworkResourceGroup indicates an expected call of NetworkResourceGroup.
+func (mr *MockLBScopeMockRecorder) NetworkResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkResourceGroup", reflect.TypeOf((*MockLBScope)(nil).NetworkResourceGroup))
+}
+
+// NodeOutboundLB mocks base method.
+func (m *MockLBScope) NodeOutboundLB() *v1alpha4.LoadBalancerSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeOutboundLB")
+	ret0, _ := ret[0].(*v1alpha4.LoadBalancerSpec)
+	return ret0
+}
+
+// NodeOutboundLB indicates an expected call of NodeOutboundLB.
+func (mr *MockLBScopeMockRecorder) NodeOutboundLB() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeOutboundLB", reflect.TypeOf((*MockLBScope)(nil).NodeOutboundLB))
+}
+
 // NodeSubnets mocks base method.
 func (m *MockLBScope) NodeSubnets() []v1alpha4.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -437,6 +491,18 @@ func (mr *MockLBScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockLBScope)(nil).ResourceGroup))
 }
 
+// SetLongRunningOperationState mocks base method.
+func (m *MockLBScope) SetLongRunningOperationState(arg0 *v1alpha4.Future) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLongRunningOperationState", arg0)
+}
+
+// SetLongRunningOperationState indicates an expected call of SetLongRunningOperationState.
+func (mr *MockLBScopeMockRecorder) SetLongRunningOperationState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockLBScope)(nil).SetLongRunningOperationState), arg0)
+}
+
 // SetSubnet mocks base method.
 func (m *MockLBScope) SetSubnet(arg0 v1alpha4.SubnetSpec) {
 	m.ctrl.T.Helper()
@@ -381,6 +381,34 @@ func (mr *MockNatGatewayScopeMockRecorder) NatGatewaySpecs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NatGatewaySpecs", reflect.TypeOf((*MockNatGatewayScope)(nil).NatGatewaySpecs))
 }
 
+// NetworkResourceGroup mocks base method.
+func (m *MockNatGatewayScope) NetworkResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NetworkResourceGroup indicates an expected call of NetworkResourceGroup.
+func (mr *MockNatGatewayScopeMockRecorder) NetworkResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkResourceGroup", reflect.TypeOf((*MockNatGatewayScope)(nil).NetworkResourceGroup))
+}
+
+// NodeOutboundLB mocks base method.
+func (m *MockNatGatewayScope) NodeOutboundLB() *v1alpha4.LoadBalancerSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeOutboundLB")
+	ret0, _ := ret[0].(*v1alpha4.LoadBalancerSpec)
+	return ret0
+}
+
+// NodeOutboundLB indicates an expected call of NodeOutboundLB.
+func (mr *MockNatGatewayScopeMockRecorder) NodeOutboundLB() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeOutboundLB", reflect.TypeOf((*MockNatGatewayScope)(nil).NodeOutboundLB))
+}
+
 // NodeSubnets mocks base method.
 func (m *MockNatGatewayScope) NodeSubnets() []v1alpha4.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -26,6 +26,7 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -62,72 +63,75 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	}
 
 	for _, natGatewaySpec := range s.Scope.NatGatewaySpecs() {
-		existingNatGateway, err := s.getExisting(ctx, natGatewaySpec)
-
+		existingNatGateway, err := s.Get(ctx, s.Scope.NetworkResourceGroup(), natGatewaySpec.Name)
 		switch {
 		case err != nil && !azure.ResourceNotFound(err):
-			return errors.Wrapf(err, "failed to get nat gateway %s in %s", natGatewaySpec.Name, s.Scope.ResourceGroup())
-		case err == nil:
-			// nat gateway already exists
-			s.Scope.V(4).Info("nat gateway already exists", "nat gateway", natGatewaySpec.Name)
-			natGatewaySpec.Subnet.NatGateway.ID = existingNatGateway.ID
-
-			if existingNatGateway.NatGatewayIP.Name == natGatewaySpec.NatGatewayIP.Name {
-				// Skip update for Nat Gateway as it exists with expected values
-				s.Scope.V(4).Info("Nat Gateway exists with expected values, skipping update", "nat gateway", natGatewaySpec.Name)
-				natGatewaySpec.Subnet.NatGateway = *existingNatGateway
-				s.Scope.SetSubnet(natGatewaySpec.Subnet)
-				continue
-			}
-		default:
+			return errors.Wrapf(err, "failed to get nat gateway %s in %s", natGatewaySpec.Name, s.Scope.NetworkResourceGroup())
+		case err != nil:
 			// nat gateway doesn't exist but its name was specified in the subnet, let's create it
 			s.Scope.V(2).Info("nat gateway doesn't exist yet, creating it", "nat gateway", natGatewaySpec.Name)
 		}
+		exists := err == nil
+
+		natGateway := infrav1.NatGateway{
+			ID:   azure.NatGatewayID(s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), natGatewaySpec.Name),
+			Name: natGatewaySpec.Name,
+			NatGatewayIP: infrav1.PublicIPSpec{
+				Name: natGatewaySpec.NatGatewayIP.Name,
+			},
+		}
 
 		natGatewayToCreate := network.NatGateway{
 			Location: to.StringPtr(s.Scope.Location()),
 			Sku:      &network.NatGatewaySku{Name: network.Standard},
+			Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+				ClusterName: s.Scope.ClusterName(),
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+				Name:        to.StringPtr(natGatewaySpec.Name),
+				Additional:  s.Scope.AdditionalTags(),
+			})),
 			NatGatewayPropertiesFormat: &network.NatGatewayPropertiesFormat{
 				PublicIPAddresses: &[]network.SubResource{
 					{
-						ID: to.StringPtr(azure.PublicIPID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), natGatewaySpec.NatGatewayIP.Name)),
+						ID: to.StringPtr(azure.PublicIPID(s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), natGatewaySpec.NatGatewayIP.Name)),
 					},
 				},
 			},
 		}
-		err = s.client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), natGatewaySpec.Name, natGatewayToCreate)
+		if exists && converters.MapToTags(existingNatGateway.Tags).HasShared(s.Scope.ClusterName()) {
+			// shared nat gateways are only read by the cluster
+			s.Scope.V(4).Info("nat gateway is shared, skipping update", "nat gateway", natGatewaySpec.Name)
+			natGatewaySpec.Subnet.NatGateway = natGateway
+			s.Scope.SetSubnet(natGatewaySpec.Subnet)
+			continue
+		}
+		if exists {
+			upToDate, err := azure.IsUpToDate(natGatewayToCreate, existingNatGateway)
+			if err != nil {
+				return errors.Wrapf(err, "failed to compare nat gateway %s with its spec", natGatewaySpec.Name)
+			}
+			if upToDate {
+				// Skip update for Nat Gateway as it exists with expected values
+				s.Scope.V(4).Info("Nat Gateway exists with expected values, skipping update", "nat gateway", natGatewaySpec.Name)
+				natGatewaySpec.Subnet.NatGateway = natGateway
+				s.Scope.SetSubnet(natGatewaySpec.Subnet)
+				continue
+			}
+			s.Scope.V(2).Info("nat gateway is out of date, updating it", "nat gateway", natGatewaySpec.Name)
+		}
+
+		err = s.client.CreateOrUpdate(ctx, s.Scope.NetworkResourceGroup(), natGatewaySpec.Name, natGatewayToCreate)
+		azure.RecordCreate(ctx, "NAT gateway", natGatewaySpec.Name, err)
 		if err != nil {
-			return errors.Wrapf(err, "failed to create nat gateway %s in resource group %s", natGatewaySpec.Name, s.Scope.ResourceGroup())
+			return errors.Wrapf(err, "failed to create nat gateway %s in resource group %s", natGatewaySpec.Name, s.Scope.NetworkResourceGroup())
 		}
 		s.Scope.V(2).Info("successfully created nat gateway", "nat gateway", natGatewaySpec.Name)
-		natGateway := infrav1.NatGateway{
-			ID:   azure.NatGatewayID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), natGatewaySpec.Name),
-			Name: natGatewaySpec.Name,
-			NatGatewayIP: infrav1.PublicIPSpec{
-				Name: natGatewaySpec.NatGatewayIP.Name,
-			},
-		}
 		natGatewaySpec.Subnet.NatGateway = natGateway
 		s.Scope.SetSubnet(natGatewaySpec.Subnet)
 	}
 	return nil
 }
 
-func (s *Service) getExisting(ctx context.Context, spec azure.NatGatewaySpec) (*infrav1.NatGateway, error) {
-	existingNatGateway, err := s.Get(ctx, s.Scope.ResourceGroup(), spec.Name)
-	if err != nil {
-		return nil, err
-	}
-
-	return &infrav1.NatGateway{
-		ID:   to.String(existingNatGateway.ID),
-		Name: to.String(existingNatGateway.Name),
-		NatGatewayIP: infrav1.PublicIPSpec{
-			Name: to.String((*existingNatGateway.PublicIPAddresses)[0].ID),
-		},
-	}, nil
-}
-
 // Delete deletes the nat gateway with the provided name.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, span := tele.Tracer().Start(ctx, "natgateways.Service.Delete")
@@ -138,14 +142,27 @@ func (s *Service) Delete(ctx context.Context) error {
 		return nil
 	}
 	for _, natGatewaySpec := range s.Scope.NatGatewaySpecs() {
+		existingNatGateway, err := s.client.Get(ctx, s.Scope.NetworkResourceGroup(), natGatewaySpec.Name)
+		switch {
+		case err != nil && azure.ResourceNotFound(err):
+			// already deleted
+			continue
+		case err != nil:
+			return errors.Wrapf(err, "failed to get nat gateway %s in %s", natGatewaySpec.Name, s.Scope.NetworkResourceGroup())
+		case converters.MapToTags(existingNatGateway.Tags).HasShared(s.Scope.ClusterName()):
+			s.Scope.V(2).Info("skipping deletion of shared nat gateway", "nat gateway", natGatewaySpec.Name)
+			continue
+		}
+
 		s.Scope.V(2).Info("deleting nat gateway", "nat gateway", natGatewaySpec.Name)
-		err := s.client.Delete(ctx, s.Scope.ResourceGroup(), natGatewaySpec.Name)
+		err = s.client.Delete(ctx, s.Scope.NetworkResourceGroup(), natGatewaySpec.Name)
+		azure.RecordDelete(ctx, "NAT gateway", natGatewaySpec.Name, err)
 		if err != nil && azure.ResourceNotFound(err) {
 			// already deleted
 			continue
 		}
 		if err != nil {
-			return errors.Wrapf(err, "failed to delete nat gateway %s in resource group %s", natGatewaySpec.Name, s.Scope.ResourceGroup())
+			return errors.Wrapf(err, "failed to delete nat gateway %s in resource group %s", natGatewaySpec.Name, s.Scope.NetworkResourceGroup())
 		}
 
 		s.Scope.V(2).Info("successfully deleted nat gateway", "nat gateway", natGatewaySpec.Name)
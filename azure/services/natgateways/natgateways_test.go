@@ -77,7 +77,8 @@ func TestReconcileNatGateways(t *testing.T) {
 					Name: "my-vnet",
 				})
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ClusterName()
+				s.ClusterName().Times(2)
+				s.AdditionalTags()
 				s.NatGatewaySpecs().Return([]azure.NatGatewaySpec{
 					{
 						Name: "my-node-natgateway",
@@ -90,7 +91,7 @@ func TestReconcileNatGateways(t *testing.T) {
 				})
 
 				s.SubscriptionID().AnyTimes().Return("123")
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-node-natgateway").Return(network.NatGateway{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")).Times(1)
 				s.Location().Return("westus")
 				s.SetSubnet(infrav1.SubnetSpec{
@@ -120,7 +121,8 @@ func TestReconcileNatGateways(t *testing.T) {
 					Name: "my-vnet",
 				})
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ClusterName()
+				s.ClusterName().Times(3)
+				s.AdditionalTags()
 				s.NatGatewaySpecs().Return([]azure.NatGatewaySpec{
 					{
 						Name: "my-node-natgateway",
@@ -135,7 +137,8 @@ func TestReconcileNatGateways(t *testing.T) {
 				})
 
 				s.SubscriptionID().AnyTimes().Return("123")
-				s.ResourceGroup().Return("my-rg").AnyTimes()
+				s.NetworkResourceGroup().Return("my-rg").AnyTimes()
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-node-natgateway").Times(1).Return(network.NatGateway{
 					Name: to.StringPtr("my-node-natgateway"),
 					ID:   to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/my-node-natgateway"),
@@ -171,7 +174,8 @@ func TestReconcileNatGateways(t *testing.T) {
 					Name: "my-vnet",
 				})
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ClusterName()
+				s.ClusterName().Times(3).Return("test-cluster")
+				s.AdditionalTags()
 				s.NatGatewaySpecs().Return([]azure.NatGatewaySpec{
 					{
 						Name: "my-node-natgateway",
@@ -180,16 +184,24 @@ func TestReconcileNatGateways(t *testing.T) {
 							Role: infrav1.SubnetNode,
 						},
 						NatGatewayIP: infrav1.PublicIPSpec{
-							Name: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/pip-my-node-natgateway-node-subnet-natgw",
+							Name: "pip-my-node-natgateway-node-subnet-natgw",
 						},
 					},
 				})
 
 				s.SubscriptionID().AnyTimes().Return("123")
-				s.ResourceGroup().Return("my-rg").AnyTimes()
+				s.NetworkResourceGroup().Return("my-rg").AnyTimes()
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-node-natgateway").Times(1).Return(network.NatGateway{
-					Name: to.StringPtr("my-node-natgateway"),
-					ID:   to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/my-node-natgateway"),
+					Name:     to.StringPtr("my-node-natgateway"),
+					ID:       to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/my-node-natgateway"),
+					Location: to.StringPtr("westus"),
+					Sku:      &network.NatGatewaySku{Name: network.Standard},
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-node-natgateway"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						"created-by": to.StringPtr("someone"),
+					},
 					NatGatewayPropertiesFormat: &network.NatGatewayPropertiesFormat{PublicIPAddresses: &[]network.SubResource{
 						{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/pip-my-node-natgateway-node-subnet-natgw")},
 					}},
@@ -201,14 +213,63 @@ func TestReconcileNatGateways(t *testing.T) {
 						ID:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/my-node-natgateway",
 						Name: "my-node-natgateway",
 						NatGatewayIP: infrav1.PublicIPSpec{
-							Name: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/pip-my-node-natgateway-node-subnet-natgw",
+							Name: "pip-my-node-natgateway-node-subnet-natgw",
 						},
 					},
 				})
-				s.Location().Return("westus").Times(0)
+				s.Location().Return("westus")
 				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-node-natgateway", gomock.AssignableToTypeOf(network.NatGateway{})).Times(0)
 			},
 		},
+		{
+			name: "shared nat gateway is not updated",
+			tags: infrav1.Tags{
+				"Name": "my-vnet",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			},
+			expectedError: "",
+			expect: func(s *mock_natgateways.MockNatGatewayScopeMockRecorder, m *mock_natgateways.MockclientMockRecorder) {
+				s.Vnet().Return(&infrav1.VnetSpec{
+					Name: "my-vnet",
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.AdditionalTags()
+				s.NatGatewaySpecs().Return([]azure.NatGatewaySpec{
+					{
+						Name: "my-node-natgateway",
+						Subnet: infrav1.SubnetSpec{
+							Name: "node-subnet",
+							Role: infrav1.SubnetNode,
+						},
+						NatGatewayIP: infrav1.PublicIPSpec{
+							Name: "pip-my-node-natgateway-node-subnet-natgw",
+						},
+					},
+				})
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().Return("westus")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-node-natgateway").Return(network.NatGateway{
+					Name: to.StringPtr("my-node-natgateway"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("shared"),
+					},
+				}, nil)
+				s.SetSubnet(infrav1.SubnetSpec{
+					Role: infrav1.SubnetNode,
+					Name: "node-subnet",
+					NatGateway: infrav1.NatGateway{
+						ID:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/my-node-natgateway",
+						Name: "my-node-natgateway",
+						NatGatewayIP: infrav1.PublicIPSpec{
+							Name: "pip-my-node-natgateway-node-subnet-natgw",
+						},
+					},
+				})
+			},
+		},
 		{
 			name: "fail when getting existing nat gateway",
 			tags: infrav1.Tags{
@@ -232,7 +293,7 @@ func TestReconcileNatGateways(t *testing.T) {
 						},
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-node-natgateway").Return(network.NatGateway{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 				m.CreateOrUpdate(gomockinternal.AContext(), gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(network.NatGateway{})).Times(0)
 			},
@@ -250,7 +311,8 @@ func TestReconcileNatGateways(t *testing.T) {
 					Name: "my-vnet",
 				})
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ClusterName()
+				s.ClusterName().Times(2)
+				s.AdditionalTags()
 				s.NatGatewaySpecs().Return([]azure.NatGatewaySpec{
 					{
 						Name: "my-node-natgateway",
@@ -261,7 +323,7 @@ func TestReconcileNatGateways(t *testing.T) {
 					},
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-node-natgateway").Return(network.NatGateway{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				s.Location().Return("westus")
 				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-node-natgateway", gomock.AssignableToTypeOf(network.NatGateway{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
@@ -334,7 +396,7 @@ func TestDeleteNatGateway(t *testing.T) {
 					Name: "my-vnet",
 				})
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ClusterName()
+				s.ClusterName().AnyTimes().Return("test-cluster")
 				s.NatGatewaySpecs().Return([]azure.NatGatewaySpec{
 					{
 						Name: "my-node-natgateway",
@@ -344,7 +406,8 @@ func TestDeleteNatGateway(t *testing.T) {
 						},
 					},
 				})
-				s.ResourceGroup().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-node-natgateway").Return(network.NatGateway{Name: to.StringPtr("my-node-natgateway")}, nil)
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-node-natgateway")
 			},
 		},
@@ -361,7 +424,7 @@ func TestDeleteNatGateway(t *testing.T) {
 					Name: "my-vnet",
 				})
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ClusterName()
+				s.ClusterName().AnyTimes().Return("test-cluster")
 				s.NatGatewaySpecs().Return([]azure.NatGatewaySpec{
 					{
 						Name: "my-node-natgateway",
@@ -371,12 +434,44 @@ func TestDeleteNatGateway(t *testing.T) {
 						},
 					},
 				})
-				s.ResourceGroup().Return("my-rg")
-				m.Delete(gomockinternal.AContext(), "my-rg", "my-node-natgateway").Return(autorest.NewErrorWithResponse("", "", &http.Response{
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-node-natgateway").Return(network.NatGateway{}, autorest.NewErrorWithResponse("", "", &http.Response{
 					StatusCode: 404,
 				}, "Not Found"))
 			},
 		},
+		{
+			name: "shared nat gateway is not deleted",
+			tags: infrav1.Tags{
+				"Name": "my-vnet",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			},
+			expectedError: "",
+			expect: func(s *mock_natgateways.MockNatGatewayScopeMockRecorder, m *mock_natgateways.MockclientMockRecorder) {
+				s.Vnet().Return(&infrav1.VnetSpec{
+					Name: "my-vnet",
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.NatGatewaySpecs().Return([]azure.NatGatewaySpec{
+					{
+						Name: "my-node-natgateway",
+						Subnet: infrav1.SubnetSpec{
+							Name: "node-subnet",
+							Role: infrav1.SubnetNode,
+						},
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-node-natgateway").Return(network.NatGateway{
+					Name: to.StringPtr("my-node-natgateway"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("shared"),
+					},
+				}, nil)
+			},
+		},
 		{
 			name: "nat gateway deletion fails",
 			tags: infrav1.Tags{
@@ -390,7 +485,7 @@ func TestDeleteNatGateway(t *testing.T) {
 					Name: "my-vnet",
 				})
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ClusterName()
+				s.ClusterName().AnyTimes().Return("test-cluster")
 				s.NatGatewaySpecs().Return([]azure.NatGatewaySpec{
 					{
 						Name: "my-node-natgateway",
@@ -400,7 +495,8 @@ func TestDeleteNatGateway(t *testing.T) {
 						},
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-node-natgateway").Return(network.NatGateway{Name: to.StringPtr("my-node-natgateway")}, nil)
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-node-natgateway").Return(autorest.NewErrorWithResponse("", "", &http.Response{
 					StatusCode: 500,
 				}, "Internal Server Error"))
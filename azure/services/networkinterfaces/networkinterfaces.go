@@ -24,7 +24,9 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -84,13 +86,13 @@ func (s *Service) Reconcile(ctx context.Context) error {
 				if nicSpec.PublicLBAddressPoolName != "" {
 					backendAddressPools = append(backendAddressPools,
 						network.BackendAddressPool{
-							ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), nicSpec.PublicLBName, nicSpec.PublicLBAddressPoolName)),
+							ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), nicSpec.PublicLBName, nicSpec.PublicLBAddressPoolName)),
 						})
 				}
 				if nicSpec.PublicLBNATRuleName != "" {
 					nicConfig.LoadBalancerInboundNatRules = &[]network.InboundNatRule{
 						{
-							ID: to.StringPtr(azure.NATRuleID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), nicSpec.PublicLBName, nicSpec.PublicLBNATRuleName)),
+							ID: to.StringPtr(azure.NATRuleID(s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), nicSpec.PublicLBName, nicSpec.PublicLBNATRuleName)),
 						},
 					}
 				}
@@ -98,14 +100,14 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			if nicSpec.InternalLBName != "" && nicSpec.InternalLBAddressPoolName != "" {
 				backendAddressPools = append(backendAddressPools,
 					network.BackendAddressPool{
-						ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), nicSpec.InternalLBName, nicSpec.InternalLBAddressPoolName)),
+						ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), nicSpec.InternalLBName, nicSpec.InternalLBAddressPoolName)),
 					})
 			}
 			nicConfig.LoadBalancerBackendAddressPools = &backendAddressPools
 
 			if nicSpec.PublicIPName != "" {
 				nicConfig.PublicIPAddress = &network.PublicIPAddress{
-					ID: to.StringPtr(azure.PublicIPID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), nicSpec.PublicIPName)),
+					ID: to.StringPtr(azure.PublicIPID(s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), nicSpec.PublicIPName)),
 				}
 			}
 
@@ -145,12 +147,19 @@ func (s *Service) Reconcile(ctx context.Context) error {
 				nicSpec.Name,
 				network.Interface{
 					Location: to.StringPtr(s.Scope.Location()),
+					Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+						ClusterName: s.Scope.ClusterName(),
+						Lifecycle:   infrav1.ResourceLifecycleOwned,
+						Name:        to.StringPtr(nicSpec.Name),
+						Additional:  s.Scope.AdditionalTags(),
+					})),
 					InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
 						EnableAcceleratedNetworking: nicSpec.AcceleratedNetworking,
 						IPConfigurations:            &ipConfigurations,
 						EnableIPForwarding:          to.BoolPtr(nicSpec.EnableIPForwarding),
 					},
 				})
+			azure.RecordCreate(ctx, "network interface", nicSpec.Name, err)
 
 			if err != nil {
 				return errors.Wrapf(err, "failed to create network interface %s in resource group %s", nicSpec.Name, s.Scope.ResourceGroup())
@@ -169,6 +178,7 @@ func (s *Service) Delete(ctx context.Context) error {
 	for _, nicSpec := range s.Scope.NICSpecs() {
 		s.Scope.V(2).Info("deleting network interface %s", "network interface", nicSpec.Name)
 		err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), nicSpec.Name)
+		azure.RecordDelete(ctx, "network interface", nicSpec.Name, err)
 		if err != nil && !azure.ResourceNotFound(err) {
 			return errors.Wrapf(err, "failed to delete network interface %s in resource group %s", nicSpec.Name, s.Scope.ResourceGroup())
 		}
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphans
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
+	"github.com/Azure/go-autorest/autorest/to"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// listPageSize is the maximum number of resources requested per page, which bounds the number of resources held in
+// memory while listing a resource group.
+const listPageSize = 100
+
+// client wraps go-sdk.
+type client interface {
+	ListOwnedPages(context.Context, string, string, func([]resources.GenericResourceExpanded) error) error
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	resources resources.Client
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new orphans client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	resourcesClient := resources.NewClientWithBaseURI(auth.BaseURI(), auth.SubscriptionID())
+	azure.SetAutoRestClientDefaults(&resourcesClient.Client, auth.Authorizer())
+	return &azureClient{resourcesClient}
+}
+
+// ListOwnedPages calls process with each page of the resources of a resource group which are tagged as owned by the
+// cluster, along with their creation time. Pages hold at most listPageSize resources, and the next page is only
+// fetched once process returned.
+func (ac *azureClient) ListOwnedPages(ctx context.Context, resourceGroupName, clusterName string, process func([]resources.GenericResourceExpanded) error) error {
+	ctx, span := tele.Tracer().Start(ctx, "orphans.AzureClient.ListOwnedPages")
+	defer span.End()
+
+	filter := fmt.Sprintf("tagName eq '%s' and tagValue eq '%s'", infrav1.ClusterTagKey(clusterName), infrav1.ResourceLifecycleOwned)
+	page, err := ac.resources.ListByResourceGroup(ctx, resourceGroupName, filter, "createdTime", to.Int32Ptr(listPageSize))
+	if err != nil {
+		return err
+	}
+
+	for page.NotDone() {
+		if err := process(page.Values()); err != nil {
+			return err
+		}
+		if err := page.NextWithContext(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphans
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/disks"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// garbageCollectionInterval is the minimum time between two garbage collection passes.
+	garbageCollectionInterval = time.Hour
+	// gracePeriod is the minimum age of a resource before it is considered an orphan, so that resources which are
+	// still being created by another controller, and are not yet referenced by any spec, are left alone.
+	gracePeriod = time.Hour
+
+	networkInterfaceType = "Microsoft.Network/networkInterfaces"
+	publicIPType         = "Microsoft.Network/publicIPAddresses"
+	diskType             = "Microsoft.Compute/disks"
+)
+
+// OrphansScope defines the scope interface for an orphans service.
+type OrphansScope interface {
+	logr.Logger
+	azure.ClusterDescriber
+	ExpectedResourceIDs(ctx context.Context) ([]string, error)
+	LastGarbageCollection() time.Time
+	SetLastGarbageCollection(time.Time)
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope OrphansScope
+	client
+	interfacesClient networkinterfaces.Client
+	publicIPsClient  publicips.Client
+	disksClient      disks.Client
+	now              func() time.Time
+}
+
+// New creates a new orphans service.
+func New(scope OrphansScope) *Service {
+	return &Service{
+		Scope:            scope,
+		client:           newClient(scope),
+		interfacesClient: networkinterfaces.NewClient(scope),
+		publicIPsClient:  publicips.NewClient(scope),
+		disksClient:      disks.NewClient(scope),
+		now:              time.Now,
+	}
+}
+
+// Reconcile garbage collects the network interfaces, public IPs and disks which are tagged as owned by the cluster but
+// don't match any spec anymore, like the ones left behind by a failed machine creation. Resources attached to a VM or
+// a network interface, or created less than a grace period ago, are never collected. Garbage collection is best
+// effort: failures are logged and retried on the next pass.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, span := tele.Tracer().Start(ctx, "orphans.Service.Reconcile")
+	defer span.End()
+
+	now := s.now()
+	if now.Sub(s.Scope.LastGarbageCollection()) < garbageCollectionInterval {
+		return nil
+	}
+
+	expectedIDs, err := s.Scope.ExpectedResourceIDs(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the expected resources of the cluster")
+	}
+	expected := make(map[string]bool, len(expectedIDs))
+	for _, id := range expectedIDs {
+		expected[strings.ToLower(id)] = true
+	}
+
+	resourceGroups := []string{s.Scope.ResourceGroup()}
+	if s.Scope.NetworkResourceGroup() != s.Scope.ResourceGroup() {
+		resourceGroups = append(resourceGroups, s.Scope.NetworkResourceGroup())
+	}
+
+	for _, resourceGroup := range resourceGroups {
+		resourceGroup := resourceGroup
+		err := s.client.ListOwnedPages(ctx, resourceGroup, s.Scope.ClusterName(), func(owned []resources.GenericResourceExpanded) error {
+			for _, resource := range owned {
+				if !s.isCandidate(resource, expected, now) {
+					continue
+				}
+				attached, err := s.isAttached(ctx, resourceGroup, resource)
+				if err != nil {
+					if !azure.ResourceNotFound(err) {
+						s.Scope.Error(err, "failed to get orphaned resource", "resource", to.String(resource.ID))
+					}
+					continue
+				}
+				if attached {
+					continue
+				}
+				s.Scope.V(2).Info("deleting orphaned resource", "resource", to.String(resource.ID))
+				if err := s.deleteResource(ctx, resourceGroup, resource); err != nil && !azure.ResourceNotFound(err) {
+					s.Scope.Error(err, "failed to delete orphaned resource", "resource", to.String(resource.ID))
+					continue
+				}
+				s.Scope.V(2).Info("successfully deleted orphaned resource", "resource", to.String(resource.ID))
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to list resources owned by the cluster in resource group %s", resourceGroup)
+		}
+	}
+
+	s.Scope.SetLastGarbageCollection(now)
+	return nil
+}
+
+// isCandidate returns true if the resource is of a collected type, is not expected by any spec and is older than the
+// grace period.
+func (s *Service) isCandidate(resource resources.GenericResourceExpanded, expected map[string]bool, now time.Time) bool {
+	switch to.String(resource.Type) {
+	case networkInterfaceType, publicIPType, diskType:
+	default:
+		return false
+	}
+	if expected[strings.ToLower(to.String(resource.ID))] {
+		return false
+	}
+	if resource.CreatedTime == nil || now.Sub(resource.CreatedTime.Time) < gracePeriod {
+		return false
+	}
+	return true
+}
+
+// isAttached returns true if the resource is attached to another resource. Azure doesn't set the managedBy property of
+// network interfaces and public IPs, so the resource is fetched to check its references.
+func (s *Service) isAttached(ctx context.Context, resourceGroup string, resource resources.GenericResourceExpanded) (bool, error) {
+	name := to.String(resource.Name)
+	switch to.String(resource.Type) {
+	case networkInterfaceType:
+		nic, err := s.interfacesClient.Get(ctx, resourceGroup, name)
+		if err != nil {
+			return false, err
+		}
+		return nic.InterfacePropertiesFormat != nil && nic.VirtualMachine != nil, nil
+	case publicIPType:
+		ip, err := s.publicIPsClient.Get(ctx, resourceGroup, name)
+		if err != nil {
+			return false, err
+		}
+		return ip.PublicIPAddressPropertiesFormat != nil && (ip.IPConfiguration != nil || ip.NatGateway != nil), nil
+	case diskType:
+		disk, err := s.disksClient.Get(ctx, resourceGroup, name)
+		if err != nil {
+			return false, err
+		}
+		return to.String(disk.ManagedBy) != "", nil
+	default:
+		return false, errors.Errorf("unsupported resource type %s", to.String(resource.Type))
+	}
+}
+
+// deleteResource deletes an orphaned resource with the client matching its type.
+func (s *Service) deleteResource(ctx context.Context, resourceGroup string, resource resources.GenericResourceExpanded) error {
+	name := to.String(resource.Name)
+	switch to.String(resource.Type) {
+	case networkInterfaceType:
+		return s.interfacesClient.Delete(ctx, resourceGroup, name)
+	case publicIPType:
+		return s.publicIPsClient.Delete(ctx, resourceGroup, name)
+	case diskType:
+		return s.disksClient.Delete(ctx, resourceGroup, name)
+	default:
+		return errors.Errorf("unsupported resource type %s", to.String(resource.Type))
+	}
+}
+
+// Delete is a no-op as the resources of the cluster are deleted by their own service, or along with the resource group.
+func (s *Service) Delete(ctx context.Context) error {
+	_, span := tele.Tracer().Start(ctx, "orphans.Service.Delete")
+	defer span.End()
+
+	return nil
+}
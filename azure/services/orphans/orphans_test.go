@@ -0,0 +1,287 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphans
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-30/compute"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/v2/klogr"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/disks/mock_disks"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces/mock_networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/orphans/mock_orphans"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips/mock_publicips"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	now      = time.Date(2021, time.July, 1, 12, 0, 0, 0, time.UTC)
+	longAgo  = &date.Time{Time: now.Add(-24 * time.Hour)}
+	recently = &date.Time{Time: now.Add(-time.Minute)}
+)
+
+func TestReconcileOrphans(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expect        func(s *mock_orphans.MockOrphansScopeMockRecorder, m *mock_orphans.MockclientMockRecorder, mnic *mock_networkinterfaces.MockClientMockRecorder, mpip *mock_publicips.MockClientMockRecorder, mdisk *mock_disks.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name:          "garbage collected recently",
+			expectedError: "",
+			expect: func(s *mock_orphans.MockOrphansScopeMockRecorder, m *mock_orphans.MockclientMockRecorder, mnic *mock_networkinterfaces.MockClientMockRecorder, mpip *mock_publicips.MockClientMockRecorder, mdisk *mock_disks.MockClientMockRecorder) {
+				s.LastGarbageCollection().Return(now.Add(-10 * time.Minute))
+			},
+		},
+		{
+			name:          "delete orphaned resources",
+			expectedError: "",
+			expect: func(s *mock_orphans.MockOrphansScopeMockRecorder, m *mock_orphans.MockclientMockRecorder, mnic *mock_networkinterfaces.MockClientMockRecorder, mpip *mock_publicips.MockClientMockRecorder, mdisk *mock_disks.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.LastGarbageCollection().Return(time.Time{})
+				s.ExpectedResourceIDs(gomockinternal.AContext()).Return([]string{
+					"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/my-vm-nic",
+				}, nil)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.ListOwnedPages(gomockinternal.AContext(), "my-rg", "my-cluster", gomock.Any()).DoAndReturn(ownedPages([]resources.GenericResourceExpanded{
+					{
+						ID:          to.StringPtr("/subscriptions/123/resourceGroups/MY-RG/providers/Microsoft.Network/networkInterfaces/my-vm-nic"),
+						Name:        to.StringPtr("my-vm-nic"),
+						Type:        to.StringPtr("Microsoft.Network/networkInterfaces"),
+						CreatedTime: longAgo,
+					},
+					{
+						ID:          to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/failed-vm-nic"),
+						Name:        to.StringPtr("failed-vm-nic"),
+						Type:        to.StringPtr("Microsoft.Network/networkInterfaces"),
+						CreatedTime: longAgo,
+					},
+					{
+						ID:          to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/failed-vm-public-ip"),
+						Name:        to.StringPtr("failed-vm-public-ip"),
+						Type:        to.StringPtr("Microsoft.Network/publicIPAddresses"),
+						CreatedTime: longAgo,
+					},
+					{
+						ID:          to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/other-vm-nic"),
+						Name:        to.StringPtr("other-vm-nic"),
+						Type:        to.StringPtr("Microsoft.Network/networkInterfaces"),
+						CreatedTime: longAgo,
+					},
+					{
+						ID:          to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/other-vm-public-ip"),
+						Name:        to.StringPtr("other-vm-public-ip"),
+						Type:        to.StringPtr("Microsoft.Network/publicIPAddresses"),
+						CreatedTime: longAgo,
+					},
+				}, []resources.GenericResourceExpanded{
+					{
+						ID:          to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/failed-vm_OSDisk"),
+						Name:        to.StringPtr("failed-vm_OSDisk"),
+						Type:        to.StringPtr("Microsoft.Compute/disks"),
+						CreatedTime: longAgo,
+					},
+					{
+						ID:          to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/other-vm_OSDisk"),
+						Name:        to.StringPtr("other-vm_OSDisk"),
+						Type:        to.StringPtr("Microsoft.Compute/disks"),
+						CreatedTime: longAgo,
+					},
+					{
+						ID:          to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/new-vm-nic"),
+						Name:        to.StringPtr("new-vm-nic"),
+						Type:        to.StringPtr("Microsoft.Network/networkInterfaces"),
+						CreatedTime: recently,
+					},
+					{
+						ID:          to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/unknown-vm"),
+						Name:        to.StringPtr("unknown-vm"),
+						Type:        to.StringPtr("Microsoft.Compute/virtualMachines"),
+						CreatedTime: longAgo,
+					},
+				}))
+				mnic.Get(gomockinternal.AContext(), "my-rg", "failed-vm-nic").Return(network.Interface{InterfacePropertiesFormat: &network.InterfacePropertiesFormat{}}, nil)
+				mnic.Delete(gomockinternal.AContext(), "my-rg", "failed-vm-nic")
+				mpip.Get(gomockinternal.AContext(), "my-rg", "failed-vm-public-ip").Return(network.PublicIPAddress{PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{}}, nil)
+				mpip.Delete(gomockinternal.AContext(), "my-rg", "failed-vm-public-ip")
+				mnic.Get(gomockinternal.AContext(), "my-rg", "other-vm-nic").Return(network.Interface{
+					InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+						VirtualMachine: &network.SubResource{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/other-vm")},
+					},
+				}, nil)
+				mpip.Get(gomockinternal.AContext(), "my-rg", "other-vm-public-ip").Return(network.PublicIPAddress{
+					PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+						IPConfiguration: &network.IPConfiguration{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/other-vm-public-nic/ipConfigurations/pipConfig")},
+					},
+				}, nil)
+				mdisk.Get(gomockinternal.AContext(), "my-rg", "failed-vm_OSDisk").Return(compute.Disk{}, nil)
+				mdisk.Delete(gomockinternal.AContext(), "my-rg", "failed-vm_OSDisk")
+				mdisk.Get(gomockinternal.AContext(), "my-rg", "other-vm_OSDisk").Return(compute.Disk{
+					ManagedBy: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/other-vm"),
+				}, nil)
+				s.SetLastGarbageCollection(now)
+			},
+		},
+		{
+			name:          "list resources in the network resource group",
+			expectedError: "",
+			expect: func(s *mock_orphans.MockOrphansScopeMockRecorder, m *mock_orphans.MockclientMockRecorder, mnic *mock_networkinterfaces.MockClientMockRecorder, mpip *mock_publicips.MockClientMockRecorder, mdisk *mock_disks.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.LastGarbageCollection().Return(now.Add(-2 * time.Hour))
+				s.ExpectedResourceIDs(gomockinternal.AContext()).Return(nil, nil)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-network-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.ListOwnedPages(gomockinternal.AContext(), "my-rg", "my-cluster", gomock.Any()).DoAndReturn(ownedPages())
+				m.ListOwnedPages(gomockinternal.AContext(), "my-network-rg", "my-cluster", gomock.Any()).DoAndReturn(ownedPages([]resources.GenericResourceExpanded{
+					{
+						ID:          to.StringPtr("/subscriptions/123/resourceGroups/my-network-rg/providers/Microsoft.Network/publicIPAddresses/failed-vm-public-ip"),
+						Name:        to.StringPtr("failed-vm-public-ip"),
+						Type:        to.StringPtr("Microsoft.Network/publicIPAddresses"),
+						CreatedTime: longAgo,
+					},
+				}))
+				mpip.Get(gomockinternal.AContext(), "my-network-rg", "failed-vm-public-ip").Return(network.PublicIPAddress{PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{}}, nil)
+				mpip.Delete(gomockinternal.AContext(), "my-network-rg", "failed-vm-public-ip")
+				s.SetLastGarbageCollection(now)
+			},
+		},
+		{
+			name:          "get and deletion failures are ignored",
+			expectedError: "",
+			expect: func(s *mock_orphans.MockOrphansScopeMockRecorder, m *mock_orphans.MockclientMockRecorder, mnic *mock_networkinterfaces.MockClientMockRecorder, mpip *mock_publicips.MockClientMockRecorder, mdisk *mock_disks.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.LastGarbageCollection().Return(time.Time{})
+				s.ExpectedResourceIDs(gomockinternal.AContext()).Return(nil, nil)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.ListOwnedPages(gomockinternal.AContext(), "my-rg", "my-cluster", gomock.Any()).DoAndReturn(ownedPages([]resources.GenericResourceExpanded{
+					{
+						ID:          to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/failed-vm-nic"),
+						Name:        to.StringPtr("failed-vm-nic"),
+						Type:        to.StringPtr("Microsoft.Network/networkInterfaces"),
+						CreatedTime: longAgo,
+					},
+					{
+						ID:          to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/failed-vm-public-ip"),
+						Name:        to.StringPtr("failed-vm-public-ip"),
+						Type:        to.StringPtr("Microsoft.Network/publicIPAddresses"),
+						CreatedTime: longAgo,
+					},
+					{
+						ID:          to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/deleted-vm_OSDisk"),
+						Name:        to.StringPtr("deleted-vm_OSDisk"),
+						Type:        to.StringPtr("Microsoft.Compute/disks"),
+						CreatedTime: longAgo,
+					},
+				}))
+				mnic.Get(gomockinternal.AContext(), "my-rg", "failed-vm-nic").Return(network.Interface{InterfacePropertiesFormat: &network.InterfacePropertiesFormat{}}, nil)
+				mnic.Delete(gomockinternal.AContext(), "my-rg", "failed-vm-nic").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				s.Error(gomock.Any(), "failed to delete orphaned resource", "resource", "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/failed-vm-nic")
+				mpip.Get(gomockinternal.AContext(), "my-rg", "failed-vm-public-ip").
+					Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				s.Error(gomock.Any(), "failed to get orphaned resource", "resource", "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/failed-vm-public-ip")
+				mdisk.Get(gomockinternal.AContext(), "my-rg", "deleted-vm_OSDisk").
+					Return(compute.Disk{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				s.SetLastGarbageCollection(now)
+			},
+		},
+		{
+			name:          "error getting expected resources",
+			expectedError: "failed to get the expected resources of the cluster: failed to list AzureMachines",
+			expect: func(s *mock_orphans.MockOrphansScopeMockRecorder, m *mock_orphans.MockclientMockRecorder, mnic *mock_networkinterfaces.MockClientMockRecorder, mpip *mock_publicips.MockClientMockRecorder, mdisk *mock_disks.MockClientMockRecorder) {
+				s.LastGarbageCollection().Return(time.Time{})
+				s.ExpectedResourceIDs(gomockinternal.AContext()).Return(nil, errors.New("failed to list AzureMachines"))
+			},
+		},
+		{
+			name:          "error listing owned resources",
+			expectedError: "failed to list resources owned by the cluster in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_orphans.MockOrphansScopeMockRecorder, m *mock_orphans.MockclientMockRecorder, mnic *mock_networkinterfaces.MockClientMockRecorder, mpip *mock_publicips.MockClientMockRecorder, mdisk *mock_disks.MockClientMockRecorder) {
+				s.LastGarbageCollection().Return(time.Time{})
+				s.ExpectedResourceIDs(gomockinternal.AContext()).Return(nil, nil)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.ListOwnedPages(gomockinternal.AContext(), "my-rg", "my-cluster", gomock.Any()).
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_orphans.NewMockOrphansScope(mockCtrl)
+			clientMock := mock_orphans.NewMockclient(mockCtrl)
+			interfaceMock := mock_networkinterfaces.NewMockClient(mockCtrl)
+			publicIPMock := mock_publicips.NewMockClient(mockCtrl)
+			diskMock := mock_disks.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), interfaceMock.EXPECT(), publicIPMock.EXPECT(), diskMock.EXPECT())
+
+			s := &Service{
+				Scope:            scopeMock,
+				client:           clientMock,
+				interfacesClient: interfaceMock,
+				publicIPsClient:  publicIPMock,
+				disksClient:      diskMock,
+				now:              func() time.Time { return now },
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+// ownedPages returns a fake ListOwnedPages implementation which processes the given pages of resources.
+func ownedPages(pages ...[]resources.GenericResourceExpanded) func(context.Context, string, string, func([]resources.GenericResourceExpanded) error) error {
+	return func(_ context.Context, _, _ string, process func([]resources.GenericResourceExpanded) error) error {
+		for _, page := range pages {
+			if err := process(page); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -28,10 +28,13 @@ import (
 
 // Client wraps go-sdk.
 type client interface {
+	GetZone(context.Context, string, string) (privatedns.PrivateZone, error)
 	CreateOrUpdateZone(context.Context, string, string, privatedns.PrivateZone) error
 	DeleteZone(context.Context, string, string) error
+	GetLink(context.Context, string, string, string) (privatedns.VirtualNetworkLink, error)
 	CreateOrUpdateLink(context.Context, string, string, string, privatedns.VirtualNetworkLink) error
 	DeleteLink(context.Context, string, string, string) error
+	GetRecordSet(context.Context, string, string, privatedns.RecordType, string) (privatedns.RecordSet, error)
 	CreateOrUpdateRecordSet(context.Context, string, string, privatedns.RecordType, string, privatedns.RecordSet) error
 	DeleteRecordSet(context.Context, string, string, privatedns.RecordType, string) error
 }
@@ -74,6 +77,14 @@ func newRecordSetsClient(subscriptionID string, baseURI string, authorizer autor
 	return recordsClient
 }
 
+// GetZone gets the specified private zone.
+func (ac *azureClient) GetZone(ctx context.Context, resourceGroupName, zoneName string) (privatedns.PrivateZone, error) {
+	ctx, span := tele.Tracer().Start(ctx, "privatedns.AzureClient.GetZone")
+	defer span.End()
+
+	return ac.privatezones.Get(ctx, resourceGroupName, zoneName)
+}
+
 // CreateOrUpdateZone creates or updates a private zone.
 func (ac *azureClient) CreateOrUpdateZone(ctx context.Context, resourceGroupName string, zoneName string, zone privatedns.PrivateZone) error {
 	ctx, span := tele.Tracer().Start(ctx, "privatedns.AzureClient.CreateOrUpdateZone")
@@ -108,6 +119,14 @@ func (ac *azureClient) DeleteZone(ctx context.Context, resourceGroupName, name s
 	return err
 }
 
+// GetLink gets the specified virtual network link to the specified Private DNS zone.
+func (ac *azureClient) GetLink(ctx context.Context, resourceGroupName, privateZoneName, name string) (privatedns.VirtualNetworkLink, error) {
+	ctx, span := tele.Tracer().Start(ctx, "privatedns.AzureClient.GetLink")
+	defer span.End()
+
+	return ac.vnetlinks.Get(ctx, resourceGroupName, privateZoneName, name)
+}
+
 // CreateOrUpdateLink creates or updates a virtual network link to the specified Private DNS zone.
 func (ac *azureClient) CreateOrUpdateLink(ctx context.Context, resourceGroupName, privateZoneName, name string, link privatedns.VirtualNetworkLink) error {
 	ctx, span := tele.Tracer().Start(ctx, "privatedns.AzureClient.CreateOrUpdateLink")
@@ -142,6 +161,14 @@ func (ac *azureClient) DeleteLink(ctx context.Context, resourceGroupName, privat
 	return err
 }
 
+// GetRecordSet gets the specified record set within the specified Private DNS zone.
+func (ac *azureClient) GetRecordSet(ctx context.Context, resourceGroupName, privateZoneName string, recordType privatedns.RecordType, name string) (privatedns.RecordSet, error) {
+	ctx, span := tele.Tracer().Start(ctx, "privatedns.AzureClient.GetRecordSet")
+	defer span.End()
+
+	return ac.recordsets.Get(ctx, resourceGroupName, privateZoneName, recordType, name)
+}
+
 // CreateOrUpdateRecordSet creates or updates a record set within the specified Private DNS zone.
 func (ac *azureClient) CreateOrUpdateRecordSet(ctx context.Context, resourceGroupName string, privateZoneName string, recordType privatedns.RecordType, name string, set privatedns.RecordSet) error {
 	ctx, span := tele.Tracer().Start(ctx, "privatedns.AzureClient.CreateOrUpdateRecordSet")
@@ -24,6 +24,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -58,15 +59,36 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	zoneSpec := s.Scope.PrivateDNSSpec()
 	if zoneSpec != nil {
 		// Create the private DNS zone.
-		s.Scope.V(2).Info("creating private DNS zone", "private dns zone", zoneSpec.ZoneName)
-		err := s.client.CreateOrUpdateZone(ctx, s.Scope.ResourceGroup(), zoneSpec.ZoneName, privatedns.PrivateZone{Location: to.StringPtr(azure.Global)})
+		zone := privatedns.PrivateZone{
+			Location: to.StringPtr(azure.Global),
+			Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+				ClusterName: s.Scope.ClusterName(),
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+				Name:        to.StringPtr(zoneSpec.ZoneName),
+				Additional:  s.Scope.AdditionalTags(),
+			})),
+		}
+		existingZone, err := s.client.GetZone(ctx, s.Scope.NetworkResourceGroup(), zoneSpec.ZoneName)
+		upToDate, err := isUpToDate(zone, existingZone, err)
 		if err != nil {
-			return errors.Wrapf(err, "failed to create private DNS zone %s", zoneSpec.ZoneName)
+			return errors.Wrapf(err, "failed to get private DNS zone %s", zoneSpec.ZoneName)
+		}
+		if upToDate {
+			s.Scope.V(4).Info("private DNS zone is up to date, skipping update", "private dns zone", zoneSpec.ZoneName)
+		} else if converters.MapToTags(existingZone.Tags).HasShared(s.Scope.ClusterName()) {
+			// shared private DNS zones are only read by the cluster, which still adds its own records to them
+			s.Scope.V(4).Info("private DNS zone is shared, skipping update", "private dns zone", zoneSpec.ZoneName)
+		} else {
+			s.Scope.V(2).Info("creating private DNS zone", "private dns zone", zoneSpec.ZoneName)
+			err = s.client.CreateOrUpdateZone(ctx, s.Scope.NetworkResourceGroup(), zoneSpec.ZoneName, zone)
+			azure.RecordCreate(ctx, "private DNS zone", zoneSpec.ZoneName, err)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create private DNS zone %s", zoneSpec.ZoneName)
+			}
+			s.Scope.V(2).Info("successfully created private DNS zone", "private dns zone", zoneSpec.ZoneName)
 		}
-		s.Scope.V(2).Info("successfully created private DNS zone", "private dns zone", zoneSpec.ZoneName)
 
 		// Link the virtual network.
-		s.Scope.V(2).Info("creating a virtual network link", "virtual network", zoneSpec.VNetName, "private dns zone", zoneSpec.ZoneName)
 		link := privatedns.VirtualNetworkLink{
 			VirtualNetworkLinkProperties: &privatedns.VirtualNetworkLinkProperties{
 				VirtualNetwork: &privatedns.SubResource{
@@ -75,16 +97,34 @@ func (s *Service) Reconcile(ctx context.Context) error {
 				RegistrationEnabled: to.BoolPtr(false),
 			},
 			Location: to.StringPtr(azure.Global),
+			Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+				ClusterName: s.Scope.ClusterName(),
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+				Name:        to.StringPtr(zoneSpec.LinkName),
+				Additional:  s.Scope.AdditionalTags(),
+			})),
 		}
-		err = s.client.CreateOrUpdateLink(ctx, s.Scope.ResourceGroup(), zoneSpec.ZoneName, zoneSpec.LinkName, link)
+		existingLink, err := s.client.GetLink(ctx, s.Scope.NetworkResourceGroup(), zoneSpec.ZoneName, zoneSpec.LinkName)
+		upToDate, err = isUpToDate(link, existingLink, err)
 		if err != nil {
-			return errors.Wrapf(err, "failed to create virtual network link %s", zoneSpec.LinkName)
+			return errors.Wrapf(err, "failed to get virtual network link %s", zoneSpec.LinkName)
+		}
+		if upToDate {
+			s.Scope.V(4).Info("virtual network link is up to date, skipping update", "virtual network", zoneSpec.VNetName, "private dns zone", zoneSpec.ZoneName)
+		} else if converters.MapToTags(existingLink.Tags).HasShared(s.Scope.ClusterName()) {
+			s.Scope.V(4).Info("virtual network link is shared, skipping update", "virtual network", zoneSpec.VNetName, "private dns zone", zoneSpec.ZoneName)
+		} else {
+			s.Scope.V(2).Info("creating a virtual network link", "virtual network", zoneSpec.VNetName, "private dns zone", zoneSpec.ZoneName)
+			err = s.client.CreateOrUpdateLink(ctx, s.Scope.NetworkResourceGroup(), zoneSpec.ZoneName, zoneSpec.LinkName, link)
+			azure.RecordCreate(ctx, "private DNS zone virtual network link", zoneSpec.LinkName, err)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create virtual network link %s", zoneSpec.LinkName)
+			}
+			s.Scope.V(2).Info("successfully created virtual network link", "virtual network", zoneSpec.VNetName, "private dns zone", zoneSpec.ZoneName)
 		}
-		s.Scope.V(2).Info("successfully created virtual network link", "virtual network", zoneSpec.VNetName, "private dns zone", zoneSpec.ZoneName)
 
 		// Create the record(s).
 		for _, record := range zoneSpec.Records {
-			s.Scope.V(2).Info("creating record set", "private dns zone", zoneSpec.ZoneName, "record", record.Hostname)
 			set := privatedns.RecordSet{
 				RecordSetProperties: &privatedns.RecordSetProperties{
 					TTL: to.Int64Ptr(300),
@@ -100,8 +140,17 @@ func (s *Service) Reconcile(ctx context.Context) error {
 					Ipv6Address: &record.IP,
 				}}
 			}
-			err := s.client.CreateOrUpdateRecordSet(ctx, s.Scope.ResourceGroup(), zoneSpec.ZoneName, recordType, record.Hostname, set)
+			existingSet, err := s.client.GetRecordSet(ctx, s.Scope.NetworkResourceGroup(), zoneSpec.ZoneName, recordType, record.Hostname)
+			upToDate, err := isUpToDate(set, existingSet, err)
 			if err != nil {
+				return errors.Wrapf(err, "failed to get record %s in private DNS zone %s", record.Hostname, zoneSpec.ZoneName)
+			}
+			if upToDate {
+				s.Scope.V(4).Info("record set is up to date, skipping update", "private dns zone", zoneSpec.ZoneName, "record", record.Hostname)
+				continue
+			}
+			s.Scope.V(2).Info("creating record set", "private dns zone", zoneSpec.ZoneName, "record", record.Hostname)
+			if err := s.client.CreateOrUpdateRecordSet(ctx, s.Scope.NetworkResourceGroup(), zoneSpec.ZoneName, recordType, record.Hostname, set); err != nil {
 				return errors.Wrapf(err, "failed to create record %s in private DNS zone %s", record.Hostname, zoneSpec.ZoneName)
 			}
 			s.Scope.V(2).Info("successfully created record set", "private dns zone", zoneSpec.ZoneName, "record", record.Hostname)
@@ -110,6 +159,18 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	return nil
 }
 
+// isUpToDate returns whether an existing resource, read with the given error, already matches the desired one.
+// Resources which don't exist yet are not up to date.
+func isUpToDate(desired, existing interface{}, getErr error) (bool, error) {
+	switch {
+	case azure.ResourceNotFound(getErr):
+		return false, nil
+	case getErr != nil:
+		return false, getErr
+	}
+	return azure.IsUpToDate(desired, existing)
+}
+
 // Delete deletes the private zone.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, span := tele.Tracer().Start(ctx, "privatedns.Service.Delete")
@@ -117,22 +178,45 @@ func (s *Service) Delete(ctx context.Context) error {
 
 	zoneSpec := s.Scope.PrivateDNSSpec()
 	if zoneSpec != nil {
-		// Remove the virtual network link.
-		s.Scope.V(2).Info("removing virtual network link", "virtual network", zoneSpec.VNetName, "private dns zone", zoneSpec.ZoneName)
-		err := s.client.DeleteLink(ctx, s.Scope.ResourceGroup(), zoneSpec.ZoneName, zoneSpec.LinkName)
-		if err != nil && !azure.ResourceNotFound(err) {
-			return errors.Wrapf(err, "failed to delete virtual network link %s with zone %s in resource group %s", zoneSpec.VNetName, zoneSpec.ZoneName, s.Scope.ResourceGroup())
+		// Remove the virtual network link, unless it is shared.
+		existingLink, err := s.client.GetLink(ctx, s.Scope.NetworkResourceGroup(), zoneSpec.ZoneName, zoneSpec.LinkName)
+		switch {
+		case err != nil && !azure.ResourceNotFound(err):
+			return errors.Wrapf(err, "failed to get virtual network link %s", zoneSpec.LinkName)
+		case err != nil:
+			// already deleted
+		case converters.MapToTags(existingLink.Tags).HasShared(s.Scope.ClusterName()):
+			s.Scope.V(2).Info("skipping deletion of shared virtual network link", "virtual network", zoneSpec.VNetName, "private dns zone", zoneSpec.ZoneName)
+		default:
+			s.Scope.V(2).Info("removing virtual network link", "virtual network", zoneSpec.VNetName, "private dns zone", zoneSpec.ZoneName)
+			err := s.client.DeleteLink(ctx, s.Scope.NetworkResourceGroup(), zoneSpec.ZoneName, zoneSpec.LinkName)
+			azure.RecordDelete(ctx, "private DNS zone virtual network link", zoneSpec.LinkName, err)
+			if err != nil && !azure.ResourceNotFound(err) {
+				return errors.Wrapf(err, "failed to delete virtual network link %s with zone %s in resource group %s", zoneSpec.VNetName, zoneSpec.ZoneName, s.Scope.NetworkResourceGroup())
+			}
 		}
 
-		// Delete the private DNS zone, which also deletes all records.
+		// Delete the private DNS zone, which also deletes all records, unless it is shared.
+		existingZone, err := s.client.GetZone(ctx, s.Scope.NetworkResourceGroup(), zoneSpec.ZoneName)
+		switch {
+		case err != nil && azure.ResourceNotFound(err):
+			// already deleted
+			return nil
+		case err != nil:
+			return errors.Wrapf(err, "failed to get private DNS zone %s", zoneSpec.ZoneName)
+		case converters.MapToTags(existingZone.Tags).HasShared(s.Scope.ClusterName()):
+			s.Scope.V(2).Info("skipping deletion of shared private dns zone", "private dns zone", zoneSpec.ZoneName)
+			return nil
+		}
 		s.Scope.V(2).Info("deleting private dns zone", "private dns zone", zoneSpec.ZoneName)
-		err = s.client.DeleteZone(ctx, s.Scope.ResourceGroup(), zoneSpec.ZoneName)
+		err = s.client.DeleteZone(ctx, s.Scope.NetworkResourceGroup(), zoneSpec.ZoneName)
+		azure.RecordDelete(ctx, "private DNS zone", zoneSpec.ZoneName, err)
 		if err != nil && azure.ResourceNotFound(err) {
 			// already deleted
 			return nil
 		}
 		if err != nil && !azure.ResourceNotFound(err) {
-			return errors.Wrapf(err, "failed to delete private dns zone %s in resource group %s", zoneSpec.ZoneName, s.Scope.ResourceGroup())
+			return errors.Wrapf(err, "failed to delete private dns zone %s in resource group %s", zoneSpec.ZoneName, s.Scope.NetworkResourceGroup())
 		}
 		s.Scope.V(2).Info("successfully deleted private dns zone", "private dns zone", zoneSpec.ZoneName)
 	}
@@ -65,9 +65,20 @@ func TestReconcilePrivateDNS(t *testing.T) {
 						},
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.SubscriptionID().Return("123")
-				m.CreateOrUpdateZone(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.PrivateZone{Location: to.StringPtr(azure.Global)})
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{"foo": "bar"})
+				m.GetZone(gomockinternal.AContext(), "my-rg", "my-dns-zone").Return(privatedns.PrivateZone{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateZone(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.PrivateZone{
+					Location: to.StringPtr(azure.Global),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-dns-zone"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"foo": to.StringPtr("bar"),
+					},
+				})
+				m.GetLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link").Return(privatedns.VirtualNetworkLink{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdateLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link", privatedns.VirtualNetworkLink{
 					VirtualNetworkLinkProperties: &privatedns.VirtualNetworkLinkProperties{
 						VirtualNetwork: &privatedns.SubResource{
@@ -76,7 +87,13 @@ func TestReconcilePrivateDNS(t *testing.T) {
 						RegistrationEnabled: to.BoolPtr(false),
 					},
 					Location: to.StringPtr(azure.Global),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-link"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"foo": to.StringPtr("bar"),
+					},
 				})
+				m.GetRecordSet(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.A, "hostname-1").Return(privatedns.RecordSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdateRecordSet(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.A, "hostname-1", privatedns.RecordSet{
 					RecordSetProperties: &privatedns.RecordSetProperties{
 						TTL: to.Int64Ptr(300),
@@ -106,9 +123,20 @@ func TestReconcilePrivateDNS(t *testing.T) {
 						},
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.SubscriptionID().Return("123")
-				m.CreateOrUpdateZone(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.PrivateZone{Location: to.StringPtr(azure.Global)})
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{"foo": "bar"})
+				m.GetZone(gomockinternal.AContext(), "my-rg", "my-dns-zone").Return(privatedns.PrivateZone{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateZone(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.PrivateZone{
+					Location: to.StringPtr(azure.Global),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-dns-zone"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"foo": to.StringPtr("bar"),
+					},
+				})
+				m.GetLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link").Return(privatedns.VirtualNetworkLink{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdateLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link", privatedns.VirtualNetworkLink{
 					VirtualNetworkLinkProperties: &privatedns.VirtualNetworkLinkProperties{
 						VirtualNetwork: &privatedns.SubResource{
@@ -117,7 +145,13 @@ func TestReconcilePrivateDNS(t *testing.T) {
 						RegistrationEnabled: to.BoolPtr(false),
 					},
 					Location: to.StringPtr(azure.Global),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-link"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"foo": to.StringPtr("bar"),
+					},
 				})
+				m.GetRecordSet(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.AAAA, "hostname-2").Return(privatedns.RecordSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdateRecordSet(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.AAAA, "hostname-2", privatedns.RecordSet{
 					RecordSetProperties: &privatedns.RecordSetProperties{
 						TTL: to.Int64Ptr(300),
@@ -130,6 +164,108 @@ func TestReconcilePrivateDNS(t *testing.T) {
 				})
 			},
 		},
+		{
+			name:          "skip updates of up to date private dns resources",
+			expectedError: "",
+			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
+					ZoneName:          "my-dns-zone",
+					VNetName:          "my-vnet",
+					VNetResourceGroup: "vnet-rg",
+					LinkName:          "my-link",
+					Records: []infrav1.AddressRecord{
+						{
+							Hostname: "hostname-1",
+							IP:       "10.0.0.8",
+						},
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.SubscriptionID().Return("123")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{"foo": "bar"})
+				m.GetZone(gomockinternal.AContext(), "my-rg", "my-dns-zone").Return(privatedns.PrivateZone{
+					ID:       to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/privateDnsZones/my-dns-zone"),
+					Location: to.StringPtr(azure.Global),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-dns-zone"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"foo": to.StringPtr("bar"),
+					},
+				}, nil)
+				m.GetLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link").Return(privatedns.VirtualNetworkLink{
+					VirtualNetworkLinkProperties: &privatedns.VirtualNetworkLinkProperties{
+						VirtualNetwork: &privatedns.SubResource{
+							ID: to.StringPtr("/subscriptions/123/resourcegroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"),
+						},
+						RegistrationEnabled: to.BoolPtr(false),
+					},
+					Location: to.StringPtr(azure.Global),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-link"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"foo": to.StringPtr("bar"),
+					},
+				}, nil)
+				m.GetRecordSet(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.A, "hostname-1").Return(privatedns.RecordSet{
+					RecordSetProperties: &privatedns.RecordSetProperties{
+						TTL: to.Int64Ptr(300),
+						ARecords: &[]privatedns.ARecord{
+							{
+								Ipv4Address: to.StringPtr("10.0.0.8"),
+							},
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "shared zone and link are not updated",
+			expectedError: "",
+			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
+					ZoneName:          "my-dns-zone",
+					VNetName:          "my-vnet",
+					VNetResourceGroup: "vnet-rg",
+					LinkName:          "my-link",
+					Records: []infrav1.AddressRecord{
+						{
+							Hostname: "hostname-1",
+							IP:       "10.0.0.8",
+						},
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.SubscriptionID().Return("123")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{"foo": "bar"})
+				m.GetZone(gomockinternal.AContext(), "my-rg", "my-dns-zone").Return(privatedns.PrivateZone{
+					Location: to.StringPtr(azure.Global),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("shared"),
+					},
+				}, nil)
+				m.GetLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link").Return(privatedns.VirtualNetworkLink{
+					Location: to.StringPtr(azure.Global),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("shared"),
+					},
+				}, nil)
+				m.GetRecordSet(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.A, "hostname-1").Return(privatedns.RecordSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateRecordSet(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.A, "hostname-1", privatedns.RecordSet{
+					RecordSetProperties: &privatedns.RecordSetProperties{
+						TTL: to.Int64Ptr(300),
+						ARecords: &[]privatedns.ARecord{
+							{
+								Ipv4Address: to.StringPtr("10.0.0.8"),
+							},
+						},
+					},
+				})
+			},
+		},
 		{
 			name:          "link creation fails",
 			expectedError: "failed to create virtual network link my-link: #: Internal Server Error: StatusCode=500",
@@ -147,9 +283,20 @@ func TestReconcilePrivateDNS(t *testing.T) {
 						},
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.SubscriptionID().Return("123")
-				m.CreateOrUpdateZone(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.PrivateZone{Location: to.StringPtr(azure.Global)})
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{"foo": "bar"})
+				m.GetZone(gomockinternal.AContext(), "my-rg", "my-dns-zone").Return(privatedns.PrivateZone{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateZone(gomockinternal.AContext(), "my-rg", "my-dns-zone", privatedns.PrivateZone{
+					Location: to.StringPtr(azure.Global),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-dns-zone"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"foo": to.StringPtr("bar"),
+					},
+				})
+				m.GetLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link").Return(privatedns.VirtualNetworkLink{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdateLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link", privatedns.VirtualNetworkLink{
 					VirtualNetworkLinkProperties: &privatedns.VirtualNetworkLinkProperties{
 						VirtualNetwork: &privatedns.SubResource{
@@ -158,6 +305,11 @@ func TestReconcilePrivateDNS(t *testing.T) {
 						RegistrationEnabled: to.BoolPtr(false),
 					},
 					Location: to.StringPtr(azure.Global),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-link"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"foo": to.StringPtr("bar"),
+					},
 				}).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
@@ -223,8 +375,11 @@ func TestDeletePrivateDNS(t *testing.T) {
 						},
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link").Return(privatedns.VirtualNetworkLink{Name: to.StringPtr("my-link")}, nil)
 				m.DeleteLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link")
+				m.GetZone(gomockinternal.AContext(), "my-rg", "my-dns-zone").Return(privatedns.PrivateZone{Name: to.StringPtr("my-dns-zone")}, nil)
 				m.DeleteZone(gomockinternal.AContext(), "my-rg", "my-dns-zone")
 			},
 		},
@@ -245,9 +400,11 @@ func TestDeletePrivateDNS(t *testing.T) {
 						},
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				m.DeleteLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link").
-					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link").
+					Return(privatedns.VirtualNetworkLink{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.GetZone(gomockinternal.AContext(), "my-rg", "my-dns-zone").Return(privatedns.PrivateZone{Name: to.StringPtr("my-dns-zone")}, nil)
 				m.DeleteZone(gomockinternal.AContext(), "my-rg", "my-dns-zone")
 			},
 		},
@@ -268,11 +425,45 @@ func TestDeletePrivateDNS(t *testing.T) {
 						},
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				m.DeleteLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link").
-					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				m.DeleteZone(gomockinternal.AContext(), "my-rg", "my-dns-zone").
-					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link").
+					Return(privatedns.VirtualNetworkLink{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.GetZone(gomockinternal.AContext(), "my-rg", "my-dns-zone").
+					Return(privatedns.PrivateZone{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "shared link and zone are not deleted",
+			expectedError: "",
+			expect: func(s *mock_privatedns.MockScopeMockRecorder, m *mock_privatedns.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
+					ZoneName:          "my-dns-zone",
+					VNetName:          "my-vnet",
+					VNetResourceGroup: "vnet-rg",
+					LinkName:          "my-link",
+					Records: []infrav1.AddressRecord{
+						{
+							Hostname: "hostname-1",
+							IP:       "10.0.0.8",
+						},
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link").Return(privatedns.VirtualNetworkLink{
+					Name: to.StringPtr("my-link"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("shared"),
+					},
+				}, nil)
+				m.GetZone(gomockinternal.AContext(), "my-rg", "my-dns-zone").Return(privatedns.PrivateZone{
+					Name: to.StringPtr("my-dns-zone"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("shared"),
+					},
+				}, nil)
 			},
 		},
 		{
@@ -292,7 +483,9 @@ func TestDeletePrivateDNS(t *testing.T) {
 						},
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link").Return(privatedns.VirtualNetworkLink{Name: to.StringPtr("my-link")}, nil)
 				m.DeleteLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link").
 					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
@@ -314,8 +507,11 @@ func TestDeletePrivateDNS(t *testing.T) {
 						},
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link").Return(privatedns.VirtualNetworkLink{Name: to.StringPtr("my-link")}, nil)
 				m.DeleteLink(gomockinternal.AContext(), "my-rg", "my-dns-zone", "my-link")
+				m.GetZone(gomockinternal.AContext(), "my-rg", "my-dns-zone").Return(privatedns.PrivateZone{Name: to.StringPtr("my-dns-zone")}, nil)
 				m.DeleteZone(gomockinternal.AContext(), "my-rg", "my-dns-zone").
 					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
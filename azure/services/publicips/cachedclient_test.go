@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publicips
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips/mock_publicips"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+)
+
+func TestCachedClientGet(t *testing.T) {
+	ip := network.PublicIPAddress{ID: to.StringPtr("subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-publicip")}
+
+	testcases := []struct {
+		name   string
+		calls  func(g *WithT, cc *CachedClient)
+		expect func(m *mock_publicips.MockClientMockRecorder)
+	}{
+		{
+			name: "public IP is read from Azure once and then served from the cache",
+			calls: func(g *WithT, cc *CachedClient) {
+				for i := 0; i < 3; i++ {
+					got, err := cc.Get(context.TODO(), "my-rg", "my-publicip")
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(got).To(Equal(ip))
+				}
+			},
+			expect: func(m *mock_publicips.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Times(1).Return(ip, nil)
+			},
+		},
+		{
+			name: "errors are not cached",
+			calls: func(g *WithT, cc *CachedClient) {
+				_, err := cc.Get(context.TODO(), "my-rg", "my-publicip")
+				g.Expect(err).To(HaveOccurred())
+				got, err := cc.Get(context.TODO(), "my-rg", "my-publicip")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(got).To(Equal(ip))
+			},
+			expect: func(m *mock_publicips.MockClientMockRecorder) {
+				gomock.InOrder(
+					m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")),
+					m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(ip, nil),
+				)
+			},
+		},
+		{
+			name: "deleting a public IP evicts it from the cache",
+			calls: func(g *WithT, cc *CachedClient) {
+				_, err := cc.Get(context.TODO(), "my-rg", "my-publicip")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(cc.Delete(context.TODO(), "my-rg", "my-publicip")).To(Succeed())
+				_, err = cc.Get(context.TODO(), "my-rg", "my-publicip")
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expect: func(m *mock_publicips.MockClientMockRecorder) {
+				gomock.InOrder(
+					m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(ip, nil),
+					m.Delete(gomockinternal.AContext(), "my-rg", "my-publicip").Return(nil),
+					m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(ip, nil),
+				)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			clientMock := mock_publicips.NewMockClient(mockCtrl)
+
+			tc.expect(clientMock.EXPECT())
+
+			cache, err := ttllru.New(16, time.Minute)
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.calls(g, newCachedClient(clientMock, cache, "hash"))
+		})
+	}
+}
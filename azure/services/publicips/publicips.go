@@ -28,6 +28,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/quotas"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -42,13 +43,15 @@ type PublicIPScope interface {
 type Service struct {
 	Scope PublicIPScope
 	Client
+	quotasSvc *quotas.Service
 }
 
 // New creates a new service.
 func New(scope PublicIPScope) *Service {
 	return &Service{
-		Scope:  scope,
-		Client: NewClient(scope),
+		Scope:     scope,
+		Client:    NewClient(scope),
+		quotasSvc: quotas.New(scope),
 	}
 }
 
@@ -58,8 +61,6 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	defer span.End()
 
 	for _, ip := range s.Scope.PublicIPSpecs() {
-		s.Scope.V(2).Info("creating public IP", "public ip", ip.Name)
-
 		// only set DNS properties if there is a DNS name specified
 		addressVersion := network.IPVersionIPv4
 		if ip.IsIPv6 {
@@ -75,28 +76,65 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			}
 		}
 
-		err := s.Client.CreateOrUpdate(
-			ctx,
-			s.Scope.ResourceGroup(),
-			ip.Name,
-			network.PublicIPAddress{
-				Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
-					ClusterName: s.Scope.ClusterName(),
-					Lifecycle:   infrav1.ResourceLifecycleOwned,
-					Name:        to.StringPtr(ip.Name),
-					Additional:  s.Scope.AdditionalTags(),
-				})),
-				Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
-				Name:     to.StringPtr(ip.Name),
-				Location: to.StringPtr(s.Scope.Location()),
-				PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
-					PublicIPAddressVersion:   addressVersion,
-					PublicIPAllocationMethod: network.IPAllocationMethodStatic,
-					DNSSettings:              dnsSettings,
-				},
+		// The zones of a public IP can't be changed after creation, so existing IPs keep their zones.
+		zones := ip.Zones
+		existingIP, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), ip.Name)
+		switch {
+		case err != nil && !azure.ResourceNotFound(err):
+			return errors.Wrapf(err, "failed to get public IP %s", ip.Name)
+		case err == nil && converters.MapToTags(existingIP.Tags).HasShared(s.Scope.ClusterName()):
+			// shared public IPs are only read by the cluster
+			s.Scope.V(4).Info("public IP is shared, skipping update", "public ip", ip.Name)
+			continue
+		case err == nil:
+			zones = to.StringSlice(existingIP.Zones)
+		}
+
+		var ipZones *[]string
+		if len(zones) > 0 {
+			ipZones = &zones
+		}
+
+		publicIP := network.PublicIPAddress{
+			Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+				ClusterName: s.Scope.ClusterName(),
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+				Name:        to.StringPtr(ip.Name),
+				Additional:  s.Scope.AdditionalTags(),
+			})),
+			Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
+			Name:     to.StringPtr(ip.Name),
+			Location: to.StringPtr(s.Scope.Location()),
+			Zones:    ipZones,
+			PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+				PublicIPAddressVersion:   addressVersion,
+				PublicIPAllocationMethod: network.IPAllocationMethodStatic,
+				DNSSettings:              dnsSettings,
 			},
-		)
+		}
 
+		if err == nil {
+			upToDate, err := azure.IsUpToDate(publicIP, existingIP)
+			if err != nil {
+				return errors.Wrapf(err, "failed to compare public IP %s", ip.Name)
+			}
+			if upToDate {
+				s.Scope.V(4).Info("public IP is up to date, skipping update", "public ip", ip.Name)
+				continue
+			}
+		} else if s.quotasSvc != nil {
+			if err := s.quotasSvc.CheckPublicIPs(ctx, 1); err != nil {
+				return errors.Wrapf(err, "failed to create public IP %s", ip.Name)
+			}
+		}
+
+		s.Scope.V(2).Info("creating public IP", "public ip", ip.Name)
+		err = s.Client.CreateOrUpdate(ctx, s.Scope.NetworkResourceGroup(), ip.Name, publicIP)
+		azure.RecordCreate(ctx, "public IP", ip.Name, err)
+		if err != nil && azure.DNSRecordInUse(err) {
+			// Retrying won't help if the DNS label is already taken by another public IP in the region.
+			return azure.WithTerminalError(errors.Wrapf(err, "DNS name %s of public IP %s is already in use", ip.DNSName, ip.Name))
+		}
 		if err != nil {
 			return errors.Wrap(err, "cannot create public IP")
 		}
@@ -124,13 +162,14 @@ func (s *Service) Delete(ctx context.Context) error {
 		}
 
 		s.Scope.V(2).Info("deleting public IP", "public ip", ip.Name)
-		err = s.Client.Delete(ctx, s.Scope.ResourceGroup(), ip.Name)
+		err = s.Client.Delete(ctx, s.Scope.NetworkResourceGroup(), ip.Name)
+		azure.RecordDelete(ctx, "public IP", ip.Name, err)
 		if err != nil && azure.ResourceNotFound(err) {
 			// already deleted
 			continue
 		}
 		if err != nil {
-			return errors.Wrapf(err, "failed to delete public IP %s in resource group %s", ip.Name, s.Scope.ResourceGroup())
+			return errors.Wrapf(err, "failed to delete public IP %s in resource group %s", ip.Name, s.Scope.NetworkResourceGroup())
 		}
 
 		s.Scope.V(2).Info("deleted public IP", "public ip", ip.Name)
@@ -141,7 +180,7 @@ func (s *Service) Delete(ctx context.Context) error {
 // isIPManaged returns true if the IP has an owned tag with the cluster name as value,
 // meaning that the IP's lifecycle is managed.
 func (s *Service) isIPManaged(ctx context.Context, ipName string) (bool, error) {
-	ip, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), ipName)
+	ip, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), ipName)
 	if err != nil {
 		return false, err
 	}
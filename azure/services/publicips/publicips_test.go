@@ -18,6 +18,7 @@ package publicips
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"testing"
 
@@ -31,6 +32,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips/mock_publicips"
 
 	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 
@@ -73,10 +75,11 @@ func TestReconcilePublicIP(t *testing.T) {
 						DNSName: "fakename.mydomain.io",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.ClusterName().AnyTimes().Return("my-cluster")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
 				s.Location().AnyTimes().Return("testlocation")
+				m.Get(gomockinternal.AContext(), "my-rg", gomock.Any()).AnyTimes().Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				gomock.InOrder(
 					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip", gomockinternal.DiffEq(network.PublicIPAddress{
 						Name:     to.StringPtr("my-publicip"),
@@ -156,13 +159,164 @@ func TestReconcilePublicIP(t *testing.T) {
 						DNSName: "fakedns.mydomain.io",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.ClusterName().AnyTimes().Return("my-cluster")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
 				s.Location().AnyTimes().Return("testlocation")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip", gomock.AssignableToTypeOf(network.PublicIPAddress{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
+		{
+			name:          "creates zonal and zone-redundant public IPs",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:  "my-zonal-publicip",
+						Zones: []string{"2"},
+					},
+					{
+						Name:  "my-zone-redundant-publicip",
+						Zones: []string{"1", "2", "3"},
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Location().AnyTimes().Return("testlocation")
+				m.Get(gomockinternal.AContext(), "my-rg", gomock.Any()).AnyTimes().Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				gomock.InOrder(
+					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-zonal-publicip", gomockinternal.DiffEq(network.PublicIPAddress{
+						Name:     to.StringPtr("my-zonal-publicip"),
+						Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
+						Location: to.StringPtr("testlocation"),
+						Zones:    &[]string{"2"},
+						Tags: map[string]*string{
+							"Name": to.StringPtr("my-zonal-publicip"),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						},
+						PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+							PublicIPAddressVersion:   network.IPVersionIPv4,
+							PublicIPAllocationMethod: network.IPAllocationMethodStatic,
+						},
+					})).Times(1),
+					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-zone-redundant-publicip", gomockinternal.DiffEq(network.PublicIPAddress{
+						Name:     to.StringPtr("my-zone-redundant-publicip"),
+						Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
+						Location: to.StringPtr("testlocation"),
+						Zones:    &[]string{"1", "2", "3"},
+						Tags: map[string]*string{
+							"Name": to.StringPtr("my-zone-redundant-publicip"),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						},
+						PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+							PublicIPAddressVersion:   network.IPVersionIPv4,
+							PublicIPAllocationMethod: network.IPAllocationMethodStatic,
+						},
+					})).Times(1),
+				)
+			},
+		},
+		{
+			name:          "keeps the zones of an existing public IP",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:  "my-publicip",
+						Zones: []string{"1", "2", "3"},
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Location().AnyTimes().Return("testlocation")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{
+					Name: to.StringPtr("my-publicip"),
+				}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip", gomockinternal.DiffEq(network.PublicIPAddress{
+					Name:     to.StringPtr("my-publicip"),
+					Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
+					Location: to.StringPtr("testlocation"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-publicip"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+					},
+					PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+						PublicIPAddressVersion:   network.IPVersionIPv4,
+						PublicIPAllocationMethod: network.IPAllocationMethodStatic,
+					},
+				})).Times(1)
+			},
+		},
+		{
+			name:          "skips the update of an up to date public IP",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name: "my-publicip",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Location().AnyTimes().Return("testlocation")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{
+					ID:       to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-publicip"),
+					Name:     to.StringPtr("my-publicip"),
+					Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
+					Location: to.StringPtr("testlocation"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-publicip"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+					},
+					PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+						PublicIPAddressVersion:   network.IPVersionIPv4,
+						PublicIPAllocationMethod: network.IPAllocationMethodStatic,
+						IPAddress:                to.StringPtr("1.2.3.4"),
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "skips the update of a shared public IP",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name: "my-publicip",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{
+					Name: to.StringPtr("my-publicip"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("shared"),
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "fail to get a public IP",
+			expectedError: "failed to get public IP my-publicip: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name: "my-publicip",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -194,6 +348,44 @@ func TestReconcilePublicIP(t *testing.T) {
 	}
 }
 
+func TestReconcilePublicIPDNSConflict(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_publicips.NewMockPublicIPScope(mockCtrl)
+	clientMock := mock_publicips.NewMockClient(mockCtrl)
+
+	scopeMock.EXPECT().V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+	scopeMock.EXPECT().PublicIPSpecs().Return([]azure.PublicIPSpec{
+		{
+			Name:    "my-publicip",
+			DNSName: "fakedns.mydomain.io",
+		},
+	})
+	scopeMock.EXPECT().NetworkResourceGroup().AnyTimes().Return("my-rg")
+	scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+	scopeMock.EXPECT().AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+	scopeMock.EXPECT().Location().AnyTimes().Return("testlocation")
+	clientMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+	clientMock.EXPECT().CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip", gomock.AssignableToTypeOf(network.PublicIPAddress{})).Return(autorest.DetailedError{
+		StatusCode: 400,
+		Original:   &azureautorest.ServiceError{Code: "DnsRecordInUse", Message: "DNS record fakedns.mydomain.io is already used by another public IP."},
+	})
+
+	s := &Service{
+		Scope:  scopeMock,
+		Client: clientMock,
+	}
+
+	err := s.Reconcile(context.TODO())
+	g.Expect(err).To(HaveOccurred())
+	var reconcileError azure.ReconcileError
+	g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+	g.Expect(reconcileError.IsTerminal()).To(BeTrue())
+	g.Expect(azure.DNSRecordInUse(err)).To(BeTrue())
+}
+
 func TestDeletePublicIP(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -213,7 +405,7 @@ func TestDeletePublicIP(t *testing.T) {
 						Name: "my-publicip-2",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.ClusterName().AnyTimes().Return("my-cluster")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{
 					Name: to.StringPtr("my-publicip"),
@@ -246,7 +438,7 @@ func TestDeletePublicIP(t *testing.T) {
 						Name: "my-publicip-2",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.ClusterName().AnyTimes().Return("my-cluster")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip-2").Return(network.PublicIPAddress{
@@ -269,7 +461,7 @@ func TestDeletePublicIP(t *testing.T) {
 						Name: "my-publicip",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.ClusterName().AnyTimes().Return("my-cluster")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{
 					Name: to.StringPtr("my-publicip"),
@@ -295,7 +487,7 @@ func TestDeletePublicIP(t *testing.T) {
 						Name: "my-publicip-2",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.ClusterName().AnyTimes().Return("my-cluster")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{
 					Name: to.StringPtr("my-public-ip"),
@@ -313,6 +505,26 @@ func TestDeletePublicIP(t *testing.T) {
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-publicip-2")
 			},
 		},
+		{
+			name:          "skip shared public ip deletion",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name: "my-publicip",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{
+					Name: to.StringPtr("my-publicip"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("shared"),
+					},
+				}, nil)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotas
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-30/compute"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client wraps go-sdk.
+type Client interface {
+	ListComputeUsages(context.Context, string) ([]compute.Usage, error)
+	ListNetworkUsages(context.Context, string) ([]network.Usage, error)
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	computeUsages compute.UsageClient
+	networkUsages network.UsagesClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new usages client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		computeUsages: newComputeUsageClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		networkUsages: newNetworkUsagesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newComputeUsageClient creates a new compute usage client from subscription ID.
+func newComputeUsageClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.UsageClient {
+	c := compute.NewUsageClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	return c
+}
+
+// newNetworkUsagesClient creates a new network usages client from subscription ID.
+func newNetworkUsagesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.UsagesClient {
+	c := network.NewUsagesClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	return c
+}
+
+// ListComputeUsages returns the current usages and limits of the compute resources of the subscription in a location.
+func (ac *AzureClient) ListComputeUsages(ctx context.Context, location string) ([]compute.Usage, error) {
+	ctx, span := tele.Tracer().Start(ctx, "quotas.AzureClient.ListComputeUsages")
+	defer span.End()
+
+	iter, err := ac.computeUsages.ListComplete(ctx, location)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list compute usages")
+	}
+
+	var usages []compute.Usage
+	for iter.NotDone() {
+		usages = append(usages, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return usages, errors.Wrap(err, "could not iterate compute usages")
+		}
+	}
+
+	return usages, nil
+}
+
+// ListNetworkUsages returns the current usages and limits of the network resources of the subscription in a location.
+func (ac *AzureClient) ListNetworkUsages(ctx context.Context, location string) ([]network.Usage, error) {
+	ctx, span := tele.Tracer().Start(ctx, "quotas.AzureClient.ListNetworkUsages")
+	defer span.End()
+
+	iter, err := ac.networkUsages.ListComplete(ctx, location)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list network usages")
+	}
+
+	var usages []network.Usage
+	for iter.NotDone() {
+		usages = append(usages, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return usages, errors.Wrap(err, "could not iterate network usages")
+		}
+	}
+
+	return usages, nil
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotas
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/go-logr/logr"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// regionalCoresUsage is the name of the usage of the total regional vCPUs of the subscription.
+	regionalCoresUsage = "cores"
+	// spotCoresUsage is the name of the usage of the Spot vCPUs of the subscription, which Spot VMs count against
+	// instead of the regional and VM family vCPUs.
+	spotCoresUsage = "lowPriorityCores"
+	// publicIPAddressesUsage is the name of the usage of the public IP addresses of the subscription.
+	publicIPAddressesUsage = "PublicIPAddresses"
+	// standardPublicIPAddressesUsage is the name of the usage of the Standard SKU public IP addresses of the subscription.
+	standardPublicIPAddressesUsage = "StandardSkuPublicIpAddresses"
+)
+
+// QuotaScope defines the scope interface for a quotas service.
+type QuotaScope interface {
+	logr.Logger
+	azure.Authorizer
+	Location() string
+}
+
+// Service checks the resources about to be created against the usages and limits of the subscription, so that
+// exceeded quotas are reported before Azure fails the creation of the resources.
+type Service struct {
+	Scope QuotaScope
+	Client
+}
+
+// New creates a new service.
+func New(scope QuotaScope) *Service {
+	return &Service{
+		Scope:  scope,
+		Client: NewClient(scope),
+	}
+}
+
+// quotaRequest is an amount of a usage about to be requested.
+type quotaRequest struct {
+	name   string
+	amount int64
+}
+
+// usage is the current value and limit of a usage of the subscription.
+type usage struct {
+	localizedName string
+	current       int64
+	limit         int64
+}
+
+// CheckVirtualMachines returns a QuotaExceededError if creating count VMs of the SKU would exceed the vCPU quotas of
+// the subscription in the location. Spot VMs only count against the Spot vCPUs quota.
+func (s *Service) CheckVirtualMachines(ctx context.Context, sku resourceskus.SKU, count int64, spot bool) error {
+	ctx, span := tele.Tracer().Start(ctx, "quotas.Service.CheckVirtualMachines")
+	defer span.End()
+
+	if count <= 0 {
+		return nil
+	}
+	vCPUs, ok := sku.GetCapability(resourceskus.VCPUs)
+	if !ok {
+		return nil
+	}
+	cores, err := strconv.ParseInt(vCPUs, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	var requests []quotaRequest
+	if spot {
+		requests = append(requests, quotaRequest{name: spotCoresUsage, amount: cores * count})
+	} else {
+		requests = append(requests, quotaRequest{name: regionalCoresUsage, amount: cores * count})
+		if family := to.String(sku.Family); family != "" {
+			requests = append(requests, quotaRequest{name: family, amount: cores * count})
+		}
+	}
+
+	computeUsages, err := s.Client.ListComputeUsages(ctx, s.Scope.Location())
+	if err != nil {
+		// The quotas are only checked on a best-effort basis, Azure enforces them anyway.
+		s.Scope.V(2).Info("skipping vCPU quota check", "reason", err.Error())
+		return nil
+	}
+	usages := make(map[string]usage, len(computeUsages))
+	for _, u := range computeUsages {
+		if u.Name == nil || u.CurrentValue == nil || u.Limit == nil {
+			continue
+		}
+		usages[strings.ToLower(to.String(u.Name.Value))] = usage{
+			localizedName: to.String(u.Name.LocalizedValue),
+			current:       int64(*u.CurrentValue),
+			limit:         *u.Limit,
+		}
+	}
+
+	return s.check(usages, requests)
+}
+
+// CheckPublicIPs returns a QuotaExceededError if creating count Standard SKU public IPs would exceed the public IP
+// quotas of the subscription in the location.
+func (s *Service) CheckPublicIPs(ctx context.Context, count int64) error {
+	ctx, span := tele.Tracer().Start(ctx, "quotas.Service.CheckPublicIPs")
+	defer span.End()
+
+	if count <= 0 {
+		return nil
+	}
+
+	networkUsages, err := s.Client.ListNetworkUsages(ctx, s.Scope.Location())
+	if err != nil {
+		// The quotas are only checked on a best-effort basis, Azure enforces them anyway.
+		s.Scope.V(2).Info("skipping public IP quota check", "reason", err.Error())
+		return nil
+	}
+	usages := make(map[string]usage, len(networkUsages))
+	for _, u := range networkUsages {
+		if u.Name == nil || u.CurrentValue == nil || u.Limit == nil {
+			continue
+		}
+		usages[strings.ToLower(to.String(u.Name.Value))] = usage{
+			localizedName: to.String(u.Name.LocalizedValue),
+			current:       *u.CurrentValue,
+			limit:         *u.Limit,
+		}
+	}
+
+	return s.check(usages, []quotaRequest{
+		{name: publicIPAddressesUsage, amount: count},
+		{name: standardPublicIPAddressesUsage, amount: count},
+	})
+}
+
+// check returns a QuotaExceededError for the first request which would exceed the limit of its usage. Requests for
+// usages which are not reported in the location are not checked.
+func (s *Service) check(usages map[string]usage, requests []quotaRequest) error {
+	for _, request := range requests {
+		u, ok := usages[strings.ToLower(request.name)]
+		if !ok {
+			continue
+		}
+		if u.current+request.amount > u.limit {
+			quota := u.localizedName
+			if quota == "" {
+				quota = request.name
+			}
+			return azure.QuotaExceededError{
+				Quota:     quota,
+				Location:  s.Scope.Location(),
+				Limit:     u.limit,
+				Usage:     u.current,
+				Requested: request.amount,
+			}
+		}
+	}
+	return nil
+}
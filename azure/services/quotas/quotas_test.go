@@ -0,0 +1,190 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotas
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-30/compute"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/v2/klogr"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/quotas/mock_quotas"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+func computeUsage(name, localizedName string, current int32, limit int64) compute.Usage {
+	return compute.Usage{
+		Name:         &compute.UsageName{Value: to.StringPtr(name), LocalizedValue: to.StringPtr(localizedName)},
+		CurrentValue: to.Int32Ptr(current),
+		Limit:        to.Int64Ptr(limit),
+	}
+}
+
+func networkUsage(name, localizedName string, current int64, limit int64) network.Usage {
+	return network.Usage{
+		Name:         &network.UsageName{Value: to.StringPtr(name), LocalizedValue: to.StringPtr(localizedName)},
+		CurrentValue: to.Int64Ptr(current),
+		Limit:        to.Int64Ptr(limit),
+	}
+}
+
+func TestCheckVirtualMachines(t *testing.T) {
+	sku := resourceskus.SKU{
+		Name:   to.StringPtr("Standard_D4s_v3"),
+		Family: to.StringPtr("standardDSv3Family"),
+		Capabilities: &[]compute.ResourceSkuCapabilities{
+			{Name: to.StringPtr(resourceskus.VCPUs), Value: to.StringPtr("4")},
+		},
+	}
+	usages := []compute.Usage{
+		computeUsage("cores", "Total Regional vCPUs", 80, 100),
+		computeUsage("standardDSv3Family", "Standard DSv3 Family vCPUs", 40, 48),
+		computeUsage("lowPriorityCores", "Total Regional Low-priority vCPUs", 0, 10),
+	}
+
+	testcases := []struct {
+		name          string
+		count         int64
+		spot          bool
+		listErr       error
+		expectedError string
+	}{
+		{
+			name:  "within the quotas",
+			count: 2,
+		},
+		{
+			name:          "family quota exceeded",
+			count:         3,
+			expectedError: "Standard DSv3 Family vCPUs quota exceeded in location eastus: 12 requested, 40 of 48 already used",
+		},
+		{
+			name:          "regional quota exceeded",
+			count:         6,
+			expectedError: "Total Regional vCPUs quota exceeded in location eastus: 24 requested, 80 of 100 already used",
+		},
+		{
+			name:          "spot VMs only count against the spot quota",
+			count:         3,
+			spot:          true,
+			expectedError: "Total Regional Low-priority vCPUs quota exceeded in location eastus: 12 requested, 0 of 10 already used",
+		},
+		{
+			name:    "usages can't be listed",
+			count:   100,
+			listErr: errors.New("boom"),
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_quotas.NewMockQuotaScope(mockCtrl)
+			clientMock := mock_quotas.NewMockClient(mockCtrl)
+
+			scopeMock.EXPECT().V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			scopeMock.EXPECT().Location().AnyTimes().Return("eastus")
+			clientMock.EXPECT().ListComputeUsages(gomockinternal.AContext(), "eastus").Return(usages, tc.listErr)
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.CheckVirtualMachines(context.TODO(), sku, tc.count, tc.spot)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(azure.IsQuotaExceededError(err)).To(BeTrue())
+				g.Expect(err.Error()).To(Equal(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestCheckPublicIPs(t *testing.T) {
+	testcases := []struct {
+		name          string
+		count         int64
+		usages        []network.Usage
+		expectedError string
+	}{
+		{
+			name:  "within the quotas",
+			count: 1,
+			usages: []network.Usage{
+				networkUsage("PublicIPAddresses", "Public IP Addresses", 9, 10),
+				networkUsage("StandardSkuPublicIpAddresses", "Standard Sku Public IP Addresses", 5, 10),
+			},
+		},
+		{
+			name:  "standard public IPs quota exceeded",
+			count: 2,
+			usages: []network.Usage{
+				networkUsage("PublicIPAddresses", "Public IP Addresses", 5, 20),
+				networkUsage("StandardSkuPublicIpAddresses", "Standard Sku Public IP Addresses", 9, 10),
+			},
+			expectedError: "Standard Sku Public IP Addresses quota exceeded in location eastus: 2 requested, 9 of 10 already used",
+		},
+		{
+			name:   "quotas not reported",
+			count:  1,
+			usages: []network.Usage{},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_quotas.NewMockQuotaScope(mockCtrl)
+			clientMock := mock_quotas.NewMockClient(mockCtrl)
+
+			scopeMock.EXPECT().Location().AnyTimes().Return("eastus")
+			clientMock.EXPECT().ListNetworkUsages(gomockinternal.AContext(), "eastus").Return(tc.usages, nil)
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.CheckPublicIPs(context.TODO(), tc.count)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(Equal(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package regions
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2021-01-01/subscriptions"
+	"github.com/Azure/go-autorest/autorest"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	ListLocations(context.Context, string) ([]subscriptions.Location, error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	subscriptions subscriptions.Client
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new subscriptions client.
+func newClient(auth azure.Authorizer) *azureClient {
+	c := newSubscriptionsClient(auth.BaseURI(), auth.Authorizer())
+	return &azureClient{
+		subscriptions: c,
+	}
+}
+
+// newSubscriptionsClient creates a new subscriptions client.
+func newSubscriptionsClient(baseURI string, authorizer autorest.Authorizer) subscriptions.Client {
+	subscriptionsClient := subscriptions.NewClientWithBaseURI(baseURI)
+	azure.SetAutoRestClientDefaults(&subscriptionsClient.Client, authorizer)
+	return subscriptionsClient
+}
+
+// ListLocations lists the locations available to a subscription, along with their metadata.
+func (ac *azureClient) ListLocations(ctx context.Context, subscriptionID string) ([]subscriptions.Location, error) {
+	ctx, span := tele.Tracer().Start(ctx, "regions.AzureClient.ListLocations")
+	defer span.End()
+
+	result, err := ac.subscriptions.ListLocations(ctx, subscriptionID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if result.Value == nil {
+		return nil, nil
+	}
+	return *result.Value, nil
+}
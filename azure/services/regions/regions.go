@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package regions
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Scope defines the scope interface for a regions service.
+type Scope interface {
+	logr.Logger
+	azure.Authorizer
+	Location() string
+	FailureDomainNames() []string
+	Region() *infrav1.RegionStatus
+	SetRegion(infrav1.RegionStatus)
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope Scope
+	client
+}
+
+// New creates a new regions service.
+func New(scope Scope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Reconcile publishes the cluster region, its availability zones and its paired regions.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, span := tele.Tracer().Start(ctx, "regions.Service.Reconcile")
+	defer span.End()
+
+	region := infrav1.RegionStatus{
+		Name:  s.Scope.Location(),
+		Zones: s.Scope.FailureDomainNames(),
+	}
+
+	// Region pairs don't change, so they are only looked up once.
+	if current := s.Scope.Region(); current != nil && current.Name == region.Name {
+		region.PairedRegions = current.PairedRegions
+		s.Scope.SetRegion(region)
+		return nil
+	}
+
+	s.Scope.V(2).Info("looking up paired regions", "location", region.Name)
+	locations, err := s.client.ListLocations(ctx, s.Scope.SubscriptionID())
+	if err != nil {
+		return errors.Wrapf(err, "failed to list locations of subscription %s", s.Scope.SubscriptionID())
+	}
+
+	found := false
+	for _, location := range locations {
+		if !isLocation(location.Name, location.DisplayName, region.Name) {
+			continue
+		}
+		found = true
+		if location.Metadata != nil && location.Metadata.PairedRegion != nil {
+			for _, paired := range *location.Metadata.PairedRegion {
+				region.PairedRegions = append(region.PairedRegions, to.String(paired.Name))
+			}
+		}
+		break
+	}
+	if !found {
+		return errors.Errorf("location %s is not available in subscription %s", region.Name, s.Scope.SubscriptionID())
+	}
+
+	s.Scope.SetRegion(region)
+	return nil
+}
+
+// isLocation returns true if a location of the subscription, with the given name and display name, is the named
+// location. Locations can be named by their name, e.g. "westus2", or by their display name, e.g. "West US 2".
+func isLocation(name, displayName *string, location string) bool {
+	normalized := strings.ReplaceAll(location, " ", "")
+	return strings.EqualFold(to.String(name), normalized) || strings.EqualFold(strings.ReplaceAll(to.String(displayName), " ", ""), normalized)
+}
+
+// Delete is a no-op as the region status doesn't map to any Azure resource.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
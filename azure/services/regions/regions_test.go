@@ -0,0 +1,187 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package regions
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2021-01-01/subscriptions"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/v2/klogr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/regions/mock_regions"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var fakeLocations = []subscriptions.Location{
+	{
+		Name:        to.StringPtr("eastus"),
+		DisplayName: to.StringPtr("East US"),
+		Metadata: &subscriptions.LocationMetadata{
+			PairedRegion: &[]subscriptions.PairedRegion{
+				{Name: to.StringPtr("westus")},
+			},
+		},
+	},
+	{
+		Name:        to.StringPtr("westus"),
+		DisplayName: to.StringPtr("West US"),
+		Metadata: &subscriptions.LocationMetadata{
+			PairedRegion: &[]subscriptions.PairedRegion{
+				{Name: to.StringPtr("eastus")},
+			},
+		},
+	},
+	{
+		Name:        to.StringPtr("brazilsoutheast"),
+		DisplayName: to.StringPtr("Brazil Southeast"),
+		Metadata:    &subscriptions.LocationMetadata{},
+	},
+}
+
+func TestReconcileRegion(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_regions.MockScopeMockRecorder, m *mock_regions.MockclientMockRecorder)
+	}{
+		{
+			name:          "publishes the region with its zones and paired regions",
+			expectedError: "",
+			expect: func(s *mock_regions.MockScopeMockRecorder, m *mock_regions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.Location().AnyTimes().Return("eastus")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.FailureDomainNames().Return([]string{"1", "2", "3"})
+				s.Region().Return(nil)
+				m.ListLocations(gomockinternal.AContext(), "123").Return(fakeLocations, nil)
+				s.SetRegion(infrav1.RegionStatus{
+					Name:          "eastus",
+					Zones:         []string{"1", "2", "3"},
+					PairedRegions: []string{"westus"},
+				})
+			},
+		},
+		{
+			name:          "publishes a region without zones nor paired regions",
+			expectedError: "",
+			expect: func(s *mock_regions.MockScopeMockRecorder, m *mock_regions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.Location().AnyTimes().Return("brazilsoutheast")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.FailureDomainNames().Return(nil)
+				s.Region().Return(nil)
+				m.ListLocations(gomockinternal.AContext(), "123").Return(fakeLocations, nil)
+				s.SetRegion(infrav1.RegionStatus{
+					Name: "brazilsoutheast",
+				})
+			},
+		},
+		{
+			name:          "publishes a region set by its display name",
+			expectedError: "",
+			expect: func(s *mock_regions.MockScopeMockRecorder, m *mock_regions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.Location().AnyTimes().Return("West US")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.FailureDomainNames().Return(nil)
+				s.Region().Return(nil)
+				m.ListLocations(gomockinternal.AContext(), "123").Return(fakeLocations, nil)
+				s.SetRegion(infrav1.RegionStatus{
+					Name:          "West US",
+					PairedRegions: []string{"eastus"},
+				})
+			},
+		},
+		{
+			name:          "reuses the paired regions already published",
+			expectedError: "",
+			expect: func(s *mock_regions.MockScopeMockRecorder, m *mock_regions.MockclientMockRecorder) {
+				s.Location().AnyTimes().Return("eastus")
+				s.FailureDomainNames().Return([]string{"1", "2"})
+				s.Region().Return(&infrav1.RegionStatus{
+					Name:          "eastus",
+					Zones:         []string{"1", "2", "3"},
+					PairedRegions: []string{"westus"},
+				})
+				s.SetRegion(infrav1.RegionStatus{
+					Name:          "eastus",
+					Zones:         []string{"1", "2"},
+					PairedRegions: []string{"westus"},
+				})
+			},
+		},
+		{
+			name:          "location is not available in the subscription",
+			expectedError: "location northpole is not available in subscription 123",
+			expect: func(s *mock_regions.MockScopeMockRecorder, m *mock_regions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.Location().AnyTimes().Return("northpole")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.FailureDomainNames().Return(nil)
+				s.Region().Return(nil)
+				m.ListLocations(gomockinternal.AContext(), "123").Return(fakeLocations, nil)
+			},
+		},
+		{
+			name:          "fail to list locations",
+			expectedError: "failed to list locations of subscription 123: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_regions.MockScopeMockRecorder, m *mock_regions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.Location().AnyTimes().Return("eastus")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.FailureDomainNames().Return(nil)
+				s.Region().Return(nil)
+				m.ListLocations(gomockinternal.AContext(), "123").Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_regions.NewMockScope(mockCtrl)
+			clientMock := mock_regions.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
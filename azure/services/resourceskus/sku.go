@@ -68,6 +68,17 @@ const (
 	MaximumPlatformFaultDomainCount = "MaximumPlatformFaultDomainCount"
 	// UltraSSDAvailable identifies the capability for the support of UltraSSD data disks.
 	UltraSSDAvailable = "UltraSSDAvailable"
+	// PremiumIO identifies the capability for the support of premium storage disks.
+	PremiumIO = "PremiumIO"
+	// HyperVGenerations identifies the hypervisor generations of the VMs a VM size supports, e.g. "V1,V2".
+	HyperVGenerations = "HyperVGenerations"
+)
+
+const (
+	// HyperVGenerationV1 is the generation 1 of the VMs, using BIOS boot.
+	HyperVGenerationV1 = "V1"
+	// HyperVGenerationV2 is the generation 2 of the VMs, using UEFI boot.
+	HyperVGenerationV2 = "V2"
 )
 
 // HasCapability return true for a capability which can be either
@@ -118,6 +129,21 @@ func (s SKU) HasCapabilityWithCapacity(name string, value int64) (bool, error) {
 	return false, nil
 }
 
+// SupportsHyperVGeneration returns true if the VM size supports VMs of the given hypervisor generation. VM sizes which
+// don't report their generations only support generation 1 VMs.
+func (s SKU) SupportsHyperVGeneration(generation string) bool {
+	generations, ok := s.GetCapability(HyperVGenerations)
+	if !ok {
+		return generation == HyperVGenerationV1
+	}
+	for _, g := range strings.Split(generations, ",") {
+		if strings.EqualFold(strings.TrimSpace(g), generation) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetCapability gets the value assigned to the given capability.
 // Eg. MaximumPlatformFaultDomainCount -> "3" will return "3" for the capability "MaximumPlatformFaultDomainCount".
 func (s SKU) GetCapability(name string) (string, bool) {
@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceskus
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-30/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+func TestSupportsHyperVGeneration(t *testing.T) {
+	cases := map[string]struct {
+		capabilities []compute.ResourceSkuCapabilities
+		generation   string
+		want         bool
+	}{
+		"should support generation 1 without capability": {
+			generation: HyperVGenerationV1,
+			want:       true,
+		},
+		"should not support generation 2 without capability": {
+			generation: HyperVGenerationV2,
+			want:       false,
+		},
+		"should support generation 2": {
+			capabilities: []compute.ResourceSkuCapabilities{
+				{
+					Name:  to.StringPtr(HyperVGenerations),
+					Value: to.StringPtr("V1,V2"),
+				},
+			},
+			generation: HyperVGenerationV2,
+			want:       true,
+		},
+		"should not support generation 2 of a generation 1 only size": {
+			capabilities: []compute.ResourceSkuCapabilities{
+				{
+					Name:  to.StringPtr(HyperVGenerations),
+					Value: to.StringPtr("V1"),
+				},
+			},
+			generation: HyperVGenerationV2,
+			want:       false,
+		},
+		"should not support generation 1 of a generation 2 only size": {
+			capabilities: []compute.ResourceSkuCapabilities{
+				{
+					Name:  to.StringPtr(HyperVGenerations),
+					Value: to.StringPtr("V2"),
+				},
+			},
+			generation: HyperVGenerationV1,
+			want:       false,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			sku := SKU{
+				Name:         to.StringPtr("Standard_D2s_v3"),
+				Capabilities: &tc.capabilities,
+			}
+
+			if got := sku.SupportsHyperVGeneration(tc.generation); got != tc.want {
+				t.Fatalf("expected generation %s support to be %t, but was %t", tc.generation, tc.want, got)
+			}
+		})
+	}
+}
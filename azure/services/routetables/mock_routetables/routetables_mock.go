@@ -367,6 +367,34 @@ func (mr *MockRouteTableScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockRouteTableScope)(nil).Location))
 }
 
+// NetworkResourceGroup mocks base method.
+func (m *MockRouteTableScope) NetworkResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NetworkResourceGroup indicates an expected call of NetworkResourceGroup.
+func (mr *MockRouteTableScopeMockRecorder) NetworkResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkResourceGroup", reflect.TypeOf((*MockRouteTableScope)(nil).NetworkResourceGroup))
+}
+
+// NodeOutboundLB mocks base method.
+func (m *MockRouteTableScope) NodeOutboundLB() *v1alpha4.LoadBalancerSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeOutboundLB")
+	ret0, _ := ret[0].(*v1alpha4.LoadBalancerSpec)
+	return ret0
+}
+
+// NodeOutboundLB indicates an expected call of NodeOutboundLB.
+func (mr *MockRouteTableScopeMockRecorder) NodeOutboundLB() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeOutboundLB", reflect.TypeOf((*MockRouteTableScope)(nil).NodeOutboundLB))
+}
+
 // NodeSubnets mocks base method.
 func (m *MockRouteTableScope) NodeSubnets() []v1alpha4.SubnetSpec {
 	m.ctrl.T.Helper()
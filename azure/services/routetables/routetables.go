@@ -24,7 +24,9 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -62,10 +64,10 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	}
 
 	for _, routeTableSpec := range s.Scope.RouteTableSpecs() {
-		existingRouteTable, err := s.Get(ctx, s.Scope.ResourceGroup(), routeTableSpec.Name)
+		existingRouteTable, err := s.Get(ctx, s.Scope.NetworkResourceGroup(), routeTableSpec.Name)
 		if !azure.ResourceNotFound(err) {
 			if err != nil {
-				return errors.Wrapf(err, "failed to get route table %s in %s", routeTableSpec.Name, s.Scope.ResourceGroup())
+				return errors.Wrapf(err, "failed to get route table %s in %s", routeTableSpec.Name, s.Scope.NetworkResourceGroup())
 			}
 
 			// route table already exists
@@ -80,15 +82,22 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		s.Scope.V(2).Info("creating Route Table", "route table", routeTableSpec.Name)
 		err = s.client.CreateOrUpdate(
 			ctx,
-			s.Scope.ResourceGroup(),
+			s.Scope.NetworkResourceGroup(),
 			routeTableSpec.Name,
 			network.RouteTable{
-				Location:                   to.StringPtr(s.Scope.Location()),
+				Location: to.StringPtr(s.Scope.Location()),
+				Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+					ClusterName: s.Scope.ClusterName(),
+					Lifecycle:   infrav1.ResourceLifecycleOwned,
+					Name:        to.StringPtr(routeTableSpec.Name),
+					Additional:  s.Scope.AdditionalTags(),
+				})),
 				RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{},
 			},
 		)
+		azure.RecordCreate(ctx, "route table", routeTableSpec.Name, err)
 		if err != nil {
-			return errors.Wrapf(err, "failed to create route table %s in resource group %s", routeTableSpec.Name, s.Scope.ResourceGroup())
+			return errors.Wrapf(err, "failed to create route table %s in resource group %s", routeTableSpec.Name, s.Scope.NetworkResourceGroup())
 		}
 		s.Scope.V(2).Info("successfully created route table", "route table", routeTableSpec.Name)
 	}
@@ -105,14 +114,27 @@ func (s *Service) Delete(ctx context.Context) error {
 		return nil
 	}
 	for _, routeTableSpec := range s.Scope.RouteTableSpecs() {
+		existingRouteTable, err := s.client.Get(ctx, s.Scope.NetworkResourceGroup(), routeTableSpec.Name)
+		switch {
+		case err != nil && azure.ResourceNotFound(err):
+			// already deleted
+			continue
+		case err != nil:
+			return errors.Wrapf(err, "failed to get route table %s in %s", routeTableSpec.Name, s.Scope.NetworkResourceGroup())
+		case converters.MapToTags(existingRouteTable.Tags).HasShared(s.Scope.ClusterName()):
+			s.Scope.V(2).Info("skipping deletion of shared route table", "route table", routeTableSpec.Name)
+			continue
+		}
+
 		s.Scope.V(2).Info("deleting route table", "route table", routeTableSpec.Name)
-		err := s.client.Delete(ctx, s.Scope.ResourceGroup(), routeTableSpec.Name)
+		err = s.client.Delete(ctx, s.Scope.NetworkResourceGroup(), routeTableSpec.Name)
+		azure.RecordDelete(ctx, "route table", routeTableSpec.Name, err)
 		if err != nil && azure.ResourceNotFound(err) {
 			// already deleted
 			continue
 		}
 		if err != nil {
-			return errors.Wrapf(err, "failed to delete route table %s in resource group %s", routeTableSpec.Name, s.Scope.ResourceGroup())
+			return errors.Wrapf(err, "failed to delete route table %s in resource group %s", routeTableSpec.Name, s.Scope.NetworkResourceGroup())
 		}
 
 		s.Scope.V(2).Info("successfully deleted route table", "route table", routeTableSpec.Name)
@@ -77,7 +77,8 @@ func TestReconcileRouteTables(t *testing.T) {
 					Name: "my-vnet",
 				})
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ClusterName()
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{"foo": "bar"})
 				s.RouteTableSpecs().Return([]azure.RouteTableSpec{
 					{
 						Name: "my-cp-routetable",
@@ -95,10 +96,18 @@ func TestReconcileRouteTables(t *testing.T) {
 					},
 				})
 				s.ControlPlaneRouteTable().AnyTimes().Return(infrav1.RouteTable{Name: "my-cp-routetable"})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-cp-routetable").Return(network.RouteTable{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				s.Location().Return("westus")
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cp-routetable", gomock.AssignableToTypeOf(network.RouteTable{}))
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cp-routetable", gomockinternal.DiffEq(network.RouteTable{
+					Location: to.StringPtr("westus"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-cp-routetable"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						"foo": to.StringPtr("bar"),
+					},
+					RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{},
+				}))
 				m.Get(gomockinternal.AContext(), "my-rg", "my-node-routetable").Return(network.RouteTable{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				s.Location().Return("westus")
 				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-node-routetable", gomock.AssignableToTypeOf(network.RouteTable{}))
@@ -136,7 +145,7 @@ func TestReconcileRouteTables(t *testing.T) {
 				})
 				s.ControlPlaneSubnet().AnyTimes().Return(infrav1.SubnetSpec{Name: "control-plane-subnet", Role: infrav1.SubnetControlPlane})
 				s.ControlPlaneRouteTable().AnyTimes().Return(infrav1.RouteTable{Name: "my-cp-routetable"})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-cp-routetable").Return(network.RouteTable{
 					Name: to.StringPtr("my-cp-routetable"),
 					ID:   to.StringPtr("1"),
@@ -149,7 +158,7 @@ func TestReconcileRouteTables(t *testing.T) {
 						Name: "my-cp-routetable",
 					},
 				}).Times(1)
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-node-routetable").Return(network.RouteTable{
 					Name: to.StringPtr("my-node-routetable"),
 					ID:   to.StringPtr("2"),
@@ -187,7 +196,7 @@ func TestReconcileRouteTables(t *testing.T) {
 				}})
 				s.ControlPlaneSubnet().AnyTimes().Return(infrav1.SubnetSpec{})
 				s.ControlPlaneRouteTable().AnyTimes().Return(infrav1.RouteTable{Name: "my-routetable"})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-cp-routetable").Return(network.RouteTable{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 				m.CreateOrUpdate(gomockinternal.AContext(), gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(network.RouteTable{})).Times(0)
 			},
@@ -205,7 +214,8 @@ func TestReconcileRouteTables(t *testing.T) {
 					Name: "my-vnet",
 				})
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ClusterName()
+				s.ClusterName().Times(2)
+				s.AdditionalTags()
 				s.RouteTableSpecs().Return([]azure.RouteTableSpec{{
 					Name: "my-cp-routetable",
 					Subnet: infrav1.SubnetSpec{
@@ -215,7 +225,7 @@ func TestReconcileRouteTables(t *testing.T) {
 				}})
 				s.ControlPlaneSubnet().AnyTimes().Return(infrav1.SubnetSpec{})
 				s.ControlPlaneRouteTable().AnyTimes().Return(infrav1.RouteTable{Name: "my-cp-routetable"})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-cp-routetable").Return(network.RouteTable{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				s.Location().Return("westus")
 				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cp-routetable", gomock.AssignableToTypeOf(network.RouteTable{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
@@ -288,7 +298,7 @@ func TestDeleteRouteTable(t *testing.T) {
 					Name: "my-vnet",
 				})
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ClusterName()
+				s.ClusterName().AnyTimes().Return("test-cluster")
 				s.RouteTableSpecs().Return([]azure.RouteTableSpec{
 					{
 						Name: "my-cp-routetable",
@@ -306,9 +316,10 @@ func TestDeleteRouteTable(t *testing.T) {
 					},
 				})
 				s.ControlPlaneRouteTable().AnyTimes().Return(infrav1.RouteTable{Name: "my-cp-routetable"})
-				s.ResourceGroup().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cp-routetable").Return(network.RouteTable{Name: to.StringPtr("my-cp-routetable")}, nil)
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-cp-routetable")
-				s.ResourceGroup().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-node-routetable").Return(network.RouteTable{Name: to.StringPtr("my-node-routetable")}, nil)
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-node-routetable")
 			},
 		},
@@ -325,7 +336,7 @@ func TestDeleteRouteTable(t *testing.T) {
 					Name: "my-vnet",
 				})
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ClusterName()
+				s.ClusterName().AnyTimes().Return("test-cluster")
 				s.RouteTableSpecs().Return([]azure.RouteTableSpec{
 					{
 						Name: "my-cp-routetable",
@@ -343,12 +354,42 @@ func TestDeleteRouteTable(t *testing.T) {
 					},
 				})
 				s.ControlPlaneRouteTable().AnyTimes().Return(infrav1.RouteTable{Name: "my-cp-routetable"})
-				s.ResourceGroup().Return("my-rg")
-				m.Delete(gomockinternal.AContext(), "my-rg", "my-cp-routetable").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
-				s.ResourceGroup().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cp-routetable").Return(network.RouteTable{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+				m.Get(gomockinternal.AContext(), "my-rg", "my-node-routetable").Return(network.RouteTable{Name: to.StringPtr("my-node-routetable")}, nil)
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-node-routetable").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
 			},
 		},
+		{
+			name: "shared route table is not deleted",
+			tags: infrav1.Tags{
+				"Name": "my-vnet",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			},
+			expectedError: "",
+			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, m *mock_routetables.MockclientMockRecorder) {
+				s.Vnet().Return(&infrav1.VnetSpec{
+					Name: "my-vnet",
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.RouteTableSpecs().Return([]azure.RouteTableSpec{{
+					Name: "my-cp-routetable",
+					Subnet: infrav1.SubnetSpec{
+						Name: "control-plane-subnet",
+						Role: infrav1.SubnetControlPlane,
+					},
+				}})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cp-routetable").Return(network.RouteTable{
+					Name: to.StringPtr("my-cp-routetable"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("shared"),
+					},
+				}, nil)
+			},
+		},
 		{
 			name: "route table deletion fails",
 			tags: infrav1.Tags{
@@ -362,7 +403,7 @@ func TestDeleteRouteTable(t *testing.T) {
 					Name: "my-vnet",
 				})
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ClusterName()
+				s.ClusterName().AnyTimes().Return("test-cluster")
 				s.RouteTableSpecs().Return([]azure.RouteTableSpec{{
 					Name: "my-cp-routetable",
 					Subnet: infrav1.SubnetSpec{
@@ -371,7 +412,8 @@ func TestDeleteRouteTable(t *testing.T) {
 					},
 				}})
 				s.ControlPlaneRouteTable().AnyTimes().Return(infrav1.RouteTable{Name: "my-cp-routetable"})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cp-routetable").Return(network.RouteTable{Name: to.StringPtr("my-cp-routetable")}, nil)
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-cp-routetable").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
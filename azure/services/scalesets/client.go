@@ -36,8 +36,8 @@ import (
 
 // Client wraps go-sdk.
 type Client interface {
-	List(context.Context, string) ([]compute.VirtualMachineScaleSet, error)
-	ListInstances(context.Context, string, string) ([]compute.VirtualMachineScaleSetVM, error)
+	ListPages(context.Context, string, func([]compute.VirtualMachineScaleSet) error) error
+	ListInstancePages(context.Context, string, string, func([]compute.VirtualMachineScaleSetVM) error) error
 	Get(context.Context, string, string) (compute.VirtualMachineScaleSet, error)
 	CreateOrUpdate(context.Context, string, string, compute.VirtualMachineScaleSet) error
 	CreateOrUpdateAsync(context.Context, string, string, compute.VirtualMachineScaleSet) (*infrav1.Future, error)
@@ -122,46 +122,48 @@ func newPublicIPsClient(subscriptionID string, baseURI string, authorizer autore
 	return c
 }
 
-// ListInstances retrieves information about the model views of a virtual machine scale set.
-func (ac *AzureClient) ListInstances(ctx context.Context, resourceGroupName, vmssName string) ([]compute.VirtualMachineScaleSetVM, error) {
-	ctx, span := tele.Tracer().Start(ctx, "scalesets.AzureClient.ListInstances")
+// ListInstancePages calls process with each page of the model views of the instances of a virtual machine scale set.
+// Only one page is held in memory at a time, so that scale sets with many instances can be processed in bounded batches.
+func (ac *AzureClient) ListInstancePages(ctx context.Context, resourceGroupName, vmssName string, process func([]compute.VirtualMachineScaleSetVM) error) error {
+	ctx, span := tele.Tracer().Start(ctx, "scalesets.AzureClient.ListInstancePages")
 	defer span.End()
 
-	itr, err := ac.scalesetvms.ListComplete(ctx, resourceGroupName, vmssName, "", "", "")
+	page, err := ac.scalesetvms.List(ctx, resourceGroupName, vmssName, "", "", "")
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var instances []compute.VirtualMachineScaleSetVM
-	for ; itr.NotDone(); err = itr.NextWithContext(ctx) {
-		if err != nil {
-			return nil, fmt.Errorf("failed to iterate vm scale set vms [%w]", err)
+	for page.NotDone() {
+		if err := process(page.Values()); err != nil {
+			return err
+		}
+		if err := page.NextWithContext(ctx); err != nil {
+			return fmt.Errorf("failed to iterate vm scale set vms [%w]", err)
 		}
-		vm := itr.Value()
-		instances = append(instances, vm)
 	}
-	return instances, nil
+	return nil
 }
 
-// List returns all scale sets in a resource group.
-func (ac *AzureClient) List(ctx context.Context, resourceGroupName string) ([]compute.VirtualMachineScaleSet, error) {
-	ctx, span := tele.Tracer().Start(ctx, "scalesets.AzureClient.List")
+// ListPages calls process with each page of the scale sets in a resource group. Only one page is held in memory at a
+// time.
+func (ac *AzureClient) ListPages(ctx context.Context, resourceGroupName string, process func([]compute.VirtualMachineScaleSet) error) error {
+	ctx, span := tele.Tracer().Start(ctx, "scalesets.AzureClient.ListPages")
 	defer span.End()
 
-	itr, err := ac.scalesets.ListComplete(ctx, resourceGroupName)
+	page, err := ac.scalesets.List(ctx, resourceGroupName)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to list scalesets in the resource group")
+		return errors.Wrap(err, "failed to list scalesets in the resource group")
 	}
 
-	var instances []compute.VirtualMachineScaleSet
-	for ; itr.NotDone(); err = itr.NextWithContext(ctx) {
-		if err != nil {
-			return nil, fmt.Errorf("failed to iterate vm scale sets [%w]", err)
+	for page.NotDone() {
+		if err := process(page.Values()); err != nil {
+			return err
+		}
+		if err := page.NextWithContext(ctx); err != nil {
+			return fmt.Errorf("failed to iterate vm scale sets [%w]", err)
 		}
-		vm := itr.Value()
-		instances = append(instances, vm)
 	}
-	return instances, nil
+	return nil
 }
 
 // Get retrieves information about the model view of a virtual machine scale set.
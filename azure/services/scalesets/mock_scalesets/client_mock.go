@@ -157,34 +157,32 @@ func (mr *MockClientMockRecorder) GetResultIfDone(ctx, future interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResultIfDone", reflect.TypeOf((*MockClient)(nil).GetResultIfDone), ctx, future)
 }
 
-// List mocks base method.
-func (m *MockClient) List(arg0 context.Context, arg1 string) ([]compute.VirtualMachineScaleSet, error) {
+// ListInstancePages mocks base method.
+func (m *MockClient) ListInstancePages(arg0 context.Context, arg1, arg2 string, arg3 func([]compute.VirtualMachineScaleSetVM) error) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", arg0, arg1)
-	ret0, _ := ret[0].([]compute.VirtualMachineScaleSet)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "ListInstancePages", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// List indicates an expected call of List.
-func (mr *MockClientMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+// ListInstancePages indicates an expected call of ListInstancePages.
+func (mr *MockClientMockRecorder) ListInstancePages(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockClient)(nil).List), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstancePages", reflect.TypeOf((*MockClient)(nil).ListInstancePages), arg0, arg1, arg2, arg3)
 }
 
-// ListInstances mocks base method.
-func (m *MockClient) ListInstances(arg0 context.Context, arg1, arg2 string) ([]compute.VirtualMachineScaleSetVM, error) {
+// ListPages mocks base method.
+func (m *MockClient) ListPages(arg0 context.Context, arg1 string, arg2 func([]compute.VirtualMachineScaleSet) error) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListInstances", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]compute.VirtualMachineScaleSetVM)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "ListPages", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ListInstances indicates an expected call of ListInstances.
-func (mr *MockClientMockRecorder) ListInstances(arg0, arg1, arg2 interface{}) *gomock.Call {
+// ListPages indicates an expected call of ListPages.
+func (mr *MockClientMockRecorder) ListPages(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstances", reflect.TypeOf((*MockClient)(nil).ListInstances), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPages", reflect.TypeOf((*MockClient)(nil).ListPages), arg0, arg1, arg2)
 }
 
 // Update mocks base method.
@@ -315,6 +315,20 @@ func (mr *MockScaleSetScopeMockRecorder) MaxSurge() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaxSurge", reflect.TypeOf((*MockScaleSetScope)(nil).MaxSurge))
 }
 
+// NetworkResourceGroup mocks base method.
+func (m *MockScaleSetScope) NetworkResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NetworkResourceGroup indicates an expected call of NetworkResourceGroup.
+func (mr *MockScaleSetScopeMockRecorder) NetworkResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkResourceGroup", reflect.TypeOf((*MockScaleSetScope)(nil).NetworkResourceGroup))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockScaleSetScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -446,17 +460,18 @@ func (mr *MockScaleSetScopeMockRecorder) V(level interface{}) *gomock.Call {
 }
 
 // VMSSExtensionSpecs mocks base method.
-func (m *MockScaleSetScope) VMSSExtensionSpecs() []azure.VMSSExtensionSpec {
+func (m *MockScaleSetScope) VMSSExtensionSpecs(arg0 context.Context) ([]azure.VMSSExtensionSpec, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "VMSSExtensionSpecs")
+	ret := m.ctrl.Call(m, "VMSSExtensionSpecs", arg0)
 	ret0, _ := ret[0].([]azure.VMSSExtensionSpec)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // VMSSExtensionSpecs indicates an expected call of VMSSExtensionSpecs.
-func (mr *MockScaleSetScopeMockRecorder) VMSSExtensionSpecs() *gomock.Call {
+func (mr *MockScaleSetScopeMockRecorder) VMSSExtensionSpecs(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMSSExtensionSpecs", reflect.TypeOf((*MockScaleSetScope)(nil).VMSSExtensionSpecs))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMSSExtensionSpecs", reflect.TypeOf((*MockScaleSetScope)(nil).VMSSExtensionSpecs), arg0)
 }
 
 // WithName mocks base method.
@@ -33,6 +33,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/quotas"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -53,7 +54,7 @@ type (
 		SaveVMImageToStatus(*infrav1.Image)
 		MaxSurge() (int, error)
 		ScaleSetSpec() azure.ScaleSetSpec
-		VMSSExtensionSpecs() []azure.VMSSExtensionSpec
+		VMSSExtensionSpecs(context.Context) ([]azure.VMSSExtensionSpec, error)
 		SetAnnotation(string, string)
 		SetLongRunningOperationState(*infrav1.Future)
 		SetProviderID(string)
@@ -65,6 +66,7 @@ type (
 		Scope ScaleSetScope
 		Client
 		resourceSKUCache *resourceskus.Cache
+		quotasSvc        *quotas.Service
 	}
 )
 
@@ -74,6 +76,7 @@ func NewService(scope ScaleSetScope, skuCache *resourceskus.Cache) *Service {
 		Client:           NewClient(scope),
 		Scope:            scope,
 		resourceSKUCache: skuCache,
+		quotasSvc:        quotas.New(scope),
 	}
 }
 
@@ -218,11 +221,20 @@ func (s *Service) createVMSS(ctx context.Context) (*infrav1.Future, error) {
 
 	spec := s.Scope.ScaleSetSpec()
 
+	if spec.OSDisk.OSType == azure.WindowsOS {
+		// the VMSS is created with the bootstrap extension, see azure.WindowsBootstrapExtensionAnnotation
+		s.Scope.SetAnnotation(azure.WindowsBootstrapExtensionAnnotation, "true")
+	}
+
 	vmss, err := s.buildVMSSFromSpec(ctx, spec)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed building VMSS from spec")
 	}
 
+	if err := s.checkQuotas(ctx, spec, spec.Capacity); err != nil {
+		return nil, err
+	}
+
 	future, err := s.Client.CreateOrUpdateAsync(ctx, s.Scope.ResourceGroup(), spec.Name, vmss)
 	if err != nil {
 		return future, errors.Wrap(err, "cannot create VMSS")
@@ -269,6 +281,10 @@ func (s *Service) patchVMSSIfNeeded(ctx context.Context, infraVMSS *azure.VMSS)
 		return nil, nil
 	}
 
+	if err := s.checkQuotas(ctx, spec, *patch.Sku.Capacity-infraVMSS.Capacity); err != nil {
+		return nil, err
+	}
+
 	s.Scope.V(4).Info("patching vmss", "scale set", spec.Name, "patch", patch)
 	future, err := s.UpdateAsync(ctx, s.Scope.ResourceGroup(), spec.Name, patch)
 	if err != nil {
@@ -283,6 +299,23 @@ func (s *Service) patchVMSSIfNeeded(ctx context.Context, infraVMSS *azure.VMSS)
 	return future, err
 }
 
+// checkQuotas returns a QuotaExceededError if adding instances to the scale set would exceed the vCPU quotas of the
+// subscription.
+func (s *Service) checkQuotas(ctx context.Context, spec azure.ScaleSetSpec, instances int64) error {
+	if s.quotasSvc == nil || instances <= 0 {
+		return nil
+	}
+
+	sku, err := s.resourceSKUCache.Get(ctx, spec.Size, resourceskus.VirtualMachines)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get SKU %s in compute api", spec.Size)
+	}
+	if err := s.quotasSvc.CheckVirtualMachines(ctx, sku, instances, spec.SpotVMOptions != nil); err != nil {
+		return errors.Wrapf(err, "failed to add %d instances to VMSS %s", instances, spec.Name)
+	}
+	return nil
+}
+
 func hasModelModifyingDifferences(infraVMSS *azure.VMSS, vmss compute.VirtualMachineScaleSet) bool {
 	other := converters.SDKToVMSS(vmss, []compute.VirtualMachineScaleSetVM{})
 	return infraVMSS.HasModelChanges(*other)
@@ -373,7 +406,10 @@ func (s *Service) buildVMSSFromSpec(ctx context.Context, vmssSpec azure.ScaleSet
 		vmssSpec.AcceleratedNetworking = &accelNet
 	}
 
-	extensions := s.generateExtensions()
+	extensions, err := s.generateExtensions(ctx)
+	if err != nil {
+		return compute.VirtualMachineScaleSet{}, err
+	}
 
 	storageProfile, err := s.generateStorageProfile(vmssSpec, sku)
 	if err != nil {
@@ -396,7 +432,7 @@ func (s *Service) buildVMSSFromSpec(ctx context.Context, vmssSpec azure.ScaleSet
 		if vmssSpec.PublicLBAddressPoolName != "" {
 			backendAddressPools = append(backendAddressPools,
 				compute.SubResource{
-					ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), vmssSpec.PublicLBName, vmssSpec.PublicLBAddressPoolName)),
+					ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), vmssSpec.PublicLBName, vmssSpec.PublicLBAddressPoolName)),
 				})
 		}
 	}
@@ -511,12 +547,12 @@ func (s *Service) getVirtualMachineScaleSet(ctx context.Context, vmssName string
 		return nil, errors.Wrap(err, "failed to get existing vmss")
 	}
 
-	vmssInstances, err := s.Client.ListInstances(ctx, s.Scope.ResourceGroup(), vmssName)
-	if err != nil {
+	result := converters.SDKToVMSS(vmss, nil)
+	if result.Instances, err = s.listInstances(ctx, s.Scope.ResourceGroup(), vmssName); err != nil {
 		return nil, errors.Wrap(err, "failed to list instances")
 	}
 
-	return converters.SDKToVMSS(vmss, vmssInstances), nil
+	return result, nil
 }
 
 // getVirtualMachineScaleSetIfDone gets a Virtual Machine Scale Set and its instances from Azure if the future is completed.
@@ -529,29 +565,55 @@ func (s *Service) getVirtualMachineScaleSetIfDone(ctx context.Context, future *i
 		return nil, errors.Wrap(err, "failed to get result from future")
 	}
 
-	vmssInstances, err := s.Client.ListInstances(ctx, future.ResourceGroup, future.Name)
-	if err != nil {
+	result := converters.SDKToVMSS(vmss, nil)
+	if result.Instances, err = s.listInstances(ctx, future.ResourceGroup, future.Name); err != nil {
 		return nil, errors.Wrap(err, "failed to list instances")
 	}
 
-	return converters.SDKToVMSS(vmss, vmssInstances), nil
+	return result, nil
 }
 
-func (s *Service) generateExtensions() []compute.VirtualMachineScaleSetExtension {
-	extensions := make([]compute.VirtualMachineScaleSetExtension, len(s.Scope.VMSSExtensionSpecs()))
-	for i, extensionSpec := range s.Scope.VMSSExtensionSpecs() {
+// listInstances lists the instances of a Virtual Machine Scale Set page by page, converting each page before fetching
+// the next one so that the full SDK models of all instances are never held in memory at the same time.
+func (s *Service) listInstances(ctx context.Context, resourceGroup, vmssName string) ([]azure.VMSSVM, error) {
+	var instances []azure.VMSSVM
+	err := s.Client.ListInstancePages(ctx, resourceGroup, vmssName, func(page []compute.VirtualMachineScaleSetVM) error {
+		for _, instance := range page {
+			instances = append(instances, *converters.SDKToVMSSVM(instance))
+		}
+		return nil
+	})
+	return instances, err
+}
+
+func (s *Service) generateExtensions(ctx context.Context) ([]compute.VirtualMachineScaleSetExtension, error) {
+	extensionSpecs, err := s.Scope.VMSSExtensionSpecs(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get vmss extension specs")
+	}
+
+	extensions := make([]compute.VirtualMachineScaleSetExtension, len(extensionSpecs))
+	for i := range extensionSpecs {
+		extensionSpec := extensionSpecs[i]
+		properties := &compute.VirtualMachineScaleSetExtensionProperties{
+			Publisher:          to.StringPtr(extensionSpec.Publisher),
+			Type:               to.StringPtr(extensionSpec.Name),
+			TypeHandlerVersion: to.StringPtr(extensionSpec.Version),
+			Settings:           nil,
+			ProtectedSettings:  extensionSpec.ProtectedSettings,
+		}
+		if extensionSpec.Settings != nil {
+			properties.Settings = extensionSpec.Settings
+		}
+		if extensionSpec.AutoUpgradeMinorVersion {
+			properties.AutoUpgradeMinorVersion = to.BoolPtr(true)
+		}
 		extensions[i] = compute.VirtualMachineScaleSetExtension{
 			Name: &extensionSpec.Name,
-			VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
-				Publisher:          to.StringPtr(extensionSpec.Publisher),
-				Type:               to.StringPtr(extensionSpec.Name),
-				TypeHandlerVersion: to.StringPtr(extensionSpec.Version),
-				Settings:           nil,
-				ProtectedSettings:  extensionSpec.ProtectedSettings,
-			},
+			VirtualMachineScaleSetExtensionProperties: properties,
 		}
 	}
-	return extensions
+	return extensions, nil
 }
 
 // generateStorageProfile generates a pointer to a compute.VirtualMachineScaleSetStorageProfile which can utilized for VM creation.
@@ -611,6 +673,10 @@ func (s *Service) generateStorageProfile(vmssSpec azure.ScaleSetSpec, sku resour
 		return nil, errors.Wrap(err, "failed to get VM image")
 	}
 
+	if azure.IsGeneration2Image(image) && !sku.SupportsHyperVGeneration(resourceskus.HyperVGenerationV2) {
+		return nil, fmt.Errorf("vm size %s does not support generation 2 VMs required by image %s. select a different vm size", vmssSpec.Size, image.Marketplace.SKU)
+	}
+
 	s.Scope.SaveVMImageToStatus(image)
 
 	imageRef, err := converters.ImageToSDK(image)
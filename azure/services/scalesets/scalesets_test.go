@@ -113,6 +113,7 @@ func TestGetExistingVMSS(t *testing.T) {
 			expectedError: "failed to get existing vmss: #: Not found: StatusCode=404",
 			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss").Return(compute.VirtualMachineScaleSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 			},
@@ -141,6 +142,7 @@ func TestGetExistingVMSS(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss").Return(compute.VirtualMachineScaleSet{
 					ID:   to.StringPtr("my-id"),
@@ -155,7 +157,7 @@ func TestGetExistingVMSS(t *testing.T) {
 					},
 					Zones: &[]string{"1", "3"},
 				}, nil)
-				m.ListInstances(gomock.Any(), "my-rg", "my-vmss").Return([]compute.VirtualMachineScaleSetVM{
+				m.ListInstancePages(gomock.Any(), "my-rg", "my-vmss", gomock.Any()).DoAndReturn(instancePages([]compute.VirtualMachineScaleSetVM{
 					{
 						ID:         to.StringPtr("my-vm-id"),
 						InstanceID: to.StringPtr("my-vm-1"),
@@ -167,7 +169,7 @@ func TestGetExistingVMSS(t *testing.T) {
 							},
 						},
 					},
-				}, nil)
+				}))
 			},
 		},
 		{
@@ -177,6 +179,7 @@ func TestGetExistingVMSS(t *testing.T) {
 			expectedError: "failed to list instances: #: Not found: StatusCode=404",
 			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss").Return(compute.VirtualMachineScaleSet{
 					ID:   to.StringPtr("my-id"),
@@ -186,7 +189,7 @@ func TestGetExistingVMSS(t *testing.T) {
 						ProvisioningState:    to.StringPtr("Succeeded"),
 					},
 				}, nil)
-				m.ListInstances(gomockinternal.AContext(), "my-rg", "my-vmss").Return([]compute.VirtualMachineScaleSetVM{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.ListInstancePages(gomockinternal.AContext(), "my-rg", "my-vmss", gomock.Any()).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 			},
 		},
 	}
@@ -482,7 +485,7 @@ func TestReconcileVMSS(t *testing.T) {
 				existingVMSS.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
 				instances := newDefaultInstances()
 				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(existingVMSS, nil)
-				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil)
+				m.ListInstancePages(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomock.Any()).DoAndReturn(instancePages(instances))
 
 				clone := newDefaultExistingVMSS("VM_SIZE")
 				clone.Sku.Capacity = to.Int64Ptr(3)
@@ -497,7 +500,7 @@ func TestReconcileVMSS(t *testing.T) {
 				s.SetLongRunningOperationState(patchFuture)
 				m.GetResultIfDone(gomockinternal.AContext(), patchFuture).Return(compute.VirtualMachineScaleSet{}, azure.NewOperationNotDoneError(patchFuture))
 				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(clone, nil)
-				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil)
+				m.ListInstancePages(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomock.Any()).DoAndReturn(instancePages(instances))
 			},
 		},
 		{
@@ -622,6 +625,7 @@ func TestDeleteVMSS(t *testing.T) {
 					Capacity: 3,
 				}).AnyTimes()
 				s.ResourceGroup().AnyTimes().Return("my-existing-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-existing-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				future := &infrav1.Future{}
 				s.GetLongRunningOperationState().Return(future)
@@ -641,6 +645,7 @@ func TestDeleteVMSS(t *testing.T) {
 					Capacity: 3,
 				}).AnyTimes()
 				s.ResourceGroup().AnyTimes().Return(resourceGroup)
+				s.NetworkResourceGroup().AnyTimes().Return(resourceGroup)
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.GetLongRunningOperationState().Return(nil)
 				m.DeleteAsync(gomockinternal.AContext(), resourceGroup, name).
@@ -659,13 +664,14 @@ func TestDeleteVMSS(t *testing.T) {
 					Capacity: 3,
 				}).AnyTimes()
 				s.ResourceGroup().AnyTimes().Return(resourceGroup)
+				s.NetworkResourceGroup().AnyTimes().Return(resourceGroup)
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.GetLongRunningOperationState().Return(nil)
 				m.DeleteAsync(gomockinternal.AContext(), resourceGroup, name).
 					Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 				m.Get(gomockinternal.AContext(), resourceGroup, name).
 					Return(newDefaultVMSS("VM_SIZE"), nil)
-				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(newDefaultInstances(), nil).AnyTimes()
+				m.ListInstancePages(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomock.Any()).DoAndReturn(instancePages(newDefaultInstances())).AnyTimes()
 				s.SetVMSSState(gomock.AssignableToTypeOf(&azure.VMSS{}))
 			},
 		},
@@ -1210,7 +1216,7 @@ func setupDefaultVMSSInProgressOperationDoneExpectations(s *mock_scalesets.MockS
 	}
 	s.GetLongRunningOperationState().Return(future)
 	m.GetResultIfDone(gomockinternal.AContext(), future).Return(createdVMSS, nil).AnyTimes()
-	m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil).AnyTimes()
+	m.ListInstancePages(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomock.Any()).DoAndReturn(instancePages(instances)).AnyTimes()
 	s.MaxSurge().Return(1, nil)
 	s.SetVMSSState(gomock.Any())
 	s.SetProviderID(azure.ProviderIDPrefix + *createdVMSS.ID)
@@ -1228,7 +1234,7 @@ func setupCreatingSucceededExpectations(s *mock_scalesets.MockScaleSetScopeMockR
 	s.SetLongRunningOperationState(future)
 	m.GetResultIfDone(gomockinternal.AContext(), future).Return(compute.VirtualMachineScaleSet{}, azure.NewOperationNotDoneError(future))
 	m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(vmss, nil)
-	m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(newDefaultInstances(), nil).AnyTimes()
+	m.ListInstancePages(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomock.Any()).DoAndReturn(instancePages(newDefaultInstances())).AnyTimes()
 	s.SetVMSSState(gomock.Any())
 	s.SetProviderID(azure.ProviderIDPrefix + *vmss.ID)
 }
@@ -1264,12 +1270,13 @@ func setupUpdateVMSSExpectations(s *mock_scalesets.MockScaleSetScopeMockRecorder
 func setupVMSSExpectationsWithoutVMImage(s *mock_scalesets.MockScaleSetScopeMockRecorder) {
 	s.SubscriptionID().AnyTimes().Return(defaultSubscriptionID)
 	s.ResourceGroup().AnyTimes().Return(defaultResourceGroup)
+	s.NetworkResourceGroup().AnyTimes().Return(defaultResourceGroup)
 	s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 	s.AdditionalTags()
 	s.Location().AnyTimes().Return("test-location")
 	s.ClusterName().Return("my-cluster")
 	s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
-	s.VMSSExtensionSpecs().Return([]azure.VMSSExtensionSpec{
+	s.VMSSExtensionSpecs(gomockinternal.AContext()).Return([]azure.VMSSExtensionSpec{
 		{
 			Name:         "someExtension",
 			ScaleSetName: "my-vmss",
@@ -1279,7 +1286,7 @@ func setupVMSSExpectationsWithoutVMImage(s *mock_scalesets.MockScaleSetScopeMock
 				"commandToExecute": "echo hello",
 			},
 		},
-	}).AnyTimes()
+	}, nil).AnyTimes()
 }
 
 func setupDefaultVMSSUpdateExpectations(s *mock_scalesets.MockScaleSetScopeMockRecorder) {
@@ -1289,3 +1296,15 @@ func setupDefaultVMSSUpdateExpectations(s *mock_scalesets.MockScaleSetScopeMockR
 	s.MaxSurge().Return(1, nil)
 	s.SetVMSSState(gomock.Any())
 }
+
+// instancePages returns a fake ListInstancePages implementation which processes the given pages of instances.
+func instancePages(pages ...[]compute.VirtualMachineScaleSetVM) func(context.Context, string, string, func([]compute.VirtualMachineScaleSetVM) error) error {
+	return func(_ context.Context, _, _ string, process func([]compute.VirtualMachineScaleSetVM) error) error {
+		for _, page := range pages {
+			if err := process(page); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
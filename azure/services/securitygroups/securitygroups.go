@@ -25,6 +25,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -65,15 +66,22 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	for _, nsgSpec := range s.Scope.NSGSpecs() {
 		securityRules := make([]network.SecurityRule, 0)
 		var etag *string
+		var tags map[string]*string
 
-		existingNSG, err := s.client.Get(ctx, s.Scope.ResourceGroup(), nsgSpec.Name)
+		existingNSG, err := s.client.Get(ctx, s.Scope.NetworkResourceGroup(), nsgSpec.Name)
 		switch {
 		case err != nil && !azure.ResourceNotFound(err):
-			return errors.Wrapf(err, "failed to get NSG %s in %s", nsgSpec.Name, s.Scope.ResourceGroup())
+			return errors.Wrapf(err, "failed to get NSG %s in %s", nsgSpec.Name, s.Scope.NetworkResourceGroup())
+		case err == nil && converters.MapToTags(existingNSG.Tags).HasShared(s.Scope.ClusterName()):
+			// shared security groups are only read by the cluster
+			s.Scope.V(4).Info("security group is shared, skipping update", "security group", nsgSpec.Name)
+			continue
 		case err == nil:
 			// security group already exists
 			// We append the existing NSG etag to the header to ensure we only apply the updates if the NSG has not been modified.
 			etag = existingNSG.Etag
+			// Keep the existing tags, the update only adds the missing rules.
+			tags = existingNSG.Tags
 			// Check if the expected rules are present
 			update := false
 			securityRules = *existingNSG.SecurityRules
@@ -91,20 +99,28 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			}
 		default:
 			s.Scope.V(2).Info("creating security group", "security group", nsgSpec.Name)
+			tags = converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+				ClusterName: s.Scope.ClusterName(),
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+				Name:        to.StringPtr(nsgSpec.Name),
+				Additional:  s.Scope.AdditionalTags(),
+			}))
 			for _, rule := range nsgSpec.SecurityRules {
 				securityRules = append(securityRules, converters.SecurityRuleToSDK(rule))
 			}
 		}
 		sg := network.SecurityGroup{
 			Location: to.StringPtr(s.Scope.Location()),
+			Tags:     tags,
 			SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
 				SecurityRules: &securityRules,
 			},
 			Etag: etag,
 		}
-		err = s.client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), nsgSpec.Name, sg)
+		err = s.client.CreateOrUpdate(ctx, s.Scope.NetworkResourceGroup(), nsgSpec.Name, sg)
+		azure.RecordCreate(ctx, "network security group", nsgSpec.Name, err)
 		if err != nil {
-			return errors.Wrapf(err, "failed to create or update security group %s in resource group %s", nsgSpec.Name, s.Scope.ResourceGroup())
+			return errors.Wrapf(err, "failed to create or update security group %s in resource group %s", nsgSpec.Name, s.Scope.NetworkResourceGroup())
 		}
 
 		s.Scope.V(2).Info("successfully created or updated security group", "security group", nsgSpec.Name)
@@ -146,14 +162,27 @@ func (s *Service) Delete(ctx context.Context) error {
 	}
 
 	for _, nsgSpec := range s.Scope.NSGSpecs() {
+		existingNSG, err := s.client.Get(ctx, s.Scope.NetworkResourceGroup(), nsgSpec.Name)
+		switch {
+		case err != nil && azure.ResourceNotFound(err):
+			// already deleted
+			continue
+		case err != nil:
+			return errors.Wrapf(err, "failed to get NSG %s in %s", nsgSpec.Name, s.Scope.NetworkResourceGroup())
+		case converters.MapToTags(existingNSG.Tags).HasShared(s.Scope.ClusterName()):
+			s.Scope.V(2).Info("skipping deletion of shared security group", "security group", nsgSpec.Name)
+			continue
+		}
+
 		s.Scope.V(2).Info("deleting security group", "security group", nsgSpec.Name)
-		err := s.client.Delete(ctx, s.Scope.ResourceGroup(), nsgSpec.Name)
+		err = s.client.Delete(ctx, s.Scope.NetworkResourceGroup(), nsgSpec.Name)
+		azure.RecordDelete(ctx, "network security group", nsgSpec.Name, err)
 		if err != nil && azure.ResourceNotFound(err) {
 			// already deleted
 			continue
 		}
 		if err != nil {
-			return errors.Wrapf(err, "failed to delete security group %s in resource group %s", nsgSpec.Name, s.Scope.ResourceGroup())
+			return errors.Wrapf(err, "failed to delete security group %s in resource group %s", nsgSpec.Name, s.Scope.NetworkResourceGroup())
 		}
 
 		s.Scope.V(2).Info("successfully deleted security group", "security group", nsgSpec.Name)
@@ -76,8 +76,10 @@ func TestReconcileSecurityGroups(t *testing.T) {
 					},
 				})
 				s.IsVnetManaged().Return(true)
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{"foo": "bar"})
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				m.Get(gomockinternal.AContext(), "my-rg", "nsg-one").Return(network.SecurityGroup{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "nsg-one", gomockinternal.DiffEq(network.SecurityGroup{
@@ -115,6 +117,11 @@ func TestReconcileSecurityGroups(t *testing.T) {
 					},
 					Etag:     nil,
 					Location: to.StringPtr("test-location"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("nsg-one"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"foo": to.StringPtr("bar"),
+					},
 				}))
 				m.Get(gomockinternal.AContext(), "my-rg", "nsg-two").Return(network.SecurityGroup{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "nsg-two", gomockinternal.DiffEq(network.SecurityGroup{
@@ -123,6 +130,11 @@ func TestReconcileSecurityGroups(t *testing.T) {
 					},
 					Etag:     nil,
 					Location: to.StringPtr("test-location"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("nsg-two"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"foo": to.StringPtr("bar"),
+					},
 				}))
 			},
 		}, {
@@ -151,8 +163,9 @@ func TestReconcileSecurityGroups(t *testing.T) {
 					},
 				})
 				s.IsVnetManaged().AnyTimes().Return(true)
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
+				s.ClusterName().AnyTimes().Return("my-cluster")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				m.Get(gomockinternal.AContext(), "my-rg", "nsg-one").Return(network.SecurityGroup{
 					Response: autorest.Response{},
@@ -224,6 +237,41 @@ func TestReconcileSecurityGroups(t *testing.T) {
 					Name: to.StringPtr("nsg-two"),
 				}, nil)
 			},
+		}, {
+			name: "shared security group is not updated",
+			expect: func(s *mock_securitygroups.MockNSGScopeMockRecorder, m *mock_securitygroups.MockclientMockRecorder) {
+				s.NSGSpecs().Return([]azure.NSGSpec{
+					{
+						Name: "nsg-one",
+						SecurityRules: infrav1.SecurityRules{
+							{
+								Name:             "first-rule",
+								Description:      "a test rule",
+								Protocol:         "*",
+								Priority:         400,
+								SourcePorts:      to.StringPtr("*"),
+								DestinationPorts: to.StringPtr("*"),
+								Source:           to.StringPtr("*"),
+								Destination:      to.StringPtr("*"),
+								Direction:        infrav1.SecurityRuleDirectionOutbound,
+							},
+						},
+					},
+				})
+				s.IsVnetManaged().AnyTimes().Return(true)
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				m.Get(gomockinternal.AContext(), "my-rg", "nsg-one").Return(network.SecurityGroup{
+					SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
+						SecurityRules: &[]network.SecurityRule{},
+					},
+					Name: to.StringPtr("nsg-one"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("shared"),
+					},
+				}, nil)
+			},
 		}, {
 			name: "skipping network security group reconcile in custom VNet mode",
 			expect: func(s *mock_securitygroups.MockNSGScopeMockRecorder, m *mock_securitygroups.MockclientMockRecorder) {
@@ -285,10 +333,13 @@ func TestDeleteSecurityGroups(t *testing.T) {
 						SecurityRules: infrav1.SecurityRules{},
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("my-cluster")
 				s.IsVnetManaged().Return(true)
+				m.Get(gomockinternal.AContext(), "my-rg", "nsg-one").Return(network.SecurityGroup{Name: to.StringPtr("nsg-one")}, nil)
 				m.Delete(gomockinternal.AContext(), "my-rg", "nsg-one")
+				m.Get(gomockinternal.AContext(), "my-rg", "nsg-two").Return(network.SecurityGroup{Name: to.StringPtr("nsg-two")}, nil)
 				m.Delete(gomockinternal.AContext(), "my-rg", "nsg-two")
 			},
 		},
@@ -305,12 +356,36 @@ func TestDeleteSecurityGroups(t *testing.T) {
 						SecurityRules: infrav1.SecurityRules{},
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("my-cluster")
 				s.IsVnetManaged().Return(true)
-				m.Delete(gomockinternal.AContext(), "my-rg", "nsg-one").
+				m.Get(gomockinternal.AContext(), "my-rg", "nsg-one").
+					Return(network.SecurityGroup{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.Get(gomockinternal.AContext(), "my-rg", "nsg-two").Return(network.SecurityGroup{Name: to.StringPtr("nsg-two")}, nil)
+				m.Delete(gomockinternal.AContext(), "my-rg", "nsg-two").
 					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				m.Delete(gomockinternal.AContext(), "my-rg", "nsg-two")
+			},
+		},
+		{
+			name: "shared security group is not deleted",
+			expect: func(s *mock_securitygroups.MockNSGScopeMockRecorder, m *mock_securitygroups.MockclientMockRecorder) {
+				s.NSGSpecs().Return([]azure.NSGSpec{
+					{
+						Name:          "nsg-one",
+						SecurityRules: infrav1.SecurityRules{},
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.IsVnetManaged().Return(true)
+				m.Get(gomockinternal.AContext(), "my-rg", "nsg-one").Return(network.SecurityGroup{
+					Name: to.StringPtr("nsg-one"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("shared"),
+					},
+				}, nil)
 			},
 		},
 		{
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subnets
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// cacheTimeToLive is how long a subnet read from Azure is served from the cache before it is read again.
+const cacheTimeToLive = 2 * time.Minute
+
+var (
+	doOnce      sync.Once
+	clientCache ttllru.PeekingCacher
+	cacheErr    error
+)
+
+// CachedClient is a Client which serves subnet gets from a TTL cache shared across reconciles. It is meant for
+// services which only read subnets, to avoid spending the ARM read quota on data which rarely changes.
+type CachedClient struct {
+	Client
+	cache   ttllru.PeekingCacher
+	hashKey string
+}
+
+var _ Client = &CachedClient{}
+
+// NewCachedClient creates a new subnets client which caches gets in the cache shared by all the cached subnets clients.
+func NewCachedClient(auth azure.Authorizer) (*CachedClient, error) {
+	doOnce.Do(func() {
+		clientCache, cacheErr = ttllru.New(1024, cacheTimeToLive)
+	})
+	if cacheErr != nil {
+		return nil, errors.Wrap(cacheErr, "failed creating LRU cache for subnets")
+	}
+
+	return newCachedClient(NewClient(auth), clientCache, auth.HashKey()), nil
+}
+
+// newCachedClient creates a new cached subnets client on top of the provided client and cache.
+func newCachedClient(client Client, cache ttllru.PeekingCacher, hashKey string) *CachedClient {
+	return &CachedClient{
+		Client:  client,
+		cache:   cache,
+		hashKey: hashKey,
+	}
+}
+
+// Get gets the specified subnet by virtual network and resource group, from the cache if it was read recently.
+func (cc *CachedClient) Get(ctx context.Context, resourceGroupName, vnetName, snName string) (network.Subnet, error) {
+	ctx, span := tele.Tracer().Start(ctx, "subnets.CachedClient.Get")
+	defer span.End()
+
+	key := cc.key(resourceGroupName, vnetName, snName)
+	// Peek does not extend the expiration of the item, so that a subnet read in every reconcile is still refreshed.
+	if cached, _, ok := cc.cache.Peek(key); ok {
+		if subnet, ok := cached.(network.Subnet); ok {
+			return subnet, nil
+		}
+	}
+
+	subnet, err := cc.Client.Get(ctx, resourceGroupName, vnetName, snName)
+	if err != nil {
+		return subnet, err
+	}
+	_ = cc.cache.Add(key, subnet)
+	return subnet, nil
+}
+
+// CreateOrUpdate creates or updates a subnet in the specified virtual network and evicts it from the cache.
+func (cc *CachedClient) CreateOrUpdate(ctx context.Context, resourceGroupName, vnetName, snName string, sn network.Subnet) error {
+	defer cc.cache.Remove(cc.key(resourceGroupName, vnetName, snName))
+	return cc.Client.CreateOrUpdate(ctx, resourceGroupName, vnetName, snName, sn)
+}
+
+// Delete deletes the specified subnet and evicts it from the cache.
+func (cc *CachedClient) Delete(ctx context.Context, resourceGroupName, vnetName, snName string) error {
+	defer cc.cache.Remove(cc.key(resourceGroupName, vnetName, snName))
+	return cc.Client.Delete(ctx, resourceGroupName, vnetName, snName)
+}
+
+// key returns the cache key of a subnet, which includes the hash of the credentials and subscription used to read it.
+func (cc *CachedClient) key(resourceGroupName, vnetName, snName string) string {
+	return strings.Join([]string{cc.hashKey, strings.ToLower(resourceGroupName), strings.ToLower(vnetName), strings.ToLower(snName)}, "/")
+}
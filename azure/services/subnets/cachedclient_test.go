@@ -0,0 +1,159 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subnets
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets/mock_subnets"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+)
+
+func TestCachedClientGet(t *testing.T) {
+	subnet := network.Subnet{ID: to.StringPtr("subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")}
+	notFound := autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found")
+
+	testcases := []struct {
+		name   string
+		calls  func(g *WithT, cc *CachedClient)
+		expect func(m *mock_subnets.MockClientMockRecorder)
+	}{
+		{
+			name: "subnet is read from Azure once and then served from the cache",
+			calls: func(g *WithT, cc *CachedClient) {
+				for i := 0; i < 3; i++ {
+					got, err := cc.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet")
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(got).To(Equal(subnet))
+				}
+			},
+			expect: func(m *mock_subnets.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Times(1).Return(subnet, nil)
+			},
+		},
+		{
+			name: "subnets are cached per resource group, vnet and name, ignoring case",
+			calls: func(g *WithT, cc *CachedClient) {
+				_, err := cc.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet")
+				g.Expect(err).NotTo(HaveOccurred())
+				_, err = cc.Get(context.TODO(), "my-rg", "my-vnet", "other-subnet")
+				g.Expect(err).NotTo(HaveOccurred())
+				_, err = cc.Get(context.TODO(), "MY-RG", "my-vnet", "my-subnet")
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expect: func(m *mock_subnets.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Times(1).Return(subnet, nil)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "other-subnet").Times(1).Return(network.Subnet{}, nil)
+			},
+		},
+		{
+			name: "errors are not cached",
+			calls: func(g *WithT, cc *CachedClient) {
+				_, err := cc.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet")
+				g.Expect(err).To(HaveOccurred())
+				got, err := cc.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(got).To(Equal(subnet))
+			},
+			expect: func(m *mock_subnets.MockClientMockRecorder) {
+				gomock.InOrder(
+					m.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, notFound),
+					m.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(subnet, nil),
+				)
+			},
+		},
+		{
+			name: "updating a subnet evicts it from the cache",
+			calls: func(g *WithT, cc *CachedClient) {
+				_, err := cc.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(cc.CreateOrUpdate(context.TODO(), "my-rg", "my-vnet", "my-subnet", subnet)).To(Succeed())
+				_, err = cc.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet")
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expect: func(m *mock_subnets.MockClientMockRecorder) {
+				gomock.InOrder(
+					m.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(subnet, nil),
+					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet", subnet).Return(nil),
+					m.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(subnet, nil),
+				)
+			},
+		},
+		{
+			name: "deleting a subnet evicts it from the cache",
+			calls: func(g *WithT, cc *CachedClient) {
+				_, err := cc.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(cc.Delete(context.TODO(), "my-rg", "my-vnet", "my-subnet")).To(Succeed())
+				_, err = cc.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet")
+				g.Expect(err).To(HaveOccurred())
+			},
+			expect: func(m *mock_subnets.MockClientMockRecorder) {
+				gomock.InOrder(
+					m.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(subnet, nil),
+					m.Delete(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(nil),
+					m.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, notFound),
+				)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			clientMock := mock_subnets.NewMockClient(mockCtrl)
+
+			tc.expect(clientMock.EXPECT())
+
+			cache, err := ttllru.New(16, time.Minute)
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.calls(g, newCachedClient(clientMock, cache, "hash"))
+		})
+	}
+}
+
+func TestCachedClientGetExpires(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_subnets.NewMockClient(mockCtrl)
+	clientMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Times(2).Return(network.Subnet{}, nil)
+
+	cache, err := ttllru.New(16, 10*time.Millisecond)
+	g.Expect(err).NotTo(HaveOccurred())
+	cc := newCachedClient(clientMock, cache, "hash")
+
+	_, err = cc.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet")
+	g.Expect(err).NotTo(HaveOccurred())
+	time.Sleep(20 * time.Millisecond)
+	_, err = cc.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet")
+	g.Expect(err).NotTo(HaveOccurred())
+}
@@ -27,6 +27,7 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -41,6 +42,8 @@ type SubnetScope interface {
 type Service struct {
 	Scope SubnetScope
 	Client
+	// evictVnet evicts the vnet of a subnet written by the service from the cache of the virtual networks service.
+	evictVnet func(resourceGroupName, vnetName string)
 }
 
 // New creates a new service.
@@ -48,6 +51,9 @@ func New(scope SubnetScope) *Service {
 	return &Service{
 		Scope:  scope,
 		Client: NewClient(scope),
+		evictVnet: func(resourceGroupName, vnetName string) {
+			virtualnetworks.EvictCached(scope, resourceGroupName, vnetName)
+		},
 	}
 }
 
@@ -89,19 +95,19 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 			if subnetSpec.RouteTableName != "" {
 				subnetProperties.RouteTable = &network.RouteTable{
-					ID: to.StringPtr(azure.RouteTableID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), subnetSpec.RouteTableName)),
+					ID: to.StringPtr(azure.RouteTableID(s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), subnetSpec.RouteTableName)),
 				}
 			}
 
 			if subnetSpec.NatGatewayName != "" {
 				subnetProperties.NatGateway = &network.SubResource{
-					ID: to.StringPtr(azure.NatGatewayID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), subnetSpec.NatGatewayName)),
+					ID: to.StringPtr(azure.NatGatewayID(s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), subnetSpec.NatGatewayName)),
 				}
 			}
 
 			if subnetSpec.SecurityGroupName != "" {
 				subnetProperties.NetworkSecurityGroup = &network.SecurityGroup{
-					ID: to.StringPtr(azure.SecurityGroupID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), subnetSpec.SecurityGroupName)),
+					ID: to.StringPtr(azure.SecurityGroupID(s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), subnetSpec.SecurityGroupName)),
 				}
 			}
 
@@ -115,6 +121,8 @@ func (s *Service) Reconcile(ctx context.Context) error {
 					SubnetPropertiesFormat: &subnetProperties,
 				},
 			)
+			azure.RecordCreate(ctx, "subnet", subnetSpec.Name, err)
+			s.evictCachedVnet(s.Scope.Vnet().ResourceGroup, subnetSpec.VNetName)
 			if err != nil {
 				return errors.Wrapf(err, "failed to create subnet %s in resource group %s", subnetSpec.Name, s.Scope.Vnet().ResourceGroup)
 			}
@@ -137,6 +145,8 @@ func (s *Service) Delete(ctx context.Context) error {
 		}
 		s.Scope.V(2).Info("deleting subnet in vnet", "subnet", subnetSpec.Name, "vnet", subnetSpec.VNetName)
 		err := s.Client.Delete(ctx, s.Scope.Vnet().ResourceGroup, subnetSpec.VNetName, subnetSpec.Name)
+		azure.RecordDelete(ctx, "subnet", subnetSpec.Name, err)
+		s.evictCachedVnet(s.Scope.Vnet().ResourceGroup, subnetSpec.VNetName)
 		if err != nil && azure.ResourceNotFound(err) {
 			// already deleted
 			continue
@@ -150,6 +160,14 @@ func (s *Service) Delete(ctx context.Context) error {
 	return nil
 }
 
+// evictCachedVnet evicts the vnet of a subnet from the cache of the virtual networks service, as writing the subnet
+// changes the vnet.
+func (s *Service) evictCachedVnet(resourceGroupName, vnetName string) {
+	if s.evictVnet != nil {
+		s.evictVnet(resourceGroupName, vnetName)
+	}
+}
+
 // getExisting provides information about an existing subnet.
 func (s *Service) getExisting(ctx context.Context, rgName string, spec azure.SubnetSpec) (*infrav1.SubnetSpec, error) {
 	ctx, span := tele.Tracer().Start(ctx, "subnets.Service.getExisting")
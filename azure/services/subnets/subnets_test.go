@@ -61,6 +61,7 @@ func TestReconcileSubnets(t *testing.T) {
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.IsIPv6Enabled().AnyTimes().Return(false)
 				s.IsVnetManaged().Return(true)
 				m.Get(gomockinternal.AContext(), "", "my-vnet", "my-subnet").
@@ -92,6 +93,7 @@ func TestReconcileSubnets(t *testing.T) {
 				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"})
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.IsVnetManaged().Return(true)
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vnet", "my-ipv6-subnet").
@@ -127,6 +129,7 @@ func TestReconcileSubnets(t *testing.T) {
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.IsIPv6Enabled().AnyTimes().Return(false)
 				s.IsVnetManaged().Return(true)
 				m.Get(gomockinternal.AContext(), "", "my-vnet", "my-subnet").
@@ -153,6 +156,7 @@ func TestReconcileSubnets(t *testing.T) {
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "", "my-vnet", "my-subnet").
 					Return(network.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
@@ -180,6 +184,7 @@ func TestReconcileSubnets(t *testing.T) {
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("custom-vnet-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("custom-vnet-rg")
 				s.IsVnetManaged().Return(false)
 				m.Get(gomockinternal.AContext(), "custom-vnet-rg", "custom-vnet", "my-subnet").
 					Return(network.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
@@ -212,6 +217,7 @@ func TestReconcileSubnets(t *testing.T) {
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "", "my-vnet", "my-subnet").
 					Return(network.Subnet{
 						ID:   to.StringPtr("subnet-id"),
@@ -286,6 +292,7 @@ func TestReconcileSubnets(t *testing.T) {
 				s.IsIPv6Enabled().AnyTimes().Return(true)
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Get(gomockinternal.AContext(), "", "my-vnet", "my-ipv6-subnet").
 					Return(network.Subnet{
 						ID:   to.StringPtr("subnet-id"),
@@ -401,6 +408,7 @@ func TestDeleteSubnets(t *testing.T) {
 				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet"})
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Delete(gomockinternal.AContext(), "", "my-vnet", "my-subnet")
 				m.Delete(gomockinternal.AContext(), "", "my-vnet", "my-subnet-1")
 			},
@@ -423,6 +431,7 @@ func TestDeleteSubnets(t *testing.T) {
 				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet"})
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Delete(gomockinternal.AContext(), "", "my-vnet", "my-subnet").
 					Return(autorest.NewErrorWithResponse("", "my-vnet", &http.Response{StatusCode: 404}, "Not found"))
 			},
@@ -453,6 +462,7 @@ func TestDeleteSubnets(t *testing.T) {
 				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet"})
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Delete(gomockinternal.AContext(), "", "my-vnet", "my-subnet").
 					Return(autorest.NewErrorWithResponse("", "my-vnet", &http.Response{StatusCode: 404}, "Not found"))
 				m.Delete(gomockinternal.AContext(), "", "my-vnet", "my-subnet-1")
@@ -476,6 +486,7 @@ func TestDeleteSubnets(t *testing.T) {
 				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{ResourceGroup: "custom-vnet-rg", Name: "custom-vnet", ID: "id1"})
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 			},
 		},
 		{
@@ -496,6 +507,7 @@ func TestDeleteSubnets(t *testing.T) {
 				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"})
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-vnet", "my-subnet").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
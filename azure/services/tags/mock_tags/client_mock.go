@@ -51,32 +51,32 @@ func (m *Mockclient) EXPECT() *MockclientMockRecorder {
 	return m.recorder
 }
 
-// CreateOrUpdateAtScope mocks base method.
-func (m *Mockclient) CreateOrUpdateAtScope(arg0 context.Context, arg1 string, arg2 resources.TagsResource) (resources.TagsResource, error) {
+// GetAtScope mocks base method.
+func (m *Mockclient) GetAtScope(arg0 context.Context, arg1 string) (resources.TagsResource, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrUpdateAtScope", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "GetAtScope", arg0, arg1)
 	ret0, _ := ret[0].(resources.TagsResource)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateOrUpdateAtScope indicates an expected call of CreateOrUpdateAtScope.
-func (mr *MockclientMockRecorder) CreateOrUpdateAtScope(arg0, arg1, arg2 interface{}) *gomock.Call {
+// GetAtScope indicates an expected call of GetAtScope.
+func (mr *MockclientMockRecorder) GetAtScope(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateAtScope", reflect.TypeOf((*Mockclient)(nil).CreateOrUpdateAtScope), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAtScope", reflect.TypeOf((*Mockclient)(nil).GetAtScope), arg0, arg1)
 }
 
-// GetAtScope mocks base method.
-func (m *Mockclient) GetAtScope(arg0 context.Context, arg1 string) (resources.TagsResource, error) {
+// UpdateAtScope mocks base method.
+func (m *Mockclient) UpdateAtScope(arg0 context.Context, arg1 string, arg2 resources.TagsPatchResource) (resources.TagsResource, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAtScope", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateAtScope", arg0, arg1, arg2)
 	ret0, _ := ret[0].(resources.TagsResource)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAtScope indicates an expected call of GetAtScope.
-func (mr *MockclientMockRecorder) GetAtScope(arg0, arg1 interface{}) *gomock.Call {
+// UpdateAtScope indicates an expected call of UpdateAtScope.
+func (mr *MockclientMockRecorder) UpdateAtScope(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAtScope", reflect.TypeOf((*Mockclient)(nil).GetAtScope), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAtScope", reflect.TypeOf((*Mockclient)(nil).UpdateAtScope), arg0, arg1, arg2)
 }
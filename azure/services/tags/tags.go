@@ -20,11 +20,11 @@ import (
 	"context"
 
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
-	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -52,49 +52,84 @@ func New(scope TagScope) *Service {
 }
 
 // Reconcile ensures tags are correct.
+// Tags are patched in place on existing resources: the tags added or updated since the last applied annotation are merged
+// and the ones removed from it are deleted, leaving the tags managed by other parties untouched. Resources which are not
+// owned by the cluster are skipped. Annotations are only updated once every resource tracked by them has been patched,
+// so that several specs can share the same annotation.
 func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, span := tele.Tracer().Start(ctx, "tags.Service.Reconcile")
 	defer span.End()
 
+	var annotations []string
+	newAnnotations := make(map[string]map[string]interface{})
 	for _, tagsSpec := range s.Scope.TagsSpecs() {
 		annotation, err := s.Scope.AnnotationJSON(tagsSpec.Annotation)
 		if err != nil {
 			return err
 		}
 		changed, created, deleted, newAnnotation := tagsChanged(annotation, tagsSpec.Tags)
-		if changed {
-			s.Scope.V(2).Info("Updating tags")
-			result, err := s.client.GetAtScope(ctx, tagsSpec.Scope)
-			if err != nil {
-				return errors.Wrap(err, "failed to get existing tags")
-			}
-			tags := make(map[string]*string)
-			if result.Properties != nil && result.Properties.Tags != nil {
-				tags = result.Properties.Tags
-			}
-			for k, v := range created {
-				tags[k] = to.StringPtr(v)
-			}
-
-			for k := range deleted {
-				delete(tags, k)
-			}
-
-			if _, err := s.client.CreateOrUpdateAtScope(ctx, tagsSpec.Scope, resources.TagsResource{Properties: &resources.Tags{Tags: tags}}); err != nil {
-				return errors.Wrap(err, "cannot update tags")
-			}
-
-			// We also need to update the annotation if anything changed.
-			if err = s.Scope.UpdateAnnotationJSON(tagsSpec.Annotation, newAnnotation); err != nil {
-				return err
-			}
-			s.Scope.V(2).Info("successfully updated tags")
+		if !changed {
+			continue
+		}
+
+		if err := s.updateTags(ctx, tagsSpec.Scope, created, deleted); err != nil {
+			return err
+		}
+
+		if _, ok := newAnnotations[tagsSpec.Annotation]; !ok {
+			annotations = append(annotations, tagsSpec.Annotation)
+		}
+		newAnnotations[tagsSpec.Annotation] = newAnnotation
+	}
+
+	// We also need to update the annotations if anything changed.
+	for _, annotation := range annotations {
+		if err := s.Scope.UpdateAnnotationJSON(annotation, newAnnotations[annotation]); err != nil {
+			return err
+		}
+	}
+	if len(annotations) > 0 {
+		s.Scope.V(2).Info("successfully updated tags")
+	}
+	return nil
+}
+
+// updateTags merges the created tags into and removes the deleted tags from the resource at the given scope.
+func (s *Service) updateTags(ctx context.Context, scope string, created, deleted map[string]string) error {
+	result, err := s.client.GetAtScope(ctx, scope)
+	if err != nil && azure.ResourceNotFound(err) {
+		// the resource gets the current tags when it is created
+		s.Scope.V(2).Info("skipping tags update of missing resource", "scope", scope)
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "failed to get existing tags")
+	}
+	if result.Properties == nil || !converters.MapToTags(result.Properties.Tags).HasOwned(s.Scope.ClusterName()) {
+		s.Scope.V(2).Info("skipping tags update of unmanaged resource", "scope", scope)
+		return nil
+	}
+
+	s.Scope.V(2).Info("updating tags", "scope", scope)
+	if len(created) > 0 {
+		if _, err := s.client.UpdateAtScope(ctx, scope, resources.TagsPatchResource{
+			Operation:  "Merge",
+			Properties: &resources.Tags{Tags: converters.TagsToMap(created)},
+		}); err != nil {
+			return errors.Wrap(err, "cannot update tags")
+		}
+	}
+	if len(deleted) > 0 {
+		if _, err := s.client.UpdateAtScope(ctx, scope, resources.TagsPatchResource{
+			Operation:  "Delete",
+			Properties: &resources.Tags{Tags: converters.TagsToMap(deleted)},
+		}); err != nil {
+			return errors.Wrap(err, "cannot delete tags")
 		}
 	}
 	return nil
 }
 
-// Delete is a no-op as the tags get deleted as part of VM deletion.
+// Delete is a no-op as the tags get deleted along with the resources.
 func (s *Service) Delete(ctx context.Context) error {
 	_, span := tele.Tracer().Start(ctx, "tags.Service.Delete")
 	defer span.End()
@@ -44,6 +44,7 @@ func TestReconcileTags(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_tags.MockTagScopeMockRecorder, m *mock_tags.MockclientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("test-cluster")
 				s.TagsSpecs().Return([]azure.TagsSpec{
 					{
 						Scope: "/sub/123/fake/scope",
@@ -61,9 +62,16 @@ func TestReconcileTags(t *testing.T) {
 						Annotation: "my-annotation-2",
 					},
 				})
-				m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(resources.TagsResource{}, nil)
+				m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(resources.TagsResource{
+					Properties: &resources.Tags{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						},
+					},
+				}, nil)
 				s.AnnotationJSON("my-annotation")
-				m.CreateOrUpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", resources.TagsResource{
+				m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", resources.TagsPatchResource{
+					Operation: "Merge",
 					Properties: &resources.Tags{
 						Tags: map[string]*string{
 							"foo":   to.StringPtr("bar"),
@@ -72,9 +80,16 @@ func TestReconcileTags(t *testing.T) {
 					},
 				})
 				s.UpdateAnnotationJSON("my-annotation", map[string]interface{}{"foo": "bar", "thing": "stuff"})
-				m.GetAtScope(gomockinternal.AContext(), "/sub/123/other/scope").Return(resources.TagsResource{}, nil)
+				m.GetAtScope(gomockinternal.AContext(), "/sub/123/other/scope").Return(resources.TagsResource{
+					Properties: &resources.Tags{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						},
+					},
+				}, nil)
 				s.AnnotationJSON("my-annotation-2")
-				m.CreateOrUpdateAtScope(gomockinternal.AContext(), "/sub/123/other/scope", resources.TagsResource{
+				m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/other/scope", resources.TagsPatchResource{
+					Operation: "Merge",
 					Properties: &resources.Tags{
 						Tags: map[string]*string{
 							"tag1": to.StringPtr("value1"),
@@ -84,6 +99,124 @@ func TestReconcileTags(t *testing.T) {
 				s.UpdateAnnotationJSON("my-annotation-2", map[string]interface{}{"tag1": "value1"})
 			},
 		},
+		{
+			name:          "update and delete tags",
+			expectedError: "",
+			expect: func(s *mock_tags.MockTagScopeMockRecorder, m *mock_tags.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.TagsSpecs().Return([]azure.TagsSpec{
+					{
+						Scope: "/sub/123/fake/scope",
+						Tags: map[string]string{
+							"foo": "baz",
+						},
+						Annotation: "my-annotation",
+					},
+				})
+				s.AnnotationJSON("my-annotation").Return(map[string]interface{}{"foo": "bar", "thing": "stuff"}, nil)
+				m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(resources.TagsResource{
+					Properties: &resources.Tags{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						},
+					},
+				}, nil)
+				m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", resources.TagsPatchResource{
+					Operation: "Merge",
+					Properties: &resources.Tags{
+						Tags: map[string]*string{
+							"foo": to.StringPtr("baz"),
+						},
+					},
+				})
+				m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", resources.TagsPatchResource{
+					Operation: "Delete",
+					Properties: &resources.Tags{
+						Tags: map[string]*string{
+							"thing": to.StringPtr("stuff"),
+						},
+					},
+				})
+				s.UpdateAnnotationJSON("my-annotation", map[string]interface{}{"foo": "baz"})
+			},
+		},
+		{
+			name:          "resources sharing an annotation",
+			expectedError: "",
+			expect: func(s *mock_tags.MockTagScopeMockRecorder, m *mock_tags.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.TagsSpecs().Return([]azure.TagsSpec{
+					{
+						Scope: "/sub/123/fake/scope",
+						Tags: map[string]string{
+							"foo": "bar",
+						},
+						Annotation: "my-annotation",
+					},
+					{
+						Scope: "/sub/123/other/scope",
+						Tags: map[string]string{
+							"foo": "bar",
+						},
+						Annotation: "my-annotation",
+					},
+				})
+				s.AnnotationJSON("my-annotation").Times(2)
+				m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(resources.TagsResource{
+					Properties: &resources.Tags{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						},
+					},
+				}, nil)
+				m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", gomock.Any())
+				m.GetAtScope(gomockinternal.AContext(), "/sub/123/other/scope").Return(resources.TagsResource{
+					Properties: &resources.Tags{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						},
+					},
+				}, nil)
+				m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/other/scope", gomock.Any())
+				s.UpdateAnnotationJSON("my-annotation", map[string]interface{}{"foo": "bar"})
+			},
+		},
+		{
+			name:          "skip unmanaged and missing resources",
+			expectedError: "",
+			expect: func(s *mock_tags.MockTagScopeMockRecorder, m *mock_tags.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				s.TagsSpecs().Return([]azure.TagsSpec{
+					{
+						Scope: "/sub/123/fake/scope",
+						Tags: map[string]string{
+							"foo": "bar",
+						},
+						Annotation: "my-annotation",
+					},
+					{
+						Scope: "/sub/123/other/scope",
+						Tags: map[string]string{
+							"foo": "bar",
+						},
+						Annotation: "my-annotation",
+					},
+				})
+				s.AnnotationJSON("my-annotation").Times(2)
+				m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(resources.TagsResource{
+					Properties: &resources.Tags{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_other-cluster": to.StringPtr("owned"),
+						},
+					},
+				}, nil)
+				m.GetAtScope(gomockinternal.AContext(), "/sub/123/other/scope").Return(resources.TagsResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				s.UpdateAnnotationJSON("my-annotation", map[string]interface{}{"foo": "bar"})
+			},
+		},
 		{
 			name:          "error getting existing tags",
 			expectedError: "failed to get existing tags: #: Internal Server Error: StatusCode=500",
@@ -108,6 +241,7 @@ func TestReconcileTags(t *testing.T) {
 			expectedError: "cannot update tags: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_tags.MockTagScopeMockRecorder, m *mock_tags.MockclientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ClusterName().AnyTimes().Return("test-cluster")
 				s.TagsSpecs().Return([]azure.TagsSpec{
 					{
 						Scope: "/sub/123/fake/scope",
@@ -117,9 +251,16 @@ func TestReconcileTags(t *testing.T) {
 						Annotation: "my-annotation",
 					},
 				})
-				m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(resources.TagsResource{}, nil)
+				m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(resources.TagsResource{
+					Properties: &resources.Tags{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						},
+					},
+				}, nil)
 				s.AnnotationJSON("my-annotation")
-				m.CreateOrUpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", resources.TagsResource{
+				m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", resources.TagsPatchResource{
+					Operation: "Merge",
 					Properties: &resources.Tags{
 						Tags: map[string]*string{
 							"key": to.StringPtr("value"),
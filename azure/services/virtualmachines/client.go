@@ -23,15 +23,18 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 // Client wraps go-sdk.
 type Client interface {
 	Get(context.Context, string, string) (compute.VirtualMachine, error)
-	CreateOrUpdate(context.Context, string, string, compute.VirtualMachine) error
-	Delete(context.Context, string, string) error
+	CreateOrUpdateAsync(context.Context, string, string, compute.VirtualMachine) (*infrav1.Future, error)
+	DeleteAsync(context.Context, string, string) (*infrav1.Future, error)
+	IsDone(context.Context, *infrav1.Future) (bool, error)
 }
 
 // AzureClient contains the Azure go-sdk Client.
@@ -62,38 +65,38 @@ func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vmName string
 	return ac.virtualmachines.Get(ctx, resourceGroupName, vmName, "")
 }
 
-// CreateOrUpdate the operation to create or update a virtual machine.
-func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, vmName string, vm compute.VirtualMachine) error {
-	ctx, span := tele.Tracer().Start(ctx, "virtualmachines.AzureClient.CreateOrUpdate")
+// CreateOrUpdateAsync starts the operation to create or update a virtual machine, and returns the future of the
+// long-running operation without waiting for it to complete.
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupName, vmName string, vm compute.VirtualMachine) (*infrav1.Future, error) {
+	ctx, span := tele.Tracer().Start(ctx, "virtualmachines.AzureClient.CreateOrUpdateAsync")
 	defer span.End()
 
 	future, err := ac.virtualmachines.CreateOrUpdate(ctx, resourceGroupName, vmName, vm)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = future.WaitForCompletionRef(ctx, ac.virtualmachines.Client)
-	if err != nil {
-		return err
-	}
-	_, err = future.Result(ac.virtualmachines)
-	return err
+	return async.NewFuture(&future, async.PutFuture, serviceName, resourceGroupName, vmName)
 }
 
-// Delete the operation to delete a virtual machine.
-func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, vmName string) error {
-	ctx, span := tele.Tracer().Start(ctx, "virtualmachines.AzureClient.Delete")
+// DeleteAsync starts the operation to delete a virtual machine, and returns the future of the long-running operation
+// without waiting for it to complete.
+func (ac *AzureClient) DeleteAsync(ctx context.Context, resourceGroupName, vmName string) (*infrav1.Future, error) {
+	ctx, span := tele.Tracer().Start(ctx, "virtualmachines.AzureClient.DeleteAsync")
 	defer span.End()
 
 	// TODO: pass variable to force the deletion or not
 	// now we are not forcing.
 	future, err := ac.virtualmachines.Delete(ctx, resourceGroupName, vmName, to.BoolPtr(false))
 	if err != nil {
-		return err
-	}
-	err = future.WaitForCompletionRef(ctx, ac.virtualmachines.Client)
-	if err != nil {
-		return err
+		return nil, err
 	}
-	_, err = future.Result(ac.virtualmachines)
-	return err
+	return async.NewFuture(&future, async.DeleteFuture, serviceName, resourceGroupName, vmName)
+}
+
+// IsDone returns true if the long-running operation of the future is done.
+func (ac *AzureClient) IsDone(ctx context.Context, future *infrav1.Future) (bool, error) {
+	ctx, span := tele.Tracer().Start(ctx, "virtualmachines.AzureClient.IsDone")
+	defer span.End()
+
+	return async.IsDone(ctx, ac.virtualmachines, future)
 }
@@ -31,9 +31,11 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/availabilitysets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/quotas"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/util/generators"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -42,12 +44,15 @@ import (
 const (
 	// UltraSSDStorageAccountType identifies the Ultra disk storage account type.
 	UltraSSDStorageAccountType = "UltraSSD_LRS"
+
+	serviceName = "virtualmachines"
 )
 
 // VMScope defines the scope interface for a virtual machines service.
 type VMScope interface {
 	logr.Logger
 	azure.ClusterDescriber
+	azure.AsyncStatusUpdater
 	VMSpec() azure.VMSpec
 	GetBootstrapData(ctx context.Context) (string, error)
 	GetVMImage() (*infrav1.Image, error)
@@ -68,6 +73,7 @@ type Service struct {
 	publicIPsClient        publicips.Client
 	availabilitySetsClient availabilitysets.Client
 	resourceSKUCache       *resourceskus.Cache
+	quotasSvc              *quotas.Service
 }
 
 // New creates a new service.
@@ -79,6 +85,7 @@ func New(scope VMScope, skuCache *resourceskus.Cache) *Service {
 		publicIPsClient:        publicips.NewClient(scope),
 		availabilitySetsClient: availabilitysets.NewClient(scope),
 		resourceSKUCache:       skuCache,
+		quotasSvc:              quotas.New(scope),
 	}
 }
 
@@ -88,6 +95,14 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	defer span.End()
 
 	vmSpec := s.Scope.VMSpec()
+
+	// wait for the VM creation started by a previous reconcile before refreshing the status from the VM
+	if future := s.Scope.GetLongRunningOperationState(vmSpec.Name, serviceName); future != nil {
+		if err := async.CheckOperation(ctx, s.Scope, s.Client, future); err != nil {
+			return errors.Wrapf(err, "failed to create VM %s in resource group %s", vmSpec.Name, s.Scope.ResourceGroup())
+		}
+	}
+
 	existingVM, err := s.getExisting(ctx, vmSpec.Name)
 
 	switch {
@@ -106,11 +121,21 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		s.Scope.UpdateStatus()
 	default:
 		s.Scope.V(2).Info("creating VM", "vm", vmSpec.Name)
+		if vmSpec.OSDisk.OSType == azure.WindowsOS {
+			// the VM is created with the bootstrap extension, see azure.WindowsBootstrapExtensionAnnotation
+			s.Scope.SetAnnotation(azure.WindowsBootstrapExtensionAnnotation, "true")
+		}
 		sku, err := s.resourceSKUCache.Get(ctx, vmSpec.Size, resourceskus.VirtualMachines)
 		if err != nil {
 			return azure.WithTerminalError(errors.Wrapf(err, "failed to get SKU %s in compute api", vmSpec.Size))
 		}
 
+		if s.quotasSvc != nil {
+			if err := s.quotasSvc.CheckVirtualMachines(ctx, sku, 1, vmSpec.SpotVMOptions != nil); err != nil {
+				return errors.Wrapf(err, "failed to create VM %s", vmSpec.Name)
+			}
+		}
+
 		storageProfile, err := s.generateStorageProfile(ctx, vmSpec, sku)
 		if err != nil {
 			return err
@@ -206,7 +231,12 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			}
 		}
 
-		if err := s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), vmSpec.Name, virtualMachine); err != nil {
+		future, err := s.Client.CreateOrUpdateAsync(ctx, s.Scope.ResourceGroup(), vmSpec.Name, virtualMachine)
+		if err != nil {
+			azure.RecordCreate(ctx, async.ResourceKind(serviceName), vmSpec.Name, err)
+			return errors.Wrapf(err, "failed to create VM %s in resource group %s", vmSpec.Name, s.Scope.ResourceGroup())
+		}
+		if err := async.TrackOperation(ctx, s.Scope, s.Client, future); err != nil {
 			return errors.Wrapf(err, "failed to create VM %s in resource group %s", vmSpec.Name, s.Scope.ResourceGroup())
 		}
 
@@ -222,13 +252,27 @@ func (s *Service) Delete(ctx context.Context) error {
 	defer span.End()
 
 	vmSpec := s.Scope.VMSpec()
+	if future := s.Scope.GetLongRunningOperationState(vmSpec.Name, serviceName); future != nil {
+		if err := async.CheckOperation(ctx, s.Scope, s.Client, future); err != nil {
+			return errors.Wrapf(err, "failed to delete VM %s in resource group %s", vmSpec.Name, s.Scope.ResourceGroup())
+		}
+		if future.Type == async.DeleteFuture {
+			s.Scope.V(2).Info("successfully deleted VM", "vm", vmSpec.Name)
+			return nil
+		}
+	}
+
 	s.Scope.V(2).Info("deleting VM", "vm", vmSpec.Name)
-	err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), vmSpec.Name)
+	future, err := s.Client.DeleteAsync(ctx, s.Scope.ResourceGroup(), vmSpec.Name)
 	if err != nil && azure.ResourceNotFound(err) {
 		// already deleted
 		return nil
 	}
 	if err != nil {
+		azure.RecordDelete(ctx, async.ResourceKind(serviceName), vmSpec.Name, err)
+		return errors.Wrapf(err, "failed to delete VM %s in resource group %s", vmSpec.Name, s.Scope.ResourceGroup())
+	}
+	if err := async.TrackOperation(ctx, s.Scope, s.Client, future); err != nil {
 		return errors.Wrapf(err, "failed to delete VM %s in resource group %s", vmSpec.Name, s.Scope.ResourceGroup())
 	}
 
@@ -351,7 +395,7 @@ func (s *Service) getPublicIPAddress(ctx context.Context, publicIPAddressName st
 	defer span.End()
 
 	retAddress := corev1.NodeAddress{}
-	publicIP, err := s.publicIPsClient.Get(ctx, s.Scope.ResourceGroup(), publicIPAddressName)
+	publicIP, err := s.publicIPsClient.Get(ctx, s.Scope.NetworkResourceGroup(), publicIPAddressName)
 	if err != nil {
 		return retAddress, err
 	}
@@ -448,6 +492,10 @@ func (s *Service) generateStorageProfile(ctx context.Context, vmSpec azure.VMSpe
 		return nil, errors.Wrap(err, "failed to get VM image")
 	}
 
+	if azure.IsGeneration2Image(image) && !sku.SupportsHyperVGeneration(resourceskus.HyperVGenerationV2) {
+		return nil, azure.WithTerminalError(fmt.Errorf("vm size %s does not support generation 2 VMs required by image %s. select a different vm size", vmSpec.Size, image.Marketplace.SKU))
+	}
+
 	imageRef, err := converters.ImageToSDK(image)
 	if err != nil {
 		return nil, err
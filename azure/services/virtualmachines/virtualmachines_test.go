@@ -34,6 +34,7 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/availabilitysets/mock_availabilitysets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces/mock_networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips/mock_publicips"
@@ -42,6 +43,11 @@ import (
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 )
 
+var (
+	fakeFuture       = &infrav1.Future{Type: async.PutFuture, ServiceName: serviceName, ResourceGroup: "my-rg", Name: "my-vm"}
+	fakeDeleteFuture = &infrav1.Future{Type: async.DeleteFuture, ServiceName: serviceName, ResourceGroup: "my-existing-rg", Name: "my-existing-vm"}
+)
+
 func TestGetExistingVM(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -73,6 +79,7 @@ func TestGetExistingVM(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder, mnic *mock_networkinterfaces.MockClientMockRecorder, mpip *mock_publicips.MockClientMockRecorder) {
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				mpip.Get(gomockinternal.AContext(), "my-rg", "my-publicIP-id").Return(network.PublicIPAddress{
 					PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
@@ -128,6 +135,7 @@ func TestGetExistingVM(t *testing.T) {
 			expectedError: "#: Not found: StatusCode=404",
 			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder, mnic *mock_networkinterfaces.MockClientMockRecorder, mpip *mock_publicips.MockClientMockRecorder) {
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(compute.VirtualMachine{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 			},
@@ -139,6 +147,7 @@ func TestGetExistingVM(t *testing.T) {
 			expectedError: "#: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder, mnic *mock_networkinterfaces.MockClientMockRecorder, mpip *mock_publicips.MockClientMockRecorder) {
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(compute.VirtualMachine{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
@@ -150,6 +159,7 @@ func TestGetExistingVM(t *testing.T) {
 			expectedError: "#: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder, mnic *mock_networkinterfaces.MockClientMockRecorder, mpip *mock_publicips.MockClientMockRecorder) {
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				mpip.Get(gomockinternal.AContext(), "my-rg", "my-publicIP-id").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 				mnic.Get(gomockinternal.AContext(), "my-rg", gomock.Any()).Return(network.Interface{
@@ -199,6 +209,7 @@ func TestGetExistingVM(t *testing.T) {
 			expectedError: "#: Not Found: StatusCode=404",
 			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder, mnic *mock_networkinterfaces.MockClientMockRecorder, mpip *mock_publicips.MockClientMockRecorder) {
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				mpip.Get(gomockinternal.AContext(), "my-rg", "my-publicIP-id").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
 				mnic.Get(gomockinternal.AContext(), "my-rg", gomock.Any()).Return(network.Interface{
@@ -334,6 +345,7 @@ func TestReconcileVM(t *testing.T) {
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.AdditionalTags()
 				s.Location().Return("test-location").AnyTimes()
@@ -351,7 +363,7 @@ func TestReconcileVM(t *testing.T) {
 				}, nil)
 				s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
 				s.AvailabilitySet().Return("", false)
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-vm", gomockinternal.DiffEq(compute.VirtualMachine{
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", gomockinternal.DiffEq(compute.VirtualMachine{
 					VirtualMachineProperties: &compute.VirtualMachineProperties{
 						HardwareProfile: &compute.HardwareProfile{VMSize: "Standard_D2v3"},
 						StorageProfile: &compute.StorageProfile{
@@ -446,7 +458,7 @@ func TestReconcileVM(t *testing.T) {
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
 						"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr("control-plane"),
 					},
-				}))
+				})).Return(fakeFuture, nil)
 			},
 			ExpectedError: "",
 			SetupSKUs: func(svc *Service) {
@@ -497,6 +509,7 @@ func TestReconcileVM(t *testing.T) {
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.AdditionalTags()
 				s.Location().Return("test-location").AnyTimes()
@@ -514,10 +527,10 @@ func TestReconcileVM(t *testing.T) {
 				}, nil)
 				s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
 				s.AvailabilitySet().Return("", false)
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.VirtualMachine{})).Do(func(_, _, _ interface{}, vm compute.VirtualMachine) {
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.VirtualMachine{})).Do(func(_, _, _ interface{}, vm compute.VirtualMachine) {
 					g.Expect(vm.Identity.Type).To(Equal(compute.ResourceIdentityTypeSystemAssigned))
 					g.Expect(vm.Identity.UserAssignedIdentities).To(HaveLen(0))
-				})
+				}).Return(fakeFuture, nil)
 			},
 			ExpectedError: "",
 			SetupSKUs: func(svc *Service) {
@@ -568,6 +581,7 @@ func TestReconcileVM(t *testing.T) {
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.AdditionalTags()
 				s.Location().Return("test-location").AnyTimes()
@@ -585,10 +599,10 @@ func TestReconcileVM(t *testing.T) {
 				}, nil)
 				s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
 				s.AvailabilitySet().Return("", false)
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.VirtualMachine{})).Do(func(_, _, _ interface{}, vm compute.VirtualMachine) {
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.VirtualMachine{})).Do(func(_, _, _ interface{}, vm compute.VirtualMachine) {
 					g.Expect(vm.Identity.Type).To(Equal(compute.ResourceIdentityTypeUserAssigned))
 					g.Expect(vm.Identity.UserAssignedIdentities).To(Equal(map[string]*compute.VirtualMachineIdentityUserAssignedIdentitiesValue{"my-user-id": {}}))
-				})
+				}).Return(fakeFuture, nil)
 			},
 			ExpectedError: "",
 			SetupSKUs: func(svc *Service) {
@@ -639,6 +653,7 @@ func TestReconcileVM(t *testing.T) {
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.AdditionalTags()
 				s.Location().Return("test-location").AnyTimes()
@@ -656,11 +671,11 @@ func TestReconcileVM(t *testing.T) {
 				}, nil)
 				s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
 				s.AvailabilitySet().Return("", false)
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.VirtualMachine{})).Do(func(_, _, _ interface{}, vm compute.VirtualMachine) {
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.VirtualMachine{})).Do(func(_, _, _ interface{}, vm compute.VirtualMachine) {
 					g.Expect(vm.Priority).To(Equal(compute.Spot))
 					g.Expect(vm.EvictionPolicy).To(Equal(compute.Deallocate))
 					g.Expect(vm.BillingProfile).To(BeNil())
-				})
+				}).Return(fakeFuture, nil)
 			},
 			ExpectedError: "",
 			SetupSKUs: func(svc *Service) {
@@ -725,6 +740,7 @@ func TestReconcileVM(t *testing.T) {
 				)
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.AdditionalTags()
 				s.Location().Return("test-location").AnyTimes()
@@ -740,14 +756,15 @@ func TestReconcileVM(t *testing.T) {
 						Version:   "1.0",
 					},
 				}, nil)
+				s.SetAnnotation(azure.WindowsBootstrapExtensionAnnotation, "true")
 				s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
 				s.AvailabilitySet().Return("", false)
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.VirtualMachine{})).Do(func(_, _, _ interface{}, vm compute.VirtualMachine) {
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.VirtualMachine{})).Do(func(_, _, _ interface{}, vm compute.VirtualMachine) {
 					g.Expect(vm.VirtualMachineProperties.StorageProfile.OsDisk.OsType).To(Equal(compute.Windows))
 					g.Expect(*vm.VirtualMachineProperties.OsProfile.AdminPassword).Should(HaveLen(123))
 					g.Expect(*vm.VirtualMachineProperties.OsProfile.AdminUsername).Should(Equal("capi"))
 					g.Expect(*vm.VirtualMachineProperties.OsProfile.WindowsConfiguration.EnableAutomaticUpdates).Should(Equal(false))
-				})
+				}).Return(fakeFuture, nil)
 			},
 			ExpectedError: "",
 			SetupSKUs: func(svc *Service) {
@@ -804,6 +821,7 @@ func TestReconcileVM(t *testing.T) {
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.AdditionalTags()
 				s.Location().Return("test-location").AnyTimes()
@@ -821,9 +839,9 @@ func TestReconcileVM(t *testing.T) {
 				}, nil)
 				s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
 				s.AvailabilitySet().Return("", false)
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.VirtualMachine{})).Do(func(_, _, _ interface{}, vm compute.VirtualMachine) {
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.VirtualMachine{})).Do(func(_, _, _ interface{}, vm compute.VirtualMachine) {
 					g.Expect(vm.VirtualMachineProperties.StorageProfile.OsDisk.ManagedDisk.DiskEncryptionSet.ID).To(Equal(to.StringPtr("my-diskencryptionset-id")))
-				})
+				}).Return(fakeFuture, nil)
 			},
 			ExpectedError: "",
 			SetupSKUs: func(svc *Service) {
@@ -871,6 +889,7 @@ func TestReconcileVM(t *testing.T) {
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.AdditionalTags()
 				s.Location().Return("test-location").AnyTimes()
@@ -888,9 +907,9 @@ func TestReconcileVM(t *testing.T) {
 				}, nil)
 				s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
 				s.AvailabilitySet().Return("", false)
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.VirtualMachine{})).Do(func(_, _, _ interface{}, vm compute.VirtualMachine) {
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.VirtualMachine{})).Do(func(_, _, _ interface{}, vm compute.VirtualMachine) {
 					g.Expect(*vm.VirtualMachineProperties.SecurityProfile.EncryptionAtHost).To(Equal(true))
-				})
+				}).Return(fakeFuture, nil)
 			},
 			ExpectedError: "",
 			SetupSKUs: func(svc *Service) {
@@ -957,6 +976,7 @@ func TestReconcileVM(t *testing.T) {
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.AdditionalTags()
 				s.Location().Return("test-location").AnyTimes()
@@ -974,7 +994,7 @@ func TestReconcileVM(t *testing.T) {
 				}, nil)
 				s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
 				s.AvailabilitySet().Return("as-name", true)
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-vm", gomockinternal.DiffEq(compute.VirtualMachine{
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", gomockinternal.DiffEq(compute.VirtualMachine{
 					VirtualMachineProperties: &compute.VirtualMachineProperties{
 						HardwareProfile: &compute.HardwareProfile{VMSize: "Standard_D2v3"},
 						StorageProfile: &compute.StorageProfile{
@@ -1050,7 +1070,7 @@ func TestReconcileVM(t *testing.T) {
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
 						"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr("control-plane"),
 					},
-				}))
+				})).Return(fakeFuture, nil)
 			},
 			ExpectedError: "",
 			SetupSKUs: func(svc *Service) {
@@ -1096,6 +1116,7 @@ func TestReconcileVM(t *testing.T) {
 					SecurityProfile: &infrav1.SecurityProfile{EncryptionAtHost: to.BoolPtr(true)},
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.GetVMImage().AnyTimes().Return(&infrav1.Image{
 					Marketplace: &infrav1.AzureMarketplaceImage{
@@ -1159,6 +1180,7 @@ func TestReconcileVM(t *testing.T) {
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.AdditionalTags()
 				s.Location().Return("test-location").AnyTimes()
@@ -1176,7 +1198,7 @@ func TestReconcileVM(t *testing.T) {
 				}, nil)
 				s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
 				s.AvailabilitySet().Return("", false)
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.VirtualMachine{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.VirtualMachine{})).Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 			ExpectedError: "failed to create VM my-vm in resource group my-rg: #: Internal Server Error: StatusCode=500",
 			SetupSKUs: func(svc *Service) {
@@ -1239,6 +1261,7 @@ func TestReconcileVM(t *testing.T) {
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.ProviderID().Return("")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm").
@@ -1305,6 +1328,7 @@ func TestReconcileVM(t *testing.T) {
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.ProviderID().Return("")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm").
@@ -1374,6 +1398,7 @@ func TestReconcileVM(t *testing.T) {
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.ProviderID().Return("")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm").
@@ -1447,6 +1472,7 @@ func TestReconcileVM(t *testing.T) {
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.AdditionalTags()
 				s.Location().Return("test-location").AnyTimes()
@@ -1464,7 +1490,7 @@ func TestReconcileVM(t *testing.T) {
 				}, nil)
 				s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
 				s.AvailabilitySet().Return("", false)
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-vm", gomockinternal.DiffEq(compute.VirtualMachine{
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", gomockinternal.DiffEq(compute.VirtualMachine{
 					VirtualMachineProperties: &compute.VirtualMachineProperties{
 						HardwareProfile: &compute.HardwareProfile{VMSize: "Standard_D2v3"},
 						StorageProfile: &compute.StorageProfile{
@@ -1541,7 +1567,7 @@ func TestReconcileVM(t *testing.T) {
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
 						"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr("control-plane"),
 					},
-				}))
+				})).Return(fakeFuture, nil)
 			},
 			ExpectedError: "",
 			SetupSKUs: func(svc *Service) {
@@ -1608,6 +1634,7 @@ func TestReconcileVM(t *testing.T) {
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.AdditionalTags()
 				s.Location().Return("test-location").AnyTimes()
@@ -1626,7 +1653,7 @@ func TestReconcileVM(t *testing.T) {
 				}, nil)
 				s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
 				s.AvailabilitySet().Return("", false)
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-vm", gomockinternal.DiffEq(compute.VirtualMachine{
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", gomockinternal.DiffEq(compute.VirtualMachine{
 					Plan: &compute.Plan{
 						Name:      to.StringPtr("sku-id"),
 						Publisher: to.StringPtr("fake-publisher"),
@@ -1705,7 +1732,7 @@ func TestReconcileVM(t *testing.T) {
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
 						"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr("control-plane"),
 					},
-				}))
+				})).Return(fakeFuture, nil)
 			},
 			ExpectedError: "",
 			SetupSKUs: func(svc *Service) {
@@ -1746,6 +1773,7 @@ func TestReconcileVM(t *testing.T) {
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.ProviderID().Times(2).Return("ExistingVM-ProviderID")
 				s.SetVMState(infrav1.Deleted)
@@ -1785,6 +1813,7 @@ func TestReconcileVM(t *testing.T) {
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.AdditionalTags()
 				s.Location().Return("test-location").AnyTimes()
@@ -1806,7 +1835,7 @@ func TestReconcileVM(t *testing.T) {
 				}, nil)
 				s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
 				s.AvailabilitySet().Return("", false)
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-vm", gomockinternal.DiffEq(compute.VirtualMachine{
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", gomockinternal.DiffEq(compute.VirtualMachine{
 					Plan: &compute.Plan{
 						Name:      to.StringPtr("sku-id"),
 						Publisher: to.StringPtr("fake-publisher"),
@@ -1882,7 +1911,7 @@ func TestReconcileVM(t *testing.T) {
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
 						"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr("control-plane"),
 					},
-				}))
+				})).Return(fakeFuture, nil)
 			},
 			ExpectedError: "",
 			SetupSKUs: func(svc *Service) {
@@ -1973,6 +2002,7 @@ func TestReconcileVM(t *testing.T) {
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.AdditionalTags()
 				s.Location().Return("test-location").AnyTimes()
@@ -1990,7 +2020,7 @@ func TestReconcileVM(t *testing.T) {
 				}, nil)
 				s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
 				s.AvailabilitySet().Return("", false)
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-ultra-ssd-vm", gomockinternal.DiffEq(compute.VirtualMachine{
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-ultra-ssd-vm", gomockinternal.DiffEq(compute.VirtualMachine{
 					VirtualMachineProperties: &compute.VirtualMachineProperties{
 						HardwareProfile: &compute.HardwareProfile{VMSize: "Standard_D2v3"},
 						StorageProfile: &compute.StorageProfile{
@@ -2095,7 +2125,7 @@ func TestReconcileVM(t *testing.T) {
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
 						"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr("control-plane"),
 					},
-				}))
+				})).Return(fakeFuture, nil)
 			},
 			ExpectedError: "",
 			SetupSKUs: func(svc *Service) {
@@ -2173,6 +2203,7 @@ func TestReconcileVM(t *testing.T) {
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.Location().Return("test-location").AnyTimes()
 				s.ProviderID().Return("")
@@ -2210,6 +2241,21 @@ func TestReconcileVM(t *testing.T) {
 				svc.resourceSKUCache = resourceSkusCache
 			},
 		},
+		{
+			Name: "vm creation started by a previous reconcile still in progress",
+			Expect: func(g *WithT, s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder, mnic *mock_networkinterfaces.MockClientMockRecorder, mpip *mock_publicips.MockClientMockRecorder) {
+				s.VMSpec().Return(azure.VMSpec{
+					Name: "my-vm",
+					Size: "Standard_D2v3",
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState("my-vm", serviceName).Return(fakeFuture)
+				m.IsDone(gomockinternal.AContext(), fakeFuture).Return(false, nil)
+			},
+			ExpectedError: "failed to create VM my-vm in resource group my-rg: transient reconcile error occurred: operation type PUT on Azure resource my-rg/my-vm is not done. Object will be requeued after 15s",
+			SetupSKUs:     func(svc *Service) {},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -2227,6 +2273,11 @@ func TestReconcileVM(t *testing.T) {
 			availabilitySetsMock := mock_availabilitysets.NewMockClient(mockCtrl)
 
 			tc.Expect(g, scopeMock.EXPECT(), clientMock.EXPECT(), interfaceMock.EXPECT(), publicIPMock.EXPECT())
+			// unless a test case expects otherwise, no operation is in progress and the VM creation completes right away
+			scopeMock.EXPECT().GetLongRunningOperationState(gomock.Any(), serviceName).AnyTimes().Return(nil)
+			scopeMock.EXPECT().SetLongRunningOperationState(gomock.Any()).AnyTimes()
+			scopeMock.EXPECT().DeleteLongRunningOperationState(gomock.Any(), serviceName).AnyTimes()
+			clientMock.EXPECT().IsDone(gomockinternal.AContext(), gomock.Any()).AnyTimes().Return(true, nil)
 
 			s := &Service{
 				Scope:                  scopeMock,
@@ -2274,8 +2325,12 @@ func TestDeleteVM(t *testing.T) {
 					SpotVMOptions:          nil,
 				})
 				s.ResourceGroup().AnyTimes().Return("my-existing-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-existing-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				m.Delete(gomockinternal.AContext(), "my-existing-rg", "my-existing-vm")
+				m.DeleteAsync(gomockinternal.AContext(), "my-existing-rg", "my-existing-vm").Return(fakeDeleteFuture, nil)
+				s.SetLongRunningOperationState(fakeDeleteFuture)
+				m.IsDone(gomockinternal.AContext(), fakeDeleteFuture).Return(true, nil)
+				s.DeleteLongRunningOperationState("my-existing-vm", serviceName)
 			},
 		},
 		{
@@ -2296,9 +2351,39 @@ func TestDeleteVM(t *testing.T) {
 					SpotVMOptions:          nil,
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				m.DeleteAsync(gomockinternal.AContext(), "my-rg", "my-vm").
+					Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "vm deletion in progress",
+			expectedError: "failed to delete VM my-vm in resource group my-rg: transient reconcile error occurred: operation type DELETE on Azure resource my-rg/my-vm is not done. Object will be requeued after 15s",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				future := &infrav1.Future{Type: async.DeleteFuture, ServiceName: serviceName, ResourceGroup: "my-rg", Name: "my-vm"}
+				s.VMSpec().Return(azure.VMSpec{
+					Name: "my-vm",
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				m.DeleteAsync(gomockinternal.AContext(), "my-rg", "my-vm").Return(future, nil)
+				s.SetLongRunningOperationState(future)
+				m.IsDone(gomockinternal.AContext(), future).Return(false, nil)
+			},
+		},
+		{
+			name:          "vm deletion started by a previous reconcile is done",
+			expectedError: "",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.VMSpec().Return(azure.VMSpec{
+					Name: "my-existing-vm",
+				})
+				s.ResourceGroup().AnyTimes().Return("my-existing-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				m.Delete(gomockinternal.AContext(), "my-rg", "my-vm").
-					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				s.GetLongRunningOperationState("my-existing-vm", serviceName).Return(fakeDeleteFuture)
+				m.IsDone(gomockinternal.AContext(), fakeDeleteFuture).Return(true, nil)
+				s.DeleteLongRunningOperationState("my-existing-vm", serviceName)
 			},
 		},
 		{
@@ -2319,9 +2404,10 @@ func TestDeleteVM(t *testing.T) {
 					SpotVMOptions:          nil,
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				m.Delete(gomockinternal.AContext(), "my-rg", "my-vm").
-					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				m.DeleteAsync(gomockinternal.AContext(), "my-rg", "my-vm").
+					Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
 	}
@@ -2337,6 +2423,7 @@ func TestDeleteVM(t *testing.T) {
 			clientMock := mock_virtualmachines.NewMockClient(mockCtrl)
 
 			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+			scopeMock.EXPECT().GetLongRunningOperationState(gomock.Any(), serviceName).AnyTimes().Return(nil)
 
 			s := &Service{
 				Scope:  scopeMock,
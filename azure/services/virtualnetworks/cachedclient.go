@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetworks
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// cacheTimeToLive is how long a virtual network read from Azure is served from the cache before it is read again.
+const cacheTimeToLive = 2 * time.Minute
+
+var (
+	doOnce      sync.Once
+	clientCache ttllru.PeekingCacher
+	cacheErr    error
+)
+
+// CachedClient is a Client which serves virtual network gets from a TTL cache shared across reconciles. It is meant
+// for services which only read virtual networks, to avoid spending the ARM read quota on data which rarely changes.
+type CachedClient struct {
+	Client
+	cache   ttllru.PeekingCacher
+	hashKey string
+}
+
+var _ Client = &CachedClient{}
+
+// NewCachedClient creates a new vnet client which caches gets in the cache shared by all the cached vnet clients.
+func NewCachedClient(auth azure.Authorizer) (*CachedClient, error) {
+	cache, err := sharedCache()
+	if err != nil {
+		return nil, err
+	}
+
+	return newCachedClient(NewClient(auth), cache, auth.HashKey()), nil
+}
+
+// EvictCached evicts a virtual network from the cache shared by all the cached vnet clients. It is meant for the
+// clients which write the vnet indirectly, e.g. through its subnets, so that the next get reads the vnet from Azure.
+func EvictCached(auth azure.Authorizer, resourceGroupName, vnetName string) {
+	if cache, err := sharedCache(); err == nil {
+		cache.Remove(cacheKey(auth.HashKey(), resourceGroupName, vnetName))
+	}
+}
+
+// sharedCache returns the cache shared by all the cached vnet clients, creating it on first use.
+func sharedCache() (ttllru.PeekingCacher, error) {
+	doOnce.Do(func() {
+		clientCache, cacheErr = ttllru.New(256, cacheTimeToLive)
+	})
+	if cacheErr != nil {
+		return nil, errors.Wrap(cacheErr, "failed creating LRU cache for virtual networks")
+	}
+	return clientCache, nil
+}
+
+// newCachedClient creates a new cached vnet client on top of the provided client and cache.
+func newCachedClient(client Client, cache ttllru.PeekingCacher, hashKey string) *CachedClient {
+	return &CachedClient{
+		Client:  client,
+		cache:   cache,
+		hashKey: hashKey,
+	}
+}
+
+// Get gets the specified virtual network by resource group, from the cache if it was read recently. Only the vnets
+// whose provisioning state is Succeeded are cached, so that a vnet being created, updated or deleted is read again.
+func (cc *CachedClient) Get(ctx context.Context, resourceGroupName, vnetName string) (network.VirtualNetwork, error) {
+	ctx, span := tele.Tracer().Start(ctx, "virtualnetworks.CachedClient.Get")
+	defer span.End()
+
+	key := cc.key(resourceGroupName, vnetName)
+	// Peek does not extend the expiration of the item, so that a vnet read in every reconcile is still refreshed.
+	if cached, _, ok := cc.cache.Peek(key); ok {
+		if vnet, ok := cached.(network.VirtualNetwork); ok {
+			return vnet, nil
+		}
+	}
+
+	vnet, err := cc.Client.Get(ctx, resourceGroupName, vnetName)
+	if err != nil {
+		return vnet, err
+	}
+	if vnet.VirtualNetworkPropertiesFormat != nil && vnet.VirtualNetworkPropertiesFormat.ProvisioningState == network.ProvisioningStateSucceeded {
+		_ = cc.cache.Add(key, vnet)
+	}
+	return vnet, nil
+}
+
+// CreateOrUpdateAsync starts creating or updating a virtual network and evicts it from the cache.
+func (cc *CachedClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupName, vnetName string, vn network.VirtualNetwork) (*infrav1.Future, error) {
+	defer cc.cache.Remove(cc.key(resourceGroupName, vnetName))
+	return cc.Client.CreateOrUpdateAsync(ctx, resourceGroupName, vnetName, vn)
+}
+
+// DeleteAsync starts deleting a virtual network and evicts it from the cache.
+func (cc *CachedClient) DeleteAsync(ctx context.Context, resourceGroupName, vnetName string) (*infrav1.Future, error) {
+	defer cc.cache.Remove(cc.key(resourceGroupName, vnetName))
+	return cc.Client.DeleteAsync(ctx, resourceGroupName, vnetName)
+}
+
+// IsDone returns true if the long-running operation has completed. The vnet of the operation is evicted from the cache
+// while the operation is tracked and once it completes, as the operation changes it.
+func (cc *CachedClient) IsDone(ctx context.Context, future *infrav1.Future) (bool, error) {
+	defer cc.cache.Remove(cc.key(future.ResourceGroup, future.Name))
+	return cc.Client.IsDone(ctx, future)
+}
+
+// key returns the cache key of a vnet, which includes the hash of the credentials and subscription used to read it.
+func (cc *CachedClient) key(resourceGroupName, vnetName string) string {
+	return cacheKey(cc.hashKey, resourceGroupName, vnetName)
+}
+
+// cacheKey returns the cache key of a vnet read with the credentials and subscription of the given hash.
+func cacheKey(hashKey, resourceGroupName, vnetName string) string {
+	return strings.Join([]string{hashKey, strings.ToLower(resourceGroupName), strings.ToLower(vnetName)}, "/")
+}
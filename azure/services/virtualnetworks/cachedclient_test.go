@@ -0,0 +1,242 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetworks
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/mocks"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks/mock_virtualnetworks"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+)
+
+func TestCachedClientGet(t *testing.T) {
+	vnet := network.VirtualNetwork{
+		ID: to.StringPtr("subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"),
+		VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+			ProvisioningState: network.ProvisioningStateSucceeded,
+		},
+	}
+	updatingVnet := network.VirtualNetwork{
+		ID: vnet.ID,
+		VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+			ProvisioningState: network.ProvisioningStateUpdating,
+		},
+	}
+	notFound := autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found")
+
+	testcases := []struct {
+		name   string
+		calls  func(g *WithT, cc *CachedClient)
+		expect func(m *mock_virtualnetworks.MockClientMockRecorder)
+	}{
+		{
+			name: "vnet is read from Azure once and then served from the cache",
+			calls: func(g *WithT, cc *CachedClient) {
+				for i := 0; i < 3; i++ {
+					got, err := cc.Get(context.TODO(), "my-rg", "my-vnet")
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(got).To(Equal(vnet))
+				}
+			},
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vnet").Times(1).Return(vnet, nil)
+			},
+		},
+		{
+			name: "vnets are cached per resource group and name, ignoring case",
+			calls: func(g *WithT, cc *CachedClient) {
+				_, err := cc.Get(context.TODO(), "my-rg", "my-vnet")
+				g.Expect(err).NotTo(HaveOccurred())
+				_, err = cc.Get(context.TODO(), "my-rg", "other-vnet")
+				g.Expect(err).NotTo(HaveOccurred())
+				_, err = cc.Get(context.TODO(), "MY-RG", "My-Vnet")
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vnet").Times(1).Return(vnet, nil)
+				m.Get(gomockinternal.AContext(), "my-rg", "other-vnet").Times(1).Return(network.VirtualNetwork{}, nil)
+			},
+		},
+		{
+			name: "errors are not cached",
+			calls: func(g *WithT, cc *CachedClient) {
+				_, err := cc.Get(context.TODO(), "my-rg", "my-vnet")
+				g.Expect(err).To(HaveOccurred())
+				got, err := cc.Get(context.TODO(), "my-rg", "my-vnet")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(got).To(Equal(vnet))
+			},
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				gomock.InOrder(
+					m.Get(gomockinternal.AContext(), "my-rg", "my-vnet").Return(network.VirtualNetwork{}, notFound),
+					m.Get(gomockinternal.AContext(), "my-rg", "my-vnet").Return(vnet, nil),
+				)
+			},
+		},
+		{
+			name: "vnet is not cached during an in-flight update",
+			calls: func(g *WithT, cc *CachedClient) {
+				got, err := cc.Get(context.TODO(), "my-rg", "my-vnet")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(got).To(Equal(updatingVnet))
+				for i := 0; i < 2; i++ {
+					got, err = cc.Get(context.TODO(), "my-rg", "my-vnet")
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(got).To(Equal(vnet))
+				}
+			},
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				gomock.InOrder(
+					m.Get(gomockinternal.AContext(), "my-rg", "my-vnet").Return(updatingVnet, nil),
+					m.Get(gomockinternal.AContext(), "my-rg", "my-vnet").Times(1).Return(vnet, nil),
+				)
+			},
+		},
+		{
+			name: "checking on a long-running operation evicts its vnet from the cache",
+			calls: func(g *WithT, cc *CachedClient) {
+				_, err := cc.Get(context.TODO(), "my-rg", "my-vnet")
+				g.Expect(err).NotTo(HaveOccurred())
+				done, err := cc.IsDone(context.TODO(), &infrav1.Future{ResourceGroup: "my-rg", Name: "my-vnet"})
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(done).To(BeTrue())
+				_, err = cc.Get(context.TODO(), "my-rg", "my-vnet")
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				gomock.InOrder(
+					m.Get(gomockinternal.AContext(), "my-rg", "my-vnet").Return(vnet, nil),
+					m.IsDone(gomockinternal.AContext(), &infrav1.Future{ResourceGroup: "my-rg", Name: "my-vnet"}).Return(true, nil),
+					m.Get(gomockinternal.AContext(), "my-rg", "my-vnet").Return(vnet, nil),
+				)
+			},
+		},
+		{
+			name: "creating a vnet evicts it from the cache",
+			calls: func(g *WithT, cc *CachedClient) {
+				_, err := cc.Get(context.TODO(), "my-rg", "my-vnet")
+				g.Expect(err).NotTo(HaveOccurred())
+				_, err = cc.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-vnet", vnet)
+				g.Expect(err).NotTo(HaveOccurred())
+				_, err = cc.Get(context.TODO(), "my-rg", "my-vnet")
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				gomock.InOrder(
+					m.Get(gomockinternal.AContext(), "my-rg", "my-vnet").Return(vnet, nil),
+					m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vnet", vnet).Return(&infrav1.Future{}, nil),
+					m.Get(gomockinternal.AContext(), "my-rg", "my-vnet").Return(vnet, nil),
+				)
+			},
+		},
+		{
+			name: "deleting a vnet evicts it from the cache",
+			calls: func(g *WithT, cc *CachedClient) {
+				_, err := cc.Get(context.TODO(), "my-rg", "my-vnet")
+				g.Expect(err).NotTo(HaveOccurred())
+				_, err = cc.DeleteAsync(context.TODO(), "my-rg", "my-vnet")
+				g.Expect(err).NotTo(HaveOccurred())
+				_, err = cc.Get(context.TODO(), "my-rg", "my-vnet")
+				g.Expect(err).To(HaveOccurred())
+			},
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				gomock.InOrder(
+					m.Get(gomockinternal.AContext(), "my-rg", "my-vnet").Return(vnet, nil),
+					m.DeleteAsync(gomockinternal.AContext(), "my-rg", "my-vnet").Return(&infrav1.Future{}, nil),
+					m.Get(gomockinternal.AContext(), "my-rg", "my-vnet").Return(network.VirtualNetwork{}, notFound),
+				)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			clientMock := mock_virtualnetworks.NewMockClient(mockCtrl)
+
+			tc.expect(clientMock.EXPECT())
+
+			cache, err := ttllru.New(16, time.Minute)
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.calls(g, newCachedClient(clientMock, cache, "hash"))
+		})
+	}
+}
+
+func TestEvictCached(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_virtualnetworks.NewMockClient(mockCtrl)
+	authMock := mocks.NewMockAuthorizer(mockCtrl)
+	authMock.EXPECT().HashKey().Return("evict-hash")
+	vnet := network.VirtualNetwork{
+		VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+			ProvisioningState: network.ProvisioningStateSucceeded,
+		},
+	}
+	clientMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "my-vnet").Times(2).Return(vnet, nil)
+
+	cache, err := sharedCache()
+	g.Expect(err).NotTo(HaveOccurred())
+	cc := newCachedClient(clientMock, cache, "evict-hash")
+
+	_, err = cc.Get(context.TODO(), "my-rg", "my-vnet")
+	g.Expect(err).NotTo(HaveOccurred())
+	EvictCached(authMock, "my-rg", "my-vnet")
+	_, err = cc.Get(context.TODO(), "my-rg", "my-vnet")
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestCachedClientGetExpires(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_virtualnetworks.NewMockClient(mockCtrl)
+	vnet := network.VirtualNetwork{
+		VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+			ProvisioningState: network.ProvisioningStateSucceeded,
+		},
+	}
+	clientMock.EXPECT().Get(gomockinternal.AContext(), "my-rg", "my-vnet").Times(2).Return(vnet, nil)
+
+	cache, err := ttllru.New(16, 10*time.Millisecond)
+	g.Expect(err).NotTo(HaveOccurred())
+	cc := newCachedClient(clientMock, cache, "hash")
+
+	_, err = cc.Get(context.TODO(), "my-rg", "my-vnet")
+	g.Expect(err).NotTo(HaveOccurred())
+	time.Sleep(20 * time.Millisecond)
+	_, err = cc.Get(context.TODO(), "my-rg", "my-vnet")
+	g.Expect(err).NotTo(HaveOccurred())
+}
@@ -22,15 +22,18 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
 	"github.com/Azure/go-autorest/autorest"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 // Client wraps go-sdk.
 type Client interface {
 	Get(context.Context, string, string) (network.VirtualNetwork, error)
-	CreateOrUpdate(context.Context, string, string, network.VirtualNetwork) error
-	Delete(context.Context, string, string) error
+	CreateOrUpdateAsync(context.Context, string, string, network.VirtualNetwork) (*infrav1.Future, error)
+	DeleteAsync(context.Context, string, string) (*infrav1.Future, error)
+	IsDone(context.Context, *infrav1.Future) (bool, error)
 	CheckIPAddressAvailability(context.Context, string, string, string) (network.IPAddressAvailabilityResult, error)
 }
 
@@ -64,38 +67,38 @@ func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vnetName stri
 	return ac.virtualnetworks.Get(ctx, resourceGroupName, vnetName, "")
 }
 
-// CreateOrUpdate creates or updates a virtual network in the specified resource group.
-func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, vnetName string, vn network.VirtualNetwork) error {
-	ctx, span := tele.Tracer().Start(ctx, "virtualnetworks.AzureClient.CreateOrUpdate")
+// CreateOrUpdateAsync starts creating or updating a virtual network in the specified resource group, and returns the
+// future of the long-running operation without waiting for it to complete.
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupName, vnetName string, vn network.VirtualNetwork) (*infrav1.Future, error) {
+	ctx, span := tele.Tracer().Start(ctx, "virtualnetworks.AzureClient.CreateOrUpdateAsync")
 	defer span.End()
 
 	future, err := ac.virtualnetworks.CreateOrUpdate(ctx, resourceGroupName, vnetName, vn)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = future.WaitForCompletionRef(ctx, ac.virtualnetworks.Client)
-	if err != nil {
-		return err
-	}
-	_, err = future.Result(ac.virtualnetworks)
-	return err
+	return async.NewFuture(&future, async.PutFuture, serviceName, resourceGroupName, vnetName)
 }
 
-// Delete deletes the specified virtual network.
-func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, vnetName string) error {
-	ctx, span := tele.Tracer().Start(ctx, "virtualnetworks.AzureClient.Delete")
+// DeleteAsync starts deleting the specified virtual network, and returns the future of the long-running operation
+// without waiting for it to complete.
+func (ac *AzureClient) DeleteAsync(ctx context.Context, resourceGroupName, vnetName string) (*infrav1.Future, error) {
+	ctx, span := tele.Tracer().Start(ctx, "virtualnetworks.AzureClient.DeleteAsync")
 	defer span.End()
 
 	future, err := ac.virtualnetworks.Delete(ctx, resourceGroupName, vnetName)
 	if err != nil {
-		return err
-	}
-	err = future.WaitForCompletionRef(ctx, ac.virtualnetworks.Client)
-	if err != nil {
-		return err
+		return nil, err
 	}
-	_, err = future.Result(ac.virtualnetworks)
-	return err
+	return async.NewFuture(&future, async.DeleteFuture, serviceName, resourceGroupName, vnetName)
+}
+
+// IsDone returns true if the long-running operation of the future is done.
+func (ac *AzureClient) IsDone(ctx context.Context, future *infrav1.Future) (bool, error) {
+	ctx, span := tele.Tracer().Start(ctx, "virtualnetworks.AzureClient.IsDone")
+	defer span.End()
+
+	return async.IsDone(ctx, ac.virtualnetworks, future)
 }
 
 // CheckIPAddressAvailability checks whether a private IP address is available for use.
@@ -27,13 +27,17 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+const serviceName = "virtualnetworks"
+
 // VNetScope defines the scope interface for a virtual network service.
 type VNetScope interface {
 	logr.Logger
 	azure.ClusterDescriber
+	azure.AsyncStatusUpdater
 	Vnet() *infrav1.VnetSpec
 	VNetSpec() azure.VNetSpec
 }
@@ -46,10 +50,19 @@ type Service struct {
 
 // New creates a new service.
 func New(scope VNetScope) *Service {
-	return &Service{
+	svc := &Service{
 		Scope:  scope,
 		Client: NewClient(scope),
 	}
+
+	// The vnet is read on every reconcile of the cluster but rarely changes: serve it from the cache shared across
+	// reconciles. The cached client evicts the vnet when it is created or deleted, and while the operation is in progress.
+	if client, err := NewCachedClient(scope); err == nil {
+		svc.Client = client
+	} else {
+		scope.Error(err, "failed to create cached virtual networks client, falling back to uncached reads")
+	}
+	return svc
 }
 
 // Reconcile gets/creates/updates a virtual network.
@@ -66,6 +79,14 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	//    * Node NSG
 	//    * Node Route Table
 	vnetSpec := s.Scope.VNetSpec()
+
+	// check on a long-running operation started by a previous reconciliation loop, and requeue while it is in progress
+	if future := s.Scope.GetLongRunningOperationState(vnetSpec.Name, serviceName); future != nil {
+		if err := async.CheckOperation(ctx, s.Scope, s.Client, future); err != nil {
+			return errors.Wrapf(err, "failed to create virtual network %s", vnetSpec.Name)
+		}
+	}
+
 	existingVnet, err := s.getExisting(ctx, vnetSpec)
 
 	switch {
@@ -97,8 +118,12 @@ func (s *Service) Reconcile(ctx context.Context) error {
 				},
 			},
 		}
-		err = s.Client.CreateOrUpdate(ctx, vnetSpec.ResourceGroup, vnetSpec.Name, vnetProperties)
+		future, err := s.Client.CreateOrUpdateAsync(ctx, vnetSpec.ResourceGroup, vnetSpec.Name, vnetProperties)
 		if err != nil {
+			azure.RecordCreate(ctx, async.ResourceKind(serviceName), vnetSpec.Name, err)
+			return errors.Wrapf(err, "failed to create virtual network %s", vnetSpec.Name)
+		}
+		if err := async.TrackOperation(ctx, s.Scope, s.Client, future); err != nil {
 			return errors.Wrapf(err, "failed to create virtual network %s", vnetSpec.Name)
 		}
 		s.Scope.V(2).Info("successfully created VNet", "VNet", vnetSpec.Name)
@@ -113,6 +138,18 @@ func (s *Service) Delete(ctx context.Context) error {
 	defer span.End()
 
 	vnetSpec := s.Scope.VNetSpec()
+
+	// check on a long-running operation started by a previous reconciliation loop, and requeue while it is in progress
+	if future := s.Scope.GetLongRunningOperationState(vnetSpec.Name, serviceName); future != nil {
+		if err := async.CheckOperation(ctx, s.Scope, s.Client, future); err != nil {
+			return errors.Wrapf(err, "failed to delete VNet %s in resource group %s", vnetSpec.Name, vnetSpec.ResourceGroup)
+		}
+		if future.Type == async.DeleteFuture {
+			s.Scope.V(2).Info("successfully deleted VNet", "VNet", vnetSpec.Name)
+			return nil
+		}
+	}
+
 	existingVnet, err := s.getExisting(ctx, vnetSpec)
 	if azure.ResourceNotFound(err) {
 		// vnet does not exist, there is nothing to delete
@@ -125,13 +162,17 @@ func (s *Service) Delete(ctx context.Context) error {
 	}
 
 	s.Scope.V(2).Info("deleting VNet", "VNet", vnetSpec.Name)
-	err = s.Client.Delete(ctx, vnetSpec.ResourceGroup, vnetSpec.Name)
+	future, err := s.Client.DeleteAsync(ctx, vnetSpec.ResourceGroup, vnetSpec.Name)
 	if err != nil {
 		if azure.ResourceGroupNotFound(err) || azure.ResourceNotFound(err) {
 			return nil
 		}
 	}
 	if err != nil {
+		azure.RecordDelete(ctx, async.ResourceKind(serviceName), vnetSpec.Name, err)
+		return errors.Wrapf(err, "failed to delete VNet %s in resource group %s", vnetSpec.Name, vnetSpec.ResourceGroup)
+	}
+	if err := async.TrackOperation(ctx, s.Scope, s.Client, future); err != nil {
 		return errors.Wrapf(err, "failed to delete VNet %s in resource group %s", vnetSpec.Name, vnetSpec.ResourceGroup)
 	}
 
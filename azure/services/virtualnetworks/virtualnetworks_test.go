@@ -30,10 +30,16 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks/mock_virtualnetworks"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 )
 
+var (
+	fakeFuture       = &infrav1.Future{Type: async.PutFuture, ServiceName: serviceName, ResourceGroup: "my-rg", Name: "vnet-new"}
+	fakeDeleteFuture = &infrav1.Future{Type: async.DeleteFuture, ServiceName: serviceName, ResourceGroup: "my-rg", Name: "vnet-exists"}
+)
+
 func TestReconcileVnet(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -45,6 +51,7 @@ func TestReconcileVnet(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState(gomock.Any(), serviceName).Return(nil)
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "vnet-exists"})
 				s.VNetSpec().Return(azure.VNetSpec{
@@ -74,6 +81,7 @@ func TestReconcileVnet(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState(gomock.Any(), serviceName).Return(nil)
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "vnet-exists"})
 				s.VNetSpec().Return(azure.VNetSpec{
@@ -106,6 +114,7 @@ func TestReconcileVnet(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState(gomock.Any(), serviceName).Return(nil)
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.Location().AnyTimes().Return("fake-location")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
@@ -118,7 +127,45 @@ func TestReconcileVnet(t *testing.T) {
 				m.Get(gomockinternal.AContext(), "my-rg", "vnet-new").
 					Return(network.VirtualNetwork{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "vnet-new", gomock.AssignableToTypeOf(network.VirtualNetwork{}))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "vnet-new", gomock.AssignableToTypeOf(network.VirtualNetwork{})).Return(fakeFuture, nil)
+				s.SetLongRunningOperationState(fakeFuture)
+				m.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, nil)
+				s.DeleteLongRunningOperationState(fakeFuture.Name, serviceName)
+			},
+		},
+		{
+			name:          "vnet creation in progress",
+			expectedError: "failed to create virtual network vnet-new: transient reconcile error occurred: operation type PUT on Azure resource my-rg/vnet-new is not done. Object will be requeued after 15s",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState(gomock.Any(), serviceName).Return(nil)
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				s.Location().AnyTimes().Return("fake-location")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.VNetSpec().Return(azure.VNetSpec{
+					ResourceGroup: "my-rg",
+					Name:          "vnet-new",
+					CIDRs:         []string{"10.0.0.0/8"},
+				})
+				m.Get(gomockinternal.AContext(), "my-rg", "vnet-new").
+					Return(network.VirtualNetwork{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "vnet-new", gomock.AssignableToTypeOf(network.VirtualNetwork{})).Return(fakeFuture, nil)
+				s.SetLongRunningOperationState(fakeFuture)
+				m.IsDone(gomockinternal.AContext(), fakeFuture).Return(false, nil)
+			},
+		},
+		{
+			name:          "vnet creation started by a previous reconcile still in progress",
+			expectedError: "failed to create virtual network vnet-new: transient reconcile error occurred: operation type PUT on Azure resource my-rg/vnet-new is not done. Object will be requeued after 15s",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VNetSpec().Return(azure.VNetSpec{
+					ResourceGroup: "my-rg",
+					Name:          "vnet-new",
+					CIDRs:         []string{"10.0.0.0/8"},
+				})
+				s.GetLongRunningOperationState("vnet-new", serviceName).Return(fakeFuture)
+				m.IsDone(gomockinternal.AContext(), fakeFuture).Return(false, nil)
 			},
 		},
 		{
@@ -126,6 +173,7 @@ func TestReconcileVnet(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState(gomock.Any(), serviceName).Return(nil)
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.Location().AnyTimes().Return("fake-location")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
@@ -138,7 +186,7 @@ func TestReconcileVnet(t *testing.T) {
 				m.Get(gomockinternal.AContext(), "my-rg", "vnet-ipv6-new").
 					Return(network.VirtualNetwork{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "vnet-ipv6-new", gomockinternal.DiffEq(network.VirtualNetwork{
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "vnet-ipv6-new", gomockinternal.DiffEq(network.VirtualNetwork{
 					Tags: map[string]*string{
 						"Name": to.StringPtr("vnet-ipv6-new"),
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": to.StringPtr(string(infrav1.ResourceLifecycleOwned)),
@@ -153,7 +201,10 @@ func TestReconcileVnet(t *testing.T) {
 							}),
 						},
 					},
-				}))
+				})).Return(fakeFuture, nil)
+				s.SetLongRunningOperationState(fakeFuture)
+				m.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, nil)
+				s.DeleteLongRunningOperationState(fakeFuture.Name, serviceName)
 			},
 		},
 		{
@@ -161,6 +212,7 @@ func TestReconcileVnet(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState(gomock.Any(), serviceName).Return(nil)
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.Location().AnyTimes().Return("fake-location")
 				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "custom-vnet"})
@@ -189,6 +241,7 @@ func TestReconcileVnet(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState(gomock.Any(), serviceName).Return(nil)
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.Location().AnyTimes().Return("fake-location")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
@@ -201,7 +254,10 @@ func TestReconcileVnet(t *testing.T) {
 				m.Get(gomockinternal.AContext(), "custom-vnet-rg", "custom-vnet").
 					Return(network.VirtualNetwork{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 
-				m.CreateOrUpdate(gomockinternal.AContext(), "custom-vnet-rg", "custom-vnet", gomock.AssignableToTypeOf(network.VirtualNetwork{}))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "custom-vnet-rg", "custom-vnet", gomock.AssignableToTypeOf(network.VirtualNetwork{})).Return(fakeFuture, nil)
+				s.SetLongRunningOperationState(fakeFuture)
+				m.IsDone(gomockinternal.AContext(), fakeFuture).Return(true, nil)
+				s.DeleteLongRunningOperationState(fakeFuture.Name, serviceName)
 			},
 		},
 		{
@@ -209,6 +265,7 @@ func TestReconcileVnet(t *testing.T) {
 			expectedError: "failed to get VNet custom-vnet: failed to get VNet custom-vnet: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState(gomock.Any(), serviceName).Return(nil)
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.VNetSpec().Return(azure.VNetSpec{
 					ResourceGroup: "custom-vnet-rg",
@@ -224,6 +281,7 @@ func TestReconcileVnet(t *testing.T) {
 			expectedError: "failed to create virtual network custom-vnet: #: Internal Server Honk: StatusCode=500",
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState(gomock.Any(), serviceName).Return(nil)
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.Location().AnyTimes().Return("fake-location")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
@@ -236,7 +294,7 @@ func TestReconcileVnet(t *testing.T) {
 				m.Get(gomockinternal.AContext(), "custom-vnet-rg", "custom-vnet").
 					Return(network.VirtualNetwork{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 
-				m.CreateOrUpdate(gomockinternal.AContext(), "custom-vnet-rg", "custom-vnet", gomock.AssignableToTypeOf(network.VirtualNetwork{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Honk"))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "custom-vnet-rg", "custom-vnet", gomock.AssignableToTypeOf(network.VirtualNetwork{})).Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Honk"))
 			},
 		},
 	}
@@ -281,6 +339,7 @@ func TestDeleteVnet(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState(gomock.Any(), serviceName).Return(nil)
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.Location().AnyTimes().Return("fake-location")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{
@@ -309,7 +368,10 @@ func TestDeleteVnet(t *testing.T) {
 							"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr("common"),
 						},
 					}, nil)
-				m.Delete(gomockinternal.AContext(), "my-rg", "vnet-exists")
+				m.DeleteAsync(gomockinternal.AContext(), "my-rg", "vnet-exists").Return(fakeDeleteFuture, nil)
+				s.SetLongRunningOperationState(fakeDeleteFuture)
+				m.IsDone(gomockinternal.AContext(), fakeDeleteFuture).Return(true, nil)
+				s.DeleteLongRunningOperationState(fakeDeleteFuture.Name, serviceName)
 			},
 		},
 		{
@@ -317,6 +379,7 @@ func TestDeleteVnet(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState(gomock.Any(), serviceName).Return(nil)
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.Location().AnyTimes().Return("fake-location")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{
@@ -334,11 +397,27 @@ func TestDeleteVnet(t *testing.T) {
 					Return(network.VirtualNetwork{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 			},
 		},
+		{
+			name:          "vnet deletion started by a previous reconcile is done",
+			expectedError: "",
+			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VNetSpec().Return(azure.VNetSpec{
+					ResourceGroup: "my-rg",
+					Name:          "vnet-exists",
+					CIDRs:         []string{"10.0.0.0/16"},
+				})
+				s.GetLongRunningOperationState("vnet-exists", serviceName).Return(fakeDeleteFuture)
+				m.IsDone(gomockinternal.AContext(), fakeDeleteFuture).Return(true, nil)
+				s.DeleteLongRunningOperationState("vnet-exists", serviceName)
+			},
+		},
 		{
 			name:          "unmanaged vnet",
 			expectedError: "",
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState(gomock.Any(), serviceName).Return(nil)
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.Location().AnyTimes().Return("fake-location")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
@@ -368,6 +447,7 @@ func TestDeleteVnet(t *testing.T) {
 			expectedError: "failed to delete VNet vnet-exists in resource group my-rg: #: Internal Honk Server: StatusCode=500",
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_virtualnetworks.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.GetLongRunningOperationState(gomock.Any(), serviceName).Return(nil)
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.Location().AnyTimes().Return("fake-location")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{
@@ -396,8 +476,8 @@ func TestDeleteVnet(t *testing.T) {
 							"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr("common"),
 						},
 					}, nil)
-				m.Delete(gomockinternal.AContext(), "my-rg", "vnet-exists").
-					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Honk Server"))
+				m.DeleteAsync(gomockinternal.AContext(), "my-rg", "vnet-exists").
+					Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Honk Server"))
 			},
 		},
 	}
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmbackups
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/recoveryservices/mgmt/2021-01-01/backup"
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// fabricName is the fabric of the Azure VMs protected by Recovery Services vaults.
+	fabricName = "Azure"
+	// iaasVMFilter selects the Azure VMs among the containers discovered by Recovery Services vaults.
+	iaasVMFilter = "backupManagementType eq 'AzureIaasVM'"
+)
+
+// client wraps go-sdk.
+type client interface {
+	Get(ctx context.Context, resourceGroupName, vaultName, containerName, protectedItemName string) (backup.ProtectedItemResource, error)
+	CreateOrUpdate(ctx context.Context, resourceGroupName, vaultName, containerName, protectedItemName string, parameters backup.ProtectedItemResource) error
+	RefreshContainersAsync(ctx context.Context, resourceGroupName, vaultName, vmName string) (*infrav1.Future, error)
+	IsDone(ctx context.Context, future *infrav1.Future) (bool, error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	protecteditems       backup.ProtectedItemsClient
+	protectioncontainers backup.ProtectionContainersClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new VM backups client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	return &azureClient{
+		protecteditems:       newProtectedItemsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		protectioncontainers: newProtectionContainersClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newProtectedItemsClient creates a new protected items client from subscription ID.
+func newProtectedItemsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) backup.ProtectedItemsClient {
+	protectedItemsClient := backup.NewProtectedItemsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&protectedItemsClient.Client, authorizer)
+	return protectedItemsClient
+}
+
+// newProtectionContainersClient creates a new protection containers client from subscription ID.
+func newProtectionContainersClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) backup.ProtectionContainersClient {
+	protectionContainersClient := backup.NewProtectionContainersClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&protectionContainersClient.Client, authorizer)
+	return protectionContainersClient
+}
+
+// Get gets the protected item of a VM in a Recovery Services vault.
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, vaultName, containerName, protectedItemName string) (backup.ProtectedItemResource, error) {
+	ctx, span := tele.Tracer().Start(ctx, "vmbackups.AzureClient.Get")
+	defer span.End()
+
+	return ac.protecteditems.Get(ctx, vaultName, resourceGroupName, fabricName, containerName, protectedItemName, "")
+}
+
+// CreateOrUpdate creates or updates the protected item of a VM in a Recovery Services vault. The vault applies the
+// change asynchronously.
+func (ac *azureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, vaultName, containerName, protectedItemName string, parameters backup.ProtectedItemResource) error {
+	ctx, span := tele.Tracer().Start(ctx, "vmbackups.AzureClient.CreateOrUpdate")
+	defer span.End()
+
+	_, err := ac.protecteditems.CreateOrUpdate(ctx, vaultName, resourceGroupName, fabricName, containerName, protectedItemName, parameters)
+	return err
+}
+
+// RefreshContainersAsync starts the discovery of the VMs of the subscription by a Recovery Services vault, and returns
+// the future of the long-running operation without waiting for it to complete.
+func (ac *azureClient) RefreshContainersAsync(ctx context.Context, resourceGroupName, vaultName, vmName string) (*infrav1.Future, error) {
+	ctx, span := tele.Tracer().Start(ctx, "vmbackups.AzureClient.RefreshContainersAsync")
+	defer span.End()
+
+	result, err := ac.protectioncontainers.Refresh(ctx, vaultName, resourceGroupName, fabricName, iaasVMFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	future, err := azureautorest.NewFutureFromResponse(result.Response)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to track the discovery of VMs")
+	}
+	return async.NewFuture(&future, async.PostFuture, serviceName, resourceGroupName, vmName)
+}
+
+// IsDone returns true if the long-running operation of the future is done.
+func (ac *azureClient) IsDone(ctx context.Context, future *infrav1.Future) (bool, error) {
+	ctx, span := tele.Tracer().Start(ctx, "vmbackups.AzureClient.IsDone")
+	defer span.End()
+
+	return async.IsDone(ctx, ac.protectioncontainers, future)
+}
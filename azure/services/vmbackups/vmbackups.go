@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmbackups
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/services/recoveryservices/mgmt/2021-01-01/backup"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "vmbackups"
+
+// VMBackupScope defines the scope interface for a VM backups service.
+type VMBackupScope interface {
+	logr.Logger
+	azure.ClusterDescriber
+	azure.AsyncStatusUpdater
+	BackupSpec() *azure.BackupSpec
+}
+
+// Service provides operations on the backup protection of VMs by Recovery Services vaults.
+type Service struct {
+	Scope VMBackupScope
+	client
+}
+
+// New creates a new VM backups service.
+func New(scope VMBackupScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Reconcile enrolls the VM into the backup policy of its Recovery Services vault, unless the vault already protects it.
+// It returns an OperationNotDoneError wrapped in a transient error while the vault discovers the VM.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, span := tele.Tracer().Start(ctx, "vmbackups.Service.Reconcile")
+	defer span.End()
+
+	backupSpec := s.Scope.BackupSpec()
+	if backupSpec == nil {
+		return nil
+	}
+
+	containerName, protectedItemName := protectedItemNames(s.Scope.ResourceGroup(), backupSpec.VMName)
+	if future := s.Scope.GetLongRunningOperationState(backupSpec.VMName, serviceName); future != nil {
+		// the discovery of the VM was started by a previous reconciliation loop.
+		if err := async.CheckOperation(ctx, s.Scope, s.client, future); err != nil {
+			return errors.Wrapf(err, "failed to discover VM %s in Recovery Services vault %s", backupSpec.VMName, backupSpec.VaultName)
+		}
+	} else {
+		if _, err := s.client.Get(ctx, backupSpec.VaultResourceGroup, backupSpec.VaultName, containerName, protectedItemName); err == nil {
+			// the VM is only enrolled once, later changes of its protection are left to the users of the vault.
+			return nil
+		} else if !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to get backup protection of VM %s in Recovery Services vault %s", backupSpec.VMName, backupSpec.VaultName)
+		}
+
+		// vaults can only protect the VMs they have discovered, and don't discover new VMs by themselves.
+		s.Scope.V(2).Info("discovering VM in Recovery Services vault", "vm", backupSpec.VMName, "vault", backupSpec.VaultName)
+		future, err := s.client.RefreshContainersAsync(ctx, backupSpec.VaultResourceGroup, backupSpec.VaultName, backupSpec.VMName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to discover VM %s in Recovery Services vault %s", backupSpec.VMName, backupSpec.VaultName)
+		}
+		if err := async.TrackOperation(ctx, s.Scope, s.client, future); err != nil {
+			return errors.Wrapf(err, "failed to discover VM %s in Recovery Services vault %s", backupSpec.VMName, backupSpec.VaultName)
+		}
+	}
+
+	s.Scope.V(2).Info("enabling backup protection of VM", "vm", backupSpec.VMName, "vault", backupSpec.VaultName, "policy", backupSpec.PolicyName)
+	err := s.client.CreateOrUpdate(ctx, backupSpec.VaultResourceGroup, backupSpec.VaultName, containerName, protectedItemName, backup.ProtectedItemResource{
+		Properties: backup.AzureIaaSComputeVMProtectedItem{
+			SourceResourceID: to.StringPtr(azure.VMID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), backupSpec.VMName)),
+			PolicyID:         to.StringPtr(azure.BackupPolicyID(s.Scope.SubscriptionID(), backupSpec.VaultResourceGroup, backupSpec.VaultName, backupSpec.PolicyName)),
+		},
+	})
+	azure.RecordCreate(ctx, "VM backup protection", backupSpec.VMName, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to enable backup protection of VM %s in Recovery Services vault %s", backupSpec.VMName, backupSpec.VaultName)
+	}
+	s.Scope.V(2).Info("successfully enabled backup protection of VM", "vm", backupSpec.VMName, "vault", backupSpec.VaultName, "policy", backupSpec.PolicyName)
+	return nil
+}
+
+// Delete stops the backup protection of the VM, retaining its existing recovery points.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, span := tele.Tracer().Start(ctx, "vmbackups.Service.Delete")
+	defer span.End()
+
+	backupSpec := s.Scope.BackupSpec()
+	if backupSpec == nil {
+		return nil
+	}
+
+	containerName, protectedItemName := protectedItemNames(s.Scope.ResourceGroup(), backupSpec.VMName)
+	existing, err := s.client.Get(ctx, backupSpec.VaultResourceGroup, backupSpec.VaultName, containerName, protectedItemName)
+	if azure.ResourceNotFound(err) {
+		// the VM was never protected, or its protection was deleted.
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to get backup protection of VM %s in Recovery Services vault %s", backupSpec.VMName, backupSpec.VaultName)
+	}
+	if item, ok := existing.Properties.AsAzureIaaSComputeVMProtectedItem(); ok && item.ProtectionState == backup.ProtectionStateProtectionStopped {
+		return nil
+	}
+
+	s.Scope.V(2).Info("stopping backup protection of VM", "vm", backupSpec.VMName, "vault", backupSpec.VaultName)
+	err = s.client.CreateOrUpdate(ctx, backupSpec.VaultResourceGroup, backupSpec.VaultName, containerName, protectedItemName, backup.ProtectedItemResource{
+		Properties: backup.AzureIaaSComputeVMProtectedItem{
+			SourceResourceID: to.StringPtr(azure.VMID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), backupSpec.VMName)),
+			ProtectionState:  backup.ProtectionStateProtectionStopped,
+		},
+	})
+	azure.RecordDelete(ctx, "VM backup protection", backupSpec.VMName, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to stop backup protection of VM %s in Recovery Services vault %s", backupSpec.VMName, backupSpec.VaultName)
+	}
+	s.Scope.V(2).Info("successfully stopped backup protection of VM", "vm", backupSpec.VMName, "vault", backupSpec.VaultName)
+	return nil
+}
+
+// protectedItemNames returns the names of the protection container and the protected item of a VM in Recovery Services
+// vaults.
+func protectedItemNames(resourceGroup, vmName string) (containerName, protectedItemName string) {
+	return fmt.Sprintf("iaasvmcontainer;iaasvmcontainerv2;%s;%s", resourceGroup, vmName),
+		fmt.Sprintf("vm;iaasvmcontainerv2;%s;%s", resourceGroup, vmName)
+}
@@ -0,0 +1,301 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmbackups
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/recoveryservices/mgmt/2021-01-01/backup"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/v2/klogr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmbackups/mock_vmbackups"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+const (
+	fakeContainerName     = "iaasvmcontainer;iaasvmcontainerv2;my-rg;my-vm"
+	fakeProtectedItemName = "vm;iaasvmcontainerv2;my-rg;my-vm"
+)
+
+var fakeRefreshFuture = &infrav1.Future{Type: async.PostFuture, ServiceName: serviceName, ResourceGroup: "my-backup-rg", Name: "my-vm"}
+
+var fakeBackupSpec = azure.BackupSpec{
+	VMName:             "my-vm",
+	VaultResourceGroup: "my-backup-rg",
+	VaultName:          "my-vault",
+	PolicyName:         "DefaultPolicy",
+}
+
+func TestReconcileVMBackup(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_vmbackups.MockVMBackupScopeMockRecorder, m *mock_vmbackups.MockclientMockRecorder)
+	}{
+		{
+			name:          "noop if the machine has no backup policy",
+			expectedError: "",
+			expect: func(s *mock_vmbackups.MockVMBackupScopeMockRecorder, m *mock_vmbackups.MockclientMockRecorder) {
+				s.BackupSpec().Return(nil)
+			},
+		},
+		{
+			name:          "VM already protected",
+			expectedError: "",
+			expect: func(s *mock_vmbackups.MockVMBackupScopeMockRecorder, m *mock_vmbackups.MockclientMockRecorder) {
+				s.BackupSpec().Return(&fakeBackupSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.GetLongRunningOperationState("my-vm", serviceName).Return(nil)
+				m.Get(gomockinternal.AContext(), "my-backup-rg", "my-vault", fakeContainerName, fakeProtectedItemName).
+					Return(backup.ProtectedItemResource{
+						Properties: backup.AzureIaaSComputeVMProtectedItem{ProtectionState: backup.ProtectionStateProtected},
+					}, nil)
+			},
+		},
+		{
+			name:          "enables the protection of the VM",
+			expectedError: "",
+			expect: func(s *mock_vmbackups.MockVMBackupScopeMockRecorder, m *mock_vmbackups.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BackupSpec().Return(&fakeBackupSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.GetLongRunningOperationState("my-vm", serviceName).Return(nil)
+				m.Get(gomockinternal.AContext(), "my-backup-rg", "my-vault", fakeContainerName, fakeProtectedItemName).
+					Return(backup.ProtectedItemResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.RefreshContainersAsync(gomockinternal.AContext(), "my-backup-rg", "my-vault", "my-vm").Return(fakeRefreshFuture, nil)
+				s.SetLongRunningOperationState(fakeRefreshFuture)
+				m.IsDone(gomockinternal.AContext(), fakeRefreshFuture).Return(true, nil)
+				s.DeleteLongRunningOperationState("my-vm", serviceName)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-backup-rg", "my-vault", fakeContainerName, fakeProtectedItemName, backup.ProtectedItemResource{
+					Properties: backup.AzureIaaSComputeVMProtectedItem{
+						SourceResourceID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm"),
+						PolicyID:         to.StringPtr("/subscriptions/123/resourceGroups/my-backup-rg/providers/Microsoft.RecoveryServices/vaults/my-vault/backupPolicies/DefaultPolicy"),
+					},
+				})
+			},
+		},
+		{
+			name:          "waits for the vault to discover the VM",
+			expectedError: "failed to discover VM my-vm in Recovery Services vault my-vault: transient reconcile error occurred: operation type POST on Azure resource my-backup-rg/my-vm is not done. Object will be requeued after 15s",
+			expect: func(s *mock_vmbackups.MockVMBackupScopeMockRecorder, m *mock_vmbackups.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BackupSpec().Return(&fakeBackupSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.GetLongRunningOperationState("my-vm", serviceName).Return(nil)
+				m.Get(gomockinternal.AContext(), "my-backup-rg", "my-vault", fakeContainerName, fakeProtectedItemName).
+					Return(backup.ProtectedItemResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.RefreshContainersAsync(gomockinternal.AContext(), "my-backup-rg", "my-vault", "my-vm").Return(fakeRefreshFuture, nil)
+				s.SetLongRunningOperationState(fakeRefreshFuture)
+				m.IsDone(gomockinternal.AContext(), fakeRefreshFuture).Return(false, nil)
+			},
+		},
+		{
+			name:          "enables the protection of the VM once the vault has discovered it",
+			expectedError: "",
+			expect: func(s *mock_vmbackups.MockVMBackupScopeMockRecorder, m *mock_vmbackups.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BackupSpec().Return(&fakeBackupSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.GetLongRunningOperationState("my-vm", serviceName).Return(fakeRefreshFuture)
+				m.IsDone(gomockinternal.AContext(), fakeRefreshFuture).Return(true, nil)
+				s.DeleteLongRunningOperationState("my-vm", serviceName)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-backup-rg", "my-vault", fakeContainerName, fakeProtectedItemName, gomock.AssignableToTypeOf(backup.ProtectedItemResource{}))
+			},
+		},
+		{
+			name:          "fails to get the protection of the VM",
+			expectedError: "failed to get backup protection of VM my-vm in Recovery Services vault my-vault: #: Forbidden: StatusCode=403",
+			expect: func(s *mock_vmbackups.MockVMBackupScopeMockRecorder, m *mock_vmbackups.MockclientMockRecorder) {
+				s.BackupSpec().Return(&fakeBackupSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.GetLongRunningOperationState("my-vm", serviceName).Return(nil)
+				m.Get(gomockinternal.AContext(), "my-backup-rg", "my-vault", fakeContainerName, fakeProtectedItemName).
+					Return(backup.ProtectedItemResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 403}, "Forbidden"))
+			},
+		},
+		{
+			name:          "fails to discover the VM",
+			expectedError: "failed to discover VM my-vm in Recovery Services vault my-vault: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_vmbackups.MockVMBackupScopeMockRecorder, m *mock_vmbackups.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BackupSpec().Return(&fakeBackupSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.GetLongRunningOperationState("my-vm", serviceName).Return(nil)
+				m.Get(gomockinternal.AContext(), "my-backup-rg", "my-vault", fakeContainerName, fakeProtectedItemName).
+					Return(backup.ProtectedItemResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.RefreshContainersAsync(gomockinternal.AContext(), "my-backup-rg", "my-vault", "my-vm").
+					Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:          "fails to enable the protection of the VM",
+			expectedError: "failed to enable backup protection of VM my-vm in Recovery Services vault my-vault: #: Not found: StatusCode=404",
+			expect: func(s *mock_vmbackups.MockVMBackupScopeMockRecorder, m *mock_vmbackups.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BackupSpec().Return(&fakeBackupSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.GetLongRunningOperationState("my-vm", serviceName).Return(fakeRefreshFuture)
+				m.IsDone(gomockinternal.AContext(), fakeRefreshFuture).Return(true, nil)
+				s.DeleteLongRunningOperationState("my-vm", serviceName)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-backup-rg", "my-vault", fakeContainerName, fakeProtectedItemName, gomock.AssignableToTypeOf(backup.ProtectedItemResource{})).
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_vmbackups.NewMockVMBackupScope(mockCtrl)
+			clientMock := mock_vmbackups.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteVMBackup(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_vmbackups.MockVMBackupScopeMockRecorder, m *mock_vmbackups.MockclientMockRecorder)
+	}{
+		{
+			name:          "noop if the machine has no backup policy",
+			expectedError: "",
+			expect: func(s *mock_vmbackups.MockVMBackupScopeMockRecorder, m *mock_vmbackups.MockclientMockRecorder) {
+				s.BackupSpec().Return(nil)
+			},
+		},
+		{
+			name:          "stops the protection of the VM",
+			expectedError: "",
+			expect: func(s *mock_vmbackups.MockVMBackupScopeMockRecorder, m *mock_vmbackups.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BackupSpec().Return(&fakeBackupSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.SubscriptionID().AnyTimes().Return("123")
+				m.Get(gomockinternal.AContext(), "my-backup-rg", "my-vault", fakeContainerName, fakeProtectedItemName).
+					Return(backup.ProtectedItemResource{
+						Properties: backup.AzureIaaSComputeVMProtectedItem{ProtectionState: backup.ProtectionStateProtected},
+					}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-backup-rg", "my-vault", fakeContainerName, fakeProtectedItemName, backup.ProtectedItemResource{
+					Properties: backup.AzureIaaSComputeVMProtectedItem{
+						SourceResourceID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm"),
+						ProtectionState:  backup.ProtectionStateProtectionStopped,
+					},
+				})
+			},
+		},
+		{
+			name:          "protection already stopped",
+			expectedError: "",
+			expect: func(s *mock_vmbackups.MockVMBackupScopeMockRecorder, m *mock_vmbackups.MockclientMockRecorder) {
+				s.BackupSpec().Return(&fakeBackupSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-backup-rg", "my-vault", fakeContainerName, fakeProtectedItemName).
+					Return(backup.ProtectedItemResource{
+						Properties: backup.AzureIaaSComputeVMProtectedItem{ProtectionState: backup.ProtectionStateProtectionStopped},
+					}, nil)
+			},
+		},
+		{
+			name:          "VM never protected",
+			expectedError: "",
+			expect: func(s *mock_vmbackups.MockVMBackupScopeMockRecorder, m *mock_vmbackups.MockclientMockRecorder) {
+				s.BackupSpec().Return(&fakeBackupSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-backup-rg", "my-vault", fakeContainerName, fakeProtectedItemName).
+					Return(backup.ProtectedItemResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "fails to stop the protection of the VM",
+			expectedError: "failed to stop backup protection of VM my-vm in Recovery Services vault my-vault: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_vmbackups.MockVMBackupScopeMockRecorder, m *mock_vmbackups.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BackupSpec().Return(&fakeBackupSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.SubscriptionID().AnyTimes().Return("123")
+				m.Get(gomockinternal.AContext(), "my-backup-rg", "my-vault", fakeContainerName, fakeProtectedItemName).
+					Return(backup.ProtectedItemResource{
+						Properties: backup.AzureIaaSComputeVMProtectedItem{ProtectionState: backup.ProtectionStateProtected},
+					}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-backup-rg", "my-vault", fakeContainerName, fakeProtectedItemName, gomock.AssignableToTypeOf(backup.ProtectedItemResource{})).
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_vmbackups.NewMockVMBackupScope(mockCtrl)
+			clientMock := mock_vmbackups.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
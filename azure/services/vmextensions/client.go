@@ -21,14 +21,16 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-30/compute"
 	"github.com/Azure/go-autorest/autorest"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 // client wraps go-sdk.
 type client interface {
 	Get(ctx context.Context, resourceGroupName, vmName, name string) (compute.VirtualMachineExtension, error)
-	CreateOrUpdateAsync(context.Context, string, string, string, compute.VirtualMachineExtension) error
+	CreateOrUpdateAsync(context.Context, string, string, string, compute.VirtualMachineExtension) (*infrav1.Future, error)
 	Delete(context.Context, string, string, string) error
 }
 
@@ -60,13 +62,17 @@ func (ac *azureClient) Get(ctx context.Context, resourceGroupName, vmName, name
 	return ac.vmextensions.Get(ctx, resourceGroupName, vmName, name, "")
 }
 
-// CreateOrUpdateAsync creates or updates the virtual machine extension.
-func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupName, vmName, name string, parameters compute.VirtualMachineExtension) error {
+// CreateOrUpdateAsync starts creating or updating the virtual machine extension, and returns the future of the
+// long-running operation without waiting for it to complete.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupName, vmName, name string, parameters compute.VirtualMachineExtension) (*infrav1.Future, error) {
 	ctx, span := tele.Tracer().Start(ctx, "vmextensions.AzureClient.CreateOrUpdate")
 	defer span.End()
 
-	_, err := ac.vmextensions.CreateOrUpdate(ctx, resourceGroupName, vmName, name, parameters)
-	return err
+	future, err := ac.vmextensions.CreateOrUpdate(ctx, resourceGroupName, vmName, name, parameters)
+	if err != nil {
+		return nil, err
+	}
+	return async.NewFuture(&future, async.PutFuture, serviceName, resourceGroupName, name)
 }
 
 // Delete removes the virtual machine extension.
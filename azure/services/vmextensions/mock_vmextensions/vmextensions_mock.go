@@ -21,6 +21,7 @@ limitations under the License.
 package mock_vmextensions
 
 import (
+	context "context"
 	reflect "reflect"
 
 	autorest "github.com/Azure/go-autorest/autorest"
@@ -255,6 +256,20 @@ func (mr *MockVMExtensionScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockVMExtensionScope)(nil).Location))
 }
 
+// NetworkResourceGroup mocks base method.
+func (m *MockVMExtensionScope) NetworkResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NetworkResourceGroup indicates an expected call of NetworkResourceGroup.
+func (mr *MockVMExtensionScopeMockRecorder) NetworkResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkResourceGroup", reflect.TypeOf((*MockVMExtensionScope)(nil).NetworkResourceGroup))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockVMExtensionScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -326,17 +341,18 @@ func (mr *MockVMExtensionScopeMockRecorder) V(level interface{}) *gomock.Call {
 }
 
 // VMExtensionSpecs mocks base method.
-func (m *MockVMExtensionScope) VMExtensionSpecs() []azure.VMExtensionSpec {
+func (m *MockVMExtensionScope) VMExtensionSpecs(arg0 context.Context) ([]azure.VMExtensionSpec, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "VMExtensionSpecs")
+	ret := m.ctrl.Call(m, "VMExtensionSpecs", arg0)
 	ret0, _ := ret[0].([]azure.VMExtensionSpec)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // VMExtensionSpecs indicates an expected call of VMExtensionSpecs.
-func (mr *MockVMExtensionScopeMockRecorder) VMExtensionSpecs() *gomock.Call {
+func (mr *MockVMExtensionScopeMockRecorder) VMExtensionSpecs(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMExtensionSpecs", reflect.TypeOf((*MockVMExtensionScope)(nil).VMExtensionSpecs))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMExtensionSpecs", reflect.TypeOf((*MockVMExtensionScope)(nil).VMExtensionSpecs), arg0)
 }
 
 // WithName mocks base method.
@@ -27,11 +27,13 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+const serviceName = "vmextensions"
+
 // VMExtensionScope defines the scope interface for a vm extension service.
 type VMExtensionScope interface {
 	logr.Logger
 	azure.ClusterDescriber
-	VMExtensionSpecs() []azure.VMExtensionSpec
+	VMExtensionSpecs(context.Context) ([]azure.VMExtensionSpec, error)
 	SetBootstrapConditions(string, string) error
 }
 
@@ -49,16 +51,30 @@ func New(scope VMExtensionScope) *Service {
 	}
 }
 
-// Reconcile creates or updates the VM extension.
+// Reconcile creates the VM extensions one at a time, in order, as Azure doesn't allow concurrent operations on the
+// extensions of a VM. It returns a transient error while an extension is provisioning, so that the next extensions are
+// created by the next reconciliation loops.
 func (s *Service) Reconcile(ctx context.Context) error {
 	_, span := tele.Tracer().Start(ctx, "vmextensions.Service.Reconcile")
 	defer span.End()
 
-	for _, extensionSpec := range s.Scope.VMExtensionSpecs() {
+	extensionSpecs, err := s.Scope.VMExtensionSpecs(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get vm extension specs")
+	}
+
+	for _, extensionSpec := range extensionSpecs {
 		if existing, err := s.client.Get(ctx, s.Scope.ResourceGroup(), extensionSpec.VMName, extensionSpec.Name); err == nil {
-			// check the extension status and set the associated conditions.
-			if retErr := s.Scope.SetBootstrapConditions(to.String(existing.ProvisioningState), extensionSpec.Name); retErr != nil {
-				return retErr
+			provisioningState := to.String(existing.ProvisioningState)
+			if !extensionSpec.SkipBootstrapConditions {
+				// check the extension status and set the associated conditions.
+				if retErr := s.Scope.SetBootstrapConditions(provisioningState, extensionSpec.Name); retErr != nil {
+					return retErr
+				}
+			}
+			switch compute.ProvisioningState(provisioningState) {
+			case compute.ProvisioningStateCreating, compute.ProvisioningStateUpdating:
+				return azure.WithTransientError(errors.Errorf("VM extension %s on VM %s is still provisioning", extensionSpec.Name, extensionSpec.VMName), azure.PollingDelay(azure.OperationWrite))
 			}
 			// if the extension already exists, do not update it.
 			continue
@@ -66,27 +82,37 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			return errors.Wrapf(err, "failed to get vm extension %s on vm %s", extensionSpec.Name, extensionSpec.VMName)
 		}
 
+		properties := &compute.VirtualMachineExtensionProperties{
+			Publisher:          to.StringPtr(extensionSpec.Publisher),
+			Type:               to.StringPtr(extensionSpec.Name),
+			TypeHandlerVersion: to.StringPtr(extensionSpec.Version),
+			Settings:           nil,
+			ProtectedSettings:  extensionSpec.ProtectedSettings,
+		}
+		if extensionSpec.Settings != nil {
+			properties.Settings = extensionSpec.Settings
+		}
+		if extensionSpec.AutoUpgradeMinorVersion {
+			properties.AutoUpgradeMinorVersion = to.BoolPtr(true)
+		}
+
 		s.Scope.V(2).Info("creating VM extension", "vm extension", extensionSpec.Name)
-		err := s.client.CreateOrUpdateAsync(
+		future, err := s.client.CreateOrUpdateAsync(
 			ctx,
 			s.Scope.ResourceGroup(),
 			extensionSpec.VMName,
 			extensionSpec.Name,
 			compute.VirtualMachineExtension{
-				VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
-					Publisher:          to.StringPtr(extensionSpec.Publisher),
-					Type:               to.StringPtr(extensionSpec.Name),
-					TypeHandlerVersion: to.StringPtr(extensionSpec.Version),
-					Settings:           nil,
-					ProtectedSettings:  extensionSpec.ProtectedSettings,
-				},
-				Location: to.StringPtr(s.Scope.Location()),
+				VirtualMachineExtensionProperties: properties,
+				Location:                          to.StringPtr(s.Scope.Location()),
 			},
 		)
+		azure.RecordCreate(ctx, "VM extension", extensionSpec.Name, err)
 		if err != nil {
 			return errors.Wrapf(err, "failed to create VM extension %s on VM %s in resource group %s", extensionSpec.Name, extensionSpec.VMName, s.Scope.ResourceGroup())
 		}
-		s.Scope.V(2).Info("successfully created VM extension", "vm extension", extensionSpec.Name)
+		// the next extensions wait for this one to be provisioned.
+		return azure.WithTransientError(azure.NewOperationNotDoneError(future), azure.PollingDelay(azure.OperationWrite))
 	}
 	return nil
 }
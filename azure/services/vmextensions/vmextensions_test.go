@@ -25,15 +25,20 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-30/compute"
 	"github.com/Azure/go-autorest/autorest"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensions/mock_vmextensions"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"k8s.io/klog/v2/klogr"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 )
 
+var fakeFuture = &infrav1.Future{Type: async.PutFuture, ServiceName: serviceName, ResourceGroup: "my-rg", Name: "my-extension-1"}
+
 func TestReconcileVMExtension(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -45,14 +50,14 @@ func TestReconcileVMExtension(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.VMExtensionSpecs().Return([]azure.VMExtensionSpec{
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return([]azure.VMExtensionSpec{
 					{
 						Name:      "my-extension-1",
 						VMName:    "my-vm",
 						Publisher: "some-publisher",
 						Version:   "1.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").Return(compute.VirtualMachineExtension{
@@ -72,14 +77,14 @@ func TestReconcileVMExtension(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.VMExtensionSpecs().Return([]azure.VMExtensionSpec{
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return([]azure.VMExtensionSpec{
 					{
 						Name:      "my-extension-1",
 						VMName:    "my-vm",
 						Publisher: "some-publisher",
 						Version:   "1.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").Return(compute.VirtualMachineExtension{
@@ -96,17 +101,17 @@ func TestReconcileVMExtension(t *testing.T) {
 		},
 		{
 			name:          "extension is still creating",
-			expectedError: "",
+			expectedError: "transient reconcile error occurred: VM extension my-extension-1 on VM my-vm is still provisioning. Object will be requeued after 15s",
 			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.VMExtensionSpecs().Return([]azure.VMExtensionSpec{
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return([]azure.VMExtensionSpec{
 					{
 						Name:      "my-extension-1",
 						VMName:    "my-vm",
 						Publisher: "some-publisher",
 						Version:   "1.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").Return(compute.VirtualMachineExtension{
@@ -122,11 +127,11 @@ func TestReconcileVMExtension(t *testing.T) {
 			},
 		},
 		{
-			name:          "reconcile multiple extensions",
-			expectedError: "",
+			name:          "creates one extension at a time",
+			expectedError: "transient reconcile error occurred: operation type PUT on Azure resource my-rg/my-extension-1 is not done. Object will be requeued after 15s",
 			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.VMExtensionSpecs().Return([]azure.VMExtensionSpec{
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return([]azure.VMExtensionSpec{
 					{
 						Name:      "my-extension-1",
 						VMName:    "my-vm",
@@ -139,15 +144,74 @@ func TestReconcileVMExtension(t *testing.T) {
 						Publisher: "other-publisher",
 						Version:   "2.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").
 					Return(compute.VirtualMachineExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1", gomock.AssignableToTypeOf(compute.VirtualMachineExtension{}))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1", gomock.AssignableToTypeOf(compute.VirtualMachineExtension{})).Return(fakeFuture, nil)
+			},
+		},
+		{
+			name:          "waits for the previous extension to be provisioned",
+			expectedError: "transient reconcile error occurred: VM extension AzureMonitorLinuxAgent on VM my-vm is still provisioning. Object will be requeued after 15s",
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return([]azure.VMExtensionSpec{
+					{
+						Name:                    "AzureMonitorLinuxAgent",
+						VMName:                  "my-vm",
+						Publisher:               "Microsoft.Azure.Monitor",
+						Version:                 "1.0",
+						SkipBootstrapConditions: true,
+					},
+					{
+						Name:      "other-extension",
+						VMName:    "my-vm",
+						Publisher: "other-publisher",
+						Version:   "2.0",
+					},
+				}, nil)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("test-location")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "AzureMonitorLinuxAgent").Return(compute.VirtualMachineExtension{
+					VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+						ProvisioningState: to.StringPtr(string(compute.ProvisioningStateUpdating)),
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "creates the next extension once the previous one is provisioned",
+			expectedError: "transient reconcile error occurred: operation type PUT on Azure resource my-rg/other-extension is not done. Object will be requeued after 15s",
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return([]azure.VMExtensionSpec{
+					{
+						Name:      "my-extension-1",
+						VMName:    "my-vm",
+						Publisher: "some-publisher",
+						Version:   "1.0",
+					},
+					{
+						Name:      "other-extension",
+						VMName:    "my-vm",
+						Publisher: "other-publisher",
+						Version:   "2.0",
+					},
+				}, nil)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("test-location")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").Return(compute.VirtualMachineExtension{
+					VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+						ProvisioningState: to.StringPtr(string(compute.ProvisioningStateSucceeded)),
+					},
+				}, nil)
+				s.SetBootstrapConditions(string(compute.ProvisioningStateSucceeded), "my-extension-1")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "other-extension").
 					Return(compute.VirtualMachineExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", "other-extension", gomock.AssignableToTypeOf(compute.VirtualMachineExtension{}))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", "other-extension", gomock.AssignableToTypeOf(compute.VirtualMachineExtension{})).
+					Return(&infrav1.Future{Type: async.PutFuture, ServiceName: serviceName, ResourceGroup: "my-rg", Name: "other-extension"}, nil)
 			},
 		},
 		{
@@ -155,7 +219,7 @@ func TestReconcileVMExtension(t *testing.T) {
 			expectedError: "failed to get vm extension my-extension-1 on vm my-vm: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.VMExtensionSpecs().Return([]azure.VMExtensionSpec{
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return([]azure.VMExtensionSpec{
 					{
 						Name:      "my-extension-1",
 						VMName:    "my-vm",
@@ -168,7 +232,7 @@ func TestReconcileVMExtension(t *testing.T) {
 						Publisher: "other-publisher",
 						Version:   "2.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").
@@ -180,7 +244,7 @@ func TestReconcileVMExtension(t *testing.T) {
 			expectedError: "failed to create VM extension my-extension-1 on VM my-vm in resource group my-rg: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.VMExtensionSpecs().Return([]azure.VMExtensionSpec{
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return([]azure.VMExtensionSpec{
 					{
 						Name:      "my-extension-1",
 						VMName:    "my-vm",
@@ -193,12 +257,82 @@ func TestReconcileVMExtension(t *testing.T) {
 						Publisher: "other-publisher",
 						Version:   "2.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").
 					Return(compute.VirtualMachineExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1", gomock.AssignableToTypeOf(compute.VirtualMachineExtension{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1", gomock.AssignableToTypeOf(compute.VirtualMachineExtension{})).Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:          "monitoring extension does not set the bootstrap conditions",
+			expectedError: "",
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return([]azure.VMExtensionSpec{
+					{
+						Name:                    "AzureMonitorLinuxAgent",
+						VMName:                  "my-vm",
+						Publisher:               "Microsoft.Azure.Monitor",
+						Version:                 "1.0",
+						AutoUpgradeMinorVersion: true,
+						SkipBootstrapConditions: true,
+					},
+				}, nil)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("test-location")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "AzureMonitorLinuxAgent").Return(compute.VirtualMachineExtension{
+					VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+						Publisher:         to.StringPtr("Microsoft.Azure.Monitor"),
+						Type:              to.StringPtr("AzureMonitorLinuxAgent"),
+						ProvisioningState: to.StringPtr(string(compute.ProvisioningStateFailed)),
+					},
+					ID:   to.StringPtr("fake/id"),
+					Name: to.StringPtr("AzureMonitorLinuxAgent"),
+				}, nil)
+			},
+		},
+		{
+			name:          "create the monitoring extension with its settings",
+			expectedError: "transient reconcile error occurred: operation type PUT on Azure resource my-rg/OmsAgentForLinux is not done. Object will be requeued after 15s",
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return([]azure.VMExtensionSpec{
+					{
+						Name:                    "OmsAgentForLinux",
+						VMName:                  "my-vm",
+						Publisher:               "Microsoft.EnterpriseCloud.Monitoring",
+						Version:                 "1.13",
+						Settings:                map[string]interface{}{"workspaceId": "my-workspace"},
+						ProtectedSettings:       map[string]string{"workspaceKey": "my-key"},
+						AutoUpgradeMinorVersion: true,
+						SkipBootstrapConditions: true,
+					},
+				}, nil)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("test-location")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "OmsAgentForLinux").
+					Return(compute.VirtualMachineExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", "OmsAgentForLinux", compute.VirtualMachineExtension{
+					VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+						Publisher:               to.StringPtr("Microsoft.EnterpriseCloud.Monitoring"),
+						Type:                    to.StringPtr("OmsAgentForLinux"),
+						TypeHandlerVersion:      to.StringPtr("1.13"),
+						AutoUpgradeMinorVersion: to.BoolPtr(true),
+						Settings:                map[string]interface{}{"workspaceId": "my-workspace"},
+						ProtectedSettings:       map[string]string{"workspaceKey": "my-key"},
+					},
+					Location: to.StringPtr("test-location"),
+				}).Return(&infrav1.Future{Type: async.PutFuture, ServiceName: serviceName, ResourceGroup: "my-rg", Name: "OmsAgentForLinux"}, nil)
+			},
+		},
+		{
+			name:          "error getting the extension specs",
+			expectedError: "failed to get vm extension specs: failed to get Log Analytics workspace secret",
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return(nil, errors.New("failed to get Log Analytics workspace secret"))
 			},
 		},
 	}
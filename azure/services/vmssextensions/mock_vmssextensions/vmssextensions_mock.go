@@ -21,6 +21,7 @@ limitations under the License.
 package mock_vmssextensions
 
 import (
+	context "context"
 	reflect "reflect"
 
 	autorest "github.com/Azure/go-autorest/autorest"
@@ -255,6 +256,20 @@ func (mr *MockVMSSExtensionScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockVMSSExtensionScope)(nil).Location))
 }
 
+// NetworkResourceGroup mocks base method.
+func (m *MockVMSSExtensionScope) NetworkResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NetworkResourceGroup indicates an expected call of NetworkResourceGroup.
+func (mr *MockVMSSExtensionScopeMockRecorder) NetworkResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkResourceGroup", reflect.TypeOf((*MockVMSSExtensionScope)(nil).NetworkResourceGroup))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockVMSSExtensionScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -326,17 +341,18 @@ func (mr *MockVMSSExtensionScopeMockRecorder) V(level interface{}) *gomock.Call
 }
 
 // VMSSExtensionSpecs mocks base method.
-func (m *MockVMSSExtensionScope) VMSSExtensionSpecs() []azure.VMSSExtensionSpec {
+func (m *MockVMSSExtensionScope) VMSSExtensionSpecs(arg0 context.Context) ([]azure.VMSSExtensionSpec, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "VMSSExtensionSpecs")
+	ret := m.ctrl.Call(m, "VMSSExtensionSpecs", arg0)
 	ret0, _ := ret[0].([]azure.VMSSExtensionSpec)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // VMSSExtensionSpecs indicates an expected call of VMSSExtensionSpecs.
-func (mr *MockVMSSExtensionScopeMockRecorder) VMSSExtensionSpecs() *gomock.Call {
+func (mr *MockVMSSExtensionScopeMockRecorder) VMSSExtensionSpecs(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMSSExtensionSpecs", reflect.TypeOf((*MockVMSSExtensionScope)(nil).VMSSExtensionSpecs))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMSSExtensionSpecs", reflect.TypeOf((*MockVMSSExtensionScope)(nil).VMSSExtensionSpecs), arg0)
 }
 
 // WithName mocks base method.
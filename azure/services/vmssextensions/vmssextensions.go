@@ -30,7 +30,7 @@ import (
 type VMSSExtensionScope interface {
 	logr.Logger
 	azure.ClusterDescriber
-	VMSSExtensionSpecs() []azure.VMSSExtensionSpec
+	VMSSExtensionSpecs(context.Context) ([]azure.VMSSExtensionSpec, error)
 	SetBootstrapConditions(string, string) error
 }
 
@@ -53,8 +53,16 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	_, span := tele.Tracer().Start(ctx, "vmssextensions.Service.Reconcile")
 	defer span.End()
 
-	for _, extensionSpec := range s.Scope.VMSSExtensionSpecs() {
+	extensionSpecs, err := s.Scope.VMSSExtensionSpecs(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get vmss extension specs")
+	}
+
+	for _, extensionSpec := range extensionSpecs {
 		if existing, err := s.client.Get(ctx, s.Scope.ResourceGroup(), extensionSpec.ScaleSetName, extensionSpec.Name); err == nil {
+			if extensionSpec.SkipBootstrapConditions {
+				continue
+			}
 			// check the extension status and set the associated conditions.
 			if retErr := s.Scope.SetBootstrapConditions(to.String(existing.ProvisioningState), extensionSpec.Name); retErr != nil {
 				return retErr
@@ -29,6 +29,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"k8s.io/klog/v2/klogr"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
@@ -45,14 +46,14 @@ func TestReconcileVMSSExtension(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_vmssextensions.MockVMSSExtensionScopeMockRecorder, m *mock_vmssextensions.MockclientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.VMSSExtensionSpecs().Return([]azure.VMSSExtensionSpec{
+				s.VMSSExtensionSpecs(gomockinternal.AContext()).Return([]azure.VMSSExtensionSpec{
 					{
 						Name:         "my-extension-1",
 						ScaleSetName: "my-vmss",
 						Publisher:    "some-publisher",
 						Version:      "1.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss", "my-extension-1").Return(compute.VirtualMachineScaleSetExtension{
@@ -72,7 +73,7 @@ func TestReconcileVMSSExtension(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_vmssextensions.MockVMSSExtensionScopeMockRecorder, m *mock_vmssextensions.MockclientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.VMSSExtensionSpecs().Return([]azure.VMSSExtensionSpec{
+				s.VMSSExtensionSpecs(gomockinternal.AContext()).Return([]azure.VMSSExtensionSpec{
 					{
 						Name:         "my-extension-1",
 						ScaleSetName: "my-vmss",
@@ -85,7 +86,7 @@ func TestReconcileVMSSExtension(t *testing.T) {
 						Publisher:    "other-publisher",
 						Version:      "2.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss", "my-extension-1").
@@ -99,7 +100,7 @@ func TestReconcileVMSSExtension(t *testing.T) {
 			expectedError: "failed to get vm extension my-extension-1 on scale set my-vmss: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_vmssextensions.MockVMSSExtensionScopeMockRecorder, m *mock_vmssextensions.MockclientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.VMSSExtensionSpecs().Return([]azure.VMSSExtensionSpec{
+				s.VMSSExtensionSpecs(gomockinternal.AContext()).Return([]azure.VMSSExtensionSpec{
 					{
 						Name:         "my-extension-1",
 						ScaleSetName: "my-vmss",
@@ -112,13 +113,49 @@ func TestReconcileVMSSExtension(t *testing.T) {
 						Publisher:    "other-publisher",
 						Version:      "2.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss", "my-extension-1").
 					Return(compute.VirtualMachineScaleSetExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
+		{
+			name:          "monitoring extension does not set the bootstrap conditions",
+			expectedError: "",
+			expect: func(s *mock_vmssextensions.MockVMSSExtensionScopeMockRecorder, m *mock_vmssextensions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VMSSExtensionSpecs(gomockinternal.AContext()).Return([]azure.VMSSExtensionSpec{
+					{
+						Name:                    "AzureMonitorLinuxAgent",
+						ScaleSetName:            "my-vmss",
+						Publisher:               "Microsoft.Azure.Monitor",
+						Version:                 "1.0",
+						AutoUpgradeMinorVersion: true,
+						SkipBootstrapConditions: true,
+					},
+				}, nil)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("test-location")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss", "AzureMonitorLinuxAgent").Return(compute.VirtualMachineScaleSetExtension{
+					Name: to.StringPtr("AzureMonitorLinuxAgent"),
+					VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+						Publisher:         to.StringPtr("Microsoft.Azure.Monitor"),
+						Type:              to.StringPtr("AzureMonitorLinuxAgent"),
+						ProvisioningState: to.StringPtr(string(compute.ProvisioningStateFailed)),
+					},
+					ID: to.StringPtr("some/fake/id"),
+				}, nil)
+			},
+		},
+		{
+			name:          "error getting the extension specs",
+			expectedError: "failed to get vmss extension specs: failed to get Log Analytics workspace secret",
+			expect: func(s *mock_vmssextensions.MockVMSSExtensionScopeMockRecorder, m *mock_vmssextensions.MockclientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VMSSExtensionSpecs(gomockinternal.AContext()).Return(nil, errors.New("failed to get Log Analytics workspace secret"))
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// WithTracing returns a SendDecorator which wraps each request sent to the Azure API in a trace span named after its
+// HTTP method and resource type, carrying the resource group and name of the resource it refers to.
+func WithTracing() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+			resourceType := resourceTypeFromPath(req.URL.Path)
+			attrs := append(resourceAttributesFromPath(req.URL.Path),
+				semconv.HTTPMethodKey.String(req.Method),
+				attribute.String("azure.resource_type", resourceType),
+			)
+			ctx, span := tele.Tracer().Start(req.Context(), fmt.Sprintf("%s %s", req.Method, resourceType),
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...),
+			)
+			defer span.End()
+
+			resp, err := s.Do(req.WithContext(ctx))
+			if resp != nil {
+				span.SetAttributes(semconv.HTTPStatusCodeKey.Int(resp.StatusCode))
+				if resp.StatusCode >= http.StatusBadRequest {
+					span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+				}
+			}
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return resp, err
+		})
+	}
+}
+
+// resourceAttributesFromPath returns the span attributes of the resource group and name an Azure Resource Manager
+// request path refers to. The name is only set for paths ending with one, not for the ones listing resources.
+func resourceAttributesFromPath(path string) []attribute.KeyValue {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	attrs := []attribute.KeyValue{}
+	for i := 0; i+1 < len(segments); i += 2 {
+		if strings.EqualFold(segments[i], "resourceGroups") {
+			attrs = append(attrs, attribute.String("azure.resource_group", segments[i+1]))
+			break
+		}
+	}
+
+	start := 0
+	for i := len(segments) - 2; i >= 0; i-- {
+		if strings.EqualFold(segments[i], "providers") {
+			// the resource provider namespace is followed by pairs of resource types and names
+			start = i + 2
+			break
+		}
+	}
+	if len(segments) > start && (len(segments)-start)%2 == 0 {
+		attrs = append(attrs, attribute.String("azure.resource_name", segments[len(segments)-1]))
+	}
+	return attrs
+}
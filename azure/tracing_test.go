@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+func TestResourceAttributesFromPath(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		path string
+		want []attribute.KeyValue
+	}{
+		{
+			path: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet",
+			want: []attribute.KeyValue{
+				attribute.String("azure.resource_group", "my-rg"),
+				attribute.String("azure.resource_name", "my-subnet"),
+			},
+		},
+		{
+			path: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/my-vmss/virtualMachines",
+			want: []attribute.KeyValue{
+				attribute.String("azure.resource_group", "my-rg"),
+			},
+		},
+		{
+			path: "/subscriptions/123/providers/Microsoft.Compute/skus",
+			want: []attribute.KeyValue{},
+		},
+		{
+			path: "/subscriptions/123/resourcegroups/my-rg",
+			want: []attribute.KeyValue{
+				attribute.String("azure.resource_group", "my-rg"),
+				attribute.String("azure.resource_name", "my-rg"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		g.Expect(resourceAttributesFromPath(tt.path)).To(Equal(tt.want), tt.path)
+	}
+}
+
+func TestTracingPropagatesCorrelationID(t *testing.T) {
+	g := NewWithT(t)
+
+	var sent *http.Request
+	sender := WithTracing()(autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+		sent = req
+		return &http.Response{StatusCode: http.StatusOK, Request: req}, nil
+	}))
+	req := &http.Request{Method: http.MethodGet, URL: &url.URL{Path: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"}}
+
+	_, err := sender.Do(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sent).NotTo(BeNil())
+	_, ok := tele.CorrIDFromCtx(sent.Context())
+	g.Expect(ok).To(BeTrue())
+}
@@ -29,6 +29,9 @@ type PublicIPSpec struct {
 	Name    string
 	DNSName string
 	IsIPv6  bool
+	// Zones are the availability zones of the public IP. A single zone makes the IP zonal, several zones make it
+	// zone-redundant and no zones leave the zone placement to Azure.
+	Zones []string
 }
 
 // NICSpec defines the specification for a Network Interface.
@@ -67,6 +70,8 @@ type LBSpec struct {
 	FrontendIPConfigs    []infrav1.FrontendIP
 	APIServerPort        int32
 	IdleTimeoutInMinutes *int32
+	// FrontendIPZones are the availability zones of the private frontend IPs of an internal load balancer.
+	FrontendIPZones []string
 }
 
 // RouteTableRole defines the unique role of a route table.
@@ -89,6 +94,8 @@ type NatGatewaySpec struct {
 type InboundNatSpec struct {
 	Name             string
 	LoadBalancerName string
+	// BackendPort is the port the rule forwards to, SSHPort if zero.
+	BackendPort int32
 }
 
 // SubnetSpec defines the specification for a Subnet.
@@ -158,10 +165,12 @@ type BastionSpec struct {
 
 // AzureBastionSpec defines the specification for azure bastion feature.
 type AzureBastionSpec struct { // nolint
-	Name         string
-	SubnetSpec   infrav1.SubnetSpec
-	PublicIPName string
-	VNetName     string
+	Name              string
+	SubnetSpec        infrav1.SubnetSpec
+	PublicIPName      string
+	VNetName          string
+	VNetResourceGroup string
+	DNSName           string
 }
 
 // ScaleSetSpec defines the specification for a Scale Set.
@@ -193,6 +202,12 @@ type TagsSpec struct {
 	Annotation string
 }
 
+// AdoptionSpec defines the specification for the adoption of a pre-existing resource.
+type AdoptionSpec struct {
+	Scope     string
+	Lifecycle infrav1.ResourceLifecycle
+}
+
 // PrivateDNSSpec defines the specification for a private DNS zone.
 type PrivateDNSSpec struct {
 	ZoneName          string
@@ -202,6 +217,13 @@ type PrivateDNSSpec struct {
 	Records           []infrav1.AddressRecord
 }
 
+// DNSForwardingRulesetLinkSpec defines the specification for a virtual network link to a DNS forwarding ruleset.
+type DNSForwardingRulesetLinkSpec struct {
+	Name      string
+	RulesetID string
+	VNetID    string
+}
+
 // AvailabilitySetSpec defines the specification for an availability set.
 type AvailabilitySetSpec struct {
 	Name string
@@ -213,7 +235,31 @@ type VMExtensionSpec struct {
 	VMName            string
 	Publisher         string
 	Version           string
+	Settings          map[string]interface{}
 	ProtectedSettings map[string]string
+	// AutoUpgradeMinorVersion installs the latest minor version of the extension rather than Version.
+	AutoUpgradeMinorVersion bool
+	// SkipBootstrapConditions is true for the extensions whose provisioning state doesn't report on the bootstrap of
+	// the VM, e.g. the monitoring agents.
+	SkipBootstrapConditions bool
+}
+
+// BootstrapDataSecretSpec defines the specification for the Key Vault secret storing the bootstrap data of a VM.
+type BootstrapDataSecretSpec struct {
+	Name     string
+	VaultURL string
+	// Consumed is true once the node of the VM has joined the cluster, and the secret is no longer needed.
+	Consumed bool
+	// State is the recorded state of the secret: Succeeded once it is created, Deleted once it is deleted, or empty.
+	State infrav1.ProvisioningState
+}
+
+// BackupSpec defines the specification for the backup protection of a VM by a Recovery Services vault.
+type BackupSpec struct {
+	VMName             string
+	VaultResourceGroup string
+	VaultName          string
+	PolicyName         string
 }
 
 // VMSSExtensionSpec defines the specification for a VMSS extension.
@@ -222,7 +268,13 @@ type VMSSExtensionSpec struct {
 	ScaleSetName      string
 	Publisher         string
 	Version           string
+	Settings          map[string]interface{}
 	ProtectedSettings map[string]string
+	// AutoUpgradeMinorVersion installs the latest minor version of the extension rather than Version.
+	AutoUpgradeMinorVersion bool
+	// SkipBootstrapConditions is true for the extensions whose provisioning state doesn't report on the bootstrap of
+	// the instances, e.g. the monitoring agents.
+	SkipBootstrapConditions bool
 }
 
 type (
@@ -401,3 +453,37 @@ type AgentPoolSpec struct {
 	// Mode represents mode of an agent pool. Possible values include: 'System', 'User'.
 	Mode string
 }
+
+// Types of the Azure resources whose monthly cost is estimated.
+const (
+	VirtualMachineResourceType = "Microsoft.Compute/virtualMachines"
+	DiskResourceType           = "Microsoft.Compute/disks"
+	PublicIPResourceType       = "Microsoft.Network/publicIPAddresses"
+	LoadBalancerResourceType   = "Microsoft.Network/loadBalancers"
+	NatGatewayResourceType     = "Microsoft.Network/natGateways"
+	BastionHostResourceType    = "Microsoft.Network/bastionHosts"
+)
+
+// CostSpec defines the specification of an Azure resource whose monthly cost is estimated.
+type CostSpec struct {
+	// Name is the name of the resource.
+	Name string
+
+	// Type is the type of the resource, e.g. Microsoft.Compute/virtualMachines.
+	Type string
+
+	// SKU is the size of a virtual machine, the storage account type of a disk, or the SKU of the other resources.
+	SKU string
+
+	// DiskSizeGB is the size of a disk.
+	DiskSizeGB int32
+
+	// IsIPv6 is true for an IPv6 public IP.
+	IsIPv6 bool
+
+	// IsWindows is true for a virtual machine running Windows, whose price includes the Windows license.
+	IsWindows bool
+
+	// IsSpot is true for a Spot virtual machine.
+	IsSpot bool
+}
@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
@@ -33,21 +34,25 @@ import (
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+// dnsLabelSuffixLength is the length of the random suffix appended to the DNS labels generated for a cluster.
+const dnsLabelSuffixLength = 6
+
 // AzureClusterReconciler reconciles an AzureCluster object.
 type AzureClusterReconciler struct {
 	client.Client
@@ -55,19 +60,22 @@ type AzureClusterReconciler struct {
 	Recorder                  record.EventRecorder
 	ReconcileTimeout          time.Duration
 	WatchFilterValue          string
+	LockResourceGroups        bool
+	requeueInterval           time.Duration
 	createAzureClusterService azureClusterServiceCreator
 }
 
 type azureClusterServiceCreator func(clusterScope *scope.ClusterScope) (*azureClusterService, error)
 
 // NewAzureClusterReconciler returns a new AzureClusterReconciler instance.
-func NewAzureClusterReconciler(client client.Client, log logr.Logger, recorder record.EventRecorder, reconcileTimeout time.Duration, watchFilterValue string) *AzureClusterReconciler {
+func NewAzureClusterReconciler(client client.Client, log logr.Logger, recorder record.EventRecorder, reconcileTimeout time.Duration, watchFilterValue string, lockResourceGroups bool) *AzureClusterReconciler {
 	acr := &AzureClusterReconciler{
-		Client:           client,
-		Log:              log,
-		Recorder:         recorder,
-		ReconcileTimeout: reconcileTimeout,
-		WatchFilterValue: watchFilterValue,
+		Client:             client,
+		Log:                log,
+		Recorder:           recorder,
+		ReconcileTimeout:   reconcileTimeout,
+		WatchFilterValue:   watchFilterValue,
+		LockResourceGroups: lockResourceGroups,
 	}
 
 	acr.createAzureClusterService = newAzureClusterService
@@ -76,10 +84,11 @@ func NewAzureClusterReconciler(client client.Client, log logr.Logger, recorder r
 }
 
 // SetupWithManager initializes this controller with a manager.
-func (r *AzureClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+func (r *AzureClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options Options) error {
 	log := r.Log.WithValues("controller", "AzureCluster")
+	r.requeueInterval = options.RequeueInterval
 	c, err := ctrl.NewControllerManagedBy(mgr).
-		WithOptions(options).
+		WithOptions(options.Options).
 		For(&infrav1.AzureCluster{}).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
 		WithEventFilter(predicates.ResourceIsNotExternallyManaged(ctrl.LoggerFrom(ctx))).
@@ -97,12 +106,20 @@ func (r *AzureClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.
 		return errors.Wrap(err, "failed adding a watch for ready clusters")
 	}
 
+	// Add a watch on the objects whose Azure resources were modified out-of-band.
+	if options.DriftEvents != nil {
+		if err := c.Watch(&source.Channel{Source: options.DriftEvents}, &handler.EnqueueRequestForObject{}); err != nil {
+			return errors.Wrap(err, "failed adding a watch for drift events")
+		}
+	}
+
 	return nil
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=controlplane.cluster.x-k8s.io,resources=kubeadmcontrolplanes,verbs=get;list;watch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachinetemplates;azuremachinetemplates/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusteridentities;azureclusteridentities/status,verbs=get;list;watch;create;update;patch;delete
 
@@ -132,6 +149,14 @@ func (r *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return reconcile.Result{}, err
 	}
 
+	// Record the lifecycle events of the Azure resources of the cluster on the AzureCluster.
+	ctx = azure.WithEventRecorder(ctx, r.Recorder, azureCluster)
+
+	// Report the changes to the Azure resources of the cluster instead of making them in dry-run mode.
+	if azure.IsDryRun(ctx) || azureCluster.Annotations[infrav1.DryRunAnnotation] == "true" {
+		ctx = azure.WithDryRun(ctx)
+	}
+
 	// Fetch the Cluster.
 	cluster, err := util.GetOwnerCluster(ctx, r.Client, azureCluster.ObjectMeta)
 	if err != nil {
@@ -153,13 +178,19 @@ func (r *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	if azureCluster.Spec.IdentityRef != nil {
-		identity, err := GetClusterIdentityFromRef(ctx, r.Client, azureCluster.Namespace, azureCluster.Spec.IdentityRef)
+		identity, err := ValidateClusterIdentity(ctx, r.Client, azureCluster.Namespace, azureCluster.Spec.IdentityRef)
 		if err != nil {
+			var identityErr *IdentityError
+			if errors.As(err, &identityErr) {
+				r.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, identityErr.Reason, identityErr.Error())
+				if patchErr := r.markIdentityNotReady(ctx, azureCluster, identityErr); patchErr != nil {
+					log.Error(patchErr, "failed to set the IdentityReady condition")
+				}
+			}
 			return reconcile.Result{}, err
 		}
-		if !scope.IsClusterNamespaceAllowed(ctx, r.Client, identity.Spec.AllowedNamespaces, azureCluster.Namespace) {
-			conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, infrav1.NamespaceNotAllowedByIdentity, clusterv1.ConditionSeverityError, "")
-			return reconcile.Result{}, errors.New("AzureClusterIdentity list of allowed namespaces doesn't include current cluster namespace")
+		if err := EnsureIdentitySecretMoveLabel(ctx, r.Client, identity); err != nil {
+			return reconcile.Result{}, err
 		}
 	} else {
 		log.Info(fmt.Sprintf("WARNING, %s", deprecatedManagerCredsWarning))
@@ -168,10 +199,11 @@ func (r *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	// Create the scope.
 	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
-		Client:       r.Client,
-		Logger:       log,
-		Cluster:      cluster,
-		AzureCluster: azureCluster,
+		Client:            r.Client,
+		Logger:            log,
+		Cluster:           cluster,
+		AzureCluster:      azureCluster,
+		LockResourceGroup: r.LockResourceGroups,
 	})
 	if err != nil {
 		err = errors.Errorf("failed to create scope: %+v", err)
@@ -179,6 +211,9 @@ func (r *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return reconcile.Result{}, err
 	}
 
+	// Use the API versions supported by the cloud of the cluster, e.g. an Azure Stack Hub.
+	ctx = azure.WithAPIProfile(ctx, azureCluster.Spec.APIProfile, clusterScope.BaseURI())
+
 	// Always close the scope when exiting this function so we can persist any AzureMachine changes.
 	defer func() {
 		if err := clusterScope.Close(ctx); err != nil && reterr == nil {
@@ -186,6 +221,10 @@ func (r *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		}
 	}()
 
+	if azureCluster.Spec.IdentityRef != nil {
+		conditions.MarkTrue(azureCluster, infrav1.IdentityReadyCondition)
+	}
+
 	// Handle deleted clusters
 	if !azureCluster.DeletionTimestamp.IsZero() {
 		return r.reconcileDelete(ctx, clusterScope)
@@ -195,6 +234,20 @@ func (r *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	return r.reconcileNormal(ctx, clusterScope)
 }
 
+// markIdentityNotReady sets the IdentityReady condition of an AzureCluster whose identity can't be used. The condition is
+// patched right away, as the cluster scope can't be created without a usable identity.
+func (r *AzureClusterReconciler) markIdentityNotReady(ctx context.Context, azureCluster *infrav1.AzureCluster, identityErr *IdentityError) error {
+	patchHelper, err := patch.NewHelper(azureCluster, r.Client)
+	if err != nil {
+		return err
+	}
+	conditions.MarkFalse(azureCluster, infrav1.IdentityReadyCondition, identityErr.Reason, clusterv1.ConditionSeverityError, identityErr.Error())
+	if identityErr.Reason == infrav1.NamespaceNotAllowedByIdentity {
+		conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, infrav1.NamespaceNotAllowedByIdentity, clusterv1.ConditionSeverityError, "")
+	}
+	return patchHelper.Patch(ctx, azureCluster)
+}
+
 func (r *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
 	ctx, span := tele.Tracer().Start(ctx, "controllers.AzureClusterReconciler.reconcileNormal")
 	defer span.End()
@@ -204,7 +257,11 @@ func (r *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterSco
 
 	// If the AzureCluster doesn't have our finalizer, add it.
 	controllerutil.AddFinalizer(azureCluster, infrav1.ClusterFinalizer)
-	// Register the finalizer immediately to avoid orphaning Azure resources on delete
+	// Generate the suffix of the DNS labels of the cluster once, so that the labels remain stable.
+	if clusterScope.DNSLabelSuffix() == "" {
+		clusterScope.SetDNSLabelSuffix(strings.ToLower(util.RandomString(dnsLabelSuffixLength)))
+	}
+	// Register the finalizer and the DNS label suffix immediately to avoid orphaning Azure resources on delete
 	if err := clusterScope.PatchObject(ctx); err != nil {
 		return reconcile.Result{}, err
 	}
@@ -215,8 +272,24 @@ func (r *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterSco
 	}
 
 	if err := acr.Reconcile(ctx); err != nil {
+		// Long-running operations on Azure resources are not waited for: requeue until they are done.
+		var reconcileError azure.ReconcileError
+		if errors.As(err, &reconcileError) && reconcileError.IsTransient() && azure.IsOperationNotDoneError(err) {
+			clusterScope.V(2).Info("AzureCluster reconcile not done, requeueing", "reason", err.Error())
+			return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}, nil
+		}
+
 		wrappedErr := errors.Wrap(err, "failed to reconcile cluster services")
-		r.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "ClusterReconcilerNormalFailed", wrappedErr.Error())
+		r.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "ClusterReconcilerNormalFailed", azure.ErrorMessage(wrappedErr))
+
+		// A DNS label conflict can't be resolved by retrying, so we surface it and stop requeueing. The DNS label suffix
+		// is persisted, so only removing its annotation, which generates a new suffix, resolves the conflict.
+		if errors.As(err, &reconcileError) && reconcileError.IsTerminal() && azure.DNSRecordInUse(err) {
+			clusterScope.Error(err, "DNS label conflict detected, will not requeue")
+			conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, infrav1.DNSLabelConflictReason, clusterv1.ConditionSeverityError,
+				"%s: remove the %s annotation to generate a new DNS label", err.Error(), infrav1.DNSLabelSuffixAnnotation)
+			return reconcile.Result{}, nil
+		}
 		return reconcile.Result{}, wrappedErr
 	}
 
@@ -226,11 +299,18 @@ func (r *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterSco
 		Port: clusterScope.APIServerPort(),
 	}
 
+	// In dry-run mode, the cluster is not ready until the skipped changes are made.
+	if skipped := azure.DryRunOperations(ctx); len(skipped) > 0 {
+		clusterScope.Info("AzureCluster reconciled in dry-run mode", "skippedOperations", len(skipped))
+		conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, infrav1.DryRunReason, clusterv1.ConditionSeverityInfo, "%d Azure operations skipped in dry-run mode", len(skipped))
+		return reconcile.Result{RequeueAfter: r.requeueInterval}, nil
+	}
+
 	// No errors, so mark us ready so the Cluster API Cluster Controller can pull it
 	azureCluster.Status.Ready = true
 	conditions.MarkTrue(azureCluster, infrav1.NetworkInfrastructureReadyCondition)
 
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: r.requeueInterval}, nil
 }
 
 func (r *AzureClusterReconciler) reconcileDelete(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
@@ -240,6 +320,12 @@ func (r *AzureClusterReconciler) reconcileDelete(ctx context.Context, clusterSco
 	clusterScope.Info("Reconciling AzureCluster delete")
 
 	azureCluster := clusterScope.AzureCluster
+	if IsDeletedForMove(azureCluster) {
+		clusterScope.Info("AzureCluster was moved to another management cluster, skipping the deletion of its Azure resources")
+		controllerutil.RemoveFinalizer(azureCluster, infrav1.ClusterFinalizer)
+		return reconcile.Result{}, nil
+	}
+
 	conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
 	if err := clusterScope.PatchObject(ctx); err != nil {
 		return reconcile.Result{}, err
@@ -251,9 +337,15 @@ func (r *AzureClusterReconciler) reconcileDelete(ctx context.Context, clusterSco
 	}
 
 	if err := acr.Delete(ctx); err != nil {
+		var reconcileError azure.ReconcileError
+		if errors.As(err, &reconcileError) && reconcileError.IsTransient() && azure.IsOperationNotDoneError(err) {
+			clusterScope.V(2).Info("AzureCluster delete not done, requeueing", "reason", err.Error())
+			return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}, nil
+		}
+
 		wrappedErr := errors.Wrapf(err, "error deleting AzureCluster %s/%s", azureCluster.Namespace, azureCluster.Name)
-		r.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "ClusterReconcilerDeleteFailed", wrappedErr.Error())
-		conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, clusterv1.DeletionFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		r.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "ClusterReconcilerDeleteFailed", azure.ErrorMessage(wrappedErr))
+		conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, clusterv1.DeletionFailedReason, clusterv1.ConditionSeverityWarning, azure.ErrorMessage(err))
 		return reconcile.Result{}, wrappedErr
 	}
 
@@ -81,7 +81,7 @@ var _ = Describe("AzureClusterReconciler", func() {
 
 			c, err := client.New(testEnv.Config, client.Options{Scheme: testEnv.GetScheme()})
 			Expect(err).ToNot(HaveOccurred())
-			reconciler := NewAzureClusterReconciler(c, log, testEnv.GetEventRecorderFor("azurecluster-reconciler"), 1*time.Second, "")
+			reconciler := NewAzureClusterReconciler(c, log, testEnv.GetEventRecorderFor("azurecluster-reconciler"), 1*time.Second, "", false)
 
 			instance := &infrav1.AzureCluster{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}}
 			_, err = reconciler.Reconcile(ctx, ctrl.Request{
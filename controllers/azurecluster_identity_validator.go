@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+)
+
+// identityClientSecretKey is the key of the client secret of a service principal in the secret referenced by an
+// AzureClusterIdentity.
+const identityClientSecretKey = "clientSecret"
+
+// IdentityError is returned when the AzureClusterIdentity referenced by a cluster can't be used. Its reason is set on
+// the IdentityReady condition of the cluster.
+type IdentityError struct {
+	Reason  string
+	Message string
+}
+
+// Error returns the message of an IdentityError.
+func (e *IdentityError) Error() string {
+	return e.Message
+}
+
+// ValidateClusterIdentity returns the AzureClusterIdentity referenced by a cluster in the given namespace, or an
+// IdentityError if the identity doesn't exist, doesn't allow the namespace, or has a missing or malformed client secret.
+func ValidateClusterIdentity(ctx context.Context, c client.Client, namespace string, ref *corev1.ObjectReference) (*infrav1.AzureClusterIdentity, error) {
+	identity, err := GetClusterIdentityFromRef(ctx, c, namespace, ref)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, &IdentityError{
+				Reason:  infrav1.IdentityNotFoundReason,
+				Message: fmt.Sprintf("AzureClusterIdentity %s not found", identityRefName(namespace, ref)),
+			}
+		}
+		return nil, errors.Wrapf(err, "failed to get AzureClusterIdentity %s", identityRefName(namespace, ref))
+	}
+
+	if !scope.IsClusterNamespaceAllowed(ctx, c, identity.Spec.AllowedNamespaces, namespace) {
+		return nil, &IdentityError{
+			Reason:  infrav1.NamespaceNotAllowedByIdentity,
+			Message: fmt.Sprintf("AzureClusterIdentity %s/%s list of allowed namespaces doesn't include namespace %s", identity.Namespace, identity.Name, namespace),
+		}
+	}
+
+	if err := validateIdentitySecret(ctx, c, identity); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// validateIdentitySecret returns an IdentityError if the client secret of a service principal identity is not
+// referenced, doesn't exist or doesn't hold a client secret. User-assigned managed identities don't have a client secret.
+func validateIdentitySecret(ctx context.Context, c client.Client, identity *infrav1.AzureClusterIdentity) error {
+	if identity.Spec.Type != infrav1.ServicePrincipal && identity.Spec.Type != infrav1.ManualServicePrincipal {
+		return nil
+	}
+
+	secretRef := identity.Spec.ClientSecret
+	if secretRef.Name == "" {
+		return &IdentityError{
+			Reason:  infrav1.InvalidIdentitySecretReason,
+			Message: fmt.Sprintf("AzureClusterIdentity %s/%s of type %s doesn't reference a client secret", identity.Namespace, identity.Name, identity.Spec.Type),
+		}
+	}
+
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: secretRef.Namespace, Name: secretRef.Name}
+	if err := c.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &IdentityError{
+				Reason:  infrav1.IdentitySecretNotFoundReason,
+				Message: fmt.Sprintf("client secret %s/%s of AzureClusterIdentity %s/%s not found", key.Namespace, key.Name, identity.Namespace, identity.Name),
+			}
+		}
+		return errors.Wrapf(err, "failed to get client secret of AzureClusterIdentity %s/%s", identity.Namespace, identity.Name)
+	}
+
+	if len(secret.Data[identityClientSecretKey]) == 0 {
+		return &IdentityError{
+			Reason:  infrav1.InvalidIdentitySecretReason,
+			Message: fmt.Sprintf("client secret %s/%s of AzureClusterIdentity %s/%s has no %q key", key.Namespace, key.Name, identity.Namespace, identity.Name, identityClientSecretKey),
+		}
+	}
+	return nil
+}
+
+// identityRefName returns the namespaced name of the AzureClusterIdentity referenced by a cluster in the given namespace.
+func identityRefName(namespace string, ref *corev1.ObjectReference) string {
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+	return fmt.Sprintf("%s/%s", namespace, ref.Name)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+)
+
+func newIdentity(identityType infrav1.IdentityType, allowedNamespaces *infrav1.AllowedNamespaces) *infrav1.AzureClusterIdentity {
+	return &infrav1.AzureClusterIdentity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-identity",
+			Namespace: "identities",
+		},
+		Spec: infrav1.AzureClusterIdentitySpec{
+			Type:     identityType,
+			ClientID: "client-id",
+			TenantID: "tenant-id",
+			ClientSecret: corev1.SecretReference{
+				Name:      "my-identity-secret",
+				Namespace: "identities",
+			},
+			AllowedNamespaces: allowedNamespaces,
+		},
+	}
+}
+
+func newIdentitySecret(data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-identity-secret",
+			Namespace: "identities",
+		},
+		Data: data,
+	}
+}
+
+func TestValidateClusterIdentity(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := newScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ref := &corev1.ObjectReference{Name: "my-identity", Namespace: "identities"}
+	allNamespaces := &infrav1.AllowedNamespaces{}
+	validSecret := newIdentitySecret(map[string][]byte{"clientSecret": []byte("secret")})
+	withoutSecretRef := newIdentity(infrav1.ManualServicePrincipal, allNamespaces)
+	withoutSecretRef.Spec.ClientSecret = corev1.SecretReference{}
+
+	cases := map[string]struct {
+		objects        []client.Object
+		expectedReason string
+	}{
+		"valid service principal identity": {
+			objects: []client.Object{newIdentity(infrav1.ServicePrincipal, allNamespaces), validSecret},
+		},
+		"valid manual service principal identity": {
+			objects: []client.Object{newIdentity(infrav1.ManualServicePrincipal, allNamespaces), validSecret},
+		},
+		"user-assigned managed identity without a secret": {
+			objects: []client.Object{newIdentity(infrav1.UserAssignedMSI, allNamespaces)},
+		},
+		"identity namespace list includes the cluster namespace": {
+			objects: []client.Object{newIdentity(infrav1.ServicePrincipal, &infrav1.AllowedNamespaces{NamespaceList: []string{"default"}}), validSecret},
+		},
+		"identity not found": {
+			expectedReason: infrav1.IdentityNotFoundReason,
+		},
+		"identity doesn't allow any namespace": {
+			objects:        []client.Object{newIdentity(infrav1.ServicePrincipal, nil), validSecret},
+			expectedReason: infrav1.NamespaceNotAllowedByIdentity,
+		},
+		"identity namespace list doesn't include the cluster namespace": {
+			objects:        []client.Object{newIdentity(infrav1.ServicePrincipal, &infrav1.AllowedNamespaces{NamespaceList: []string{"other"}}), validSecret},
+			expectedReason: infrav1.NamespaceNotAllowedByIdentity,
+		},
+		"identity without a client secret reference": {
+			objects:        []client.Object{withoutSecretRef},
+			expectedReason: infrav1.InvalidIdentitySecretReason,
+		},
+		"client secret not found": {
+			objects:        []client.Object{newIdentity(infrav1.ServicePrincipal, allNamespaces)},
+			expectedReason: infrav1.IdentitySecretNotFoundReason,
+		},
+		"client secret without the clientSecret key": {
+			objects:        []client.Object{newIdentity(infrav1.ServicePrincipal, allNamespaces), newIdentitySecret(map[string][]byte{"password": []byte("secret")})},
+			expectedReason: infrav1.InvalidIdentitySecretReason,
+		},
+		"empty client secret": {
+			objects:        []client.Object{newIdentity(infrav1.ServicePrincipal, allNamespaces), newIdentitySecret(map[string][]byte{"clientSecret": {}})},
+			expectedReason: infrav1.InvalidIdentitySecretReason,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.objects...).Build()
+
+			identity, err := ValidateClusterIdentity(context.TODO(), c, "default", ref)
+			if tc.expectedReason != "" {
+				var identityErr *IdentityError
+				g.Expect(err).To(BeAssignableToTypeOf(identityErr))
+				g.Expect(err.(*IdentityError).Reason).To(Equal(tc.expectedReason))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(identity.Name).To(Equal("my-identity"))
+			}
+		})
+	}
+}
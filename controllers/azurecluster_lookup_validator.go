@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+)
+
+// AzureClusterLookupValidator rejects AzureClusters whose subnet CIDRs collide with the pod or service CIDRs declared
+// in the cluster network of their Cluster, or which reference an AzureClusterIdentity which doesn't allow their
+// namespace or whose client secret is malformed. An identity or a client secret which doesn't exist yet is not
+// rejected, as they are commonly created along with or after the AzureCluster, and is reported by the IdentityReady
+// condition.
+type AzureClusterLookupValidator struct {
+	Client  client.Client
+	Log     logr.Logger
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &AzureClusterLookupValidator{}
+var _ admission.DecoderInjector = &AzureClusterLookupValidator{}
+
+// InjectDecoder injects the decoder into an AzureClusterLookupValidator.
+func (v *AzureClusterLookupValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle handles admission requests.
+func (v *AzureClusterLookupValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	azureCluster := &infrav1.AzureCluster{}
+	if err := v.decoder.Decode(req, azureCluster); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	var old *infrav1.AzureCluster
+	if req.Operation == admissionv1.Update {
+		old = &infrav1.AzureCluster{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+
+	log := v.Log.WithValues("namespace", azureCluster.Namespace, "azureCluster", azureCluster.Name)
+	var errs []error
+	if err := v.validateNetwork(ctx, log, azureCluster); err != nil {
+		errs = append(errs, err)
+	}
+	if err := v.validateIdentity(ctx, log, old, azureCluster); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return admission.Denied(kerrors.NewAggregate(errs).Error())
+	}
+	return admission.Allowed("")
+}
+
+// validateNetwork returns an error if the subnet CIDRs of the AzureCluster collide with the cluster network of its
+// Cluster.
+func (v *AzureClusterLookupValidator) validateNetwork(ctx context.Context, log logr.Logger, azureCluster *infrav1.AzureCluster) error {
+	cluster, err := v.getCluster(ctx, azureCluster)
+	if err != nil {
+		log.V(2).Info("skipping cluster network validation", "reason", err.Error())
+		return nil
+	}
+	if cluster == nil || cluster.Spec.ClusterNetwork == nil {
+		// The Cluster may be created after its AzureCluster, in which case there is nothing to validate against yet.
+		return nil
+	}
+	return validateAzureClusterNetwork(azureCluster, cluster)
+}
+
+// validateIdentity returns an error if the AzureClusterIdentity referenced by the AzureCluster can't be used by it.
+func (v *AzureClusterLookupValidator) validateIdentity(ctx context.Context, log logr.Logger, old, azureCluster *infrav1.AzureCluster) error {
+	if azureCluster.Spec.IdentityRef == nil || !azureCluster.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	// Only validate changes of the identity reference, so that an identity going bad doesn't block the updates needed
+	// to fix or delete the cluster.
+	if old != nil && equality.Semantic.DeepEqual(old.Spec.IdentityRef, azureCluster.Spec.IdentityRef) {
+		return nil
+	}
+
+	_, err := ValidateClusterIdentity(ctx, v.Client, azureCluster.Namespace, azureCluster.Spec.IdentityRef)
+	if err == nil {
+		return nil
+	}
+	var identityErr *IdentityError
+	if !errors.As(err, &identityErr) {
+		log.V(2).Info("skipping identity validation", "reason", err.Error())
+		return nil
+	}
+	switch identityErr.Reason {
+	case infrav1.IdentityNotFoundReason, infrav1.IdentitySecretNotFoundReason:
+		return nil
+	}
+	return err
+}
+
+// getCluster returns the Cluster whose infrastructure reference points to the AzureCluster, or nil if there is none.
+// The Cluster is looked up by reference rather than by the cluster name label, which may not be set yet on creation.
+func (v *AzureClusterLookupValidator) getCluster(ctx context.Context, azureCluster *infrav1.AzureCluster) (*clusterv1.Cluster, error) {
+	clusters := &clusterv1.ClusterList{}
+	if err := v.Client.List(ctx, clusters, client.InNamespace(azureCluster.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list clusters")
+	}
+
+	for i := range clusters.Items {
+		ref := clusters.Items[i].Spec.InfrastructureRef
+		if ref != nil && ref.Kind == "AzureCluster" && ref.Name == azureCluster.Name {
+			return &clusters.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// validateAzureClusterNetwork returns an error if the subnet CIDRs of the AzureCluster collide with the pod or
+// service CIDRs of its Cluster.
+func validateAzureClusterNetwork(azureCluster *infrav1.AzureCluster, cluster *clusterv1.Cluster) error {
+	var podCIDRBlocks, serviceCIDRBlocks []string
+	if cluster.Spec.ClusterNetwork.Pods != nil {
+		podCIDRBlocks = cluster.Spec.ClusterNetwork.Pods.CIDRBlocks
+	}
+	if cluster.Spec.ClusterNetwork.Services != nil {
+		serviceCIDRBlocks = cluster.Spec.ClusterNetwork.Services.CIDRBlocks
+	}
+	return azureCluster.ValidateClusterNetworkCIDRs(podCIDRBlocks, serviceCIDRBlocks)
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2/klogr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+)
+
+func TestAzureClusterLookupValidatorGetCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := newScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-azure-cluster",
+			Namespace: "default",
+		},
+	}
+	newCluster := func(name, namespace, infraName string) client.Object {
+		return &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{
+					Kind: "AzureCluster",
+					Name: infraName,
+				},
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		objects         []client.Object
+		expectedCluster string
+	}{
+		"cluster references the AzureCluster": {
+			objects: []client.Object{
+				newCluster("other-cluster", "default", "other-azure-cluster"),
+				newCluster("my-cluster", "default", "my-azure-cluster"),
+			},
+			expectedCluster: "my-cluster",
+		},
+		"cluster in another namespace": {
+			objects: []client.Object{
+				newCluster("my-cluster", "other", "my-azure-cluster"),
+			},
+		},
+		"no cluster": {},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			v := &AzureClusterLookupValidator{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.objects...).Build(),
+				Log:    klogr.New(),
+			}
+			cluster, err := v.getCluster(context.TODO(), azureCluster)
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expectedCluster != "" {
+				g.Expect(cluster).NotTo(BeNil())
+				g.Expect(cluster.Name).To(Equal(tc.expectedCluster))
+			} else {
+				g.Expect(cluster).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestAzureClusterLookupValidatorHandle(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := newScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+	decoder, err := admission.NewDecoder(scheme)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	newAzureCluster := func(identityName string) *infrav1.AzureCluster {
+		azureCluster := &infrav1.AzureCluster{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: infrav1.GroupVersion.String(),
+				Kind:       "AzureCluster",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-azure-cluster",
+				Namespace: "default",
+			},
+		}
+		if identityName != "" {
+			azureCluster.Spec.IdentityRef = &corev1.ObjectReference{Name: identityName, Namespace: "identities"}
+		}
+		return azureCluster
+	}
+	newRequest := func(operation admissionv1.Operation, azureCluster, old *infrav1.AzureCluster) admission.Request {
+		raw, err := json.Marshal(azureCluster)
+		g.Expect(err).NotTo(HaveOccurred())
+		req := admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: operation,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		}
+		if old != nil {
+			oldRaw, err := json.Marshal(old)
+			g.Expect(err).NotTo(HaveOccurred())
+			req.OldObject = runtime.RawExtension{Raw: oldRaw}
+		}
+		return req
+	}
+	withNodeSubnet := func(azureCluster *infrav1.AzureCluster) *infrav1.AzureCluster {
+		azureCluster.Spec.NetworkSpec.Subnets = infrav1.Subnets{
+			{
+				Name:       "node-subnet",
+				Role:       infrav1.SubnetNode,
+				CIDRBlocks: []string{infrav1.DefaultNodeSubnetCIDR},
+			},
+		}
+		return azureCluster
+	}
+	objects := []client.Object{
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-cluster",
+				Namespace: "default",
+			},
+			Spec: clusterv1.ClusterSpec{
+				ClusterNetwork: &clusterv1.ClusterNetwork{
+					Pods: &clusterv1.NetworkRanges{CIDRBlocks: []string{"10.1.0.0/16"}},
+				},
+				InfrastructureRef: &corev1.ObjectReference{
+					Kind: "AzureCluster",
+					Name: "my-azure-cluster",
+				},
+			},
+		},
+		newIdentity(infrav1.ServicePrincipal, &infrav1.AllowedNamespaces{NamespaceList: []string{"other"}}),
+		newIdentitySecret(map[string][]byte{"clientSecret": []byte("secret")}),
+	}
+
+	cases := map[string]struct {
+		request         admission.Request
+		expectedAllowed bool
+	}{
+		"no identity reference": {
+			request:         newRequest(admissionv1.Create, newAzureCluster(""), nil),
+			expectedAllowed: true,
+		},
+		"identity not created yet": {
+			request:         newRequest(admissionv1.Create, newAzureCluster("other-identity"), nil),
+			expectedAllowed: true,
+		},
+		"identity doesn't allow the namespace": {
+			request: newRequest(admissionv1.Create, newAzureCluster("my-identity"), nil),
+		},
+		"update changing the identity reference": {
+			request: newRequest(admissionv1.Update, newAzureCluster("my-identity"), newAzureCluster("other-identity")),
+		},
+		"update keeping the identity reference": {
+			request:         newRequest(admissionv1.Update, newAzureCluster("my-identity"), newAzureCluster("my-identity")),
+			expectedAllowed: true,
+		},
+		"subnet overlapping the pod cidr": {
+			request: newRequest(admissionv1.Create, withNodeSubnet(newAzureCluster("")), nil),
+		},
+		"update keeping the identity reference with a subnet overlapping the pod cidr": {
+			request: newRequest(admissionv1.Update, withNodeSubnet(newAzureCluster("my-identity")), newAzureCluster("my-identity")),
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			v := &AzureClusterLookupValidator{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+				Log:    klogr.New(),
+			}
+			g.Expect(v.InjectDecoder(decoder)).To(Succeed())
+
+			response := v.Handle(context.TODO(), tc.request)
+			g.Expect(response.Allowed).To(Equal(tc.expectedAllowed))
+		})
+	}
+}
+
+func TestValidateAzureClusterNetwork(t *testing.T) {
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-azure-cluster",
+		},
+		Spec: infrav1.AzureClusterSpec{
+			NetworkSpec: infrav1.NetworkSpec{
+				Vnet: infrav1.VnetSpec{
+					CIDRBlocks: []string{infrav1.DefaultVnetCIDR},
+				},
+				Subnets: infrav1.Subnets{
+					{
+						Name:       "control-plane-subnet",
+						Role:       infrav1.SubnetControlPlane,
+						CIDRBlocks: []string{infrav1.DefaultControlPlaneSubnetCIDR},
+					},
+					{
+						Name:       "node-subnet",
+						Role:       infrav1.SubnetNode,
+						CIDRBlocks: []string{infrav1.DefaultNodeSubnetCIDR},
+					},
+				},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		clusterNetwork *clusterv1.ClusterNetwork
+		expectedError  string
+	}{
+		"no pod or service cidrs": {
+			clusterNetwork: &clusterv1.ClusterNetwork{},
+		},
+		"pod and service cidrs outside of the subnets": {
+			clusterNetwork: &clusterv1.ClusterNetwork{
+				Pods:     &clusterv1.NetworkRanges{CIDRBlocks: []string{"192.168.0.0/16"}},
+				Services: &clusterv1.NetworkRanges{CIDRBlocks: []string{"10.96.0.0/12"}},
+			},
+		},
+		"pod cidr overlaps the node subnet": {
+			clusterNetwork: &clusterv1.ClusterNetwork{
+				Pods: &clusterv1.NetworkRanges{CIDRBlocks: []string{"10.1.0.0/16"}},
+			},
+			expectedError: "spec.networkSpec.subnets[1].cidrBlocks[0]: Invalid value: \"10.1.0.0/16\": subnet CIDR overlaps with the pod CIDR 10.1.0.0/16 of the Cluster",
+		},
+		"service cidr contains the control plane subnet": {
+			clusterNetwork: &clusterv1.ClusterNetwork{
+				Services: &clusterv1.NetworkRanges{CIDRBlocks: []string{"10.0.0.0/12"}},
+			},
+			expectedError: "spec.networkSpec.subnets[0].cidrBlocks[0]: Invalid value: \"10.0.0.0/16\": subnet CIDR overlaps with the service CIDR 10.0.0.0/12 of the Cluster",
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			cluster := &clusterv1.Cluster{
+				Spec: clusterv1.ClusterSpec{
+					ClusterNetwork: tc.clusterNetwork,
+				},
+			}
+			err := validateAzureClusterNetwork(azureCluster, cluster)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -22,36 +22,50 @@ import (
 	"github.com/pkg/errors"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/adoption"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bastionhosts"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/costs"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsforwardingrulesets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/natgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/orphans"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privatedns"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/regions"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/routetables"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/securitygroups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 // azureClusterService is the reconciler called by the AzureCluster controller.
 type azureClusterService struct {
-	scope            *scope.ClusterScope
-	groupsSvc        azure.Reconciler
-	vnetSvc          azure.Reconciler
-	securityGroupSvc azure.Reconciler
-	routeTableSvc    azure.Reconciler
-	subnetsSvc       azure.Reconciler
-	publicIPSvc      azure.Reconciler
-	loadBalancerSvc  azure.Reconciler
-	privateDNSSvc    azure.Reconciler
-	bastionSvc       azure.Reconciler
-	skuCache         *resourceskus.Cache
-	natGatewaySvc    azure.Reconciler
+	scope                   *scope.ClusterScope
+	adoptionSvc             azure.Reconciler
+	groupsSvc               azure.Reconciler
+	vnetSvc                 azure.Reconciler
+	securityGroupSvc        azure.Reconciler
+	routeTableSvc           azure.Reconciler
+	subnetsSvc              azure.Reconciler
+	publicIPSvc             azure.Reconciler
+	loadBalancerSvc         azure.Reconciler
+	privateDNSSvc           azure.Reconciler
+	dnsForwardingRulesetSvc azure.Reconciler
+	bastionSvc              azure.Reconciler
+	regionsSvc              azure.Reconciler
+	tagsSvc                 azure.Reconciler
+	orphansSvc              azure.Reconciler
+	costsSvc                azure.Reconciler
+	skuCache                *resourceskus.Cache
+	natGatewaySvc           azure.Reconciler
 }
 
 // newAzureClusterService populates all the services based on input scope.
@@ -61,19 +75,30 @@ func newAzureClusterService(scope *scope.ClusterScope) (*azureClusterService, er
 		return nil, errors.Wrap(err, "failed creating a NewCache")
 	}
 
+	costsSvc, err := costs.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating the costs service")
+	}
+
 	return &azureClusterService{
-		scope:            scope,
-		groupsSvc:        groups.New(scope),
-		vnetSvc:          virtualnetworks.New(scope),
-		securityGroupSvc: securitygroups.New(scope),
-		routeTableSvc:    routetables.New(scope),
-		natGatewaySvc:    natgateways.New(scope),
-		subnetsSvc:       subnets.New(scope),
-		publicIPSvc:      publicips.New(scope),
-		loadBalancerSvc:  loadbalancers.New(scope),
-		privateDNSSvc:    privatedns.New(scope),
-		bastionSvc:       bastionhosts.New(scope),
-		skuCache:         skuCache,
+		scope:                   scope,
+		adoptionSvc:             adoption.New(scope),
+		groupsSvc:               groups.New(scope),
+		vnetSvc:                 virtualnetworks.New(scope),
+		securityGroupSvc:        securitygroups.New(scope),
+		routeTableSvc:           routetables.New(scope),
+		natGatewaySvc:           natgateways.New(scope),
+		subnetsSvc:              subnets.New(scope),
+		publicIPSvc:             publicips.New(scope),
+		loadBalancerSvc:         loadbalancers.New(scope),
+		privateDNSSvc:           privatedns.New(scope),
+		dnsForwardingRulesetSvc: dnsforwardingrulesets.New(scope),
+		bastionSvc:              bastionhosts.New(scope),
+		regionsSvc:              regions.New(scope),
+		tagsSvc:                 tags.New(scope),
+		orphansSvc:              orphans.New(scope),
+		costsSvc:                costsSvc,
+		skuCache:                skuCache,
 	}, nil
 }
 
@@ -88,103 +113,170 @@ func (s *azureClusterService) Reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "failed to get availability zones")
 	}
 
+	// The region metadata is only informational, so failing to look it up doesn't fail the reconciliation.
+	if err := s.reconcileService(ctx, s.regionsSvc, infrav1.RegionMetadataAvailableCondition, "region metadata"); err != nil {
+		s.scope.Error(err, "failed to reconcile region metadata")
+	}
+
 	s.scope.SetDNSName()
 	s.scope.SetControlPlaneSecurityRules()
 
-	if err := s.groupsSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, s.adoptionSvc, "", ""); err != nil {
+		return errors.Wrap(err, "failed to adopt pre-existing resources")
+	}
+
+	if err := s.reconcileService(ctx, s.groupsSvc, infrav1.ResourceGroupReadyCondition, "resource group"); err != nil {
 		return errors.Wrap(err, "failed to reconcile resource group")
 	}
 
-	if err := s.vnetSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, s.vnetSvc, infrav1.VNetReadyCondition, "virtual network"); err != nil {
 		return errors.Wrap(err, "failed to reconcile virtual network")
 	}
 
-	if err := s.securityGroupSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, s.securityGroupSvc, infrav1.SecurityGroupsReadyCondition, "network security group"); err != nil {
 		return errors.Wrap(err, "failed to reconcile network security group")
 	}
 
-	if err := s.routeTableSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, s.routeTableSvc, infrav1.RouteTablesReadyCondition, "route table"); err != nil {
 		return errors.Wrap(err, "failed to reconcile route table")
 	}
 
-	if err := s.publicIPSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, s.publicIPSvc, infrav1.PublicIPsReadyCondition, "public IP"); err != nil {
 		return errors.Wrap(err, "failed to reconcile public IP")
 	}
 
-	if err := s.natGatewaySvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, s.natGatewaySvc, infrav1.NATGatewaysReadyCondition, "nat gateway"); err != nil {
 		return errors.Wrapf(err, "failed to reconcile nat gateway")
 	}
 
-	if err := s.subnetsSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, s.subnetsSvc, infrav1.SubnetsReadyCondition, "subnet"); err != nil {
 		return errors.Wrapf(err, "failed to reconcile subnet")
 	}
 
-	if err := s.loadBalancerSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, s.loadBalancerSvc, infrav1.LoadBalancersReadyCondition, "load balancer"); err != nil {
 		return errors.Wrap(err, "failed to reconcile load balancer")
 	}
 
-	if err := s.privateDNSSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, s.privateDNSSvc, infrav1.PrivateDNSReadyCondition, "private dns"); err != nil {
 		return errors.Wrap(err, "failed to reconcile private dns")
 	}
 
-	if err := s.bastionSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, s.dnsForwardingRulesetSvc, "", ""); err != nil {
+		return errors.Wrap(err, "failed to reconcile dns forwarding ruleset link")
+	}
+
+	if err := s.reconcileService(ctx, s.bastionSvc, infrav1.BastionHostReadyCondition, "bastion"); err != nil {
 		return errors.Wrap(err, "failed to reconcile bastion")
 	}
 
+	if err := s.reconcileService(ctx, s.tagsSvc, "", ""); err != nil {
+		return errors.Wrap(err, "unable to update tags")
+	}
+
+	if err := s.reconcileService(ctx, s.orphansSvc, "", ""); err != nil {
+		return errors.Wrap(err, "failed to garbage collect orphaned resources")
+	}
+
+	// The cost estimate is only informational, so failing to compute it doesn't fail the reconciliation.
+	if feature.Gates.Enabled(feature.CostEstimation) {
+		if err := s.costsSvc.Reconcile(ctx); err != nil {
+			s.scope.Error(err, "failed to estimate the monthly cost of the cluster")
+		}
+	}
+
 	return nil
 }
 
-// Delete reconciles all the services in a predetermined order.
+// reconcileService reconciles the resources of svc and reports on them in the given condition of the AzureCluster, if
+// any. The requests skipped in dry-run mode are not errors, so that the next services report theirs too.
+func (s *azureClusterService) reconcileService(ctx context.Context, svc azure.Reconciler, condition clusterv1.ConditionType, name string) error {
+	err := svc.Reconcile(ctx)
+	if condition != "" {
+		s.scope.UpdatePutStatus(condition, name, err)
+	}
+	if azure.IsDryRunError(err) {
+		return nil
+	}
+	return err
+}
+
+// Delete deletes the resources of all the services, concurrently where they don't depend on each other.
 func (s *azureClusterService) Delete(ctx context.Context) error {
 	ctx, span := tele.Tracer().Start(ctx, "controllers.azureClusterService.Delete")
 	defer span.End()
 
-	if err := s.groupsSvc.Delete(ctx); err != nil {
-		if errors.Is(err, azure.ErrNotOwned) {
-			if err := s.bastionSvc.Delete(ctx); err != nil {
-				return errors.Wrap(err, "failed to delete bastion")
-			}
-
-			if err := s.privateDNSSvc.Delete(ctx); err != nil {
-				return errors.Wrap(err, "failed to delete private dns")
-			}
-
-			if err := s.loadBalancerSvc.Delete(ctx); err != nil {
-				return errors.Wrap(err, "failed to delete load balancer")
-			}
+	// Network resources placed in a separate resource group aren't removed along with the cluster resource group.
+	// They don't depend on the cluster resource group, so it is deleted even if some of them couldn't be.
+	if s.scope.NetworkResourceGroup() != s.scope.ResourceGroup() {
+		networkErr := s.deleteNetworkResources(ctx, true)
+		if err := s.deleteGroup(ctx); err != nil && !errors.Is(err, azure.ErrNotOwned) {
+			return flatten([]error{networkErr, errors.Wrap(err, "failed to delete resource group")})
+		}
+		return networkErr
+	}
 
-			if err := s.subnetsSvc.Delete(ctx); err != nil {
-				return errors.Wrap(err, "failed to delete subnet")
-			}
+	// The DNS forwarding ruleset lives outside of the cluster resource group, so its link has to be removed explicitly.
+	if err := s.dnsForwardingRulesetSvc.Delete(ctx); err != nil {
+		return errors.Wrap(err, "failed to delete dns forwarding ruleset link")
+	}
 
-			if err := s.natGatewaySvc.Delete(ctx); err != nil {
-				return errors.Wrapf(err, "failed to delete nat gateway")
-			}
+	if err := s.deleteGroup(ctx); err != nil {
+		if !errors.Is(err, azure.ErrNotOwned) {
+			return errors.Wrap(err, "failed to delete resource group")
+		}
+		return s.deleteNetworkResources(ctx, false)
+	}
 
-			if err := s.publicIPSvc.Delete(ctx); err != nil {
-				return errors.Wrapf(err, "failed to delete public IP")
-			}
+	return nil
+}
 
-			if err := s.routeTableSvc.Delete(ctx); err != nil {
-				return errors.Wrap(err, "failed to delete route table")
-			}
+// deleteGroup deletes the cluster resource group, reporting on its deletion unless it isn't owned by the cluster.
+func (s *azureClusterService) deleteGroup(ctx context.Context) error {
+	err := s.groupsSvc.Delete(ctx)
+	if !errors.Is(err, azure.ErrNotOwned) {
+		s.scope.UpdateDeleteStatus(infrav1.ResourceGroupReadyCondition, "resource group", err)
+	}
+	return err
+}
 
-			if err := s.securityGroupSvc.Delete(ctx); err != nil {
-				return errors.Wrap(err, "failed to delete network security group")
-			}
+// networkResourceConditions are the conditions of the AzureCluster reporting on its network resources, by deletion step.
+var networkResourceConditions = map[string]clusterv1.ConditionType{
+	"bastion":                infrav1.BastionHostReadyCondition,
+	"private dns":            infrav1.PrivateDNSReadyCondition,
+	"load balancer":          infrav1.LoadBalancersReadyCondition,
+	"subnet":                 infrav1.SubnetsReadyCondition,
+	"nat gateway":            infrav1.NATGatewaysReadyCondition,
+	"public IP":              infrav1.PublicIPsReadyCondition,
+	"route table":            infrav1.RouteTablesReadyCondition,
+	"network security group": infrav1.SecurityGroupsReadyCondition,
+	"virtual network":        infrav1.VNetReadyCondition,
+}
 
-			if err := s.vnetSvc.Delete(ctx); err != nil {
-				return errors.Wrap(err, "failed to delete virtual network")
-			}
-		} else {
-			return errors.Wrap(err, "failed to delete resource group")
-		}
+// deleteNetworkResources deletes the network resources of the cluster, each one as soon as the resources referencing it are gone.
+// The link of the DNS forwarding ruleset to the virtual network is deleted too if withRulesetLink is set.
+func (s *azureClusterService) deleteNetworkResources(ctx context.Context, withRulesetLink bool) error {
+	vnetDependencies := []string{"private dns", "subnet"}
+	steps := []deletionStep{
+		{name: "bastion", svc: s.bastionSvc},
+		{name: "private dns", svc: s.privateDNSSvc},
+		{name: "load balancer", svc: s.loadBalancerSvc},
+		{name: "subnet", svc: s.subnetsSvc, dependsOn: []string{"bastion", "load balancer"}},
+		{name: "nat gateway", svc: s.natGatewaySvc, dependsOn: []string{"subnet"}},
+		{name: "public IP", svc: s.publicIPSvc, dependsOn: []string{"bastion", "load balancer", "nat gateway"}},
+		{name: "route table", svc: s.routeTableSvc, dependsOn: []string{"subnet"}},
+		{name: "network security group", svc: s.securityGroupSvc, dependsOn: []string{"subnet"}},
+	}
+	if withRulesetLink {
+		steps = append(steps, deletionStep{name: "dns forwarding ruleset link", svc: s.dnsForwardingRulesetSvc})
+		vnetDependencies = append(vnetDependencies, "dns forwarding ruleset link")
 	}
+	steps = append(steps, deletionStep{name: "virtual network", svc: s.vnetSvc, dependsOn: vnetDependencies})
 
-	return nil
+	return deleteConcurrently(ctx, trackWithConditions(steps, networkResourceConditions, s.scope.UpdateDeleteStatus))
 }
 
 // setFailureDomainsForLocation sets the AzureCluster Status failure domains based on which Azure Availability Zones are available in the cluster location.
+// Zones that don't offer the control plane VM size are still published, but are not marked as suitable for the control plane.
 // Note that this is not done in a webhook as it requires API calls to fetch the availability zones.
 func (s *azureClusterService) setFailureDomainsForLocation(ctx context.Context) error {
 	zones, err := s.skuCache.GetZones(ctx, s.scope.Location())
@@ -192,9 +284,26 @@ func (s *azureClusterService) setFailureDomainsForLocation(ctx context.Context)
 		return errors.Wrapf(err, "failed to get zones for location %s", s.scope.Location())
 	}
 
+	controlPlaneZones := zones
+	vmSize, err := s.scope.ControlPlaneVMSize(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get control plane VM size")
+	}
+	if vmSize != "" {
+		controlPlaneZones, err = s.skuCache.GetZonesWithVMSize(ctx, vmSize, s.scope.Location())
+		if err != nil {
+			return errors.Wrapf(err, "failed to get zones for VM size %s in location %s", vmSize, s.scope.Location())
+		}
+	}
+
+	isControlPlaneZone := make(map[string]bool, len(controlPlaneZones))
+	for _, zone := range controlPlaneZones {
+		isControlPlaneZone[zone] = true
+	}
+
 	for _, zone := range zones {
 		s.scope.SetFailureDomain(zone, clusterv1.FailureDomainSpec{
-			ControlPlane: true,
+			ControlPlane: isControlPlaneZone[zone],
 		})
 	}
 
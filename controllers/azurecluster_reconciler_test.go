@@ -19,9 +19,11 @@ package controllers
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-30/compute"
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 
@@ -33,68 +35,121 @@ import (
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 )
 
-type expect func(grp *mocks.MockReconcilerMockRecorder, vnet *mocks.MockReconcilerMockRecorder, sg *mocks.MockReconcilerMockRecorder, rt *mocks.MockReconcilerMockRecorder, sn *mocks.MockReconcilerMockRecorder, natg *mocks.MockReconcilerMockRecorder, pip *mocks.MockReconcilerMockRecorder, lb *mocks.MockReconcilerMockRecorder, dns *mocks.MockReconcilerMockRecorder, bastion *mocks.MockReconcilerMockRecorder)
+type expect func(grp *mocks.MockReconcilerMockRecorder, vnet *mocks.MockReconcilerMockRecorder, sg *mocks.MockReconcilerMockRecorder, rt *mocks.MockReconcilerMockRecorder, sn *mocks.MockReconcilerMockRecorder, natg *mocks.MockReconcilerMockRecorder, pip *mocks.MockReconcilerMockRecorder, lb *mocks.MockReconcilerMockRecorder, dns *mocks.MockReconcilerMockRecorder, dnsRuleset *mocks.MockReconcilerMockRecorder, bastion *mocks.MockReconcilerMockRecorder)
 
 func TestAzureClusterReconcilerDelete(t *testing.T) {
 	cases := map[string]struct {
-		expectedError string
-		expect        expect
+		expectedError        string
+		networkResourceGroup string
+		expect               expect
 	}{
 		"Resource Group is deleted successfully": {
 			expectedError: "",
-			expect: func(grp *mocks.MockReconcilerMockRecorder, vnet *mocks.MockReconcilerMockRecorder, sg *mocks.MockReconcilerMockRecorder, rt *mocks.MockReconcilerMockRecorder, sn *mocks.MockReconcilerMockRecorder, natg *mocks.MockReconcilerMockRecorder, pip *mocks.MockReconcilerMockRecorder, lb *mocks.MockReconcilerMockRecorder, dns *mocks.MockReconcilerMockRecorder, bastion *mocks.MockReconcilerMockRecorder) {
+			expect: func(grp *mocks.MockReconcilerMockRecorder, vnet *mocks.MockReconcilerMockRecorder, sg *mocks.MockReconcilerMockRecorder, rt *mocks.MockReconcilerMockRecorder, sn *mocks.MockReconcilerMockRecorder, natg *mocks.MockReconcilerMockRecorder, pip *mocks.MockReconcilerMockRecorder, lb *mocks.MockReconcilerMockRecorder, dns *mocks.MockReconcilerMockRecorder, dnsRuleset *mocks.MockReconcilerMockRecorder, bastion *mocks.MockReconcilerMockRecorder) {
 				gomock.InOrder(
+					dnsRuleset.Delete(gomockinternal.AContext()),
 					grp.Delete(gomockinternal.AContext()).Return(nil))
 			},
 		},
+		"DNS forwarding ruleset link delete fails": {
+			expectedError: "failed to delete dns forwarding ruleset link: internal error",
+			expect: func(grp *mocks.MockReconcilerMockRecorder, vnet *mocks.MockReconcilerMockRecorder, sg *mocks.MockReconcilerMockRecorder, rt *mocks.MockReconcilerMockRecorder, sn *mocks.MockReconcilerMockRecorder, natg *mocks.MockReconcilerMockRecorder, pip *mocks.MockReconcilerMockRecorder, lb *mocks.MockReconcilerMockRecorder, dns *mocks.MockReconcilerMockRecorder, dnsRuleset *mocks.MockReconcilerMockRecorder, bastion *mocks.MockReconcilerMockRecorder) {
+				gomock.InOrder(
+					dnsRuleset.Delete(gomockinternal.AContext()).Return(errors.New("internal error")))
+			},
+		},
 		"Resource Group delete fails": {
 			expectedError: "failed to delete resource group: internal error",
-			expect: func(grp *mocks.MockReconcilerMockRecorder, vnet *mocks.MockReconcilerMockRecorder, sg *mocks.MockReconcilerMockRecorder, rt *mocks.MockReconcilerMockRecorder, sn *mocks.MockReconcilerMockRecorder, natg *mocks.MockReconcilerMockRecorder, pip *mocks.MockReconcilerMockRecorder, lb *mocks.MockReconcilerMockRecorder, dns *mocks.MockReconcilerMockRecorder, bastion *mocks.MockReconcilerMockRecorder) {
+			expect: func(grp *mocks.MockReconcilerMockRecorder, vnet *mocks.MockReconcilerMockRecorder, sg *mocks.MockReconcilerMockRecorder, rt *mocks.MockReconcilerMockRecorder, sn *mocks.MockReconcilerMockRecorder, natg *mocks.MockReconcilerMockRecorder, pip *mocks.MockReconcilerMockRecorder, lb *mocks.MockReconcilerMockRecorder, dns *mocks.MockReconcilerMockRecorder, dnsRuleset *mocks.MockReconcilerMockRecorder, bastion *mocks.MockReconcilerMockRecorder) {
 				gomock.InOrder(
+					dnsRuleset.Delete(gomockinternal.AContext()),
 					grp.Delete(gomockinternal.AContext()).Return(errors.New("internal error")))
 			},
 		},
 		"Resource Group not owned by cluster": {
 			expectedError: "",
-			expect: func(grp *mocks.MockReconcilerMockRecorder, vnet *mocks.MockReconcilerMockRecorder, sg *mocks.MockReconcilerMockRecorder, rt *mocks.MockReconcilerMockRecorder, sn *mocks.MockReconcilerMockRecorder, natg *mocks.MockReconcilerMockRecorder, pip *mocks.MockReconcilerMockRecorder, lb *mocks.MockReconcilerMockRecorder, dns *mocks.MockReconcilerMockRecorder, bastion *mocks.MockReconcilerMockRecorder) {
-				gomock.InOrder(
-					grp.Delete(gomockinternal.AContext()).Return(azure.ErrNotOwned),
-					bastion.Delete(gomockinternal.AContext()),
-					dns.Delete(gomockinternal.AContext()),
-					lb.Delete(gomockinternal.AContext()),
-					sn.Delete(gomockinternal.AContext()),
-					natg.Delete(gomockinternal.AContext()),
-					pip.Delete(gomockinternal.AContext()),
-					rt.Delete(gomockinternal.AContext()),
-					sg.Delete(gomockinternal.AContext()),
-					vnet.Delete(gomockinternal.AContext()),
-				)
+			expect: func(grp *mocks.MockReconcilerMockRecorder, vnet *mocks.MockReconcilerMockRecorder, sg *mocks.MockReconcilerMockRecorder, rt *mocks.MockReconcilerMockRecorder, sn *mocks.MockReconcilerMockRecorder, natg *mocks.MockReconcilerMockRecorder, pip *mocks.MockReconcilerMockRecorder, lb *mocks.MockReconcilerMockRecorder, dns *mocks.MockReconcilerMockRecorder, dnsRuleset *mocks.MockReconcilerMockRecorder, bastion *mocks.MockReconcilerMockRecorder) {
+				grpDelete := grp.Delete(gomockinternal.AContext()).Return(azure.ErrNotOwned).After(
+					dnsRuleset.Delete(gomockinternal.AContext()))
+				expectNetworkResourcesDeleted(vnet, sg, rt, sn, natg, pip, lb, dns, bastion, grpDelete)
+			},
+		},
+		"Network resources in a separate resource group are deleted before the resource group": {
+			expectedError:        "",
+			networkResourceGroup: "my-network-rg",
+			expect: func(grp *mocks.MockReconcilerMockRecorder, vnet *mocks.MockReconcilerMockRecorder, sg *mocks.MockReconcilerMockRecorder, rt *mocks.MockReconcilerMockRecorder, sn *mocks.MockReconcilerMockRecorder, natg *mocks.MockReconcilerMockRecorder, pip *mocks.MockReconcilerMockRecorder, lb *mocks.MockReconcilerMockRecorder, dns *mocks.MockReconcilerMockRecorder, dnsRuleset *mocks.MockReconcilerMockRecorder, bastion *mocks.MockReconcilerMockRecorder) {
+				vnetDelete := expectNetworkResourcesDeleted(vnet, sg, rt, sn, natg, pip, lb, dns, bastion, nil,
+					dnsRuleset.Delete(gomockinternal.AContext()))
+				grp.Delete(gomockinternal.AContext()).Return(nil).After(vnetDelete)
+			},
+		},
+		"Network resources in a separate resource group are not deleted twice when the resource group is not owned": {
+			expectedError:        "",
+			networkResourceGroup: "my-network-rg",
+			expect: func(grp *mocks.MockReconcilerMockRecorder, vnet *mocks.MockReconcilerMockRecorder, sg *mocks.MockReconcilerMockRecorder, rt *mocks.MockReconcilerMockRecorder, sn *mocks.MockReconcilerMockRecorder, natg *mocks.MockReconcilerMockRecorder, pip *mocks.MockReconcilerMockRecorder, lb *mocks.MockReconcilerMockRecorder, dns *mocks.MockReconcilerMockRecorder, dnsRuleset *mocks.MockReconcilerMockRecorder, bastion *mocks.MockReconcilerMockRecorder) {
+				vnetDelete := expectNetworkResourcesDeleted(vnet, sg, rt, sn, natg, pip, lb, dns, bastion, nil,
+					dnsRuleset.Delete(gomockinternal.AContext()))
+				grp.Delete(gomockinternal.AContext()).Return(azure.ErrNotOwned).After(vnetDelete)
+			},
+		},
+		"Resource group in a separate resource group is deleted even if network resources fail": {
+			expectedError:        "[failed to delete private dns: some error happened, failed to delete resource group: internal error]",
+			networkResourceGroup: "my-network-rg",
+			expect: func(grp *mocks.MockReconcilerMockRecorder, vnet *mocks.MockReconcilerMockRecorder, sg *mocks.MockReconcilerMockRecorder, rt *mocks.MockReconcilerMockRecorder, sn *mocks.MockReconcilerMockRecorder, natg *mocks.MockReconcilerMockRecorder, pip *mocks.MockReconcilerMockRecorder, lb *mocks.MockReconcilerMockRecorder, dns *mocks.MockReconcilerMockRecorder, dnsRuleset *mocks.MockReconcilerMockRecorder, bastion *mocks.MockReconcilerMockRecorder) {
+				dnsRuleset.Delete(gomockinternal.AContext())
+				bastionDelete := bastion.Delete(gomockinternal.AContext())
+				lbDelete := lb.Delete(gomockinternal.AContext())
+				dns.Delete(gomockinternal.AContext()).Return(errors.New("some error happened"))
+				snDelete := sn.Delete(gomockinternal.AContext()).After(bastionDelete).After(lbDelete)
+				natgDelete := natg.Delete(gomockinternal.AContext()).After(snDelete)
+				pip.Delete(gomockinternal.AContext()).After(natgDelete)
+				rt.Delete(gomockinternal.AContext()).After(snDelete)
+				sg.Delete(gomockinternal.AContext()).After(snDelete)
+				grp.Delete(gomockinternal.AContext()).Return(errors.New("internal error"))
 			},
 		},
 		"Load Balancer delete fails": {
 			expectedError: "failed to delete load balancer: some error happened",
-			expect: func(grp *mocks.MockReconcilerMockRecorder, vnet *mocks.MockReconcilerMockRecorder, sg *mocks.MockReconcilerMockRecorder, rt *mocks.MockReconcilerMockRecorder, sn *mocks.MockReconcilerMockRecorder, pip *mocks.MockReconcilerMockRecorder, natg *mocks.MockReconcilerMockRecorder, lb *mocks.MockReconcilerMockRecorder, dns *mocks.MockReconcilerMockRecorder, bastion *mocks.MockReconcilerMockRecorder) {
+			expect: func(grp *mocks.MockReconcilerMockRecorder, vnet *mocks.MockReconcilerMockRecorder, sg *mocks.MockReconcilerMockRecorder, rt *mocks.MockReconcilerMockRecorder, sn *mocks.MockReconcilerMockRecorder, natg *mocks.MockReconcilerMockRecorder, pip *mocks.MockReconcilerMockRecorder, lb *mocks.MockReconcilerMockRecorder, dns *mocks.MockReconcilerMockRecorder, dnsRuleset *mocks.MockReconcilerMockRecorder, bastion *mocks.MockReconcilerMockRecorder) {
 				gomock.InOrder(
+					dnsRuleset.Delete(gomockinternal.AContext()),
 					grp.Delete(gomockinternal.AContext()).Return(azure.ErrNotOwned),
-					bastion.Delete(gomockinternal.AContext()),
-					dns.Delete(gomockinternal.AContext()),
-					lb.Delete(gomockinternal.AContext()).Return(errors.New("some error happened")),
 				)
+				bastion.Delete(gomockinternal.AContext())
+				dns.Delete(gomockinternal.AContext())
+				lb.Delete(gomockinternal.AContext()).Return(errors.New("some error happened"))
 			},
 		},
 		"Route table delete fails": {
 			expectedError: "failed to delete route table: some error happened",
-			expect: func(grp *mocks.MockReconcilerMockRecorder, vnet *mocks.MockReconcilerMockRecorder, sg *mocks.MockReconcilerMockRecorder, rt *mocks.MockReconcilerMockRecorder, sn *mocks.MockReconcilerMockRecorder, pip *mocks.MockReconcilerMockRecorder, natg *mocks.MockReconcilerMockRecorder, lb *mocks.MockReconcilerMockRecorder, dns *mocks.MockReconcilerMockRecorder, bastion *mocks.MockReconcilerMockRecorder) {
-				gomock.InOrder(
-					grp.Delete(gomockinternal.AContext()).Return(azure.ErrNotOwned),
-					bastion.Delete(gomockinternal.AContext()),
-					dns.Delete(gomockinternal.AContext()),
-					lb.Delete(gomockinternal.AContext()),
-					sn.Delete(gomockinternal.AContext()),
-					pip.Delete(gomockinternal.AContext()),
-					natg.Delete(gomockinternal.AContext()),
-					rt.Delete(gomockinternal.AContext()).Return(errors.New("some error happened")),
-				)
+			expect: func(grp *mocks.MockReconcilerMockRecorder, vnet *mocks.MockReconcilerMockRecorder, sg *mocks.MockReconcilerMockRecorder, rt *mocks.MockReconcilerMockRecorder, sn *mocks.MockReconcilerMockRecorder, natg *mocks.MockReconcilerMockRecorder, pip *mocks.MockReconcilerMockRecorder, lb *mocks.MockReconcilerMockRecorder, dns *mocks.MockReconcilerMockRecorder, dnsRuleset *mocks.MockReconcilerMockRecorder, bastion *mocks.MockReconcilerMockRecorder) {
+				grpDelete := grp.Delete(gomockinternal.AContext()).Return(azure.ErrNotOwned).After(
+					dnsRuleset.Delete(gomockinternal.AContext()))
+				bastionDelete := bastion.Delete(gomockinternal.AContext()).After(grpDelete)
+				dnsDelete := dns.Delete(gomockinternal.AContext()).After(grpDelete)
+				lbDelete := lb.Delete(gomockinternal.AContext()).After(grpDelete)
+				snDelete := sn.Delete(gomockinternal.AContext()).After(bastionDelete).After(lbDelete)
+				natgDelete := natg.Delete(gomockinternal.AContext()).After(snDelete)
+				pip.Delete(gomockinternal.AContext()).After(natgDelete)
+				rt.Delete(gomockinternal.AContext()).Return(errors.New("some error happened")).After(snDelete)
+				sg.Delete(gomockinternal.AContext()).After(snDelete)
+				vnet.Delete(gomockinternal.AContext()).After(snDelete).After(dnsDelete)
+			},
+		},
+		"Resources which are not found are considered deleted": {
+			expectedError: "",
+			expect: func(grp *mocks.MockReconcilerMockRecorder, vnet *mocks.MockReconcilerMockRecorder, sg *mocks.MockReconcilerMockRecorder, rt *mocks.MockReconcilerMockRecorder, sn *mocks.MockReconcilerMockRecorder, natg *mocks.MockReconcilerMockRecorder, pip *mocks.MockReconcilerMockRecorder, lb *mocks.MockReconcilerMockRecorder, dns *mocks.MockReconcilerMockRecorder, dnsRuleset *mocks.MockReconcilerMockRecorder, bastion *mocks.MockReconcilerMockRecorder) {
+				grpDelete := grp.Delete(gomockinternal.AContext()).Return(azure.ErrNotOwned).After(
+					dnsRuleset.Delete(gomockinternal.AContext()))
+				bastionDelete := bastion.Delete(gomockinternal.AContext()).After(grpDelete)
+				dnsDelete := dns.Delete(gomockinternal.AContext()).After(grpDelete)
+				lbDelete := lb.Delete(gomockinternal.AContext()).Return(autorest.DetailedError{StatusCode: http.StatusNotFound}).After(grpDelete)
+				snDelete := sn.Delete(gomockinternal.AContext()).After(bastionDelete).After(lbDelete)
+				natgDelete := natg.Delete(gomockinternal.AContext()).After(snDelete)
+				pip.Delete(gomockinternal.AContext()).After(natgDelete)
+				rt.Delete(gomockinternal.AContext()).After(snDelete)
+				sg.Delete(gomockinternal.AContext()).After(snDelete)
+				vnet.Delete(gomockinternal.AContext()).After(snDelete).After(dnsDelete)
 			},
 		},
 	}
@@ -116,25 +171,34 @@ func TestAzureClusterReconcilerDelete(t *testing.T) {
 			publicIPMock := mocks.NewMockReconciler(mockCtrl)
 			lbMock := mocks.NewMockReconciler(mockCtrl)
 			dnsMock := mocks.NewMockReconciler(mockCtrl)
+			dnsRulesetMock := mocks.NewMockReconciler(mockCtrl)
 			bastionMock := mocks.NewMockReconciler(mockCtrl)
 
-			tc.expect(groupsMock.EXPECT(), vnetMock.EXPECT(), sgMock.EXPECT(), rtMock.EXPECT(), subnetsMock.EXPECT(), natGatewaysMock.EXPECT(), publicIPMock.EXPECT(), lbMock.EXPECT(), dnsMock.EXPECT(), bastionMock.EXPECT())
+			tc.expect(groupsMock.EXPECT(), vnetMock.EXPECT(), sgMock.EXPECT(), rtMock.EXPECT(), subnetsMock.EXPECT(), natGatewaysMock.EXPECT(), publicIPMock.EXPECT(), lbMock.EXPECT(), dnsMock.EXPECT(), dnsRulesetMock.EXPECT(), bastionMock.EXPECT())
 
 			s := &azureClusterService{
 				scope: &scope.ClusterScope{
-					AzureCluster: &infrav1.AzureCluster{},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							NetworkSpec: infrav1.NetworkSpec{
+								ResourceGroup: tc.networkResourceGroup,
+							},
+						},
+					},
 				},
-				groupsSvc:        groupsMock,
-				vnetSvc:          vnetMock,
-				securityGroupSvc: sgMock,
-				routeTableSvc:    rtMock,
-				natGatewaySvc:    natGatewaysMock,
-				subnetsSvc:       subnetsMock,
-				publicIPSvc:      publicIPMock,
-				loadBalancerSvc:  lbMock,
-				privateDNSSvc:    dnsMock,
-				bastionSvc:       bastionMock,
-				skuCache:         resourceskus.NewStaticCache([]compute.ResourceSku{}, ""),
+				groupsSvc:               groupsMock,
+				vnetSvc:                 vnetMock,
+				securityGroupSvc:        sgMock,
+				routeTableSvc:           rtMock,
+				natGatewaySvc:           natGatewaysMock,
+				subnetsSvc:              subnetsMock,
+				publicIPSvc:             publicIPMock,
+				loadBalancerSvc:         lbMock,
+				privateDNSSvc:           dnsMock,
+				dnsForwardingRulesetSvc: dnsRulesetMock,
+				bastionSvc:              bastionMock,
+				skuCache:                resourceskus.NewStaticCache([]compute.ResourceSku{}, ""),
 			}
 
 			err := s.Delete(context.TODO())
@@ -147,3 +211,29 @@ func TestAzureClusterReconcilerDelete(t *testing.T) {
 		})
 	}
 }
+
+// expectNetworkResourcesDeleted expects the network resources to be deleted after the given call, if any, each one after the
+// resources depending on it. The deletion of the virtual network also waits for the given extra dependencies. It returns the
+// deletion of the virtual network.
+func expectNetworkResourcesDeleted(vnet, sg, rt, sn, natg, pip, lb, dns, bastion *mocks.MockReconcilerMockRecorder, after *gomock.Call, vnetDependencies ...*gomock.Call) *gomock.Call {
+	calls := map[string]*gomock.Call{
+		"bastion": bastion.Delete(gomockinternal.AContext()),
+		"dns":     dns.Delete(gomockinternal.AContext()),
+		"lb":      lb.Delete(gomockinternal.AContext()),
+	}
+	if after != nil {
+		for _, call := range calls {
+			call.After(after)
+		}
+	}
+	snDelete := sn.Delete(gomockinternal.AContext()).After(calls["bastion"]).After(calls["lb"])
+	natgDelete := natg.Delete(gomockinternal.AContext()).After(snDelete)
+	pip.Delete(gomockinternal.AContext()).After(natgDelete).After(calls["bastion"]).After(calls["lb"])
+	rt.Delete(gomockinternal.AContext()).After(snDelete)
+	sg.Delete(gomockinternal.AContext()).After(snDelete)
+	vnetDelete := vnet.Delete(gomockinternal.AContext()).After(snDelete).After(calls["dns"])
+	for _, dep := range vnetDependencies {
+		vnetDelete.After(dep)
+	}
+	return vnetDelete
+}
@@ -142,6 +142,18 @@ func (r *AzureIdentityReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return reconcile.Result{}, err
 	}
 
+	// Return early if the identity owner or its Cluster is paused.
+	if owner, ok := identityOwner.(client.Object); ok {
+		paused, err := isPaused(ctx, r.Client, owner)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if paused {
+			log.Info("Identity owner or linked Cluster is marked as paused. Won't reconcile")
+			return reconcile.Result{}, nil
+		}
+	}
+
 	// get all the bindings
 	var bindings aadpodv1.AzureIdentityBindingList
 	if err := r.List(ctx, &bindings, client.InNamespace(system.GetManagerNamespace())); err != nil {
@@ -30,8 +30,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -43,12 +47,21 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+const (
+	azureJSONTemplateControllerName = "azurejson-template-controller"
+
+	// externalCloudProviderRequeueInterval is how often the control plane is checked for initialization before the
+	// external cloud provider config can be written to the workload cluster.
+	externalCloudProviderRequeueInterval = time.Minute
+)
+
 // AzureJSONTemplateReconciler reconciles Azure json secrets for AzureMachineTemplate objects.
 type AzureJSONTemplateReconciler struct {
 	client.Client
 	Log              logr.Logger
 	Recorder         record.EventRecorder
 	ReconcileTimeout time.Duration
+	WatchFilterValue string
 }
 
 // SetupWithManager initializes this controller with a manager.
@@ -56,6 +69,7 @@ func (r *AzureJSONTemplateReconciler) SetupWithManager(ctx context.Context, mgr
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(options).
 		For(&infrav1.AzureMachineTemplate{}).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(r.Log, r.WatchFilterValue)).
 		Owns(&corev1.Secret{}).
 		Complete(r)
 }
@@ -164,10 +178,62 @@ func (r *AzureJSONTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return ctrl.Result{}, errors.Wrap(err, "failed to create cloud provider config")
 	}
 
+	if err := overrideCloudProviderSecret(ctx, r.Client, clusterScope, azureMachineTemplate.Namespace, newSecret); err != nil {
+		r.Recorder.Eventf(azureMachineTemplate, corev1.EventTypeWarning, "CloudProviderConfigSecretInvalid", err.Error())
+		return ctrl.Result{}, errors.Wrap(err, "failed to override cloud provider config")
+	}
+
 	if err := reconcileAzureSecret(ctx, log, r.Client, owner, newSecret, clusterScope.ClusterName()); err != nil {
 		r.Recorder.Eventf(azureMachineTemplate, corev1.EventTypeWarning, "Error reconciling cloud provider secret for AzureMachineTemplate", err.Error())
 		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile azure secret")
 	}
 
+	if azureCluster.Spec.CloudProviderMode == infrav1.CloudProviderModeExternal {
+		return r.reconcileExternalCloudProvider(ctx, log, clusterScope, azureMachineTemplate, newSecret.Name)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reconcileExternalCloudProvider writes the cloud provider config of the control plane to the workload cluster,
+// where cloud-provider-azure reads it from when it runs as a deployment.
+func (r *AzureJSONTemplateReconciler) reconcileExternalCloudProvider(ctx context.Context, log logr.Logger, clusterScope *scope.ClusterScope, azureMachineTemplate *infrav1.AzureMachineTemplate, secretName string) (ctrl.Result, error) {
+	ctx, span := tele.Tracer().Start(ctx, "controllers.AzureJSONTemplateReconciler.reconcileExternalCloudProvider")
+	defer span.End()
+
+	controlPlaneTemplate, err := clusterScope.ControlPlaneMachineTemplate(ctx)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to get the control plane machine template")
+	}
+	if controlPlaneTemplate == nil || controlPlaneTemplate.Namespace != azureMachineTemplate.Namespace || controlPlaneTemplate.Name != azureMachineTemplate.Name {
+		return ctrl.Result{}, nil
+	}
+
+	// The workload cluster API server is only reachable once the first control plane machine is up.
+	if !conditions.IsTrue(clusterScope.Cluster, clusterv1.ControlPlaneInitializedCondition) {
+		log.V(2).Info("waiting for the control plane to be initialized to write the external cloud provider config")
+		return ctrl.Result{RequeueAfter: externalCloudProviderRequeueInterval}, nil
+	}
+
+	// The secret may have been provided by the user rather than generated, so the config is read back from it.
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: azureMachineTemplate.Namespace, Name: secretName}, secret); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to get azure json")
+	}
+	cloudConfig, ok := secret.Data["control-plane-azure.json"]
+	if !ok {
+		cloudConfig = secret.Data["azure.json"]
+	}
+
+	workloadClient, err := remote.NewClusterClient(ctx, azureJSONTemplateControllerName, r.Client, util.ObjectKey(clusterScope.Cluster))
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to create the workload cluster client")
+	}
+
+	if err := reconcileExternalCloudProviderSecret(ctx, log, workloadClient, cloudConfig); err != nil {
+		r.Recorder.Eventf(azureMachineTemplate, corev1.EventTypeWarning, "Error reconciling external cloud provider config", err.Error())
+		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile external cloud provider config")
+	}
+
 	return ctrl.Result{}, nil
 }
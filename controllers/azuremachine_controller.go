@@ -35,7 +35,6 @@ import (
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -55,6 +54,7 @@ type AzureMachineReconciler struct {
 	Recorder                  record.EventRecorder
 	ReconcileTimeout          time.Duration
 	WatchFilterValue          string
+	requeueInterval           time.Duration
 	createAzureMachineService azureMachineServiceCreator
 }
 
@@ -76,8 +76,9 @@ func NewAzureMachineReconciler(client client.Client, log logr.Logger, recorder r
 }
 
 // SetupWithManager initializes this controller with a manager.
-func (r *AzureMachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+func (r *AzureMachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options Options) error {
 	log := r.Log.WithValues("controller", "AzureMachine")
+	r.requeueInterval = options.RequeueInterval
 	// create mapper to transform incoming AzureClusters into AzureMachine requests
 	azureClusterToAzureMachinesMapper, err := AzureClusterToAzureMachinesMapper(ctx, r.Client, &infrav1.AzureMachineList{}, mgr.GetScheme(), log)
 	if err != nil {
@@ -85,7 +86,7 @@ func (r *AzureMachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.
 	}
 
 	c, err := ctrl.NewControllerManagedBy(mgr).
-		WithOptions(options).
+		WithOptions(options.Options).
 		For(&infrav1.AzureMachine{}).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
 		// watch for changes in CAPI Machine resources
@@ -117,6 +118,13 @@ func (r *AzureMachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.
 		return errors.Wrap(err, "failed adding a watch for ready clusters")
 	}
 
+	// Add a watch on the objects whose Azure resources were modified out-of-band.
+	if options.DriftEvents != nil {
+		if err := c.Watch(&source.Channel{Source: options.DriftEvents}, &handler.EnqueueRequestForObject{}); err != nil {
+			return errors.Wrap(err, "failed adding a watch for drift events")
+		}
+	}
+
 	return nil
 }
 
@@ -151,6 +159,9 @@ func (r *AzureMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return reconcile.Result{}, err
 	}
 
+	// Record the lifecycle events of the Azure resources of the machine on the AzureMachine.
+	ctx = azure.WithEventRecorder(ctx, r.Recorder, azureMachine)
+
 	// Fetch the Machine.
 	machine, err := util.GetOwnerMachine(ctx, r.Client, azureMachine.ObjectMeta)
 	if err != nil {
@@ -193,6 +204,11 @@ func (r *AzureMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	logger = logger.WithValues("AzureCluster", azureCluster.Name)
 
+	// Report the changes to the Azure resources of the machine instead of making them in dry-run mode.
+	if azure.IsDryRun(ctx) || azureCluster.Annotations[infrav1.DryRunAnnotation] == "true" {
+		ctx = azure.WithDryRun(ctx)
+	}
+
 	// Create the cluster scope
 	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
 		Client:       r.Client,
@@ -205,6 +221,9 @@ func (r *AzureMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return reconcile.Result{}, err
 	}
 
+	// Use the API versions supported by the cloud of the cluster, e.g. an Azure Stack Hub.
+	ctx = azure.WithAPIProfile(ctx, azureCluster.Spec.APIProfile, clusterScope.BaseURI())
+
 	// Create the machine scope
 	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
 		Logger:       logger,
@@ -245,8 +264,9 @@ func (r *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineSco
 		return reconcile.Result{}, nil
 	}
 
-	// If the AzureMachine doesn't have our finalizer, add it.
+	// If the AzureMachine doesn't have our finalizers, add them.
 	controllerutil.AddFinalizer(machineScope.AzureMachine, infrav1.MachineFinalizer)
+	addTrackingFinalizers(machineScope.AzureMachine, machineResourceFinalizers)
 	// Register the finalizer immediately to avoid orphaning Azure resources on delete
 	if err := machineScope.PatchObject(ctx); err != nil {
 		return reconcile.Result{}, err
@@ -265,6 +285,10 @@ func (r *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineSco
 		return reconcile.Result{}, nil
 	}
 
+	if err := machineScope.AssignFailureDomain(ctx, clusterScope.AzureCluster.Status.FailureDomains); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to assign a failure domain")
+	}
+
 	ams, err := r.createAzureMachineService(machineScope)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to create azure machine service")
@@ -282,13 +306,14 @@ func (r *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineSco
 			return reconcile.Result{}, errors.Wrap(err, "failed to reconcile AzureMachine")
 		}
 
-		// Handle transient and terminal errors
+		// Handle transient and terminal errors, including ARM errors which retrying won't recover from
+		err = azure.ClassifyError(err)
 		var reconcileError azure.ReconcileError
 		if errors.As(err, &reconcileError) {
 			if reconcileError.IsTerminal() {
-				r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "ReconcileError", errors.Wrapf(err, "failed to reconcile AzureMachine").Error())
+				r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "ReconcileError", azure.ErrorMessage(errors.Wrapf(err, "failed to reconcile AzureMachine")))
 				machineScope.Error(err, "failed to reconcile AzureMachine", "name", machineScope.Name())
-				machineScope.SetFailureReason(capierrors.CreateMachineError)
+				machineScope.SetFailureReason(azure.MachineFailureReason(err))
 				machineScope.SetFailureMessage(err)
 				machineScope.SetNotReady()
 				machineScope.SetVMState(infrav1.Failed)
@@ -296,22 +321,44 @@ func (r *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineSco
 			}
 
 			if reconcileError.IsTransient() {
-				machineScope.Error(err, "transient failure to reconcile AzureMachine, retrying", "name", machineScope.Name())
+				if azure.IsOperationNotDoneError(reconcileError) {
+					machineScope.V(2).Info("AzureMachine reconcile not done, requeueing", "name", machineScope.Name(), "reason", reconcileError.Error())
+				} else if azure.IsQuotaExceededError(reconcileError) {
+					// The quotas were checked before creating the resources, retry once they might have changed.
+					r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, infrav1.QuotaExceededReason, azure.ErrorMessage(err))
+					machineScope.Info("quota exceeded, waiting before creating the VM", "name", machineScope.Name(), "reason", reconcileError.Unwrap().Error())
+					conditions.MarkFalse(machineScope.AzureMachine, infrav1.VMRunningCondition, infrav1.QuotaExceededReason, clusterv1.ConditionSeverityWarning, reconcileError.Unwrap().Error())
+				} else {
+					machineScope.Error(err, "transient failure to reconcile AzureMachine, retrying", "name", machineScope.Name())
+				}
 				machineScope.SetNotReady()
 				return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}, nil
 			}
 		}
-		r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "ReconcileError", errors.Wrapf(err, "failed to reconcile AzureMachine").Error())
-		conditions.MarkFalse(machineScope.AzureMachine, infrav1.VMRunningCondition, infrav1.VMProvisionFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "ReconcileError", azure.ErrorMessage(errors.Wrapf(err, "failed to reconcile AzureMachine")))
+		conditions.MarkFalse(machineScope.AzureMachine, infrav1.VMRunningCondition, infrav1.VMProvisionFailedReason, clusterv1.ConditionSeverityError, azure.ErrorMessage(err))
 		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile AzureMachine")
 	}
 
+	// In dry-run mode, the machine is not ready until the skipped changes are made.
+	if skipped := azure.DryRunOperations(ctx); len(skipped) > 0 {
+		machineScope.Info("AzureMachine reconciled in dry-run mode", "skippedOperations", len(skipped))
+		conditions.MarkFalse(machineScope.AzureMachine, infrav1.VMRunningCondition, infrav1.DryRunReason, clusterv1.ConditionSeverityInfo, "%d Azure operations skipped in dry-run mode", len(skipped))
+		machineScope.SetNotReady()
+		return reconcile.Result{RequeueAfter: r.requeueInterval}, nil
+	}
+
 	machineScope.SetReady()
 
-	return reconcile.Result{}, nil
+	// The VM is enrolled into its backup policy in the background of a ready machine.
+	if conditions.IsFalse(machineScope.AzureMachine, infrav1.VMBackupReadyCondition) {
+		return reconcile.Result{RequeueAfter: azure.DefaultPollingDelay}, nil
+	}
+
+	return reconcile.Result{RequeueAfter: r.requeueInterval}, nil
 }
 
-func (r *AzureMachineReconciler) reconcileDelete(ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) (_ reconcile.Result, reterr error) {
+func (r *AzureMachineReconciler) reconcileDelete(ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) (result reconcile.Result, reterr error) {
 	ctx, span := tele.Tracer().Start(ctx, "controllers.AzureMachineReconciler.reconcileDelete")
 	defer span.End()
 
@@ -323,13 +370,17 @@ func (r *AzureMachineReconciler) reconcileDelete(ctx context.Context, machineSco
 	}
 
 	defer func() {
-		if reterr == nil {
+		// The finalizer is kept while the deletion of the VM is still in progress.
+		if reterr == nil && result.RequeueAfter == 0 {
 			machineScope.Info("Removing finalizer from AzureMachine")
+			removeTrackingFinalizers(machineScope.AzureMachine, machineResourceFinalizers)
 			controllerutil.RemoveFinalizer(machineScope.AzureMachine, infrav1.MachineFinalizer)
 		}
 	}()
 
-	if ShouldDeleteIndividualResources(ctx, clusterScope) {
+	if IsDeletedForMove(machineScope.AzureMachine) {
+		machineScope.Info("AzureMachine was moved to another management cluster, skipping the deletion of its Azure resources")
+	} else if ShouldDeleteIndividualResources(ctx, clusterScope) {
 		machineScope.Info("Deleting AzureMachine")
 		ams, err := r.createAzureMachineService(machineScope)
 		if err != nil {
@@ -338,8 +389,14 @@ func (r *AzureMachineReconciler) reconcileDelete(ctx context.Context, machineSco
 		}
 
 		if err := ams.Delete(ctx); err != nil {
-			r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "Error deleting AzureMachine", errors.Wrapf(err, "error deleting AzureMachine %s/%s", clusterScope.Namespace(), clusterScope.ClusterName()).Error())
-			conditions.MarkFalse(machineScope.AzureMachine, infrav1.VMRunningCondition, clusterv1.DeletionFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			var reconcileError azure.ReconcileError
+			if errors.As(err, &reconcileError) && reconcileError.IsTransient() && azure.IsOperationNotDoneError(err) {
+				machineScope.V(2).Info("AzureMachine deletion not done, requeueing", "name", machineScope.Name(), "reason", err.Error())
+				return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}, nil
+			}
+
+			r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "Error deleting AzureMachine", azure.ErrorMessage(errors.Wrapf(err, "error deleting AzureMachine %s/%s", clusterScope.Namespace(), clusterScope.ClusterName())))
+			conditions.MarkFalse(machineScope.AzureMachine, infrav1.VMRunningCondition, clusterv1.DeletionFailedReason, clusterv1.ConditionSeverityWarning, azure.ErrorMessage(err))
 			reterr = errors.Wrapf(err, "error deleting AzureMachine %s/%s", clusterScope.Namespace(), clusterScope.ClusterName())
 			return
 		}
@@ -0,0 +1,209 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+)
+
+// AzureMachineLookupValidator rejects AzureMachines whose VM size, as described by the resource SKUs of the cluster
+// location, can't satisfy the rest of their spec: sizes below the minimum vCPU and memory requirements, premium disks
+// on sizes without premium storage support, accelerated networking on sizes that don't offer it, and failure domains
+// which don't offer the VM size.
+type AzureMachineLookupValidator struct {
+	Client  client.Client
+	Log     logr.Logger
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &AzureMachineLookupValidator{}
+var _ admission.DecoderInjector = &AzureMachineLookupValidator{}
+
+// InjectDecoder injects the decoder into an AzureMachineLookupValidator.
+func (v *AzureMachineLookupValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle handles admission requests.
+func (v *AzureMachineLookupValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	azureMachine := &infrav1.AzureMachine{}
+	if err := v.decoder.Decode(req, azureMachine); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	// The VM size and the settings it is validated against are immutable, while the failure domain may be set later.
+	validateVMSize := req.Operation == admissionv1.Create && azureMachine.Spec.VMSize != ""
+	validateZone := azureMachine.Spec.FailureDomain != nil && *azureMachine.Spec.FailureDomain != ""
+	if !validateVMSize && !validateZone {
+		return admission.Allowed("")
+	}
+
+	log := v.Log.WithValues("namespace", azureMachine.Namespace, "azureMachine", azureMachine.Name)
+	skuCache, location, err := getClusterSKUCache(ctx, v.Client, v.Log, azureMachine.ObjectMeta)
+	if err != nil {
+		// The SKUs are only a best-effort early check, VM creation will report any remaining incompatibility.
+		log.V(2).Info("skipping resource SKU validation", "reason", err.Error())
+		return admission.Allowed("")
+	}
+
+	var allErrs field.ErrorList
+	if validateVMSize {
+		allErrs = append(allErrs, validateAzureMachineVMSize(ctx, skuCache, azureMachine)...)
+	}
+	if validateZone {
+		if err := validateAzureMachineZone(ctx, skuCache, location, azureMachine); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	if len(allErrs) > 0 {
+		return admission.Denied(allErrs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
+
+// getClusterSKUCache returns the resource SKUs cache and the location of the cluster the object belongs to.
+func getClusterSKUCache(ctx context.Context, c client.Client, log logr.Logger, obj metav1.ObjectMeta) (*resourceskus.Cache, string, error) {
+	cluster, err := util.GetClusterFromMetadata(ctx, c, obj)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to get owner cluster")
+	}
+	if cluster.Spec.InfrastructureRef == nil {
+		return nil, "", errors.Errorf("cluster %s has no infrastructure reference", cluster.Name)
+	}
+
+	azureCluster := &infrav1.AzureCluster{}
+	azureClusterName := client.ObjectKey{
+		Namespace: obj.Namespace,
+		Name:      cluster.Spec.InfrastructureRef.Name,
+	}
+	if err := c.Get(ctx, azureClusterName, azureCluster); err != nil {
+		return nil, "", errors.Wrap(err, "failed to get AzureCluster")
+	}
+
+	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
+		Client:       c,
+		Logger:       log,
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+	})
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to create cluster scope")
+	}
+
+	skuCache, err := resourceskus.GetCache(clusterScope, clusterScope.Location())
+	if err != nil {
+		return nil, "", err
+	}
+	return skuCache, clusterScope.Location(), nil
+}
+
+// validateAzureMachineVMSize returns the requirements of the AzureMachine spec the capabilities of its VM size don't
+// meet, if the VM size is known in the location.
+func validateAzureMachineVMSize(ctx context.Context, skuCache *resourceskus.Cache, azureMachine *infrav1.AzureMachine) field.ErrorList {
+	var allErrs field.ErrorList
+	vmSize := azureMachine.Spec.VMSize
+
+	sku, err := skuCache.Get(ctx, vmSize, resourceskus.VirtualMachines)
+	if err != nil {
+		// Unknown VM sizes are not the concern of this validation.
+		return nil
+	}
+
+	if ok, err := sku.HasCapabilityWithCapacity(resourceskus.VCPUs, resourceskus.MinimumVCPUS); err == nil && !ok {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "vmSize"), vmSize,
+			fmt.Sprintf("VM size should have at least %d vCPUs", resourceskus.MinimumVCPUS)))
+	}
+
+	// Memory is not always a whole number of GiB, e.g. 3.5 for Standard_D1_v2.
+	if memory, ok := sku.GetCapability(resourceskus.MemoryGB); ok {
+		if memoryGB, err := strconv.ParseFloat(memory, 64); err == nil && memoryGB < resourceskus.MinimumMemory {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "vmSize"), vmSize,
+				fmt.Sprintf("VM size should have at least %dGi of memory, it has %sGi", resourceskus.MinimumMemory, memory)))
+		}
+	}
+
+	if !sku.HasCapability(resourceskus.PremiumIO) {
+		if osDisk := azureMachine.Spec.OSDisk.ManagedDisk; osDisk != nil && isPremiumStorage(osDisk.StorageAccountType) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "osDisk", "managedDisk", "storageAccountType"), osDisk.StorageAccountType,
+				fmt.Sprintf("VM size %s does not support premium storage", vmSize)))
+		}
+		for i, disk := range azureMachine.Spec.DataDisks {
+			if disk.ManagedDisk != nil && isPremiumStorage(disk.ManagedDisk.StorageAccountType) {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "dataDisks").Index(i).Child("managedDisk", "storageAccountType"), disk.ManagedDisk.StorageAccountType,
+					fmt.Sprintf("VM size %s does not support premium storage", vmSize)))
+			}
+		}
+	}
+
+	if azureMachine.Spec.AcceleratedNetworking != nil && *azureMachine.Spec.AcceleratedNetworking && !sku.HasCapability(resourceskus.AcceleratedNetworking) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "acceleratedNetworking"), true,
+			fmt.Sprintf("VM size %s does not support accelerated networking", vmSize)))
+	}
+
+	return allErrs
+}
+
+// isPremiumStorage returns true if the storage account type is backed by premium SSDs, e.g. Premium_LRS or Premium_ZRS.
+func isPremiumStorage(storageAccountType string) bool {
+	return strings.HasPrefix(storageAccountType, "Premium_")
+}
+
+// validateAzureMachineZone returns an error if the VM size of the AzureMachine is known in the location,
+// but not offered in the zone set as its failure domain.
+func validateAzureMachineZone(ctx context.Context, skuCache *resourceskus.Cache, location string, azureMachine *infrav1.AzureMachine) *field.Error {
+	vmSize := azureMachine.Spec.VMSize
+	zone := *azureMachine.Spec.FailureDomain
+
+	if _, err := skuCache.Get(ctx, vmSize, resourceskus.VirtualMachines); err != nil {
+		// Unknown VM sizes are not the concern of this validation.
+		return nil
+	}
+
+	zones, err := skuCache.GetZonesWithVMSize(ctx, vmSize, location)
+	if err != nil {
+		return nil
+	}
+	for _, z := range zones {
+		if z == zone {
+			return nil
+		}
+	}
+
+	return field.Invalid(field.NewPath("spec", "failureDomain"), zone,
+		fmt.Sprintf("VM size %s is not available in zone %s of location %s, available zones: [%s]", vmSize, zone, location, strings.Join(zones, ", ")))
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-30/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+)
+
+func TestValidateAzureMachineVMSize(t *testing.T) {
+	newSKU := func(name string, capabilities map[string]string) compute.ResourceSku {
+		var skuCapabilities []compute.ResourceSkuCapabilities
+		for k, v := range capabilities {
+			skuCapabilities = append(skuCapabilities, compute.ResourceSkuCapabilities{Name: to.StringPtr(k), Value: to.StringPtr(v)})
+		}
+		return compute.ResourceSku{
+			Name:         to.StringPtr(name),
+			ResourceType: to.StringPtr(string(resourceskus.VirtualMachines)),
+			Locations:    &[]string{"eastus"},
+			Capabilities: &skuCapabilities,
+		}
+	}
+	skus := []compute.ResourceSku{
+		newSKU("Standard_D2s_v3", map[string]string{
+			resourceskus.VCPUs:                 "2",
+			resourceskus.MemoryGB:              "8",
+			resourceskus.PremiumIO:             "True",
+			resourceskus.AcceleratedNetworking: "True",
+		}),
+		newSKU("Standard_D2_v3", map[string]string{
+			resourceskus.VCPUs:                 "2",
+			resourceskus.MemoryGB:              "8",
+			resourceskus.PremiumIO:             "False",
+			resourceskus.AcceleratedNetworking: "True",
+		}),
+		newSKU("Standard_D1_v2", map[string]string{
+			resourceskus.VCPUs:    "1",
+			resourceskus.MemoryGB: "3.5",
+		}),
+		newSKU("Standard_B2ts_v2", map[string]string{
+			resourceskus.VCPUs:    "2",
+			resourceskus.MemoryGB: "1",
+		}),
+	}
+
+	cases := map[string]struct {
+		spec           infrav1.AzureMachineSpec
+		expectedErrors []string
+	}{
+		"VM size meets the spec": {
+			spec: infrav1.AzureMachineSpec{
+				VMSize: "Standard_D2s_v3",
+				OSDisk: infrav1.OSDisk{
+					ManagedDisk: &infrav1.ManagedDiskParameters{StorageAccountType: "Premium_LRS"},
+				},
+				AcceleratedNetworking: to.BoolPtr(true),
+			},
+		},
+		"unknown VM size": {
+			spec: infrav1.AzureMachineSpec{
+				VMSize: "Standard_Unknown",
+			},
+		},
+		"not enough vCPUs": {
+			spec: infrav1.AzureMachineSpec{
+				VMSize: "Standard_D1_v2",
+			},
+			expectedErrors: []string{
+				"spec.vmSize: Invalid value: \"Standard_D1_v2\": VM size should have at least 2 vCPUs",
+			},
+		},
+		"not enough memory": {
+			spec: infrav1.AzureMachineSpec{
+				VMSize: "Standard_B2ts_v2",
+			},
+			expectedErrors: []string{
+				"spec.vmSize: Invalid value: \"Standard_B2ts_v2\": VM size should have at least 2Gi of memory, it has 1Gi",
+			},
+		},
+		"premium disks without premium storage support": {
+			spec: infrav1.AzureMachineSpec{
+				VMSize: "Standard_D2_v3",
+				OSDisk: infrav1.OSDisk{
+					ManagedDisk: &infrav1.ManagedDiskParameters{StorageAccountType: "Premium_LRS"},
+				},
+				DataDisks: []infrav1.DataDisk{
+					{NameSuffix: "standard", ManagedDisk: &infrav1.ManagedDiskParameters{StorageAccountType: "Standard_LRS"}},
+					{NameSuffix: "premium", ManagedDisk: &infrav1.ManagedDiskParameters{StorageAccountType: "Premium_ZRS"}},
+				},
+			},
+			expectedErrors: []string{
+				"spec.osDisk.managedDisk.storageAccountType: Invalid value: \"Premium_LRS\": VM size Standard_D2_v3 does not support premium storage",
+				"spec.dataDisks[1].managedDisk.storageAccountType: Invalid value: \"Premium_ZRS\": VM size Standard_D2_v3 does not support premium storage",
+			},
+		},
+		"accelerated networking not supported": {
+			spec: infrav1.AzureMachineSpec{
+				VMSize:                "Standard_B2ts_v2",
+				AcceleratedNetworking: to.BoolPtr(true),
+			},
+			expectedErrors: []string{
+				"spec.vmSize: Invalid value: \"Standard_B2ts_v2\": VM size should have at least 2Gi of memory, it has 1Gi",
+				"spec.acceleratedNetworking: Invalid value: true: VM size Standard_B2ts_v2 does not support accelerated networking",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			azureMachine := &infrav1.AzureMachine{Spec: tc.spec}
+			errs := validateAzureMachineVMSize(context.TODO(), resourceskus.NewStaticCache(skus, "eastus"), azureMachine)
+			var actual []string
+			for _, err := range errs {
+				actual = append(actual, err.Error())
+			}
+			g.Expect(actual).To(Equal(tc.expectedErrors))
+		})
+	}
+}
+
+func TestValidateAzureMachineZone(t *testing.T) {
+	skus := []compute.ResourceSku{
+		{
+			Name:         to.StringPtr("Standard_D2s_v3"),
+			ResourceType: to.StringPtr(string(resourceskus.VirtualMachines)),
+			Locations:    &[]string{"eastus"},
+			LocationInfo: &[]compute.ResourceSkuLocationInfo{
+				{
+					Location: to.StringPtr("eastus"),
+					Zones:    &[]string{"1", "2"},
+				},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		vmSize        string
+		failureDomain string
+		expectedError string
+	}{
+		"zone offers the VM size": {
+			vmSize:        "Standard_D2s_v3",
+			failureDomain: "2",
+		},
+		"zone does not offer the VM size": {
+			vmSize:        "Standard_D2s_v3",
+			failureDomain: "3",
+			expectedError: "spec.failureDomain: Invalid value: \"3\": VM size Standard_D2s_v3 is not available in zone 3 of location eastus, available zones: [1, 2]",
+		},
+		"unknown VM size": {
+			vmSize:        "Standard_Unknown",
+			failureDomain: "3",
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			azureMachine := &infrav1.AzureMachine{
+				Spec: infrav1.AzureMachineSpec{
+					VMSize:        tc.vmSize,
+					FailureDomain: to.StringPtr(tc.failureDomain),
+				},
+			}
+			err := validateAzureMachineZone(context.TODO(), resourceskus.NewStaticCache(skus, "eastus"), "eastus", azureMachine)
+			if tc.expectedError != "" {
+				g.Expect(err).NotTo(BeNil())
+				g.Expect(err.Error()).To(Equal(tc.expectedError))
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
@@ -20,10 +20,13 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/availabilitysets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bootstrapsecrets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/disks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inboundnatrules"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
@@ -32,6 +35,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmbackups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensions"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -48,6 +52,8 @@ type azureMachineService struct {
 	tagsSvc              azure.Reconciler
 	vmExtensionsSvc      azure.Reconciler
 	availabilitySetsSvc  azure.Reconciler
+	bootstrapSecretsSvc  azure.Reconciler
+	vmBackupsSvc         azure.Reconciler
 	skuCache             *resourceskus.Cache
 }
 
@@ -71,6 +77,8 @@ func newAzureMachineService(machineScope *scope.MachineScope) (*azureMachineServ
 		tagsSvc:              tags.New(machineScope),
 		vmExtensionsSvc:      vmextensions.New(machineScope),
 		availabilitySetsSvc:  availabilitysets.New(machineScope, cache),
+		bootstrapSecretsSvc:  bootstrapsecrets.New(machineScope),
+		vmBackupsSvc:         vmbackups.New(machineScope),
 		skuCache:             cache,
 	}, nil
 }
@@ -84,69 +92,108 @@ func (s *azureMachineService) Reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "failed defaulting subnet name")
 	}
 
-	if err := s.publicIPsSvc.Reconcile(ctx); err != nil {
+	if err := s.scope.ValidateRDP(); err != nil {
+		return errors.Wrap(err, "failed to enable Remote Desktop access")
+	}
+
+	if err := s.reconcileService(ctx, s.publicIPsSvc, infrav1.PublicIPsReadyCondition, "public IPs"); err != nil {
 		return errors.Wrap(err, "failed to create public IP")
 	}
 
-	if err := s.inboundNatRulesSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, s.inboundNatRulesSvc, infrav1.InboundNATRulesReadyCondition, "inbound NAT rule"); err != nil {
 		return errors.Wrap(err, "failed to create inbound NAT rule")
 	}
 
-	if err := s.networkInterfacesSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, s.networkInterfacesSvc, infrav1.NetworkInterfacesReadyCondition, "network interface"); err != nil {
 		return errors.Wrap(err, "failed to create network interface")
 	}
 
-	if err := s.availabilitySetsSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, s.availabilitySetsSvc, infrav1.AvailabilitySetReadyCondition, "availability set"); err != nil {
 		return errors.Wrap(err, "failed to create availability set")
 	}
 
-	if err := s.virtualMachinesSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, s.bootstrapSecretsSvc, "", ""); err != nil {
+		return errors.Wrap(err, "failed to reconcile bootstrap data secret")
+	}
+
+	if err := s.reconcileService(ctx, s.virtualMachinesSvc, "", ""); err != nil {
 		return errors.Wrap(err, "failed to create virtual machine")
 	}
 
-	if err := s.roleAssignmentsSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, s.disksSvc, infrav1.DisksReadyCondition, "disks"); err != nil {
+		return errors.Wrap(err, "failed to reconcile disks")
+	}
+
+	if err := s.reconcileService(ctx, s.roleAssignmentsSvc, "", ""); err != nil {
 		return errors.Wrap(err, "unable to create role assignment")
 	}
 
-	if err := s.vmExtensionsSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, s.vmExtensionsSvc, infrav1.VMExtensionsReadyCondition, "vm extension"); err != nil {
 		return errors.Wrap(err, "unable to create vm extension")
 	}
 
-	if err := s.tagsSvc.Reconcile(ctx); err != nil {
+	if err := s.reconcileService(ctx, s.tagsSvc, "", ""); err != nil {
 		return errors.Wrap(err, "unable to update tags")
 	}
 
+	// The machine doesn't wait for its VM to be enrolled into its backup policy: vaults discover new VMs
+	// asynchronously, so the enrollment is retried by the next reconciliation loops until the condition reports it.
+	if s.scope.BackupSpec() != nil {
+		if err := s.reconcileService(ctx, s.vmBackupsSvc, infrav1.VMBackupReadyCondition, "vm backup"); err != nil {
+			s.scope.V(2).Info("vm backup not enabled yet, retrying later", "reason", err.Error())
+		}
+	}
+
 	return nil
 }
 
-// Delete deletes all the services in a predetermined order.
-func (s *azureMachineService) Delete(ctx context.Context) error {
-	ctx, span := tele.Tracer().Start(ctx, "controllers.azureMachineService.Delete")
-	defer span.End()
-
-	if err := s.virtualMachinesSvc.Delete(ctx); err != nil {
-		return errors.Wrap(err, "failed to delete machine")
-	}
-
-	if err := s.networkInterfacesSvc.Delete(ctx); err != nil {
-		return errors.Wrap(err, "failed to delete network interface")
-	}
+// machineResourceFinalizers are the finalizers tracking the Azure resources of an AzureMachine, by deletion step.
+var machineResourceFinalizers = map[string]string{
+	"machine":           infrav1.VMFinalizer,
+	"network interface": infrav1.NetworkInterfacesFinalizer,
+	"inbound NAT rule":  infrav1.InboundNatRulesFinalizer,
+	"public IPs":        infrav1.PublicIPsFinalizer,
+	"OS disk":           infrav1.DisksFinalizer,
+	"availability set":  infrav1.AvailabilitySetFinalizer,
+}
 
-	if err := s.inboundNatRulesSvc.Delete(ctx); err != nil {
-		return errors.Wrap(err, "failed to delete inbound NAT rule")
+// reconcileService reconciles the resources of svc and reports on them in the given condition of the AzureMachine, if
+// any. The requests skipped in dry-run mode are not errors, so that the next services report theirs too.
+func (s *azureMachineService) reconcileService(ctx context.Context, svc azure.Reconciler, condition clusterv1.ConditionType, name string) error {
+	err := svc.Reconcile(ctx)
+	if condition != "" {
+		s.scope.UpdatePutStatus(condition, name, err)
 	}
-
-	if err := s.publicIPsSvc.Delete(ctx); err != nil {
-		return errors.Wrap(err, "failed to delete public IPs")
+	if azure.IsDryRunError(err) {
+		return nil
 	}
+	return err
+}
 
-	if err := s.disksSvc.Delete(ctx); err != nil {
-		return errors.Wrap(err, "failed to delete OS disk")
-	}
+// machineResourceConditions are the conditions of the AzureMachine reporting on its Azure resources, by deletion step.
+var machineResourceConditions = map[string]clusterv1.ConditionType{
+	"network interface": infrav1.NetworkInterfacesReadyCondition,
+	"inbound NAT rule":  infrav1.InboundNATRulesReadyCondition,
+	"public IPs":        infrav1.PublicIPsReadyCondition,
+	"OS disk":           infrav1.DisksReadyCondition,
+	"availability set":  infrav1.AvailabilitySetReadyCondition,
+}
 
-	if err := s.availabilitySetsSvc.Delete(ctx); err != nil {
-		return errors.Wrap(err, "failed to delete availability set")
-	}
+// Delete deletes all the services, concurrently where they don't depend on each other.
+func (s *azureMachineService) Delete(ctx context.Context) error {
+	ctx, span := tele.Tracer().Start(ctx, "controllers.azureMachineService.Delete")
+	defer span.End()
 
-	return nil
+	steps := trackWithFinalizers(s.scope.AzureMachine, []deletionStep{
+		{name: "machine", svc: s.virtualMachinesSvc},
+		{name: "network interface", svc: s.networkInterfacesSvc, dependsOn: []string{"machine"}},
+		{name: "inbound NAT rule", svc: s.inboundNatRulesSvc, dependsOn: []string{"network interface"}},
+		{name: "public IPs", svc: s.publicIPsSvc, dependsOn: []string{"network interface"}},
+		{name: "OS disk", svc: s.disksSvc, dependsOn: []string{"machine"}},
+		{name: "availability set", svc: s.availabilitySetsSvc, dependsOn: []string{"machine"}},
+		{name: "bootstrap data secret", svc: s.bootstrapSecretsSvc},
+		{name: "backup", svc: s.vmBackupsSvc, dependsOn: []string{"machine"}},
+	}, machineResourceFinalizers)
+
+	return deleteConcurrently(ctx, trackWithConditions(steps, machineResourceConditions, s.scope.UpdateDeleteStatus))
 }
@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	utilSSH "sigs.k8s.io/cluster-api-provider-azure/util/ssh"
+)
+
+// sshPublicKeySecretKey is the key of the public key in the cluster SSH keypair Secret, the private key is stored
+// under the corev1.SSHAuthPrivateKey key of the kubernetes.io/ssh-auth Secret type.
+const sshPublicKeySecretKey = "ssh-publickey"
+
+// AzureMachineSSHKeyDefaulter sets the SSH public key of AzureMachines created without one. The key comes from a
+// keypair generated once per cluster and stored in the <cluster name>-ssh-keypair Secret, so that the private key can
+// be retrieved to access the nodes. AzureMachines which don't belong to a cluster get a throwaway key instead.
+type AzureMachineSSHKeyDefaulter struct {
+	Client client.Client
+	// APIReader reads the keypair Secret without going through the cache, so that concurrent requests for the
+	// machines of a new cluster agree on its keypair.
+	APIReader client.Reader
+	Log       logr.Logger
+	decoder   *admission.Decoder
+}
+
+var _ admission.Handler = &AzureMachineSSHKeyDefaulter{}
+var _ admission.DecoderInjector = &AzureMachineSSHKeyDefaulter{}
+
+// InjectDecoder injects the decoder into an AzureMachineSSHKeyDefaulter.
+func (d *AzureMachineSSHKeyDefaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+// Handle handles admission requests.
+func (d *AzureMachineSSHKeyDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create {
+		// The SSH public key is immutable.
+		return admission.Allowed("")
+	}
+
+	azureMachine := &infrav1.AzureMachine{}
+	if err := d.decoder.Decode(req, azureMachine); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if azureMachine.Spec.SSHPublicKey != "" {
+		return admission.Allowed("")
+	}
+
+	log := d.Log.WithValues("namespace", azureMachine.Namespace, "azureMachine", azureMachine.Name)
+	if req.DryRun != nil && *req.DryRun {
+		// Dry runs must not create the keypair Secret.
+		if err := azureMachine.Spec.SetDefaultSSHPublicKey(); err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+	} else if err := d.setSSHPublicKey(ctx, azureMachine); err != nil {
+		log.Error(err, "failed to get the cluster SSH keypair, generating a throwaway SSH key")
+		if err := azureMachine.Spec.SetDefaultSSHPublicKey(); err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+	}
+
+	marshaled, err := json.Marshal(azureMachine)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// setSSHPublicKey sets the SSH public key of the AzureMachine to the public key of its cluster keypair.
+func (d *AzureMachineSSHKeyDefaulter) setSSHPublicKey(ctx context.Context, azureMachine *infrav1.AzureMachine) error {
+	clusterName, ok := azureMachine.Labels[clusterv1.ClusterLabelName]
+	if !ok || clusterName == "" {
+		return errors.Errorf("missing %s label", clusterv1.ClusterLabelName)
+	}
+
+	secret, err := d.getOrCreateSSHKeySecret(ctx, azureMachine.Namespace, clusterName)
+	if err != nil {
+		return err
+	}
+
+	publicKey := secret.Data[sshPublicKeySecretKey]
+	if len(publicKey) == 0 {
+		return errors.Errorf("secret %s/%s has no %s key", secret.Namespace, secret.Name, sshPublicKeySecretKey)
+	}
+	azureMachine.Spec.SSHPublicKey = base64.StdEncoding.EncodeToString(publicKey)
+	return nil
+}
+
+// getOrCreateSSHKeySecret returns the Secret holding the SSH keypair of the cluster, generating the keypair if the
+// Secret doesn't exist yet.
+func (d *AzureMachineSSHKeyDefaulter) getOrCreateSSHKeySecret(ctx context.Context, namespace, clusterName string) (*corev1.Secret, error) {
+	key := client.ObjectKey{Namespace: namespace, Name: SSHKeySecretName(clusterName)}
+	secret := &corev1.Secret{}
+	err := d.APIReader.Get(ctx, key, secret)
+	if err == nil {
+		return secret, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, errors.Wrapf(err, "failed to get secret %s", key)
+	}
+
+	privateKey, publicKey, err := utilSSH.GenerateSSHKey()
+	if err != nil {
+		return nil, err
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      key.Name,
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName: clusterName,
+			},
+		},
+		Type: corev1.SecretTypeSSHAuth,
+		Data: map[string][]byte{
+			corev1.SSHAuthPrivateKey: pem.EncodeToMemory(&pem.Block{
+				Type:  "RSA PRIVATE KEY",
+				Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+			}),
+			sshPublicKeySecretKey: ssh.MarshalAuthorizedKey(publicKey),
+		},
+	}
+
+	// The Cluster owns the keypair so that it is deleted and moved along with it.
+	cluster := &clusterv1.Cluster{}
+	if err := d.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: clusterName}, cluster); err == nil {
+		secret.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+			Name:       cluster.Name,
+			UID:        cluster.UID,
+		}}
+	}
+
+	if err := d.Client.Create(ctx, secret); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return nil, errors.Wrapf(err, "failed to create secret %s", key)
+		}
+		// Another machine of the cluster created the keypair in the meantime.
+		secret = &corev1.Secret{}
+		if err := d.APIReader.Get(ctx, key, secret); err != nil {
+			return nil, errors.Wrapf(err, "failed to get secret %s", key)
+		}
+	}
+	return secret, nil
+}
+
+// SSHKeySecretName returns the name of the Secret holding the SSH keypair generated for the cluster.
+func SSHKeySecretName(clusterName string) string {
+	return fmt.Sprintf("%s-ssh-keypair", clusterName)
+}
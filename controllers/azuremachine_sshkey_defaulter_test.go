@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2/klogr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+)
+
+func TestAzureMachineSSHKeyDefaulterSetSSHPublicKey(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := newScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster",
+			Namespace: "default",
+		},
+	}
+	newAzureMachine := func(name string) *infrav1.AzureMachine {
+		return &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName: "my-cluster",
+				},
+			},
+		}
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build()
+	d := &AzureMachineSSHKeyDefaulter{
+		Client:    fakeClient,
+		APIReader: fakeClient,
+		Log:       klogr.New(),
+	}
+
+	first := newAzureMachine("my-machine-0")
+	g.Expect(d.setSSHPublicKey(context.TODO(), first)).To(Succeed())
+	g.Expect(first.Spec.SSHPublicKey).NotTo(BeEmpty())
+	g.Expect(infrav1.ValidateSSHKey(first.Spec.SSHPublicKey, field.NewPath("sshPublicKey"))).To(BeEmpty())
+
+	secret := &corev1.Secret{}
+	g.Expect(fakeClient.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "my-cluster-ssh-keypair"}, secret)).To(Succeed())
+	g.Expect(secret.Type).To(Equal(corev1.SecretTypeSSHAuth))
+	g.Expect(secret.Data).To(HaveKey(corev1.SSHAuthPrivateKey))
+	g.Expect(base64.StdEncoding.EncodeToString(secret.Data[sshPublicKeySecretKey])).To(Equal(first.Spec.SSHPublicKey))
+	g.Expect(secret.Labels).To(HaveKeyWithValue(clusterv1.ClusterLabelName, "my-cluster"))
+	g.Expect(secret.OwnerReferences).To(HaveLen(1))
+	g.Expect(secret.OwnerReferences[0].Name).To(Equal("my-cluster"))
+
+	// The other machines of the cluster reuse the keypair.
+	second := newAzureMachine("my-machine-1")
+	g.Expect(d.setSSHPublicKey(context.TODO(), second)).To(Succeed())
+	g.Expect(second.Spec.SSHPublicKey).To(Equal(first.Spec.SSHPublicKey))
+
+	unlabeled := newAzureMachine("my-machine-2")
+	unlabeled.Labels = nil
+	g.Expect(d.setSSHPublicKey(context.TODO(), unlabeled)).NotTo(Succeed())
+}
+
+func TestAzureMachineSSHKeyDefaulterHandle(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := newScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+	decoder, err := admission.NewDecoder(scheme)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	newRequest := func(operation admissionv1.Operation, azureMachine *infrav1.AzureMachine) admission.Request {
+		raw, err := json.Marshal(azureMachine)
+		g.Expect(err).NotTo(HaveOccurred())
+		return admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: operation,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		}
+	}
+	withoutKey := &infrav1.AzureMachine{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: infrav1.GroupVersion.String(),
+			Kind:       "AzureMachine",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-machine",
+			Namespace: "default",
+		},
+	}
+	withKey := withoutKey.DeepCopy()
+	withKey.Spec.SSHPublicKey = "key"
+
+	cases := map[string]struct {
+		request         admission.Request
+		expectedPatches bool
+	}{
+		"machine without a cluster gets a throwaway key": {
+			request:         newRequest(admissionv1.Create, withoutKey),
+			expectedPatches: true,
+		},
+		"machine with a key": {
+			request: newRequest(admissionv1.Create, withKey),
+		},
+		"update": {
+			request: newRequest(admissionv1.Update, withoutKey),
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+			d := &AzureMachineSSHKeyDefaulter{
+				Client:    fakeClient,
+				APIReader: fakeClient,
+				Log:       klogr.New(),
+			}
+			g.Expect(d.InjectDecoder(decoder)).To(Succeed())
+
+			response := d.Handle(context.TODO(), tc.request)
+			g.Expect(response.Allowed).To(BeTrue())
+			if tc.expectedPatches {
+				g.Expect(response.Patches).NotTo(BeEmpty())
+			} else {
+				g.Expect(response.Patches).To(BeEmpty())
+			}
+		})
+	}
+}
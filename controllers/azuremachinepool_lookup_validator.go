@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterexpv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	infraexpv1 "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/util/slice"
+)
+
+// AzureMachinePoolLookupValidator rejects AzureMachinePools whose VM size, as described by the resource SKUs of the
+// cluster location, isn't offered in all the failure domains of their MachinePool, before the scale set is created.
+type AzureMachinePoolLookupValidator struct {
+	Client  client.Client
+	Log     logr.Logger
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &AzureMachinePoolLookupValidator{}
+var _ admission.DecoderInjector = &AzureMachinePoolLookupValidator{}
+
+// InjectDecoder injects the decoder into an AzureMachinePoolLookupValidator.
+func (v *AzureMachinePoolLookupValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle handles admission requests.
+func (v *AzureMachinePoolLookupValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	azureMachinePool := &infraexpv1.AzureMachinePool{}
+	if err := v.decoder.Decode(req, azureMachinePool); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	log := v.Log.WithValues("namespace", azureMachinePool.Namespace, "azureMachinePool", azureMachinePool.Name)
+	machinePool, err := v.getMachinePool(ctx, azureMachinePool)
+	if err != nil {
+		log.V(2).Info("skipping failure domains validation", "reason", err.Error())
+		return admission.Allowed("")
+	}
+	if machinePool == nil || len(machinePool.Spec.FailureDomains) == 0 {
+		return admission.Allowed("")
+	}
+
+	skuCache, location, err := getClusterSKUCache(ctx, v.Client, v.Log, azureMachinePool.ObjectMeta)
+	if err != nil {
+		// The SKUs are only a best-effort early check, the scale set reconciliation will report any remaining
+		// incompatibility.
+		log.V(2).Info("skipping failure domains validation", "reason", err.Error())
+		return admission.Allowed("")
+	}
+
+	if err := validateAzureMachinePoolZones(ctx, skuCache, location, azureMachinePool, machinePool.Spec.FailureDomains); err != nil {
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("")
+}
+
+// getMachinePool returns the MachinePool using the AzureMachinePool as infrastructure, if any.
+func (v *AzureMachinePoolLookupValidator) getMachinePool(ctx context.Context, azureMachinePool *infraexpv1.AzureMachinePool) (*clusterexpv1.MachinePool, error) {
+	machinePools := &clusterexpv1.MachinePoolList{}
+	if err := v.Client.List(ctx, machinePools, client.InNamespace(azureMachinePool.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list machine pools")
+	}
+
+	for i := range machinePools.Items {
+		ref := machinePools.Items[i].Spec.Template.Spec.InfrastructureRef
+		if ref.Kind == "AzureMachinePool" && ref.Name == azureMachinePool.Name {
+			return &machinePools.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// validateAzureMachinePoolZones returns an error if the VM size of the AzureMachinePool is known in the location, but
+// not offered in some of the failure domains.
+func validateAzureMachinePoolZones(ctx context.Context, skuCache *resourceskus.Cache, location string, azureMachinePool *infraexpv1.AzureMachinePool, failureDomains []string) *field.Error {
+	vmSize := azureMachinePool.Spec.Template.VMSize
+
+	if _, err := skuCache.Get(ctx, vmSize, resourceskus.VirtualMachines); err != nil {
+		// Unknown VM sizes are not the concern of this validation.
+		return nil
+	}
+
+	zones, err := skuCache.GetZonesWithVMSize(ctx, vmSize, location)
+	if err != nil {
+		return nil
+	}
+
+	var unavailable []string
+	for _, zone := range failureDomains {
+		if !slice.Contains(zones, zone) {
+			unavailable = append(unavailable, zone)
+		}
+	}
+	if len(unavailable) == 0 {
+		return nil
+	}
+
+	return field.Invalid(field.NewPath("spec", "template", "vmSize"), vmSize,
+		fmt.Sprintf("VM size %s is not available in zones [%s] of the machine pool failure domains in location %s, available zones: [%s]",
+			vmSize, strings.Join(unavailable, ", "), location, strings.Join(zones, ", ")))
+}
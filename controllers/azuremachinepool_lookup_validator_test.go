@@ -0,0 +1,165 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-30/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2/klogr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	clusterexpv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	infraexpv1 "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha4"
+)
+
+func TestAzureMachinePoolLookupValidatorGetMachinePool(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := newScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	azureMachinePool := &infraexpv1.AzureMachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-azure-machine-pool",
+			Namespace: "default",
+		},
+	}
+	newMachinePool := func(name, namespace, infraName string) client.Object {
+		return &clusterexpv1.MachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: clusterexpv1.MachinePoolSpec{
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						InfrastructureRef: corev1.ObjectReference{
+							Kind: "AzureMachinePool",
+							Name: infraName,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		objects             []client.Object
+		expectedMachinePool string
+	}{
+		"machine pool references the AzureMachinePool": {
+			objects: []client.Object{
+				newMachinePool("other-machine-pool", "default", "other-azure-machine-pool"),
+				newMachinePool("my-machine-pool", "default", "my-azure-machine-pool"),
+			},
+			expectedMachinePool: "my-machine-pool",
+		},
+		"machine pool in another namespace": {
+			objects: []client.Object{
+				newMachinePool("my-machine-pool", "other", "my-azure-machine-pool"),
+			},
+		},
+		"no machine pool": {},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			v := &AzureMachinePoolLookupValidator{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.objects...).Build(),
+				Log:    klogr.New(),
+			}
+			machinePool, err := v.getMachinePool(context.TODO(), azureMachinePool)
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expectedMachinePool != "" {
+				g.Expect(machinePool).NotTo(BeNil())
+				g.Expect(machinePool.Name).To(Equal(tc.expectedMachinePool))
+			} else {
+				g.Expect(machinePool).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateAzureMachinePoolZones(t *testing.T) {
+	skus := []compute.ResourceSku{
+		{
+			Name:         to.StringPtr("Standard_D2s_v3"),
+			ResourceType: to.StringPtr(string(resourceskus.VirtualMachines)),
+			Locations:    &[]string{"eastus"},
+			LocationInfo: &[]compute.ResourceSkuLocationInfo{
+				{
+					Location: to.StringPtr("eastus"),
+					Zones:    &[]string{"1", "2"},
+				},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		vmSize         string
+		failureDomains []string
+		expectedError  string
+	}{
+		"zones offer the VM size": {
+			vmSize:         "Standard_D2s_v3",
+			failureDomains: []string{"1", "2"},
+		},
+		"some zones do not offer the VM size": {
+			vmSize:         "Standard_D2s_v3",
+			failureDomains: []string{"1", "3", "4"},
+			expectedError:  "spec.template.vmSize: Invalid value: \"Standard_D2s_v3\": VM size Standard_D2s_v3 is not available in zones [3, 4] of the machine pool failure domains in location eastus, available zones: [1, 2]",
+		},
+		"unknown VM size": {
+			vmSize:         "Standard_Unknown",
+			failureDomains: []string{"3"},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			azureMachinePool := &infraexpv1.AzureMachinePool{
+				Spec: infraexpv1.AzureMachinePoolSpec{
+					Template: infraexpv1.AzureMachinePoolMachineTemplate{
+						VMSize: tc.vmSize,
+					},
+				},
+			}
+			err := validateAzureMachinePoolZones(context.TODO(), resourceskus.NewStaticCache(skus, "eastus"), "eastus", azureMachinePool, tc.failureDomains)
+			if tc.expectedError != "" {
+				g.Expect(err).NotTo(BeNil())
+				g.Expect(err.Error()).To(Equal(tc.expectedError))
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// deletionStep is the deletion of the resources of a single service, which has to wait for the deletion of the
+// resources it depends on.
+type deletionStep struct {
+	name      string
+	svc       azure.Reconciler
+	dependsOn []string
+	// skip is set if the resources of the step are already known to be deleted.
+	skip bool
+	// onDeleted, if set, is called once the resources of the step are deleted.
+	onDeleted func()
+	// onResult, if set, is called with the result of the deletion of the resources of the step, once it was attempted.
+	onResult func(err error)
+}
+
+// trackWithFinalizers ties each step to a finalizer of obj tracking its resources, by step name. The steps whose finalizer was
+// already removed are skipped, and the finalizer of each step is removed once its resources are deleted. The steps of objects
+// carrying none of the finalizers, which were created before they were introduced, are all run.
+func trackWithFinalizers(obj client.Object, steps []deletionStep, finalizers map[string]string) []deletionStep {
+	tracked := false
+	for _, finalizer := range finalizers {
+		if controllerutil.ContainsFinalizer(obj, finalizer) {
+			tracked = true
+			break
+		}
+	}
+
+	for i := range steps {
+		finalizer, ok := finalizers[steps[i].name]
+		if !ok {
+			continue
+		}
+		steps[i].skip = tracked && !controllerutil.ContainsFinalizer(obj, finalizer)
+		steps[i].onDeleted = func() {
+			controllerutil.RemoveFinalizer(obj, finalizer)
+		}
+	}
+	return steps
+}
+
+// trackWithConditions reports the result of each step in a condition, by step name, using update.
+func trackWithConditions(steps []deletionStep, conds map[string]clusterv1.ConditionType, update func(clusterv1.ConditionType, string, error)) []deletionStep {
+	for i := range steps {
+		condition, ok := conds[steps[i].name]
+		if !ok {
+			continue
+		}
+		name := steps[i].name
+		steps[i].onResult = func(err error) {
+			update(condition, name, err)
+		}
+	}
+	return steps
+}
+
+// addTrackingFinalizers adds the finalizers tracking the resources of deletion steps to obj, in a stable order.
+func addTrackingFinalizers(obj client.Object, finalizers map[string]string) {
+	sorted := make([]string, 0, len(finalizers))
+	for _, finalizer := range finalizers {
+		sorted = append(sorted, finalizer)
+	}
+	sort.Strings(sorted)
+	for _, finalizer := range sorted {
+		controllerutil.AddFinalizer(obj, finalizer)
+	}
+}
+
+// removeTrackingFinalizers removes the finalizers tracking the resources of deletion steps from obj.
+func removeTrackingFinalizers(obj client.Object, finalizers map[string]string) {
+	for _, finalizer := range finalizers {
+		controllerutil.RemoveFinalizer(obj, finalizer)
+	}
+}
+
+// deleteConcurrently deletes the resources of each step as soon as the steps it depends on have succeeded, so that
+// independent resources are deleted in parallel. A failing step only holds back the steps depending on it; the
+// errors of all failed steps are aggregated. Errors of operations that are still in progress are only returned when
+// no step failed, so that the caller requeues instead of backing off. Skipped steps count as succeeded, and the
+// onDeleted and onResult callbacks of the steps are never called concurrently.
+func deleteConcurrently(ctx context.Context, steps []deletionStep) error {
+	done := make(map[string]chan struct{}, len(steps))
+	failed := make(map[string]bool, len(steps))
+	for _, step := range steps {
+		done[step.name] = make(chan struct{})
+	}
+	for _, step := range steps {
+		for _, dep := range step.dependsOn {
+			if _, ok := done[dep]; !ok {
+				return errors.Errorf("deletion step %s depends on unknown step %s", step.name, dep)
+			}
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		errs     = make([]error, len(steps))
+		notDones = make([]error, len(steps))
+	)
+	for i, step := range steps {
+		wg.Add(1)
+		go func(i int, step deletionStep) {
+			defer wg.Done()
+			defer close(done[step.name])
+
+			for _, dep := range step.dependsOn {
+				<-done[dep]
+				mu.Lock()
+				depFailed := failed[dep]
+				mu.Unlock()
+				if depFailed {
+					mu.Lock()
+					failed[step.name] = true
+					mu.Unlock()
+					return
+				}
+			}
+
+			if step.skip {
+				return
+			}
+
+			err := step.svc.Delete(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			if step.onResult != nil {
+				step.onResult(err)
+			}
+			if err == nil || azure.ResourceNotFound(err) {
+				if step.onDeleted != nil {
+					step.onDeleted()
+				}
+				return
+			}
+			failed[step.name] = true
+			err = errors.Wrapf(err, "failed to delete %s", step.name)
+			if azure.IsOperationNotDoneError(err) {
+				notDones[i] = err
+				return
+			}
+			errs[i] = err
+		}(i, step)
+	}
+	wg.Wait()
+
+	if err := flatten(errs); err != nil {
+		return err
+	}
+	for _, err := range notDones {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flatten returns the only error of errs as is, so that it can still be inspected with errors.As, and an aggregate
+// if there are several.
+func flatten(errs []error) error {
+	agg := kerrors.NewAggregate(errs)
+	if agg == nil {
+		return nil
+	}
+	if len(agg.Errors()) == 1 {
+		return agg.Errors()[0]
+	}
+	return agg
+}
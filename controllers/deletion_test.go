@@ -0,0 +1,242 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/mocks"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+func TestDeleteConcurrently(t *testing.T) {
+	notDoneErr := azure.WithTransientError(azure.NewOperationNotDoneError(&infrav1.Future{}), 15*time.Second)
+
+	cases := []struct {
+		name          string
+		expect        func(a, b, c *mocks.MockReconcilerMockRecorder)
+		expectedError string
+		expectNotDone bool
+	}{
+		{
+			name: "deletes each step after its dependencies",
+			expect: func(a, b, c *mocks.MockReconcilerMockRecorder) {
+				aDelete := a.Delete(gomockinternal.AContext())
+				bDelete := b.Delete(gomockinternal.AContext())
+				c.Delete(gomockinternal.AContext()).After(aDelete).After(bDelete)
+			},
+		},
+		{
+			name: "skips the steps depending on a failed step",
+			expect: func(a, b, c *mocks.MockReconcilerMockRecorder) {
+				a.Delete(gomockinternal.AContext()).Return(errors.New("boom"))
+				b.Delete(gomockinternal.AContext())
+			},
+			expectedError: "failed to delete a: boom",
+		},
+		{
+			name: "aggregates the errors of independent steps",
+			expect: func(a, b, c *mocks.MockReconcilerMockRecorder) {
+				a.Delete(gomockinternal.AContext()).Return(errors.New("boom"))
+				b.Delete(gomockinternal.AContext()).Return(errors.New("bang"))
+			},
+			expectedError: "[failed to delete a: boom, failed to delete b: bang]",
+		},
+		{
+			name: "returns operations in progress if no step failed",
+			expect: func(a, b, c *mocks.MockReconcilerMockRecorder) {
+				a.Delete(gomockinternal.AContext()).Return(notDoneErr)
+				b.Delete(gomockinternal.AContext())
+			},
+			expectNotDone: true,
+		},
+		{
+			name: "returns failures over operations in progress",
+			expect: func(a, b, c *mocks.MockReconcilerMockRecorder) {
+				a.Delete(gomockinternal.AContext()).Return(notDoneErr)
+				b.Delete(gomockinternal.AContext()).Return(errors.New("bang"))
+			},
+			expectedError: "failed to delete b: bang",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			aMock := mocks.NewMockReconciler(mockCtrl)
+			bMock := mocks.NewMockReconciler(mockCtrl)
+			cMock := mocks.NewMockReconciler(mockCtrl)
+
+			tc.expect(aMock.EXPECT(), bMock.EXPECT(), cMock.EXPECT())
+
+			err := deleteConcurrently(context.TODO(), []deletionStep{
+				{name: "c", svc: cMock, dependsOn: []string{"a", "b"}},
+				{name: "a", svc: aMock},
+				{name: "b", svc: bMock},
+			})
+			switch {
+			case tc.expectNotDone:
+				g.Expect(azure.IsOperationNotDoneError(err)).To(BeTrue())
+				var reconcileError azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+				g.Expect(reconcileError.IsTransient()).To(BeTrue())
+			case tc.expectedError != "":
+				g.Expect(err).To(MatchError(tc.expectedError))
+			default:
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteConcurrentlyUnknownDependency(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	err := deleteConcurrently(context.TODO(), []deletionStep{
+		{name: "a", svc: mocks.NewMockReconciler(mockCtrl), dependsOn: []string{"b"}},
+	})
+	g.Expect(err).To(MatchError("deletion step a depends on unknown step b"))
+}
+
+func TestDeleteConcurrentlyTrackedWithFinalizers(t *testing.T) {
+	finalizers := map[string]string{
+		"a": "test.infrastructure.cluster.x-k8s.io/a",
+		"b": "test.infrastructure.cluster.x-k8s.io/b",
+	}
+
+	cases := []struct {
+		name               string
+		finalizers         []string
+		expect             func(a, b *mocks.MockReconcilerMockRecorder)
+		expectedFinalizers []string
+	}{
+		{
+			name:       "removes the finalizer of each deleted step",
+			finalizers: []string{"keep", finalizers["a"], finalizers["b"]},
+			expect: func(a, b *mocks.MockReconcilerMockRecorder) {
+				a.Delete(gomockinternal.AContext())
+				b.Delete(gomockinternal.AContext())
+			},
+			expectedFinalizers: []string{"keep"},
+		},
+		{
+			name:       "skips the steps whose finalizer was already removed",
+			finalizers: []string{"keep", finalizers["b"]},
+			expect: func(a, b *mocks.MockReconcilerMockRecorder) {
+				b.Delete(gomockinternal.AContext())
+			},
+			expectedFinalizers: []string{"keep"},
+		},
+		{
+			name:       "keeps the finalizer of a failed step",
+			finalizers: []string{"keep", finalizers["a"], finalizers["b"]},
+			expect: func(a, b *mocks.MockReconcilerMockRecorder) {
+				a.Delete(gomockinternal.AContext())
+				b.Delete(gomockinternal.AContext()).Return(errors.New("boom"))
+			},
+			expectedFinalizers: []string{"keep", finalizers["b"]},
+		},
+		{
+			name:       "runs all the steps of objects predating the finalizers",
+			finalizers: []string{"keep"},
+			expect: func(a, b *mocks.MockReconcilerMockRecorder) {
+				a.Delete(gomockinternal.AContext())
+				b.Delete(gomockinternal.AContext())
+			},
+			expectedFinalizers: []string{"keep"},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			aMock := mocks.NewMockReconciler(mockCtrl)
+			bMock := mocks.NewMockReconciler(mockCtrl)
+
+			tc.expect(aMock.EXPECT(), bMock.EXPECT())
+
+			obj := &infrav1.AzureMachine{ObjectMeta: metav1.ObjectMeta{Finalizers: tc.finalizers}}
+			_ = deleteConcurrently(context.TODO(), trackWithFinalizers(obj, []deletionStep{
+				{name: "a", svc: aMock},
+				{name: "b", svc: bMock, dependsOn: []string{"a"}},
+			}, finalizers))
+			g.Expect(obj.Finalizers).To(Equal(tc.expectedFinalizers))
+		})
+	}
+}
+
+func TestDeleteConcurrentlyTrackedWithConditions(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	aMock := mocks.NewMockReconciler(mockCtrl)
+	bMock := mocks.NewMockReconciler(mockCtrl)
+	cMock := mocks.NewMockReconciler(mockCtrl)
+
+	aMock.EXPECT().Delete(gomockinternal.AContext())
+	bMock.EXPECT().Delete(gomockinternal.AContext()).Return(errors.New("boom"))
+
+	results := map[clusterv1.ConditionType]error{}
+	update := func(condition clusterv1.ConditionType, name string, err error) {
+		results[condition] = err
+	}
+	_ = deleteConcurrently(context.TODO(), trackWithConditions([]deletionStep{
+		{name: "a", svc: aMock},
+		{name: "b", svc: bMock, dependsOn: []string{"a"}},
+		{name: "c", svc: cMock, dependsOn: []string{"b"}},
+	}, map[string]clusterv1.ConditionType{
+		"a": "AReady",
+		"b": "BReady",
+		"c": "CReady",
+	}, update))
+
+	g.Expect(results).To(HaveLen(2))
+	g.Expect(results["AReady"]).NotTo(HaveOccurred())
+	g.Expect(results["BReady"]).To(MatchError("boom"))
+}
+
+func TestAddTrackingFinalizers(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &infrav1.AzureMachine{}
+	addTrackingFinalizers(obj, machineResourceFinalizers)
+	g.Expect(obj.Finalizers).To(HaveLen(len(machineResourceFinalizers)))
+	g.Expect(obj.Finalizers[0]).To(Equal(infrav1.AvailabilitySetFinalizer))
+
+	removeTrackingFinalizers(obj, machineResourceFinalizers)
+	g.Expect(obj.Finalizers).To(BeEmpty())
+}
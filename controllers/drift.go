@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/eventgrid"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// DriftDetector triggers reconciles of the AzureClusters and AzureMachines whose Azure resources are modified or
+// deleted out-of-band, as soon as Azure Resource Manager reports it through Event Grid, instead of waiting for the
+// sync period. It implements the manager.Runnable interface of controller-runtime.
+type DriftDetector struct {
+	client.Client
+	Log                logr.Logger
+	events             <-chan eventgrid.ResourceEvent
+	azureClusterEvents chan event.GenericEvent
+	azureMachineEvents chan event.GenericEvent
+}
+
+// NewDriftDetector returns a new DriftDetector triggering reconciles for the resource events it receives.
+func NewDriftDetector(client client.Client, log logr.Logger, events <-chan eventgrid.ResourceEvent) *DriftDetector {
+	return &DriftDetector{
+		Client:             client,
+		Log:                log,
+		events:             events,
+		azureClusterEvents: make(chan event.GenericEvent),
+		azureMachineEvents: make(chan event.GenericEvent),
+	}
+}
+
+// AzureClusterEvents returns the events triggering reconciles of AzureClusters, or nil if d is nil.
+func (d *DriftDetector) AzureClusterEvents() <-chan event.GenericEvent {
+	if d == nil {
+		return nil
+	}
+	return d.azureClusterEvents
+}
+
+// AzureMachineEvents returns the events triggering reconciles of AzureMachines, or nil if d is nil.
+func (d *DriftDetector) AzureMachineEvents() <-chan event.GenericEvent {
+	if d == nil {
+		return nil
+	}
+	return d.azureMachineEvents
+}
+
+// Start triggers reconciles for the resource events received until ctx is cancelled.
+func (d *DriftDetector) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-d.events:
+			if err := d.handle(ctx, e); err != nil {
+				d.Log.Error(err, "failed to handle resource event", "type", e.EventType, "resource", e.ResourceID)
+			}
+		}
+	}
+}
+
+// handle triggers a reconcile of the AzureClusters owning the resource group of the resource an event is about, and
+// of the AzureMachine owning the resource if it is a virtual machine.
+func (d *DriftDetector) handle(ctx context.Context, e eventgrid.ResourceEvent) error {
+	ctx, span := tele.Tracer().Start(ctx, "controllers.DriftDetector.handle")
+	defer span.End()
+
+	resource, err := azure.ParseResourceID(e.ResourceID)
+	if err != nil {
+		// Events about resource groups or subscriptions themselves aren't about cluster resources.
+		d.Log.V(4).Info("ignoring event about a resource which isn't in a resource group", "resource", e.ResourceID)
+		return nil
+	}
+
+	objects, err := d.objectsFor(ctx, resource, e.ResourceID)
+	if err != nil {
+		return err
+	}
+	for _, object := range objects {
+		d.Log.V(2).Info("Azure resource changed out-of-band, requeueing", "type", e.EventType, "resource", e.ResourceID,
+			"kind", object.kind, "namespace", object.obj.GetNamespace(), "name", object.obj.GetName())
+		select {
+		case object.events <- event.GenericEvent{Object: object.obj}:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return nil
+}
+
+// driftedObject is an object to reconcile because of a resource event.
+type driftedObject struct {
+	kind   string
+	obj    client.Object
+	events chan<- event.GenericEvent
+}
+
+// objectsFor returns the objects to reconcile because of an event about a resource.
+func (d *DriftDetector) objectsFor(ctx context.Context, resource azure.Resource, resourceID string) ([]driftedObject, error) {
+	var objects []driftedObject
+
+	clusters := &infrav1.AzureClusterList{}
+	if err := d.List(ctx, clusters); err != nil {
+		return nil, errors.Wrap(err, "failed to list AzureClusters")
+	}
+	for i := range clusters.Items {
+		if ownsResourceGroup(&clusters.Items[i], resource) {
+			objects = append(objects, driftedObject{kind: "AzureCluster", obj: &clusters.Items[i], events: d.azureClusterEvents})
+		}
+	}
+
+	if !strings.EqualFold(resource.Provider, "Microsoft.Compute") || !strings.EqualFold(resource.ResourceType, "virtualMachines") {
+		return objects, nil
+	}
+	machines := &infrav1.AzureMachineList{}
+	if err := d.List(ctx, machines); err != nil {
+		return nil, errors.Wrap(err, "failed to list AzureMachines")
+	}
+	for i := range machines.Items {
+		providerID := machines.Items[i].Spec.ProviderID
+		if providerID != nil && strings.EqualFold(strings.TrimPrefix(*providerID, "azure://"), resourceID) {
+			objects = append(objects, driftedObject{kind: "AzureMachine", obj: &machines.Items[i], events: d.azureMachineEvents})
+		}
+	}
+	return objects, nil
+}
+
+// ownsResourceGroup returns true if the resource is in the resource group of the cluster or of its network.
+func ownsResourceGroup(cluster *infrav1.AzureCluster, resource azure.Resource) bool {
+	if cluster.Spec.SubscriptionID != "" && !strings.EqualFold(cluster.Spec.SubscriptionID, resource.SubscriptionID) {
+		return false
+	}
+	for _, rg := range []string{cluster.Spec.ResourceGroup, cluster.Spec.NetworkSpec.ResourceGroup, cluster.Spec.NetworkSpec.Vnet.ResourceGroup} {
+		if rg != "" && strings.EqualFold(rg, resource.ResourceGroup) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2/klogr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/eventgrid"
+)
+
+func TestDriftDetectorObjectsFor(t *testing.T) {
+	vmID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm"
+	objects := []runtime.Object{
+		&infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+			Spec:       infrav1.AzureClusterSpec{ResourceGroup: "my-rg", SubscriptionID: "123"},
+		},
+		&infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-network-cluster", Namespace: "default"},
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "other-rg",
+				NetworkSpec:   infrav1.NetworkSpec{Vnet: infrav1.VnetSpec{ResourceGroup: "MY-RG"}},
+			},
+		},
+		&infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-subscription-cluster", Namespace: "default"},
+			Spec:       infrav1.AzureClusterSpec{ResourceGroup: "my-rg", SubscriptionID: "456"},
+		},
+		&infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-vm", Namespace: "default"},
+			Spec:       infrav1.AzureMachineSpec{ProviderID: to.StringPtr("azure:///subscriptions/123/resourcegroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm")},
+		},
+		&infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-vm", Namespace: "default"},
+			Spec:       infrav1.AzureMachineSpec{ProviderID: to.StringPtr("azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/other-vm")},
+		},
+	}
+
+	testcases := []struct {
+		name       string
+		resourceID string
+		expected   []string
+	}{
+		{
+			name:       "virtual machine",
+			resourceID: vmID,
+			expected:   []string{"AzureCluster/my-cluster", "AzureCluster/my-network-cluster", "AzureMachine/my-vm"},
+		},
+		{
+			name:       "network resource",
+			resourceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-nsg",
+			expected:   []string{"AzureCluster/my-cluster", "AzureCluster/my-network-cluster"},
+		},
+		{
+			name:       "resource of another resource group",
+			resourceID: "/subscriptions/123/resourceGroups/unknown-rg/providers/Microsoft.Network/networkSecurityGroups/my-nsg",
+			expected:   nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme, err := newScheme()
+			g.Expect(err).NotTo(HaveOccurred())
+			client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+			d := NewDriftDetector(client, klogr.New(), make(chan eventgrid.ResourceEvent))
+
+			resource, err := azure.ParseResourceID(tc.resourceID)
+			g.Expect(err).NotTo(HaveOccurred())
+			drifted, err := d.objectsFor(context.TODO(), resource, tc.resourceID)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			var names []string
+			for _, o := range drifted {
+				names = append(names, o.kind+"/"+o.obj.GetName())
+			}
+			g.Expect(names).To(ConsistOf(tc.expected))
+		})
+	}
+}
+
+func TestDriftDetectorStart(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := newScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+	client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(&infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+		Spec:       infrav1.AzureClusterSpec{ResourceGroup: "my-rg"},
+	}).Build()
+	events := make(chan eventgrid.ResourceEvent, 2)
+	d := NewDriftDetector(client, klogr.New(), events)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = d.Start(ctx)
+	}()
+
+	events <- eventgrid.ResourceEvent{ResourceID: "/subscriptions/123/resourceGroups/my-rg", EventType: "Microsoft.Resources.ResourceWriteSuccess"}
+	events <- eventgrid.ResourceEvent{ResourceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/routeTables/my-rt", EventType: "Microsoft.Resources.ResourceDeleteSuccess"}
+
+	var received event.GenericEvent
+	g.Eventually(d.AzureClusterEvents()).Should(Receive(&received))
+	g.Expect(received.Object.GetName()).To(Equal("my-cluster"))
+	g.Consistently(d.AzureClusterEvents()).ShouldNot(Receive())
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -40,15 +41,26 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	capiv1exp "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 )
 
+const (
+	// externalCloudProviderSecretName is the name of the secret of the kube-system namespace that cloud-provider-azure
+	// reads its config from when it runs as a deployment.
+	externalCloudProviderSecretName = "azure-cloud-provider"
+	// externalCloudProviderSecretKey is the key of the cloud provider config in the external cloud provider secret.
+	externalCloudProviderSecretKey = "cloud-config"
+)
+
 const (
 	spIdentityWarning = "You are using Service Principal authentication for Cloud Provider Azure which is less secure than Managed Identity. " +
 		"Your Service Principal credentials will be written to a file on the disk of each VM in order to be accessible by Cloud Provider. " +
@@ -63,6 +75,11 @@ type (
 	Options struct {
 		controller.Options
 		Cache *coalescing.ReconcileCache
+		// RequeueInterval is how long to wait before reconciling an object which was successfully reconciled again, to
+		// detect drift sooner than the sync period. Disabled when 0.
+		RequeueInterval time.Duration
+		// DriftEvents are events triggering reconciles of objects whose Azure resources were modified out-of-band.
+		DriftEvents <-chan event.GenericEvent
 	}
 )
 
@@ -228,16 +245,26 @@ func userAssignedIdentityCloudProviderConfig(d azure.ClusterScoper, identityID s
 	controlPlaneConfig.AadClientID = ""
 	controlPlaneConfig.AadClientSecret = ""
 	controlPlaneConfig.UseManagedIdentityExtension = true
-	controlPlaneConfig.UserAssignedIdentityID = identityID
+	if controlPlaneConfig.UserAssignedIdentityID == "" {
+		controlPlaneConfig.UserAssignedIdentityID = identityID
+	}
 	workerConfig.AadClientID = ""
 	workerConfig.AadClientSecret = ""
 	workerConfig.UseManagedIdentityExtension = true
-	workerConfig.UserAssignedIdentityID = identityID
+	if workerConfig.UserAssignedIdentityID == "" {
+		workerConfig.UserAssignedIdentityID = identityID
+	}
 	return controlPlaneConfig, workerConfig
 }
 
 func newCloudProviderConfig(d azure.ClusterScoper) (controlPlaneConfig *CloudProviderConfig, workerConfig *CloudProviderConfig) {
 	subnet := getOneNodeSubnet(d)
+	// The route table lives in the network resource group, which the cloud provider only needs to know about
+	// when it differs from the cluster resource group.
+	var routeTableResourceGroup string
+	if d.NetworkResourceGroup() != d.ResourceGroup() {
+		routeTableResourceGroup = d.NetworkResourceGroup()
+	}
 	return (&CloudProviderConfig{
 			Cloud:                        d.CloudEnvironment(),
 			AadClientID:                  d.ClientID(),
@@ -253,6 +280,7 @@ func newCloudProviderConfig(d azure.ClusterScoper) (controlPlaneConfig *CloudPro
 			VnetResourceGroup:            d.Vnet().ResourceGroup,
 			SubnetName:                   subnet.Name,
 			RouteTableName:               subnet.RouteTable.Name,
+			RouteTableResourceGroup:      routeTableResourceGroup,
 			LoadBalancerSku:              "Standard",
 			MaximumLoadBalancerRuleCount: 250,
 			UseManagedIdentityExtension:  false,
@@ -273,6 +301,7 @@ func newCloudProviderConfig(d azure.ClusterScoper) (controlPlaneConfig *CloudPro
 			VnetResourceGroup:            d.Vnet().ResourceGroup,
 			SubnetName:                   subnet.Name,
 			RouteTableName:               subnet.RouteTable.Name,
+			RouteTableResourceGroup:      routeTableResourceGroup,
 			LoadBalancerSku:              "Standard",
 			MaximumLoadBalancerRuleCount: 250,
 			UseManagedIdentityExtension:  false,
@@ -332,9 +361,55 @@ func (cpc *CloudProviderConfig) overrideFromSpec(d azure.ClusterScoper) *CloudPr
 	}
 
 	cpc.BackOffConfig = toCloudProviderBackOffConfig(d.CloudProviderConfigOverrides().BackOffs)
+
+	if d.CloudProviderConfigOverrides().VMType != "" {
+		cpc.VMType = d.CloudProviderConfigOverrides().VMType
+	}
+	if d.CloudProviderConfigOverrides().UserAssignedIdentityID != "" {
+		cpc.UserAssignedIdentityID = d.CloudProviderConfigOverrides().UserAssignedIdentityID
+	}
+	cpc.ExcludeMasterFromStandardLB = d.CloudProviderConfigOverrides().ExcludeMasterFromStandardLB
 	return cpc
 }
 
+// overrideCloudProviderSecret replaces the generated cloud provider config of the secret with the one in the
+// Secret referenced by the cloud provider config overrides of the cluster, if any.
+func overrideCloudProviderSecret(ctx context.Context, kubeclient client.Client, d azure.ClusterScoper, namespace string, secret *corev1.Secret) error {
+	ctx, span := tele.Tracer().Start(ctx, "controllers.overrideCloudProviderSecret")
+	defer span.End()
+
+	overrides := d.CloudProviderConfigOverrides()
+	if overrides == nil || overrides.SecretRef == nil {
+		return nil
+	}
+
+	userSecret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: namespace, Name: overrides.SecretRef.Name}
+	if err := kubeclient.Get(ctx, key, userSecret); err != nil {
+		return errors.Wrapf(err, "failed to get cloud provider config secret %s", key)
+	}
+
+	data, ok := userSecret.Data["azure.json"]
+	if !ok {
+		return errors.Errorf("cloud provider config secret %s does not have an azure.json key", key)
+	}
+	controlPlaneData, workerNodeData := data, data
+	if v, ok := userSecret.Data["control-plane-azure.json"]; ok {
+		controlPlaneData = v
+	}
+	if v, ok := userSecret.Data["worker-node-azure.json"]; ok {
+		workerNodeData = v
+	}
+
+	secret.Data = map[string][]byte{
+		"control-plane-azure.json": controlPlaneData,
+		"worker-node-azure.json":   workerNodeData,
+		// added for backwards compatibility
+		"azure.json": controlPlaneData,
+	}
+	return nil
+}
+
 // toCloudProviderRateLimitConfig returns converts infrav1.RateLimitConfig to RateLimitConfig that is required with the cloud provider.
 func toCloudProviderRateLimitConfig(source infrav1.RateLimitConfig) *RateLimitConfig {
 	rateLimitConfig := RateLimitConfig{}
@@ -366,11 +441,13 @@ type CloudProviderConfig struct {
 	VnetResourceGroup            string `json:"vnetResourceGroup"`
 	SubnetName                   string `json:"subnetName"`
 	RouteTableName               string `json:"routeTableName"`
+	RouteTableResourceGroup      string `json:"routeTableResourceGroup,omitempty"`
 	LoadBalancerSku              string `json:"loadBalancerSku"`
 	MaximumLoadBalancerRuleCount int    `json:"maximumLoadBalancerRuleCount"`
 	UseManagedIdentityExtension  bool   `json:"useManagedIdentityExtension"`
 	UseInstanceMetadata          bool   `json:"useInstanceMetadata"`
 	UserAssignedIdentityID       string `json:"userAssignedIdentityId,omitempty"`
+	ExcludeMasterFromStandardLB  *bool  `json:"excludeMasterFromStandardLB,omitempty"`
 	CloudProviderRateLimitConfig
 	BackOffConfig
 }
@@ -496,6 +573,52 @@ func reconcileAzureSecret(ctx context.Context, log logr.Logger, kubeclient clien
 	return nil
 }
 
+// reconcileExternalCloudProviderSecret writes the cloud provider config to the secret of the workload cluster that
+// cloud-provider-azure reads its config from when it runs as a deployment.
+func reconcileExternalCloudProviderSecret(ctx context.Context, log logr.Logger, workloadClient client.Client, cloudConfig []byte) error {
+	ctx, span := tele.Tracer().Start(ctx, "controllers.reconcileExternalCloudProviderSecret")
+	defer span.End()
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: externalCloudProviderSecretName}
+	err := workloadClient.Get(ctx, key, secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to fetch existing external cloud provider config")
+	}
+
+	if apierrors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: key.Namespace,
+				Name:      key.Name,
+			},
+			Data: map[string][]byte{
+				externalCloudProviderSecretKey: cloudConfig,
+			},
+		}
+		if err := workloadClient.Create(ctx, secret); err != nil && !apierrors.IsAlreadyExists(err) {
+			return errors.Wrap(err, "failed to create external cloud provider config")
+		}
+		return nil
+	}
+
+	if equality.Semantic.DeepEqual(secret.Data[externalCloudProviderSecretKey], cloudConfig) {
+		log.V(2).Info("returning early from external cloud provider config reconcile, no update needed")
+		return nil
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[externalCloudProviderSecretKey] = cloudConfig
+
+	log.V(2).Info("updating external cloud provider config")
+	if err := workloadClient.Update(ctx, secret); err != nil {
+		return errors.Wrap(err, "failed to update external cloud provider config")
+	}
+	return nil
+}
+
 // GetOwnerMachinePool returns the MachinePool object owning the current resource.
 func GetOwnerMachinePool(ctx context.Context, c client.Client, obj metav1.ObjectMeta) (*capiv1exp.MachinePool, error) {
 	ctx, span := tele.Tracer().Start(ctx, "controllers.GetOwnerMachinePool")
@@ -574,6 +697,11 @@ func ShouldDeleteIndividualResources(ctx context.Context, clusterScope *scope.Cl
 	if clusterScope.Cluster.DeletionTimestamp.IsZero() {
 		return true
 	}
+	// Machines may have resources, such as public IPs, in a separate network resource group which
+	// is not deleted along with the cluster resource group.
+	if clusterScope.NetworkResourceGroup() != clusterScope.ResourceGroup() {
+		return true
+	}
 	grpSvc := groups.New(clusterScope)
 	managed, err := grpSvc.IsGroupManaged(ctx)
 	// Since this is a best effort attempt to speed up delete, we don't fail the delete if we can't get the RG status.
@@ -597,3 +725,60 @@ func GetClusterIdentityFromRef(ctx context.Context, c client.Client, azureCluste
 	}
 	return nil, nil
 }
+
+// EnsureIdentitySecretMoveLabel sets the clusterctl move label on the secret holding the client secret of the
+// AzureClusterIdentity, so that clusterctl move moves the secret along with the identity. The label doesn't make the
+// secret depend on the identity, so deleting the identity doesn't garbage collect the credentials. Secrets outside of the
+// namespace of the identity aren't moved with it and are left untouched.
+func EnsureIdentitySecretMoveLabel(ctx context.Context, c client.Client, identity *infrav1.AzureClusterIdentity) error {
+	ctx, span := tele.Tracer().Start(ctx, "controllers.EnsureIdentitySecretMoveLabel")
+	defer span.End()
+
+	secretRef := identity.Spec.ClientSecret
+	if secretRef.Name == "" || secretRef.Namespace != identity.Namespace {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: secretRef.Namespace, Name: secretRef.Name}
+	if err := c.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			// the missing secret is reported when building the credentials of the identity
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get client secret of AzureClusterIdentity %s/%s", identity.Namespace, identity.Name)
+	}
+
+	if _, ok := secret.Labels[clusterctlv1.ClusterctlMoveLabelName]; ok {
+		return nil
+	}
+
+	before := secret.DeepCopy()
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	secret.Labels[clusterctlv1.ClusterctlMoveLabelName] = ""
+	if err := c.Patch(ctx, secret, client.MergeFrom(before)); err != nil {
+		return errors.Wrapf(err, "failed to set clusterctl move label on client secret of AzureClusterIdentity %s/%s", identity.Namespace, identity.Name)
+	}
+	return nil
+}
+
+// isPaused returns true if the object or the Cluster owning it, if any, is paused.
+func isPaused(ctx context.Context, c client.Client, obj client.Object) (bool, error) {
+	cluster, err := util.GetOwnerCluster(ctx, c, metav1.ObjectMeta{Namespace: obj.GetNamespace(), OwnerReferences: obj.GetOwnerReferences()})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	if cluster == nil {
+		return annotations.HasPausedAnnotation(obj), nil
+	}
+	return annotations.IsPaused(cluster, obj), nil
+}
+
+// IsDeletedForMove returns true if the object is deleted by clusterctl move after having been moved to another management
+// cluster, in which case its Azure resources must not be deleted.
+func IsDeletedForMove(obj metav1.Object) bool {
+	_, ok := obj.GetAnnotations()[infrav1.DeleteForMoveAnnotation]
+	return ok
+}
@@ -43,6 +43,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/internal/test/mock_log"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 )
 
 func TestAzureClusterToAzureMachinesMapper(t *testing.T) {
@@ -146,6 +147,14 @@ func TestGetCloudProviderConfig(t *testing.T) {
 			expectedControlPlaneConfig: backOffCloudConfig,
 			expectedWorkerNodeConfig:   backOffCloudConfig,
 		},
+		"with node overrides": {
+			cluster:                    cluster,
+			azureCluster:               withNodeOverrides(*azureCluster),
+			identityType:               infrav1.VMIdentityUserAssigned,
+			identityID:                 "foobar",
+			expectedControlPlaneConfig: nodeOverridesCloudConfig,
+			expectedWorkerNodeConfig:   nodeOverridesCloudConfig,
+		},
 	}
 
 	os.Setenv(auth.ClientID, "fooClient")
@@ -184,6 +193,111 @@ func TestGetCloudProviderConfig(t *testing.T) {
 	}
 }
 
+func TestOverrideCloudProviderSecret(t *testing.T) {
+	g := NewWithT(t)
+	scheme := setupScheme(g)
+
+	cluster := newCluster("foo")
+	cluster.Default()
+	azureCluster := newAzureCluster("foo", "bar")
+	azureCluster.Default()
+
+	cases := map[string]struct {
+		overrides                  *infrav1.CloudProviderConfigOverrides
+		userSecretData             map[string][]byte
+		expectedControlPlaneConfig string
+		expectedWorkerNodeConfig   string
+		expectedError              string
+	}{
+		"no override secret keeps the generated config": {
+			overrides:                  &infrav1.CloudProviderConfigOverrides{VMType: infrav1.VMTypeStandard},
+			expectedControlPlaneConfig: "generated",
+			expectedWorkerNodeConfig:   "generated",
+		},
+		"override secret with a single config": {
+			overrides: &infrav1.CloudProviderConfigOverrides{
+				SecretRef: &corev1.LocalObjectReference{Name: "custom-azure-json"},
+			},
+			userSecretData: map[string][]byte{
+				"azure.json": []byte("custom"),
+			},
+			expectedControlPlaneConfig: "custom",
+			expectedWorkerNodeConfig:   "custom",
+		},
+		"override secret with a config per role": {
+			overrides: &infrav1.CloudProviderConfigOverrides{
+				SecretRef: &corev1.LocalObjectReference{Name: "custom-azure-json"},
+			},
+			userSecretData: map[string][]byte{
+				"azure.json":             []byte("custom"),
+				"worker-node-azure.json": []byte("custom-worker"),
+			},
+			expectedControlPlaneConfig: "custom",
+			expectedWorkerNodeConfig:   "custom-worker",
+		},
+		"override secret without azure.json": {
+			overrides: &infrav1.CloudProviderConfigOverrides{
+				SecretRef: &corev1.LocalObjectReference{Name: "custom-azure-json"},
+			},
+			userSecretData: map[string][]byte{
+				"worker-node-azure.json": []byte("custom-worker"),
+			},
+			expectedError: "does not have an azure.json key",
+		},
+		"missing override secret": {
+			overrides: &infrav1.CloudProviderConfigOverrides{
+				SecretRef: &corev1.LocalObjectReference{Name: "missing"},
+			},
+			expectedError: "failed to get cloud provider config secret",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			ac := azureCluster.DeepCopy()
+			ac.Spec.CloudProviderConfigOverrides = tc.overrides
+
+			initObjects := []runtime.Object{cluster, ac}
+			if tc.userSecretData != nil {
+				initObjects = append(initObjects, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "custom-azure-json", Namespace: "default"},
+					Data:       tc.userSecretData,
+				})
+			}
+			kubeclient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+			clusterScope, err := scope.NewClusterScope(context.Background(), scope.ClusterScopeParams{
+				AzureClients: scope.AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Cluster:      cluster,
+				AzureCluster: ac,
+				Client:       kubeclient,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			secret := &corev1.Secret{
+				Data: map[string][]byte{
+					"control-plane-azure.json": []byte("generated"),
+					"worker-node-azure.json":   []byte("generated"),
+					"azure.json":               []byte("generated"),
+				},
+			}
+			err = overrideCloudProviderSecret(context.Background(), kubeclient, clusterScope, "default", secret)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(secret.Data["control-plane-azure.json"])).To(Equal(tc.expectedControlPlaneConfig))
+			g.Expect(string(secret.Data["worker-node-azure.json"])).To(Equal(tc.expectedWorkerNodeConfig))
+			g.Expect(string(secret.Data["azure.json"])).To(Equal(tc.expectedControlPlaneConfig))
+		})
+	}
+}
+
 func TestReconcileAzureSecret(t *testing.T) {
 	g := NewWithT(t)
 
@@ -260,6 +374,33 @@ func TestReconcileAzureSecret(t *testing.T) {
 	}
 }
 
+func TestReconcileExternalCloudProviderSecret(t *testing.T) {
+	g := NewWithT(t)
+
+	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+	testLog := ctrl.Log.WithName("reconcileExternalCloudProviderSecret")
+
+	scheme := setupScheme(g)
+	workloadClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	key := types.NamespacedName{Namespace: "kube-system", Name: "azure-cloud-provider"}
+
+	g.Expect(reconcileExternalCloudProviderSecret(context.Background(), testLog, workloadClient, []byte("config"))).To(Succeed())
+	found := &corev1.Secret{}
+	g.Expect(workloadClient.Get(context.Background(), key, found)).To(Succeed())
+	g.Expect(found.Data).To(Equal(map[string][]byte{"cloud-config": []byte("config")}))
+
+	// Keys added by the user are kept when the config changes.
+	found.Data["extra"] = []byte("extra")
+	g.Expect(workloadClient.Update(context.Background(), found)).To(Succeed())
+
+	g.Expect(reconcileExternalCloudProviderSecret(context.Background(), testLog, workloadClient, []byte("new-config"))).To(Succeed())
+	g.Expect(workloadClient.Get(context.Background(), key, found)).To(Succeed())
+	g.Expect(found.Data).To(Equal(map[string][]byte{
+		"cloud-config": []byte("new-config"),
+		"extra":        []byte("extra"),
+	}))
+}
+
 func setupScheme(g *WithT) *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	g.Expect(clientgoscheme.AddToScheme(scheme)).ToNot(HaveOccurred())
@@ -351,6 +492,16 @@ func withbackOffConfig(ac infrav1.AzureCluster) *infrav1.AzureCluster {
 	return &ac
 }
 
+func withNodeOverrides(ac infrav1.AzureCluster) *infrav1.AzureCluster {
+	excludeMasterFromStandardLB := true
+	ac.Spec.CloudProviderConfigOverrides = &infrav1.CloudProviderConfigOverrides{
+		VMType:                      infrav1.VMTypeStandard,
+		UserAssignedIdentityID:      "cloud-provider-identity",
+		ExcludeMasterFromStandardLB: &excludeMasterFromStandardLB,
+	}
+	return &ac
+}
+
 func newAzureClusterWithCustomVnet(name, location string) *infrav1.AzureCluster {
 	return &infrav1.AzureCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -612,5 +763,159 @@ const (
     "cloudProviderBackoffExponent": 1.2000000000000002,
     "cloudProviderBackoffDuration": 60,
     "cloudProviderBackoffJitter": 1.2000000000000002
+}`
+	nodeOverridesCloudConfig = `{
+    "cloud": "AzurePublicCloud",
+    "tenantId": "fooTenant",
+    "subscriptionId": "baz",
+    "resourceGroup": "bar",
+    "securityGroupName": "foo-node-nsg",
+    "securityGroupResourceGroup": "bar",
+    "location": "bar",
+    "vmType": "standard",
+    "vnetName": "foo-vnet",
+    "vnetResourceGroup": "bar",
+    "subnetName": "foo-node-subnet",
+    "routeTableName": "foo-node-routetable",
+    "loadBalancerSku": "Standard",
+    "maximumLoadBalancerRuleCount": 250,
+    "useManagedIdentityExtension": true,
+    "useInstanceMetadata": true,
+    "userAssignedIdentityId": "cloud-provider-identity",
+    "excludeMasterFromStandardLB": true
 }`
 )
+
+func TestEnsureIdentitySecretMoveLabel(t *testing.T) {
+	cases := map[string]struct {
+		secretNamespace string
+		existingLabels  map[string]string
+		expectedLabels  map[string]string
+	}{
+		"secret gets the clusterctl move label": {
+			secretNamespace: "default",
+			expectedLabels:  map[string]string{clusterctlv1.ClusterctlMoveLabelName: ""},
+		},
+		"secret already labeled is left as is": {
+			secretNamespace: "default",
+			existingLabels:  map[string]string{clusterctlv1.ClusterctlMoveLabelName: "true"},
+			expectedLabels:  map[string]string{clusterctlv1.ClusterctlMoveLabelName: "true"},
+		},
+		"other labels of the secret are preserved": {
+			secretNamespace: "default",
+			existingLabels:  map[string]string{"app": "other"},
+			expectedLabels:  map[string]string{"app": "other", clusterctlv1.ClusterctlMoveLabelName: ""},
+		},
+		"secret in another namespace than the identity is left as is": {
+			secretNamespace: "other",
+			expectedLabels:  nil,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := setupScheme(g)
+
+			identity := &infrav1.AzureClusterIdentity{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-identity", Namespace: "default", UID: "identity-uid"},
+				Spec: infrav1.AzureClusterIdentitySpec{
+					ClientSecret: corev1.SecretReference{Name: "my-secret", Namespace: tc.secretNamespace},
+				},
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-secret", Namespace: tc.secretNamespace, Labels: tc.existingLabels},
+			}
+			kubeclient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(identity, secret).Build()
+
+			g.Expect(EnsureIdentitySecretMoveLabel(context.Background(), kubeclient, identity)).To(Succeed())
+
+			got := &corev1.Secret{}
+			g.Expect(kubeclient.Get(context.Background(), types.NamespacedName{Name: "my-secret", Namespace: tc.secretNamespace}, got)).To(Succeed())
+			g.Expect(got.Labels).To(Equal(tc.expectedLabels))
+			g.Expect(got.OwnerReferences).To(BeEmpty())
+		})
+	}
+}
+
+func TestEnsureIdentitySecretMoveLabelMissingSecret(t *testing.T) {
+	g := NewWithT(t)
+	scheme := setupScheme(g)
+
+	identity := &infrav1.AzureClusterIdentity{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-identity", Namespace: "default"},
+		Spec: infrav1.AzureClusterIdentitySpec{
+			ClientSecret: corev1.SecretReference{Name: "my-secret", Namespace: "default"},
+		},
+	}
+	kubeclient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(identity).Build()
+
+	g.Expect(EnsureIdentitySecretMoveLabel(context.Background(), kubeclient, identity)).To(Succeed())
+}
+
+func TestIsDeletedForMove(t *testing.T) {
+	g := NewWithT(t)
+
+	azureCluster := newAzureCluster("foo", "bar")
+	g.Expect(IsDeletedForMove(azureCluster)).To(BeFalse())
+
+	azureCluster.Annotations = map[string]string{infrav1.DeleteForMoveAnnotation: ""}
+	g.Expect(IsDeletedForMove(azureCluster)).To(BeTrue())
+}
+
+func TestIsPaused(t *testing.T) {
+	cases := map[string]struct {
+		clusterPaused    bool
+		objectPaused     bool
+		withOwnerCluster bool
+		expected         bool
+	}{
+		"neither the object nor its Cluster are paused": {
+			withOwnerCluster: true,
+			expected:         false,
+		},
+		"owner Cluster is paused": {
+			clusterPaused:    true,
+			withOwnerCluster: true,
+			expected:         true,
+		},
+		"object is annotated paused": {
+			objectPaused:     true,
+			withOwnerCluster: true,
+			expected:         true,
+		},
+		"object without owner Cluster is annotated paused": {
+			objectPaused: true,
+			expected:     true,
+		},
+		"object without owner Cluster is not paused": {
+			expected: false,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := setupScheme(g)
+
+			cluster := newCluster("foo")
+			cluster.Spec.Paused = tc.clusterPaused
+			azureCluster := newAzureCluster("foo", "bar")
+			if tc.withOwnerCluster {
+				azureCluster.OwnerReferences = []metav1.OwnerReference{
+					{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: cluster.Name},
+				}
+			}
+			if tc.objectPaused {
+				azureCluster.Annotations = map[string]string{clusterv1.PausedAnnotation: ""}
+			}
+			kubeclient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cluster, azureCluster).Build()
+
+			paused, err := isPaused(context.Background(), kubeclient, azureCluster)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(paused).To(Equal(tc.expected))
+		})
+	}
+}
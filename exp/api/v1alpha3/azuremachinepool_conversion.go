@@ -47,6 +47,8 @@ func (src *AzureMachinePool) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	}
 
 	dst.Spec.Template.SubnetName = restored.Spec.Template.SubnetName
+	dst.Spec.Template.OSDisk.BootstrapFormat = restored.Spec.Template.OSDisk.BootstrapFormat
+	dst.Spec.Template.OSDisk.Distribution = restored.Spec.Template.OSDisk.Distribution
 
 	dst.Spec.Strategy.Type = restored.Spec.Strategy.Type
 	if restored.Spec.Strategy.RollingUpdate != nil {
@@ -58,6 +60,8 @@ func (src *AzureMachinePool) ConvertTo(dstRaw conversion.Hub) error { // nolint
 		dst.Spec.Strategy.RollingUpdate.DeletePolicy = restored.Spec.Strategy.RollingUpdate.DeletePolicy
 	}
 
+	dst.Spec.AzureMonitor = restored.Spec.AzureMonitor
+
 	if restored.Spec.NodeDrainTimeout != nil {
 		dst.Spec.NodeDrainTimeout = restored.Spec.NodeDrainTimeout
 	}
@@ -66,6 +70,10 @@ func (src *AzureMachinePool) ConvertTo(dstRaw conversion.Hub) error { // nolint
 		dst.Status.Image = restored.Status.Image
 	}
 
+	if restored.Status.LongRunningOperationState != nil && dst.Status.LongRunningOperationState != nil {
+		dst.Status.LongRunningOperationState.ServiceName = restored.Status.LongRunningOperationState.ServiceName
+	}
+
 	if restored.Spec.Template.Image != nil && restored.Spec.Template.Image.SharedGallery != nil {
 		dst.Spec.Template.Image.SharedGallery.Offer = restored.Spec.Template.Image.SharedGallery.Offer
 		dst.Spec.Template.Image.SharedGallery.Publisher = restored.Spec.Template.Image.SharedGallery.Publisher
@@ -134,6 +134,11 @@ type (
 		// +optional
 		RoleAssignmentName string `json:"roleAssignmentName,omitempty"`
 
+		// AzureMonitor installs an Azure Monitor agent on the instances of the scale set, sending their metrics and
+		// logs to Azure Monitor.
+		// +optional
+		AzureMonitor *infrav1.AzureMonitor `json:"azureMonitor,omitempty"`
+
 		// The deployment strategy to use to replace existing AzureMachinePoolMachines with new ones.
 		// +optional
 		// +kubebuilder:default={type: "RollingUpdate", rollingUpdate: {maxSurge: 1, maxUnavailable: 0, deletePolicy: Oldest}}
@@ -58,6 +58,7 @@ func (amp *AzureMachinePool) Default() {
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha4-azuremachinepool,mutating=false,failurePolicy=fail,groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepools,versions=v1alpha4,name=validation.azuremachinepool.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha4-azuremachinepool-lookup,mutating=false,failurePolicy=ignore,groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepools,versions=v1alpha4,name=lookupvalidation.azuremachinepool.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
 var _ webhook.Validator = &AzureMachinePool{}
 
@@ -87,7 +88,10 @@ func (amp *AzureMachinePool) Validate(old runtime.Object) error {
 		amp.ValidateSSHKey,
 		amp.ValidateUserAssignedIdentity,
 		amp.ValidateStrategy(),
+		amp.ValidateSpotVMOptions,
 		amp.ValidateSystemAssignedIdentity(old),
+		amp.ValidateAdditionalTags(old),
+		amp.ValidateAzureMonitor,
 	}
 
 	var errs []error
@@ -154,15 +158,62 @@ func (amp *AzureMachinePool) ValidateUserAssignedIdentity() error {
 	return nil
 }
 
+// ValidateAzureMonitor validates the Azure Monitor agent of the scale set.
+func (amp *AzureMachinePool) ValidateAzureMonitor() error {
+	if errs := infrav1.ValidateAzureMonitor(amp.Spec.AzureMonitor, amp.Spec.Identity, amp.Spec.UserAssignedIdentities, field.NewPath("azureMonitor")); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateAdditionalTags validates the additional tags, on update only if they changed.
+func (amp *AzureMachinePool) ValidateAdditionalTags(old runtime.Object) func() error {
+	return func() error {
+		var oldTags infrav1.Tags
+		if old != nil {
+			oldMachinePool, ok := old.(*AzureMachinePool)
+			if !ok {
+				return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+					"AzureMachinePool", reflect.TypeOf(old))
+			}
+			oldTags = oldMachinePool.Spec.AdditionalTags
+		}
+
+		if errs := infrav1.ValidateTagsUpdate(amp.Spec.AdditionalTags, oldTags, field.NewPath("spec", "additionalTags")); len(errs) > 0 {
+			return kerrors.NewAggregate(errs.ToAggregate().Errors())
+		}
+
+		return nil
+	}
+}
+
+// ValidateSpotVMOptions validates the Spot VM options of the template.
+func (amp *AzureMachinePool) ValidateSpotVMOptions() error {
+	if errs := infrav1.ValidateSpotVMOptions(amp.Spec.Template.SpotVMOptions, field.NewPath("template", "spotVMOptions")); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
 // ValidateStrategy validates the strategy.
 func (amp *AzureMachinePool) ValidateStrategy() func() error {
 	return func() error {
 		if amp.Spec.Strategy.Type == RollingUpdateAzureMachinePoolDeploymentStrategyType && amp.Spec.Strategy.RollingUpdate != nil {
 			rollingUpdateStrategy := amp.Spec.Strategy.RollingUpdate
-			maxSurge := rollingUpdateStrategy.MaxSurge
-			maxUnavailable := rollingUpdateStrategy.MaxUnavailable
-			if maxSurge.Type == intstr.Int && maxSurge.IntVal == 0 &&
-				maxUnavailable.Type == intstr.Int && maxUnavailable.IntVal == 0 {
+			fldPath := field.NewPath("strategy", "rollingUpdate")
+
+			var allErrs field.ErrorList
+			maxSurge, errs := validateIntOrPercent(rollingUpdateStrategy.MaxSurge, fldPath.Child("maxSurge"), false)
+			allErrs = append(allErrs, errs...)
+			maxUnavailable, errs := validateIntOrPercent(rollingUpdateStrategy.MaxUnavailable, fldPath.Child("maxUnavailable"), true)
+			allErrs = append(allErrs, errs...)
+			if len(allErrs) > 0 {
+				return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+			}
+
+			if maxSurge == 0 && maxUnavailable == 0 {
 				return errors.New("rolling update strategy MaxUnavailable must not be 0 if MaxSurge is 0")
 			}
 		}
@@ -171,6 +222,28 @@ func (amp *AzureMachinePool) ValidateStrategy() func() error {
 	}
 }
 
+// validateIntOrPercent validates a number or percentage of machines of a rolling update, and returns its value. Unset
+// values are 0. Percentages can be more than 100% unless isMaxUnavailable is set, since more than all the machines of a
+// pool can't be unavailable.
+func validateIntOrPercent(value *intstr.IntOrString, fldPath *field.Path, isMaxUnavailable bool) (int, field.ErrorList) {
+	if value == nil {
+		return 0, nil
+	}
+
+	// Scaling the value to 100 machines leaves numbers unchanged and turns percentages into numbers.
+	scaled, err := intstr.GetScaledValueFromIntOrPercent(value, 100, true)
+	if err != nil {
+		return 0, field.ErrorList{field.Invalid(fldPath, value.String(), "must be an integer or a percentage, e.g. 1 or 10%")}
+	}
+	if scaled < 0 {
+		return 0, field.ErrorList{field.Invalid(fldPath, value.String(), "must not be negative")}
+	}
+	if isMaxUnavailable && value.Type == intstr.String && scaled > 100 {
+		return 0, field.ErrorList{field.Invalid(fldPath, value.String(), "must not be greater than 100%")}
+	}
+	return scaled, nil
+}
+
 // ValidateSystemAssignedIdentity validates system-assigned identity role.
 func (amp *AzureMachinePool) ValidateSystemAssignedIdentity(old runtime.Object) func() error {
 	return func() error {
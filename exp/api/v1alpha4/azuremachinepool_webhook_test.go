@@ -22,6 +22,7 @@ import (
 	"encoding/base64"
 	"testing"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/uuid"
 
@@ -42,8 +43,14 @@ func TestAzureMachinePool_ValidateCreate(t *testing.T) {
 	g := NewWithT(t)
 
 	var (
-		zero = intstr.FromInt(0)
-		one  = intstr.FromInt(1)
+		zero              = intstr.FromInt(0)
+		one               = intstr.FromInt(1)
+		minusOne          = intstr.FromInt(-1)
+		zeroPercent       = intstr.FromString("0%")
+		tenPercent        = intstr.FromString("10%")
+		hundredPercent    = intstr.FromString("100%")
+		twoHundredPercent = intstr.FromString("200%")
+		notAPercentage    = intstr.FromString("ten")
 	)
 
 	tests := []struct {
@@ -138,6 +145,95 @@ func TestAzureMachinePool_ValidateCreate(t *testing.T) {
 			}),
 			wantErr: false,
 		},
+		{
+			name: "azuremachinepool with percentage MaxSurge and MaxUnavailable rolling upgrade configuration",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge:       &twoHundredPercent,
+					MaxUnavailable: &hundredPercent,
+				},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with 0% MaxSurge and 0 MaxUnavailable rolling upgrade configuration",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge:       &zeroPercent,
+					MaxUnavailable: &zero,
+				},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with negative MaxSurge rolling upgrade configuration",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge:       &minusOne,
+					MaxUnavailable: &one,
+				},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with MaxUnavailable over 100% rolling upgrade configuration",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge:       &tenPercent,
+					MaxUnavailable: &twoHundredPercent,
+				},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with malformed MaxSurge rolling upgrade configuration",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge: &notAPercentage,
+				},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with only MaxSurge rolling upgrade configuration",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge: &one,
+				},
+			}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with spot VM max price",
+			amp:     createMachinePoolWithSpotVMMaxPrice("0.05"),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with on-demand spot VM max price",
+			amp:     createMachinePoolWithSpotVMMaxPrice("-1"),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with invalid spot VM max price",
+			amp:     createMachinePoolWithSpotVMMaxPrice("0"),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with AzureMonitorAgent and a system-assigned identity",
+			amp:     createMachinePoolWithAzureMonitor(infrav1.VMIdentitySystemAssigned, &infrav1.AzureMonitor{Agent: infrav1.MonitoringAgentAzureMonitor}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with AzureMonitorAgent and no identity",
+			amp:     createMachinePoolWithAzureMonitor(infrav1.VMIdentityNone, &infrav1.AzureMonitor{Agent: infrav1.MonitoringAgentAzureMonitor}),
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -364,3 +460,29 @@ func createMachinePoolWithStrategy(strategy AzureMachinePoolDeploymentStrategy)
 		},
 	}
 }
+
+func createMachinePoolWithAzureMonitor(identity infrav1.VMIdentity, monitor *infrav1.AzureMonitor) *AzureMachinePool {
+	amp := &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Identity:     identity,
+			AzureMonitor: monitor,
+		},
+	}
+	if identity == infrav1.VMIdentitySystemAssigned {
+		amp.Spec.RoleAssignmentName = string(uuid.NewUUID())
+	}
+	return amp
+}
+
+func createMachinePoolWithSpotVMMaxPrice(maxPrice string) *AzureMachinePool {
+	price := resource.MustParse(maxPrice)
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				SpotVMOptions: &infrav1.SpotVMOptions{
+					MaxPrice: &price,
+				},
+			},
+		},
+	}
+}
@@ -66,6 +66,10 @@ func (r *AzureManagedControlPlane) Default() {
 		r.Spec.NetworkPolicy = &NetworkPolicy
 	}
 
+	if r.Spec.AzureEnvironment == "" {
+		r.Spec.AzureEnvironment = infrav1.DefaultAzureCloud
+	}
+
 	if r.Spec.Version != "" && !strings.HasPrefix(r.Spec.Version, "v") {
 		normalizedVersion := "v" + r.Spec.Version
 		r.Spec.Version = normalizedVersion
@@ -89,7 +93,7 @@ var _ webhook.Validator = &AzureManagedControlPlane{}
 func (r *AzureManagedControlPlane) ValidateCreate() error {
 	azuremanagedcontrolplanelog.Info("validate create", "name", r.Name)
 
-	return r.Validate()
+	return r.Validate(nil)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -130,6 +134,16 @@ func (r *AzureManagedControlPlane) ValidateUpdate(oldRaw runtime.Object) error {
 				"field is immutable"))
 	}
 
+	// Control planes created before the field was added are defaulted to the public cloud they were created in.
+	if r.Spec.AzureEnvironment != old.Spec.AzureEnvironment &&
+		!(old.Spec.AzureEnvironment == "" && r.Spec.AzureEnvironment == infrav1.DefaultAzureCloud) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "AzureEnvironment"),
+				r.Spec.AzureEnvironment,
+				"field is immutable"))
+	}
+
 	if old.Spec.SSHPublicKey != "" {
 		// Prevent SSH key modification if it was already set to some value
 		if r.Spec.SSHPublicKey != old.Spec.SSHPublicKey {
@@ -243,7 +257,7 @@ func (r *AzureManagedControlPlane) ValidateUpdate(oldRaw runtime.Object) error {
 	}
 
 	if len(allErrs) == 0 {
-		return r.Validate()
+		return r.Validate(old)
 	}
 
 	return apierrors.NewInvalid(GroupVersion.WithKind("AzureManagedControlPlane").GroupKind(), r.Name, allErrs)
@@ -256,12 +270,14 @@ func (r *AzureManagedControlPlane) ValidateDelete() error {
 	return nil
 }
 
-// Validate the Azure Machine Pool and return an aggregate error.
-func (r *AzureManagedControlPlane) Validate() error {
+// Validate the Azure Machine Pool and return an aggregate error. old is the managed control plane being updated, if any.
+func (r *AzureManagedControlPlane) Validate(old *AzureManagedControlPlane) error {
 	validators := []func() error{
 		r.validateVersion,
 		r.validateDNSServiceIP,
 		r.validateSSHKey,
+		r.validateAdditionalTags(old),
+		r.validateAzureEnvironment,
 	}
 
 	var errs []error
@@ -306,3 +322,30 @@ func (r *AzureManagedControlPlane) validateSSHKey() error {
 
 	return nil
 }
+
+// validateAdditionalTags validates the additional tags, on update only if they changed.
+func (r *AzureManagedControlPlane) validateAdditionalTags(old *AzureManagedControlPlane) func() error {
+	return func() error {
+		var oldTags infrav1.Tags
+		if old != nil {
+			oldTags = old.Spec.AdditionalTags
+		}
+		if errs := infrav1.ValidateTagsUpdate(r.Spec.AdditionalTags, oldTags, field.NewPath("spec", "additionalTags")); len(errs) > 0 {
+			return kerrors.NewAggregate(errs.ToAggregate().Errors())
+		}
+
+		return nil
+	}
+}
+
+// validateAzureEnvironment validates the name of the Azure cloud. AKS is not available in Azure Stack Hub.
+func (r *AzureManagedControlPlane) validateAzureEnvironment() error {
+	if strings.EqualFold(r.Spec.AzureEnvironment, infrav1.AzureStackCloud) {
+		return errors.New("managed clusters are not supported in Azure Stack Hub")
+	}
+	if errs := infrav1.ValidateAzureEnvironment(r.Spec.AzureEnvironment, field.NewPath("azureEnvironment")); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
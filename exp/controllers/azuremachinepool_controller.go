@@ -33,6 +33,7 @@ import (
 	capiv1exp "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -59,6 +60,7 @@ type (
 		Recorder                      record.EventRecorder
 		ReconcileTimeout              time.Duration
 		WatchFilterValue              string
+		requeueInterval               time.Duration
 		createAzureMachinePoolService azureMachinePoolServiceCreator
 	}
 
@@ -92,6 +94,7 @@ func (ampr *AzureMachinePoolReconciler) SetupWithManager(ctx context.Context, mg
 	defer span.End()
 
 	log := ampr.Log.WithValues("controller", "AzureMachinePool")
+	ampr.requeueInterval = options.RequeueInterval
 	var r reconcile.Reconciler = ampr
 	if options.Cache != nil {
 		r = coalescing.NewReconciler(ampr, options.Cache, log)
@@ -220,6 +223,9 @@ func (ampr *AzureMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.
 
 	logger = logger.WithValues("AzureCluster", azureCluster.Name)
 
+	// Use the API versions supported by the cloud of the cluster, e.g. an Azure Stack Hub.
+	ctx = azure.WithAPIProfile(ctx, azureCluster.Spec.APIProfile)
+
 	// Create the cluster scope
 	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
 		Client:       ampr.Client,
@@ -294,6 +300,14 @@ func (ampr *AzureMachinePoolReconciler) reconcileNormal(ctx context.Context, mac
 	}
 
 	if err := ams.Reconcile(ctx); err != nil {
+		if azure.IsQuotaExceededError(err) {
+			// The quotas were checked before adding instances to the scale set, retry once they might have changed.
+			ampr.Recorder.Eventf(machinePoolScope.AzureMachinePool, corev1.EventTypeWarning, infrav1.QuotaExceededReason, azure.ErrorMessage(err))
+			machinePoolScope.Info("quota exceeded, waiting before adding instances to the scale set", "name", machinePoolScope.Name(), "reason", err.Error())
+			conditions.MarkFalse(machinePoolScope.AzureMachinePool, infrav1.ScaleSetRunningCondition, infrav1.QuotaExceededReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return reconcile.Result{RequeueAfter: azure.DefaultQuotaExceededBackoff}, nil
+		}
+
 		// Handle transient and terminal errors
 		var reconcileError azure.ReconcileError
 		if errors.As(err, &reconcileError) {
@@ -334,7 +348,7 @@ func (ampr *AzureMachinePoolReconciler) reconcileNormal(ctx context.Context, mac
 		}, nil
 	}
 
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: ampr.requeueInterval}, nil
 }
 
 func (ampr *AzureMachinePoolReconciler) reconcileDelete(ctx context.Context, machinePoolScope *scope.MachinePoolScope, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
@@ -343,7 +357,9 @@ func (ampr *AzureMachinePoolReconciler) reconcileDelete(ctx context.Context, mac
 
 	machinePoolScope.V(2).Info("handling deleted AzureMachinePool")
 
-	if infracontroller.ShouldDeleteIndividualResources(ctx, clusterScope) {
+	if infracontroller.IsDeletedForMove(machinePoolScope.AzureMachinePool) {
+		machinePoolScope.V(2).Info("AzureMachinePool was moved to another management cluster, skipping the deletion of its Azure resources")
+	} else if infracontroller.ShouldDeleteIndividualResources(ctx, clusterScope) {
 		amps, err := ampr.createAzureMachinePoolService(machinePoolScope)
 		if err != nil {
 			return reconcile.Result{}, errors.Wrap(err, "failed creating a new AzureMachinePoolService")
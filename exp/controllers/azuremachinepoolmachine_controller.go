@@ -30,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
@@ -98,7 +99,7 @@ func (ampmr *AzureMachinePoolMachineController) SetupWithManager(ctx context.Con
 	c, err := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(options.Options).
 		For(&infrav1exp.AzureMachinePoolMachine{}).
-		WithEventFilter(predicates.ResourceNotPaused(log)). // don't queue reconcile if resource is paused
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(log, ampmr.WatchFilterValue)). // don't queue reconcile if resource is paused or filtered out
 		Build(r)
 	if err != nil {
 		return errors.Wrapf(err, "error creating controller")
@@ -113,6 +114,20 @@ func (ampmr *AzureMachinePoolMachineController) SetupWithManager(ctx context.Con
 		return errors.Wrapf(err, "failed adding a watch for AzureMachinePool model changes")
 	}
 
+	azureMachinePoolMachineMapper, err := util.ClusterToObjectsMapper(ampmr.Client, &infrav1exp.AzureMachinePoolMachineList{}, mgr.GetScheme())
+	if err != nil {
+		return errors.Wrap(err, "failed to create mapper for Cluster to AzureMachinePoolMachines")
+	}
+
+	// Add a watch on clusterv1.Cluster object for unpause notifications.
+	if err := c.Watch(
+		&source.Kind{Type: &clusterv1.Cluster{}},
+		handler.EnqueueRequestsFromMapFunc(azureMachinePoolMachineMapper),
+		predicates.ClusterUnpaused(log),
+	); err != nil {
+		return errors.Wrap(err, "failed adding a watch for unpaused clusters")
+	}
+
 	return nil
 }
 
@@ -201,6 +216,9 @@ func (ampmr *AzureMachinePoolMachineController) Reconcile(ctx context.Context, r
 
 	logger = logger.WithValues("AzureCluster", azureCluster.Name)
 
+	// Use the API versions supported by the cloud of the cluster, e.g. an Azure Stack Hub.
+	ctx = azure.WithAPIProfile(ctx, azureCluster.Spec.APIProfile)
+
 	// Create the cluster scope
 	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
 		Client:       ampmr.Client,
@@ -309,6 +327,12 @@ func (ampmr *AzureMachinePoolMachineController) reconcileDelete(ctx context.Cont
 
 	machineScope.Info("Handling deleted AzureMachinePoolMachine")
 
+	if infracontroller.IsDeletedForMove(machineScope.AzureMachinePoolMachine) {
+		machineScope.Info("AzureMachinePoolMachine was moved to another management cluster, skipping the deletion of its Azure resources")
+		controllerutil.RemoveFinalizer(machineScope.AzureMachinePoolMachine, infrav1exp.AzureMachinePoolMachineFinalizer)
+		return reconcile.Result{}, nil
+	}
+
 	if machineScope.AzureMachinePool == nil || !machineScope.AzureMachinePool.ObjectMeta.DeletionTimestamp.IsZero() {
 		// deleting the entire VMSS, so just remove finalizer and VMSS delete remove the underlying infrastructure.
 		controllerutil.RemoveFinalizer(machineScope.AzureMachinePoolMachine, infrav1exp.AzureMachinePoolMachineFinalizer)
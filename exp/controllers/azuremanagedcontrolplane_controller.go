@@ -42,6 +42,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	infracontroller "sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha4"
@@ -150,12 +151,16 @@ func (r *AzureManagedControlPlaneReconciler) Reconcile(ctx context.Context, req
 
 	// check if the control plane's namespace is allowed for this identity and update owner references for the identity.
 	if azureControlPlane.Spec.IdentityRef != nil {
-		identity, err := infracontroller.GetClusterIdentityFromRef(ctx, r.Client, azureControlPlane.Namespace, azureControlPlane.Spec.IdentityRef)
+		identity, err := infracontroller.ValidateClusterIdentity(ctx, r.Client, azureControlPlane.Namespace, azureControlPlane.Spec.IdentityRef)
 		if err != nil {
+			var identityErr *infracontroller.IdentityError
+			if errors.As(err, &identityErr) {
+				r.Recorder.Eventf(azureControlPlane, corev1.EventTypeWarning, identityErr.Reason, identityErr.Error())
+			}
 			return reconcile.Result{}, err
 		}
-		if !scope.IsClusterNamespaceAllowed(ctx, r.Client, identity.Spec.AllowedNamespaces, azureControlPlane.Namespace) {
-			return reconcile.Result{}, errors.New("AzureClusterIdentity list of allowed namespaces doesn't include current azure managed control plane namespace")
+		if err := infracontroller.EnsureIdentitySecretMoveLabel(ctx, r.Client, identity); err != nil {
+			return reconcile.Result{}, err
 		}
 	} else {
 		warningMessage := ("You're using deprecated functionality: ")
@@ -206,6 +211,12 @@ func (r *AzureManagedControlPlaneReconciler) reconcileNormal(ctx context.Context
 	}
 
 	if err := newAzureManagedControlPlaneReconciler(scope).Reconcile(ctx); err != nil {
+		// Long-running operations on Azure resources are not waited for: requeue until they are done.
+		var reconcileError azure.ReconcileError
+		if errors.As(err, &reconcileError) && reconcileError.IsTransient() && azure.IsOperationNotDoneError(err) {
+			scope.Logger.V(2).Info("AzureManagedControlPlane reconcile not done, requeueing", "reason", err.Error())
+			return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}, nil
+		}
 		return reconcile.Result{}, errors.Wrapf(err, "error creating AzureManagedControlPlane %s/%s", scope.ControlPlane.Namespace, scope.ControlPlane.Name)
 	}
 
@@ -222,7 +233,18 @@ func (r *AzureManagedControlPlaneReconciler) reconcileDelete(ctx context.Context
 
 	scope.Logger.Info("Reconciling AzureManagedControlPlane delete")
 
+	if infracontroller.IsDeletedForMove(scope.ControlPlane) {
+		scope.Logger.Info("AzureManagedControlPlane was moved to another management cluster, skipping the deletion of its Azure resources")
+		controllerutil.RemoveFinalizer(scope.ControlPlane, infrav1.ClusterFinalizer)
+		return reconcile.Result{}, nil
+	}
+
 	if err := newAzureManagedControlPlaneReconciler(scope).Delete(ctx); err != nil {
+		var reconcileError azure.ReconcileError
+		if errors.As(err, &reconcileError) && reconcileError.IsTransient() && azure.IsOperationNotDoneError(err) {
+			scope.Logger.V(2).Info("AzureManagedControlPlane delete not done, requeueing", "reason", err.Error())
+			return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}, nil
+		}
 		return reconcile.Result{}, errors.Wrapf(err, "error deleting AzureManagedControlPlane %s/%s", scope.ControlPlane.Namespace, scope.ControlPlane.Name)
 	}
 
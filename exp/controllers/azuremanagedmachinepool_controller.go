@@ -255,7 +255,11 @@ func (r *AzureManagedMachinePoolReconciler) reconcileDelete(ctx context.Context,
 
 	scope.Logger.Info("Reconciling AzureManagedMachinePool delete")
 
-	if !scope.Cluster.DeletionTimestamp.IsZero() {
+	if infracontroller.IsDeletedForMove(scope.InfraMachinePool) {
+		// Machine pool was moved to another management cluster, which manages it from now on.
+		scope.Logger.Info("AzureManagedMachinePool was moved to another management cluster, skipping the deletion of its Azure resources")
+		controllerutil.RemoveFinalizer(scope.InfraMachinePool, infrav1.ClusterFinalizer)
+	} else if !scope.Cluster.DeletionTimestamp.IsZero() {
 		// Cluster was deleted, skip machine pool deletion and let AKS delete the whole cluster.
 		// So, remove the finalizer.
 		controllerutil.RemoveFinalizer(scope.InfraMachinePool, infrav1.ClusterFinalizer)
@@ -47,8 +47,8 @@ type (
 
 	// NodeLister is a service interface for returning generic lists.
 	NodeLister interface {
-		ListInstances(context.Context, string, string) ([]compute.VirtualMachineScaleSetVM, error)
-		List(context.Context, string) ([]compute.VirtualMachineScaleSet, error)
+		ListInstancePages(context.Context, string, string, func([]compute.VirtualMachineScaleSetVM) error) error
+		ListPages(context.Context, string, func([]compute.VirtualMachineScaleSet) error) error
 	}
 )
 
@@ -98,34 +98,34 @@ func (s *azureManagedMachinePoolService) Reconcile(ctx context.Context) error {
 	}
 
 	nodeResourceGroup := s.scope.NodeResourceGroup()
-	vmss, err := s.scaleSetsSvc.List(ctx, nodeResourceGroup)
+	var match string
+	err := s.scaleSetsSvc.ListPages(ctx, nodeResourceGroup, func(vmss []compute.VirtualMachineScaleSet) error {
+		for _, ss := range vmss {
+			if match == "" && ss.Tags["poolName"] != nil && *ss.Tags["poolName"] == agentPoolName {
+				match = *ss.Name
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return errors.Wrapf(err, "failed to list vmss in resource group %s", nodeResourceGroup)
 	}
 
-	var match *compute.VirtualMachineScaleSet
-	for _, ss := range vmss {
-		ss := ss
-		if ss.Tags["poolName"] != nil && *ss.Tags["poolName"] == agentPoolName {
-			match = &ss
-			break
-		}
-	}
-
-	if match == nil {
+	if match == "" {
 		return NewAgentPoolVMSSNotFoundError(nodeResourceGroup, agentPoolName)
 	}
 
-	instances, err := s.scaleSetsSvc.ListInstances(ctx, nodeResourceGroup, *match.Name)
+	var providerIDs []string
+	err = s.scaleSetsSvc.ListInstancePages(ctx, nodeResourceGroup, match, func(instances []compute.VirtualMachineScaleSetVM) error {
+		for _, instance := range instances {
+			providerIDs = append(providerIDs, strings.ToLower(azure.ProviderIDPrefix+*instance.ID))
+		}
+		return nil
+	})
 	if err != nil {
 		return errors.Wrapf(err, "failed to reconcile machine pool %s", agentPoolName)
 	}
 
-	var providerIDs = make([]string, len(instances))
-	for i := 0; i < len(instances); i++ {
-		providerIDs[i] = strings.ToLower(azure.ProviderIDPrefix + *instances[i].ID)
-	}
-
 	s.scope.SetAgentPoolProviderIDList(providerIDs)
 	s.scope.SetAgentPoolReplicas(int32(len(providerIDs)))
 	s.scope.SetAgentPoolReady(true)
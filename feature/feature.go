@@ -34,6 +34,10 @@ const (
 	// owner: @alexeldeib
 	// alpha: v0.4
 	AKS featuregate.Feature = "AKS"
+
+	// CostEstimation is the feature gate for the estimation of the monthly cost of clusters from the Azure retail prices.
+	// alpha: v0.5
+	CostEstimation featuregate.Feature = "CostEstimation"
 )
 
 func init() {
@@ -44,5 +48,6 @@ func init() {
 // To add a new feature, define a key for it above and add it here.
 var defaultCAPZFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	// Every feature should be initiated here:
-	AKS: {Default: false, PreRelease: featuregate.Alpha},
+	AKS:            {Default: false, PreRelease: featuregate.Alpha},
+	CostEstimation: {Default: false, PreRelease: featuregate.Alpha},
 }
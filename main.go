@@ -23,6 +23,7 @@ import (
 	"net/http"
 	_ "net/http/pprof" //nolint
 	"os"
+	"strings"
 	"time"
 
 	// +kubebuilder:scaffold:imports
@@ -36,19 +37,23 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/eventgrid"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	clusterv1exp "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
 	capifeature "sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/util/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	aadpodv1 "github.com/Azure/aad-pod-identity/pkg/apis/aadpodidentity/v1"
 
 	infrav1alpha3 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	infrav1alpha4 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1alpha3exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha3"
 	infrav1alpha4exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha4"
@@ -91,24 +96,50 @@ func init() {
 }
 
 var (
-	metricsAddr                        string
-	enableLeaderElection               bool
-	leaderElectionNamespace            string
-	leaderElectionLeaseDuration        time.Duration
-	leaderElectionRenewDeadline        time.Duration
-	leaderElectionRetryPeriod          time.Duration
-	watchNamespace                     string
-	watchFilterValue                   string
-	profilerAddress                    string
-	azureClusterConcurrency            int
-	azureMachineConcurrency            int
-	azureMachinePoolConcurrency        int
-	azureMachinePoolMachineConcurrency int
-	syncPeriod                         time.Duration
-	healthAddr                         string
-	webhookPort                        int
-	reconcileTimeout                   time.Duration
-	enableTracing                      bool
+	metricsAddr                         string
+	enableLeaderElection                bool
+	leaderElectionID                    string
+	leaderElectionNamespace             string
+	leaderElectionLeaseDuration         time.Duration
+	leaderElectionRenewDeadline         time.Duration
+	leaderElectionRetryPeriod           time.Duration
+	watchNamespace                      string
+	watchFilterValue                    string
+	profilerAddress                     string
+	azureClusterConcurrency             int
+	azureMachineConcurrency             int
+	azureMachinePoolConcurrency         int
+	azureMachinePoolMachineConcurrency  int
+	azureManagedClusterConcurrency      int
+	azureManagedControlPlaneConcurrency int
+	azureManagedMachinePoolConcurrency  int
+	syncPeriod                          time.Duration
+	azureClusterRequeueInterval         time.Duration
+	azureMachineRequeueInterval         time.Duration
+	azureMachinePoolRequeueInterval     time.Duration
+	azureClusterErrorBackoff            time.Duration
+	azureMachineErrorBackoff            time.Duration
+	azureMachinePoolErrorBackoff        time.Duration
+	maxErrorBackoff                     time.Duration
+	healthAddr                          string
+	webhookPort                         int
+	reconcileTimeout                    time.Duration
+	enableTracing                       bool
+	tracingEndpoint                     string
+	tracingSamplingRatio                float64
+	lockResourceGroups                  bool
+	azureAPIQPS                         float32
+	azureAPIBurst                       int
+	azurePollingDelays                  map[string]string
+	azureRetryAttempts                  map[string]string
+	azureOperationTimeouts              map[string]string
+	eventGridBindAddr                   string
+	dryRun                              bool
+	httpProxy                           string
+	httpsProxy                          string
+	noProxy                             string
+	additionalCABundle                  string
+	bootstrapProxy                      bool
 )
 
 // InitFlags initializes all command-line flags.
@@ -127,6 +158,13 @@ func InitFlags(fs *pflag.FlagSet) {
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.",
 	)
 
+	fs.StringVar(
+		&leaderElectionID,
+		"leader-election-id",
+		"controller-leader-election-capz",
+		"Name of the lease the controller performs leader election with. Controller managers sharding the cluster-api objects with --namespace or --watch-filter must each use a distinct name, so that every shard has its own leader.",
+	)
+
 	flag.StringVar(
 		&leaderElectionNamespace,
 		"leader-election-namespace",
@@ -159,7 +197,7 @@ func InitFlags(fs *pflag.FlagSet) {
 		&watchNamespace,
 		"namespace",
 		"",
-		"Namespace that the controller watches to reconcile cluster-api objects. If unspecified, the controller watches for cluster-api objects across all namespaces.",
+		"Comma-separated list of namespaces that the controller watches to reconcile cluster-api objects. If unspecified, the controller watches for cluster-api objects across all namespaces.",
 	)
 
 	fs.StringVar(
@@ -198,12 +236,69 @@ func InitFlags(fs *pflag.FlagSet) {
 		10,
 		"Number of AzureMachinePoolMachines to process simultaneously")
 
+	fs.IntVar(&azureManagedClusterConcurrency,
+		"azuremanagedcluster-concurrency",
+		10,
+		"Number of AzureManagedClusters to process simultaneously")
+
+	fs.IntVar(&azureManagedControlPlaneConcurrency,
+		"azuremanagedcontrolplane-concurrency",
+		10,
+		"Number of AzureManagedControlPlanes to process simultaneously")
+
+	fs.IntVar(&azureManagedMachinePoolConcurrency,
+		"azuremanagedmachinepool-concurrency",
+		10,
+		"Number of AzureManagedMachinePools to process simultaneously")
+
 	fs.DurationVar(&syncPeriod,
 		"sync-period",
 		10*time.Minute,
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)",
 	)
 
+	fs.DurationVar(&azureClusterRequeueInterval,
+		"azurecluster-requeue-interval",
+		0,
+		"Interval at which AzureClusters are reconciled again after a successful reconcile, to detect drift sooner than --sync-period (e.g. 5m). Disabled when 0.",
+	)
+
+	fs.DurationVar(&azureMachineRequeueInterval,
+		"azuremachine-requeue-interval",
+		0,
+		"Interval at which AzureMachines are reconciled again after a successful reconcile, to detect drift sooner than --sync-period (e.g. 5m). Disabled when 0.",
+	)
+
+	fs.DurationVar(&azureMachinePoolRequeueInterval,
+		"azuremachinepool-requeue-interval",
+		0,
+		"Interval at which AzureMachinePools are reconciled again after a successful reconcile, to detect drift sooner than --sync-period (e.g. 5m). Disabled when 0.",
+	)
+
+	fs.DurationVar(&azureClusterErrorBackoff,
+		"azurecluster-error-backoff",
+		reconciler.DefaultMinErrorBackoff,
+		"Delay before retrying an AzureCluster which failed to reconcile, doubled after each consecutive failure up to --max-error-backoff (e.g. 1s)",
+	)
+
+	fs.DurationVar(&azureMachineErrorBackoff,
+		"azuremachine-error-backoff",
+		reconciler.DefaultMinErrorBackoff,
+		"Delay before retrying an AzureMachine which failed to reconcile, doubled after each consecutive failure up to --max-error-backoff (e.g. 1s)",
+	)
+
+	fs.DurationVar(&azureMachinePoolErrorBackoff,
+		"azuremachinepool-error-backoff",
+		reconciler.DefaultMinErrorBackoff,
+		"Delay before retrying an AzureMachinePool or AzureMachinePoolMachine which failed to reconcile, doubled after each consecutive failure up to --max-error-backoff (e.g. 1s)",
+	)
+
+	fs.DurationVar(&maxErrorBackoff,
+		"max-error-backoff",
+		reconciler.DefaultMaxErrorBackoff,
+		"Maximum delay before retrying an object which failed to reconcile (e.g. 15m)",
+	)
+
 	fs.StringVar(&healthAddr,
 		"health-addr",
 		":9440",
@@ -226,7 +321,100 @@ func InitFlags(fs *pflag.FlagSet) {
 		&enableTracing,
 		"enable-tracing",
 		false,
-		"Enable tracing to the opentelemetry-collector service in the same namespace.",
+		"Enable tracing to the OTLP endpoint set by --tracing-endpoint.",
+	)
+
+	fs.StringVar(
+		&tracingEndpoint,
+		"tracing-endpoint",
+		"opentelemetry-collector:4317",
+		"The host:port of the OTLP gRPC endpoint traces are exported to, by default the opentelemetry-collector service in the same namespace.",
+	)
+
+	fs.Float64Var(
+		&tracingSamplingRatio,
+		"tracing-sampling-ratio",
+		1,
+		"The ratio of the reconcile loops which are traced, between 0 and 1.",
+	)
+
+	fs.BoolVar(
+		&lockResourceGroups,
+		"lock-resource-groups",
+		false,
+		"Protect the resource groups managed by the provider from deletion with a CanNotDelete management lock on the cluster virtual network, which is only removed when the cluster is deleted.",
+	)
+
+	fs.Float32Var(&azureAPIQPS,
+		"azure-api-qps",
+		0,
+		"Maximum number of requests per second sent to the Azure API for each subscription. Disabled when 0, requests throttled by Azure are always retried after the time it asks for.",
+	)
+
+	fs.IntVar(&azureAPIBurst,
+		"azure-api-burst",
+		100,
+		"Maximum burst of requests sent to the Azure API for each subscription, when --azure-api-qps is set.",
+	)
+
+	fs.StringToStringVar(&azurePollingDelays,
+		"azure-polling-delay",
+		nil,
+		"Delay before checking again on a long-running Azure operation which is not done yet, by operation type, e.g. write=30s,delete=1m. Operation types are read, write and delete. Defaults to 15s.",
+	)
+
+	fs.StringToStringVar(&azureRetryAttempts,
+		"azure-retry-attempts",
+		nil,
+		"Number of times an Azure API request which failed with a transient error is retried, by operation type, e.g. read=5,write=0. Operation types are read, write and delete. Defaults to 2.",
+	)
+
+	fs.StringToStringVar(&azureOperationTimeouts,
+		"azure-operation-timeout",
+		nil,
+		"Maximum duration of an Azure API request including its retries, by operation type, e.g. read=30s,write=2m. Operation types are read, write and delete. Requests are only bounded by --reconcile-timeout when unset.",
+	)
+
+	fs.StringVar(&eventGridBindAddr,
+		"event-grid-bind-addr",
+		"",
+		"The address the Event Grid webhook endpoint binds to, which receives the Azure Resource Manager events of the cluster resource groups to detect drift without waiting for --sync-period. Disabled when empty. Requests must carry the token of the EVENT_GRID_WEBHOOK_TOKEN environment variable, if set, as token query parameter.",
+	)
+
+	fs.BoolVar(&dryRun,
+		"dry-run",
+		false,
+		"Report the Azure API requests which would create, update or delete Azure resources in events, logs and conditions instead of sending them. It can be enabled for a single cluster with the "+infrav1alpha4.DryRunAnnotation+" annotation.",
+	)
+
+	fs.StringVar(&httpsProxy,
+		"https-proxy",
+		"",
+		"The URL of the proxy the Azure requests are sent through, e.g. http://proxy.example.com:3128. The proxy of the HTTPS_PROXY and NO_PROXY environment variables is used when empty.",
+	)
+
+	fs.StringVar(&httpProxy,
+		"http-proxy",
+		"",
+		"The URL of the proxy of the HTTP requests of the nodes, when --bootstrap-proxy is set.",
+	)
+
+	fs.StringVar(&noProxy,
+		"no-proxy",
+		"",
+		"The comma-separated list of the hosts, domains and CIDRs the nodes reach without the proxy, when --bootstrap-proxy is set.",
+	)
+
+	fs.StringVar(&additionalCABundle,
+		"additional-ca-bundle",
+		"",
+		"The path of a file holding PEM encoded CA certificates trusted by the Azure requests, in addition to the system ones.",
+	)
+
+	fs.BoolVar(&bootstrapProxy,
+		"bootstrap-proxy",
+		false,
+		"Add the proxy and CA settings to the cloud-init bootstrap data of the Linux nodes, for their container runtime and kubelet.",
 	)
 
 	feature.MutableGates.AddFlag(fs)
@@ -237,8 +425,9 @@ func main() {
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 	pflag.Parse()
 
-	if watchNamespace != "" {
-		setupLog.Info("Watching cluster-api objects only in namespace for reconciliation", "namespace", watchNamespace)
+	watchNamespaces := parseNamespaces(watchNamespace)
+	if len(watchNamespaces) > 0 {
+		setupLog.Info("Watching cluster-api objects only in namespaces for reconciliation", "namespaces", watchNamespaces)
 	}
 
 	if profilerAddress != "" {
@@ -250,29 +439,65 @@ func main() {
 
 	ctrl.SetLogger(klogr.New())
 
+	azure.SetAPIRateLimits(azureAPIQPS, azureAPIBurst)
+	operationPolicies, err := azure.ParseOperationPolicies(azurePollingDelays, azureRetryAttempts, azureOperationTimeouts)
+	if err != nil {
+		setupLog.Error(err, "invalid Azure operation policies")
+		os.Exit(1)
+	}
+	azure.SetOperationPolicies(operationPolicies)
+	azure.SetDryRun(dryRun)
+
+	proxyConfig := azure.HTTPProxyConfig{
+		HTTPProxy:  httpProxy,
+		HTTPSProxy: httpsProxy,
+		NoProxy:    noProxy,
+		Bootstrap:  bootstrapProxy,
+	}
+	if additionalCABundle != "" {
+		caBundle, err := os.ReadFile(additionalCABundle)
+		if err != nil {
+			setupLog.Error(err, "unable to read the additional CA bundle")
+			os.Exit(1)
+		}
+		proxyConfig.CABundle = caBundle
+	}
+	if err := azure.SetHTTPProxy(proxyConfig); err != nil {
+		setupLog.Error(err, "unable to configure the proxy")
+		os.Exit(1)
+	}
+
 	// Machine and cluster operations can create enough events to trigger the event recorder spam filter
 	// Setting the burst size higher ensures all events will be recorded and submitted to the API
 	broadcaster := cgrecord.NewBroadcasterWithCorrelatorOptions(cgrecord.CorrelatorOptions{
 		BurstSize: 100,
 	})
 
-	restConfig := ctrl.GetConfigOrDie()
-	restConfig.UserAgent = "cluster-api-provider-azure-manager"
-	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
+	options := ctrl.Options{
 		Scheme:                  scheme,
 		MetricsBindAddress:      metricsAddr,
 		LeaderElection:          enableLeaderElection,
-		LeaderElectionID:        "controller-leader-election-capz",
+		LeaderElectionID:        leaderElectionID,
 		LeaderElectionNamespace: leaderElectionNamespace,
 		LeaseDuration:           &leaderElectionLeaseDuration,
 		RenewDeadline:           &leaderElectionRenewDeadline,
 		RetryPeriod:             &leaderElectionRetryPeriod,
 		SyncPeriod:              &syncPeriod,
-		Namespace:               watchNamespace,
 		HealthProbeBindAddress:  healthAddr,
 		Port:                    webhookPort,
 		EventBroadcaster:        broadcaster,
-	})
+	}
+	switch len(watchNamespaces) {
+	case 0:
+	case 1:
+		options.Namespace = watchNamespaces[0]
+	default:
+		options.NewCache = cache.MultiNamespacedCacheBuilder(watchNamespaces)
+	}
+
+	restConfig := ctrl.GetConfigOrDie()
+	restConfig.UserAgent = "cluster-api-provider-azure-manager"
+	mgr, err := ctrl.NewManager(restConfig, options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
@@ -285,7 +510,7 @@ func main() {
 	ctx := ctrl.SetupSignalHandler()
 
 	if enableTracing {
-		if err := ot.RegisterTracing(ctx, setupLog); err != nil {
+		if err := ot.RegisterTracing(ctx, tracingEndpoint, tracingSamplingRatio, setupLog); err != nil {
 			setupLog.Error(err, "unable to initialize tracing")
 			os.Exit(1)
 		}
@@ -296,7 +521,22 @@ func main() {
 		os.Exit(1)
 	}
 
-	registerControllers(ctx, mgr)
+	var driftDetector *controllers.DriftDetector
+	if eventGridBindAddr != "" {
+		events := make(chan eventgrid.ResourceEvent, 100)
+		eventGridLog := ctrl.Log.WithName("eventgrid")
+		if err := mgr.Add(eventgrid.NewServer(eventGridBindAddr, eventgrid.NewHandler(os.Getenv("EVENT_GRID_WEBHOOK_TOKEN"), events, eventGridLog), eventGridLog)); err != nil {
+			setupLog.Error(err, "unable to create Event Grid server")
+			os.Exit(1)
+		}
+		driftDetector = controllers.NewDriftDetector(mgr.GetClient(), ctrl.Log.WithName("controllers").WithName("DriftDetector"), events)
+		if err := mgr.Add(driftDetector); err != nil {
+			setupLog.Error(err, "unable to create drift detector")
+			os.Exit(1)
+		}
+	}
+
+	registerControllers(ctx, mgr, driftDetector)
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddReadyzCheck("ping", healthz.Ping); err != nil {
@@ -316,12 +556,12 @@ func main() {
 	}
 }
 
-func registerControllers(ctx context.Context, mgr manager.Manager) {
+func registerControllers(ctx context.Context, mgr manager.Manager, driftDetector *controllers.DriftDetector) {
 	if err := controllers.NewAzureMachineReconciler(mgr.GetClient(), ctrl.Log.WithName("controllers").WithName("AzureMachine"),
 		mgr.GetEventRecorderFor("azuremachine-reconciler"),
 		reconcileTimeout,
 		watchFilterValue,
-	).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureMachineConcurrency}); err != nil {
+	).SetupWithManager(ctx, mgr, controllers.Options{Options: controllerOptions(azureMachineConcurrency, azureMachineErrorBackoff), RequeueInterval: azureMachineRequeueInterval, DriftEvents: driftDetector.AzureMachineEvents()}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureMachine")
 		os.Exit(1)
 	}
@@ -332,7 +572,8 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 		mgr.GetEventRecorderFor("azurecluster-reconciler"),
 		reconcileTimeout,
 		watchFilterValue,
-	).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}); err != nil {
+		lockResourceGroups,
+	).SetupWithManager(ctx, mgr, controllers.Options{Options: controllerOptions(azureClusterConcurrency, azureClusterErrorBackoff), RequeueInterval: azureClusterRequeueInterval, DriftEvents: driftDetector.AzureClusterEvents()}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureCluster")
 		os.Exit(1)
 	}
@@ -342,7 +583,8 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 		Log:              ctrl.Log.WithName("controllers").WithName("AzureJSONTemplate"),
 		Recorder:         mgr.GetEventRecorderFor("azurejsontemplate-reconciler"),
 		ReconcileTimeout: reconcileTimeout,
-	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureMachineConcurrency}); err != nil {
+		WatchFilterValue: watchFilterValue,
+	}).SetupWithManager(ctx, mgr, controllerOptions(azureMachineConcurrency, azureMachineErrorBackoff)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureJSONTemplate")
 		os.Exit(1)
 	}
@@ -352,7 +594,8 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 		Log:              ctrl.Log.WithName("controllers").WithName("AzureJSONMachine"),
 		Recorder:         mgr.GetEventRecorderFor("azurejsonmachine-reconciler"),
 		ReconcileTimeout: reconcileTimeout,
-	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureMachineConcurrency}); err != nil {
+		WatchFilterValue: watchFilterValue,
+	}).SetupWithManager(ctx, mgr, controllerOptions(azureMachineConcurrency, azureMachineErrorBackoff)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureJSONMachine")
 		os.Exit(1)
 	}
@@ -363,7 +606,7 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 		Recorder:         mgr.GetEventRecorderFor("azureidentity-reconciler"),
 		ReconcileTimeout: reconcileTimeout,
 		WatchFilterValue: watchFilterValue,
-	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}); err != nil {
+	}).SetupWithManager(ctx, mgr, controllerOptions(azureClusterConcurrency, azureClusterErrorBackoff)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureIdentity")
 		os.Exit(1)
 	}
@@ -382,7 +625,7 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 			mgr.GetEventRecorderFor("azuremachinepool-reconciler"),
 			reconcileTimeout,
 			watchFilterValue,
-		).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureMachinePoolConcurrency}, Cache: mpCache}); err != nil {
+		).SetupWithManager(ctx, mgr, controllers.Options{Options: controllerOptions(azureMachinePoolConcurrency, azureMachinePoolErrorBackoff), Cache: mpCache, RequeueInterval: azureMachinePoolRequeueInterval}); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "AzureMachinePool")
 			os.Exit(1)
 		}
@@ -398,7 +641,7 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 			mgr.GetEventRecorderFor("azuremachinepoolmachine-reconciler"),
 			reconcileTimeout,
 			watchFilterValue,
-		).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureMachinePoolMachineConcurrency}, Cache: mpmCache}); err != nil {
+		).SetupWithManager(ctx, mgr, controllers.Options{Options: controllerOptions(azureMachinePoolMachineConcurrency, azureMachinePoolErrorBackoff), Cache: mpmCache}); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "AzureMachinePoolMachine")
 			os.Exit(1)
 		}
@@ -408,7 +651,8 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 			Log:              ctrl.Log.WithName("controllers").WithName("AzureJSONMachinePool"),
 			Recorder:         mgr.GetEventRecorderFor("azurejsonmachinepool-reconciler"),
 			ReconcileTimeout: reconcileTimeout,
-		}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureMachinePoolConcurrency}); err != nil {
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, controllerOptions(azureMachinePoolConcurrency, azureMachinePoolErrorBackoff)); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "AzureJSONMachinePool")
 			os.Exit(1)
 		}
@@ -419,7 +663,7 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 				mgr.GetEventRecorderFor("azuremachine-reconciler"),
 				reconcileTimeout,
 				watchFilterValue,
-			).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureMachineConcurrency}); err != nil {
+			).SetupWithManager(ctx, mgr, controllerOptions(azureManagedMachinePoolConcurrency, azureMachineErrorBackoff)); err != nil {
 				setupLog.Error(err, "unable to create controller", "controller", "AzureManagedMachinePool")
 				os.Exit(1)
 			}
@@ -430,7 +674,7 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 				Recorder:         mgr.GetEventRecorderFor("azuremanagedcluster-reconciler"),
 				ReconcileTimeout: reconcileTimeout,
 				WatchFilterValue: watchFilterValue,
-			}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}); err != nil {
+			}).SetupWithManager(ctx, mgr, controllerOptions(azureManagedClusterConcurrency, azureClusterErrorBackoff)); err != nil {
 				setupLog.Error(err, "unable to create controller", "controller", "AzureManagedCluster")
 				os.Exit(1)
 			}
@@ -441,7 +685,7 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 				Recorder:         mgr.GetEventRecorderFor("azuremanagedcontrolplane-reconciler"),
 				ReconcileTimeout: reconcileTimeout,
 				WatchFilterValue: watchFilterValue,
-			}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}); err != nil {
+			}).SetupWithManager(ctx, mgr, controllerOptions(azureManagedControlPlaneConcurrency, azureClusterErrorBackoff)); err != nil {
 				setupLog.Error(err, "unable to create controller", "controller", "AzureManagedControlPlane")
 				os.Exit(1)
 			}
@@ -453,11 +697,33 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 		os.Exit(1)
 	}
 
+	mgr.GetWebhookServer().Register("/validate-infrastructure-cluster-x-k8s-io-v1alpha4-azurecluster-lookup", &ctrlwebhook.Admission{
+		Handler: &controllers.AzureClusterLookupValidator{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("webhooks").WithName("AzureClusterLookupValidator"),
+		},
+	})
+
 	if err := (&infrav1alpha4.AzureMachine{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AzureMachine")
 		os.Exit(1)
 	}
 
+	mgr.GetWebhookServer().Register("/mutate-infrastructure-cluster-x-k8s-io-v1alpha4-azuremachine-sshkey", &ctrlwebhook.Admission{
+		Handler: &controllers.AzureMachineSSHKeyDefaulter{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Log:       ctrl.Log.WithName("webhooks").WithName("AzureMachineSSHKeyDefaulter"),
+		},
+	})
+
+	mgr.GetWebhookServer().Register("/validate-infrastructure-cluster-x-k8s-io-v1alpha4-azuremachine-lookup", &ctrlwebhook.Admission{
+		Handler: &controllers.AzureMachineLookupValidator{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("webhooks").WithName("AzureMachineLookupValidator"),
+		},
+	})
+
 	if err := (&infrav1alpha4.AzureMachineTemplate{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AzureMachineTemplate")
 		os.Exit(1)
@@ -469,6 +735,13 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 			os.Exit(1)
 		}
 
+		mgr.GetWebhookServer().Register("/validate-infrastructure-cluster-x-k8s-io-v1alpha4-azuremachinepool-lookup", &ctrlwebhook.Admission{
+			Handler: &controllers.AzureMachinePoolLookupValidator{
+				Client: mgr.GetClient(),
+				Log:    ctrl.Log.WithName("webhooks").WithName("AzureMachinePoolLookupValidator"),
+			},
+		})
+
 		if err := (&infrav1alpha4exp.AzureMachinePoolMachine{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "AzureMachinePoolMachine")
 			os.Exit(1)
@@ -509,3 +782,23 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 		os.Exit(1)
 	}
 }
+
+// parseNamespaces returns the namespaces of a comma-separated list, ignoring empty items.
+func parseNamespaces(list string) []string {
+	var namespaces []string
+	for _, namespace := range strings.Split(list, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// controllerOptions returns the options of a controller which reconciles up to concurrency objects simultaneously, and
+// retries objects which failed to reconcile with an exponential backoff starting at errorBackoff.
+func controllerOptions(concurrency int, errorBackoff time.Duration) controller.Options {
+	return controller.Options{
+		MaxConcurrentReconciles: concurrency,
+		RateLimiter:             reconciler.NewRateLimiter(errorBackoff, maxErrorBackoff),
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eventgrid receives the Azure Resource Manager events delivered by an Event Grid subscription to a webhook.
+package eventgrid
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	// subscriptionValidationEvent is the event sent by Event Grid to validate the ownership of a webhook endpoint.
+	subscriptionValidationEvent = "Microsoft.EventGrid.SubscriptionValidationEvent"
+	// resourceEventPrefix is the prefix of the types of the events about resources of a resource group or subscription.
+	resourceEventPrefix = "Microsoft.Resources.Resource"
+	// maxRequestSize is the maximum size of a batch of events delivered by Event Grid.
+	maxRequestSize = 1024 * 1024
+)
+
+// ResourceEvent is an event about an Azure resource which was written, deleted or acted on.
+type ResourceEvent struct {
+	// ResourceID is the ID of the Azure resource.
+	ResourceID string
+	// EventType is the type of the event, e.g. Microsoft.Resources.ResourceDeleteSuccess.
+	EventType string
+}
+
+// event is an event in the Event Grid schema.
+type event struct {
+	ID        string          `json:"id"`
+	Subject   string          `json:"subject"`
+	EventType string          `json:"eventType"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// validationData is the data of a subscription validation event.
+type validationData struct {
+	ValidationCode string `json:"validationCode"`
+}
+
+// validationResponse is the response to a subscription validation event.
+type validationResponse struct {
+	ValidationResponse string `json:"validationResponse"`
+}
+
+// Handler is an http.Handler receiving the events delivered by an Event Grid subscription, which publishes the
+// successful resource write, delete and action events to a channel.
+type Handler struct {
+	token  string
+	events chan<- ResourceEvent
+	log    logr.Logger
+}
+
+// NewHandler returns a new Handler publishing the resource events it receives to events. When token is not empty,
+// only the requests with a matching token query parameter are accepted.
+func NewHandler(token string, events chan<- ResourceEvent, log logr.Logger) *Handler {
+	return &Handler{
+		token:  token,
+		events: events,
+		log:    log.WithName("EventGridHandler"),
+	}
+}
+
+// ServeHTTP handles a batch of events delivered by Event Grid.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.token != "" && subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(h.token)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var events []event
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&events); err != nil {
+		http.Error(w, "invalid event batch", http.StatusBadRequest)
+		return
+	}
+
+	for _, e := range events {
+		switch {
+		case e.EventType == subscriptionValidationEvent:
+			var data validationData
+			if err := json.Unmarshal(e.Data, &data); err != nil || data.ValidationCode == "" {
+				http.Error(w, "invalid subscription validation event", http.StatusBadRequest)
+				return
+			}
+			h.log.Info("validating Event Grid subscription", "id", e.ID)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(validationResponse{ValidationResponse: data.ValidationCode})
+			return
+		case strings.HasPrefix(e.EventType, resourceEventPrefix) && strings.HasSuffix(e.EventType, "Success"):
+			h.log.V(4).Info("received resource event", "type", e.EventType, "resource", e.Subject)
+			if !h.publish(r.Context(), ResourceEvent{ResourceID: e.Subject, EventType: e.EventType}) {
+				// Let Event Grid deliver the batch again later.
+				http.Error(w, "event queue is full", http.StatusServiceUnavailable)
+				return
+			}
+		default:
+			h.log.V(4).Info("ignoring event", "type", e.EventType, "subject", e.Subject)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// publish publishes an event, and returns false if it can't be published before the request is cancelled.
+func (h *Handler) publish(ctx context.Context, e ResourceEvent) bool {
+	select {
+	case h.events <- e:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
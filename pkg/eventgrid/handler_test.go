@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventgrid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/v2/klogr"
+)
+
+const vmID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm"
+
+func TestHandler(t *testing.T) {
+	testcases := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+		expectedBody   string
+		expectedEvents []ResourceEvent
+	}{
+		{
+			name:           "subscription validation",
+			method:         http.MethodPost,
+			target:         "/?token=secret",
+			body:           `[{"id":"1","eventType":"Microsoft.EventGrid.SubscriptionValidationEvent","data":{"validationCode":"abc"}}]`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"validationResponse":"abc"}`,
+		},
+		{
+			name:           "resource events",
+			method:         http.MethodPost,
+			target:         "/?token=secret",
+			body:           `[{"id":"1","subject":"` + vmID + `","eventType":"Microsoft.Resources.ResourceWriteSuccess"},{"id":"2","subject":"` + vmID + `","eventType":"Microsoft.Resources.ResourceDeleteSuccess"}]`,
+			expectedStatus: http.StatusOK,
+			expectedEvents: []ResourceEvent{
+				{ResourceID: vmID, EventType: "Microsoft.Resources.ResourceWriteSuccess"},
+				{ResourceID: vmID, EventType: "Microsoft.Resources.ResourceDeleteSuccess"},
+			},
+		},
+		{
+			name:           "failed and unknown events are ignored",
+			method:         http.MethodPost,
+			target:         "/?token=secret",
+			body:           `[{"id":"1","subject":"` + vmID + `","eventType":"Microsoft.Resources.ResourceWriteFailure"},{"id":"2","subject":"my-blob","eventType":"Microsoft.Storage.BlobCreated"}]`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid token",
+			method:         http.MethodPost,
+			target:         "/?token=wrong",
+			body:           `[]`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid body",
+			method:         http.MethodPost,
+			target:         "/?token=secret",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid method",
+			method:         http.MethodGet,
+			target:         "/?token=secret",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			events := make(chan ResourceEvent, 10)
+			h := NewHandler("secret", events, klogr.New())
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body)))
+
+			g.Expect(w.Code).To(Equal(tc.expectedStatus))
+			if tc.expectedBody != "" {
+				g.Expect(w.Body.String()).To(MatchJSON(tc.expectedBody))
+			}
+			close(events)
+			var received []ResourceEvent
+			for e := range events {
+				received = append(received, e)
+			}
+			g.Expect(received).To(Equal(tc.expectedEvents))
+		})
+	}
+}
+
+func TestHandlerQueueFull(t *testing.T) {
+	g := NewWithT(t)
+
+	h := NewHandler("", make(chan ResourceEvent), klogr.New())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w := httptest.NewRecorder()
+	body := `[{"id":"1","subject":"` + vmID + `","eventType":"Microsoft.Resources.ResourceWriteSuccess"}]`
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)).WithContext(ctx))
+
+	g.Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+}
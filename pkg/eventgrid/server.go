@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventgrid
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+)
+
+// shutdownTimeout is how long the server waits for the requests in progress to complete when it is stopped.
+const shutdownTimeout = 10 * time.Second
+
+// Server serves a Handler until its context is cancelled. It implements the manager.Runnable interface of
+// controller-runtime, and only runs on the elected leader, which reconciles the objects the events are about.
+type Server struct {
+	addr    string
+	handler http.Handler
+	log     logr.Logger
+}
+
+// NewServer returns a new Server serving handler on addr.
+func NewServer(addr string, handler http.Handler, log logr.Logger) *Server {
+	return &Server{
+		addr:    addr,
+		handler: handler,
+		log:     log.WithName("EventGridServer"),
+	}
+}
+
+// Start serves the handler until ctx is cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %s", s.addr)
+	}
+
+	srv := &http.Server{
+		Handler:           s.handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			s.log.Error(err, "failed to shut down Event Grid server")
+		}
+	}()
+
+	s.log.Info("serving Event Grid events", "addr", s.addr)
+	if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return errors.Wrap(err, "failed to serve Event Grid events")
+	}
+	return nil
+}
@@ -34,9 +34,10 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/version"
 )
 
-// RegisterTracing enables code tracing via OpenTelemetry.
-func RegisterTracing(ctx context.Context, log logr.Logger) error {
-	tp, err := otlpTracerProvider(ctx, "opentelemetry-collector:4317")
+// RegisterTracing enables code tracing via OpenTelemetry, exporting the traces to the OTLP gRPC endpoint at url.
+// Only the given ratio of the root spans, and of their children, are sampled.
+func RegisterTracing(ctx context.Context, url string, samplingRatio float64, log logr.Logger) error {
+	tp, err := otlpTracerProvider(ctx, url, samplingRatio)
 	if err != nil {
 		return err
 	}
@@ -57,7 +58,7 @@ func RegisterTracing(ctx context.Context, log logr.Logger) error {
 }
 
 // otlpTracerProvider initializes an OTLP exporter and configures the corresponding tracer provider.
-func otlpTracerProvider(ctx context.Context, url string) (*sdktrace.TracerProvider, error) {
+func otlpTracerProvider(ctx context.Context, url string, samplingRatio float64) (*sdktrace.TracerProvider, error) {
 	res, err := resource.New(ctx,
 		resource.WithAttributes(
 			semconv.ServiceNameKey.String("capz"),
@@ -80,7 +81,7 @@ func otlpTracerProvider(ctx context.Context, url string) (*sdktrace.TracerProvid
 
 	bsp := sdktrace.NewBatchSpanProcessor(traceExporter)
 	tracerProvider := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(samplingRatio))),
 		sdktrace.WithResource(res),
 		sdktrace.WithSpanProcessor(bsp),
 	)
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package futures
+
+import (
+	"sync"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+)
+
+// The functions of this package take the mutex guarding the list of futures of an object, held by the scope of the
+// object, as the services deleting the resources of the object run concurrently.
+
+// Get returns a copy of the future of the long-running operation on the named resource of a service, or nil if there
+// is none.
+func Get(mu *sync.Mutex, futures *infrav1.Futures, name, service string) *infrav1.Future {
+	mu.Lock()
+	defer mu.Unlock()
+	if i := index(*futures, name, service); i >= 0 {
+		future := (*futures)[i]
+		return &future
+	}
+	return nil
+}
+
+// Set adds a future to the list, replacing the existing future for the same resource and service if there is one.
+func Set(mu *sync.Mutex, futures *infrav1.Futures, future *infrav1.Future) {
+	if future == nil {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if i := index(*futures, future.Name, future.ServiceName); i >= 0 {
+		(*futures)[i] = *future
+		return
+	}
+	*futures = append(*futures, *future)
+}
+
+// Delete removes the future of the long-running operation on the named resource of a service from the list.
+func Delete(mu *sync.Mutex, futures *infrav1.Futures, name, service string) {
+	mu.Lock()
+	defer mu.Unlock()
+	var result infrav1.Futures
+	for _, f := range *futures {
+		if f.Name != name || f.ServiceName != service {
+			result = append(result, f)
+		}
+	}
+	*futures = result
+}
+
+// index returns the index of the future of the named resource of a service in the list, or -1 if there is none.
+func index(futures infrav1.Futures, name, service string) int {
+	for i := range futures {
+		if futures[i].Name == name && futures[i].ServiceName == service {
+			return i
+		}
+	}
+	return -1
+}
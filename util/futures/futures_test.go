@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package futures
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
+)
+
+func TestFutures(t *testing.T) {
+	g := NewWithT(t)
+
+	var (
+		mu      sync.Mutex
+		futures infrav1.Futures
+	)
+	g.Expect(Get(&mu, &futures, "my-vnet", "virtualnetworks")).To(BeNil())
+
+	Set(&mu, &futures, &infrav1.Future{Type: "PUT", ServiceName: "virtualnetworks", Name: "my-vnet", FutureData: "a"})
+	Set(&mu, &futures, &infrav1.Future{Type: "PUT", ServiceName: "loadbalancers", Name: "my-vnet", FutureData: "b"})
+	g.Expect(futures).To(HaveLen(2))
+	g.Expect(Get(&mu, &futures, "my-vnet", "virtualnetworks").FutureData).To(Equal("a"))
+
+	// setting a future for the same resource and service replaces the existing one
+	Set(&mu, &futures, &infrav1.Future{Type: "DELETE", ServiceName: "virtualnetworks", Name: "my-vnet", FutureData: "c"})
+	g.Expect(futures).To(HaveLen(2))
+	g.Expect(Get(&mu, &futures, "my-vnet", "virtualnetworks").Type).To(Equal("DELETE"))
+
+	Delete(&mu, &futures, "my-vnet", "virtualnetworks")
+	g.Expect(futures).To(HaveLen(1))
+	g.Expect(Get(&mu, &futures, "my-vnet", "virtualnetworks")).To(BeNil())
+	g.Expect(Get(&mu, &futures, "my-vnet", "loadbalancers")).NotTo(BeNil())
+
+	Delete(&mu, &futures, "my-vnet", "loadbalancers")
+	g.Expect(futures).To(BeEmpty())
+}
+
+func TestFuturesConcurrently(t *testing.T) {
+	g := NewWithT(t)
+
+	var (
+		mu      sync.Mutex
+		futures infrav1.Futures
+	)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			Set(&mu, &futures, &infrav1.Future{Type: "DELETE", ServiceName: "bastionhosts", Name: name})
+			Set(&mu, &futures, &infrav1.Future{Type: "DELETE", ServiceName: "loadbalancers", Name: name})
+			Delete(&mu, &futures, name, "bastionhosts")
+		}(fmt.Sprintf("resource-%d", i))
+	}
+	wg.Wait()
+
+	g.Expect(futures).To(HaveLen(10))
+	for i := 0; i < 10; i++ {
+		g.Expect(Get(&mu, &futures, fmt.Sprintf("resource-%d", i), "bastionhosts")).To(BeNil())
+		g.Expect(Get(&mu, &futures, fmt.Sprintf("resource-%d", i), "loadbalancers")).NotTo(BeNil())
+	}
+}
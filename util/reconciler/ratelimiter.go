@@ -0,0 +1,43 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+)
+
+const (
+	// DefaultMinErrorBackoff is the default delay before the first retry of a request which failed to reconcile.
+	DefaultMinErrorBackoff = 5 * time.Millisecond
+	// DefaultMaxErrorBackoff is the default maximum delay before retrying a request which failed to reconcile.
+	DefaultMaxErrorBackoff = 1000 * time.Second
+)
+
+// NewRateLimiter returns a rate limiter for the requests of a controller, which retries requests that failed to
+// reconcile with an exponential backoff starting at minBackoff and capped at maxBackoff.
+func NewRateLimiter(minBackoff, maxBackoff time.Duration) ratelimiter.RateLimiter {
+	if minBackoff <= 0 {
+		minBackoff = DefaultMinErrorBackoff
+	}
+	if maxBackoff < minBackoff {
+		maxBackoff = minBackoff
+	}
+	return workqueue.NewItemExponentialFailureRateLimiter(minBackoff, maxBackoff)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	cases := []struct {
+		Name       string
+		MinBackoff time.Duration
+		MaxBackoff time.Duration
+		Expected   []time.Duration
+	}{
+		{
+			Name:       "WithExponentialBackoff",
+			MinBackoff: time.Second,
+			MaxBackoff: 5 * time.Second,
+			Expected:   []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second},
+		},
+		{
+			Name:       "WithZeroValueDefaults",
+			MinBackoff: 0,
+			MaxBackoff: reconciler.DefaultMaxErrorBackoff,
+			Expected:   []time.Duration{reconciler.DefaultMinErrorBackoff, 2 * reconciler.DefaultMinErrorBackoff},
+		},
+		{
+			Name:       "WithMaxBelowMin",
+			MinBackoff: time.Minute,
+			MaxBackoff: time.Second,
+			Expected:   []time.Duration{time.Minute, time.Minute},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			limiter := reconciler.NewRateLimiter(c.MinBackoff, c.MaxBackoff)
+			for _, expected := range c.Expected {
+				g.Expect(limiter.When("my-request")).To(gomega.Equal(expected))
+			}
+
+			limiter.Forget("my-request")
+			g.Expect(limiter.NumRequeues("my-request")).To(gomega.Equal(0))
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -28,14 +29,17 @@ type tracer struct {
 }
 
 // Start creates a new context with a new Azure correlation ID, then
-// creates a new trace.Span with that new context. This function then
-// returns the new Context and Span.
+// creates a new trace.Span with that new context, carrying the correlation
+// ID as an attribute. This function then returns the new Context and Span.
 func (t tracer) Start(
 	ctx context.Context,
 	op string,
 	opts ...trace.SpanStartOption,
 ) (context.Context, trace.Span) {
-	ctx, _ = ctxWithCorrID(ctx)
+	ctx, corrID := ctxWithCorrID(ctx)
+	if corrID != "" {
+		opts = append(opts, trace.WithAttributes(attribute.String(string(corrIDKeyVal), string(corrID))))
+	}
 	return t.Tracer.Start(ctx, op, opts...)
 }
 